@@ -0,0 +1,269 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/ALH477/infgo/analysis"
+	syslogger "github.com/ALH477/infgo/logger"
+	"github.com/ALH477/infgo/metrics"
+)
+
+// ── Live alerts (-alert) ──────────────────────────────────────────────────────
+
+// alertHold is the -alert-for default: how long a condition must hold
+// before its alert starts, and stop holding before it clears, so a single
+// spike neither pages nor ends an incident.
+const alertHold = 10 * time.Second
+
+// alertRecent is how much of a rule's metric an alertEvent carries for the
+// chat sparklines, and alertSamples how many whole samples it carries for
+// the email table.
+const (
+	alertRecent  = time.Minute
+	alertSamples = 20
+)
+
+// alertRule is one -alert condition on live samples, e.g. cpu>90.  Unlike
+// analyze's -fail-if it addresses the sample itself, not a statistic.
+type alertRule struct {
+	analysis.Comparison
+	metric analysis.Metric
+}
+
+// parseAlertRule parses `metric<op>N` with a metric from analysis.Metrics.
+func parseAlertRule(expr string) (alertRule, error) {
+	c, err := analysis.ParseComparison(expr)
+	if err != nil {
+		return alertRule{}, err
+	}
+	m, ok := analysis.LookupMetric(c.Field)
+	if !ok {
+		names := make([]string, len(analysis.Metrics))
+		for i, m := range analysis.Metrics {
+			names[i] = m.Name
+		}
+		return alertRule{}, fmt.Errorf("%q: unknown metric %q (want %s)", expr, c.Field, strings.Join(names, ", "))
+	}
+	return alertRule{Comparison: c, metric: m}, nil
+}
+
+// worse reports whether v is further into r's alerting range than w.
+func (r alertRule) worse(v, w float64) bool {
+	if r.Op == analysis.OpLT || r.Op == analysis.OpLE {
+		return v < w
+	}
+	return v > w
+}
+
+// alertEvent is an alert starting or clearing.  It is also the JSON body
+// posted to -alert-webhook URLs.
+type alertEvent struct {
+	Hostname  string     `json:"hostname"`
+	Rule      string     `json:"rule"`
+	Metric    string     `json:"metric"`
+	Op        string     `json:"op"`
+	Threshold float64    `json:"threshold"`
+	Value     float64    `json:"value"`
+	State     string     `json:"state"` // "started" or "cleared"
+	StartedAt time.Time  `json:"started_at"`
+	ClearedAt *time.Time `json:"cleared_at,omitempty"`
+	DurationS float64    `json:"duration_s"`
+
+	// Recent is the metric over the last alertRecent and Samples the last
+	// alertSamples samples, oldest first.  Peak is the worst value since
+	// the condition began to hold.  Suppressed counts the transitions a
+	// limited target held back while the rule was flapping.  None of these
+	// are part of the generic webhook payload.
+	Recent     []float64        `json:"-"`
+	Samples    []metrics.Sample `json:"-"`
+	Peak       float64          `json:"-"`
+	Suppressed int              `json:"-"`
+}
+
+const (
+	alertStarted  = "started"
+	alertCleared  = "cleared"
+	alertFlapping = "flapping" // only sent to chat targets; see alertLimiter
+)
+
+// String is the message of the log Event recorded for e.
+func (e alertEvent) String() string {
+	if e.State == alertStarted {
+		return fmt.Sprintf("%s started: %s is %.4g", e.Rule, e.Metric, e.Value)
+	}
+	d := time.Duration(e.DurationS * float64(time.Second)).Round(time.Second)
+	return fmt.Sprintf("%s cleared after %s: %s is %.4g", e.Rule, d, e.Metric, e.Value)
+}
+
+// alertMonitor tracks every rule across successive samples.  It is used
+// from one goroutine only: the TUI's Update or the headless loop.
+type alertMonitor struct {
+	hold    time.Duration
+	host    string
+	rules   []alertRule
+	tracks  []alertTrack
+	samples []metrics.Sample // the last alertSamples, oldest first
+}
+
+type alertTrack struct {
+	active  bool
+	pending time.Time // when the condition last flipped against active
+	started time.Time
+	recent  []recentValue
+	peak    float64
+	peaked  bool // peak is set
+}
+
+type recentValue struct {
+	at time.Time
+	v  float64
+}
+
+// remember appends v and forgets values older than alertRecent.
+func (tr *alertTrack) remember(at time.Time, v float64) {
+	drop := 0
+	for drop < len(tr.recent) && at.Sub(tr.recent[drop].at) > alertRecent {
+		drop++
+	}
+	tr.recent = append(tr.recent[drop:], recentValue{at, v})
+}
+
+func (tr *alertTrack) values() []float64 {
+	out := make([]float64, len(tr.recent))
+	for i, r := range tr.recent {
+		out[i] = r.v
+	}
+	return out
+}
+
+func newAlertMonitor(rules []alertRule, hold time.Duration) *alertMonitor {
+	return &alertMonitor{hold: hold, rules: rules, tracks: make([]alertTrack, len(rules))}
+}
+
+func (a *alertMonitor) setHost(h string) {
+	if a != nil {
+		a.host = h
+	}
+}
+
+// observe feeds one sample and returns the alerts that started or cleared
+// on it.  A nil monitor never alerts.
+func (a *alertMonitor) observe(s metrics.Sample) []alertEvent {
+	if a == nil {
+		return nil
+	}
+	// Shift the window in place rather than re-slicing it along its
+	// backing array, which would reallocate every alertSamples ticks.
+	if len(a.samples) == alertSamples {
+		copy(a.samples, a.samples[1:])
+		a.samples = a.samples[:alertSamples-1]
+	}
+	a.samples = append(a.samples, s)
+	// Rules read the stored copy: taking &s would move every sample
+	// passed in to the heap, rules or not.
+	cur := &a.samples[len(a.samples)-1]
+	at := cur.Time()
+	var events []alertEvent
+	for i, r := range a.rules {
+		if cur.Missing.Has(r.metric.Group) {
+			continue // not read this time; the rule keeps its state
+		}
+		tr := &a.tracks[i]
+		v := r.metric.Value(cur)
+		tr.remember(at, v)
+		holds := r.Op.Eval(v, r.Value)
+		switch {
+		case holds && (!tr.peaked || r.worse(v, tr.peak)):
+			tr.peak, tr.peaked = v, true
+		case !holds && !tr.active:
+			tr.peaked = false
+		}
+		if holds == tr.active {
+			tr.pending = time.Time{}
+			continue
+		}
+		if tr.pending.IsZero() {
+			tr.pending = at
+		}
+		if at.Sub(tr.pending) < a.hold {
+			continue
+		}
+
+		ev := alertEvent{
+			Hostname:  a.host,
+			Rule:      r.String(),
+			Metric:    r.metric.Name,
+			Op:        string(r.Op),
+			Threshold: r.Value,
+			Value:     v,
+			Recent:    tr.values(),
+			Samples:   append([]metrics.Sample(nil), a.samples...),
+			Peak:      tr.peak,
+		}
+		if !tr.active {
+			tr.active, tr.started = true, tr.pending
+			ev.State, ev.StartedAt = alertStarted, tr.started
+		} else {
+			cleared := tr.pending
+			tr.active = false
+			ev.State, ev.StartedAt, ev.ClearedAt = alertCleared, tr.started, &cleared
+			ev.DurationS = cleared.Sub(tr.started).Seconds()
+			tr.peaked = false
+		}
+		tr.pending = time.Time{}
+		events = append(events, ev)
+	}
+	return events
+}
+
+// firing lists the rules whose alerts are active.
+func (a *alertMonitor) firing() []string {
+	if a == nil {
+		return nil
+	}
+	var out []string
+	for i, tr := range a.tracks {
+		if tr.active {
+			out = append(out, a.rules[i].String())
+		}
+	}
+	return out
+}
+
+// alertBadges are the footer badges for the firing alerts of a and, once a
+// delivery of n has failed, the reason; either is empty when there is none.
+func alertBadges(a *alertMonitor, n *alertNotifier) (firing, warning string) {
+	if names := a.firing(); len(names) > 0 {
+		firing = lipgloss.NewStyle().Foreground(cRed).Bold(true).Render("▲ " + strings.Join(names, " "))
+	}
+	if w := n.warningText(); w != "" {
+		warning = lipgloss.NewStyle().Foreground(cAmber).Render("⚠") +
+			dimSt.Render(" "+ansi.Truncate(w, 40, "…"))
+	}
+	return firing, warning
+}
+
+// recordAlert writes ev to the activity log, streams it to -listen clients
+// and queues its webhook deliveries.  Any sink may be nil.
+func recordAlert(ev alertEvent, lgr *syslogger.Logger, live *liveState, n *alertNotifier) {
+	at := ev.StartedAt
+	if ev.ClearedAt != nil {
+		at = *ev.ClearedAt
+	}
+	e := metrics.Event{TimestampUnixMs: at.UnixMilli(), Kind: "alert", Message: ev.String()}
+	if lgr != nil {
+		_ = lgr.WriteEvent(e)
+	}
+	if live != nil {
+		live.publishEvent(e)
+	}
+	n.notify(ev)
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	syslogger "github.com/ALH477/infgo/logger"
+	"github.com/ALH477/infgo/metrics"
+)
+
+func TestParseAlertRule(t *testing.T) {
+	tests := []struct {
+		in, want string
+		wantErr  bool
+	}{
+		{"cpu>90", "cpu>90", false},
+		{"load1 >= 8", "load1>=8", false},
+		{"mem>85%", "mem>85", false},
+		{"cpu.p95>90", "", true},
+		{"disk>90", "", true},
+		{"cpu", "", true},
+	}
+	for _, tt := range tests {
+		r, err := parseAlertRule(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseAlertRule(%q): got err %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if err == nil && r.String() != tt.want {
+			t.Errorf("parseAlertRule(%q): got %q, want %q", tt.in, r.String(), tt.want)
+		}
+	}
+}
+
+func TestAlertMonitor(t *testing.T) {
+	rule, _ := parseAlertRule("cpu>90")
+	mon := newAlertMonitor([]alertRule{rule}, 2*time.Second)
+	mon.setHost("node7")
+
+	// cpu at 1 s intervals: a 1 s spike, then 3 s above, a 1 s dip, and
+	// 2 s below.
+	cpu := []float64{50, 95, 50, 95, 99, 97, 50, 97, 50, 40, 30}
+	var got []alertEvent
+	for i, v := range cpu {
+		got = append(got, mon.observe(metrics.Sample{TimestampUnixMs: int64(i) * 1000, CpuTotal: v})...)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d transitions, want a start and a clear: %+v", len(got), got)
+	}
+	start, clear := got[0], got[1]
+	if start.State != alertStarted || start.Hostname != "node7" || start.StartedAt.UnixMilli() != 3000 || start.Value != 97 || start.Peak != 99 {
+		t.Errorf("start: got %+v, want started at 3s with the value at 5s and a peak of 99", start)
+	}
+	if len(start.Samples) != 6 || len(start.Recent) != 6 {
+		t.Errorf("start: got %d samples and %d recent values, want 6", len(start.Samples), len(start.Recent))
+	}
+	if clear.State != alertCleared || clear.ClearedAt == nil || clear.ClearedAt.UnixMilli() != 8000 || clear.DurationS != 5 {
+		t.Errorf("clear: got %+v, want cleared at 8s after 5s", clear)
+	}
+	if f := mon.firing(); len(f) != 0 {
+		t.Errorf("firing after clear: %v", f)
+	}
+
+	var none *alertMonitor
+	if none.observe(metrics.Sample{CpuTotal: 100}) != nil || none.firing() != nil {
+		t.Error("nil monitor alerted")
+	}
+}
+
+func TestRecordAlert(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts.infgo")
+	lgr, err := syslogger.New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	live := newLiveState()
+	sub := live.subscribe()
+	defer live.unsubscribe(sub)
+
+	started := time.UnixMilli(1000)
+	cleared := time.UnixMilli(61000)
+	recordAlert(alertEvent{Rule: "mem>80", Metric: "mem", Value: 42, State: alertCleared,
+		StartedAt: started, ClearedAt: &cleared, DurationS: 60}, lgr, live, nil)
+
+	want := "mem>80 cleared after 1m0s: mem is 42"
+	select {
+	case u := <-sub.ch:
+		if u.Event == nil || u.Event.Kind != "alert" || u.Event.Message != want || u.Event.TimestampUnixMs != 61000 {
+			t.Errorf("streamed event: got %+v", u.Event)
+		}
+	default:
+		t.Error("no event streamed")
+	}
+	if err := lgr.Close(); err != nil {
+		t.Fatal(err)
+	}
+	rd, err := syslogger.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rd.Close()
+	rec, err := rd.Next()
+	if err != nil || rec.Event == nil || !strings.Contains(rec.Event.Message, "cleared after 1m0s") {
+		t.Errorf("logged record: got %+v, %v", rec, err)
+	}
+}
+
+func TestAlertMonitorWindow(t *testing.T) {
+	rule, _ := parseAlertRule("cpu>90")
+	mon := newAlertMonitor([]alertRule{rule}, 0)
+	var got []alertEvent
+	for i := range 3 * alertSamples {
+		v := float64(i)
+		if i == 3*alertSamples-1 {
+			v = 95
+		}
+		got = append(got, mon.observe(metrics.Sample{TimestampUnixMs: int64(i) * 1000, CpuTotal: v})...)
+	}
+	if len(got) != 1 || len(got[0].Samples) != alertSamples {
+		t.Fatalf("got %+v, want one start carrying %d samples", got, alertSamples)
+	}
+	first := 2 * alertSamples
+	for i, s := range got[0].Samples {
+		if s.TimestampUnixMs != int64(first+i)*1000 {
+			t.Fatalf("sample %d: got timestamp %d, want %d", i, s.TimestampUnixMs, int64(first+i)*1000)
+		}
+	}
+
+	// The event's samples are its own: the window moving on leaves them be.
+	mon.observe(metrics.Sample{TimestampUnixMs: 99e3, CpuTotal: 99})
+	if got[0].Samples[0].TimestampUnixMs != int64(first)*1000 {
+		t.Error("a later sample changed an event's samples")
+	}
+
+	// Once the window is full, observing allocates nothing.
+	quiet := newAlertMonitor(nil, 0)
+	s := metrics.Sample{CpuCores: []float64{1, 2}}
+	if allocs := testing.AllocsPerRun(100, func() { quiet.observe(s) }); allocs != 0 {
+		t.Errorf("observe: got %v allocations, want 0", allocs)
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package analysis
+
+import (
+	"math"
+	"time"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// ── Anomalies ─────────────────────────────────────────────────────────────────
+
+// Anomaly is a run of consecutive samples in which a metric sat more than
+// K standard deviations above its capture-wide mean.
+type Anomaly struct {
+	Metric Metric
+	Start  time.Time // first anomalous sample
+	End    time.Time // last anomalous sample
+	Peak   float64
+	PeakAt time.Time
+
+	Mean  float64 // capture-wide baseline the run was measured against
+	Sigma float64
+}
+
+// Duration is the time between the first and last anomalous sample.
+func (a Anomaly) Duration() time.Duration { return a.End.Sub(a.Start) }
+
+// DetectAnomalies returns every run of samples where m exceeds mean + k·σ,
+// in chronological order.  A flat series (σ = 0) has no anomalies.
+func DetectAnomalies(samples []metrics.Sample, m Metric, k float64) []Anomaly {
+	vals := Series(samples, m)
+	if len(vals) < 2 {
+		return nil
+	}
+	var mean float64
+	for _, v := range vals {
+		mean += v
+	}
+	mean /= float64(len(vals))
+	var ss float64
+	for _, v := range vals {
+		ss += (v - mean) * (v - mean)
+	}
+	sigma := math.Sqrt(ss / float64(len(vals)))
+	if sigma == 0 {
+		return nil
+	}
+	limit := mean + k*sigma
+
+	var (
+		out []Anomaly
+		cur *Anomaly
+	)
+	for i, v := range vals {
+		t := samples[i].Time()
+		if v <= limit {
+			cur = nil
+			continue
+		}
+		if cur == nil {
+			out = append(out, Anomaly{Metric: m, Start: t, Peak: v, PeakAt: t, Mean: mean, Sigma: sigma})
+			cur = &out[len(out)-1]
+		}
+		cur.End = t
+		if v > cur.Peak {
+			cur.Peak, cur.PeakAt = v, t
+		}
+	}
+	return out
+}
+
+// Welford keeps the mean and standard deviation of a changing set of
+// values at a constant cost per change: Welford's online algorithm,
+// extended to take values out again, so that a moving window costs the
+// same however long it is.  The zero value is empty.
+type Welford struct {
+	n    int
+	mean float64
+	m2   float64 // sum of squared deviations from mean
+}
+
+// Add counts x.
+func (w *Welford) Add(x float64) {
+	w.n++
+	d := x - w.mean
+	w.mean += d / float64(w.n)
+	w.m2 += d * (x - w.mean)
+}
+
+// Remove takes out x, which must have been added.
+func (w *Welford) Remove(x float64) {
+	if w.n <= 1 {
+		*w = Welford{}
+		return
+	}
+	old := w.mean
+	w.n--
+	w.mean -= (x - old) / float64(w.n)
+	// Rounding can leave a hair below zero once the values left are equal.
+	w.m2 = max(0, w.m2-(x-old)*(x-w.mean))
+}
+
+// N is the number of values counted.
+func (w *Welford) N() int { return w.n }
+
+// Mean is the mean of the values; 0 when there are none.
+func (w *Welford) Mean() float64 { return w.mean }
+
+// Sigma is the population standard deviation, as DetectAnomalies measures
+// it; 0 when there are none.
+func (w *Welford) Sigma() float64 {
+	if w.n == 0 {
+		return 0
+	}
+	return math.Sqrt(w.m2 / float64(w.n))
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package analysis
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestDetectAnomalies(t *testing.T) {
+	start := time.Date(2026, 1, 14, 0, 0, 0, 0, time.UTC)
+	cpu := make([]float64, 100)
+	for i := range cpu {
+		cpu[i] = 20 + float64(i%3) // small jitter around 21
+	}
+	cpu[40], cpu[41], cpu[42] = 95, 99, 90
+	cpu[80] = 97
+	m, _ := LookupMetric("cpu")
+
+	got := DetectAnomalies(secondSamples(start, cpu...), m, 3)
+	if len(got) != 2 {
+		t.Fatalf("anomalies: got %d, want 2", len(got))
+	}
+	tests := []struct {
+		start, end, peakAt time.Duration
+		peak               float64
+	}{
+		{40 * time.Second, 42 * time.Second, 41 * time.Second, 99},
+		{80 * time.Second, 80 * time.Second, 80 * time.Second, 97},
+	}
+	for i, tt := range tests {
+		a := got[i]
+		if !a.Start.Equal(start.Add(tt.start)) || !a.End.Equal(start.Add(tt.end)) {
+			t.Errorf("anomaly %d: got %v–%v, want +%v–+%v", i, a.Start, a.End, tt.start, tt.end)
+		}
+		if a.Peak != tt.peak || !a.PeakAt.Equal(start.Add(tt.peakAt)) {
+			t.Errorf("anomaly %d peak: got %v at %v, want %v at +%v", i, a.Peak, a.PeakAt, tt.peak, tt.peakAt)
+		}
+	}
+
+	if flat := DetectAnomalies(secondSamples(start, 50, 50, 50), m, 3); len(flat) != 0 {
+		t.Errorf("flat series: got %d anomalies, want 0", len(flat))
+	}
+}
+
+func TestWelford(t *testing.T) {
+	var w Welford
+	vals := []float64{2, 4, 4, 4, 5, 5, 7, 9}
+	for _, v := range vals {
+		w.Add(v)
+	}
+	if w.N() != 8 || w.Mean() != 5 || w.Sigma() != 2 {
+		t.Errorf("all: got n %d, mean %v, σ %v; want 8, 5, 2", w.N(), w.Mean(), w.Sigma())
+	}
+	for _, v := range vals[:4] {
+		w.Remove(v)
+	}
+	if w.N() != 4 || math.Abs(w.Mean()-6.5) > 1e-12 || math.Abs(w.Sigma()-math.Sqrt(2.75)) > 1e-12 {
+		t.Errorf("last four: got n %d, mean %v, σ %v; want 4, 6.5, %v", w.N(), w.Mean(), w.Sigma(), math.Sqrt(2.75))
+	}
+	for _, v := range vals[4:] {
+		w.Remove(v)
+	}
+	if w != (Welford{}) {
+		t.Errorf("emptied: got %+v", w)
+	}
+	w.Add(3)
+	w.Add(3)
+	w.Remove(3)
+	if w.Mean() != 3 || w.Sigma() != 0 {
+		t.Errorf("equal values: got mean %v, σ %v", w.Mean(), w.Sigma())
+	}
+}
+
+// A window moved along a long series keeps to the statistics computed
+// over it afresh.
+func TestWelfordMovingWindow(t *testing.T) {
+	const win = 600
+	rng := rand.New(rand.NewSource(3))
+	vals := make([]float64, 20000)
+	var w Welford
+	for i := range vals {
+		vals[i] = 30 + 20*rng.Float64()
+		if i%5000 == 0 {
+			vals[i] = 100 // the odd spike
+		}
+		w.Add(vals[i])
+		if i >= win {
+			w.Remove(vals[i-win])
+		}
+	}
+	tail := vals[len(vals)-win:]
+	var mean, ss float64
+	for _, v := range tail {
+		mean += v
+	}
+	mean /= win
+	for _, v := range tail {
+		ss += (v - mean) * (v - mean)
+	}
+	if sigma := math.Sqrt(ss / win); math.Abs(w.Mean()-mean) > 1e-9 || math.Abs(w.Sigma()-sigma) > 1e-9 {
+		t.Errorf("got mean %v, σ %v; want %v, %v", w.Mean(), w.Sigma(), mean, sigma)
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package analysis
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// ── Correlation ───────────────────────────────────────────────────────────────
+
+// Pearson returns the Pearson correlation coefficient of x and y over their
+// common prefix.  It returns NaN when either series is constant or shorter
+// than two points, since the coefficient is undefined there.
+func Pearson(x, y []float64) float64 {
+	n := min(len(x), len(y))
+	if n < 2 {
+		return math.NaN()
+	}
+	var mx, my float64
+	for i := 0; i < n; i++ {
+		mx += x[i]
+		my += y[i]
+	}
+	mx /= float64(n)
+	my /= float64(n)
+
+	var sxy, sxx, syy float64
+	for i := 0; i < n; i++ {
+		dx, dy := x[i]-mx, y[i]-my
+		sxy += dx * dy
+		sxx += dx * dx
+		syy += dy * dy
+	}
+	if sxx == 0 || syy == 0 {
+		return math.NaN()
+	}
+	return sxy / math.Sqrt(sxx*syy)
+}
+
+// LaggedPearson correlates x[t] with y[t+lag].  A positive lag means y
+// follows x; a negative lag means y leads.
+func LaggedPearson(x, y []float64, lag int) float64 {
+	if lag >= 0 {
+		if lag >= len(y) {
+			return math.NaN()
+		}
+		return Pearson(x, y[lag:])
+	}
+	if -lag >= len(x) {
+		return math.NaN()
+	}
+	return Pearson(x[-lag:], y)
+}
+
+// Pair is the correlation between two metrics.  Coefficients are NaN when
+// undefined (e.g. a metric that never changed).
+type Pair struct {
+	A, B string
+	R    float64 // at zero lag
+
+	// BestLag is the lag with the largest |r| and BestR the coefficient
+	// there.  Without a lag sweep they equal 0 and R.
+	BestLag time.Duration
+	BestR   float64
+}
+
+// Correlate computes pairwise correlations between ms over samples.  When
+// maxLag is positive every pair is additionally swept over lags of up to
+// ±maxLag, in steps of the capture's typical sample spacing.
+func Correlate(samples []metrics.Sample, ms []Metric, maxLag time.Duration) []Pair {
+	series := make([][]float64, len(ms))
+	for i, m := range ms {
+		series[i] = Series(samples, m)
+	}
+	step := MedianInterval(samples)
+	steps := 0
+	if maxLag > 0 && step > 0 {
+		steps = int(maxLag / step)
+	}
+
+	var out []Pair
+	for i := range ms {
+		for j := i + 1; j < len(ms); j++ {
+			p := Pair{A: ms[i].Name, B: ms[j].Name, R: Pearson(series[i], series[j])}
+			p.BestR = p.R
+			for k := -steps; k <= steps; k++ {
+				r := LaggedPearson(series[i], series[j], k)
+				if !math.IsNaN(r) && (math.IsNaN(p.BestR) || math.Abs(r) > math.Abs(p.BestR)) {
+					p.BestR = r
+					p.BestLag = time.Duration(k) * step
+				}
+			}
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// MedianInterval returns the median spacing between consecutive samples,
+// which is robust against the occasional gap or late tick.
+func MedianInterval(samples []metrics.Sample) time.Duration {
+	if len(samples) < 2 {
+		return 0
+	}
+	gaps := make([]float64, len(samples)-1)
+	for i := 1; i < len(samples); i++ {
+		gaps[i-1] = float64(samples[i].TimestampUnixMs - samples[i-1].TimestampUnixMs)
+	}
+	sort.Float64s(gaps)
+	return time.Duration(Percentile(gaps, 50)) * time.Millisecond
+}
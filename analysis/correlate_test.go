@@ -0,0 +1,96 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package analysis
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+func TestPearson(t *testing.T) {
+	x := []float64{1, 2, 3, 4, 5, 6}
+	tests := []struct {
+		name string
+		y    []float64
+		want float64
+	}{
+		{"correlated", []float64{2, 4, 6, 8, 10, 12}, 1},
+		{"anti-correlated", []float64{60, 50, 40, 30, 20, 10}, -1},
+		{"uncorrelated", []float64{1, -1, -1, -1, -1, 1}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Pearson(x, tt.y); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Pearson: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if got := Pearson(x, []float64{3, 3, 3, 3, 3, 3}); !math.IsNaN(got) {
+		t.Errorf("Pearson with constant series: got %v, want NaN", got)
+	}
+}
+
+func TestCorrelateLagSweep(t *testing.T) {
+	// A pseudo-random CPU series, with memory trailing it by 5 s and the
+	// load average mirroring it inverted at zero lag.
+	const n, delay = 200, 5
+	cpu := make([]float64, n+delay)
+	seed := uint32(1)
+	for i := range cpu {
+		seed = seed*1664525 + 1013904223
+		cpu[i] = float64(seed>>24) / 2.55
+	}
+	start := time.Date(2026, 1, 14, 0, 0, 0, 0, time.UTC)
+	samples := make([]metrics.Sample, n)
+	for i := range samples {
+		samples[i] = metrics.Sample{
+			TimestampUnixMs: start.Add(time.Duration(i) * time.Second).UnixMilli(),
+			CpuTotal:        cpu[i+delay],
+			MemPercent:      cpu[i],
+			Load1:           100 - cpu[i+delay],
+		}
+	}
+	var ms []Metric
+	for _, name := range []string{"cpu", "mem", "load1"} {
+		m, _ := LookupMetric(name)
+		ms = append(ms, m)
+	}
+
+	pairs := Correlate(samples, ms, time.Minute)
+	if len(pairs) != 3 {
+		t.Fatalf("pairs: got %d, want 3", len(pairs))
+	}
+	tests := []struct {
+		a, b  string
+		lag   time.Duration
+		bestR float64
+	}{
+		{"cpu", "mem", 5 * time.Second, 1},
+		{"cpu", "load1", 0, -1},
+		{"mem", "load1", -5 * time.Second, -1},
+	}
+	for i, tt := range tests {
+		p := pairs[i]
+		if p.A != tt.a || p.B != tt.b {
+			t.Fatalf("pair %d: got %s~%s, want %s~%s", i, p.A, p.B, tt.a, tt.b)
+		}
+		if p.BestLag != tt.lag {
+			t.Errorf("%s~%s best lag: got %v, want %v", p.A, p.B, p.BestLag, tt.lag)
+		}
+		if math.Abs(p.BestR-tt.bestR) > 1e-9 {
+			t.Errorf("%s~%s best r: got %v, want %v", p.A, p.B, p.BestR, tt.bestR)
+		}
+	}
+	if r := pairs[0].R; math.Abs(r) > 0.5 {
+		t.Errorf("cpu~mem at zero lag: got %v, want a weak correlation", r)
+	}
+
+	noSweep := Correlate(samples, ms, 0)
+	if noSweep[0].BestLag != 0 || noSweep[0].BestR != noSweep[0].R {
+		t.Errorf("without a sweep: got lag %v r %v, want 0 and %v", noSweep[0].BestLag, noSweep[0].BestR, noSweep[0].R)
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package analysis
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ── Comparison expressions ────────────────────────────────────────────────────
+//
+// A comparison is the smallest unit of infgo's expression language:
+//
+//	<field> <op> <number>      e.g.  cpu.p95>80   mem >= 90.5   load1!=0
+//
+// Field is a dotted identifier whose meaning depends on the caller: threshold
+// assertions address summary statistics ("cpu.p95"), while per-sample
+// filters address sample fields ("cpu").  Whitespace around the operator is
+// optional.
+
+// Op is a comparison operator.
+type Op string
+
+const (
+	OpGT Op = ">"
+	OpGE Op = ">="
+	OpLT Op = "<"
+	OpLE Op = "<="
+	OpEQ Op = "=="
+	OpNE Op = "!="
+)
+
+// ops is ordered so that two-character operators are matched first.
+var ops = []Op{OpGE, OpLE, OpEQ, OpNE, OpGT, OpLT}
+
+// Eval reports whether a <op> b holds.
+func (op Op) Eval(a, b float64) bool {
+	switch op {
+	case OpGT:
+		return a > b
+	case OpGE:
+		return a >= b
+	case OpLT:
+		return a < b
+	case OpLE:
+		return a <= b
+	case OpEQ:
+		return a == b
+	case OpNE:
+		return a != b
+	default:
+		return false
+	}
+}
+
+// Comparison is a parsed `<field> <op> <number>` expression.
+type Comparison struct {
+	Field string
+	Op    Op
+	Value float64
+}
+
+// String returns the canonical spelling of c.
+func (c Comparison) String() string {
+	return c.Field + string(c.Op) + strconv.FormatFloat(c.Value, 'g', -1, 64)
+}
+
+// ParseComparison parses a single comparison expression.
+func ParseComparison(expr string) (Comparison, error) {
+	s := strings.TrimSpace(expr)
+	for i := 0; i < len(s); i++ {
+		for _, op := range ops {
+			if !strings.HasPrefix(s[i:], string(op)) {
+				continue
+			}
+			field := strings.TrimSpace(s[:i])
+			rhs := strings.TrimSpace(s[i+len(op):])
+			if !isField(field) {
+				return Comparison{}, fmt.Errorf("%q: bad field name %q", expr, field)
+			}
+			v, err := strconv.ParseFloat(strings.TrimSuffix(rhs, "%"), 64)
+			if err != nil {
+				return Comparison{}, fmt.Errorf("%q: bad number %q", expr, rhs)
+			}
+			return Comparison{Field: field, Op: op, Value: v}, nil
+		}
+	}
+	return Comparison{}, fmt.Errorf("%q: no comparison operator (want one of > >= < <= == !=)", expr)
+}
+
+// isField reports whether s is a dotted identifier ("cpu", "cpu.p95").
+func isField(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, part := range strings.Split(s, ".") {
+		if part == "" {
+			return false
+		}
+		for i, r := range part {
+			letter := r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+			digit := r >= '0' && r <= '9'
+			if !letter && !(digit && i > 0) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// ── Threshold assertions ──────────────────────────────────────────────────────
+
+// Assertion is a `--fail-if` condition evaluated against a Summary.
+// The assertion fails when its comparison holds.
+type Assertion struct {
+	Expr   string  `json:"expr"`
+	Metric string  `json:"metric"`
+	Stat   string  `json:"stat"`
+	Op     Op      `json:"op"`
+	Limit  float64 `json:"threshold"`
+	Actual float64 `json:"actual"`
+	Delta  float64 `json:"delta"` // Actual - Limit
+	Failed bool    `json:"failed"`
+}
+
+// ParseAssertion parses a `metric.stat <op> value` fail condition and
+// checks that both names are known.
+func ParseAssertion(expr string) (Comparison, error) {
+	c, err := ParseComparison(expr)
+	if err != nil {
+		return c, err
+	}
+	metric, stat, ok := strings.Cut(c.Field, ".")
+	if !ok {
+		return c, fmt.Errorf("%q: want metric.stat on the left (e.g. cpu.p95)", expr)
+	}
+	if _, ok := LookupMetric(metric); !ok {
+		return c, fmt.Errorf("%q: unknown metric %q (want %s)", expr, metric, metricNames())
+	}
+	if _, err := (Stats{}).Get(stat); err != nil {
+		return c, fmt.Errorf("%q: unknown statistic %q (want %s)", expr, stat, strings.Join(StatNames, ", "))
+	}
+	return c, nil
+}
+
+// Evaluate checks c (as produced by ParseAssertion) against sum.
+func Evaluate(sum Summary, c Comparison) Assertion {
+	metric, stat, _ := strings.Cut(c.Field, ".")
+	actual, _ := sum[metric].Get(stat)
+	return Assertion{
+		Expr:   c.String(),
+		Metric: metric,
+		Stat:   stat,
+		Op:     c.Op,
+		Limit:  c.Value,
+		Actual: actual,
+		Delta:  actual - c.Value,
+		Failed: c.Op.Eval(actual, c.Value),
+	}
+}
+
+// metricNames returns the comma-separated names of Metrics.
+func metricNames() string {
+	names := make([]string, len(Metrics))
+	for i, m := range Metrics {
+		names[i] = m.Name
+	}
+	return strings.Join(names, ", ")
+}
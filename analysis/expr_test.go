@@ -0,0 +1,80 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package analysis
+
+import (
+	"testing"
+)
+
+func TestParseComparison(t *testing.T) {
+	tests := []struct {
+		expr string
+		want Comparison
+	}{
+		{"cpu.p95>80", Comparison{"cpu.p95", OpGT, 80}},
+		{"mem.max >= 90.5", Comparison{"mem.max", OpGE, 90.5}},
+		{"load1<=2", Comparison{"load1", OpLE, 2}},
+		{" cpu < 5% ", Comparison{"cpu", OpLT, 5}},
+		{"mem.mean==0", Comparison{"mem.mean", OpEQ, 0}},
+		{"load15!=-1", Comparison{"load15", OpNE, -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := ParseComparison(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseComparison failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	for _, bad := range []string{"", "cpu", "cpu>", ">80", "cpu..p95>1", "9cpu>1", "cpu>abc"} {
+		if _, err := ParseComparison(bad); err == nil {
+			t.Errorf("ParseComparison(%q): expected error", bad)
+		}
+	}
+}
+
+func TestParseAssertion(t *testing.T) {
+	if _, err := ParseAssertion("cpu.p95>80"); err != nil {
+		t.Errorf("valid assertion rejected: %v", err)
+	}
+	for _, bad := range []string{"cpu>80", "disk.p95>80", "cpu.p42>80"} {
+		if _, err := ParseAssertion(bad); err == nil {
+			t.Errorf("ParseAssertion(%q): expected error", bad)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	sum := Summary{
+		"cpu": {Mean: 40, P95: 85, P99: 92, Max: 99},
+		"mem": {Max: 70},
+	}
+	tests := []struct {
+		expr   string
+		failed bool
+		delta  float64
+	}{
+		{"cpu.p95>80", true, 5},
+		{"cpu.mean>80", false, -40},
+		{"mem.max>90", false, -20},
+		{"cpu.max>=99", true, 0},
+	}
+	for _, tt := range tests {
+		c, err := ParseAssertion(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseAssertion(%q) failed: %v", tt.expr, err)
+		}
+		a := Evaluate(sum, c)
+		if a.Failed != tt.failed {
+			t.Errorf("%s: failed=%v, want %v", tt.expr, a.Failed, tt.failed)
+		}
+		if a.Delta != tt.delta {
+			t.Errorf("%s: delta=%v, want %v", tt.expr, a.Delta, tt.delta)
+		}
+	}
+}
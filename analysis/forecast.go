@@ -0,0 +1,126 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package analysis
+
+import (
+	"time"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// ── Forecasts ─────────────────────────────────────────────────────────────────
+
+// Fit is the least-squares line y = Intercept + Slope·x through a series,
+// and R2, the share of the series' variance the line explains.
+type Fit struct {
+	Slope     float64
+	Intercept float64
+	R2        float64
+}
+
+// LinearFit fits a line to the points (x[i], y[i]).  A series with fewer
+// than two distinct x has no slope; one with no variance in y has an R2
+// of 0, since there is nothing for the line to explain.
+func LinearFit(x, y []float64) Fit {
+	n := min(len(x), len(y))
+	if n == 0 {
+		return Fit{}
+	}
+	var mx, my float64
+	for i := 0; i < n; i++ {
+		mx += x[i]
+		my += y[i]
+	}
+	mx /= float64(n)
+	my /= float64(n)
+	var sxx, sxy, syy float64
+	for i := 0; i < n; i++ {
+		dx, dy := x[i]-mx, y[i]-my
+		sxx += dx * dx
+		sxy += dx * dy
+		syy += dy * dy
+	}
+	if sxx == 0 {
+		return Fit{Intercept: my}
+	}
+	f := Fit{Slope: sxy / sxx}
+	f.Intercept = my - f.Slope*mx
+	if syy > 0 {
+		f.R2 = sxy * sxy / (sxx * syy)
+	}
+	return f
+}
+
+// Forecast projects a climbing series along its fitted line.
+type Forecast struct {
+	Fit
+	At    time.Time // the last point, from which Until counts
+	Level float64   // the line's value at At
+}
+
+// PerMinute returns the rate of the climb.
+func (f Forecast) PerMinute() float64 { return f.Slope * 60 }
+
+// Until returns how long after At the line reaches level: 0 if it is
+// there already.
+func (f Forecast) Until(level float64) time.Duration {
+	if level <= f.Level {
+		return 0
+	}
+	return time.Duration((level - f.Level) / f.Slope * float64(time.Second))
+}
+
+// MinForecastR2 is the R2 below which a rise is taken for noise: a ramp
+// scores close to 1, and a single step halfway through the window 0.75.
+const MinForecastR2 = 0.8
+
+// minForecastPoints is the fewest readings a forecast is made from.
+const minForecastPoints = 10
+
+// ForecastSeries fits a line to the readings vals, taken at times, in the
+// window that ends at the last of them, and returns the forecast if they
+// are climbing: a positive slope that explains at least minR2 of their
+// variance.  The readings must span half the window, so that the first few
+// seconds of a session are not projected hours ahead.
+func ForecastSeries(times []time.Time, vals []float64, window time.Duration, minR2 float64) (Forecast, bool) {
+	n := min(len(times), len(vals))
+	if n == 0 {
+		return Forecast{}, false
+	}
+	last := times[n-1]
+	from := 0
+	for from < n && last.Sub(times[from]) > window {
+		from++
+	}
+	times, vals = times[from:n], vals[from:n]
+	if len(times) < minForecastPoints || last.Sub(times[0]) < window/2 {
+		return Forecast{}, false
+	}
+	x := make([]float64, len(times))
+	for i, t := range times {
+		x[i] = t.Sub(times[0]).Seconds()
+	}
+	f := LinearFit(x, vals)
+	if f.Slope <= 0 || f.R2 < minR2 {
+		return Forecast{}, false
+	}
+	return Forecast{Fit: f, At: last, Level: f.Intercept + f.Slope*x[len(x)-1]}, true
+}
+
+// MemForecast is the forecast of memory use over the last window of the
+// samples, leaving out those whose memory reading failed.
+func MemForecast(samples []metrics.Sample, window time.Duration, minR2 float64) (Forecast, bool) {
+	var (
+		times []time.Time
+		vals  []float64
+	)
+	for _, s := range samples {
+		if s.Missing.Has(metrics.MissingMem) {
+			continue
+		}
+		times = append(times, s.Time())
+		vals = append(vals, s.MemPercent)
+	}
+	return ForecastSeries(times, vals, window, minR2)
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package analysis
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+func TestLinearFit(t *testing.T) {
+	tests := []struct {
+		name string
+		x, y []float64
+		want Fit
+	}{
+		{"line", []float64{0, 1, 2, 3}, []float64{1, 3, 5, 7}, Fit{Slope: 2, Intercept: 1, R2: 1}},
+		{"falling", []float64{0, 1, 2}, []float64{4, 2, 0}, Fit{Slope: -2, Intercept: 4, R2: 1}},
+		{"flat", []float64{0, 1, 2}, []float64{5, 5, 5}, Fit{Intercept: 5}},
+		{"one x", []float64{3, 3}, []float64{1, 2}, Fit{Intercept: 1.5}},
+		{"empty", nil, nil, Fit{}},
+	}
+	for _, tt := range tests {
+		got := LinearFit(tt.x, tt.y)
+		if math.Abs(got.Slope-tt.want.Slope) > 1e-9 || math.Abs(got.Intercept-tt.want.Intercept) > 1e-9 ||
+			math.Abs(got.R2-tt.want.R2) > 1e-9 {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// series is five minutes of readings a second apart, from f.
+func series(f func(i int) float64) ([]time.Time, []float64) {
+	t0 := time.Unix(1_700_000_000, 0)
+	var (
+		times []time.Time
+		vals  []float64
+	)
+	for i := 0; i <= 300; i++ {
+		times = append(times, t0.Add(time.Duration(i)*time.Second))
+		vals = append(vals, f(i))
+	}
+	return times, vals
+}
+
+func TestForecastSeries(t *testing.T) {
+	const window = 5 * time.Minute
+	rng := rand.New(rand.NewSource(1))
+	tests := []struct {
+		name   string
+		f      func(i int) float64
+		want   bool
+		toFull time.Duration // from the last reading, to the second
+	}{
+		// 0.5 points a minute from 50 %: 52.5 % after five minutes, and
+		// 47.5 points, 95 minutes, short of full.
+		{"ramp", func(i int) float64 { return 50 + float64(i)/120 }, true, 95 * time.Minute},
+		{"noisy ramp", func(i int) float64 { return 50 + float64(i)/60 + rng.Float64() - 0.5 }, true, 0},
+		{"flat", func(int) float64 { return 60 }, false, 0},
+		{"noise", func(int) float64 { return 60 + 2*rng.Float64() }, false, 0},
+		{"falling", func(i int) float64 { return 80 - float64(i)/60 }, false, 0},
+		{"step", func(i int) float64 { return 40 + 20*float64(i/150) }, false, 0},
+		{"late step", func(i int) float64 { return 40 + 20*float64(i/280) }, false, 0},
+	}
+	for _, tt := range tests {
+		times, vals := series(tt.f)
+		got, ok := ForecastSeries(times, vals, window, MinForecastR2)
+		if ok != tt.want {
+			t.Errorf("%s: got %v (%+v), want %v", tt.name, ok, got, tt.want)
+			continue
+		}
+		if tt.toFull > 0 {
+			if d := got.Until(100).Round(time.Second); d != tt.toFull {
+				t.Errorf("%s: got full in %v, want %v", tt.name, d, tt.toFull)
+			}
+			if !got.At.Equal(times[len(times)-1]) || math.Abs(got.PerMinute()-0.5) > 1e-9 {
+				t.Errorf("%s: got %+v", tt.name, got)
+			}
+		}
+	}
+
+	// Only the window ending at the last reading counts: a fall before it
+	// does not hide the climb in it.
+	times, vals := series(func(i int) float64 {
+		if i < 100 {
+			return 90 - float64(i)/2
+		}
+		return 40 + float64(i)/60
+	})
+	if _, ok := ForecastSeries(times, vals, 3*time.Minute, MinForecastR2); !ok {
+		t.Error("a climb after a fall: got no forecast")
+	}
+	// Nor is one made from less than half a window.
+	if _, ok := ForecastSeries(times[:60], vals[:60], window, MinForecastR2); ok {
+		t.Error("a minute of readings: got a forecast")
+	}
+}
+
+func TestForecastUntil(t *testing.T) {
+	f := Forecast{Fit: Fit{Slope: 0.1}, Level: 90}
+	if got := f.Until(95); got != 50*time.Second {
+		t.Errorf("to 95: got %v, want 50s", got)
+	}
+	if got := f.Until(85); got != 0 {
+		t.Errorf("below the level: got %v, want 0", got)
+	}
+}
+
+func TestMemForecast(t *testing.T) {
+	t0 := time.Unix(1_700_000_000, 0)
+	var samples []metrics.Sample
+	for i := 0; i <= 300; i++ {
+		s := metrics.Sample{TimestampUnixMs: t0.Add(time.Duration(i) * time.Second).UnixMilli(), MemPercent: 50 + float64(i)/60}
+		if i%10 == 0 {
+			// A failed reading is logged as 0, which is not a fall.
+			s.Missing, s.MemPercent = metrics.MissingMem, 0
+		}
+		samples = append(samples, s)
+	}
+	f, ok := MemForecast(samples, 5*time.Minute, MinForecastR2)
+	if !ok || math.Abs(f.PerMinute()-1) > 1e-9 {
+		t.Errorf("got %+v, %v; want 1 point a minute", f, ok)
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package analysis
+
+import (
+	"time"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// ── Hour-of-day heatmap ───────────────────────────────────────────────────────
+
+// HeatCell is the mean of a metric over one hour of one day.
+type HeatCell struct {
+	Mean float64
+	N    int // samples in the hour; 0 where there were none
+}
+
+// Heatmap holds the hourly means of a metric, one row per calendar day.
+type Heatmap struct {
+	Metric Metric
+	Days   []time.Time    // midnight of each day, first to last, in the bucketing zone
+	Cells  [][24]HeatCell // Cells[d][h] is hour h of Days[d]
+}
+
+// HourlyHeatmap buckets samples by the day and hour they were taken on the
+// wall clock of loc, and averages m over each.  Every day from the first
+// sample's to the last's has a row, those without samples included.
+// Samples that could not read m are left out.
+//
+// Hours are wall-clock hours: on the day the clocks go back the repeated
+// hour falls in one cell, and on the day they go forward the skipped hour
+// has none.
+func HourlyHeatmap(samples []metrics.Sample, m Metric, loc *time.Location) *Heatmap {
+	hm := &Heatmap{Metric: m}
+	if len(samples) == 0 {
+		return hm
+	}
+	first, last := civilDay(samples[0].Time().In(loc)), civilDay(samples[0].Time().In(loc))
+	for i := range samples {
+		d := civilDay(samples[i].Time().In(loc))
+		first, last = min(first, d), max(last, d)
+	}
+
+	y, mo, d := samples[0].Time().In(loc).Date()
+	base := civilDay(time.Date(y, mo, d, 0, 0, 0, 0, time.UTC))
+	for i := first; i <= last; i++ {
+		hm.Days = append(hm.Days, time.Date(y, mo, d+int(i-base), 0, 0, 0, 0, loc))
+	}
+	hm.Cells = make([][24]HeatCell, len(hm.Days))
+
+	sums := make([][24]float64, len(hm.Days))
+	for i := range samples {
+		s := &samples[i]
+		if s.Missing.Has(m.Group) {
+			continue
+		}
+		t := s.Time().In(loc)
+		row := civilDay(t) - first
+		hm.Cells[row][t.Hour()].N++
+		sums[row][t.Hour()] += m.Value(s)
+	}
+	for row := range hm.Cells {
+		for h := range hm.Cells[row] {
+			if c := &hm.Cells[row][h]; c.N > 0 {
+				c.Mean = sums[row][h] / float64(c.N)
+			}
+		}
+	}
+	return hm
+}
+
+// Max returns the highest hourly mean, 0 if there is none.
+func (hm *Heatmap) Max() float64 {
+	var hi float64
+	for _, row := range hm.Cells {
+		for _, c := range row {
+			if c.N > 0 {
+				hi = max(hi, c.Mean)
+			}
+		}
+	}
+	return hi
+}
+
+// civilDay numbers the calendar date of t, in t's own zone, in days since
+// 1970-01-01, so that consecutive dates differ by one whatever their length.
+func civilDay(t time.Time) int64 {
+	y, mo, d := t.Date()
+	return time.Date(y, mo, d, 0, 0, 0, 0, time.UTC).Unix() / 86400
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package analysis
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata" // the DST cases must not depend on the host's zoneinfo
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// everyTenMinutes returns samples every ten minutes from start up to end,
+// each with CpuTotal set to the hour of its wall clock in loc.
+func everyTenMinutes(start, end time.Time, loc *time.Location) []metrics.Sample {
+	var out []metrics.Sample
+	for t := start; t.Before(end); t = t.Add(10 * time.Minute) {
+		out = append(out, metrics.Sample{TimestampUnixMs: t.UnixMilli(), CpuTotal: float64(t.In(loc).Hour())})
+	}
+	return out
+}
+
+func TestHourlyHeatmapDST(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cpu, _ := LookupMetric("cpu")
+	tests := []struct {
+		name   string
+		day    time.Time
+		counts map[int]int // hours whose count is not 6
+	}{
+		{"ordinary day", time.Date(2026, 6, 10, 0, 0, 0, 0, ny), nil},
+		{"clocks forward", time.Date(2026, 3, 8, 0, 0, 0, 0, ny), map[int]int{2: 0}},
+		{"clocks back", time.Date(2026, 11, 1, 0, 0, 0, 0, ny), map[int]int{1: 12}},
+	}
+	for _, tt := range tests {
+		next := time.Date(tt.day.Year(), tt.day.Month(), tt.day.Day()+1, 0, 0, 0, 0, ny)
+		hm := HourlyHeatmap(everyTenMinutes(tt.day, next, ny), cpu, ny)
+		if len(hm.Days) != 1 || !hm.Days[0].Equal(tt.day) {
+			t.Fatalf("%s: got days %v, want only %v", tt.name, hm.Days, tt.day)
+		}
+		for h, c := range hm.Cells[0] {
+			want, ok := tt.counts[h]
+			if !ok {
+				want = 6
+			}
+			if c.N != want || (c.N > 0 && c.Mean != float64(h)) {
+				t.Errorf("%s: hour %d got %+v, want %d samples of mean %d", tt.name, h, c, want, h)
+			}
+		}
+	}
+}
+
+// The same instants fall in other cells, and on other days, in UTC.
+func TestHourlyHeatmapZone(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cpu, _ := LookupMetric("cpu")
+	start := time.Date(2026, 6, 10, 20, 0, 0, 0, ny) // 00:00 UTC on the 11th
+	samples := everyTenMinutes(start, start.Add(2*time.Hour), ny)
+
+	local := HourlyHeatmap(samples, cpu, ny)
+	if len(local.Days) != 1 || local.Cells[0][20].N != 6 || local.Cells[0][21].N != 6 {
+		t.Errorf("local: got %d days, %+v", len(local.Days), local.Cells)
+	}
+	utc := HourlyHeatmap(samples, cpu, time.UTC)
+	if len(utc.Days) != 1 || utc.Days[0].Day() != 11 || utc.Cells[0][0].N != 6 || utc.Cells[0][1].N != 6 {
+		t.Errorf("UTC: got days %v, %+v", utc.Days, utc.Cells)
+	}
+}
+
+func TestHourlyHeatmapGaps(t *testing.T) {
+	cpu, _ := LookupMetric("cpu")
+	start := time.Date(2026, 1, 5, 23, 0, 0, 0, time.UTC)
+	samples := everyTenMinutes(start, start.Add(time.Hour), time.UTC)
+	// Nothing on the 6th; two readings on the 7th, one of them unreadable.
+	samples = append(samples,
+		metrics.Sample{TimestampUnixMs: time.Date(2026, 1, 7, 9, 0, 0, 0, time.UTC).UnixMilli(), CpuTotal: 40},
+		metrics.Sample{TimestampUnixMs: time.Date(2026, 1, 7, 9, 30, 0, 0, time.UTC).UnixMilli(), Missing: metrics.MissingCPU})
+
+	hm := HourlyHeatmap(samples, cpu, time.UTC)
+	if len(hm.Days) != 3 {
+		t.Fatalf("got %d days, want 3", len(hm.Days))
+	}
+	for d, row := range hm.Cells {
+		for h, c := range row {
+			want := 0
+			switch {
+			case d == 0 && h == 23:
+				want = 6
+			case d == 2 && h == 9:
+				want = 1
+			}
+			if c.N != want {
+				t.Errorf("day %d hour %d: got %d samples, want %d", d, h, c.N, want)
+			}
+		}
+	}
+	if got := hm.Cells[2][9].Mean; got != 40 {
+		t.Errorf("got mean %v, want 40 without the unreadable sample", got)
+	}
+	if got := hm.Max(); got != 40 {
+		t.Errorf("Max: got %v, want 40", got)
+	}
+	if empty := HourlyHeatmap(nil, cpu, time.UTC); len(empty.Days) != 0 || empty.Max() != 0 {
+		t.Errorf("no samples: got %+v", empty)
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package analysis
+
+import (
+	"time"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// ── Idle periods ──────────────────────────────────────────────────────────────
+
+// The event kinds the collector writes when the CPU goes quiet and when it
+// is busy again.  An idle_start event is stamped with the first quiet
+// sample, not the one that confirmed the idle period, so it may follow
+// samples later than itself in the log.
+const (
+	IdleStart = "idle_start"
+	IdleEnd   = "idle_end"
+)
+
+// Span is the stretch of a capture from Start to End.
+type Span struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Duration returns End - Start.
+func (s Span) Duration() time.Duration { return s.End.Sub(s.Start) }
+
+// IdleSpans pairs the capture's idle_start and idle_end events into the
+// periods the machine was idle, clipped to its samples.  One still open
+// when the capture ends lasts to the last sample.
+func IdleSpans(c *Capture) []Span {
+	if len(c.Samples) == 0 {
+		return nil
+	}
+	first, last := c.Samples[0].Time(), c.Samples[len(c.Samples)-1].Time()
+	var (
+		out   []Span
+		open  bool
+		start time.Time
+	)
+	end := func(at time.Time) {
+		s := Span{Start: start, End: at}
+		if s.Start.Before(first) {
+			s.Start = first
+		}
+		if s.End.After(last) {
+			s.End = last
+		}
+		if s.End.After(s.Start) {
+			out = append(out, s)
+		}
+		open = false
+	}
+	for _, e := range c.Events {
+		switch e.Kind {
+		case IdleStart:
+			if !open {
+				open, start = true, e.Time()
+			}
+		case IdleEnd:
+			if open {
+				end(e.Time())
+			}
+		}
+	}
+	if open {
+		end(last)
+	}
+	return out
+}
+
+// Busy returns the samples outside every span, in order; a span's End is
+// not part of it.  spans must be in order and must not overlap, as
+// IdleSpans returns them.
+func Busy(samples []metrics.Sample, spans []Span) []metrics.Sample {
+	var out []metrics.Sample
+	i := 0
+	for _, s := range samples {
+		t := s.Time()
+		for i < len(spans) && !t.Before(spans[i].End) {
+			i++
+		}
+		if i < len(spans) && !t.Before(spans[i].Start) {
+			continue
+		}
+		out = append(out, s)
+	}
+	return out
+}
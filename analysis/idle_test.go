@@ -0,0 +1,53 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+func TestIdleSpans(t *testing.T) {
+	t0 := time.Unix(1_700_000_000, 0)
+	at := func(s int) int64 { return t0.Add(time.Duration(s) * time.Second).UnixMilli() }
+	c := &Capture{}
+	for i := 0; i <= 100; i++ {
+		c.Samples = append(c.Samples, metrics.Sample{TimestampUnixMs: at(i)})
+	}
+	c.Events = []metrics.Event{
+		{TimestampUnixMs: at(10), Kind: IdleStart},
+		{TimestampUnixMs: at(20), Kind: "marker"},
+		{TimestampUnixMs: at(30), Kind: IdleEnd},
+		{TimestampUnixMs: at(35), Kind: IdleEnd}, // unpaired: ignored
+		{TimestampUnixMs: at(80), Kind: IdleStart},
+	}
+	spans := IdleSpans(c)
+	want := []Span{
+		{t0.Add(10 * time.Second), t0.Add(30 * time.Second)},
+		{t0.Add(80 * time.Second), t0.Add(100 * time.Second)}, // open at the end
+	}
+	if len(spans) != len(want) {
+		t.Fatalf("got %v, want %v", spans, want)
+	}
+	for i := range want {
+		if !spans[i].Start.Equal(want[i].Start) || !spans[i].End.Equal(want[i].End) {
+			t.Errorf("span %d: got %v, want %v", i, spans[i], want[i])
+		}
+	}
+
+	// The idle_end sample is busy, as is the last, which ends the open
+	// span; the idle_start one is not.
+	busy := Busy(c.Samples, spans)
+	if got, want := len(busy), 101-20-20; got != want {
+		t.Fatalf("got %d busy samples, want %d", got, want)
+	}
+	for _, s := range busy {
+		sec := s.Time().Sub(t0) / time.Second
+		if (sec >= 10 && sec < 30) || (sec >= 80 && sec < 100) {
+			t.Errorf("sample at %ds is idle, got it busy", sec)
+		}
+	}
+}
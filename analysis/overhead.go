@@ -0,0 +1,88 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package analysis
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// ── Monitor overhead ──────────────────────────────────────────────────────────
+
+// Overhead summarises how long the collector spent on its own readings,
+// from the samples that recorded it (Sample.CollectMs).
+type Overhead struct {
+	Stats                  // of the collection times, in milliseconds
+	Interval time.Duration // the capture's sampling interval
+	Slow     []SlowRun     // runs that took over half the interval, in order
+}
+
+// Share is the mean collection time as a fraction of the interval.
+func (o Overhead) Share() float64 {
+	return o.Mean / (float64(o.Interval) / float64(time.Millisecond))
+}
+
+// SlowRun is a run of consecutive timed samples whose collection took over
+// half the interval: the monitor was starved, and its readings are late.
+type SlowRun struct {
+	Start, End time.Time // first and last slow sample
+	N          int
+	PeakMs     float64
+}
+
+// CaptureInterval is the spacing of c's samples: the header's nominal
+// interval, or where that was not recorded the median spacing.  It is 0
+// with fewer than two samples to go by.
+func CaptureInterval(c *Capture) time.Duration {
+	if c.Header != nil && c.Header.Interval() > 0 {
+		return c.Header.Interval()
+	}
+	if len(c.Samples) < 2 {
+		return 0
+	}
+	gaps := make([]float64, len(c.Samples)-1)
+	for i := range gaps {
+		gaps[i] = float64(c.Samples[i+1].TimestampUnixMs - c.Samples[i].TimestampUnixMs)
+	}
+	sort.Float64s(gaps)
+	return time.Duration(Percentile(gaps, 50) * float64(time.Millisecond))
+}
+
+// CollectOverhead summarises the collection times of samples taken every
+// interval.  ok is false if none of them recorded one, as in captures
+// from before collect_ms was logged.
+func CollectOverhead(samples []metrics.Sample, interval time.Duration) (o Overhead, ok bool) {
+	limit := float64(interval) / float64(time.Millisecond) / 2
+	var (
+		vals []float64
+		cur  *SlowRun
+	)
+	o.Interval = interval
+	for i := range samples {
+		s := &samples[i]
+		if s.CollectMs == nil {
+			continue // untimed samples neither extend nor end a run
+		}
+		v := *s.CollectMs
+		vals = append(vals, v)
+		if interval <= 0 || v <= limit {
+			cur = nil
+			continue
+		}
+		if cur == nil {
+			o.Slow = append(o.Slow, SlowRun{Start: s.Time()})
+			cur = &o.Slow[len(o.Slow)-1]
+		}
+		cur.End = s.Time()
+		cur.N++
+		cur.PeakMs = max(cur.PeakMs, v)
+	}
+	if len(vals) == 0 {
+		return Overhead{}, false
+	}
+	o.Stats = Summarize(vals)
+	return o, true
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+func TestCaptureInterval(t *testing.T) {
+	start := time.Date(2026, 1, 14, 0, 0, 0, 0, time.UTC)
+	samples := secondSamples(start, 1, 2, 3, 4)
+	samples[3].TimestampUnixMs += 60_000 // one gap does not move the median
+	tests := []struct {
+		name string
+		c    Capture
+		want time.Duration
+	}{
+		{"from the header", Capture{Header: &metrics.Header{IntervalMs: 250}, Samples: samples}, 250 * time.Millisecond},
+		{"from the spacing", Capture{Header: &metrics.Header{}, Samples: samples}, time.Second},
+		{"one sample", Capture{Samples: samples[:1]}, 0},
+	}
+	for _, tt := range tests {
+		if got := CaptureInterval(&tt.c); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCollectOverhead(t *testing.T) {
+	start := time.Date(2026, 1, 14, 0, 0, 0, 0, time.UTC)
+	samples := secondSamples(start, make([]float64, 8)...)
+	if _, ok := CollectOverhead(samples, time.Second); ok {
+		t.Error("untimed capture: got overhead")
+	}
+	// Over 500ms is slow; the untimed fourth sample neither ends nor
+	// extends the run around it.
+	for i, ms := range []float64{10, 600, 700, -1, 800, 20, 900, 10} {
+		if ms >= 0 {
+			samples[i].CollectMs = &ms
+		}
+	}
+	o, ok := CollectOverhead(samples, time.Second)
+	if !ok || o.N != 7 || o.Max != 900 {
+		t.Fatalf("got %+v, %v", o.Stats, ok)
+	}
+	if got := o.Share(); got != o.Mean/1000 {
+		t.Errorf("Share: got %v, want %v", got, o.Mean/1000)
+	}
+	want := []SlowRun{
+		{Start: samples[1].Time(), End: samples[4].Time(), N: 3, PeakMs: 800},
+		{Start: samples[6].Time(), End: samples[6].Time(), N: 1, PeakMs: 900},
+	}
+	if len(o.Slow) != len(want) {
+		t.Fatalf("got %d runs, want %d: %+v", len(o.Slow), len(want), o.Slow)
+	}
+	for i := range want {
+		if o.Slow[i] != want[i] {
+			t.Errorf("run %d: got %+v, want %+v", i, o.Slow[i], want[i])
+		}
+	}
+}
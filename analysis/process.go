@@ -0,0 +1,68 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package analysis
+
+import (
+	"time"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// ── Watched processes ─────────────────────────────────────────────────────────
+
+// ProcessSummary is the readings of one process watched with -pid.  A pid
+// given to a later process is another ProcessSummary.
+type ProcessSummary struct {
+	Pid     int32
+	Name    string
+	Started time.Time
+
+	First, Last time.Time // of its readings
+	Readings    int
+
+	CpuMean, CpuPeak float64 // percent of one core
+	RssPeak          int64
+	ThreadsPeak      int32
+	FdsPeak          int32 // 0 if they were never counted
+
+	// Exited is when it was seen to have gone; zero if it outlived the
+	// capture.
+	Exited time.Time
+}
+
+// SummarizeProcesses summarises ps by process, in the order each was first
+// read.
+func SummarizeProcesses(ps []metrics.Process) []ProcessSummary {
+	type key struct {
+		pid     int32
+		started int64
+	}
+	var out []ProcessSummary
+	index := map[key]int{}
+	for _, p := range ps {
+		k := key{p.Pid, p.StartedUnixMs}
+		i, ok := index[k]
+		if !ok {
+			i = len(out)
+			index[k] = i
+			out = append(out, ProcessSummary{Pid: p.Pid, Name: p.Name, Started: time.UnixMilli(p.StartedUnixMs).UTC()})
+		}
+		s := &out[i]
+		if p.Exited {
+			s.Exited = p.Time()
+			continue
+		}
+		if s.Readings == 0 {
+			s.First = p.Time()
+		}
+		s.Last = p.Time()
+		s.Readings++
+		s.CpuMean += (p.CpuPercent - s.CpuMean) / float64(s.Readings)
+		s.CpuPeak = max(s.CpuPeak, p.CpuPercent)
+		s.RssPeak = max(s.RssPeak, p.RssBytes)
+		s.ThreadsPeak = max(s.ThreadsPeak, p.Threads)
+		s.FdsPeak = max(s.FdsPeak, p.Fds)
+	}
+	return out
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+func TestSummarizeProcesses(t *testing.T) {
+	ps := []metrics.Process{
+		{TimestampUnixMs: 1000, Pid: 7, StartedUnixMs: 500, Name: "nginx", CpuPercent: 10, RssBytes: 100, Threads: 4, Fds: 20},
+		{TimestampUnixMs: 1000, Pid: 9, StartedUnixMs: 600, Name: "redis", CpuPercent: 1, RssBytes: 50, Threads: 2},
+		{TimestampUnixMs: 2000, Pid: 7, StartedUnixMs: 500, Name: "nginx", CpuPercent: 30, RssBytes: 80, Threads: 6, Fds: 18},
+		{TimestampUnixMs: 2500, Pid: 7, StartedUnixMs: 500, Name: "nginx", Exited: true},
+		// The pid given to another process.
+		{TimestampUnixMs: 3000, Pid: 7, StartedUnixMs: 2800, Name: "sh", CpuPercent: 2, RssBytes: 10, Threads: 1, Fds: 3},
+	}
+	got := SummarizeProcesses(ps)
+	ms := func(v int64) time.Time { return time.UnixMilli(v).UTC() }
+	want := []ProcessSummary{
+		{Pid: 7, Name: "nginx", Started: ms(500), First: ms(1000), Last: ms(2000), Readings: 2,
+			CpuMean: 20, CpuPeak: 30, RssPeak: 100, ThreadsPeak: 6, FdsPeak: 20, Exited: ms(2500)},
+		{Pid: 9, Name: "redis", Started: ms(600), First: ms(1000), Last: ms(1000), Readings: 1,
+			CpuMean: 1, CpuPeak: 1, RssPeak: 50, ThreadsPeak: 2},
+		{Pid: 7, Name: "sh", Started: ms(2800), First: ms(3000), Last: ms(3000), Readings: 1,
+			CpuMean: 2, CpuPeak: 2, RssPeak: 10, ThreadsPeak: 1, FdsPeak: 3},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d processes, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("%d: got %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if got := SummarizeProcesses(nil); got != nil {
+		t.Errorf("none: got %+v", got)
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package analysis
+
+import (
+	"math"
+	"sort"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// ── Running statistics ────────────────────────────────────────────────────────
+
+// P2 estimates one quantile of a stream in constant space, with the P²
+// algorithm of Jain and Chlamtac (1985): five markers whose heights are
+// nudged along a parabola as values arrive.  Until it has five values it
+// is exact.  Where the quantile falls in a gap between two clusters of
+// values, the estimate may be anywhere in the gap.
+type P2 struct {
+	p     float64
+	count int
+	q     [5]float64 // marker heights
+	n     [5]float64 // marker positions
+	want  [5]float64 // desired positions
+	step  [5]float64 // increments of want per value
+}
+
+// NewP2 returns an estimator of the p-th quantile, p in [0, 1].
+func NewP2(p float64) *P2 {
+	return &P2{
+		p:    p,
+		n:    [5]float64{0, 1, 2, 3, 4},
+		want: [5]float64{0, 2 * p, 4 * p, 2 + 2*p, 4},
+		step: [5]float64{0, p / 2, p, (1 + p) / 2, 1},
+	}
+}
+
+// Add counts x.
+func (e *P2) Add(x float64) {
+	if e.count < len(e.q) {
+		e.q[e.count] = x
+		e.count++
+		if e.count == len(e.q) {
+			sort.Float64s(e.q[:])
+		}
+		return
+	}
+	e.count++
+
+	// The cell x falls in, stretching the outer markers to take it.
+	var k int
+	switch {
+	case x < e.q[0]:
+		e.q[0], k = x, 0
+	case x >= e.q[4]:
+		e.q[4], k = x, 3
+	default:
+		for k = 0; x >= e.q[k+1]; k++ {
+		}
+	}
+	for i := k + 1; i < 5; i++ {
+		e.n[i]++
+	}
+	for i := range e.want {
+		e.want[i] += e.step[i]
+	}
+
+	// Move each middle marker that is a position or more off where it
+	// should be, if there is room between its neighbours.
+	for i := 1; i <= 3; i++ {
+		d := e.want[i] - e.n[i]
+		if (d >= 1 && e.n[i+1]-e.n[i] > 1) || (d <= -1 && e.n[i-1]-e.n[i] < -1) {
+			d = math.Copysign(1, d)
+			q := e.parabolic(i, d)
+			if e.q[i-1] >= q || q >= e.q[i+1] {
+				j := i + int(d)
+				q = e.q[i] + d*(e.q[j]-e.q[i])/(e.n[j]-e.n[i])
+			}
+			e.q[i] = q
+			e.n[i] += d
+		}
+	}
+}
+
+// parabolic is the P² prediction of marker i's height moved d (±1).
+func (e *P2) parabolic(i int, d float64) float64 {
+	q, n := &e.q, &e.n
+	return q[i] + d/(n[i+1]-n[i-1])*
+		((n[i]-n[i-1]+d)*(q[i+1]-q[i])/(n[i+1]-n[i])+
+			(n[i+1]-n[i]-d)*(q[i]-q[i-1])/(n[i]-n[i-1]))
+}
+
+// Value returns the estimate: 0 before any value, and the exact quantile,
+// as Percentile gives it, of fewer than five.
+func (e *P2) Value() float64 {
+	if e.count < len(e.q) {
+		sorted := append([]float64(nil), e.q[:e.count]...)
+		sort.Float64s(sorted)
+		return Percentile(sorted, e.p*100)
+	}
+	return e.q[2]
+}
+
+// Running accumulates the Stats of a series one value at a time, as
+// Summarize would compute them over the whole of it: the count, extremes
+// and mean exactly, and the percentiles as P² estimates.
+type Running struct {
+	n             int
+	min, max      float64
+	mean          float64
+	p50, p95, p99 *P2
+}
+
+// NewRunning returns an empty Running.
+func NewRunning() *Running {
+	return &Running{p50: NewP2(0.50), p95: NewP2(0.95), p99: NewP2(0.99)}
+}
+
+// Add counts x.
+func (r *Running) Add(x float64) {
+	r.n++
+	if r.n == 1 || x < r.min {
+		r.min = x
+	}
+	if r.n == 1 || x > r.max {
+		r.max = x
+	}
+	r.mean += (x - r.mean) / float64(r.n)
+	r.p50.Add(x)
+	r.p95.Add(x)
+	r.p99.Add(x)
+}
+
+// Stats returns the statistics so far; zero Stats before any value.
+func (r *Running) Stats() Stats {
+	if r.n == 0 {
+		return Stats{}
+	}
+	return Stats{
+		N:    r.n,
+		Min:  r.min,
+		Mean: r.mean,
+		P50:  r.p50.Value(),
+		P95:  r.p95.Value(),
+		P99:  r.p99.Value(),
+		Max:  r.max,
+	}
+}
+
+// RunningSummary keeps a Summary up to date sample by sample, for captures
+// too long, or still growing too fast, to summarise again from the start.
+type RunningSummary map[string]*Running
+
+// NewRunningSummary returns a RunningSummary of every entry of Metrics.
+func NewRunningSummary() RunningSummary {
+	rs := make(RunningSummary, len(Metrics))
+	for _, m := range Metrics {
+		rs[m.Name] = NewRunning()
+	}
+	return rs
+}
+
+// Add counts s, as SummarizeSamples would.
+func (rs RunningSummary) Add(s *metrics.Sample) {
+	for _, m := range Metrics {
+		rs[m.Name].Add(m.Value(s))
+	}
+}
+
+// Summary returns the statistics so far.
+func (rs RunningSummary) Summary() Summary {
+	sum := make(Summary, len(rs))
+	for name, r := range rs {
+		sum[name] = r.Stats()
+	}
+	return sum
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package analysis
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// The P² estimates land within a small share of the range of the exact
+// percentiles, whatever the shape of the series.
+func TestRunningAgainstExact(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	tests := []struct {
+		name string
+		gen  func(i int) float64
+	}{
+		{"uniform", func(int) float64 { return 100 * rng.Float64() }},
+		{"normal", func(int) float64 { return 50 + 10*rng.NormFloat64() }},
+		{"ramp", func(i int) float64 { return float64(i % 1000) }},
+		{"mostly idle with bursts", func(i int) float64 {
+			if rng.Intn(10) == 0 {
+				return 80 + 20*rng.Float64()
+			}
+			return 2 * rng.Float64()
+		}},
+		{"exponential", func(int) float64 { return rng.ExpFloat64() }},
+	}
+	for _, tt := range tests {
+		vals := make([]float64, 20000)
+		r := NewRunning()
+		for i := range vals {
+			vals[i] = tt.gen(i)
+			r.Add(vals[i])
+		}
+		got, want := r.Stats(), Summarize(vals)
+		if got.N != want.N || got.Min != want.Min || got.Max != want.Max || math.Abs(got.Mean-want.Mean) > 1e-9*math.Max(1, math.Abs(want.Mean)) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, want)
+		}
+		tol := 0.02 * (want.Max - want.Min)
+		for _, p := range []struct {
+			name      string
+			got, want float64
+		}{{"p50", got.P50, want.P50}, {"p95", got.P95, want.P95}, {"p99", got.P99, want.P99}} {
+			if math.Abs(p.got-p.want) > tol {
+				t.Errorf("%s %s: got %.3f, want %.3f ± %.3f", tt.name, p.name, p.got, p.want, tol)
+			}
+		}
+	}
+}
+
+// Under five values the percentiles are exact.
+func TestRunningFew(t *testing.T) {
+	for n := 0; n <= 5; n++ {
+		vals := []float64{30, 10, 50, 20, 40}[:n]
+		r := NewRunning()
+		for _, v := range vals {
+			r.Add(v)
+		}
+		if got, want := r.Stats(), Summarize(vals); n < 5 && got != want {
+			t.Errorf("%d values: got %+v, want %+v", n, got, want)
+		}
+	}
+}
+
+func TestRunningSummary(t *testing.T) {
+	var samples []metrics.Sample
+	rs := NewRunningSummary()
+	for i := 0; i < 3; i++ {
+		s := metrics.Sample{CpuTotal: float64(10 * i), MemPercent: 50, Load1: float64(i)}
+		samples = append(samples, s)
+		rs.Add(&s)
+	}
+	got, want := rs.Summary(), SummarizeSamples(samples)
+	for _, m := range Metrics {
+		if got[m.Name] != want[m.Name] {
+			t.Errorf("%s: got %+v, want %+v", m.Name, got[m.Name], want[m.Name])
+		}
+	}
+}
@@ -0,0 +1,239 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+// Package analysis computes offline statistics over recorded .infgo
+// captures: per-metric summaries, threshold assertions, and the helpers the
+// `infgo analyze` family of subcommands is built from.
+//
+// Everything here operates on plain metrics.Sample slices so it can be unit
+// tested without touching the filesystem.
+package analysis
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/ALH477/infgo/logger"
+	"github.com/ALH477/infgo/metrics"
+)
+
+// ── Capture ───────────────────────────────────────────────────────────────────
+
+// Capture is a fully-loaded recording.
+type Capture struct {
+	Header    *metrics.Header // first header record; nil if the file has none
+	Samples   []metrics.Sample
+	Events    []metrics.Event
+	Processes []metrics.Process // readings of the processes watched with -pid
+}
+
+// Load reads every record from rd.  The caller still owns rd.
+func Load(rd *logger.Reader) (*Capture, error) {
+	c := &Capture{}
+	for {
+		rec, err := rd.Next()
+		if err == io.EOF {
+			return c, nil
+		}
+		if err != nil {
+			return c, err
+		}
+		switch {
+		case rec.Header != nil && c.Header == nil:
+			c.Header = rec.Header
+		case rec.Sample != nil:
+			c.Samples = append(c.Samples, *rec.Sample)
+		case rec.Event != nil:
+			c.Events = append(c.Events, *rec.Event)
+		case rec.Process != nil:
+			c.Processes = append(c.Processes, *rec.Process)
+		}
+	}
+}
+
+// ── Metrics ───────────────────────────────────────────────────────────────────
+
+// Metric names one scalar series that can be extracted from a Sample.
+type Metric struct {
+	Name  string // short name used in expressions, e.g. "cpu"
+	Label string // human-readable label for reports
+	Unit  string // "%" or "" for dimensionless values
+	Value func(*metrics.Sample) float64
+	Group metrics.Missing // the group Value reads; see Sample.Missing
+}
+
+// Metrics lists every summarised series in report order.
+var Metrics = []Metric{
+	{"cpu", "CPU %", "%", func(s *metrics.Sample) float64 { return s.CpuTotal }, metrics.MissingCPU},
+	{"mem", "Memory %", "%", func(s *metrics.Sample) float64 { return s.MemPercent }, metrics.MissingMem},
+	{"load1", "Load 1m", "", func(s *metrics.Sample) float64 { return s.Load1 }, metrics.MissingLoad},
+	{"load5", "Load 5m", "", func(s *metrics.Sample) float64 { return s.Load5 }, metrics.MissingLoad},
+	{"load15", "Load 15m", "", func(s *metrics.Sample) float64 { return s.Load15 }, metrics.MissingLoad},
+}
+
+// LookupMetric returns the metric called name, or false.
+func LookupMetric(name string) (Metric, bool) {
+	for _, m := range Metrics {
+		if m.Name == name {
+			return m, true
+		}
+	}
+	return Metric{}, false
+}
+
+// Series extracts m from every sample.
+func Series(samples []metrics.Sample, m Metric) []float64 {
+	out := make([]float64, len(samples))
+	for i := range samples {
+		out[i] = m.Value(&samples[i])
+	}
+	return out
+}
+
+// ── Summary statistics ────────────────────────────────────────────────────────
+
+// Stats summarises one series.
+type Stats struct {
+	N    int
+	Min  float64
+	Mean float64
+	P50  float64
+	P95  float64
+	P99  float64
+	Max  float64
+}
+
+// StatNames lists the statistic names accepted by Stats.Get.
+var StatNames = []string{"min", "mean", "p50", "p95", "p99", "max"}
+
+// Get returns the statistic called name ("mean", "p95", …).
+func (s Stats) Get(name string) (float64, error) {
+	switch name {
+	case "min":
+		return s.Min, nil
+	case "mean", "avg":
+		return s.Mean, nil
+	case "p50", "median":
+		return s.P50, nil
+	case "p95":
+		return s.P95, nil
+	case "p99":
+		return s.P99, nil
+	case "max":
+		return s.Max, nil
+	default:
+		return 0, fmt.Errorf("unknown statistic %q", name)
+	}
+}
+
+// Summarize computes Stats over vals.  An empty input yields zero Stats.
+func Summarize(vals []float64) Stats {
+	if len(vals) == 0 {
+		return Stats{}
+	}
+	sorted := append([]float64(nil), vals...)
+	sort.Float64s(sorted)
+
+	var sum float64
+	for _, v := range sorted {
+		sum += v
+	}
+	return Stats{
+		N:    len(sorted),
+		Min:  sorted[0],
+		Mean: sum / float64(len(sorted)),
+		P50:  Percentile(sorted, 50),
+		P95:  Percentile(sorted, 95),
+		P99:  Percentile(sorted, 99),
+		Max:  sorted[len(sorted)-1],
+	}
+}
+
+// Percentile returns the p-th percentile (0–100) of an ascending slice using
+// linear interpolation between the closest ranks.
+func Percentile(sorted []float64, p float64) float64 {
+	switch len(sorted) {
+	case 0:
+		return 0
+	case 1:
+		return sorted[0]
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	if lo < 0 {
+		return sorted[0]
+	}
+	if hi >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	frac := rank - float64(lo)
+	return sorted[lo] + (sorted[hi]-sorted[lo])*frac
+}
+
+// Summary holds Stats for every entry of Metrics, keyed by metric name.
+type Summary map[string]Stats
+
+// SummarizeSamples computes a Summary over samples, with the used
+// percentage of each mount in their Disks under DiskMetric's key.
+func SummarizeSamples(samples []metrics.Sample) Summary {
+	sum := make(Summary, len(Metrics))
+	for _, m := range Metrics {
+		sum[m.Name] = Summarize(Series(samples, m))
+	}
+	for _, mount := range Mounts(samples) {
+		sum[DiskMetric(mount)] = Summarize(DiskSeries(samples, mount))
+	}
+	return sum
+}
+
+// ── Disks ─────────────────────────────────────────────────────────────────────
+
+const diskPrefix = "disk:"
+
+// DiskMetric is the Summary key of the used percentage of the filesystem
+// mounted at mount, e.g. "disk:/".
+func DiskMetric(mount string) string { return diskPrefix + mount }
+
+// Mounts lists the mounts with a reading in samples, sorted.
+func Mounts(samples []metrics.Sample) []string {
+	seen := map[string]bool{}
+	var out []string
+	for i := range samples {
+		for _, d := range samples[i].Disks {
+			if !seen[d.Mount] {
+				seen[d.Mount] = true
+				out = append(out, d.Mount)
+			}
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// DiskSeries extracts the used percentage of mount from the samples that
+// have a reading of it.
+func DiskSeries(samples []metrics.Sample, mount string) []float64 {
+	var out []float64
+	for i := range samples {
+		if d, ok := samples[i].Disk(mount); ok {
+			out = append(out, d.UsedPercent)
+		}
+	}
+	return out
+}
+
+// Mounts lists the mounts summarised in sum, sorted.
+func (sum Summary) Mounts() []string {
+	var out []string
+	for k := range sum {
+		if mount, ok := strings.CutPrefix(k, diskPrefix); ok {
+			out = append(out, mount)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
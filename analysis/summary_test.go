@@ -0,0 +1,81 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package analysis
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+func TestPercentile(t *testing.T) {
+	sorted := []float64{10, 20, 30, 40, 50}
+	tests := []struct {
+		p    float64
+		want float64
+	}{
+		{0, 10},
+		{50, 30},
+		{100, 50},
+		{25, 20},
+		{90, 46},
+	}
+	for _, tt := range tests {
+		if got := Percentile(sorted, tt.p); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Percentile(%v): got %v, want %v", tt.p, got, tt.want)
+		}
+	}
+	if got := Percentile(nil, 50); got != 0 {
+		t.Errorf("Percentile(nil): got %v, want 0", got)
+	}
+}
+
+func TestSummarizeSamples(t *testing.T) {
+	var samples []metrics.Sample
+	for i := 1; i <= 100; i++ {
+		samples = append(samples, metrics.Sample{CpuTotal: float64(i), MemPercent: 50})
+	}
+	sum := SummarizeSamples(samples)
+
+	cpu := sum["cpu"]
+	if cpu.N != 100 || cpu.Min != 1 || cpu.Max != 100 || cpu.Mean != 50.5 {
+		t.Errorf("cpu stats: got %+v", cpu)
+	}
+	if math.Abs(cpu.P95-95.05) > 1e-9 {
+		t.Errorf("cpu p95: got %v, want 95.05", cpu.P95)
+	}
+	if mem := sum["mem"]; mem.Min != 50 || mem.Max != 50 {
+		t.Errorf("mem stats: got %+v", mem)
+	}
+	if _, ok := sum["load15"]; !ok {
+		t.Error("summary is missing load15")
+	}
+	if m := sum.Mounts(); len(m) != 0 {
+		t.Errorf("mounts without disks: got %v", m)
+	}
+}
+
+// Each mount is summarised over the samples that have a reading of it.
+func TestSummarizeDisks(t *testing.T) {
+	samples := []metrics.Sample{
+		{Disks: []metrics.DiskUsage{{Mount: "/var", UsedPercent: 10}, {Mount: "/", UsedPercent: 40}}},
+		{Disks: []metrics.DiskUsage{{Mount: "/", UsedPercent: 50}}},
+		{},
+	}
+	if got := Mounts(samples); !slices.Equal(got, []string{"/", "/var"}) {
+		t.Errorf("Mounts: got %v", got)
+	}
+	sum := SummarizeSamples(samples)
+	if got := sum.Mounts(); !slices.Equal(got, []string{"/", "/var"}) {
+		t.Errorf("Summary.Mounts: got %v", got)
+	}
+	if root := sum[DiskMetric("/")]; root.N != 2 || root.Min != 40 || root.Max != 50 || root.Mean != 45 {
+		t.Errorf("/: got %+v", root)
+	}
+	if v := sum[DiskMetric("/var")]; v.N != 1 || v.Mean != 10 {
+		t.Errorf("/var: got %+v", v)
+	}
+}
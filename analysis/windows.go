@@ -0,0 +1,134 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package analysis
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// ── Fixed windows ─────────────────────────────────────────────────────────────
+
+// Window is a contiguous slice of a capture between Start (inclusive) and
+// End (exclusive).
+type Window struct {
+	Start   time.Time
+	End     time.Time
+	Samples []metrics.Sample // sub-slice of the input; do not modify
+	Summary Summary
+}
+
+// SplitWindows slices time-ordered samples into consecutive windows of width
+// size.  With align set, window boundaries fall on wall-clock multiples of
+// size in loc (14:31:00, 14:32:00, …); otherwise the first window starts at
+// the first sample.  Empty windows (capture gaps) are omitted.
+func SplitWindows(samples []metrics.Sample, size time.Duration, align bool, loc *time.Location) []Window {
+	if len(samples) == 0 || size <= 0 {
+		return nil
+	}
+	first := samples[0].Time()
+	start := first
+	if align {
+		start = alignTime(first, size, loc)
+	}
+
+	var out []Window
+	i := 0
+	for i < len(samples) {
+		// Skip whole empty windows in one step so long gaps stay cheap.
+		t := samples[i].Time()
+		if skip := t.Sub(start) / size; skip > 0 {
+			start = start.Add(skip * size)
+		}
+		end := start.Add(size)
+		j := i
+		for j < len(samples) && samples[j].Time().Before(end) {
+			j++
+		}
+		out = append(out, Window{
+			Start:   start,
+			End:     end,
+			Samples: samples[i:j],
+			Summary: SummarizeSamples(samples[i:j]),
+		})
+		i = j
+		start = end
+	}
+	return out
+}
+
+// TopWindows returns the n windows with the highest mean of m, busiest
+// first.  Ties keep chronological order.
+func TopWindows(windows []Window, m Metric, n int) []Window {
+	ranked := append([]Window(nil), windows...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Summary[m.Name].Mean > ranked[j].Summary[m.Name].Mean
+	})
+	if n < len(ranked) {
+		ranked = ranked[:n]
+	}
+	return ranked
+}
+
+// alignTime rounds t down to a multiple of size on the wall clock of loc.
+func alignTime(t time.Time, size time.Duration, loc *time.Location) time.Time {
+	_, off := t.In(loc).Zone()
+	shifted := t.Add(time.Duration(off) * time.Second)
+	return shifted.Truncate(size).Add(-time.Duration(off) * time.Second)
+}
+
+// ── Plain-text sparklines ─────────────────────────────────────────────────────
+
+// sparkRunes is the block-element ramp shared with the live TUI.
+var sparkRunes = []rune{'▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
+
+// Sparkline renders vals as block characters without any ANSI styling, so it
+// can be embedded in plain text, Markdown, or CSV.  Values are scaled against
+// ceil (e.g. 100 for percentages).  When there are more values than width,
+// each column shows the maximum of the values it covers so spikes survive.
+func Sparkline(vals []float64, width int, ceil float64) string {
+	if len(vals) == 0 || width <= 0 {
+		return ""
+	}
+	if ceil <= 0 {
+		ceil = 1
+	}
+	cols := vals
+	if len(vals) > width {
+		cols = make([]float64, width)
+		for c := range cols {
+			lo := c * len(vals) / width
+			hi := (c + 1) * len(vals) / width
+			peak := vals[lo]
+			for _, v := range vals[lo:hi] {
+				peak = max(peak, v)
+			}
+			cols[c] = peak
+		}
+	}
+
+	var sb strings.Builder
+	for _, v := range cols {
+		idx := int(v/ceil*float64(len(sparkRunes)-1) + 0.5)
+		idx = min(max(idx, 0), len(sparkRunes)-1)
+		sb.WriteRune(sparkRunes[idx])
+	}
+	return sb.String()
+}
+
+// Ceil returns a sensible sparkline ceiling for m over vals: 100 for
+// percentages, otherwise the series maximum.
+func Ceil(m Metric, vals []float64) float64 {
+	if m.Unit == "%" {
+		return 100
+	}
+	var hi float64
+	for _, v := range vals {
+		hi = max(hi, v)
+	}
+	return hi
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package analysis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// secondSamples returns one sample per second from start with the given
+// CpuTotal values.
+func secondSamples(start time.Time, cpu ...float64) []metrics.Sample {
+	out := make([]metrics.Sample, len(cpu))
+	for i, v := range cpu {
+		out[i] = metrics.Sample{
+			TimestampUnixMs: start.Add(time.Duration(i) * time.Second).UnixMilli(),
+			CpuTotal:        v,
+		}
+	}
+	return out
+}
+
+func TestSplitWindowsAligned(t *testing.T) {
+	loc := time.FixedZone("IST", 5*3600+1800) // half-hour offset exercises alignment
+	start := time.Date(2026, 1, 14, 14, 30, 45, 0, loc)
+	samples := secondSamples(start, make([]float64, 90)...)
+
+	windows := SplitWindows(samples, time.Minute, true, loc)
+	if len(windows) != 3 {
+		t.Fatalf("windows: got %d, want 3", len(windows))
+	}
+	if want := time.Date(2026, 1, 14, 14, 30, 0, 0, loc); !windows[0].Start.Equal(want) {
+		t.Errorf("first window start: got %v, want %v", windows[0].Start, want)
+	}
+	if n := len(windows[0].Samples); n != 15 {
+		t.Errorf("first window samples: got %d, want 15", n)
+	}
+
+	unaligned := SplitWindows(samples, time.Minute, false, loc)
+	if len(unaligned) != 2 || !unaligned[0].Start.Equal(start) {
+		t.Errorf("unaligned: got %d windows starting %v", len(unaligned), unaligned[0].Start)
+	}
+}
+
+func TestSplitWindowsSkipsGaps(t *testing.T) {
+	start := time.Date(2026, 1, 14, 0, 0, 0, 0, time.UTC)
+	samples := append(secondSamples(start, 1, 2),
+		secondSamples(start.Add(time.Hour), 3, 4)...)
+
+	windows := SplitWindows(samples, time.Minute, true, time.UTC)
+	if len(windows) != 2 {
+		t.Fatalf("windows: got %d, want 2 (gap windows must be omitted)", len(windows))
+	}
+	if !windows[1].Start.Equal(start.Add(time.Hour)) {
+		t.Errorf("second window start: got %v", windows[1].Start)
+	}
+}
+
+func TestTopWindows(t *testing.T) {
+	start := time.Date(2026, 1, 14, 0, 0, 0, 0, time.UTC)
+	var cpu []float64
+	for _, level := range []float64{10, 90, 50, 90} {
+		for i := 0; i < 10; i++ {
+			cpu = append(cpu, level)
+		}
+	}
+	windows := SplitWindows(secondSamples(start, cpu...), 10*time.Second, true, time.UTC)
+	m, _ := LookupMetric("cpu")
+	top := TopWindows(windows, m, 3)
+
+	if len(top) != 3 {
+		t.Fatalf("top: got %d, want 3", len(top))
+	}
+	wantStarts := []time.Duration{10 * time.Second, 30 * time.Second, 20 * time.Second}
+	for i, w := range top {
+		if !w.Start.Equal(start.Add(wantStarts[i])) {
+			t.Errorf("top[%d] start: got %v, want +%v", i, w.Start, wantStarts[i])
+		}
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	if got := Sparkline([]float64{0, 50, 100}, 10, 100); got != "▁▅█" {
+		t.Errorf("Sparkline: got %q", got)
+	}
+	// Eight values into four columns keeps the spike.
+	if got := Sparkline([]float64{0, 0, 0, 100, 0, 0, 0, 0}, 4, 100); got != "▁█▁▁" {
+		t.Errorf("Sparkline downsampled: got %q", got)
+	}
+	if got := Sparkline(nil, 10, 100); got != "" {
+		t.Errorf("Sparkline(nil): got %q", got)
+	}
+}
@@ -0,0 +1,497 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ALH477/infgo/analysis"
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ui"
+)
+
+// ── analyze ───────────────────────────────────────────────────────────────────
+
+// runAnalyze implements `infgo analyze <capture.infgo> [-fail-if EXPR]… [-correlate] [-heatmap] [-json] [-watch D] [-tz ZONE]`.
+func runAnalyze(args []string) error {
+	fs := newFlagSet("analyze", "<capture.infgo|-> [-fail-if 'metric.stat>N']... [-json]")
+	jsonOut := fs.Bool("json", false, "print the evaluated assertions as JSON instead of the text report")
+	top := fs.Int("top", 0, "list the `N` busiest windows")
+	window := fs.Duration("window", time.Minute, "window width for -top")
+	rankBy := fs.String("rank", "cpu", "metric that ranks -top windows: cpu, mem or load1")
+	noAlign := fs.Bool("no-align", false, "start -top windows at the first sample instead of on wall-clock boundaries")
+	correlate := fs.Bool("correlate", false, "print Pearson correlations between cpu, mem and load1")
+	forecastFor := fs.Duration("forecast-window", ui.ForecastWindow, "forecast memory from the trend of the capture's last `D` (0 to disable)")
+	watch := fs.Duration("watch", 0, "follow a capture still being written, reading what was appended and redrawing the report every `D`")
+	lag := fs.Duration("lag", 0, "with -correlate, also sweep lags up to ±`D` (e.g. 60s) and report the strongest")
+	heatmap := fs.Bool("heatmap", false, "chart the mean of each hour of each day")
+	heatBy := fs.String("heatmap-metric", "cpu", "metric the -heatmap shows: cpu, mem, load1, load5 or load15")
+	utc := fs.Bool("utc", false, "the same as -tz utc")
+	tz := addTZFlag(fs)
+	format := fs.String("o", "text", "output `format`: text, or html for the -heatmap alone as a web page")
+	addFormatFlags(fs)
+	var conds []analysis.Comparison
+	fs.Func("fail-if", "exit 1 if `metric.stat<op>N` holds, e.g. cpu.p95>80 (repeatable)", func(v string) error {
+		c, err := analysis.ParseAssertion(v)
+		if err != nil {
+			return err
+		}
+		conds = append(conds, c)
+		return nil
+	})
+
+	pos, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 1 {
+		return usageErrorf(fs, "expected exactly one input capture")
+	}
+	rank, ok := analysis.LookupMetric(*rankBy)
+	if !ok {
+		return usageErrorf(fs, "-rank: unknown metric %q", *rankBy)
+	}
+	if *top > 0 && *window <= 0 {
+		return usageErrorf(fs, "-window must be positive")
+	}
+	if *lag < 0 {
+		return usageErrorf(fs, "-lag must not be negative")
+	}
+	heatMetric, ok := analysis.LookupMetric(*heatBy)
+	if !ok {
+		return usageErrorf(fs, "-heatmap-metric: unknown metric %q", *heatBy)
+	}
+	switch *format {
+	case "text":
+	case "html":
+		if !*heatmap || *jsonOut || *top > 0 || *correlate || len(conds) > 0 {
+			return usageErrorf(fs, "-o html needs -heatmap, and cannot be combined with -json, -top, -correlate or -fail-if")
+		}
+	default:
+		return usageErrorf(fs, "-o: unsupported format %q (supported: text, html)", *format)
+	}
+	if *forecastFor < 0 {
+		return usageErrorf(fs, "-forecast-window must not be negative")
+	}
+	if *watch < 0 {
+		return usageErrorf(fs, "-watch must not be negative")
+	}
+	if *utc {
+		*tz = "utc"
+	}
+	if *watch > 0 {
+		if *jsonOut || *top > 0 || *correlate || *heatmap {
+			return usageErrorf(fs, "-watch cannot be combined with -json, -top, -correlate or -heatmap")
+		}
+		if pos[0] == stdinPath {
+			return errNeedsFile("analyze -watch", "it reads the file again as it grows")
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		return watchAnalyze(ctx, pos[0], *watch, *forecastFor, *tz, conds, os.Stdout, stdoutIsTerminal())
+	}
+
+	capture, err := loadCapture(pos[0])
+	if err != nil {
+		return err
+	}
+	if len(capture.Samples) == 0 {
+		return fmt.Errorf("%s contains no samples", inputName(pos[0]))
+	}
+	zone := resolveZone(*tz, capture.Header)
+	if *format == "html" {
+		host := "unknown host"
+		if capture.Header != nil && capture.Header.Hostname != "" {
+			host = capture.Header.Hostname
+		}
+		return writeHeatmapHTML(os.Stdout, host, analysis.HourlyHeatmap(capture.Samples, heatMetric, zone.loc), zone)
+	}
+	sum := analysis.SummarizeSamples(capture.Samples)
+
+	results := make([]analysis.Assertion, len(conds))
+	failed := 0
+	for i, c := range conds {
+		results[i] = analysis.Evaluate(sum, c)
+		if results[i].Failed {
+			failed++
+		}
+	}
+
+	var pairs []analysis.Pair
+	if *correlate {
+		pairs = analysis.Correlate(capture.Samples, correlateMetrics(), *lag)
+	}
+
+	if *jsonOut {
+		if err := writeReportJSON(os.Stdout, pos[0], results, failed, pairs); err != nil {
+			return err
+		}
+	} else {
+		printSummary(os.Stdout, capture, sum, captureForecast(capture.Samples, *forecastFor), zone)
+		if *top > 0 {
+			windows := analysis.SplitWindows(capture.Samples, *window, !*noAlign, zone.loc)
+			printTopWindows(os.Stdout, analysis.TopWindows(windows, rank, *top), rank, *window, zone.loc)
+		}
+		if *correlate {
+			printCorrelations(os.Stdout, pairs, *lag > 0)
+		}
+		if *heatmap {
+			printHeatmap(os.Stdout, analysis.HourlyHeatmap(capture.Samples, heatMetric, zone.loc), zone)
+		}
+		printAssertions(os.Stdout, results)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d assertions failed", failed, len(results))
+	}
+	return nil
+}
+
+// loadCapture opens and fully reads the capture at path ("-" for stdin).
+func loadCapture(path string) (*analysis.Capture, error) {
+	rd, err := openCapture(path)
+	if err != nil {
+		return nil, err
+	}
+	defer rd.Close()
+	return analysis.Load(rd)
+}
+
+// printSummary writes the session metadata block and statistics table,
+// with the memory forecast at the end of the capture if there is one, and
+// times in zone.
+func printSummary(w io.Writer, c *analysis.Capture, sum analysis.Summary, forecast *analysis.Forecast, zone timeZone) {
+	first, last := c.Samples[0].Time(), c.Samples[len(c.Samples)-1].Time()
+	dur := last.Sub(first)
+	printSession(w, "session report", c.Header, first, last, len(c.Samples), zone)
+	spans := analysis.IdleSpans(c)
+	if len(spans) > 0 && dur > 0 {
+		var idle time.Duration
+		for _, s := range spans {
+			idle += s.Duration()
+		}
+		share := func(d time.Duration) string {
+			return fmt.Sprintf("%s  (%s)", formatDuration(d), ui.FormatPercent(100*d.Seconds()/dur.Seconds()))
+		}
+		fmt.Fprintf(w, "  %-10s %s\n", "Busy", share(dur-idle))
+		periods := "periods"
+		if len(spans) == 1 {
+			periods = "period"
+		}
+		fmt.Fprintf(w, "  %-10s %s in %d %s\n", "Idle", share(idle), len(spans), periods)
+	}
+	printForecast(w, forecast)
+	overhead, timed := analysis.CollectOverhead(c.Samples, analysis.CaptureInterval(c))
+	if timed {
+		share := ""
+		if overhead.Interval > 0 {
+			share = fmt.Sprintf(" (%s of the %s interval)", ui.FormatPercent(100*overhead.Share()), overhead.Interval)
+		}
+		fmt.Fprintf(w, "  %-10s reading took %s mean, %s p95%s\n", "Overhead",
+			ui.FormatMillis(millis(overhead.Mean)), ui.FormatMillis(millis(overhead.P95)), share)
+	}
+
+	printStats(w, "", sum)
+	if len(spans) > 0 {
+		// The same table without the idle periods, whose near-zero CPU
+		// would otherwise drag every average down.
+		printStats(w, "when busy", analysis.SummarizeSamples(analysis.Busy(c.Samples, spans)))
+	}
+	fmt.Fprintln(w)
+	if timed && len(overhead.Slow) > 0 {
+		printSlowRuns(w, overhead, zone.loc)
+	}
+	if len(c.Processes) > 0 {
+		printProcesses(w, analysis.SummarizeProcesses(c.Processes), zone.loc)
+	}
+}
+
+// millis converts a count of milliseconds to a Duration.
+func millis(ms float64) time.Duration { return time.Duration(ms * float64(time.Millisecond)) }
+
+// slowRunsShown caps the list of slow collections.
+const slowRunsShown = 5
+
+// printSlowRuns lists the runs of readings that took over half the
+// interval, when the monitor was too starved to keep to it, with times in
+// loc.
+func printSlowRuns(w io.Writer, o analysis.Overhead, loc *time.Location) {
+	fmt.Fprintf(w, "  Slow readings (over half the %s interval)\n\n", o.Interval)
+	for i, r := range o.Slow {
+		if i == slowRunsShown {
+			fmt.Fprintf(w, "  … and %d more\n", len(o.Slow)-slowRunsShown)
+			break
+		}
+		span := r.Start.In(loc).Format("2006-01-02 15:04:05") + "–" + r.End.In(loc).Format("15:04:05")
+		readings := "readings"
+		if r.N == 1 {
+			readings = "reading"
+		}
+		fmt.Fprintf(w, "  %s  %4d %-8s  peak %s\n", span, r.N, readings, ui.FormatMillis(millis(r.PeakMs)))
+	}
+	fmt.Fprintln(w)
+}
+
+// printProcesses lists the processes watched with -pid: their CPU mean and
+// peak and their peak memory, threads and descriptors, and when those that
+// exited did, in loc.
+func printProcesses(w io.Writer, ps []analysis.ProcessSummary, loc *time.Location) {
+	fmt.Fprintf(w, "  Watched processes\n\n")
+	fmt.Fprintf(w, "  %7s  %-16s %8s %8s %8s %10s %7s %5s\n", "pid", "name", "readings", "cpu avg", "cpu max", "rss max", "threads", "fds")
+	for _, p := range ps {
+		fds := "—"
+		if p.FdsPeak > 0 {
+			fds = strconv.Itoa(int(p.FdsPeak))
+		}
+		name := p.Name
+		if utf8.RuneCountInString(name) > 16 {
+			name = string([]rune(name)[:15]) + "…"
+		}
+		fmt.Fprintf(w, "  %7d  %s %8d %8s %8s %10s %7d %5s", p.Pid, ui.PadVisual(name, 16), p.Readings,
+			ui.FormatPercent(p.CpuMean), ui.FormatPercent(p.CpuPeak), ui.FormatBytes(float64(p.RssPeak)), p.ThreadsPeak, fds)
+		if !p.Exited.IsZero() {
+			fmt.Fprintf(w, "  exited %s", p.Exited.In(loc).Format("2006-01-02 15:04:05"))
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintln(w)
+}
+
+// captureForecast is the memory forecast over the last window of samples,
+// or nil if there is none to show or window is 0.
+func captureForecast(samples []metrics.Sample, window time.Duration) *analysis.Forecast {
+	if window <= 0 {
+		return nil
+	}
+	f, ok := ui.ShownForecast(analysis.MemForecast(samples, window, analysis.MinForecastR2))
+	if !ok {
+		return nil
+	}
+	return &f
+}
+
+// printForecast writes the metadata block's Forecast line, if f is not nil.
+func printForecast(w io.Writer, f *analysis.Forecast) {
+	if f != nil {
+		fmt.Fprintf(w, "  %-10s memory rising %s%%/min at the end; %s\n", "Forecast",
+			ui.FormatNumber(f.PerMinute(), 2), ui.ForecastText(*f))
+	}
+}
+
+// printSession writes the report's title box and the metadata of a
+// capture of n samples from first to last, naming the zone its times are
+// given in.
+func printSession(w io.Writer, what string, h *metrics.Header, first, last time.Time, n int, zone timeZone) {
+	const boxW = 54
+	title := "  infgo  ·  " + what
+	fmt.Fprintf(w, "\n  ┌%s┐\n", strings.Repeat("─", boxW))
+	fmt.Fprintf(w, "  │%s%s│\n", title, strings.Repeat(" ", boxW-utf8.RuneCountInString(title)))
+	fmt.Fprintf(w, "  └%s┘\n\n", strings.Repeat("─", boxW))
+
+	dur := last.Sub(first)
+	if h != nil {
+		fmt.Fprintf(w, "  %-10s %s\n", "Host", h.Hostname)
+		fmt.Fprintf(w, "  %-10s %s\n", "OS", h.Platform)
+	}
+	fmt.Fprintf(w, "  %-10s %s\n", "Started", first.In(zone.loc).Format("2006-01-02 15:04:05 MST"))
+	fmt.Fprintf(w, "  %-10s %s\n", "Zone", zone.name)
+	fmt.Fprintf(w, "  %-10s %s\n", "Duration", formatDuration(dur))
+	rate := ""
+	if dur > 0 {
+		rate = fmt.Sprintf("  (%s Hz)", ui.FormatNumber(float64(n-1)/dur.Seconds(), 2))
+	}
+	fmt.Fprintf(w, "  %-10s %d%s\n", "Samples", n, rate)
+	if h != nil && h.NumCores > 0 {
+		fmt.Fprintf(w, "  %-10s %d logical\n", "Cores", h.NumCores)
+	}
+	if h != nil && h.IntervalMs > 0 {
+		fmt.Fprintf(w, "  %-10s %s\n", "Interval", h.Interval())
+	}
+	// Captures from before the fingerprint have none of it; one from a
+	// session without flags has no Args but the rest.
+	if h != nil && (len(h.Args) > 0 || len(h.Collectors) > 0 || h.ConfigPath != "") {
+		fmt.Fprintf(w, "  %-10s %s\n", "Command", commandLine(h.Args))
+		fmt.Fprintf(w, "  %-10s %s\n", "Collectors", strings.Join(h.Collectors, ", "))
+		fmt.Fprintf(w, "  %-10s %s\n", "Config", h.ConfigPath)
+	}
+}
+
+// printStats writes the min/avg/p95/max table of sum under title.
+func printStats(w io.Writer, title string, sum analysis.Summary) {
+	fmt.Fprintf(w, "\n  %-12s %8s %8s %8s %8s\n", title, "min", "avg", "p95", "max")
+	fmt.Fprintf(w, "  %s\n", strings.Repeat("─", 50))
+	for _, m := range analysis.Metrics {
+		st := sum[m.Name]
+		cell := func(v float64) string {
+			if m.Unit == "%" {
+				return fmt.Sprintf("%8s", ui.FormatPercent(v))
+			}
+			return fmt.Sprintf("%8s", ui.FormatNumber(v, 2))
+		}
+		fmt.Fprintf(w, "  %-12s %s %s %s %s\n", m.Label, cell(st.Min), cell(st.Mean), cell(st.P95), cell(st.Max))
+	}
+	for _, mount := range sum.Mounts() {
+		st := sum[analysis.DiskMetric(mount)]
+		cell := func(v float64) string { return fmt.Sprintf("%8s", ui.FormatPercent(v)) }
+		fmt.Fprintf(w, "  %-12s %s %s %s %s\n", "Disk "+mount, cell(st.Min), cell(st.Mean), cell(st.P95), cell(st.Max))
+	}
+}
+
+// topSparkW is the width of the per-window sparkline in the -top table.
+const topSparkW = 24
+
+// printTopWindows writes the ranked -top table: time range in loc, mean/max
+// of every metric, and a sparkline of the ranking metric across the window.
+func printTopWindows(w io.Writer, top []analysis.Window, rank analysis.Metric, size time.Duration, loc *time.Location) {
+	fmt.Fprintf(w, "  Busiest %s windows by %s mean\n\n", size, rank.Label)
+	fmt.Fprintf(w, "  %2s  %-28s", "#", "window")
+	for _, m := range analysis.Metrics {
+		fmt.Fprintf(w, "  %-15s", m.Name+" mean/max")
+	}
+	fmt.Fprintf(w, "  %s\n", rank.Name)
+
+	for i, win := range top {
+		span := win.Start.In(loc).Format("2006-01-02 15:04:05") + "–" + win.End.In(loc).Format("15:04:05")
+		fmt.Fprintf(w, "  %2d  %s", i+1, span)
+		for _, m := range analysis.Metrics {
+			st := win.Summary[m.Name]
+			fmt.Fprintf(w, "  %-15s", ui.FormatNumber(st.Mean, 1)+"/"+ui.FormatNumber(st.Max, 1))
+		}
+		vals := analysis.Series(win.Samples, rank)
+		fmt.Fprintf(w, "  %s\n", analysis.Sparkline(vals, topSparkW, analysis.Ceil(rank, vals)))
+	}
+	fmt.Fprintln(w)
+}
+
+// printAssertions lists the outcome of every -fail-if condition.
+func printAssertions(w io.Writer, results []analysis.Assertion) {
+	if len(results) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "  Assertions\n")
+	for _, a := range results {
+		status := "ok  "
+		if a.Failed {
+			status = "FAIL"
+		}
+		fmt.Fprintf(w, "  %s  %-20s actual %s (%s)\n", status, a.Expr, ui.FormatNumber(a.Actual, 2), ui.FormatSigned(a.Delta, 2))
+	}
+	fmt.Fprintln(w)
+}
+
+// correlateMetrics returns the metrics compared by -correlate.  The load5
+// and load15 averages are smoothed copies of load1 and would only add noise.
+func correlateMetrics() []analysis.Metric {
+	var ms []analysis.Metric
+	for _, name := range []string{"cpu", "mem", "load1"} {
+		m, _ := analysis.LookupMetric(name)
+		ms = append(ms, m)
+	}
+	return ms
+}
+
+// printCorrelations writes the -correlate table, one row per metric pair,
+// with coefficients colored by magnitude.
+func printCorrelations(w io.Writer, pairs []analysis.Pair, swept bool) {
+	fmt.Fprintf(w, "  Correlation (Pearson r)\n\n")
+	fmt.Fprintf(w, "  %-14s %7s", "pair", "r")
+	if swept {
+		fmt.Fprintf(w, "  %9s %7s", "best lag", "r@lag")
+	}
+	fmt.Fprintln(w)
+	for _, p := range pairs {
+		fmt.Fprintf(w, "  %-14s %s", p.A+" ~ "+p.B, corrCell(p.R))
+		if swept {
+			fmt.Fprintf(w, "  %9s %s", formatLag(p.BestLag), corrCell(p.BestR))
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintln(w)
+}
+
+// corrCell formats r in a 7-wide column, colored red for strong (|r| ≥ 0.7),
+// amber for moderate (≥ 0.4) and gray for weak relationships.
+func corrCell(r float64) string {
+	if math.IsNaN(r) {
+		return lipgloss.NewStyle().Foreground(cGray500).Render(fmt.Sprintf("%7s", "n/a"))
+	}
+	col := cGray500
+	switch a := math.Abs(r); {
+	case a >= 0.7:
+		col = cRed
+	case a >= 0.4:
+		col = cAmber
+	}
+	return lipgloss.NewStyle().Foreground(col).Render(fmt.Sprintf("%7s", ui.FormatSigned(r, 2)))
+}
+
+// formatLag renders a lag with an explicit sign; positive means the second
+// metric follows the first.
+func formatLag(d time.Duration) string {
+	if d > 0 {
+		return "+" + d.String()
+	}
+	return d.String()
+}
+
+// corrJSON is the -json form of an analysis.Pair.  Undefined coefficients
+// become null, since JSON has no NaN.
+type corrJSON struct {
+	A        string   `json:"a"`
+	B        string   `json:"b"`
+	R        *float64 `json:"r"`
+	BestLagS float64  `json:"best_lag_s"`
+	BestR    *float64 `json:"best_r"`
+}
+
+// writeReportJSON writes the machine-readable report: assertions always,
+// correlations when -correlate was given.
+func writeReportJSON(w io.Writer, path string, results []analysis.Assertion, failed int, pairs []analysis.Pair) error {
+	finite := func(v float64) *float64 {
+		if math.IsNaN(v) {
+			return nil
+		}
+		return &v
+	}
+	var corr []corrJSON
+	for _, p := range pairs {
+		corr = append(corr, corrJSON{p.A, p.B, finite(p.R), p.BestLag.Seconds(), finite(p.BestR)})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		File         string               `json:"file"`
+		Assertions   []analysis.Assertion `json:"assertions"`
+		Failed       int                  `json:"failed"`
+		Correlations []corrJSON           `json:"correlations,omitempty"`
+	}{path, results, failed, corr})
+}
+
+// formatDuration renders d as "3d 4h", "2h 5m", "4m 32s" or "12s".
+func formatDuration(d time.Duration) string {
+	s := int64(d.Round(time.Second) / time.Second)
+	switch {
+	case s >= 86400:
+		return fmt.Sprintf("%dd %dh", s/86400, s%86400/3600)
+	case s >= 3600:
+		return fmt.Sprintf("%dh %dm", s/3600, s%3600/60)
+	case s >= 60:
+		return fmt.Sprintf("%dm %ds", s/60, s%60)
+	default:
+		return fmt.Sprintf("%ds", s)
+	}
+}
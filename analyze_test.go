@@ -0,0 +1,169 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/ALH477/infgo/analysis"
+	"github.com/ALH477/infgo/metrics"
+)
+
+func TestRunAnalyzeFailIf(t *testing.T) {
+	// writeTestCapture ramps CpuTotal 0..59, so cpu.max is 59.
+	path := writeTestCapture(t, t.TempDir(), 1704067200000, 60)
+
+	if err := runAnalyze([]string{path, "-fail-if", "cpu.max>90", "-json"}); err != nil {
+		t.Errorf("passing assertion reported failure: %v", err)
+	}
+	err := runAnalyze([]string{path, "-fail-if", "cpu.max>50", "-fail-if", "mem.max>90", "-json"})
+	if err == nil || !strings.Contains(err.Error(), "1 of 2") {
+		t.Errorf("expected 1 of 2 assertions to fail, got %v", err)
+	}
+	if err := runAnalyze([]string{path, "-fail-if", "cpu>50"}); err != errUsage {
+		t.Errorf("malformed assertion: got %v, want errUsage", err)
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{12 * time.Second, "12s"},
+		{4*time.Minute + 32*time.Second, "4m 32s"},
+		{2*time.Hour + 5*time.Minute, "2h 5m"},
+		{76 * time.Hour, "3d 4h"},
+	}
+	for _, tt := range tests {
+		if got := formatDuration(tt.d); got != tt.want {
+			t.Errorf("formatDuration(%v): got %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestWriteReportJSONCorrelations(t *testing.T) {
+	pairs := []analysis.Pair{
+		{A: "cpu", B: "mem", R: 0.5, BestLag: -10 * time.Second, BestR: 0.9},
+		{A: "cpu", B: "load1", R: math.NaN(), BestR: math.NaN()},
+	}
+	var buf bytes.Buffer
+	if err := writeReportJSON(&buf, "c.infgo", nil, 0, pairs); err != nil {
+		t.Fatalf("writeReportJSON failed: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{`"best_lag_s": -10`, `"best_r": 0.9`, `"r": null`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("JSON missing %s:\n%s", want, out)
+		}
+	}
+}
+
+func TestHeatmapOutput(t *testing.T) {
+	cpu, _ := analysis.LookupMetric("cpu")
+	start := time.Date(2026, 3, 7, 22, 0, 0, 0, time.UTC)
+	var samples []metrics.Sample
+	for i := 0; i < 6; i++ {
+		// Two busy hours, then a gap until the 9th.
+		at := start.Add(time.Duration(i) * 20 * time.Minute)
+		if i >= 3 {
+			at = at.Add(30 * time.Hour)
+		}
+		samples = append(samples, metrics.Sample{TimestampUnixMs: at.UnixMilli(), CpuTotal: 95})
+	}
+	hm := analysis.HourlyHeatmap(samples, cpu, time.UTC)
+
+	var text bytes.Buffer
+	printHeatmap(&text, hm, resolveZone("utc", nil))
+	lines := strings.Split(ansi.Strip(text.String()), "\n")
+	want := []string{
+		"  CPU % by hour of day  (mean, UTC)",
+		"",
+		"                 00    03    06    09    12    15    18    21",
+		"  Sat 2026-03-07 · · · · · · · · · · · · · · · · · · · · · · ██· ",
+		"  Sun 2026-03-08 · · · · · · · · · · · · · · · · · · · · · · · · ",
+		"  Mon 2026-03-09 · · · · · ██· · · · · · · · · · · · · · · · · · ",
+		"",
+		"  · no samples   ░ 0–25   ▒ 25–50   ▓ 50–75   █ 75–100 %",
+	}
+	for i, w := range want {
+		if i >= len(lines) || lines[i] != w {
+			t.Fatalf("line %d: got\n%s\nwant\n%s", i, text.String(), strings.Join(want, "\n"))
+		}
+	}
+
+	var page bytes.Buffer
+	if err := writeHeatmapHTML(&page, "bench <1>", hm, resolveZone("utc", nil)); err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range []string{"<h1>bench &lt;1&gt;</h1>", "<svg ", "22:00 95.0 %, 3 samples", "</html>"} {
+		if !strings.Contains(page.String(), w) {
+			t.Errorf("missing %q in\n%s", w, page.String())
+		}
+	}
+	if got := strings.Count(page.String(), "<rect "); got != 2 {
+		t.Errorf("got %d filled cells, want 2", got)
+	}
+	if got := strings.Count(page.String(), "<circle "); got != 3*24-2 {
+		t.Errorf("got %d empty cells, want %d", got, 3*24-2)
+	}
+	path := writeTestCapture(t, t.TempDir(), 1704067200000, 60)
+	for _, args := range [][]string{{"-o", "html"}, {"-o", "pdf", "-heatmap"}, {"-heatmap", "-heatmap-metric", "disk"}, {"-heatmap", "-o", "html", "-top", "3"}, {"-heatmap", "-watch", "5s"}} {
+		if err := runAnalyze(append([]string{path}, args...)); err != errUsage {
+			t.Errorf("%v: got %v, want errUsage", args, err)
+		}
+	}
+}
+
+func TestPrintSummaryOverhead(t *testing.T) {
+	c := &analysis.Capture{Header: &metrics.Header{Hostname: "h", IntervalMs: 1000}}
+	for i, ms := range []float64{5, 5, 800, 700, 5} {
+		c.Samples = append(c.Samples, metrics.Sample{TimestampUnixMs: 1704067200000 + int64(i)*1000, CollectMs: &ms})
+	}
+	var out bytes.Buffer
+	printSummary(&out, c, analysis.SummarizeSamples(c.Samples), nil, resolveZone("utc", nil))
+	for _, want := range []string{
+		"Overhead   reading took 303.0ms mean, ",
+		"(30.3% of the 1s interval)",
+		"Slow readings (over half the 1s interval)",
+		"     2 readings  peak 800.0ms",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("missing %q in\n%s", want, out.String())
+		}
+	}
+
+	c.Samples = c.Samples[:2]
+	for i := range c.Samples {
+		c.Samples[i].CollectMs = nil
+	}
+	out.Reset()
+	printSummary(&out, c, analysis.SummarizeSamples(c.Samples), nil, resolveZone("utc", nil))
+	if strings.Contains(out.String(), "Overhead") {
+		t.Errorf("untimed capture: got\n%s", out.String())
+	}
+}
+
+// The processes watched with -pid are listed under the statistics, each
+// once however many readings it has.
+func TestPrintSummaryProcesses(t *testing.T) {
+	c := &analysis.Capture{Samples: []metrics.Sample{{TimestampUnixMs: 1704067200000}, {TimestampUnixMs: 1704067202000}}}
+	c.Processes = []metrics.Process{
+		{TimestampUnixMs: 1704067200000, Pid: 4242, StartedUnixMs: 1704060000000, Name: "nginx", CpuPercent: 10, RssBytes: 48 << 20, Threads: 9, Fds: 31},
+		{TimestampUnixMs: 1704067201000, Pid: 4242, StartedUnixMs: 1704060000000, Name: "nginx", CpuPercent: 30, RssBytes: 40 << 20, Threads: 9, Fds: 30},
+		{TimestampUnixMs: 1704067202000, Pid: 4242, StartedUnixMs: 1704060000000, Name: "nginx", Exited: true},
+	}
+	var out bytes.Buffer
+	printSummary(&out, c, analysis.SummarizeSamples(c.Samples), nil, resolveZone("utc", nil))
+	want := "     4242  nginx                   2    20.0%    30.0%  48.00 MiB       9    31  exited 2024-01-01 00:00:02"
+	if !strings.Contains(out.String(), "Watched processes") || strings.Count(out.String(), want) != 1 {
+		t.Errorf("missing %q in\n%s", want, out.String())
+	}
+}
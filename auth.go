@@ -0,0 +1,214 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// ── Listener security (-tls-cert, -basic-auth, -auth-token, -client-ca) ──────
+
+// listenAuth holds the credentials the listeners accept: a -basic-auth
+// user and password, an -auth-token bearer token, or either.  The zero
+// value accepts anyone.
+type listenAuth struct {
+	user, pass string
+	token      string
+}
+
+func (a listenAuth) enabled() bool { return a.user != "" || a.token != "" }
+
+// parseBasicAuth splits a -basic-auth value of the form user:pass.
+func parseBasicAuth(v string) (user, pass string, err error) {
+	user, pass, ok := strings.Cut(v, ":")
+	if !ok || user == "" || pass == "" {
+		return "", "", errors.New("-basic-auth: want user:pass")
+	}
+	return user, pass, nil
+}
+
+// authResult is the outcome of checking a request's credentials.
+type authResult int
+
+const (
+	authOK      authResult = iota
+	authMissing            // no credentials: 401, Unauthenticated
+	authDenied             // wrong credentials: 403, PermissionDenied
+)
+
+// check classifies the value of an Authorization header.
+func (a listenAuth) check(header string) authResult {
+	if !a.enabled() {
+		return authOK
+	}
+	scheme, cred, _ := strings.Cut(header, " ")
+	switch {
+	case header == "":
+		return authMissing
+	case strings.EqualFold(scheme, "Basic") && a.user != "":
+		raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(cred))
+		user, pass, ok := strings.Cut(string(raw), ":")
+		ok = ok && err == nil
+		// Both halves are always compared, so timing does not reveal
+		// which of them was wrong.
+		userOK := secureEqual(user, a.user)
+		passOK := secureEqual(pass, a.pass)
+		if ok && userOK && passOK {
+			return authOK
+		}
+	case strings.EqualFold(scheme, "Bearer") && a.token != "":
+		if secureEqual(strings.TrimSpace(cred), a.token) {
+			return authOK
+		}
+	}
+	return authDenied
+}
+
+// secureEqual compares a and b in time independent of their contents.
+// Hashing first hides the length of the secret as well.
+func secureEqual(a, b string) bool {
+	ha, hb := sha256.Sum256([]byte(a)), sha256.Sum256([]byte(b))
+	return subtle.ConstantTimeCompare(ha[:], hb[:]) == 1
+}
+
+// challenge is the WWW-Authenticate header of a 401.
+func (a listenAuth) challenge() string {
+	var schemes []string
+	if a.user != "" {
+		schemes = append(schemes, `Basic realm="infgo"`)
+	}
+	if a.token != "" {
+		schemes = append(schemes, `Bearer realm="infgo"`)
+	}
+	return strings.Join(schemes, ", ")
+}
+
+// guard wraps h so it only runs for requests with valid credentials.
+// With no credentials configured it returns h unchanged.
+func (a listenAuth) guard(h http.Handler) http.Handler {
+	if !a.enabled() {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch a.check(r.Header.Get("Authorization")) {
+		case authMissing:
+			w.Header().Set("WWW-Authenticate", a.challenge())
+			http.Error(w, "authentication required", http.StatusUnauthorized)
+		case authDenied:
+			http.Error(w, "invalid credentials", http.StatusForbidden)
+		default:
+			h.ServeHTTP(w, r)
+		}
+	})
+}
+
+// grpcCheck applies a to the authorization metadata of an RPC.
+func (a listenAuth) grpcCheck(ctx context.Context) error {
+	var header string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get("authorization"); len(v) > 0 {
+			header = v[0]
+		}
+	}
+	switch a.check(header) {
+	case authMissing:
+		return status.Error(codes.Unauthenticated, "authentication required")
+	case authDenied:
+		return status.Error(codes.PermissionDenied, "invalid credentials")
+	}
+	return nil
+}
+
+// grpcInterceptors guards every RPC with a.
+func (a listenAuth) grpcInterceptors() []grpc.ServerOption {
+	if !a.enabled() {
+		return nil
+	}
+	return []grpc.ServerOption{
+		grpc.UnaryInterceptor(func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, h grpc.UnaryHandler) (any, error) {
+			if err := a.grpcCheck(ctx); err != nil {
+				return nil, err
+			}
+			return h(ctx, req)
+		}),
+		grpc.StreamInterceptor(func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, h grpc.StreamHandler) error {
+			if err := a.grpcCheck(ss.Context()); err != nil {
+				return err
+			}
+			return h(srv, ss)
+		}),
+	}
+}
+
+// serverTLS loads the -tls-cert/-tls-key pair, and with clientCA the CA
+// bundle that client certificates must chain to.  It returns nil when TLS
+// is off.
+func serverTLS(certFile, keyFile, clientCA string) (*tls.Config, error) {
+	if certFile == "" {
+		return nil, nil
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("-tls-cert: %w", err)
+	}
+	conf := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	if clientCA != "" {
+		pem, err := os.ReadFile(clientCA)
+		if err != nil {
+			return nil, fmt.Errorf("-client-ca: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("-client-ca: no certificates in %s", clientCA)
+		}
+		conf.ClientCAs = pool
+		conf.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return conf, nil
+}
+
+// grpcServerOptions is the transport security and authentication of the
+// -grpc-listen server.
+func grpcServerOptions(cfg serveConfig) ([]grpc.ServerOption, error) {
+	conf, err := serverTLS(cfg.tlsCert, cfg.tlsKey, cfg.clientCA)
+	if err != nil {
+		return nil, err
+	}
+	opts := cfg.auth.grpcInterceptors()
+	if conf != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(conf)))
+	}
+	return opts, nil
+}
+
+// validate checks the combinations of security flags.
+func (cfg serveConfig) validate() error {
+	switch {
+	case (cfg.tlsCert == "") != (cfg.tlsKey == ""):
+		return errors.New("-tls-cert and -tls-key must be given together")
+	case cfg.clientCA != "" && cfg.tlsCert == "":
+		return errors.New("-client-ca needs -tls-cert and -tls-key")
+	case cfg.clientCA != "" && cfg.grpcAddr == "":
+		return errors.New("-client-ca applies to -grpc-listen")
+	case cfg.tlsCert != "" && !cfg.enabled():
+		return errors.New("-tls-cert needs -listen or -grpc-listen")
+	}
+	return nil
+}
@@ -0,0 +1,319 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/rpc"
+)
+
+// testCA issues certificates for 127.0.0.1 and writes them as PEM files.
+type testCA struct {
+	t    *testing.T
+	dir  string
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pool *x509.CertPool
+	path string // the CA certificate's PEM file
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	ca := &testCA{t: t, dir: t.TempDir()}
+	ca.cert, ca.key, _ = ca.sign(&x509.Certificate{
+		Subject:               pkix.Name{CommonName: "infgo test CA"},
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}, nil, nil)
+	ca.pool = x509.NewCertPool()
+	ca.pool.AddCert(ca.cert)
+	ca.path = ca.write("ca.pem", "CERTIFICATE", ca.cert.Raw)
+	return ca
+}
+
+// sign creates a certificate from tmpl, signed by parent (self-signed when
+// nil).
+func (ca *testCA) sign(tmpl, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey, []byte) {
+	ca.t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		ca.t.Fatal(err)
+	}
+	tmpl.SerialNumber = big.NewInt(time.Now().UnixNano())
+	tmpl.NotBefore, tmpl.NotAfter = time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+	if parent == nil {
+		parent, parentKey = tmpl, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		ca.t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return cert, key, der
+}
+
+func (ca *testCA) write(name, typ string, der []byte) string {
+	path := filepath.Join(ca.dir, name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0o600); err != nil {
+		ca.t.Fatal(err)
+	}
+	return path
+}
+
+// issue returns the certificate and key files of a leaf for usage.
+func (ca *testCA) issue(name string, usage x509.ExtKeyUsage) (certFile, keyFile string) {
+	_, key, der := ca.sign(&x509.Certificate{
+		Subject:     pkix.Name{CommonName: name},
+		IPAddresses: []net.IP{net.IPv4(127, 0, 0, 1)},
+		ExtKeyUsage: []x509.ExtKeyUsage{usage},
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+	}, ca.cert, ca.key)
+	kder, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		ca.t.Fatal(err)
+	}
+	return ca.write(name+".pem", "CERTIFICATE", der), ca.write(name+"-key.pem", "EC PRIVATE KEY", kder)
+}
+
+func TestListenAuthCheck(t *testing.T) {
+	a := listenAuth{user: "ops", pass: "hunter2", token: "s3cret"}
+	tests := []struct {
+		header string
+		want   authResult
+	}{
+		{"", authMissing},
+		{"Basic b3BzOmh1bnRlcjI=", authOK}, // ops:hunter2
+		{"basic b3BzOmh1bnRlcjI=", authOK},
+		{"Basic b3BzOmh1bnRlcjM=", authDenied}, // ops:hunter3
+		{"Basic b3BzOg==", authDenied},         // ops:
+		{"Basic !!!", authDenied},
+		{"Bearer s3cret", authOK},
+		{"Bearer s3cre", authDenied},
+		{"Token s3cret", authDenied},
+	}
+	for _, tt := range tests {
+		if got := a.check(tt.header); got != tt.want {
+			t.Errorf("check(%q): got %d, want %d", tt.header, got, tt.want)
+		}
+	}
+	if got := (listenAuth{token: "s3cret"}).check("Basic b3BzOmh1bnRlcjI="); got != authDenied {
+		t.Errorf("basic credentials with only a token configured: got %d, want denied", got)
+	}
+	if got := (listenAuth{}).check(""); got != authOK {
+		t.Errorf("no auth configured: got %d, want ok", got)
+	}
+}
+
+func TestServeAuth(t *testing.T) {
+	live := newLiveState()
+	live.setHeader(metrics.Header{Hostname: "node7"})
+	live.setSample(metrics.Sample{TimestampUnixMs: 1000, CpuTotal: 10}, time.Millisecond)
+	cfg := serveConfig{corsOrigin: "*", auth: listenAuth{user: "ops", pass: "hunter2", token: "s3cret"}}
+	srv := httptest.NewServer(newServeMux(live, cfg))
+	defer srv.Close()
+
+	get := func(path string, set func(*http.Request)) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+path, nil)
+		if set != nil {
+			set(req)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+	anonymous := func(*http.Request) {}
+	wrong := func(r *http.Request) { r.SetBasicAuth("ops", "guess") }
+	basic := func(r *http.Request) { r.SetBasicAuth("ops", "hunter2") }
+	bearer := func(r *http.Request) { r.Header.Set("Authorization", "Bearer s3cret") }
+
+	for _, path := range []string{"/metrics", "/api/v1/now", "/api/v1/history"} {
+		if resp := get(path, anonymous); resp.StatusCode != http.StatusUnauthorized || resp.Header.Get("WWW-Authenticate") == "" {
+			t.Errorf("%s anonymous: got %s with challenge %q, want 401 and a challenge", path, resp.Status, resp.Header.Get("WWW-Authenticate"))
+		}
+		if resp := get(path, wrong); resp.StatusCode != http.StatusForbidden {
+			t.Errorf("%s wrong password: got %s, want 403", path, resp.Status)
+		}
+		for name, set := range map[string]func(*http.Request){"basic": basic, "bearer": bearer} {
+			if resp := get(path, set); resp.StatusCode != http.StatusOK {
+				t.Errorf("%s %s: got %s, want 200", path, name, resp.Status)
+			}
+		}
+	}
+	// Load balancers probe /healthz anonymously.
+	if resp := get("/healthz", anonymous); resp.StatusCode != http.StatusOK {
+		t.Errorf("/healthz anonymous: got %s, want 200", resp.Status)
+	}
+	// A rejected browser request can still read why.
+	if resp := get("/api/v1/now", anonymous); resp.Header.Get("Access-Control-Allow-Origin") != "*" {
+		t.Error("401 lacks CORS headers")
+	}
+}
+
+func TestServeTLS(t *testing.T) {
+	ca := newTestCA(t)
+	cert, key := ca.issue("server", x509.ExtKeyUsageServerAuth)
+	conf, err := serverTLS(cert, key, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	live := newLiveState()
+	live.setSample(metrics.Sample{TimestampUnixMs: 1000}, 0)
+	stop := serveHTTP(ln, conf, serveConfig{}, live)
+	defer stop()
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: ca.pool}}}
+	resp, err := client.Get("https://" + ln.Addr().String() + "/healthz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("https /healthz: got %s", resp.Status)
+	}
+	if resp, err := http.Get("http://" + ln.Addr().String() + "/healthz"); err == nil && resp.StatusCode == http.StatusOK {
+		t.Error("plain HTTP was served on a TLS listener")
+	}
+
+	if _, err := serverTLS(cert, filepath.Join(t.TempDir(), "missing.pem"), ""); err == nil {
+		t.Error("missing -tls-key: got nil error")
+	}
+	if _, err := serverTLS(cert, key, key); err == nil {
+		t.Error("-client-ca file without certificates: got nil error")
+	}
+}
+
+// bearer attaches a token to every RPC.
+type bearer string
+
+func (b bearer) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(b)}, nil
+}
+func (bearer) RequireTransportSecurity() bool { return true }
+
+func TestGRPCMutualTLS(t *testing.T) {
+	ca := newTestCA(t)
+	cert, key := ca.issue("server", x509.ExtKeyUsageServerAuth)
+	clientCert, clientKey := ca.issue("client", x509.ExtKeyUsageClientAuth)
+	opts, err := grpcServerOptions(serveConfig{tlsCert: cert, tlsKey: key, clientCA: ca.path, auth: listenAuth{token: "s3cret"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	live := newLiveState()
+	live.setHeader(metrics.Header{Hostname: "node7"})
+	stop := serveGRPC(ln, live, "", opts...)
+	defer stop()
+
+	pair, err := tls.LoadX509KeyPair(clientCert, clientKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	getInfo := func(withCert bool, token string) error {
+		t.Helper()
+		conf := &tls.Config{RootCAs: ca.pool}
+		if withCert {
+			conf.Certificates = []tls.Certificate{pair}
+		}
+		dial := []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(conf))}
+		if token != "" {
+			dial = append(dial, grpc.WithPerRPCCredentials(bearer(token)))
+		}
+		conn, err := grpc.NewClient(ln.Addr().String(), dial...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, err = rpc.NewInfgoClient(conn).GetInfo(ctx, &rpc.InfoRequest{})
+		return err
+	}
+
+	if err := getInfo(true, "s3cret"); err != nil {
+		t.Errorf("client certificate and token: %v", err)
+	}
+	if err := getInfo(false, "s3cret"); err == nil {
+		t.Error("no client certificate: got nil error")
+	}
+	if err := getInfo(true, ""); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("no token: got %v, want Unauthenticated", err)
+	}
+	if err := getInfo(true, "guess"); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("wrong token: got %v, want PermissionDenied", err)
+	}
+}
+
+func TestGRPCAuthStream(t *testing.T) {
+	live := newLiveState()
+	opts := listenAuth{user: "ops", pass: "hunter2"}.grpcInterceptors()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stop := serveGRPC(ln, live, "", opts...)
+	defer stop()
+	conn, err := grpc.NewClient(ln.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := rpc.NewInfgoClient(conn)
+
+	for _, tt := range []struct {
+		header string
+		want   codes.Code
+	}{
+		{"", codes.Unauthenticated},
+		{"Basic b3BzOmd1ZXNz", codes.PermissionDenied}, // ops:guess
+	} {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if tt.header != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, "authorization", tt.header)
+		}
+		stream, err := client.StreamLive(ctx, &rpc.LiveRequest{})
+		if err == nil {
+			_, err = stream.Recv()
+		}
+		cancel()
+		if status.Code(err) != tt.want {
+			t.Errorf("StreamLive with %q: got %v, want %s", tt.header, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	syslogger "github.com/ALH477/infgo/logger"
+	"github.com/ALH477/infgo/ui"
+)
+
+// writeCounter counts the writes that reach the underlying writer.
+type writeCounter struct {
+	bytes.Buffer
+	writes int
+}
+
+func (w *writeCounter) Write(p []byte) (int, error) {
+	w.writes++
+	return w.Buffer.Write(p)
+}
+
+// Sampling at 20 Hz, the collector flushes its log in batches, and the
+// batches add up to every sample.
+func TestHeadlessBatchedFlush(t *testing.T) {
+	var out writeCounter
+	lgr, err := syslogger.NewWriter(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	const n = 20
+	reads := 0
+	h := &headless{logger: lgr, interval: ui.MinInterval, read: func(context.Context) ui.Reading {
+		if reads++; reads > n {
+			cancel()
+		}
+		return ui.Reading{CPUTotal: 30, CPUCores: []float64{30}, MemPercent: 40, MemTotalGB: 16}
+	}}
+	h.cores = 1
+	if err := h.run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	flushes := out.writes
+	if err := lgr.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// One write for the header, then one per batch of about five.
+	if flushes < 2 || flushes > n/2 {
+		t.Errorf("got %d writes for %d samples, want batches", flushes, n)
+	}
+	if _, samples := readLog(t, &out.Buffer); len(samples) != h.taken || h.taken < n {
+		t.Errorf("the log has %d samples, want all %d taken", len(samples), h.taken)
+	}
+	if r := h.rate(time.Now()); r == "" {
+		t.Error("no rate reported")
+	}
+}
@@ -0,0 +1,265 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ui"
+)
+
+// ── Container limits ──────────────────────────────────────────────────────────
+//
+// Inside a Docker or Kubernetes container the host's CPU and memory are
+// not what the container may use.  Where the cgroup infgo runs in has a
+// CPU quota or a memory limit, the CPU reads as the cgroup's CPU time
+// against the cores its quota is worth, and the memory as the cgroup's
+// use against its limit; the header says what the limits are.  Both v1
+// and v2 hierarchies are read, from /sys/fs/cgroup as the container sees
+// it.  On a host that is its root cgroup, which has no limits, so nothing
+// changes; -host keeps the host's readings in a container too.
+
+// cgroupRoot is where the cgroup hierarchy is mounted.
+const cgroupRoot = "/sys/fs/cgroup"
+
+// cgroupUnlimited is the least v1 memory limit read as none: the kernel
+// writes a page-rounded 2^63-1 for a cgroup without one.
+const cgroupUnlimited = 1 << 62
+
+// cgroupUsage is what a cgroup has used: CPU time since it was created,
+// and memory now, less the page cache the kernel can drop.
+type cgroupUsage struct {
+	cpuSecs  float64
+	memBytes uint64
+}
+
+// cgroup reads the cgroup infgo runs in.
+type cgroup struct {
+	fsys   fs.FS
+	v2     bool
+	limits ui.CgroupLimits
+
+	// prev is the usage at prevAt, which the next CPU reading is measured
+	// from.
+	prev   cgroupUsage
+	prevAt time.Time
+}
+
+// detectCgroup returns the cgroup at the root of fsys if it has a CPU
+// quota or a memory limit, and nil if not.
+func detectCgroup(fsys fs.FS) *cgroup {
+	c := &cgroup{fsys: fsys}
+	if _, err := fs.Stat(fsys, "cgroup.controllers"); err == nil {
+		c.v2 = true
+	}
+	var err error
+	if c.limits, err = c.readLimits(); err != nil || !c.limits.Limited() {
+		return nil
+	}
+	return c
+}
+
+// readLimits reads the CPU quota and the memory limit.  A controller that
+// is not there reads as no limit.
+func (c *cgroup) readLimits() (ui.CgroupLimits, error) {
+	var l ui.CgroupLimits
+	if c.v2 {
+		if s, err := c.readFile("cpu.max"); err == nil {
+			if l.CPUs, err = parseCPUMax(s); err != nil {
+				return l, err
+			}
+		}
+		if s, err := c.readFile("memory.max"); err == nil {
+			if l.MemBytes, err = parseMemLimit(s); err != nil {
+				return l, err
+			}
+		}
+		return l, nil
+	}
+	quota, qerr := c.readFile("cpu/cpu.cfs_quota_us")
+	period, perr := c.readFile("cpu/cpu.cfs_period_us")
+	if qerr == nil && perr == nil {
+		var err error
+		if l.CPUs, err = parseCFSQuota(quota, period); err != nil {
+			return l, err
+		}
+	}
+	if s, err := c.readFile("memory/memory.limit_in_bytes"); err == nil {
+		if l.MemBytes, err = parseMemLimit(s); err != nil {
+			return l, err
+		}
+	}
+	return l, nil
+}
+
+// usage reads what the cgroup has used.
+func (c *cgroup) usage() (cgroupUsage, error) {
+	var u cgroupUsage
+	var mem, inactive uint64
+	if c.v2 {
+		s, err := c.readFile("cpu.stat")
+		if err != nil {
+			return u, err
+		}
+		usec, err := statField(s, "usage_usec")
+		if err != nil {
+			return u, err
+		}
+		u.cpuSecs = float64(usec) / 1e6
+		if s, err = c.readFile("memory.current"); err != nil {
+			return u, err
+		}
+		if mem, err = strconv.ParseUint(s, 10, 64); err != nil {
+			return u, err
+		}
+		if s, err = c.readFile("memory.stat"); err == nil {
+			inactive, _ = statField(s, "inactive_file")
+		}
+	} else {
+		s, err := c.readFile("cpuacct/cpuacct.usage")
+		if err != nil {
+			return u, err
+		}
+		ns, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return u, err
+		}
+		u.cpuSecs = float64(ns) / 1e9
+		if s, err = c.readFile("memory/memory.usage_in_bytes"); err != nil {
+			return u, err
+		}
+		if mem, err = strconv.ParseUint(s, 10, 64); err != nil {
+			return u, err
+		}
+		if s, err = c.readFile("memory/memory.stat"); err == nil {
+			inactive, _ = statField(s, "total_inactive_file")
+		}
+	}
+	// The page cache counts against the limit but goes before the OOM
+	// killer comes; docker stats leaves it out too.
+	u.memBytes = mem - min(inactive, mem)
+	return u, nil
+}
+
+func (c *cgroup) readFile(name string) (string, error) {
+	b, err := fs.ReadFile(c.fsys, name)
+	return strings.TrimSpace(string(b)), err
+}
+
+// prime takes the usage the first CPU reading is measured from.
+func (c *cgroup) prime(at time.Time) {
+	if u, err := c.usage(); err == nil {
+		c.prev, c.prevAt = u, at
+	}
+}
+
+// cpuPercent is the cgroup's CPU time between prev and u, over the wall
+// time between, in percent of cpus cores.  It is false for the first
+// reading and where the counter went backwards, as it does when the
+// cgroup is recreated.
+func (c *cgroup) cpuPercent(u cgroupUsage, at time.Time, cpus float64) (float64, bool) {
+	prev, prevAt := c.prev, c.prevAt
+	c.prev, c.prevAt = u, at
+	dt := at.Sub(prevAt).Seconds()
+	if prevAt.IsZero() || dt <= 0 || cpus <= 0 || u.cpuSecs < prev.cpuSecs {
+		return 0, false
+	}
+	return min(100, 100*(u.cpuSecs-prev.cpuSecs)/(dt*cpus)), true
+}
+
+// parseCPUMax parses a v2 cpu.max, "$MAX $PERIOD" in microseconds, into
+// cores; "max" is no quota and reads as zero.
+func parseCPUMax(s string) (float64, error) {
+	f := strings.Fields(s)
+	if len(f) != 2 {
+		return 0, fmt.Errorf("cpu.max: %q is not a quota and a period", s)
+	}
+	if f[0] == "max" {
+		return 0, nil
+	}
+	return parseCFSQuota(f[0], f[1])
+}
+
+// parseCFSQuota parses a v1 CFS quota and its period into cores; a
+// negative quota is none and reads as zero.
+func parseCFSQuota(quota, period string) (float64, error) {
+	q, err := strconv.ParseInt(strings.TrimSpace(quota), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("cpu quota: %w", err)
+	}
+	p, err := strconv.ParseInt(strings.TrimSpace(period), 10, 64)
+	if err != nil || p <= 0 {
+		return 0, fmt.Errorf("cpu period: %q", period)
+	}
+	if q < 0 {
+		return 0, nil
+	}
+	return float64(q) / float64(p), nil
+}
+
+// parseMemLimit parses a memory limit in bytes; "max" (v2) and anything
+// from cgroupUnlimited up (v1) are none and read as zero.
+func parseMemLimit(s string) (uint64, error) {
+	s = strings.TrimSpace(s)
+	if s == "max" {
+		return 0, nil
+	}
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("memory limit: %w", err)
+	}
+	if n >= cgroupUnlimited {
+		return 0, nil
+	}
+	return n, nil
+}
+
+// statField is the value of key in a flat keyed file such as cpu.stat or
+// memory.stat, a "key value" pair a line.
+func statField(s, key string) (uint64, error) {
+	sc := bufio.NewScanner(strings.NewReader(s))
+	for sc.Scan() {
+		if k, v, ok := strings.Cut(sc.Text(), " "); ok && k == key {
+			return strconv.ParseUint(strings.TrimSpace(v), 10, 64)
+		}
+	}
+	return 0, errors.New(key + " not found")
+}
+
+// readCgroup reads the cgroup's usage into msg, in place of the host's
+// CPU and, where it has a limit, memory.  A reading of the cgroup that
+// fails leaves the host's.
+func (r *statsReader) readCgroup(msg *ui.Reading) {
+	c := r.cg
+	if c == nil {
+		return
+	}
+	u, err := c.usage()
+	if err != nil {
+		return
+	}
+	if len(msg.CPUCores) > 0 {
+		cpus := c.limits.CPUs
+		if cpus == 0 {
+			cpus = float64(len(msg.CPUCores))
+		}
+		if pct, ok := c.cpuPercent(u, r.now(), cpus); ok {
+			msg.CPUTotal = pct
+		}
+	}
+	if c.limits.MemBytes > 0 && msg.Missing&metrics.MissingMem == 0 {
+		msg.MemUsedGB = float64(u.memBytes) / ui.BytesPerGiB
+		msg.MemTotalGB = float64(c.limits.MemBytes) / ui.BytesPerGiB
+		msg.MemPercent = min(100, 100*msg.MemUsedGB/msg.MemTotalGB)
+		// The host's breakdown is not the cgroup's.
+		msg.MemAvailGB, msg.MemCachedGB, msg.MemBuffersGB = 0, 0, 0
+	}
+}
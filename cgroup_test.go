@@ -0,0 +1,101 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"os"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+func TestParseCgroupLimits(t *testing.T) {
+	for s, want := range map[string]float64{"200000 100000": 2, "50000 100000": 0.5, "max 100000": 0} {
+		if got, err := parseCPUMax(s); err != nil || got != want {
+			t.Errorf("parseCPUMax(%q) = %v, %v, want %v", s, got, err, want)
+		}
+	}
+	for _, bad := range []string{"", "max", "200000 0", "lots 100000"} {
+		if got, err := parseCPUMax(bad); err == nil {
+			t.Errorf("parseCPUMax(%q) = %v", bad, got)
+		}
+	}
+	if got, err := parseCFSQuota("-1", "100000"); err != nil || got != 0 {
+		t.Errorf("no v1 quota: got %v, %v", got, err)
+	}
+	for s, want := range map[string]uint64{"4294967296": 4 << 30, "max": 0, "9223372036854771712": 0} {
+		if got, err := parseMemLimit(s); err != nil || got != want {
+			t.Errorf("parseMemLimit(%q) = %v, %v, want %v", s, got, err, want)
+		}
+	}
+	if got, err := parseMemLimit("4G"); err == nil {
+		t.Errorf("parseMemLimit(4G) = %v", got)
+	}
+}
+
+func TestDetectCgroup(t *testing.T) {
+	tests := []struct {
+		dir    string
+		limits ui.CgroupLimits
+		usage  cgroupUsage
+	}{
+		{"v2", ui.CgroupLimits{CPUs: 2, MemBytes: 4 << 30}, cgroupUsage{cpuSecs: 8, memBytes: 1 << 30}},
+		{"v1", ui.CgroupLimits{CPUs: 1.5, MemBytes: 512 << 20}, cgroupUsage{cpuSecs: 5, memBytes: 192 << 20}},
+	}
+	for _, tt := range tests {
+		c := detectCgroup(os.DirFS("testdata/cgroup/" + tt.dir))
+		if c == nil {
+			t.Errorf("%s: no cgroup", tt.dir)
+			continue
+		}
+		if c.limits != tt.limits {
+			t.Errorf("%s: limits %+v, want %+v", tt.dir, c.limits, tt.limits)
+		}
+		if u, err := c.usage(); err != nil || u != tt.usage {
+			t.Errorf("%s: usage %+v, %v, want %+v", tt.dir, u, err, tt.usage)
+		}
+	}
+	for _, dir := range []string{"host", "v1-host", "missing"} {
+		if c := detectCgroup(os.DirFS("testdata/cgroup/" + dir)); c != nil {
+			t.Errorf("%s: got limits %+v", dir, c.limits)
+		}
+	}
+}
+
+// In a cgroup the CPU reads against its quota and the memory against its
+// limit, measured from the usage primed with the CPU.
+func TestReadCgroup(t *testing.T) {
+	fsys := fstest.MapFS{
+		"cgroup.controllers": {Data: []byte("cpu memory\n")},
+		"cpu.max":            {Data: []byte("200000 100000\n")},
+		"memory.max":         {Data: []byte("2147483648\n")},
+		"cpu.stat":           {Data: []byte("usage_usec 10000000\n")},
+		"memory.current":     {Data: []byte("536870912\n")},
+	}
+	now := time.Unix(1700000000, 0)
+	r := fakeReader(&fakeSources{}, &now)
+	r.cg = detectCgroup(fsys)
+	r.unprimed.Store(true)
+	r.read(context.Background())
+
+	now = now.Add(2 * time.Second)
+	fsys["cpu.stat"] = &fstest.MapFile{Data: []byte("usage_usec 13000000\n")}
+	msg := r.read(context.Background())
+	if msg.CPUTotal != 75 || len(msg.CPUCores) != 2 {
+		t.Errorf("CPU: got %v of %v", msg.CPUTotal, msg.CPUCores)
+	}
+	if msg.MemPercent != 25 || msg.MemUsedGB != 0.5 || msg.MemTotalGB != 2 {
+		t.Errorf("memory: got %v%% of %v GiB", msg.MemPercent, msg.MemTotalGB)
+	}
+
+	// Where the cgroup cannot be read the host's readings stand.
+	delete(fsys, "cpu.stat")
+	now = now.Add(ui.DefaultInterval)
+	if msg := r.read(context.Background()); msg.CPUTotal != 30 || msg.MemPercent != 50 {
+		t.Errorf("unreadable: got CPU %v, memory %v", msg.CPUTotal, msg.MemPercent)
+	}
+}
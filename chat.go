@@ -0,0 +1,226 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ALH477/infgo/analysis"
+)
+
+// ── Chat alert payloads (-alert-slack, -alert-discord) ────────────────────────
+
+// alertSparkWidth is the most characters an alert sparkline takes; a
+// minute of 500 ms samples is folded into it.
+const alertSparkWidth = 30
+
+// Attachment and embed colours per state, from the TUI palette.
+var alertColors = map[string]string{
+	alertStarted:  "#ef4444",
+	alertFlapping: "#f59e0b",
+	alertCleared:  "#10b981",
+}
+
+// alertView is the wording shared by the chat payloads.
+type alertView struct {
+	title     string // "cpu>90 started on web01"
+	value     string // "97.2%"
+	threshold string // "> 90%"
+	note      string // how long it fired, or the flapping explanation
+	spark     string
+	color     string
+	at        time.Time
+}
+
+func newAlertView(ev alertEvent, cooldown time.Duration) alertView {
+	m, _ := analysis.LookupMetric(ev.Metric)
+	unit := m.Unit
+	v := alertView{
+		value:     fmt.Sprintf("%.4g%s", ev.Value, unit),
+		threshold: fmt.Sprintf("%s %.4g%s", ev.Op, ev.Threshold, unit),
+		spark:     alertSparkline(ev.Recent, alertSparkWidth, unit == "%", ev.Threshold),
+		color:     alertColors[ev.State],
+		at:        ev.StartedAt,
+	}
+	verb := ev.State
+	switch ev.State {
+	case alertCleared:
+		v.at = *ev.ClearedAt
+		v.note = "Fired for " + time.Duration(ev.DurationS*float64(time.Second)).Round(time.Second).String() + "."
+	case alertFlapping:
+		verb = "is flapping"
+		v.note = fmt.Sprintf("It fired again within %s of its last message; further changes are held back until it has been steady for %s.", cooldown, cooldown)
+	}
+	if ev.Suppressed > 0 {
+		v.note = strings.TrimSpace(v.note + fmt.Sprintf(" Settled after %d changes while flapping.", ev.Suppressed))
+	}
+	v.title = ev.Rule + " " + verb
+	if ev.Hostname != "" {
+		v.title += " on " + ev.Hostname
+	}
+	return v
+}
+
+// alertSparkline draws recent as at most width block characters, keeping
+// each bucket's peak so a short spike stays visible.  Percentages use a
+// 0–100 scale; other metrics run from 0 to the larger of their peak and
+// the threshold.
+func alertSparkline(recent []float64, width int, percent bool, threshold float64) string {
+	n := len(recent)
+	if n == 0 {
+		return ""
+	}
+	top := 100.0
+	if !percent {
+		top = threshold
+		for _, v := range recent {
+			top = max(top, v)
+		}
+		if top <= 0 {
+			top = 1
+		}
+	}
+	w := min(n, width)
+	var sb strings.Builder
+	for i := 0; i < w; i++ {
+		peak := recent[i*n/w]
+		for _, v := range recent[i*n/w : (i+1)*n/w] {
+			peak = max(peak, v)
+		}
+		idx := int(peak/top*float64(len(sparkChars)-1) + 0.5)
+		idx = min(max(idx, 0), len(sparkChars)-1)
+		sb.WriteRune(sparkChars[idx])
+	}
+	return sb.String()
+}
+
+// marshalChat encodes a payload without HTML escaping, so rules like
+// cpu>90 stay readable in the request body.
+func marshalChat(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// ── Slack ─────────────────────────────────────────────────────────────────────
+
+type slackPayload struct {
+	Text        string            `json:"text"` // notification fallback
+	Attachments []slackAttachment `json:"attachments"`
+}
+
+type slackAttachment struct {
+	Color  string       `json:"color"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+type slackBlock struct {
+	Type     string      `json:"type"`
+	Text     *slackText  `json:"text,omitempty"`
+	Fields   []slackText `json:"fields,omitempty"`
+	Elements []slackText `json:"elements,omitempty"`
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// slackEscape escapes the three characters Slack's mrkdwn reserves.
+var slackEscape = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+func mrkdwn(s string) slackText { return slackText{Type: "mrkdwn", Text: s} }
+
+// slackPayloadFor builds the incoming-webhook message for ev: a headline,
+// then a coloured attachment with the value, threshold and last minute.
+func slackPayloadFor(ev alertEvent, cooldown time.Duration) slackPayload {
+	v := newAlertView(ev, cooldown)
+	blocks := []slackBlock{
+		{Type: "section", Text: &slackText{Type: "mrkdwn", Text: "*" + slackEscape.Replace(v.title) + "*"}},
+		{Type: "section", Fields: []slackText{
+			mrkdwn("*Value*\n" + slackEscape.Replace(v.value)),
+			mrkdwn("*Threshold*\n" + slackEscape.Replace(v.threshold)),
+		}},
+	}
+	if v.note != "" {
+		blocks = append(blocks, slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: v.note}})
+	}
+	if v.spark != "" {
+		blocks = append(blocks, slackBlock{Type: "context", Elements: []slackText{mrkdwn("`" + v.spark + "` last minute")}})
+	}
+	return slackPayload{
+		Text:        slackEscape.Replace(v.title),
+		Attachments: []slackAttachment{{Color: v.color, Blocks: blocks}},
+	}
+}
+
+// slackEncoder is the alertTarget encoder for -alert-slack.
+func slackEncoder(cooldown time.Duration) func(alertEvent) ([]byte, error) {
+	return func(ev alertEvent) ([]byte, error) { return marshalChat(slackPayloadFor(ev, cooldown)) }
+}
+
+// ── Discord ───────────────────────────────────────────────────────────────────
+
+type discordPayload struct {
+	Username string         `json:"username"`
+	Embeds   []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title       string         `json:"title"`
+	Description string         `json:"description,omitempty"`
+	Color       int            `json:"color"`
+	Fields      []discordField `json:"fields"`
+	Timestamp   string         `json:"timestamp"`
+}
+
+type discordField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+// discordEscape keeps hostnames like web_01 from turning into markdown.
+var discordEscape = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "~", `\~`, "`", "\\`", "|", `\|`)
+
+// discordPayloadFor builds the webhook message for ev: one embed coloured
+// like the Slack attachment, with the same fields.
+func discordPayloadFor(ev alertEvent, cooldown time.Duration) discordPayload {
+	v := newAlertView(ev, cooldown)
+	var desc []string
+	if v.note != "" {
+		desc = append(desc, v.note)
+	}
+	if v.spark != "" {
+		desc = append(desc, "`"+v.spark+"` last minute")
+	}
+	var color int
+	fmt.Sscanf(v.color, "#%x", &color)
+	return discordPayload{
+		Username: "infgo",
+		Embeds: []discordEmbed{{
+			Title:       discordEscape.Replace(v.title),
+			Description: strings.Join(desc, "\n"),
+			Color:       color,
+			Fields: []discordField{
+				{Name: "Value", Value: v.value, Inline: true},
+				{Name: "Threshold", Value: v.threshold, Inline: true},
+			},
+			Timestamp: v.at.UTC().Format(time.RFC3339),
+		}},
+	}
+}
+
+// discordEncoder is the alertTarget encoder for -alert-discord.
+func discordEncoder(cooldown time.Duration) func(alertEvent) ([]byte, error) {
+	return func(ev alertEvent) ([]byte, error) { return marshalChat(discordPayloadFor(ev, cooldown)) }
+}
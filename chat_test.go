@@ -0,0 +1,99 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// checkGolden compares indented JSON against testdata/name.
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, got, "", "  "); err != nil {
+		t.Fatalf("%s: invalid JSON: %v\n%s", name, err, got)
+	}
+	buf.WriteByte('\n')
+	path := filepath.Join("testdata", name)
+	if *updateGolden {
+		if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("%s: payload differs from the golden file (rerun with -update to accept)\ngot:\n%s", name, buf.Bytes())
+	}
+}
+
+func chatAlerts() map[string]alertEvent {
+	started := testAlert()
+	started.Recent = []float64{40, 45, 60, 88, 95, 97, 96, 97}
+
+	cleared := started
+	at := started.StartedAt.Add(5 * time.Minute)
+	cleared.State, cleared.Value, cleared.ClearedAt, cleared.DurationS = alertCleared, 42, &at, 300
+	cleared.Recent = []float64{97, 91, 70, 50, 42}
+
+	flapping := started
+	flapping.State = alertFlapping
+
+	load := alertEvent{
+		Hostname: "db_01", Rule: "load1>=8", Metric: "load1", Op: ">=", Threshold: 8, Value: 12.5,
+		State: alertCleared, StartedAt: started.StartedAt, ClearedAt: &at, DurationS: 300,
+		Recent: []float64{2, 4, 12.5, 16, 9, 6}, Suppressed: 4,
+	}
+	return map[string]alertEvent{"started": started, "cleared": cleared, "flapping": flapping, "settled": load}
+}
+
+func TestChatPayloads(t *testing.T) {
+	for name, ev := range chatAlerts() {
+		body, err := slackEncoder(5 * time.Minute)(ev)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkGolden(t, "alert_slack_"+name+".json", body)
+
+		body, err = discordEncoder(5 * time.Minute)(ev)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkGolden(t, "alert_discord_"+name+".json", body)
+	}
+}
+
+func TestAlertSparkline(t *testing.T) {
+	tests := []struct {
+		recent    []float64
+		width     int
+		percent   bool
+		threshold float64
+		want      string
+	}{
+		{nil, 30, true, 90, ""},
+		{[]float64{0, 50, 100}, 30, true, 90, "▁▅█"},
+		// Buckets keep their peak, so the spike survives folding.
+		{[]float64{0, 0, 0, 100, 0, 0}, 3, true, 90, "▁█▁"},
+		// Load scales to the larger of its peak and the threshold.
+		{[]float64{0, 2, 4}, 30, false, 8, "▁▃▅"},
+		{[]float64{0, 8, 16}, 30, false, 8, "▁▅█"},
+	}
+	for _, tt := range tests {
+		if got := alertSparkline(tt.recent, tt.width, tt.percent, tt.threshold); got != tt.want {
+			t.Errorf("alertSparkline(%v, %d): got %q, want %q", tt.recent, tt.width, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ui"
+)
+
+// ── check (Nagios / Icinga plugin) ────────────────────────────────────────────
+
+// Plugin exit statuses, which double as indexes into checkStates.
+const (
+	checkOK = iota
+	checkWarning
+	checkCritical
+	checkUnknown
+)
+
+var checkStates = [...]string{"OK", "WARNING", "CRITICAL", "UNKNOWN"}
+
+// worseCheck is the more severe of two statuses.  As in the monitoring
+// plugins' max_state, UNKNOWN ranks above OK but below WARNING, so a
+// metric that could not be read never hides one past its threshold.
+func worseCheck(a, b int) int {
+	rank := [...]int{checkOK: 0, checkUnknown: 1, checkWarning: 2, checkCritical: 3}
+	if rank[b] > rank[a] {
+		return b
+	}
+	return a
+}
+
+// checkMetric is one measured value with its thresholds; a zero threshold
+// is not checked.
+type checkMetric struct {
+	name       string
+	unit       string // "%" or ""
+	value      float64
+	warn, crit float64
+	missing    bool // no reading had it
+}
+
+// state is the plugin status of m on its own.
+func (m checkMetric) state() int {
+	switch {
+	case m.crit > 0 && m.value > m.crit:
+		return checkCritical
+	case m.warn > 0 && m.value > m.warn:
+		return checkWarning
+	}
+	return checkOK
+}
+
+// reading is m's value as printed: one decimal for a percentage, two for
+// a load average.
+func (m checkMetric) reading() string {
+	if m.unit == "%" {
+		return strconv.FormatFloat(m.value, 'f', 1, 64) + "%"
+	}
+	return strconv.FormatFloat(m.value, 'f', 2, 64)
+}
+
+// perfdata renders m as `name=value[unit];warn;crit`, leaving out
+// thresholds that are not set.
+func (m checkMetric) perfdata() string {
+	s := m.name + "=" + m.reading()
+	th := func(v float64) string {
+		if v == 0 {
+			return ""
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	return strings.TrimRight(s+";"+th(m.warn)+";"+th(m.crit), ";")
+}
+
+// checkThresholds holds the -warn-* and -crit-* flags.
+type checkThresholds struct {
+	warnCPU, critCPU     float64
+	warnMem, critMem     float64
+	warnLoad1, critLoad1 float64
+}
+
+// evaluateCheck compares the mean of readings with th and returns the plugin
+// status and its one-line output: a summary naming any metric past its
+// threshold, then perfdata after the "|".  Each mean is over the readings
+// that have the metric; one that none of them has is UNKNOWN.
+func evaluateCheck(readings []ui.Reading, th checkThresholds) (int, string) {
+	if len(readings) == 0 {
+		return checkUnknown, "INFGO UNKNOWN - no readings; the system statistics could not be read"
+	}
+	ms := []checkMetric{
+		{name: "cpu", unit: "%", warn: th.warnCPU, crit: th.critCPU},
+		{name: "mem", unit: "%", warn: th.warnMem, crit: th.critMem},
+		{name: "load1", warn: th.warnLoad1, crit: th.critLoad1},
+	}
+	groups := [...]metrics.Missing{metrics.MissingCPU, metrics.MissingMem, metrics.MissingLoad}
+	var n [len(groups)]int
+	for _, r := range readings {
+		for i, v := range [...]float64{r.CPUTotal, r.MemPercent, r.Load1} {
+			if !r.Missing.Has(groups[i]) {
+				ms[i].value += v
+				n[i]++
+			}
+		}
+	}
+	for i := range ms {
+		if n[i] == 0 {
+			ms[i].missing = true
+		} else {
+			ms[i].value /= float64(n[i])
+		}
+	}
+
+	status := checkOK
+	var summary, perf []string
+	for _, m := range ms {
+		if m.missing {
+			status = worseCheck(status, checkUnknown)
+			summary = append(summary, m.name+" could not be read")
+			continue
+		}
+		st := m.state()
+		status = worseCheck(status, st)
+		text := m.name + " " + m.reading()
+		switch st {
+		case checkCritical:
+			text += fmt.Sprintf(" > %g", m.crit)
+		case checkWarning:
+			text += fmt.Sprintf(" > %g", m.warn)
+		}
+		summary = append(summary, text)
+		perf = append(perf, m.perfdata())
+	}
+	return status, fmt.Sprintf("INFGO %s - %s | %s", checkStates[status], strings.Join(summary, ", "), strings.Join(perf, " "))
+}
+
+// sampleWindow takes readings every ui.DefaultInterval (or once, at the
+// end, for a shorter window) until window has passed.  read is primed
+// first, since CPU usage is a delta since the previous reading.
+func sampleWindow(window time.Duration, read func() ui.Reading) []ui.Reading {
+	read()
+	deadline := time.Now().Add(window)
+	tick := time.NewTicker(min(ui.DefaultInterval, window))
+	defer tick.Stop()
+	var out []ui.Reading
+	for {
+		<-tick.C
+		if msg := read(); !msg.Missing.Has(metrics.MissingAll) {
+			out = append(out, msg)
+		}
+		if !time.Now().Before(deadline) {
+			return out
+		}
+	}
+}
+
+// runCheck implements `infgo check`.  Its exit status is the result, so
+// every outcome, including bad flags, is reported as a plugin status.
+func runCheck(args []string) error {
+	read := func() ui.Reading { return readStats(context.Background()) }
+	if code := check(args, os.Stdout, read); code != checkOK {
+		return exitStatus(code)
+	}
+	return nil
+}
+
+// check is runCheck with the output and the source of readings injected.
+func check(args []string, w io.Writer, read func() ui.Reading) int {
+	fs := newFlagSet("check", "[-warn-cpu N] [-crit-cpu N] [-warn-mem N] [-crit-mem N] [-window d]")
+	var th checkThresholds
+	fs.Float64Var(&th.warnCPU, "warn-cpu", 0, "WARNING when mean CPU use exceeds `N`% (0: not checked)")
+	fs.Float64Var(&th.critCPU, "crit-cpu", 0, "CRITICAL when mean CPU use exceeds `N`%")
+	fs.Float64Var(&th.warnMem, "warn-mem", 0, "WARNING when mean memory use exceeds `N`%")
+	fs.Float64Var(&th.critMem, "crit-mem", 0, "CRITICAL when mean memory use exceeds `N`%")
+	fs.Float64Var(&th.warnLoad1, "warn-load1", 0, "WARNING when the mean 1-minute load average exceeds `N`")
+	fs.Float64Var(&th.critLoad1, "crit-load1", 0, "CRITICAL when the mean 1-minute load average exceeds `N`")
+	window := fs.Duration("window", 5*time.Second, "sample for `d` and check the mean")
+
+	unknown := func(format string, a ...any) int {
+		fmt.Fprintf(w, "INFGO UNKNOWN - %s\n", fmt.Sprintf(format, a...))
+		return checkUnknown
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return checkUnknown
+		}
+		return unknown("invalid arguments; see infgo check -h")
+	}
+	if fs.NArg() > 0 {
+		return unknown("unexpected argument %q", fs.Arg(0))
+	}
+	if *window <= 0 {
+		return unknown("-window must be positive")
+	}
+	for _, p := range [][2]float64{{th.warnCPU, th.critCPU}, {th.warnMem, th.critMem}, {th.warnLoad1, th.critLoad1}} {
+		if p[0] < 0 || p[1] < 0 || (p[0] > 0 && p[1] > 0 && p[0] > p[1]) {
+			return unknown("thresholds must not be negative, and a warning threshold must not exceed its critical one")
+		}
+	}
+
+	code, line := evaluateCheck(sampleWindow(*window, read), th)
+	fmt.Fprintln(w, line)
+	return code
+}
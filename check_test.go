@@ -0,0 +1,102 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ui"
+)
+
+func readings(load1 float64, cpuMem ...[2]float64) []ui.Reading {
+	var out []ui.Reading
+	for _, r := range cpuMem {
+		out = append(out, ui.Reading{CPUTotal: r[0], CPUCores: []float64{r[0]}, MemPercent: r[1], Load1: load1})
+	}
+	return out
+}
+
+func TestEvaluateCheck(t *testing.T) {
+	th := checkThresholds{warnCPU: 80, critCPU: 95, warnMem: 85, critMem: 95}
+	tests := []struct {
+		name     string
+		readings []ui.Reading
+		th       checkThresholds
+		wantCode int
+		wantLine string
+	}{
+		{"ok", readings(2.41, [2]float64{40, 61.8}, [2]float64{45, 61.8}), th,
+			checkOK, "INFGO OK - cpu 42.5%, mem 61.8%, load1 2.41 | cpu=42.5%;80;95 mem=61.8%;85;95 load1=2.41"},
+		{"warning", readings(1, [2]float64{85, 50}), th,
+			checkWarning, "INFGO WARNING - cpu 85.0% > 80, mem 50.0%, load1 1.00 | cpu=85.0%;80;95 mem=50.0%;85;95 load1=1.00"},
+		{"critical beats warning", readings(1, [2]float64{85, 99}), th,
+			checkCritical, "INFGO CRITICAL - cpu 85.0% > 80, mem 99.0% > 95, load1 1.00 | cpu=85.0%;80;95 mem=99.0%;85;95 load1=1.00"},
+		// A single spike above the critical threshold is averaged out.
+		{"mean, not peak", readings(1, [2]float64{100, 50}, [2]float64{0, 50}), th,
+			checkOK, "INFGO OK - cpu 50.0%, mem 50.0%, load1 1.00 | cpu=50.0%;80;95 mem=50.0%;85;95 load1=1.00"},
+		{"load only", readings(6.5, [2]float64{10, 20}), checkThresholds{critLoad1: 4},
+			checkCritical, "INFGO CRITICAL - cpu 10.0%, mem 20.0%, load1 6.50 > 4 | cpu=10.0% mem=20.0% load1=6.50;;4"},
+		// A failed memory read is left out of the mean, not averaged in as 0.
+		{"mem missing once", append(readings(1, [2]float64{40, 60}),
+			ui.Reading{CPUTotal: 60, CPUCores: []float64{60}, Load1: 1, Missing: metrics.MissingMem}), th,
+			checkOK, "INFGO OK - cpu 50.0%, mem 60.0%, load1 1.00 | cpu=50.0%;80;95 mem=60.0%;85;95 load1=1.00"},
+		{"mem never read", []ui.Reading{{CPUTotal: 40, CPUCores: []float64{40}, Load1: 1, Missing: metrics.MissingMem}}, th,
+			checkUnknown, "INFGO UNKNOWN - cpu 40.0%, mem could not be read, load1 1.00 | cpu=40.0%;80;95 load1=1.00"},
+		{"critical beats unknown", []ui.Reading{{CPUTotal: 99, CPUCores: []float64{99}, Load1: 1, Missing: metrics.MissingMem}}, th,
+			checkCritical, "INFGO CRITICAL - cpu 99.0% > 95, mem could not be read, load1 1.00 | cpu=99.0%;80;95 load1=1.00"},
+		{"no readings", nil, th,
+			checkUnknown, "INFGO UNKNOWN - no readings; the system statistics could not be read"},
+	}
+	for _, tt := range tests {
+		code, line := evaluateCheck(tt.readings, tt.th)
+		if code != tt.wantCode || line != tt.wantLine {
+			t.Errorf("%s: got %d %q, want %d %q", tt.name, code, line, tt.wantCode, tt.wantLine)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	read := func() ui.Reading { return readings(3, [2]float64{97, 50})[0] }
+	tests := []struct {
+		args     []string
+		wantCode int
+		wantLine string
+	}{
+		{[]string{"-window", "10ms", "--warn-cpu", "80", "--crit-cpu", "95"},
+			checkCritical, "INFGO CRITICAL - cpu 97.0% > 95, mem 50.0%, load1 3.00 | cpu=97.0%;80;95 mem=50.0% load1=3.00"},
+		{[]string{"-window", "10ms", "-warn-load1", "2"},
+			checkWarning, "INFGO WARNING - cpu 97.0%, mem 50.0%, load1 3.00 > 2 | cpu=97.0% mem=50.0% load1=3.00;2"},
+		{[]string{"-warn-cpu", "95", "-crit-cpu", "80"},
+			checkUnknown, "INFGO UNKNOWN - thresholds must not be negative, and a warning threshold must not exceed its critical one"},
+		{[]string{"-window", "0s"}, checkUnknown, "INFGO UNKNOWN - -window must be positive"},
+		{[]string{"-warn-disk", "80"}, checkUnknown, "INFGO UNKNOWN - invalid arguments; see infgo check -h"},
+	}
+	for _, tt := range tests {
+		var out strings.Builder
+		code := check(tt.args, &out, read)
+		if got := strings.TrimSuffix(out.String(), "\n"); code != tt.wantCode || got != tt.wantLine {
+			t.Errorf("%v: got %d %q, want %d %q", tt.args, code, got, tt.wantCode, tt.wantLine)
+		}
+	}
+}
+
+func TestSampleWindow(t *testing.T) {
+	calls := 0
+	read := func() ui.Reading {
+		calls++
+		return ui.Reading{CPUCores: []float64{float64(calls)}}
+	}
+	start := time.Now()
+	got := sampleWindow(3*ui.DefaultInterval, read)
+	if took := time.Since(start); took < 3*ui.DefaultInterval || took > 3*ui.DefaultInterval+time.Second {
+		t.Errorf("took %s for a %s window", took, 3*ui.DefaultInterval)
+	}
+	// The priming read is discarded: its CPU figure covers an unknown span.
+	if len(got) != 3 || got[0].CPUCores[0] != 2 {
+		t.Errorf("got %d readings starting at call %v, want 3 starting at call 2", len(got), got[0].CPUCores)
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	syslogger "github.com/ALH477/infgo/logger"
+)
+
+// ── Subcommands ───────────────────────────────────────────────────────────────
+//
+// `infgo` with no positional arguments starts the live TUI.  When the first
+// argument names one of the tools below, control is handed to that tool
+// instead and the TUI is never started.
+
+// subcommand is an offline tool invoked as `infgo <name> [args]`.
+type subcommand struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+// subcommands lists every offline tool in the order shown by `infgo -h`.
+var subcommands = []subcommand{
+	{"analyze", "summarise a capture and check thresholds", runAnalyze},
+	{"report", "render a capture as Markdown for issues and incident docs", runReport},
+	{"trim", "cut a capture down to a time range", runTrim},
+	{"merge", "combine several captures into one, ordered by time", runMerge},
+	{"resample", "derive a lower-resolution capture", runResample},
+	{"import", "convert CSV and other formats into a capture", runImport},
+	{"export", "convert a capture for other tools", runExport},
+	{"ctl", "send a command to a running -headless -control socket", runCtl},
+	{"check", "sample briefly and report as a Nagios/Icinga plugin", runCheck},
+	{"selftest", "put a known load on this machine and check the readings match", runSelftest},
+	{"doctor", "check that this machine and terminal are ready for infgo", runDoctor},
+	{"collect", "receive -ship streams from many agents into per-host captures", runCollect},
+	{"schema", "print the protobuf schema of captures, or the one a capture embeds", runSchema},
+}
+
+// runFormat dispatches `infgo <verb> <format> [args]` to the entry of
+// formats named by args[0], printing the available formats otherwise.
+func runFormat(verb string, formats []subcommand, args []string) error {
+	if len(args) > 0 {
+		for i := range formats {
+			if formats[i].name == args[0] {
+				return formats[i].run(args[1:])
+			}
+		}
+	}
+	w := os.Stderr
+	if len(args) > 0 && args[0] != "-h" && args[0] != "-help" && args[0] != "--help" {
+		fmt.Fprintf(w, "infgo %s: unknown format %q\n", verb, args[0])
+	}
+	fmt.Fprintf(w, "Usage: infgo %s <format> [args]\n\nFormats:\n", verb)
+	for _, f := range formats {
+		fmt.Fprintf(w, "  %-10s %s\n", f.name, f.summary)
+	}
+	return errUsage
+}
+
+// lookupSubcommand returns the subcommand called name, or nil.
+func lookupSubcommand(name string) *subcommand {
+	for i := range subcommands {
+		if subcommands[i].name == name {
+			return &subcommands[i]
+		}
+	}
+	return nil
+}
+
+// runSubcommand executes sc and exits the process with its status.
+// errUsage (flag misuse) exits 2, an exitStatus exits with that code and
+// any other error exits 1.
+func runSubcommand(sc *subcommand, args []string) {
+	err := sc.run(args)
+	var code exitStatus
+	switch {
+	case err == nil:
+		os.Exit(0)
+	case errors.Is(err, flag.ErrHelp):
+		os.Exit(0)
+	case errors.As(err, &code):
+		os.Exit(int(code))
+	case errors.Is(err, errUsage):
+		os.Exit(2)
+	default:
+		fmt.Fprintf(os.Stderr, "infgo %s: %v\n", sc.name, err)
+		os.Exit(1)
+	}
+}
+
+// errUsage is returned by subcommands after they have already printed a
+// usage message; runSubcommand exits without printing it again.
+var errUsage = errors.New("usage error")
+
+// exitStatus is returned by subcommands whose exit code is itself the
+// result, such as `infgo check`.  They have already printed their output.
+type exitStatus int
+
+func (e exitStatus) Error() string { return fmt.Sprintf("exit status %d", int(e)) }
+
+// printSubcommands writes the subcommand list for the top-level usage text.
+func printSubcommands() {
+	fmt.Fprintf(os.Stderr, "\nCommands:\n")
+	for _, sc := range subcommands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", sc.name, sc.summary)
+	}
+}
+
+// newFlagSet returns a FlagSet for a subcommand whose usage line is
+// "Usage: infgo <name> <synopsis>".  Parse errors are reported by the
+// FlagSet itself, so callers should return errUsage for them.
+func newFlagSet(name, synopsis string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: infgo %s %s\n\nFlags:\n", name, synopsis)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseInterspersed parses args with fs, allowing flags to appear after
+// positional arguments (`infgo trim in.infgo -o out.infgo`), which the
+// standard flag package would otherwise treat as further positionals.
+// It returns the positional arguments in their original order.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, errUsage
+		}
+		// "--" terminates flag parsing; the flag package consumes it and
+		// stops, so everything it left behind is positional.
+		if consumed := len(args) - fs.NArg(); consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, fs.Args()...), nil
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// usageErrorf prints a formatted message followed by fs's usage text and
+// returns errUsage.
+func usageErrorf(fs *flag.FlagSet, format string, a ...any) error {
+	fmt.Fprintf(fs.Output(), "infgo %s: %s\n", fs.Name(), strings.TrimSpace(fmt.Sprintf(format, a...)))
+	fs.Usage()
+	return errUsage
+}
+
+// stdinPath is the input path that makes a subcommand read from stdin.
+const stdinPath = "-"
+
+// openCapture opens path for reading, treating "-" as standard input so
+// captures can be streamed in (`aws s3 cp s3://b/c.infgo - | infgo analyze -`).
+func openCapture(path string) (*syslogger.Reader, error) {
+	if path == stdinPath {
+		return syslogger.NewReader(os.Stdin)
+	}
+	return syslogger.Open(path)
+}
+
+// errNeedsFile is returned by tools that must read their input more than
+// once and so cannot consume a stream.
+func errNeedsFile(tool, why string) error {
+	return fmt.Errorf("%s cannot read from stdin: %s; save the stream to a file first", tool, why)
+}
+
+// inputName returns path for messages, naming stdin explicitly.
+func inputName(path string) string {
+	if path == stdinPath {
+		return "stdin"
+	}
+	return fmt.Sprintf("%q", path)
+}
@@ -0,0 +1,448 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	syslogger "github.com/ALH477/infgo/logger"
+	"github.com/ALH477/infgo/metrics"
+)
+
+// ── Aggregator (`infgo collect`) ──────────────────────────────────────────────
+
+const (
+	// collectIdle drops a connection that sends nothing for this long;
+	// agents send a sample every ui.DefaultInterval.
+	collectIdle = 30 * time.Second
+
+	// collectRetry is how long a host whose segment could not be written
+	// (a full disk, say) has its samples dropped before writing is tried
+	// again.
+	collectRetry = 10 * time.Second
+)
+
+// collector receives `-ship` streams and writes each host's samples to
+// <dir>/<hostname>.infgo.  Every connection moves the host's previous file
+// aside, as `infgo ctl rotate` does, so a capture never mixes sessions.
+type collector struct {
+	dir         string
+	rotateEvery time.Duration
+	idle, retry time.Duration
+	errs        io.Writer
+	create      func(path string) (*syslogger.Logger, error)
+	now         func() time.Time
+
+	mu     sync.Mutex
+	hosts  map[string]*collectSession // by file name
+	conns  map[net.Conn]struct{}
+	ln     net.Listener
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// collectSession is one connected agent.  The counters are guarded by
+// collector.mu; the segment is owned by the connection's goroutine.
+type collectSession struct {
+	host, name, remote string
+	since              time.Time
+	path               string
+	bytes              atomic.Int64
+
+	samples, events, dropped int64
+	lastRecv                 time.Time
+	degraded                 string // why samples are being dropped, if they are
+
+	hdr     metrics.Header
+	lgr     *syslogger.Logger // nil while degraded
+	opened  time.Time
+	retryAt time.Time
+}
+
+func newCollector(dir string, rotateEvery time.Duration, errs io.Writer) *collector {
+	return &collector{
+		dir: dir, rotateEvery: rotateEvery,
+		idle: collectIdle, retry: collectRetry,
+		errs:   errs,
+		create: syslogger.New,
+		now:    time.Now,
+		hosts:  make(map[string]*collectSession),
+		conns:  make(map[net.Conn]struct{}),
+	}
+}
+
+func (c *collector) logf(format string, a ...any) {
+	fmt.Fprintf(c.errs, "infgo collect: "+format+"\n", a...)
+}
+
+// serve accepts agents on ln until close is called.
+func (c *collector) serve(ln net.Listener) {
+	c.mu.Lock()
+	c.ln = ln
+	c.mu.Unlock()
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		c.mu.Lock()
+		if c.closed {
+			c.mu.Unlock()
+			conn.Close()
+			return
+		}
+		c.conns[conn] = struct{}{}
+		c.wg.Add(1)
+		c.mu.Unlock()
+		go func() {
+			defer c.wg.Done()
+			c.handle(conn)
+			c.mu.Lock()
+			delete(c.conns, conn)
+			c.mu.Unlock()
+		}()
+	}
+}
+
+// close stops accepting, disconnects every agent and waits for their
+// segments to be closed.
+func (c *collector) close() {
+	c.mu.Lock()
+	c.closed = true
+	if c.ln != nil {
+		c.ln.Close()
+	}
+	for conn := range c.conns {
+		conn.Close()
+	}
+	c.mu.Unlock()
+	c.wg.Wait()
+}
+
+// handle reads one agent's stream.  A stream that is not a capture, or
+// does not open with a header naming the host, is refused; one that turns
+// malformed part-way is cut off, keeping what was already written.
+func (c *collector) handle(conn net.Conn) {
+	defer conn.Close()
+	remote := conn.RemoteAddr().String()
+	body := &countingReader{r: conn}
+	conn.SetReadDeadline(c.now().Add(c.idle))
+	rd, err := syslogger.NewReader(body)
+	if err != nil {
+		c.logf("%s: refused: %v", remote, err)
+		return
+	}
+	defer rd.Close()
+	rec, err := rd.Next()
+	if err != nil || rec.Header == nil {
+		c.logf("%s: refused: the stream does not start with a header", remote)
+		return
+	}
+	name := collectFileName(rec.Header.Hostname)
+	if name == "" {
+		c.logf("%s: refused: hostname %q cannot name a file", remote, rec.Header.Hostname)
+		return
+	}
+	s := &collectSession{
+		host: rec.Header.Hostname, name: name, remote: remote,
+		since: c.now(), path: filepath.Join(c.dir, name+".infgo"),
+		hdr: *rec.Header,
+	}
+	if prev := c.register(s); prev != nil {
+		c.logf("%s: refused: %s is already connected from %s", remote, s.host, prev.remote)
+		return
+	}
+	defer c.unregister(s)
+	s.bytes.Store(body.n)
+	c.logf("%s connected from %s", s.host, remote)
+	c.open(s)
+
+	for {
+		conn.SetReadDeadline(c.now().Add(c.idle))
+		rec, err := rd.Next()
+		s.bytes.Store(body.n)
+		if err == io.EOF {
+			break
+		}
+		if err == nil && rec.Header != nil {
+			err = errors.New("a second header in one stream")
+		}
+		if err != nil {
+			c.logf("%s: disconnected: %v", s.host, err)
+			break
+		}
+		c.write(s, rec)
+	}
+	if s.lgr != nil {
+		if err := s.lgr.Close(); err != nil {
+			c.logf("%s: close %s: %v", s.host, s.path, err)
+		}
+	}
+	c.mu.Lock()
+	n, dropped := s.samples, s.dropped
+	c.mu.Unlock()
+	c.logf("%s disconnected after %d samples (%d dropped)", s.host, n, dropped)
+}
+
+// write appends rec to the host's segment, rotating it when due.  While
+// the segment cannot be written the host is degraded: its samples are
+// counted as dropped and a fresh segment is tried every c.retry.
+func (c *collector) write(s *collectSession, rec *syslogger.Record) {
+	now := c.now()
+	if s.lgr == nil && !now.Before(s.retryAt) {
+		c.open(s)
+	} else if s.lgr != nil && c.rotateEvery > 0 && now.Sub(s.opened) >= c.rotateEvery {
+		s.lgr.Close()
+		s.lgr = nil
+		c.open(s)
+	}
+	var err error
+	switch {
+	case s.lgr == nil:
+	case rec.Sample != nil:
+		err = s.lgr.WriteSample(*rec.Sample)
+	case rec.Event != nil:
+		err = s.lgr.WriteEvent(*rec.Event)
+	case rec.Process != nil:
+		err = s.lgr.WriteProcess(*rec.Process)
+	default:
+		return // a record type this version does not know
+	}
+	if err == nil && s.lgr != nil {
+		err = s.lgr.Flush()
+	}
+	if err != nil {
+		c.degrade(s, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s.lastRecv = now
+	switch {
+	case s.lgr == nil && rec.Sample != nil:
+		s.dropped++
+	case rec.Sample != nil:
+		s.samples++
+	case s.lgr != nil && rec.Event != nil:
+		s.events++
+	}
+}
+
+// open starts a new segment for s, first moving any existing file aside.
+func (c *collector) open(s *collectSession) {
+	now := c.now()
+	if _, err := os.Stat(s.path); err == nil {
+		aside := segmentPath(s.path, now)
+		for i := 2; ; i++ {
+			if _, err := os.Stat(aside); err != nil {
+				break
+			}
+			aside = strings.TrimSuffix(segmentPath(s.path, now), ".infgo") + fmt.Sprintf("-%d.infgo", i)
+		}
+		if err := os.Rename(s.path, aside); err != nil {
+			c.degrade(s, err)
+			return
+		}
+	}
+	lgr, err := c.create(s.path)
+	if err == nil {
+		hdr := s.hdr
+		hdr.StartedUnixMs = now.UnixMilli()
+		if err = lgr.WriteHeader(hdr); err == nil {
+			err = lgr.Flush()
+		}
+		if err != nil {
+			lgr.Close()
+		}
+	}
+	if err != nil {
+		c.degrade(s, err)
+		return
+	}
+	s.lgr, s.opened = lgr, now
+	c.mu.Lock()
+	recovered := s.degraded != ""
+	s.degraded = ""
+	c.mu.Unlock()
+	if recovered {
+		c.logf("%s: writing %s again", s.host, s.path)
+	}
+}
+
+func (c *collector) degrade(s *collectSession, err error) {
+	if s.lgr != nil {
+		s.lgr.Close()
+		s.lgr = nil
+	}
+	s.retryAt = c.now().Add(c.retry)
+	c.mu.Lock()
+	first := s.degraded == ""
+	s.degraded = err.Error()
+	c.mu.Unlock()
+	if first {
+		c.logf("%s: %v; dropping samples until %s can be written", s.host, err, s.path)
+	}
+}
+
+// register adds s, unless its host is already connected; it then returns
+// that session.
+func (c *collector) register(s *collectSession) *collectSession {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if prev, ok := c.hosts[s.name]; ok {
+		return prev
+	}
+	c.hosts[s.name] = s
+	return nil
+}
+
+func (c *collector) unregister(s *collectSession) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.hosts, s.name)
+}
+
+// collectFileName makes hostname safe to use as a file name, or returns
+// "" if nothing usable is left.
+func collectFileName(hostname string) string {
+	name := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, hostname)
+	if strings.Trim(name, "._") == "" {
+		return ""
+	}
+	return strings.TrimLeft(name, ".")
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+	return n, err
+}
+
+// ── Status endpoint ───────────────────────────────────────────────────────────
+
+// collectStatusJSON is the -status response.
+type collectStatusJSON struct {
+	Hosts []collectHostJSON `json:"hosts"`
+}
+
+type collectHostJSON struct {
+	Host        string   `json:"host"`
+	Remote      string   `json:"remote"`
+	ConnectedS  float64  `json:"connected_s"`
+	File        string   `json:"file"`
+	Samples     int64    `json:"samples"`
+	Events      int64    `json:"events"`
+	Dropped     int64    `json:"dropped"`
+	Bytes       int64    `json:"bytes"`
+	LastSampleS *float64 `json:"last_sample_age_s"` // null until a record arrives
+	Degraded    string   `json:"degraded,omitempty"`
+}
+
+// status lists the connected hosts by name.
+func (c *collector) status() collectStatusJSON {
+	now := c.now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := collectStatusJSON{Hosts: []collectHostJSON{}}
+	for _, s := range c.hosts {
+		h := collectHostJSON{
+			Host: s.host, Remote: s.remote, File: s.path,
+			ConnectedS: now.Sub(s.since).Seconds(),
+			Samples:    s.samples, Events: s.events, Dropped: s.dropped,
+			Bytes:    s.bytes.Load(),
+			Degraded: s.degraded,
+		}
+		if !s.lastRecv.IsZero() {
+			age := now.Sub(s.lastRecv).Seconds()
+			h.LastSampleS = &age
+		}
+		out.Hosts = append(out.Hosts, h)
+	}
+	sort.Slice(out.Hosts, func(i, j int) bool { return out.Hosts[i].Host < out.Hosts[j].Host })
+	return out
+}
+
+func (c *collector) handleStatus(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, c.status())
+}
+
+// ── Command ───────────────────────────────────────────────────────────────────
+
+func runCollect(args []string) error {
+	fs := newFlagSet("collect", "-dir dir [-listen addr] [-status addr] [-rotate-every d]")
+	listen := fs.String("listen", ":"+collectPort, "accept -ship streams on `addr`")
+	dir := fs.String("dir", "", "write each host's capture to `dir`/<hostname>.infgo")
+	statusAddr := fs.String("status", "", "serve the connected hosts as JSON at http://`addr`/status")
+	rotateEvery := fs.Duration("rotate-every", 0, "also start a new segment per host every `d`; 0 rotates only on reconnect")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	switch {
+	case fs.NArg() > 0:
+		return usageErrorf(fs, "unexpected argument %q", fs.Arg(0))
+	case *dir == "":
+		return usageErrorf(fs, "-dir is required")
+	case *rotateEvery < 0 || (*rotateEvery > 0 && *rotateEvery < time.Second):
+		return usageErrorf(fs, "-rotate-every must be at least 1s")
+	}
+	if err := os.MkdirAll(*dir, 0o755); err != nil {
+		return err
+	}
+
+	ln, err := net.Listen("tcp", *listen)
+	if err != nil {
+		return err
+	}
+	c := newCollector(*dir, *rotateEvery, os.Stderr)
+	go c.serve(ln)
+	defer c.close()
+	fmt.Fprintf(os.Stderr, "infgo collect: receiving on %s into %s\n", ln.Addr(), *dir)
+
+	if *statusAddr != "" {
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /status", c.handleStatus)
+		srv := &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+		sln, err := net.Listen("tcp", *statusAddr)
+		if err != nil {
+			return err
+		}
+		go srv.Serve(sln)
+		defer srv.Close()
+		fmt.Fprintf(os.Stderr, "infgo collect: status on http://%s/status\n", sln.Addr())
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+	return nil
+}
@@ -0,0 +1,226 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	syslogger "github.com/ALH477/infgo/logger"
+	"github.com/ALH477/infgo/metrics"
+)
+
+// syncBuffer is a bytes.Buffer safe for the collector's goroutines.
+type syncBuffer struct {
+	mu sync.Mutex
+	b  bytes.Buffer
+}
+
+func (s *syncBuffer) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.b.Write(p)
+}
+
+func (s *syncBuffer) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.b.String()
+}
+
+func startTestCollector(t *testing.T, c *collector) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go c.serve(ln)
+	t.Cleanup(c.close)
+	return ln.Addr().String()
+}
+
+// startAgent is an in-process `-ship` agent for host.
+func startAgent(t *testing.T, addr, host string) *pusher {
+	t.Helper()
+	w, err := newShipWriter(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := startPusher("ship", w)
+	p.setHeader(metrics.Header{Hostname: host})
+	return p
+}
+
+// waitHosts polls the status until want reports true.
+func waitHosts(t *testing.T, c *collector, what string, want func([]collectHostJSON) bool) []collectHostJSON {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		hosts := c.status().Hosts
+		if want(hosts) {
+			return hosts
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("waiting for %s: got %+v", what, hosts)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestCollectTwoAgents(t *testing.T) {
+	dir := t.TempDir()
+	var errs syncBuffer
+	c := newCollector(dir, 0, &errs)
+	addr := startTestCollector(t, c)
+
+	alpha := startAgent(t, addr, "alpha")
+	beta := startAgent(t, addr, "beta.example.com")
+	for i := range 3 {
+		s := metrics.Sample{TimestampUnixMs: int64(1000 * (i + 1)), CpuTotal: 10}
+		alpha.setSample(s)
+		beta.setSample(s)
+	}
+
+	// A stream that is not a capture, and one without a header, are
+	// refused while the agents carry on.
+	for _, junk := range [][]byte{[]byte("GET / HTTP/1.1\r\n\r\n"), streamWithoutHeader(t)} {
+		conn, err := net.Dial("tcp", addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		conn.Write(junk)
+		conn.Close()
+	}
+	alpha.setSample(metrics.Sample{TimestampUnixMs: 4000})
+
+	hosts := waitHosts(t, c, "both agents", func(h []collectHostJSON) bool {
+		return len(h) == 2 && h[0].Samples == 4 && h[1].Samples == 3
+	})
+	if hosts[0].Host != "alpha" || hosts[1].Host != "beta.example.com" {
+		t.Errorf("got hosts %q, %q; want alpha, beta.example.com", hosts[0].Host, hosts[1].Host)
+	}
+	for _, h := range hosts {
+		if h.LastSampleS == nil || *h.LastSampleS > 5 || h.Bytes == 0 || h.Dropped != 0 || h.Degraded != "" {
+			t.Errorf("%s: got %+v", h.Host, h)
+		}
+	}
+
+	for _, p := range []*pusher{alpha, beta} {
+		if err := p.close(); err != nil {
+			t.Error(err)
+		}
+	}
+	waitHosts(t, c, "the agents to disconnect", func(h []collectHostJSON) bool { return len(h) == 0 })
+
+	for file, want := range map[string]int{"alpha.infgo": 4, "beta.example.com.infgo": 3} {
+		kinds := readCapture(t, filepath.Join(dir, file))
+		if len(kinds) != want+1 || kinds[0] != "header" {
+			t.Errorf("%s: got %v, want a header and %d samples", file, kinds, want)
+		}
+	}
+	got := errs.String()
+	for _, want := range []string{"bad magic bytes", "the stream does not start with a header"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("stderr %q lacks %q", got, want)
+		}
+	}
+
+	// A reconnect moves the previous capture aside.
+	again := startAgent(t, addr, "alpha")
+	again.setSample(metrics.Sample{TimestampUnixMs: 5000})
+	waitHosts(t, c, "the reconnect", func(h []collectHostJSON) bool { return len(h) == 1 && h[0].Samples == 1 })
+	again.close()
+	aside, _ := filepath.Glob(filepath.Join(dir, "alpha-*.infgo"))
+	if len(aside) != 1 || len(readCapture(t, aside[0])) != 5 {
+		t.Errorf("got segments %v, want the first session moved aside", aside)
+	}
+}
+
+// streamWithoutHeader is a valid capture that opens with a sample.
+func streamWithoutHeader(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	lgr, err := syslogger.NewWriter(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lgr.WriteSample(metrics.Sample{TimestampUnixMs: 1})
+	lgr.Close()
+	return buf.Bytes()
+}
+
+func TestCollectDegraded(t *testing.T) {
+	dir := t.TempDir()
+	var errs syncBuffer
+	c := newCollector(dir, 0, &errs)
+	c.retry = 50 * time.Millisecond
+	var (
+		mu   sync.Mutex
+		fail = true
+	)
+	c.create = func(path string) (*syslogger.Logger, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if fail {
+			return nil, errors.New("no space left on device")
+		}
+		return syslogger.New(path)
+	}
+	addr := startTestCollector(t, c)
+
+	agent := startAgent(t, addr, "gamma")
+	agent.setSample(metrics.Sample{TimestampUnixMs: 1000})
+	hosts := waitHosts(t, c, "a dropped sample", func(h []collectHostJSON) bool { return len(h) == 1 && h[0].Dropped == 1 })
+	if !strings.Contains(hosts[0].Degraded, "no space left") {
+		t.Errorf("degraded: got %q", hosts[0].Degraded)
+	}
+
+	mu.Lock()
+	fail = false
+	mu.Unlock()
+	time.Sleep(2 * c.retry)
+	agent.setSample(metrics.Sample{TimestampUnixMs: 2000})
+	waitHosts(t, c, "recovery", func(h []collectHostJSON) bool { return len(h) == 1 && h[0].Samples == 1 && h[0].Degraded == "" })
+	agent.close()
+	waitHosts(t, c, "the agent to disconnect", func(h []collectHostJSON) bool { return len(h) == 0 })
+
+	if kinds := readCapture(t, filepath.Join(dir, "gamma.infgo")); len(kinds) != 2 {
+		t.Errorf("got %v, want the header and the sample sent after recovery", kinds)
+	}
+	got := errs.String()
+	if strings.Count(got, "dropping samples") != 1 || !strings.Contains(got, "writing "+filepath.Join(dir, "gamma.infgo")+" again") {
+		t.Errorf("got stderr %q", got)
+	}
+}
+
+func TestCollectFileName(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"web-1", "web-1"},
+		{"db.example.com", "db.example.com"},
+		{"../etc/passwd", "_etc_passwd"},
+		{"a b/c", "a_b_c"},
+		{"..", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := collectFileName(tt.in); got != tt.want {
+			t.Errorf("collectFileName(%q): got %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRunCollectUsage(t *testing.T) {
+	for _, args := range [][]string{{}, {"-dir", os.DevNull, "x"}, {"-dir", "d", "-rotate-every", "1ms"}} {
+		if err := runCollect(args); !errors.Is(err, errUsage) {
+			t.Errorf("%q: got %v, want errUsage", args, err)
+		}
+	}
+}
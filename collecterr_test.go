@@ -0,0 +1,60 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	syslogger "github.com/ALH477/infgo/logger"
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ui"
+)
+
+var errBusy = errors.New("device busy")
+
+func kinds(events []metrics.Event) string {
+	var out []string
+	for _, e := range events {
+		out = append(out, e.Kind)
+	}
+	return strings.Join(out, ",")
+}
+
+// A headless run logs a failing subsystem once, and its recovery.
+func TestHeadlessCollectErrors(t *testing.T) {
+	var out bytes.Buffer
+	lgr, err := syslogger.NewWriter(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reads := 0
+	h := &headless{logger: lgr, read: func(context.Context) ui.Reading {
+		reads++
+		switch {
+		case reads <= 5:
+			return ui.Reading{CPUTotal: 30, CPUCores: []float64{30}, Missing: metrics.MissingMem,
+				Errs: map[metrics.Missing]error{metrics.MissingMem: errBusy}}
+		case reads > 6:
+			cancel()
+		}
+		return ui.Reading{CPUTotal: 30, CPUCores: []float64{30}, MemPercent: 40}
+	}}
+	if err := h.run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	lgr.Close()
+	events, _ := readLog(t, &out)
+	if kinds(events) != "collect_error,collect_ok" {
+		t.Fatalf("got events %+v, want the failure and the recovery", events)
+	}
+	if !strings.HasSuffix(events[1].Message, ": 5 failed") {
+		t.Errorf("got %q, want 5 failed", events[1].Message)
+	}
+}
@@ -0,0 +1,408 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	syslogger "github.com/ALH477/infgo/logger"
+	"github.com/ALH477/infgo/metrics"
+)
+
+// ── Control socket (-control) ─────────────────────────────────────────────────
+//
+// The protocol is one line per request, `<command> [argument]`, answered by
+// one line: `ok [text]` or `error <text>`.  The ok of a command that dumps
+// data, such as history, is followed by the data and a line holding a
+// single ".".  A client may send any number of requests on one connection.
+
+// controlWait bounds how long a request waits for the sampling loop, which
+// picks requests up between ticks.
+const controlWait = 5 * time.Second
+
+// controlCommands lists the commands in the order `infgo ctl` shows them.
+var controlCommands = []struct{ name, summary string }{
+	{"status", "print sample counts, pause state, log path and the latest reading"},
+	{"flush", "write buffered records through to the log"},
+	{"rotate", "move the log aside with a timestamp and continue in a fresh file"},
+	{"marker", "record a marker event: marker <text>"},
+	{"pause", "stop writing samples to the log"},
+	{"resume", "start writing samples to the log again"},
+	{"history", "print the last readings as CSV, as the sparklines hold them: history [cores]"},
+}
+
+func isControlCommand(name string) bool {
+	for _, c := range controlCommands {
+		if c.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// controlBody reports whether the reply to the command name carries data.
+func controlBody(name string) bool { return name == "history" }
+
+// controlRequest is one command handed to the sampling loop.
+type controlRequest struct {
+	cmd   string
+	arg   string
+	reply chan string
+}
+
+// controlServer accepts clients on a unix socket and queues their commands
+// for the headless loop, which runs them on its own goroutine: the only
+// one that touches the logger.  A nil server accepts nothing.
+type controlServer struct {
+	ln   net.Listener
+	reqs chan controlRequest
+	quit chan struct{}
+
+	mu    sync.Mutex
+	conns map[net.Conn]struct{}
+	wg    sync.WaitGroup
+}
+
+// startControl listens on path with the given permissions.  A socket left
+// behind by a crashed instance is replaced; a live one is an error.
+func startControl(path string, mode os.FileMode) (*controlServer, error) {
+	if fi, err := os.Lstat(path); err == nil {
+		if fi.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("-control: %s exists and is not a socket", path)
+		}
+		if c, err := net.DialTimeout("unix", path, time.Second); err == nil {
+			c.Close()
+			return nil, fmt.Errorf("-control: another process is listening on %s", path)
+		}
+		os.Remove(path)
+	}
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("-control: %w", err)
+	}
+	if err := os.Chmod(path, mode); err != nil {
+		ln.Close()
+		return nil, fmt.Errorf("-control: %w", err)
+	}
+	c := &controlServer{
+		ln:    ln,
+		reqs:  make(chan controlRequest),
+		quit:  make(chan struct{}),
+		conns: make(map[net.Conn]struct{}),
+	}
+	c.wg.Add(1)
+	go c.accept()
+	return c, nil
+}
+
+// requests is the channel the sampling loop selects on.
+func (c *controlServer) requests() <-chan controlRequest {
+	if c == nil {
+		return nil
+	}
+	return c.reqs
+}
+
+func (c *controlServer) accept() {
+	defer c.wg.Done()
+	for {
+		conn, err := c.ln.Accept()
+		if err != nil {
+			return
+		}
+		c.mu.Lock()
+		c.conns[conn] = struct{}{}
+		c.mu.Unlock()
+		c.wg.Add(1)
+		go c.serve(conn)
+	}
+}
+
+func (c *controlServer) serve(conn net.Conn) {
+	defer c.wg.Done()
+	defer func() {
+		c.mu.Lock()
+		delete(c.conns, conn)
+		c.mu.Unlock()
+		conn.Close()
+	}()
+	sc := bufio.NewScanner(conn)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		if _, err := fmt.Fprintln(conn, c.do(line)); err != nil {
+			return
+		}
+	}
+}
+
+// do runs one request line and returns the reply line.
+func (c *controlServer) do(line string) string {
+	cmd, arg, _ := strings.Cut(line, " ")
+	if !isControlCommand(cmd) {
+		return fmt.Sprintf("error unknown command %q", cmd)
+	}
+	req := controlRequest{cmd: cmd, arg: strings.TrimSpace(arg), reply: make(chan string, 1)}
+	timeout := time.NewTimer(controlWait)
+	defer timeout.Stop()
+	select {
+	case c.reqs <- req:
+	case <-c.quit:
+		return "error shutting down"
+	case <-timeout.C:
+		return "error the sampling loop did not respond"
+	}
+	return <-req.reply
+}
+
+// close stops accepting, disconnects clients and removes the socket.
+func (c *controlServer) close() {
+	if c == nil {
+		return
+	}
+	close(c.quit)
+	c.ln.Close()
+	c.mu.Lock()
+	for conn := range c.conns {
+		conn.Close()
+	}
+	c.mu.Unlock()
+	c.wg.Wait()
+}
+
+// ── Commands (run by the headless loop) ───────────────────────────────────────
+
+// command executes req against the collector's state and returns the
+// reply line.
+func (h *headless) command(req controlRequest, now time.Time) string {
+	reply, err := h.runCommand(req, now)
+	if err != nil {
+		return "error " + err.Error()
+	}
+	if controlBody(req.cmd) {
+		return "ok\n" + reply + "."
+	}
+	if reply == "" {
+		return "ok"
+	}
+	return "ok " + reply
+}
+
+var errNoLog = errors.New("not recording a -log capture")
+
+func (h *headless) runCommand(req controlRequest, now time.Time) (string, error) {
+	switch req.cmd {
+	case "status":
+		log := "none"
+		if h.logger != nil {
+			log = h.logger.Path()
+		}
+		return fmt.Sprintf("samples=%d written=%d paused=%t log=%s cpu=%.1f mem=%.1f",
+			h.taken, h.written, h.paused, log, h.last.CpuTotal, h.last.MemPercent), nil
+
+	case "flush":
+		if h.logger == nil {
+			return "", errNoLog
+		}
+		return "", h.logger.Flush()
+
+	case "rotate":
+		if h.logger == nil {
+			return "", errNoLog
+		}
+		return h.rotate(now)
+
+	case "marker":
+		if req.arg == "" {
+			return "", errors.New("usage: marker <text>")
+		}
+		if h.logger == nil && h.live == nil {
+			return "", errors.New("nothing to record a marker in: no -log or -listen")
+		}
+		return "", h.event(metrics.Event{TimestampUnixMs: now.UnixMilli(), Kind: "marker", Message: req.arg})
+
+	case "pause", "resume":
+		if h.logger == nil {
+			return "", errNoLog
+		}
+		if h.paused == (req.cmd == "pause") {
+			return "already " + req.cmd + "d", nil
+		}
+		h.paused = req.cmd == "pause"
+		return "", h.event(metrics.Event{TimestampUnixMs: now.UnixMilli(), Kind: req.cmd})
+
+	case "history":
+		if req.arg != "" && req.arg != "cores" {
+			return "", errors.New("usage: history [cores]")
+		}
+		return string(historyCSV(h.histTrail.Samples(&h.cpuHistory, &h.memHistory, req.arg == "cores"))), nil
+	}
+	return "", fmt.Errorf("unknown command %q", req.cmd)
+}
+
+// event writes e to the log and the live stream.
+func (h *headless) event(e metrics.Event) error {
+	if h.live != nil {
+		h.live.publishEvent(e)
+	}
+	if h.logger == nil {
+		return nil
+	}
+	if err := h.logger.WriteEvent(e); err != nil {
+		return err
+	}
+	return h.logger.Flush()
+}
+
+// rotate renames the log to <name>-<UTC time>.infgo and continues in a new
+// file at the original path that starts with a fresh header.  If the new
+// file cannot be created the rename is undone and recording carries on
+// in the old one.  The closed segment is passed to the rotated hook.
+func (h *headless) rotate(now time.Time) (string, error) {
+	path := h.logger.Path()
+	if path == stdinPath {
+		return "", errors.New("cannot rotate a -log - stream")
+	}
+	if err := h.logger.Flush(); err != nil {
+		return "", err
+	}
+	aside := segmentPath(path, now)
+	if _, err := os.Stat(aside); err == nil {
+		return "", fmt.Errorf("%s already exists; rotate at most once a second", aside)
+	}
+	if err := os.Rename(path, aside); err != nil {
+		return "", err
+	}
+	lgr, err := syslogger.New(path)
+	if err == nil {
+		if h.logSchema {
+			lgr.EmbedSchema()
+		}
+		hdr := h.hdr
+		hdr.StartedUnixMs = now.UnixMilli()
+		if err = lgr.WriteHeader(hdr); err == nil {
+			err = lgr.Flush()
+		}
+		if err != nil {
+			lgr.Close()
+		}
+	}
+	if err != nil {
+		os.Rename(aside, path)
+		return "", err
+	}
+	if err := h.logger.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "infgo: close rotated log: %v\n", err)
+	}
+	if h.logger.Held() {
+		lgr.Hold() // the new segment is on the same filesystem
+	}
+	h.logger = lgr
+	if h.rotated != nil {
+		h.rotated(aside)
+	}
+	return "rotated to " + aside, nil
+}
+
+// segmentPath is the name rotation gives path when moving it aside at now:
+// the UTC time is inserted before the extension.
+func segmentPath(path string, now time.Time) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "-" + now.UTC().Format("20060102-150405") + ext
+}
+
+// ── infgo ctl ─────────────────────────────────────────────────────────────────
+
+// controlCall sends one request line to the socket at path and returns the
+// text of an ok reply, or the error reply as an error.
+func controlCall(path, line string, timeout time.Duration) (string, error) {
+	conn, err := net.DialTimeout("unix", path, timeout)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+	if _, err := fmt.Fprintln(conn, line); err != nil {
+		return "", err
+	}
+	rd := bufio.NewReader(conn)
+	reply, err := rd.ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("%s: no reply: %w", path, err)
+	}
+	reply = strings.TrimSpace(reply)
+	if msg, ok := strings.CutPrefix(reply, "error "); ok {
+		return "", errors.New(msg)
+	}
+	if cmd, _, _ := strings.Cut(line, " "); controlBody(cmd) {
+		var body strings.Builder
+		for {
+			l, err := rd.ReadString('\n')
+			if err != nil {
+				return "", fmt.Errorf("%s: reply cut short: %w", path, err)
+			}
+			if l == ".\n" {
+				return strings.TrimSuffix(body.String(), "\n"), nil
+			}
+			body.WriteString(l)
+		}
+	}
+	return strings.TrimSpace(strings.TrimPrefix(reply, "ok")), nil
+}
+
+func runCtl(args []string) error {
+	fs := newFlagSet("ctl", "[-timeout d] <socket> <command> [text]")
+	timeout := fs.Duration("timeout", 10*time.Second, "give up after `d` without a reply")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: infgo ctl [-timeout d] <socket> <command> [text]\n\nCommands:\n")
+		for _, c := range controlCommands {
+			fmt.Fprintf(fs.Output(), "  %-8s %s\n", c.name, c.summary)
+		}
+		fmt.Fprintf(fs.Output(), "\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	// Flags come first, so marker text may itself start with "-".
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	pos := fs.Args()
+	if len(pos) < 2 {
+		return usageErrorf(fs, "expected a socket and a command")
+	}
+	if !isControlCommand(pos[1]) {
+		return usageErrorf(fs, "unknown command %q", pos[1])
+	}
+	reply, err := controlCall(pos[0], strings.Join(pos[1:], " "), *timeout)
+	if err != nil {
+		return err
+	}
+	if reply != "" {
+		fmt.Println(reply)
+	}
+	return nil
+}
+
+// parseFileMode parses an octal permission such as 0600 for flag.Func.
+func parseFileMode(v string) (os.FileMode, error) {
+	m, err := strconv.ParseUint(v, 8, 32)
+	if err != nil || m > 0o777 {
+		return 0, fmt.Errorf("want octal permissions such as 0600, got %q", v)
+	}
+	return os.FileMode(m), nil
+}
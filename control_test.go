@@ -0,0 +1,263 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	syslogger "github.com/ALH477/infgo/logger"
+	"github.com/ALH477/infgo/ui"
+)
+
+// shortTempDir is a temp directory whose paths fit in a sockaddr_un.
+func shortTempDir(t *testing.T) string {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "ctl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+// readCapture returns the kinds of records in path: "header", "sample" or
+// the event's Kind.
+func readCapture(t *testing.T, path string) []string {
+	t.Helper()
+	rd, err := syslogger.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rd.Close()
+	var kinds []string
+	for {
+		rec, err := rd.Next()
+		if err == io.EOF {
+			return kinds
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch {
+		case rec.Header != nil:
+			kinds = append(kinds, "header")
+		case rec.Sample != nil:
+			kinds = append(kinds, "sample")
+		case rec.Event != nil:
+			kinds = append(kinds, rec.Event.Kind)
+		}
+	}
+}
+
+// ctlStatus returns the fields of a status reply.
+func ctlStatus(t *testing.T, sock string) map[string]string {
+	t.Helper()
+	reply, err := controlCall(sock, "status", 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := make(map[string]string)
+	for _, kv := range strings.Fields(reply) {
+		k, v, _ := strings.Cut(kv, "=")
+		fields[k] = v
+	}
+	return fields
+}
+
+func TestControlSocket(t *testing.T) {
+	dir := shortTempDir(t)
+	sock := filepath.Join(dir, "infgo.sock")
+	logPath := filepath.Join(dir, "node.infgo")
+
+	ctl, err := startControl(sock, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ctl.close()
+	if fi, err := os.Stat(sock); err != nil || fi.Mode().Perm() != 0o600 {
+		t.Fatalf("socket mode: got %v, %v; want 0600", fi.Mode().Perm(), err)
+	}
+	lgr, err := syslogger.New(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rotated []string
+	h := &headless{logger: lgr, control: ctl, rotated: func(path string) { rotated = append(rotated, path) }}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- h.run(ctx) }()
+
+	call := func(line string) string {
+		t.Helper()
+		reply, err := controlCall(sock, line, 5*time.Second)
+		if err != nil {
+			t.Fatalf("%s: %v", line, err)
+		}
+		return reply
+	}
+	waitWritten := func(min int) map[string]string {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			st := ctlStatus(t, sock)
+			if mustAtoi(t, st["written"]) >= min {
+				return st
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("no samples written: %v", st)
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+	}
+
+	st := waitWritten(1)
+	if st["log"] != logPath || st["paused"] != "false" {
+		t.Errorf("status: got %v", st)
+	}
+	call("marker deploy v2")
+	call("flush")
+
+	// Paused, samples are taken but not written.
+	call("pause")
+	if r := call("pause"); r != "already paused" {
+		t.Errorf("second pause: got %q", r)
+	}
+	before := ctlStatus(t, sock)
+	time.Sleep(3 * ui.DefaultInterval)
+	after := ctlStatus(t, sock)
+	if after["paused"] != "true" || after["written"] != before["written"] || after["samples"] == before["samples"] {
+		t.Errorf("while paused: before %v, after %v", before, after)
+	}
+	call("resume")
+
+	// Concurrent clients, and several requests on one connection.
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := controlCall(sock, "status", 5*time.Second); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("concurrent status: %v", err)
+	}
+	conn, err := net.Dial("unix", sock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Fprint(conn, "status\nbogus\n")
+	rd := bufio.NewReader(conn)
+	first, _ := rd.ReadString('\n')
+	second, _ := rd.ReadString('\n')
+	conn.Close()
+	if !strings.HasPrefix(first, "ok samples=") || !strings.HasPrefix(second, `error unknown command "bogus"`) {
+		t.Errorf("pipelined replies: got %q, %q", first, second)
+	}
+	if _, err := controlCall(sock, "marker", 5*time.Second); err == nil {
+		t.Error("marker without text: got nil error")
+	}
+
+	r := call("rotate")
+	aside, ok := strings.CutPrefix(r, "rotated to ")
+	if !ok || filepath.Dir(aside) != dir || !strings.HasPrefix(filepath.Base(aside), "node-") || filepath.Ext(aside) != ".infgo" {
+		t.Fatalf("rotate: got %q", r)
+	}
+	waitWritten(mustAtoi(t, ctlStatus(t, sock)["written"]) + 1)
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if err := h.logger.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if len(rotated) != 1 || rotated[0] != aside {
+		t.Errorf("rotation hook: got %q, want [%q]", rotated, aside)
+	}
+
+	old := strings.Join(readCapture(t, aside), " ")
+	for _, want := range []string{"header sample", "marker", "pause", "resume"} {
+		if !strings.Contains(old, want) {
+			t.Errorf("rotated capture %q lacks %q", old, want)
+		}
+	}
+	if cur := readCapture(t, logPath); len(cur) < 2 || cur[0] != "header" || cur[1] != "sample" {
+		t.Errorf("new capture: got %v, want a header then samples", cur)
+	}
+}
+
+func mustAtoi(t *testing.T, s string) int {
+	t.Helper()
+	var n int
+	if _, err := fmt.Sscan(s, &n); err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestStartControlExistingPath(t *testing.T) {
+	dir := shortTempDir(t)
+
+	file := filepath.Join(dir, "file")
+	os.WriteFile(file, nil, 0o600)
+	if _, err := startControl(file, 0o600); err == nil || !strings.Contains(err.Error(), "not a socket") {
+		t.Errorf("regular file: got %v", err)
+	}
+
+	live := filepath.Join(dir, "live.sock")
+	ctl, err := startControl(live, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ctl.close()
+	if _, err := startControl(live, 0o600); err == nil || !strings.Contains(err.Error(), "another process") {
+		t.Errorf("live socket: got %v", err)
+	}
+
+	// A crashed instance leaves its socket behind; the next one takes over.
+	stale := filepath.Join(dir, "stale.sock")
+	ln, err := net.ListenUnix("unix", &net.UnixAddr{Name: stale, Net: "unix"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ln.SetUnlinkOnClose(false)
+	ln.Close()
+	ctl2, err := startControl(stale, 0o660)
+	if err != nil {
+		t.Fatalf("stale socket: %v", err)
+	}
+	ctl2.close()
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Errorf("socket not removed on close: %v", err)
+	}
+}
+
+func TestControlWithoutLog(t *testing.T) {
+	h := &headless{}
+	for _, cmd := range []string{"flush", "rotate", "pause", "marker x"} {
+		name, arg, _ := strings.Cut(cmd, " ")
+		if got := h.command(controlRequest{cmd: name, arg: arg}, time.Now()); !strings.HasPrefix(got, "error ") {
+			t.Errorf("%s without -log: got %q, want an error", cmd, got)
+		}
+	}
+	if got := h.command(controlRequest{cmd: "status"}, time.Now()); !strings.Contains(got, "log=none") {
+		t.Errorf("status: got %q", got)
+	}
+}
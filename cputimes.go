@@ -0,0 +1,39 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+// ── CPU time by mode ──────────────────────────────────────────────────────────
+//
+// The aggregate CPU percentage cannot tell a machine busy running programs
+// from one waiting on its disks or losing time to its hypervisor.  The
+// kernel's cumulative CPU times are read with the CPU, and the share of
+// each mode between two readings is shown under the CPU bar and recorded
+// in each sample.
+
+func cpuTimesOf(t cpu.TimesStat) ui.CPUTimes {
+	return ui.CPUTimes{User: t.User, Nice: t.Nice, System: t.System, Idle: t.Idle, Iowait: t.Iowait, Irq: t.Irq, Softirq: t.Softirq, Steal: t.Steal}
+}
+
+// readTimes reads the CPU times into msg, with the CPU.  They back off on
+// their own, as swap does.
+func (r *statsReader) readTimes(ctx context.Context, msg *ui.Reading) {
+	if r.src.times == nil || !r.times.due(r.now()) {
+		return
+	}
+	ts, err := query(ctx, &r.times, r.timeout, r.src.times)
+	if err != nil || len(ts) == 0 {
+		r.times.failed(r.now())
+		return
+	}
+	r.times.recovered()
+	msg.Times, msg.HasTimes = cpuTimesOf(ts[0]), true
+}
@@ -0,0 +1,30 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+func TestReadTimes(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	r := fakeReader(&fakeSources{}, &now)
+	if msg := r.read(context.Background()); !msg.HasTimes || msg.Times != (ui.CPUTimes{User: 30, System: 10, Idle: 60}) {
+		t.Errorf("got %+v, %v", msg.Times, msg.HasTimes)
+	}
+	r.src.times = func(context.Context) ([]cpu.TimesStat, error) { return nil, errFake }
+	now = now.Add(ui.DefaultInterval)
+	if msg := r.read(context.Background()); msg.HasTimes || msg.Missing != 0 {
+		t.Errorf("times down: got hasTimes %v, missing %q", msg.HasTimes, msg.Missing)
+	}
+	if r.times.due(now) || !r.cpu.due(now) {
+		t.Error("the times did not back off on their own")
+	}
+}
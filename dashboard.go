@@ -0,0 +1,18 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+// ── Multi-host dashboard (-connect a,b,c) ─────────────────────────────────────
+
+// runDashboard runs the grid TUI for the given -connect targets.
+func runDashboard(feeds []ui.Feed) error {
+	_, err := tea.NewProgram(ui.NewDashboard(feeds...), tea.WithAltScreen()).Run()
+	return err
+}
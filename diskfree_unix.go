@@ -0,0 +1,21 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+//go:build linux || darwin || freebsd
+
+package main
+
+import (
+	"syscall"
+)
+
+// diskFree is the space in bytes that an unprivileged writer may still use
+// on the filesystem holding dir: statfs's available blocks, which leave
+// out those the filesystem keeps for root.
+func diskFree(dir string) (uint64, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(dir, &st); err != nil {
+		return 0, err
+	}
+	return uint64(st.Bavail) * uint64(st.Bsize), nil
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	syslogger "github.com/ALH477/infgo/logger"
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ui"
+)
+
+// ── Free-disk guard (-min-free) ───────────────────────────────────────────────
+
+// A capture must not be what fills the filesystem it is written to.  infgo
+// refuses to start a -log on a filesystem with less than -min-free left,
+// unless -force is given, and while logging reads the free space again
+// every minute: below the floor it writes a disk_low event and drops
+// samples and events until there is room again, when a disk_ok event
+// marks the gap and logging resumes.
+
+const (
+	// diskFloor is the default -min-free: 500 MB.
+	diskFloor = 500_000_000
+
+	// diskCheckEvery is how often the free space is read while logging.
+	diskCheckEvery = time.Minute
+)
+
+// addMinFreeFlag registers -min-free on fs.
+func addMinFreeFlag(fs *flag.FlagSet) *uint64 {
+	floor := uint64(diskFloor)
+	fs.Func("min-free", "stop writing the -log while its filesystem has less than `size` free, e.g. 2GB or 750MiB; 0 to disable (default 500MB)", func(v string) error {
+		n, err := parseSize(v)
+		floor = n
+		return err
+	})
+	return &floor
+}
+
+// parseSize reads a size in bytes with an optional unit: B, kB, MB, GB or
+// TB in powers of 1000, KiB, MiB, GiB or TiB in powers of 1024.
+func parseSize(v string) (uint64, error) {
+	num := strings.TrimRight(v, "BbKkMmGgTtiI ")
+	unit := strings.TrimSpace(v[len(num):])
+	f, err := strconv.ParseFloat(strings.TrimSpace(num), 64)
+	if err != nil || f < 0 {
+		return 0, fmt.Errorf("bad size %q: want a number of bytes such as 500MB", v)
+	}
+	mult := map[string]float64{
+		"": 1, "b": 1,
+		"kb": 1e3, "mb": 1e6, "gb": 1e9, "tb": 1e12,
+		"kib": 1 << 10, "mib": 1 << 20, "gib": 1 << 30, "tib": 1 << 40,
+	}[strings.ToLower(unit)]
+	if mult == 0 {
+		return 0, fmt.Errorf("bad size %q: unknown unit %q", v, unit)
+	}
+	return uint64(f * mult), nil
+}
+
+// fmtMB writes b in the fixed format of the log's events, e.g. "412 MB".
+func fmtMB(b uint64) string { return strconv.FormatUint(b/1_000_000, 10) + " MB" }
+
+// diskGuard watches the free space of the filesystem a log is written to.
+// A nil *diskGuard is disabled.
+type diskGuard struct {
+	dir   string
+	floor uint64
+	free  func(dir string) (uint64, error) // diskFree unless a test replaces it
+
+	next  time.Time // of the next reading
+	low   bool      // below floor at the last reading: the log is held
+	since time.Time // when it went low
+	avail uint64    // at the last reading
+}
+
+// newDiskGuard returns the guard of a log at path, or nil for none: no
+// log, a -log - stream or a floor of 0.
+func newDiskGuard(path string, floor uint64) *diskGuard {
+	if path == "" || path == stdinPath || floor == 0 {
+		return nil
+	}
+	return &diskGuard{dir: filepath.Dir(path), floor: floor, free: diskFree}
+}
+
+// preflight is the check before the log is opened: an error below the
+// floor, or with force a warning to w.  Where the free space cannot be
+// read the log is opened regardless.
+func (g *diskGuard) preflight(force bool, w io.Writer) error {
+	if g == nil {
+		return nil
+	}
+	avail, err := g.free(g.dir)
+	if err != nil || avail >= g.floor {
+		return nil
+	}
+	msg := fmt.Sprintf("only %s free on the filesystem of %s, below -min-free %s", ui.FormatBytes(float64(avail)), g.dir, ui.FormatBytes(float64(g.floor)))
+	if !force {
+		return fmt.Errorf("%s; free some space, lower -min-free, or pass -force to start and log once there is room", msg)
+	}
+	fmt.Fprintf(w, "infgo: %s; nothing is logged until there is more\n", msg)
+	return nil
+}
+
+// poll reads the free space if diskCheckEvery has passed since the last
+// reading, and holds or releases lgr as it crosses the floor, with an
+// event that is also published to live.  It reports whether the state
+// changed.  A failed reading changes nothing.
+func (g *diskGuard) poll(now time.Time, lgr *syslogger.Logger, live *liveState) (metrics.Event, bool) {
+	if g == nil || lgr == nil || now.Before(g.next) {
+		return metrics.Event{}, false
+	}
+	g.next = now.Add(diskCheckEvery)
+	avail, err := g.free(g.dir)
+	if err != nil {
+		return metrics.Event{}, false
+	}
+	g.avail = avail
+	var e metrics.Event
+	switch low := avail < g.floor; {
+	case low && !g.low:
+		g.low, g.since = true, now
+		e = metrics.Event{TimestampUnixMs: now.UnixMilli(), Kind: "disk_low",
+			Message: fmt.Sprintf("%s free, below -min-free %s: logging paused", fmtMB(avail), fmtMB(g.floor))}
+		recordEvent(e, lgr, live)
+		_ = lgr.Flush()
+		lgr.Hold()
+	case !low && g.low:
+		g.low = false
+		lgr.Release()
+		e = metrics.Event{TimestampUnixMs: now.UnixMilli(), Kind: "disk_ok",
+			Message: fmt.Sprintf("%s free again: logging resumed after %s without samples", fmtMB(avail), now.Sub(g.since).Round(time.Second))}
+		recordEvent(e, lgr, live)
+	default:
+		return metrics.Event{}, false
+	}
+	return e, true
+}
+
+// isLow reports whether logging is paused for want of space.
+func (g *diskGuard) isLow() bool { return g != nil && g.low }
+
+// badge is the footer's stand-in for "● REC" while logging is paused.
+func (g *diskGuard) badge() string {
+	return lipgloss.NewStyle().Foreground(cAmber).Bold(true).Render("⏸ REC paused") +
+		dimSt.Render(" · "+ui.FormatBytes(float64(g.avail))+" free")
+}
@@ -0,0 +1,193 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	syslogger "github.com/ALH477/infgo/logger"
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ui"
+)
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want uint64
+		bad  bool
+	}{
+		{in: "0", want: 0},
+		{in: "1234", want: 1234},
+		{in: "500MB", want: 500_000_000},
+		{in: "2GB", want: 2_000_000_000},
+		{in: "1.5 GB", want: 1_500_000_000},
+		{in: "750MiB", want: 750 << 20},
+		{in: "1gib", want: 1 << 30},
+		{in: "64kB", want: 64_000},
+		{in: "10B", want: 10},
+		{in: "", bad: true},
+		{in: "-1MB", bad: true},
+		{in: "5XB", bad: true},
+		{in: "lots", bad: true},
+	}
+	for _, tt := range tests {
+		got, err := parseSize(tt.in)
+		if tt.bad {
+			if err == nil {
+				t.Errorf("%q: got %d, want an error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%q: got %d, %v, want %d", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+// fakeDisk is a free-space reading for a guard, counting the reads.
+type fakeDisk struct {
+	avail uint64
+	err   error
+	reads int
+}
+
+func (d *fakeDisk) free(string) (uint64, error) {
+	d.reads++
+	return d.avail, d.err
+}
+
+func guardOn(d *fakeDisk) *diskGuard {
+	g := newDiskGuard("/var/log/infgo/a.infgo", 500_000_000)
+	g.free = d.free
+	return g
+}
+
+func TestNewDiskGuard(t *testing.T) {
+	for _, tt := range []struct {
+		path  string
+		floor uint64
+	}{{"", diskFloor}, {"-", diskFloor}, {"a.infgo", 0}} {
+		if g := newDiskGuard(tt.path, tt.floor); g != nil {
+			t.Errorf("%q, %d: got a guard, want none", tt.path, tt.floor)
+		}
+	}
+	if g := newDiskGuard("/var/log/infgo/a.infgo", diskFloor); g == nil || g.dir != "/var/log/infgo" {
+		t.Errorf("got %+v, want a guard of /var/log/infgo", g)
+	}
+}
+
+func TestDiskPreflight(t *testing.T) {
+	var w bytes.Buffer
+	d := &fakeDisk{avail: 200_000_000}
+	err := guardOn(d).preflight(false, &w)
+	if err == nil || !strings.Contains(err.Error(), "-force") || !strings.Contains(err.Error(), "-min-free") {
+		t.Errorf("below the floor: got %v, want an error naming -force and -min-free", err)
+	}
+
+	if err := guardOn(d).preflight(true, &w); err != nil {
+		t.Errorf("with -force: got %v", err)
+	}
+	if !strings.Contains(w.String(), "nothing is logged") {
+		t.Errorf("with -force: got warning %q", w.String())
+	}
+
+	w.Reset()
+	for _, d := range []*fakeDisk{{avail: 600_000_000}, {err: errors.ErrUnsupported}} {
+		if err := guardOn(d).preflight(false, &w); err != nil || w.Len() > 0 {
+			t.Errorf("%+v: got %v, %q, want the log opened quietly", d, err, w.String())
+		}
+	}
+	var none *diskGuard
+	if err := none.preflight(false, &w); err != nil {
+		t.Errorf("no guard: got %v", err)
+	}
+}
+
+// The guard holds the log below the floor, with an event each way, and
+// reads the free space once a minute.
+func TestDiskGuardPoll(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "low.infgo")
+	lgr, err := syslogger.New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &fakeDisk{avail: 600_000_000}
+	g := guardOn(d)
+	t0 := time.UnixMilli(1_700_000_000_000)
+	sample := func(at time.Time) {
+		t.Helper()
+		if err := lgr.WriteSample(metrics.Sample{TimestampUnixMs: at.UnixMilli(), CpuTotal: 1}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	steps := []struct {
+		after time.Duration
+		avail uint64
+		kind  string // of the event, if the state changes
+		reads int    // so far
+	}{
+		{0, 600_000_000, "", 1},
+		{30 * time.Second, 100_000_000, "", 1}, // not read again yet
+		{time.Minute, 400_000_000, "disk_low", 2},
+		{90 * time.Second, 400_000_000, "", 2},
+		{2 * time.Minute, 450_000_000, "", 3},
+		{4 * time.Minute, 700_000_000, "disk_ok", 4},
+	}
+	for _, s := range steps {
+		d.avail = s.avail
+		now := t0.Add(s.after)
+		e, changed := g.poll(now, lgr, nil)
+		if changed != (s.kind != "") || e.Kind != s.kind {
+			t.Errorf("at %v: got %q, %v, want %q", s.after, e.Kind, changed, s.kind)
+		}
+		if d.reads != s.reads {
+			t.Errorf("at %v: got %d reads, want %d", s.after, d.reads, s.reads)
+		}
+		sample(now)
+	}
+	if g.isLow() || lgr.Held() {
+		t.Error("still held after the space came back")
+	}
+	if err := lgr.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := loadCapture(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The samples of 0, 30s and 4m; those of 1m to 2m were dropped.
+	if len(c.Samples) != 3 {
+		t.Errorf("got %d samples, want 3", len(c.Samples))
+	}
+	if len(c.Events) != 2 || c.Events[0].Kind != "disk_low" || c.Events[1].Kind != "disk_ok" {
+		t.Fatalf("got events %+v, want disk_low then disk_ok", c.Events)
+	}
+	if msg := c.Events[1].Message; !strings.Contains(msg, "700 MB free") || !strings.Contains(msg, "after 3m0s") {
+		t.Errorf("disk_ok: got %q", msg)
+	}
+
+	// A failed reading changes nothing.
+	d.err = errors.ErrUnsupported
+	if _, changed := g.poll(t0.Add(time.Hour), lgr, nil); changed {
+		t.Error("a failed reading changed the state")
+	}
+}
+
+func TestDiskGuardFooter(t *testing.T) {
+	rec := &recorder{logPath: "a.infgo"}
+	m := sizedModel(120, 40, ui.WithRecorder(rec))
+	if f := footer(m); !strings.Contains(f, "● REC") {
+		t.Fatalf("logging: got footer %q", f)
+	}
+	rec.disk = &diskGuard{low: true, avail: 120_000_000}
+	if f := footer(m); !strings.Contains(f, "⏸ REC paused · "+ui.FormatBytes(120_000_000)+" free") || strings.Contains(f, "● REC") {
+		t.Errorf("paused: got footer %q", f)
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/shirou/gopsutil/v3/disk"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+// ── Disk I/O (-disk-io) ───────────────────────────────────────────────────────
+//
+// The disks' read and write throughput, from the deltas of their byte
+// counters between readings, over the time between them as the readings
+// were stamped: a late tick spans more bytes, and as much more time.  The
+// DISK I/O panel draws the sums over the disks as sparklines; d lists each
+// disk.
+//
+// Under them is the await of the slowest disk, the time its reads or its
+// writes took on average between the readings, as iostat has it: the
+// milliseconds spent on them over their count.  Throughput can look fine
+// while a saturated SSD takes 80 ms over each write; the await is amber
+// past ioAwaitWarnMs and red past ioAwaitCritMs.
+
+// ioVirtual are the prefixes of block devices that sit on top of the
+// disks, or in memory, and would count their I/O twice or not at all.
+var ioVirtual = []string{"loop", "ram", "zram", "dm-", "md"}
+
+// ioDisks picks the whole disks from the counters gopsutil reads, leaving
+// out the partitions of another disk listed (sda1, nvme0n1p1) and the
+// ioVirtual devices, sorted by name.
+func ioDisks(stats map[string]disk.IOCountersStat) []ui.IOCounter {
+	partition := func(name string) bool {
+		for other := range stats {
+			if rest, ok := strings.CutPrefix(name, other); ok && rest != "" {
+				rest = strings.TrimPrefix(rest, "p")
+				if rest != "" && strings.Trim(rest, "0123456789") == "" {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	out := []ui.IOCounter{}
+	for name, s := range stats {
+		if partition(name) || slices.ContainsFunc(ioVirtual, func(p string) bool { return strings.HasPrefix(name, p) }) {
+			continue
+		}
+		out = append(out, ui.IOCounter{Name: name, Read: s.ReadBytes, Written: s.WriteBytes,
+			Reads: s.ReadCount, Writes: s.WriteCount, ReadMs: s.ReadTime, WriteMs: s.WriteTime})
+	}
+	slices.SortFunc(out, func(a, b ui.IOCounter) int { return cmp.Compare(a.Name, b.Name) })
+	return out
+}
+
+// readIO reads the disks' counters into msg, if -disk-io asked for them
+// and the subsystem is due.
+func (r *statsReader) readIO(ctx context.Context, msg *ui.Reading) {
+	if !r.ioOn || !r.io.due(r.now()) {
+		return
+	}
+	stats, err := query(ctx, &r.io, r.timeout, func(ctx context.Context) (map[string]disk.IOCountersStat, error) {
+		return r.src.io(ctx)
+	})
+	if err != nil {
+		r.io.failed(r.now())
+		return
+	}
+	r.io.recovered()
+	msg.IO, msg.IOAt = ioDisks(stats), r.now()
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/disk"
+)
+
+func TestIODisks(t *testing.T) {
+	stats := map[string]disk.IOCountersStat{}
+	for _, name := range []string{"sda", "sda1", "sda2", "nvme0n1", "nvme0n1p1", "loop0", "dm-0", "zram0", "md127", "mmcblk0", "mmcblk0p1", "vdb"} {
+		stats[name] = disk.IOCountersStat{ReadBytes: 1, WriteBytes: 2}
+	}
+	var got []string
+	for _, c := range ioDisks(stats) {
+		got = append(got, c.Name)
+	}
+	if want := []string{"mmcblk0", "nvme0n1", "sda", "vdb"}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestReadIO(t *testing.T) {
+	var f fakeSources
+	now := time.Unix(1700000000, 0)
+	r := fakeReader(&f, &now)
+	r.src.io = func(context.Context, ...string) (map[string]disk.IOCountersStat, error) {
+		return map[string]disk.IOCountersStat{"sda": {ReadBytes: 7}}, nil
+	}
+	if msg := r.read(context.Background()); msg.IO != nil {
+		t.Errorf("without -disk-io: got %v", msg.IO)
+	}
+	r.ioOn = true
+	if msg := r.read(context.Background()); len(msg.IO) != 1 || msg.IO[0].Read != 7 || !msg.IOAt.Equal(now) {
+		t.Errorf("got %v at %v", msg.IO, msg.IOAt)
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/disk"
+
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ui"
+)
+
+// ── DISK panel ────────────────────────────────────────────────────────────────
+//
+// The panel under MEMORY lists the mounted filesystems with a bar each,
+// the fullest diskPanelRows of them, and counts the rest.  The mounts are
+// listed again every mountsRelist, so that a drive plugged in appears
+// within seconds, and each is read on every stats tick, backing off on
+// its own like the -disks mounts when it fails.  Where a filesystem has
+// a fixed number of inodes their use follows its size, since running out
+// of them fills a disk as surely as running out of bytes.
+
+// mountsRelist is how often the list of mounts is read again.
+const mountsRelist = 5 * time.Second
+
+// pseudoFS are the filesystem types the panel leaves out unless
+// -pseudo-fs: those held in memory, and the read-only images that snaps
+// and live systems mount by the dozen.
+var pseudoFS = []string{
+	"tmpfs", "devtmpfs", "ramfs", "overlay", "squashfs", "proc", "sysfs",
+	"cgroup", "cgroup2", "devpts", "mqueue", "debugfs", "tracefs",
+	"securityfs", "pstore", "bpf", "autofs", "configfs", "fusectl",
+	"hugetlbfs", "efivarfs", "nsfs", "binfmt_misc", "rpc_pipefs",
+}
+
+// mountWatch lists the filesystems for the panel.
+type mountWatch struct {
+	pseudo bool // -pseudo-fs
+	list   subsystem
+	listed time.Time
+	mounts []*diskMount
+}
+
+// watchMounts makes the reader read the usage of every mounted
+// filesystem for the DISK panel, with pseudo filesystems if pseudo.
+func (r *statsReader) watchMounts(pseudo bool) {
+	r.mounts = &mountWatch{pseudo: pseudo}
+}
+
+// readMounts lists the filesystems again if that is due, and appends the
+// usage of each that is due to msg.mounts.  A mount also recorded with
+// -disks is read once.
+func (r *statsReader) readMounts(ctx context.Context, msg *ui.Reading) {
+	w := r.mounts
+	if w == nil {
+		return
+	}
+	if now := r.now(); (w.listed.IsZero() || now.Sub(w.listed) >= mountsRelist) && w.list.due(now) {
+		parts, err := query(ctx, &w.list, r.timeout, func(context.Context) ([]disk.PartitionStat, error) {
+			return r.src.partitions(w.pseudo)
+		})
+		if err != nil {
+			w.list.failed(now)
+		} else {
+			w.list.recovered()
+			w.listed = now
+			w.relist(parts)
+		}
+	}
+	msg.Mounts = []metrics.DiskUsage{}
+	for _, d := range w.mounts {
+		if j := slices.IndexFunc(msg.Disks, func(u metrics.DiskUsage) bool { return u.Mount == d.path }); j >= 0 {
+			msg.Mounts = append(msg.Mounts, msg.Disks[j])
+			continue
+		}
+		if u, ok := r.readMount(ctx, d); ok && (u.TotalGB > 0 || w.pseudo) {
+			msg.Mounts = append(msg.Mounts, u)
+		}
+	}
+}
+
+// relist replaces the mounts with those of parts, keeping the backoff of
+// the ones still mounted.
+func (w *mountWatch) relist(parts []disk.PartitionStat) {
+	var mounts []*diskMount
+	for _, p := range parts {
+		if !w.pseudo && slices.Contains(pseudoFS, p.Fstype) {
+			continue
+		}
+		at := func(d *diskMount) bool { return d.path == p.Mountpoint }
+		if slices.ContainsFunc(mounts, at) {
+			continue
+		}
+		if i := slices.IndexFunc(w.mounts, at); i >= 0 {
+			mounts = append(mounts, w.mounts[i])
+		} else {
+			mounts = append(mounts, &diskMount{path: p.Mountpoint})
+		}
+	}
+	slices.SortFunc(mounts, func(a, b *diskMount) int { return cmp.Compare(a.path, b.path) })
+	w.mounts = mounts
+}
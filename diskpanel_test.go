@@ -0,0 +1,89 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/disk"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+// The panel lists the real filesystems, once each, picks up those mounted
+// since, and backs off a failing one on its own.
+func TestMountWatch(t *testing.T) {
+	var f fakeSources
+	now := time.Unix(1700000000, 0)
+	r := fakeReader(&f, &now)
+	parts := []disk.PartitionStat{
+		{Mountpoint: "/", Fstype: "ext4"},
+		{Mountpoint: "/home", Fstype: "ext4"},
+		{Mountpoint: "/run", Fstype: "tmpfs"},
+		{Mountpoint: "/snap/core/1", Fstype: "squashfs"},
+		{Mountpoint: "/home", Fstype: "ext4"}, // bind-mounted twice
+	}
+	var lists int
+	r.src.partitions = func(bool) ([]disk.PartitionStat, error) { lists++; return parts, nil }
+	calls := map[string]int{}
+	r.src.disk = func(_ context.Context, path string) (*disk.UsageStat, error) {
+		calls[path]++
+		if path == "/media/usb" && calls[path] == 1 {
+			return nil, errFake
+		}
+		return &disk.UsageStat{UsedPercent: 50, Used: 10 << 30, Total: 20 << 30}, nil
+	}
+	r.watchMounts(false)
+	mounts := func() []string {
+		var out []string
+		for _, u := range r.read(context.Background()).Mounts {
+			out = append(out, u.Mount)
+		}
+		return out
+	}
+
+	if got := mounts(); !slices.Equal(got, []string{"/", "/home"}) {
+		t.Errorf("got %v", got)
+	}
+	parts = append(parts, disk.PartitionStat{Mountpoint: "/media/usb", Fstype: "vfat"})
+	now = now.Add(ui.DefaultInterval)
+	if got := mounts(); len(got) != 2 || lists != 1 {
+		t.Errorf("before the relist: got %v after %d lists", got, lists)
+	}
+	now = now.Add(mountsRelist)
+	if got := mounts(); !slices.Equal(got, []string{"/", "/home"}) || calls["/media/usb"] != 1 {
+		t.Errorf("failing drive: got %v, read %d times", got, calls["/media/usb"])
+	}
+	now = now.Add(statsBackoffMin)
+	if got := mounts(); !slices.Equal(got, []string{"/", "/home", "/media/usb"}) {
+		t.Errorf("drive plugged in: got %v", got)
+	}
+
+	r.watchMounts(true)
+	if got := mounts(); len(got) != 5 {
+		t.Errorf("-pseudo-fs: got %v", got)
+	}
+}
+
+// A filesystem with no inodes, as btrfs, reads as none rather than 0%.
+func TestReadMountInodes(t *testing.T) {
+	var f fakeSources
+	now := time.Unix(1700000000, 0)
+	r := fakeReader(&f, &now)
+	r.src.disk = func(_ context.Context, path string) (*disk.UsageStat, error) {
+		u := &disk.UsageStat{UsedPercent: 50, Used: 10 << 30, Total: 20 << 30}
+		if path == "/" {
+			u.InodesTotal, u.InodesUsed, u.InodesUsedPercent = 1000, 250, 25
+		}
+		return u, nil
+	}
+	for path, want := range map[string]float64{"/": 25, "/mnt/btrfs": 0} {
+		if u, ok := r.readMount(context.Background(), &diskMount{path: path}); !ok || u.InodesUsedPercent != want {
+			t.Errorf("%s: got %+v, %v", path, u, ok)
+		}
+	}
+}
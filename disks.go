@@ -0,0 +1,150 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/shirou/gopsutil/v3/disk"
+
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ui"
+)
+
+// ── Disk usage (-disks) ───────────────────────────────────────────────────────
+
+// diskMount is a filesystem whose usage goes in every sample, queried
+// like the other subsystems: one that fails or hangs, a stale NFS mount
+// say, backs off on its own and is left out of the samples meanwhile.
+type diskMount struct {
+	path string // the mount point
+	sub  subsystem
+}
+
+// watchDisks makes the reader record the usage of the filesystems -disks
+// names in spec, and returns their mount points; see diskMounts.  Call it
+// before the first reading.
+func (r *statsReader) watchDisks(spec, logPath string) ([]string, error) {
+	mounts, err := diskMounts(spec, logPath, r.src.partitions)
+	if err != nil {
+		return nil, err
+	}
+	r.disks = make([]diskMount, len(mounts))
+	for i, m := range mounts {
+		r.disks[i].path = m
+	}
+	return mounts, nil
+}
+
+// readDisks appends the usage of each watched filesystem that is due to
+// msg.
+func (r *statsReader) readDisks(ctx context.Context, msg *ui.Reading) {
+	for i := range r.disks {
+		if u, ok := r.readMount(ctx, &r.disks[i]); ok {
+			msg.Disks = append(msg.Disks, u)
+		}
+	}
+}
+
+// readMount reads the usage of d if it is due; false if it is not, or
+// failed.
+func (r *statsReader) readMount(ctx context.Context, d *diskMount) (metrics.DiskUsage, bool) {
+	if !d.sub.due(r.now()) {
+		return metrics.DiskUsage{}, false
+	}
+	u, err := query(ctx, &d.sub, r.timeout, func(ctx context.Context) (*disk.UsageStat, error) {
+		return r.src.disk(ctx, d.path)
+	})
+	if err != nil {
+		d.sub.failed(r.now())
+		return metrics.DiskUsage{}, false
+	}
+	d.sub.recovered()
+	const gb = 1 << 30
+	du := metrics.DiskUsage{
+		Mount:       d.path,
+		UsedPercent: u.UsedPercent,
+		UsedGB:      float64(u.Used) / gb,
+		TotalGB:     float64(u.Total) / gb,
+	}
+	// btrfs and many network filesystems report no inodes at all, which
+	// is not 0% of them used.
+	if u.InodesTotal > 0 {
+		du.InodesUsedPercent = u.InodesUsedPercent
+	}
+	return du, true
+}
+
+// diskMounts resolves -disks: a comma-separated list of paths, each
+// standing for the filesystem that holds it, or "none".  Empty means the
+// filesystem of / and that of the log at logPath, if there is one.  Each
+// path is replaced by its mount point from partitions, and duplicates are
+// dropped; where the mounts cannot be listed the paths are kept as given.
+func diskMounts(spec, logPath string, partitions func(all bool) ([]disk.PartitionStat, error)) ([]string, error) {
+	var paths []string
+	switch spec {
+	case "none":
+		return nil, nil
+	case "":
+		paths = []string{string(filepath.Separator)}
+		if logPath != "" && logPath != stdinPath {
+			paths = append(paths, filepath.Dir(logPath))
+		}
+	default:
+		for _, p := range strings.Split(spec, ",") {
+			p = strings.TrimSpace(p)
+			if p == "" {
+				return nil, fmt.Errorf("-disks: empty path in %q", spec)
+			}
+			if _, err := os.Stat(p); err != nil {
+				return nil, fmt.Errorf("-disks: %w", err)
+			}
+			paths = append(paths, p)
+		}
+	}
+	parts, err := partitions(false)
+	if err != nil {
+		parts = nil
+	}
+	var mounts []string
+	for _, p := range paths {
+		if abs, err := filepath.Abs(p); err == nil {
+			p = abs
+		}
+		m := mountPoint(p, parts)
+		if !slices.Contains(mounts, m) {
+			mounts = append(mounts, m)
+		}
+	}
+	if len(mounts) > metrics.MaxDisks {
+		return nil, fmt.Errorf("-disks: %d filesystems, more than the %d a sample holds", len(mounts), metrics.MaxDisks)
+	}
+	return mounts, nil
+}
+
+// mountPoint is the mount point of the filesystem holding the absolute
+// path p: the longest of those in parts that contains it, or p itself
+// where none does.
+func mountPoint(p string, parts []disk.PartitionStat) string {
+	best := ""
+	for _, part := range parts {
+		mp := part.Mountpoint
+		rel, err := filepath.Rel(mp, p)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if len(mp) > len(best) {
+			best = mp
+		}
+	}
+	if best == "" {
+		return p
+	}
+	return best
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/disk"
+
+	"github.com/ALH477/infgo/analysis"
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ui"
+)
+
+func partitionsAt(mounts ...string) func(bool) ([]disk.PartitionStat, error) {
+	return func(bool) ([]disk.PartitionStat, error) {
+		var out []disk.PartitionStat
+		for _, m := range mounts {
+			out = append(out, disk.PartitionStat{Mountpoint: m})
+		}
+		return out, nil
+	}
+}
+
+func TestDiskMounts(t *testing.T) {
+	dir := t.TempDir()
+	root := string(filepath.Separator)
+	parts := partitionsAt(root, dir)
+	tests := []struct {
+		spec, log string
+		want      []string
+	}{
+		{"", "", []string{root}},
+		{"", stdinPath, []string{root}},
+		{"", filepath.Join(dir, "sub", "node.infgo"), []string{root, dir}},
+		{"", filepath.Join(root, "node.infgo"), []string{root}}, // the same filesystem once
+		{dir + "," + root, "", []string{dir, root}},
+		{"none", filepath.Join(dir, "node.infgo"), nil},
+	}
+	for _, tt := range tests {
+		got, err := diskMounts(tt.spec, tt.log, parts)
+		if err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("diskMounts(%q, %q): got %v, %v; want %v", tt.spec, tt.log, got, err, tt.want)
+		}
+	}
+	if _, err := diskMounts(filepath.Join(dir, "nope"), "", parts); err == nil {
+		t.Error("a path that does not exist: got nil error")
+	}
+	if _, err := diskMounts(dir+",", "", parts); err == nil {
+		t.Error("an empty path: got nil error")
+	}
+	// Without the list of mounts, the paths stand for themselves.
+	got, err := diskMounts(dir, "", func(bool) ([]disk.PartitionStat, error) { return nil, os.ErrPermission })
+	if err != nil || !slices.Equal(got, []string{dir}) {
+		t.Errorf("without partitions: got %v, %v", got, err)
+	}
+}
+
+// A filesystem that cannot be read is left out of the samples and backs
+// off; the others carry on.
+func TestStatsReaderDisks(t *testing.T) {
+	var f fakeSources
+	now := time.Unix(1700000000, 0)
+	r := fakeReader(&f, &now)
+	calls := map[string]int{}
+	r.src.disk = func(_ context.Context, path string) (*disk.UsageStat, error) {
+		calls[path]++
+		if path == "/mnt/nfs" {
+			return nil, errFake
+		}
+		return &disk.UsageStat{Path: path, UsedPercent: 25, Used: 50 << 30, Total: 200 << 30}, nil
+	}
+	r.src.partitions = partitionsAt("/mnt/nfs")
+	dir := t.TempDir()
+	if _, err := r.watchDisks(dir+",/mnt/nfs", ""); err == nil {
+		t.Fatal("/mnt/nfs does not exist here: got nil error")
+	}
+	r.disks = []diskMount{{path: "/"}, {path: "/mnt/nfs"}}
+
+	for range 2 {
+		s := r.read(context.Background()).Sample(now)
+		want := []metrics.DiskUsage{{Mount: "/", UsedPercent: 25, UsedGB: 50, TotalGB: 200}}
+		if !slices.Equal(s.Disks, want) {
+			t.Errorf("got %+v, want %+v", s.Disks, want)
+		}
+		now = now.Add(ui.DefaultInterval)
+	}
+	if calls["/"] != 2 || calls["/mnt/nfs"] != 1 {
+		t.Errorf("got calls %v, want the failed mount backed off", calls)
+	}
+}
+
+// analyze summarises each mount, and export csv gives it three columns.
+func TestDiskColumns(t *testing.T) {
+	at := func(i int) int64 { return 1704067200000 + int64(i)*1000 }
+	samples := []metrics.Sample{
+		{TimestampUnixMs: at(0), Disks: []metrics.DiskUsage{{Mount: "/", UsedPercent: 40, UsedGB: 80, TotalGB: 200}}},
+		{TimestampUnixMs: at(1), Disks: []metrics.DiskUsage{
+			{Mount: "/", UsedPercent: 50, UsedGB: 100, TotalGB: 200}, {Mount: "/var/log", UsedPercent: 10, UsedGB: 1, TotalGB: 10}}},
+	}
+	var csv bytes.Buffer
+	if err := writeSamplesCSV(&csv, samples, nil); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(csv.String()), "\n")
+	if !strings.HasSuffix(lines[0], ",disk_used_percent@/,disk_used_gb@/,disk_total_gb@/,disk_used_percent@/var/log,disk_used_gb@/var/log,disk_total_gb@/var/log") {
+		t.Errorf("head: got %q", lines[0])
+	}
+	if !strings.HasSuffix(lines[1], ",40,80,200,,,") || !strings.HasSuffix(lines[2], ",50,100,200,10,1,10") {
+		t.Errorf("rows: got %q", lines[1:])
+	}
+
+	c := &analysis.Capture{Header: &metrics.Header{Hostname: "h", IntervalMs: 1000}, Samples: samples}
+	var out bytes.Buffer
+	printSummary(&out, c, analysis.SummarizeSamples(samples), nil, resolveZone("utc", nil))
+	if !strings.Contains(out.String(), "Disk /          "+ui.FormatPercent(40)) || !strings.Contains(out.String(), "Disk /var/log ") {
+		t.Errorf("analyze: got\n%s", out.String())
+	}
+}
@@ -0,0 +1,259 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/muesli/termenv"
+
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ui"
+)
+
+// ── doctor ────────────────────────────────────────────────────────────────────
+
+// `infgo doctor` looks over the machine before infgo is started on it:
+// whether the readings can be taken, whether the terminal can draw the
+// TUI, and whether a capture could be written where asked.  A check that
+// would leave infgo unable to do its job FAILs and sets exit status 1; one
+// that only makes it do less, such as a terminal of 16 colours or power
+// readings that need root, warns.
+
+// doctorPause is the time between the two readings taken, the first of
+// which is only the CPU's starting point.
+const doctorPause = 250 * time.Millisecond
+
+// doctorLevel grades a check.
+type doctorLevel int
+
+const (
+	doctorOK doctorLevel = iota
+	doctorWarn
+	doctorFail
+)
+
+func (l doctorLevel) String() string {
+	return [...]string{"ok", "warn", "FAIL"}[l]
+}
+
+// doctorResult is the verdict on one check.
+type doctorResult struct {
+	name   string
+	level  doctorLevel
+	detail string
+}
+
+// doctorEnv is what the checks look at, gathered by runDoctor and made up
+// by the tests.
+type doctorEnv struct {
+	procRoot string // "" where there is no /proc to read
+	read     func() ui.Reading
+	pause    time.Duration // between the two readings
+
+	terminal      bool
+	profile       termenv.Profile
+	width, height int // 0 where unknown
+
+	logDir  string
+	minFree uint64
+	free    func(dir string) (uint64, error)
+
+	raplErr error // from opening RAPL; nil where it opened
+	layout  string
+}
+
+// checkProc reads the files the Linux collectors use.
+func (e doctorEnv) checkProc() doctorResult {
+	if e.procRoot == "" {
+		return doctorResult{"proc", doctorOK, "not Linux; readings come from the system's own interfaces"}
+	}
+	for _, name := range []string{"stat", "meminfo", "loadavg"} {
+		if _, err := os.ReadFile(filepath.Join(e.procRoot, name)); err != nil {
+			return doctorResult{"proc", doctorFail, err.Error()}
+		}
+	}
+	return doctorResult{"proc", doctorOK, e.procRoot + " is readable"}
+}
+
+// checkReadings takes two readings and names the groups missing from the
+// second.  CPU and memory are what infgo is for; the load average is not
+// to be had everywhere.
+func (e doctorEnv) checkReadings() doctorResult {
+	e.read()
+	time.Sleep(e.pause)
+	r := e.read()
+	var missing []string
+	level := doctorOK
+	for _, g := range []struct {
+		bit   metrics.Missing
+		name  string
+		fatal bool
+	}{{metrics.MissingCPU, "cpu", true}, {metrics.MissingMem, "memory", true}, {metrics.MissingLoad, "load", false}} {
+		if r.Missing.Has(g.bit) {
+			missing = append(missing, g.name)
+			if g.fatal {
+				level = doctorFail
+			} else {
+				level = max(level, doctorWarn)
+			}
+		}
+	}
+	if len(missing) > 0 {
+		return doctorResult{"readings", level, "could not read " + strings.Join(missing, ", ")}
+	}
+	return doctorResult{"readings", doctorOK, fmt.Sprintf("cpu %s on %d cores, memory %s", ui.FormatPercent(r.CPUTotal), len(r.CPUCores), ui.FormatPercent(r.MemPercent))}
+}
+
+// checkTerminal grades stdout for the TUI: its colours and its size.
+func (e doctorEnv) checkTerminal() doctorResult {
+	if !e.terminal {
+		return doctorResult{"terminal", doctorWarn, "stdout is not a terminal; infgo will print a line per sample"}
+	}
+	var problems []string
+	switch e.profile {
+	case termenv.Ascii:
+		problems = append(problems, "no colour")
+	case termenv.ANSI:
+		problems = append(problems, "16 colours; the panels are drawn in the nearest")
+	}
+	if e.width > 0 && (e.width < ui.MinTermWidth || e.height < ui.MinTermHeight) {
+		problems = append(problems, fmt.Sprintf("%d×%d is below the %d×%d the layout needs", e.width, e.height, ui.MinTermWidth, ui.MinTermHeight))
+	}
+	if len(problems) > 0 {
+		return doctorResult{"terminal", doctorWarn, strings.Join(problems, "; ")}
+	}
+	colours := "256 colours"
+	if e.profile == termenv.TrueColor {
+		colours = "true colour"
+	}
+	if e.width == 0 {
+		return doctorResult{"terminal", doctorOK, colours}
+	}
+	return doctorResult{"terminal", doctorOK, fmt.Sprintf("%s, %d×%d", colours, e.width, e.height)}
+}
+
+// checkLogDir writes a file to the log directory, and compares the space
+// free there with -min-free.
+func (e doctorEnv) checkLogDir() doctorResult {
+	f, err := os.CreateTemp(e.logDir, ".infgo-doctor-*")
+	if err != nil {
+		return doctorResult{"log", doctorFail, fmt.Sprintf("%s is not writable: %v", e.logDir, errors.Unwrap(err))}
+	}
+	f.Close()
+	os.Remove(f.Name())
+	avail, err := e.free(e.logDir)
+	switch {
+	case err != nil:
+		return doctorResult{"log", doctorOK, e.logDir + " is writable"}
+	case e.minFree > 0 && avail < e.minFree:
+		return doctorResult{"log", doctorWarn, fmt.Sprintf("%s is writable, but only %s is free, below -min-free %s", e.logDir, ui.FormatBytes(float64(avail)), ui.FormatBytes(float64(e.minFree)))}
+	}
+	return doctorResult{"log", doctorOK, fmt.Sprintf("%s is writable, %s free", e.logDir, ui.FormatBytes(float64(avail)))}
+}
+
+// checkPower reports whether the Energy row will show watts.
+func (e doctorEnv) checkPower() doctorResult {
+	switch {
+	case e.raplErr == nil:
+		return doctorResult{"power", doctorOK, "RAPL is readable"}
+	case errors.Is(e.raplErr, errNoRAPL):
+		return doctorResult{"power", doctorOK, "no RAPL counters; the Energy row shows core-seconds only"}
+	case raplNote(e.raplErr) != "":
+		return doctorResult{"power", doctorWarn, raplNote(e.raplErr) + "; the Energy row shows core-seconds only"}
+	}
+	return doctorResult{"power", doctorWarn, e.raplErr.Error()}
+}
+
+// checkLayout reads the panels saved by -save-layout.
+func (e doctorEnv) checkLayout() doctorResult {
+	if e.layout == "" {
+		return doctorResult{"layout", doctorWarn, "no config directory; -save-layout cannot save"}
+	}
+	if _, err := readLayout(e.layout); err != nil {
+		return doctorResult{"layout", doctorWarn, err.Error()}
+	}
+	return doctorResult{"layout", doctorOK, e.layout}
+}
+
+// checks runs every check, in the order they are printed.
+func (e doctorEnv) checks() []doctorResult {
+	return []doctorResult{e.checkProc(), e.checkReadings(), e.checkTerminal(), e.checkLogDir(), e.checkPower(), e.checkLayout()}
+}
+
+// runDoctor implements `infgo doctor`.
+func runDoctor(args []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	env := doctorEnv{
+		read:     func() ui.Reading { return readStats(ctx) },
+		pause:    doctorPause,
+		terminal: stdoutIsTerminal(),
+		profile:  termenv.NewOutput(os.Stdout).EnvColorProfile(),
+		logDir:   ".",
+		free:     diskFree,
+	}
+	if runtime.GOOS == "linux" {
+		env.procRoot = "/proc"
+	}
+	if env.terminal {
+		env.width, env.height, _ = term.GetSize(os.Stdout.Fd())
+	}
+	_, env.raplErr = openRAPL(raplRoot)
+	env.layout, _ = layoutPath()
+	return doctor(args, os.Stdout, env)
+}
+
+// doctor is runDoctor with the output and the environment injected.
+func doctor(args []string, w io.Writer, env doctorEnv) error {
+	fs := newFlagSet("doctor", "[-log-dir dir]")
+	fs.StringVar(&env.logDir, "log-dir", env.logDir, "check that captures can be written to `dir`")
+	minFree := addMinFreeFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	if fs.NArg() > 0 {
+		return usageErrorf(fs, "unexpected argument %q", fs.Arg(0))
+	}
+	env.minFree = *minFree
+
+	results := env.checks()
+	failed, warned := 0, 0
+	for _, r := range results {
+		switch r.level {
+		case doctorFail:
+			failed++
+		case doctorWarn:
+			warned++
+		}
+		fmt.Fprintf(w, "  %-8s %-4s  %s\n", r.name, r.level, r.detail)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(results))
+	}
+	switch warned {
+	case 0:
+		fmt.Fprintln(w, "infgo doctor: ready")
+	case 1:
+		fmt.Fprintln(w, "infgo doctor: ready, with a warning")
+	default:
+		fmt.Fprintf(w, "infgo doctor: ready, with %d warnings\n", warned)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/muesli/termenv"
+
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ui"
+)
+
+// readyEnv is a machine where everything checks out.
+func readyEnv(t *testing.T) doctorEnv {
+	t.Helper()
+	proc := t.TempDir()
+	for _, name := range []string{"stat", "meminfo", "loadavg"} {
+		if err := os.WriteFile(filepath.Join(proc, name), []byte("0\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return doctorEnv{
+		procRoot: proc,
+		read:     func() ui.Reading { return ui.Reading{CPUTotal: 12, CPUCores: []float64{12, 12}, MemPercent: 40} },
+		terminal: true, profile: termenv.ANSI256, width: 120, height: 40,
+		logDir:  t.TempDir(),
+		free:    func(string) (uint64, error) { return 10 << 30, nil },
+		raplErr: errNoRAPL,
+		layout:  filepath.Join(t.TempDir(), "layout"),
+	}
+}
+
+func TestDoctorReady(t *testing.T) {
+	var b strings.Builder
+	if err := doctor(nil, &b, readyEnv(t)); err != nil {
+		t.Fatalf("got %v\n%s", err, b.String())
+	}
+	out := b.String()
+	for _, want := range []string{"  readings ok    cpu " + ui.FormatPercent(12) + " on 2 cores", "  terminal ok    256 colours, 120×40", "infgo doctor: ready\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in\n%s", want, out)
+		}
+	}
+}
+
+// Each problem is graded: those that leave infgo unable to run fail, the
+// others warn.
+func TestDoctorChecks(t *testing.T) {
+	tests := []struct {
+		name  string
+		set   func(*doctorEnv)
+		check string
+		level doctorLevel
+		want  string
+	}{
+		{"no proc", func(e *doctorEnv) { os.Remove(filepath.Join(e.procRoot, "meminfo")) }, "proc", doctorFail, "meminfo"},
+		{"no cpu", func(e *doctorEnv) {
+			e.read = func() ui.Reading { return ui.Reading{Missing: metrics.MissingCPU | metrics.MissingLoad} }
+		}, "readings", doctorFail, "could not read cpu, load"},
+		{"no load", func(e *doctorEnv) { e.read = func() ui.Reading { return ui.Reading{Missing: metrics.MissingLoad} } }, "readings", doctorWarn, "load"},
+		{"piped", func(e *doctorEnv) { e.terminal = false }, "terminal", doctorWarn, "not a terminal"},
+		{"16 colours", func(e *doctorEnv) { e.profile = termenv.ANSI }, "terminal", doctorWarn, "16 colours"},
+		{"small", func(e *doctorEnv) { e.width, e.height = 60, 15 }, "terminal", doctorWarn, "60×15 is below"},
+		{"true colour", func(e *doctorEnv) { e.profile = termenv.TrueColor }, "terminal", doctorOK, "true colour"},
+		{"unwritable", func(e *doctorEnv) { e.logDir = filepath.Join(e.logDir, "none") }, "log", doctorFail, "is not writable"},
+		{"full", func(e *doctorEnv) { e.minFree = 20 << 30 }, "log", doctorWarn, "below -min-free"},
+		{"rapl root", func(e *doctorEnv) { e.raplErr = &fs.PathError{Op: "open", Path: "energy_uj", Err: fs.ErrPermission} }, "power", doctorWarn, "RAPL needs root"},
+		{"rapl", func(e *doctorEnv) { e.raplErr = nil }, "power", doctorOK, "readable"},
+		{"no config", func(e *doctorEnv) { e.layout = "" }, "layout", doctorWarn, "-save-layout"},
+	}
+	for _, tt := range tests {
+		env := readyEnv(t)
+		tt.set(&env)
+		for _, r := range env.checks() {
+			if r.name != tt.check {
+				if r.level != doctorOK {
+					t.Errorf("%s: %s is %s: %s", tt.name, r.name, r.level, r.detail)
+				}
+				continue
+			}
+			if r.level != tt.level || !strings.Contains(r.detail, tt.want) {
+				t.Errorf("%s: got %s %q, want %s mentioning %q", tt.name, r.level, r.detail, tt.level, tt.want)
+			}
+		}
+	}
+}
+
+func TestDoctorFails(t *testing.T) {
+	env := readyEnv(t)
+	env.logDir = filepath.Join(env.logDir, "none")
+	var b strings.Builder
+	err := doctor([]string{"-min-free", "0"}, &b, env)
+	if err == nil || err.Error() != "1 of 6 checks failed" {
+		t.Errorf("got %v", err)
+	}
+	if strings.Contains(b.String(), "ready") {
+		t.Errorf("got %q", b.String())
+	}
+	if err := doctor([]string{"extra"}, &b, env); !errors.Is(err, errUsage) {
+		t.Errorf("argument: got %v", err)
+	}
+}
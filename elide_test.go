@@ -0,0 +1,84 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+// At the narrowest layout, however long the host and log path, the
+// header and footer each keep to their border rather than wrap.
+func TestHeaderFooterElision(t *testing.T) {
+	long := func(part string, n int) string { return strings.Repeat(part, n/len(part)) }
+	rule, err := parseAlertRule("cpu>90")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		host     string
+		logPath  string
+		firing   bool
+		wantHost string // in the header
+		wantFoot []string
+	}{
+		{"short", "box", "captures/node1-143207.infgo", false,
+			"box", []string{"REC", "captures/node1-143207.infgo", "500ms"}},
+		{"dotted host", long("node1.lab.example.", 198) + "com", "", false,
+			"node1.lab.example", []string{"500ms"}},
+		{"host of one label", long("x", 200), "", false, "xxxxxxx…", nil},
+		{"deep path", "box", long("deep/", 285) + "node1-143207.infgo", false,
+			"box", []string{"REC", "…", "node1-143207.infgo"}},
+		{"path and alert", long("node1.", 200), long("deep/", 285) + "node1-143207.infgo", true,
+			"node1", []string{"cpu>90", "REC", "node1-143207.infgo"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &recorder{logPath: tt.logPath}
+			if tt.firing {
+				rec.alerts = newAlertMonitor([]alertRule{rule}, alertHold)
+				rec.alerts.tracks[0].active = true
+			}
+			m := update(sizedModel(ui.MinTermWidth, ui.MinTermHeight, ui.WithRecorder(rec)),
+				ui.SysInfo{Hostname: tt.host, Platform: "linux", NumCores: 4})
+			iw := ui.MinTermWidth - 4
+			// The frame's lines, without the margin View adds.
+			lines := strings.Split(m.View(), "\n")
+			for i, l := range lines {
+				lines[i] = strings.TrimRight(strings.TrimPrefix(l, " "), " ")
+			}
+
+			header := strings.Join(lines[:3], "\n")
+			if h := lipgloss.Height(header); h != 3 {
+				t.Errorf("header is %d lines, want 3:\n%s", h, header)
+			}
+			if w := lipgloss.Width(header); w != iw+6 {
+				t.Errorf("header is %d wide, want %d", w, iw+6)
+			}
+			if got := ansi.Strip(header); !strings.Contains(got, tt.wantHost+"  ● LIVE") {
+				t.Errorf("header: got\n%s\nwant host %q", got, tt.wantHost)
+			}
+
+			footer := strings.Join(lines[len(lines)-2:], "\n")
+			if h := lipgloss.Height(footer); h != 2 {
+				t.Errorf("footer is %d lines, want 2:\n%s", h, footer)
+			}
+			if w := lipgloss.Width(footer); w != iw+4 {
+				t.Errorf("footer is %d wide, want %d", w, iw+4)
+			}
+			got := ansi.Strip(footer)
+			for _, want := range append(tt.wantFoot, "ctrl+c  quit") {
+				if !strings.Contains(got, want) {
+					t.Errorf("footer: got\n%s\nwant %q in it", got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ALH477/infgo/analysis"
+)
+
+// ── Email alerts (-alert-email) ───────────────────────────────────────────────
+
+// smtpTimeout bounds a whole delivery: connecting, STARTTLS, AUTH and DATA.
+const smtpTimeout = 30 * time.Second
+
+// smtpConfig holds the -alert-email and -smtp-* flags.
+type smtpConfig struct {
+	to   []string
+	host string // host[:port]; the port defaults to 587 (submission)
+	user string
+	pass string
+	from string
+}
+
+// smtpSender mails alerts to every -alert-email recipient in one message.
+type smtpSender struct {
+	addr     string
+	server   string // addr without the port, for TLS and PLAIN auth
+	user     string
+	pass     string
+	from     string
+	to       []string
+	timeout  time.Duration
+	cooldown time.Duration // for the flapping note
+	tlsConf  *tls.Config
+	hostname string // for Message-ID
+}
+
+func newSMTPSender(cfg smtpConfig, cooldown time.Duration) (*smtpSender, error) {
+	if cfg.host == "" {
+		return nil, errors.New("-alert-email needs -smtp-host")
+	}
+	addr := cfg.host
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, "587")
+	}
+	server, _, _ := net.SplitHostPort(addr)
+	for _, to := range cfg.to {
+		if _, err := mail.ParseAddress(to); err != nil {
+			return nil, fmt.Errorf("-alert-email: %q: %v", to, err)
+		}
+	}
+	hostname, _ := os.Hostname()
+	from := cfg.from
+	if from == "" {
+		from = cfg.user
+		if !strings.Contains(from, "@") {
+			from = "infgo@" + hostname
+		}
+	}
+	if _, err := mail.ParseAddress(from); err != nil {
+		return nil, fmt.Errorf("-smtp-from: %q: %v", from, err)
+	}
+	return &smtpSender{
+		addr:     addr,
+		server:   server,
+		user:     cfg.user,
+		pass:     cfg.pass,
+		from:     from,
+		to:       cfg.to,
+		timeout:  smtpTimeout,
+		cooldown: cooldown,
+		tlsConf:  &tls.Config{ServerName: server},
+		hostname: hostname,
+	}, nil
+}
+
+// send delivers one message.  Connection failures and 4xx replies are
+// temporary; a rejected login or recipient is not.
+func (s *smtpSender) send(msg []byte) (retry bool, err error) {
+	err = s.transact(msg)
+	if err == nil {
+		return false, nil
+	}
+	var tpErr *textproto.Error
+	if errors.As(err, &tpErr) {
+		retry = tpErr.Code/100 == 4
+	} else {
+		var netErr net.Error
+		retry = errors.As(err, &netErr)
+	}
+	return retry, fmt.Errorf("smtp %s: %w", s.addr, err)
+}
+
+func (s *smtpSender) transact(msg []byte) error {
+	conn, err := net.DialTimeout("tcp", s.addr, s.timeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(s.timeout))
+
+	c, err := smtp.NewClient(conn, s.server)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(s.tlsConf); err != nil {
+			return err
+		}
+	} else if s.user != "" {
+		return errors.New("server does not offer STARTTLS; refusing to send the password in the clear")
+	}
+	if s.user != "" {
+		if err := c.Auth(smtp.PlainAuth("", s.user, s.pass, s.server)); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(s.from); err != nil {
+		return err
+	}
+	for _, to := range s.to {
+		if err := c.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// encode renders ev as a plain-text message, headers included.
+func (s *smtpSender) encode(ev alertEvent) ([]byte, error) {
+	v := newAlertView(ev, s.cooldown)
+	var b bytes.Buffer
+	header := func(k, val string) { fmt.Fprintf(&b, "%s: %s\r\n", k, val) }
+	header("From", s.from)
+	header("To", strings.Join(s.to, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", "[infgo] "+v.title))
+	header("Date", v.at.Format(time.RFC1123Z))
+	header("Message-ID", fmt.Sprintf("<infgo.%d.%s@%s>", v.at.UnixNano(), ev.State, s.hostname))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=utf-8")
+	header("Content-Transfer-Encoding", "8bit")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(alertEmailBody(ev, v), "\n", "\r\n"))
+	return b.Bytes(), nil
+}
+
+// alertEmailBody is the text of an alert email: a summary, then the last
+// samples as a table.
+func alertEmailBody(ev alertEvent, v alertView) string {
+	m, _ := analysis.LookupMetric(ev.Metric)
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n\n", v.title)
+	row := func(k, val string) { fmt.Fprintf(&b, "%-10s %s\n", k+":", val) }
+	if ev.Hostname != "" {
+		row("Host", ev.Hostname)
+	}
+	row("Metric", fmt.Sprintf("%s (%s)", m.Label, ev.Metric))
+	row("Threshold", v.threshold)
+	row("Value", v.value)
+	row("Peak", fmt.Sprintf("%.4g%s", ev.Peak, m.Unit))
+	row("Started", ev.StartedAt.UTC().Format("2006-01-02 15:04:05 MST"))
+	if ev.ClearedAt != nil {
+		row("Cleared", ev.ClearedAt.UTC().Format("2006-01-02 15:04:05 MST"))
+		row("Duration", time.Duration(ev.DurationS*float64(time.Second)).Round(time.Second).String())
+	}
+	if ev.State == alertFlapping || ev.Suppressed > 0 {
+		fmt.Fprintf(&b, "\n%s\n", v.note)
+	}
+	if len(ev.Samples) > 0 {
+		fmt.Fprintf(&b, "\nLast %d samples:\n\n", len(ev.Samples))
+		fmt.Fprintf(&b, "%-8s  %6s  %6s  %7s\n", "Time", "CPU %", "Mem %", "Load 1m")
+		for i := range ev.Samples {
+			s := &ev.Samples[i]
+			fmt.Fprintf(&b, "%-8s  %6.1f  %6.1f  %7.2f\n", s.Time().Format("15:04:05"), s.CpuTotal, s.MemPercent, s.Load1)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,284 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/base64"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// smtpStub is a submission server that speaks just enough ESMTP for
+// net/smtp: EHLO, STARTTLS, AUTH PLAIN, MAIL, RCPT, DATA and QUIT.
+type smtpStub struct {
+	ln       net.Listener
+	tlsConf  *tls.Config // nil: no STARTTLS offered
+	user     string
+	pass     string
+	rcptCode int // reply to RCPT TO; 250 when zero
+
+	mu    sync.Mutex
+	mails []string
+	rcpts []string
+	auths int
+}
+
+// startSMTPStub borrows httptest's certificate for STARTTLS and returns a
+// client TLS config that trusts it.
+func startSMTPStub(t *testing.T, starttls bool) (*smtpStub, *tls.Config) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &smtpStub{ln: ln, user: "ops", pass: "hunter2"}
+	var client *tls.Config
+	if starttls {
+		ts := httptest.NewTLSServer(nil)
+		t.Cleanup(ts.Close)
+		s.tlsConf = &tls.Config{Certificates: ts.TLS.Certificates}
+		client = ts.Client().Transport.(*http.Transport).TLSClientConfig.Clone()
+		client.ServerName = "127.0.0.1"
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s, client
+}
+
+func (s *smtpStub) serve(conn net.Conn) {
+	defer conn.Close()
+	tp := textproto.NewConn(conn)
+	tp.PrintfLine("220 stub ESMTP")
+	secure := false
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO":
+			if s.tlsConf != nil && !secure {
+				tp.PrintfLine("250-stub\r\n250-STARTTLS\r\n250 AUTH PLAIN")
+			} else {
+				tp.PrintfLine("250-stub\r\n250 AUTH PLAIN")
+			}
+		case "STARTTLS":
+			tp.PrintfLine("220 go ahead")
+			tc := tls.Server(conn, s.tlsConf)
+			if tc.Handshake() != nil {
+				return
+			}
+			conn, tp, secure = tc, textproto.NewConn(tc), true
+		case "AUTH":
+			s.mu.Lock()
+			s.auths++
+			s.mu.Unlock()
+			_, cred, _ := strings.Cut(arg, " ")
+			raw, _ := base64.StdEncoding.DecodeString(cred)
+			if string(raw) == "\x00"+s.user+"\x00"+s.pass {
+				tp.PrintfLine("235 ok")
+			} else {
+				tp.PrintfLine("535 5.7.8 authentication failed")
+			}
+		case "MAIL":
+			tp.PrintfLine("250 ok")
+		case "RCPT":
+			if s.rcptCode != 0 {
+				tp.PrintfLine("%d no", s.rcptCode)
+				continue
+			}
+			s.mu.Lock()
+			s.rcpts = append(s.rcpts, arg)
+			s.mu.Unlock()
+			tp.PrintfLine("250 ok")
+		case "DATA":
+			tp.PrintfLine("354 go ahead")
+			body, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.mails = append(s.mails, string(body))
+			s.mu.Unlock()
+			tp.PrintfLine("250 queued")
+		case "QUIT":
+			tp.PrintfLine("221 bye")
+			return
+		default:
+			tp.PrintfLine("502 unimplemented")
+		}
+	}
+}
+
+func (s *smtpStub) sent() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.mails...)
+}
+
+func testSMTPSender(t *testing.T, s *smtpStub, client *tls.Config, user, pass string) *smtpSender {
+	t.Helper()
+	sender, err := newSMTPSender(smtpConfig{
+		to: []string{"oncall@example.com", "Ops <ops@example.com>"}, host: s.ln.Addr().String(),
+		user: user, pass: pass, from: "infgo@example.com",
+	}, alertCooldown)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client != nil {
+		sender.tlsConf = client
+	}
+	sender.timeout = 2 * time.Second
+	return sender
+}
+
+func TestSMTPSend(t *testing.T) {
+	stub, client := startSMTPStub(t, true)
+	sender := testSMTPSender(t, stub, client, "ops", "hunter2")
+
+	msg, err := sender.encode(testAlert())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if retry, err := sender.send(msg); err != nil {
+		t.Fatalf("send: %v (retry %v)", err, retry)
+	}
+	mails := stub.sent()
+	if len(mails) != 1 || len(stub.rcpts) != 2 {
+		t.Fatalf("got %d mails to %v, want one to two recipients", len(mails), stub.rcpts)
+	}
+	for _, want := range []string{"Subject: [infgo] cpu>90 started on node7", "Peak:", "Threshold: > 90%"} {
+		if !strings.Contains(mails[0], want) {
+			t.Errorf("mail lacks %q:\n%s", want, mails[0])
+		}
+	}
+
+	// A wrong password is permanent: retrying would only lock the account.
+	sender = testSMTPSender(t, stub, client, "ops", "wrong")
+	if retry, err := sender.send(msg); err == nil || retry || !strings.Contains(err.Error(), "535") {
+		t.Errorf("bad password: got retry %v, err %v; want a permanent 535", retry, err)
+	}
+	// A temporary rejection is worth another try.
+	stub.rcptCode = 451
+	sender = testSMTPSender(t, stub, client, "ops", "hunter2")
+	if retry, err := sender.send(msg); err == nil || !retry {
+		t.Errorf("451: got retry %v, err %v; want a retry", retry, err)
+	}
+}
+
+func TestSMTPRefusesCleartextLogin(t *testing.T) {
+	stub, _ := startSMTPStub(t, false)
+	sender := testSMTPSender(t, stub, nil, "ops", "hunter2")
+	msg, _ := sender.encode(testAlert())
+	if _, err := sender.send(msg); err == nil || !strings.Contains(err.Error(), "STARTTLS") {
+		t.Errorf("got %v, want a refusal to log in without STARTTLS", err)
+	}
+	if stub.auths != 0 {
+		t.Error("credentials were sent in the clear")
+	}
+
+	// Without a login, an internal relay needs no TLS.
+	sender = testSMTPSender(t, stub, nil, "", "")
+	if _, err := sender.send(msg); err != nil {
+		t.Errorf("unauthenticated relay: %v", err)
+	}
+}
+
+func TestSMTPTimeout(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		// Accept and never greet.
+		conn, err := ln.Accept()
+		if err == nil {
+			bufio.NewReader(conn).ReadByte()
+			conn.Close()
+		}
+	}()
+	sender, _ := newSMTPSender(smtpConfig{to: []string{"oncall@example.com"}, host: ln.Addr().String()}, alertCooldown)
+	sender.timeout = 50 * time.Millisecond
+	start := time.Now()
+	retry, err := sender.send([]byte("x"))
+	if err == nil || !retry || time.Since(start) > time.Second {
+		t.Errorf("got retry %v, err %v after %s; want a retryable timeout", retry, err, time.Since(start))
+	}
+}
+
+func TestAlertEmailBody(t *testing.T) {
+	ev := testAlert()
+	at := ev.StartedAt.Add(90 * time.Second)
+	ev.State, ev.ClearedAt, ev.DurationS, ev.Value, ev.Peak = alertCleared, &at, 90, 42, 99.5
+	for i := 0; i < 2; i++ {
+		ev.Samples = append(ev.Samples, metrics.Sample{
+			TimestampUnixMs: at.UnixMilli() + int64(i)*500, CpuTotal: 42, MemPercent: 50.25, Load1: 1.5,
+		})
+	}
+	got := alertEmailBody(ev, newAlertView(ev, alertCooldown))
+	want := `cpu>90 cleared on node7
+
+Host:      node7
+Metric:    CPU % (cpu)
+Threshold: > 90%
+Value:     42%
+Peak:      99.5%
+Started:   2024-01-01 00:00:00 UTC
+Cleared:   2024-01-01 00:01:30 UTC
+Duration:  1m30s
+
+Last 2 samples:
+
+Time       CPU %   Mem %  Load 1m
+00:01:30    42.0    50.2     1.50
+00:01:30    42.0    50.2     1.50
+`
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestNewSMTPSender(t *testing.T) {
+	tests := []struct {
+		cfg      smtpConfig
+		wantAddr string
+		wantFrom string
+		wantErr  bool
+	}{
+		{smtpConfig{to: []string{"a@example.com"}, host: "mail.example.com", user: "bot@example.com"}, "mail.example.com:587", "bot@example.com", false},
+		{smtpConfig{to: []string{"a@example.com"}, host: "mail.example.com:25", from: "x@example.com"}, "mail.example.com:25", "x@example.com", false},
+		{smtpConfig{to: []string{"a@example.com"}}, "", "", true},
+		{smtpConfig{to: []string{"not an address"}, host: "mail.example.com"}, "", "", true},
+	}
+	for _, tt := range tests {
+		s, err := newSMTPSender(tt.cfg, alertCooldown)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%+v: got err %v, wantErr %v", tt.cfg, err, tt.wantErr)
+			continue
+		}
+		if err == nil && (s.addr != tt.wantAddr || s.from != tt.wantFrom) {
+			t.Errorf("%+v: got %s from %s, want %s from %s", tt.cfg, s.addr, s.from, tt.wantAddr, tt.wantFrom)
+		}
+	}
+}
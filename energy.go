@@ -0,0 +1,121 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ── Energy ────────────────────────────────────────────────────────────────────
+
+// The SYSTEM panel's Energy row shows what the session has cost: the CPU
+// time it used, in core-seconds, and on Linux machines with Intel or AMD
+// RAPL counters the energy the CPU packages drew and their power now.
+
+// raplRoot is where Linux exposes the RAPL energy counters.
+const raplRoot = "/sys/class/powercap"
+
+// raplZone is the energy counter of one CPU package.
+type raplZone struct {
+	dir  string
+	wrap uint64 // energy_uj wraps to 0 after this
+	last uint64
+}
+
+// raplReader turns the packages' energy counters into joules and watts.
+// It is read from the statsReader, under its lock.
+type raplReader struct {
+	zones  []raplZone
+	joules float64 // since the reader was opened
+	last   time.Time
+}
+
+// errNoRAPL is returned by openRAPL on machines without the counters.
+var errNoRAPL = errors.New("no RAPL energy counters")
+
+// openRAPL finds the package zones under root (intel-rapl:0, intel-rapl:1,
+// …; the sub-zones intel-rapl:0:0 are parts of a package and would count
+// twice) and takes a first reading.  Most distributions let only root
+// read energy_uj; that error wraps fs.ErrPermission.
+func openRAPL(root string) (*raplReader, error) {
+	dirs, _ := filepath.Glob(filepath.Join(root, "intel-rapl:*"))
+	sort.Strings(dirs)
+	r := &raplReader{}
+	for _, dir := range dirs {
+		if strings.Count(filepath.Base(dir), ":") != 1 {
+			continue
+		}
+		wrap, err := readUint(filepath.Join(dir, "max_energy_range_uj"))
+		if err != nil {
+			return nil, err
+		}
+		e, err := readUint(filepath.Join(dir, "energy_uj"))
+		if err != nil {
+			return nil, err
+		}
+		r.zones = append(r.zones, raplZone{dir: dir, wrap: wrap, last: e})
+	}
+	if len(r.zones) == 0 {
+		return nil, errNoRAPL
+	}
+	r.last = time.Now()
+	return r, nil
+}
+
+// read returns the power drawn since the previous read and adds the energy
+// to the total.  A counter that has wrapped is read as having counted on
+// past max_energy_range_uj.
+func (r *raplReader) read(now time.Time) (watts float64, ok bool) {
+	var uj uint64
+	for i := range r.zones {
+		z := &r.zones[i]
+		e, err := readUint(filepath.Join(z.dir, "energy_uj"))
+		if err != nil {
+			return 0, false
+		}
+		uj += wrapDelta(z.last, e, z.wrap)
+		z.last = e
+	}
+	dt := now.Sub(r.last).Seconds()
+	r.last = now
+	j := float64(uj) / 1e6
+	r.joules += j
+	if dt <= 0 {
+		return 0, false
+	}
+	return j / dt, true
+}
+
+// wrapDelta is the count from was to now of a counter that wraps to 0
+// after wrap.
+func wrapDelta(was, now, wrap uint64) uint64 {
+	if now >= was {
+		return now - was
+	}
+	return wrap - was + now
+}
+
+func readUint(path string) (uint64, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(strings.TrimSpace(string(b)), 10, 64)
+}
+
+// raplNote explains why an opened RAPL reader is missing, for the Energy
+// row; "" where there is nothing to read.
+func raplNote(err error) string {
+	if errors.Is(err, fs.ErrPermission) {
+		return "RAPL needs root"
+	}
+	return ""
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWrapDelta(t *testing.T) {
+	tests := []struct{ was, now, wrap, want uint64 }{
+		{100, 250, 1000, 150},
+		{900, 100, 1000, 200},
+		{0, 0, 1000, 0},
+		{262143328850, 5000000, 262143328850, 5000000},
+	}
+	for _, tt := range tests {
+		if got := wrapDelta(tt.was, tt.now, tt.wrap); got != tt.want {
+			t.Errorf("wrapDelta(%d, %d, %d): got %d, want %d", tt.was, tt.now, tt.wrap, got, tt.want)
+		}
+	}
+}
+
+// raplSysfs lays out packages under a powercap root: energy_uj and
+// max_energy_range_uj by zone name.
+func raplSysfs(t *testing.T, zones map[string][2]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, v := range zones {
+		dir := filepath.Join(root, name)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		setEnergy(t, dir, v[0])
+		if err := os.WriteFile(filepath.Join(dir, "max_energy_range_uj"), []byte(v[1]+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func setEnergy(t *testing.T, dir, uj string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, "energy_uj"), []byte(uj+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRAPL(t *testing.T) {
+	root := raplSysfs(t, map[string][2]string{
+		"intel-rapl:0":   {"1000000", "100000000"},
+		"intel-rapl:1":   {"9500000", "10000000"},
+		"intel-rapl:0:0": {"500000", "100000000"}, // a core sub-zone: counted in :0 already
+	})
+	r, err := openRAPL(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.zones) != 2 {
+		t.Fatalf("got %d zones, want the 2 packages", len(r.zones))
+	}
+	t0 := time.Unix(1_700_000_000, 0)
+	r.last = t0
+	pkg0, pkg1 := filepath.Join(root, "intel-rapl:0"), filepath.Join(root, "intel-rapl:1")
+
+	// Two seconds on, package 0 has used 20 J and package 1 1 J, wrapping
+	// past its 10 J range on the way.
+	setEnergy(t, pkg0, "21000000")
+	setEnergy(t, pkg1, "500000")
+	watts, ok := r.read(t0.Add(2 * time.Second))
+	if !ok || watts != 10.5 || r.joules != 21 {
+		t.Fatalf("got %v W (%v), %v J; want 10.5 W, 21 J", watts, ok, r.joules)
+	}
+
+	setEnergy(t, pkg1, "2500000")
+	watts, ok = r.read(t0.Add(3 * time.Second))
+	if !ok || watts != 2 || r.joules != 23 {
+		t.Errorf("a second on: got %v W (%v), %v J; want 2 W, 23 J", watts, ok, r.joules)
+	}
+
+	if _, err := openRAPL(t.TempDir()); !errors.Is(err, errNoRAPL) {
+		t.Errorf("no counters: got %v, want errNoRAPL", err)
+	}
+	denied := &fs.PathError{Op: "open", Path: "energy_uj", Err: fs.ErrPermission}
+	if got := raplNote(denied); got == "" {
+		t.Error("permission denied: got no note")
+	}
+	if got := raplNote(errNoRAPL); got != "" {
+		t.Errorf("no counters: got note %q", got)
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+// Command embed is a deployment dashboard with infgo's CPU and memory
+// panels as a pane beside its own list.  It shows how to embed a ui.Model:
+// hand it the messages, give it its share of the width, and watch its
+// readings go by as ui.SampleMsg.
+//
+//	go run ./examples/embed
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+// listW is the width of the deployment list; the panels get the rest.
+const listW = 34
+
+type dashboard struct {
+	deploys []string
+	monitor tea.Model // a ui.Model
+	busy    int       // readings above ui.WarnPct so far
+}
+
+func (d dashboard) Init() tea.Cmd { return d.monitor.Init() }
+
+func (d dashboard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.String() == "q" || msg.String() == "ctrl+c" {
+			return d, tea.Quit
+		}
+	case tea.WindowSizeMsg:
+		// The pane is what the list leaves.
+		msg.Width -= listW
+		d.monitor, _ = d.monitor.Update(msg)
+		return d, nil
+	case ui.SampleMsg:
+		// Every reading passes through here on its way to the panels.
+		if msg.Sample.CpuTotal >= ui.WarnPct {
+			d.busy++
+		}
+	}
+	var cmd tea.Cmd
+	d.monitor, cmd = d.monitor.Update(msg)
+	return d, cmd
+}
+
+func (d dashboard) View() string {
+	dim := lipgloss.NewStyle().Foreground(ui.DefaultTheme.Dim)
+	lines := []string{lipgloss.NewStyle().Bold(true).Foreground(ui.DefaultTheme.Accent).Render("DEPLOYMENTS"), ""}
+	for _, name := range d.deploys {
+		lines = append(lines, "● "+name)
+	}
+	lines = append(lines, "", dim.Render(fmt.Sprintf("%d busy readings", d.busy)), dim.Render("q to quit"))
+	list := lipgloss.NewStyle().Width(listW).Padding(1, 2).Render(strings.Join(lines, "\n"))
+	return lipgloss.JoinHorizontal(lipgloss.Top, list, d.monitor.View())
+}
+
+func main() {
+	d := dashboard{
+		deploys: []string{"api-7f9c  production", "api-7f9b  canary", "worker-21  production"},
+		monitor: ui.New(ui.WithPanels(ui.PanelCPU, ui.PanelMemory)),
+	}
+	if _, err := tea.NewProgram(d, tea.WithAltScreen()).Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "embed: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,314 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/ALH477/infgo/analysis"
+	"github.com/ALH477/infgo/metrics"
+)
+
+// ── export ────────────────────────────────────────────────────────────────────
+
+// exporters lists the foreign formats `infgo export <format>` produces.
+var exporters = []subcommand{
+	{"pbstream", "write a length-delimited protobuf stream", runExportPbstream},
+	{"csv", "write the samples as a CSV table, as import csv reads it", runExportCSV},
+	{"jsonl", "write the samples as JSON Lines", runExportJSONL},
+}
+
+// runExport implements `infgo export <format> [args]`.
+func runExport(args []string) error {
+	return runFormat("export", exporters, args)
+}
+
+// ── export pbstream ───────────────────────────────────────────────────────────
+
+// runExportPbstream implements
+// `infgo export pbstream <capture.infgo> -o <samples.pb> [-with-header | -header FILE]`.
+func runExportPbstream(args []string) error {
+	fs := newFlagSet("export pbstream", "<capture.infgo|-> -o <samples.pb|-> [-with-header] [-header <header.pb>]")
+	out := fs.String("o", "", "write the stream to `file` (- for stdout)")
+	withHeader := fs.Bool("with-header", false, "write Record envelopes carrying the header, samples, events and process records instead of bare Samples")
+	headerOut := fs.String("header", "", "also write the Header as a single message to `file`")
+
+	pos, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 1 {
+		return usageErrorf(fs, "expected exactly one input capture")
+	}
+	if *out == "" {
+		return usageErrorf(fs, "-o is required")
+	}
+	if *out != stdinPath && samePath(pos[0], *out) {
+		return fmt.Errorf("output %q would overwrite the input", *out)
+	}
+
+	rd, err := openCapture(pos[0])
+	if err != nil {
+		return err
+	}
+	defer rd.Close()
+
+	dst := io.Writer(os.Stdout)
+	var f *os.File
+	if *out != stdinPath {
+		if f, err = os.Create(*out); err != nil {
+			return err
+		}
+		defer f.Close()
+		dst = f
+	}
+	bw := bufio.NewWriterSize(dst, 64*1024)
+
+	var st pbstreamStats
+	for {
+		rec, err := rd.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		var env metrics.Envelope
+		switch {
+		case rec.Header != nil:
+			st.headers++
+			if st.headers == 1 && *headerOut != "" {
+				if err := os.WriteFile(*headerOut, rec.Header.Marshal(), 0o644); err != nil {
+					return err
+				}
+			}
+			env.Header = rec.Header
+		case rec.Sample != nil:
+			st.samples++
+			env.Sample = rec.Sample
+		case rec.Event != nil:
+			st.events++
+			env.Event = rec.Event
+		case rec.Process != nil:
+			st.processes++
+			env.Process = rec.Process
+		default:
+			continue
+		}
+
+		var msg []byte
+		switch {
+		case *withHeader:
+			msg = env.Marshal()
+		case env.Sample != nil:
+			msg = env.Sample.Marshal()
+		default:
+			continue // bare Sample streams cannot carry headers, events or processes
+		}
+		if err := metrics.WriteDelimited(bw, msg); err != nil {
+			return err
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	if f != nil {
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+
+	if *out != stdinPath {
+		msg := fmt.Sprintf("infgo: %d samples → %s", st.samples, *out)
+		if !*withHeader && st.events+st.processes > 0 {
+			msg += fmt.Sprintf(" (%d events and %d process records omitted; use -with-header to keep them)", st.events, st.processes)
+		}
+		fmt.Println(msg)
+	}
+	return nil
+}
+
+// pbstreamStats counts the records converted by the pbstream tools.
+type pbstreamStats struct {
+	headers, samples, events, processes int
+}
+
+// ── export csv, export jsonl ──────────────────────────────────────────────────
+
+// localTimeLayout is the optional time column of the CSV and JSON Lines
+// exports: the instant in the -tz zone, with its offset so that the hour
+// repeated when daylight saving ends stays unambiguous.
+const localTimeLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// runExportCSV implements `infgo export csv <capture.infgo> -o <samples.csv> [-tz ZONE]`.
+func runExportCSV(args []string) error {
+	return runExportTable("csv", args, writeSamplesCSV)
+}
+
+// runExportJSONL implements `infgo export jsonl <capture.infgo> -o <samples.jsonl> [-tz ZONE]`.
+func runExportJSONL(args []string) error {
+	return runExportTable("jsonl", args, writeSamplesJSONL)
+}
+
+// runExportTable is the command line shared by the sample exports: it
+// loads the capture and has write put its samples in the output, with
+// times in the -tz zone if one was given.
+func runExportTable(format string, args []string, write func(w io.Writer, samples []metrics.Sample, zone *timeZone) error) error {
+	fs := newFlagSet("export "+format, fmt.Sprintf("<capture.infgo|-> -o <samples.%s|-> [-tz ZONE]", format))
+	out := fs.String("o", "", "write the samples to `file` (- for stdout)")
+	tz := addTZFlag(fs)
+
+	pos, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 1 {
+		return usageErrorf(fs, "expected exactly one input capture")
+	}
+	if *out == "" {
+		return usageErrorf(fs, "-o is required")
+	}
+	if *out != stdinPath && samePath(pos[0], *out) {
+		return fmt.Errorf("output %q would overwrite the input", *out)
+	}
+	capture, err := loadCapture(pos[0])
+	if err != nil {
+		return err
+	}
+	// The machine timestamps are always written; a localized column only
+	// when asked for.
+	var zone *timeZone
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "tz" {
+			z := resolveZone(*tz, capture.Header)
+			zone = &z
+		}
+	})
+
+	dst := io.Writer(os.Stdout)
+	var f *os.File
+	if *out != stdinPath {
+		if f, err = os.Create(*out); err != nil {
+			return err
+		}
+		defer f.Close()
+		dst = f
+	}
+	bw := bufio.NewWriterSize(dst, 64*1024)
+	if err := write(bw, capture.Samples, zone); err != nil {
+		return err
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	if f != nil {
+		if err := f.Close(); err != nil {
+			return err
+		}
+		fmt.Printf("infgo: %d samples → %s\n", len(capture.Samples), *out)
+	}
+	return nil
+}
+
+// writeSamplesCSV writes samples under the column names import csv reads
+// by default: the timestamp in Unix milliseconds, then a time column in
+// zone if it is not nil, the fields of csvFields and one core_N column
+// for each core of the widest sample, then disk_used_percent@MOUNT,
+// disk_used_gb@MOUNT and disk_total_gb@MOUNT for each mount recorded.
+// Fields the collector could not read are left empty.
+func writeSamplesCSV(w io.Writer, samples []metrics.Sample, zone *timeZone) error {
+	return writeCSVColumns(w, samples, zone, csvFields)
+}
+
+// writeCSVColumns is writeSamplesCSV with only the columns of fields.
+func writeCSVColumns(w io.Writer, samples []metrics.Sample, zone *timeZone, fields []csvField) error {
+	cores := 0
+	for i := range samples {
+		cores = max(cores, len(samples[i].CpuCores))
+	}
+	head := []string{"timestamp"}
+	if zone != nil {
+		head = append(head, "time")
+	}
+	for _, f := range fields {
+		head = append(head, f.column)
+	}
+	for i := range cores {
+		head = append(head, "core_"+strconv.Itoa(i))
+	}
+	mounts := analysis.Mounts(samples)
+	for _, m := range mounts {
+		head = append(head, "disk_used_percent@"+m, "disk_used_gb@"+m, "disk_total_gb@"+m)
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(head); err != nil {
+		return err
+	}
+	num := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	row := make([]string, 0, len(head))
+	for i := range samples {
+		s := &samples[i]
+		row = append(row[:0], strconv.FormatInt(s.TimestampUnixMs, 10))
+		if zone != nil {
+			row = append(row, s.Time().In(zone.loc).Format(localTimeLayout))
+		}
+		for _, f := range fields {
+			cell := ""
+			if !s.Missing.Has(f.group) {
+				cell = num(f.get(s))
+			}
+			row = append(row, cell)
+		}
+		for c := range cores {
+			cell := ""
+			if c < len(s.CpuCores) && !s.Missing.Has(metrics.MissingCPU) {
+				cell = num(s.CpuCores[c])
+			}
+			row = append(row, cell)
+		}
+		for _, m := range mounts {
+			if d, ok := s.Disk(m); ok {
+				row = append(row, num(d.UsedPercent), num(d.UsedGB), num(d.TotalGB))
+			} else {
+				row = append(row, "", "", "")
+			}
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// sampleJSON is a line of export jsonl: the Sample as it marshals, and
+// the time in the -tz zone when one was given.
+type sampleJSON struct {
+	metrics.Sample
+	Time string `json:"time,omitempty"`
+}
+
+// writeSamplesJSONL writes samples one JSON object to a line, each with a
+// time in zone if it is not nil.
+func writeSamplesJSONL(w io.Writer, samples []metrics.Sample, zone *timeZone) error {
+	enc := json.NewEncoder(w)
+	for i := range samples {
+		line := sampleJSON{Sample: samples[i]}
+		if zone != nil {
+			line.Time = samples[i].Time().In(zone.loc).Format(localTimeLayout)
+		}
+		if err := enc.Encode(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestPbstreamRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	src := writeTestCapture(t, dir, 1704067200000, 10)
+	orig, err := loadCapture(src)
+	if err != nil {
+		t.Fatalf("loadCapture failed: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		exportArgs []string
+		importArgs []string
+	}{
+		{"envelopes", []string{"-with-header"}, []string{"-with-header"}},
+		{"separate header", []string{"-header", filepath.Join(dir, "hdr.pb")}, []string{"-header", filepath.Join(dir, "hdr.pb")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pb := filepath.Join(dir, tt.name+".pb")
+			back := filepath.Join(dir, tt.name+".infgo")
+			if err := runExportPbstream(append([]string{src, "-o", pb}, tt.exportArgs...)); err != nil {
+				t.Fatalf("export failed: %v", err)
+			}
+			if err := runImportPbstream(append([]string{pb, "-o", back}, tt.importArgs...)); err != nil {
+				t.Fatalf("import failed: %v", err)
+			}
+			got, err := loadCapture(back)
+			if err != nil {
+				t.Fatalf("loadCapture failed: %v", err)
+			}
+			if got.Header == nil || !reflect.DeepEqual(*got.Header, *orig.Header) {
+				t.Errorf("header: got %+v, want %+v", got.Header, orig.Header)
+			}
+			if len(got.Samples) != len(orig.Samples) {
+				t.Fatalf("samples: got %d, want %d", len(got.Samples), len(orig.Samples))
+			}
+			for i := range got.Samples {
+				if got.Samples[i].TimestampUnixMs != orig.Samples[i].TimestampUnixMs || got.Samples[i].CpuTotal != orig.Samples[i].CpuTotal {
+					t.Errorf("sample %d: got %+v, want %+v", i, got.Samples[i], orig.Samples[i])
+				}
+			}
+		})
+	}
+}
+
+// Envelopes carry process records (-log-pids) through export and import.
+func TestPbstreamProcesses(t *testing.T) {
+	dir := t.TempDir()
+	src := writeProcessCapture(t, dir, 1704067200000, 5)
+	pb, back := filepath.Join(dir, "out.pb"), filepath.Join(dir, "back.infgo")
+	if err := runExportPbstream([]string{src, "-o", pb, "-with-header"}); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	if err := runImportPbstream([]string{pb, "-o", back, "-with-header"}); err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+	if got, want := readProcesses(t, back), readProcesses(t, src); !reflect.DeepEqual(got, want) {
+		t.Errorf("processes: got %+v, want %+v", got, want)
+	}
+}
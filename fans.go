@@ -0,0 +1,173 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+// ── Fans ──────────────────────────────────────────────────────────────────────
+//
+// The SYSTEM panel's Fans row: the speed of each fan, read every
+// fanInterval in a command of its own.  On Linux they are the fanN_input
+// files the hwmon drivers keep under /sys/class/hwmon (fans_linux.go).  On
+// macOS they are in the SMC, which takes IOKit, and so cgo, that infgo is
+// built without; there they are read by running smc -f, the SMC tool of
+// smcFanControl, where it is installed (fans_darwin.go).  A fan at 0 RPM
+// is red while a temperature is past tempWarnC, since a stopped fan in a
+// hot machine is how a box cooks itself quietly.  A machine without fan
+// sensors, or a Mac without smc, has no row.
+
+// fanInterval is how often the fans are read; they change slowly.
+const fanInterval = 5 * time.Second
+
+// readHwmonFans reads every fan under fsys, a /sys/class/hwmon, in the
+// order of their chips and numbers.  A fan whose input cannot be read, as
+// one unplugged from a header that reports it, is left out.
+func readHwmonFans(fsys fs.FS) ([]ui.FanReading, error) {
+	inputs, err := fs.Glob(fsys, "hwmon*/fan*_input")
+	if err != nil {
+		return nil, err
+	}
+	var fans []ui.FanReading
+	for _, in := range inputs {
+		dir, file := path.Split(in)
+		n := strings.TrimSuffix(strings.TrimPrefix(file, "fan"), "_input")
+		num, err := strconv.Atoi(n)
+		if err != nil {
+			continue
+		}
+		rpm, err := readInt(fsys, in)
+		if err != nil {
+			continue
+		}
+		f := ui.FanReading{Chip: readTrimmed(fsys, dir+"name"), Num: num, Label: readTrimmed(fsys, dir+"fan"+n+"_label"), RPM: rpm}
+		if f.Label == "" {
+			f.Label = "fan" + n
+		}
+		fans = append(fans, f)
+	}
+	slices.SortStableFunc(fans, func(a, b ui.FanReading) int {
+		return cmp.Or(cmp.Compare(a.Chip, b.Chip), cmp.Compare(a.Num, b.Num))
+	})
+	return fans, nil
+}
+
+// readTrimmed is the text of the file name under fsys, or "" if it cannot
+// be read.
+func readTrimmed(fsys fs.FS, name string) string {
+	b, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(b))
+}
+
+// readInt is the integer in the file name under fsys.
+func readInt(fsys fs.FS, name string) (int, error) {
+	b, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(b)))
+}
+
+// parseSMCFans reads the output of smc -f, a block for each fan the SMC
+// knows, numbered from 0:
+//
+//	Fan #0:
+//	    Fan ID       : Left side
+//	    Actual speed : 1998
+//	    Minimum speed: 2000
+//
+// A fan is labelled by its ID where the SMC has one, and fanN, N counted
+// from 1 as hwmon does, where not.
+func parseSMCFans(out string) []ui.FanReading {
+	var fans []ui.FanReading
+	sc := bufio.NewScanner(strings.NewReader(out))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if n, ok := strings.CutPrefix(line, "Fan #"); ok {
+			num, err := strconv.Atoi(strings.TrimSuffix(n, ":"))
+			if err != nil {
+				continue
+			}
+			fans = append(fans, ui.FanReading{Chip: "smc", Num: num + 1, Label: fmt.Sprintf("fan%d", num+1)})
+			continue
+		}
+		key, val, ok := strings.Cut(line, ":")
+		if !ok || len(fans) == 0 {
+			continue
+		}
+		f := &fans[len(fans)-1]
+		val = strings.TrimSpace(val)
+		switch strings.TrimSpace(key) {
+		case "Fan ID":
+			if val != "" {
+				f.Label = val
+			}
+		case "Actual speed":
+			if rpm, err := strconv.ParseFloat(val, 64); err == nil {
+				f.RPM = max(0, int(rpm+0.5))
+			}
+		}
+	}
+	return fans
+}
+
+// fanWatch reads the fans.
+type fanWatch struct {
+	read func(context.Context) ([]ui.FanReading, error)
+	sub  subsystem
+	now  func() time.Time
+	last time.Time // of the last reading started
+}
+
+// hwmonFans returns a fanWatch of the fans under fsys, a /sys/class/hwmon,
+// if it finds any, and nil if not.
+func hwmonFans(fsys fs.FS) *fanWatch {
+	if fsys == nil {
+		return nil
+	}
+	if fans, err := readHwmonFans(fsys); err != nil || len(fans) == 0 {
+		return nil
+	}
+	return &fanWatch{
+		read: func(context.Context) ([]ui.FanReading, error) { return readHwmonFans(fsys) },
+		now:  time.Now,
+	}
+}
+
+// readCmd reads the fans off the Update goroutine, or returns nil until
+// fanInterval has passed since the last reading, while it runs, or while a
+// failure is backed off.
+func (w *fanWatch) readCmd(ctx context.Context) tea.Cmd {
+	now := w.now()
+	if now.Sub(w.last) < fanInterval || !w.sub.due(now) || w.sub.busy.Load() {
+		return nil
+	}
+	w.last = now
+	return func() tea.Msg {
+		fans, err := query(ctx, &w.sub, statsCallTimeout, w.read)
+		if err != nil {
+			w.sub.failed(w.now())
+			return ui.FansMsg{}
+		}
+		w.sub.recovered()
+		return ui.FansMsg{Fans: fans}
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+//go:build darwin
+
+package main
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"time"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+// detectFans watches the fans in the SMC where smc is installed.  smc is
+// not run until the first reading, in the background, so that one slow to
+// answer never holds up the first frame; one that fails, or finds no fans,
+// backs off and leaves no row.
+func detectFans() *fanWatch {
+	path, err := exec.LookPath("smc")
+	if err != nil {
+		return nil
+	}
+	read := func(ctx context.Context) ([]ui.FanReading, error) {
+		out, err := exec.CommandContext(ctx, path, "-f").Output()
+		if err != nil {
+			return nil, err
+		}
+		fans := parseSMCFans(string(out))
+		if len(fans) == 0 {
+			return nil, errors.New("smc: no fans")
+		}
+		return fans, nil
+	}
+	return &fanWatch{read: read, now: time.Now}
+}
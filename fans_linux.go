@@ -0,0 +1,13 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+//go:build linux
+
+package main
+
+import (
+	"os"
+)
+
+// detectFans watches the fans the hwmon drivers list their sensors for.
+func detectFans() *fanWatch { return hwmonFans(os.DirFS("/sys/class/hwmon")) }
@@ -0,0 +1,10 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+//go:build !linux && !darwin
+
+package main
+
+// detectFans is nil here: there are no fans to read, and the SYSTEM panel
+// has no Fans row.
+func detectFans() *fanWatch { return nil }
@@ -0,0 +1,84 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"os"
+	"slices"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+// The fans are found under every hwmon chip, in the order of their chips
+// and numbers, with their labels where the driver gives them; one that
+// cannot be read is left out.
+func TestReadHwmonFans(t *testing.T) {
+	fans, err := readHwmonFans(os.DirFS("testdata/hwmon"))
+	want := []ui.FanReading{
+		{Chip: "dell_smm", Num: 1, Label: "fan1", RPM: 2400},
+		{Chip: "nct6775", Num: 1, Label: "fan1", RPM: 760},
+		{Chip: "nct6775", Num: 2, Label: "CPU Fan", RPM: 1180},
+		{Chip: "nct6775", Num: 10, Label: "fan10", RPM: 0},
+	}
+	if err != nil || !slices.Equal(fans, want) {
+		t.Errorf("got %+v, %v", fans, err)
+	}
+	if hwmonFans(os.DirFS("testdata/hwmon")) == nil {
+		t.Error("no watch of the fans")
+	}
+	for _, dir := range []string{"hwmon-none", "missing"} {
+		if w := hwmonFans(os.DirFS("testdata/" + dir)); w != nil {
+			t.Errorf("%s: a watch of no fans", dir)
+		}
+	}
+	if w := hwmonFans(nil); w != nil {
+		t.Error("a watch without hwmon")
+	}
+}
+
+// smc -f gives each fan by its ID, or its number where it has none.
+func TestParseSMCFans(t *testing.T) {
+	out, err := os.ReadFile("testdata/smc-fans")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ui.FanReading{
+		{Chip: "smc", Num: 1, Label: "Left side", RPM: 1999},
+		{Chip: "smc", Num: 2, Label: "Right side", RPM: 0},
+		{Chip: "smc", Num: 3, Label: "fan3", RPM: 1200},
+	}
+	if got := parseSMCFans(string(out)); !slices.Equal(got, want) {
+		t.Errorf("got %+v", got)
+	}
+	if got := parseSMCFans("Total fans in system: 0\n"); got != nil {
+		t.Errorf("no fans: got %+v", got)
+	}
+}
+
+// The fans are read every fanInterval, not on every stats tick.
+func TestFanWatch(t *testing.T) {
+	fsys := fstest.MapFS{"hwmon0/name": {Data: []byte("it8728\n")}, "hwmon0/fan1_input": {Data: []byte("900\n")}}
+	w := hwmonFans(fsys)
+	if w == nil {
+		t.Fatal("no watch")
+	}
+	now := time.Unix(1700000000, 0)
+	w.now = func() time.Time { return now }
+	if msg := w.readCmd(context.Background())().(ui.FansMsg); len(msg.Fans) != 1 || msg.Fans[0].RPM != 900 {
+		t.Errorf("got %+v", msg.Fans)
+	}
+	now = now.Add(ui.DefaultInterval)
+	if cmd := w.readCmd(context.Background()); cmd != nil {
+		t.Error("read again before fanInterval")
+	}
+	now = now.Add(fanInterval)
+	fsys["hwmon0/fan1_input"] = &fstest.MapFile{Data: []byte("0\n")}
+	if msg := w.readCmd(context.Background())().(ui.FansMsg); len(msg.Fans) != 1 || msg.Fans[0].RPM != 0 {
+		t.Errorf("got %+v", msg.Fans)
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+// ── File descriptors ──────────────────────────────────────────────────────────
+//
+// The SYSTEM panel's FDs row: the file descriptors allocated across the
+// system against its limit, and infgo's own, to show a leak at a glance.
+// They change slowly, so they are read on a tick of their own every
+// fdInterval rather than with the stats.  Where they cannot be read, as
+// off Linux, the row is left out.
+
+// fdInterval is the time between readings of the descriptors.
+const fdInterval = 5 * time.Second
+
+// parseFileNr parses /proc/sys/fs/file-nr: the handles allocated, those
+// of them free, and the limit.  Kernels since 2.6 never report any free,
+// but the field is still there and still subtracted.  Some systems set
+// the limit to LONG_MAX, which parses like any other.
+func parseFileNr(s string) (allocated, max uint64, err error) {
+	f := strings.Fields(s)
+	if len(f) != 3 {
+		return 0, 0, fmt.Errorf("file-nr: %q has %d fields, want 3", strings.TrimSpace(s), len(f))
+	}
+	var v [3]uint64
+	for i, x := range f {
+		if v[i], err = strconv.ParseUint(x, 10, 64); err != nil {
+			return 0, 0, fmt.Errorf("file-nr: %w", err)
+		}
+	}
+	if v[1] > v[0] {
+		return 0, 0, fmt.Errorf("file-nr: %d free of %d allocated", v[1], v[0])
+	}
+	return v[0] - v[1], v[2], nil
+}
+
+type fdTickMsg time.Time
+
+func fdTick() tea.Cmd {
+	return tea.Tick(fdInterval, func(t time.Time) tea.Msg { return fdTickMsg(t) })
+}
+
+// fdsCmd takes a reading off the Update goroutine.
+func fdsCmd() tea.Cmd {
+	return func() tea.Msg {
+		u, err := readFDs()
+		return ui.FDsMsg{Usage: u, OK: err == nil}
+	}
+}
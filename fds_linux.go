@@ -0,0 +1,31 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+//go:build linux
+
+package main
+
+import (
+	"os"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+// readFDs reads the system's descriptors from /proc/sys/fs/file-nr, and
+// infgo's by listing /proc/self/fd, less the one the listing holds open.
+func readFDs() (ui.FDUsage, error) {
+	b, err := os.ReadFile("/proc/sys/fs/file-nr")
+	if err != nil {
+		return ui.FDUsage{}, err
+	}
+	var u ui.FDUsage
+	if u.Allocated, u.Max, err = parseFileNr(string(b)); err != nil {
+		return ui.FDUsage{}, err
+	}
+	fds, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		return ui.FDUsage{}, err
+	}
+	u.Self = max(len(fds)-1, 0)
+	return u, nil
+}
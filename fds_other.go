@@ -0,0 +1,15 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+//go:build !linux
+
+package main
+
+import (
+	"errors"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+// readFDs cannot read the descriptors here; the FDs row is left out.
+func readFDs() (ui.FDUsage, error) { return ui.FDUsage{}, errors.ErrUnsupported }
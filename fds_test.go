@@ -0,0 +1,43 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestParseFileNr(t *testing.T) {
+	tests := []struct {
+		in             string
+		allocated, max uint64
+		ok             bool
+	}{
+		{"12431\t0\t1048576\n", 12431, 1048576, true},
+		{"9024\t0\t9223372036854775807\n", 9024, 9223372036854775807, true}, // fs.file-max = LONG_MAX
+		{"3391\t969\t52427\n", 2422, 52427, true},                           // 2.4 kernels report free handles
+		{"  12431 0 1048576  ", 12431, 1048576, true},
+		{"12431\t0\n", 0, 0, false},
+		{"12431\t0\t1048576\t7\n", 0, 0, false},
+		{"12431\t-1\t1048576\n", 0, 0, false},
+		{"10\t20\t100\n", 0, 0, false},
+		{"", 0, 0, false},
+	}
+	for _, tt := range tests {
+		allocated, max, err := parseFileNr(tt.in)
+		if (err == nil) != tt.ok || allocated != tt.allocated || max != tt.max {
+			t.Errorf("%q: got %d / %d, %v", tt.in, allocated, max, err)
+		}
+	}
+}
+
+func TestReadFDs(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("the descriptors are read from /proc")
+	}
+	u, err := readFDs()
+	if err != nil || u.Allocated == 0 || u.Max < u.Allocated || u.Self < 3 {
+		t.Errorf("got %+v, %v", u, err)
+	}
+}
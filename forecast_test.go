@@ -0,0 +1,35 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ALH477/infgo/analysis"
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ui"
+)
+
+func TestPrintSummaryForecast(t *testing.T) {
+	// Five minutes of readings a second apart, rising from 50% by half a
+	// point a minute.
+	c := &analysis.Capture{}
+	t0 := time.Unix(1_700_000_000, 0)
+	for i := 0; i <= 300; i++ {
+		at := t0.Add(time.Duration(i) * time.Second)
+		c.Samples = append(c.Samples, metrics.Sample{TimestampUnixMs: at.UnixMilli(), MemPercent: 50 + 0.5*float64(i)/60})
+	}
+	f, ok := ui.ShownForecast(analysis.MemForecast(c.Samples, 5*time.Minute, analysis.MinForecastR2))
+	if !ok {
+		t.Fatal("got no forecast")
+	}
+	var out bytes.Buffer
+	printSummary(&out, c, analysis.SummarizeSamples(c.Samples), &f, resolveZone("utc", nil))
+	if want := "Forecast   memory rising 0.50%/min at the end; at this rate, 95% in ~1h 25m · full in ~1h 35m"; !strings.Contains(out.String(), want) {
+		t.Errorf("missing %q in\n%s", want, out.String())
+	}
+}
@@ -0,0 +1,255 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
+
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ui"
+)
+
+// ── Whole frames ──────────────────────────────────────────────────────────────
+//
+// The frames below are the binary's screens as a user sees them, every
+// panel on, compared whole against goldens.  They pin down what the TUI
+// draws however its code is arranged.
+
+// frameStart is the time of the first reading of a frame's session.
+var frameStart = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// frameReading is the i-th reading of a busy eight-core machine.
+func frameReading(i int) ui.Reading {
+	at := frameStart.Add(time.Duration(i) * ui.DefaultInterval)
+	n := uint64(i + 1)
+	msg := ui.Reading{
+		CPUTotal:   41 + float64(i*9),
+		CPUCores:   []float64{12, 95, 40, 63, 8, 77, 51, 30},
+		CoreMHz:    []float64{3800, 4200, 2400, 3100, 800, 4000, 3300, 2900},
+		Times:      ui.CPUTimes{User: 100 * float64(n), System: 40 * float64(n), Idle: 200 * float64(n), Iowait: 5 * float64(n)},
+		HasTimes:   true,
+		MemPercent: 72 + float64(i),
+		MemUsedGB:  11.5,
+		MemTotalGB: 16,
+		Load1:      3.2,
+		Load5:      2.6,
+		Load15:     1.9,
+
+		MemAvailGB: 4.5, MemCachedGB: 3.25, MemBuffersGB: 0.5,
+		SwapUsedGB: 1, SwapTotalGB: 4, SwapPercent: 25, HasSwap: true,
+
+		Watts: 18.5, Joules: 1200 + 9*float64(i), HasWatts: true,
+		Took: 3 * time.Millisecond, At: at, Due: at, Begun: at.Add(time.Millisecond),
+
+		Disks: []metrics.DiskUsage{{Mount: "/", UsedPercent: 62, UsedGB: 124, TotalGB: 200}},
+		Mounts: []metrics.DiskUsage{
+			{Mount: "/", UsedPercent: 62, UsedGB: 124, TotalGB: 200},
+			{Mount: "/home", UsedPercent: 91, UsedGB: 910, TotalGB: 1000},
+			{Mount: "/boot", UsedPercent: 18, UsedGB: 0.18, TotalGB: 1},
+		},
+		IO: []ui.IOCounter{
+			{Name: "nvme0n1", Read: n * 40 << 20, Written: n * 12 << 20, Reads: n * 300, Writes: n * 90, ReadMs: n * 150, WriteMs: n * 270},
+			{Name: "sda", Read: n * 2 << 20, Written: n * 1 << 20, Reads: n * 20, Writes: n * 10, ReadMs: n * 80, WriteMs: n * 60},
+		},
+		IOAt: at,
+		NICs: []ui.NetCounter{
+			{Name: "eth0", Rx: n * 6 << 20, Tx: n * 1 << 20},
+			{Name: "wlan0", Rx: n * 200 << 10, Tx: n * 50 << 10},
+		},
+		NetAt:   at,
+		Retrans: n * 40, RetransAt: at,
+		Temps: []ui.TempGroup{
+			{Name: "coretemp", Sensor: "package_id_0", Celsius: 78, Sensors: 9},
+			{Name: "nvme", Sensor: "composite", Celsius: 46, Sensors: 3},
+		},
+		Pressure: ui.Pressure{
+			CPU:    ui.PSIResource{Some: ui.PSIAvgs{Avg10: 12.5, Avg60: 8, Avg300: 3}},
+			Memory: ui.PSIResource{Some: ui.PSIAvgs{Avg10: 2, Avg60: 1, Avg300: 0.5}, Full: ui.PSIAvgs{Avg10: 0.8, Avg60: 0.2}, HasFull: true},
+			IO:     ui.PSIResource{Some: ui.PSIAvgs{Avg10: 30, Avg60: 22, Avg300: 9}, Full: ui.PSIAvgs{Avg10: 14, Avg60: 9, Avg300: 4}, HasFull: true},
+		},
+		HasPressure: true,
+		Kernel:      ui.KernelCounters{Ctxt: n * 12000, Intr: n * 6000},
+		KernelAt:    at,
+		Faults:      ui.FaultCounters{All: n * 9000, Major: n * 30},
+		FaultsAt:    at,
+	}
+	return msg
+}
+
+// frameModel is a local session with every panel on, three readings in,
+// built as main builds it.
+func frameModel(t *testing.T, w, h int) ui.Model {
+	t.Helper()
+	f := &fakePid{create: frameStart.Add(-time.Hour).UnixMilli(), cpuSecs: 90}
+	pw := &pidWatcher{read: f.read}
+	first, err := pw.watch(context.Background(), 4242)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := ui.New(
+		ui.WithRecorder(&recorder{logPath: "captures/box-120000.infgo"}),
+		ui.WithTicker([]string{"process", "disk"}),
+		ui.WithDiskPanel(),
+		ui.WithNetPanel(ui.RetransWarn, ui.RetransCrit),
+		ui.WithDiskIO(),
+		ui.WithUsers(),
+		ui.WithProcs(),
+		ui.WithPID(4242, first, frameStart),
+	)
+
+	msgs := []tea.Msg{
+		tea.WindowSizeMsg{Width: w, Height: h},
+		ui.SysInfo{Hostname: "box.lab.example", Platform: "ubuntu · x86_64", Uptime: 93784, NumCores: 8},
+	}
+	for i := range 3 {
+		msgs = append(msgs, frameReading(i))
+	}
+	f.cpuSecs = 91
+	pr, _ := f.read(context.Background(), 4242)
+	msgs = append(msgs,
+		ui.GPUMsg{GPUs: []metrics.GpuUsage{{Name: "NVIDIA GeForce RTX 4090", UtilPercent: 64, MemUsedGB: 8.8, MemTotalGB: 24, TempC: 71}}},
+		ui.NUMAMsg{Nodes: []ui.NUMANode{{ID: 0, UsedGB: 6, TotalGB: 8}, {ID: 1, UsedGB: 5.5, TotalGB: 8}}},
+		ui.FansMsg{Fans: []ui.FanReading{{Chip: "nct6775", Num: 1, Label: "cpu_fan", RPM: 1180}, {Chip: "nct6775", Num: 2, Label: "fan2", RPM: 640}}},
+		ui.ThrottleMsg{Reading: ui.ThrottleReading{Limited: true}, OK: true},
+		ui.FDsMsg{Usage: ui.FDUsage{Allocated: 12431, Max: 1048576, Self: 23}, OK: true},
+		ui.ProcCountsMsg{Counts: ui.ProcCounts{Total: 412, Zombies: 3}, OK: true},
+		ui.ProcsMsg{Procs: []ui.ProcInfo{
+			{User: "alice", CPU: 180, RSS: 3 << 30, PID: 4312, PPID: 1, Name: "firefox"},
+			{User: "alice", CPU: 35, RSS: 700 << 20, PID: 4400, PPID: 4312, Name: "firefox-tab"},
+			{User: "postgres", CPU: 60, RSS: 2 << 30, PID: 900, PPID: 1, Name: "postgres"},
+			{User: "root", CPU: 4, RSS: 20 << 20, PID: 1, PPID: 0, Name: "systemd"},
+			{User: "", CPU: 1, RSS: 0, PID: 2, PPID: 0, Name: "kthreadd", Kernel: true},
+		}},
+		ui.PIDsMsg{At: frameStart.Add(2 * time.Second), Readings: []ui.PIDReading{pr}, Errs: []error{nil}},
+	)
+	return update(m, msgs...)
+}
+
+// update hands m each of msgs in turn.
+func update(m ui.Model, msgs ...tea.Msg) ui.Model {
+	var tm tea.Model = m
+	for _, msg := range msgs {
+		tm, _ = tm.Update(msg)
+	}
+	return tm.(ui.Model)
+}
+
+// pressKeys sends each key to m in turn.
+func pressKeys(m ui.Model, keys string) ui.Model {
+	for _, r := range keys {
+		m = update(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(string(r))})
+	}
+	return m
+}
+
+// sizedModel is a model with a reading of four cores, laid out for a w×h
+// terminal.
+func sizedModel(w, h int, opts ...ui.Option) ui.Model {
+	cores := make([]float64, 4)
+	for i := range cores {
+		cores[i] = float64(i * 6)
+	}
+	return update(ui.New(opts...),
+		tea.WindowSizeMsg{Width: w, Height: h},
+		ui.SysInfo{Hostname: "box", Platform: "linux", NumCores: 4},
+		ui.Reading{CPUTotal: 45, CPUCores: cores, MemPercent: 61, MemUsedGB: 9.8, MemTotalGB: 16},
+	)
+}
+
+// footer is the last line of m's frame.
+func footer(m ui.Model) string {
+	v := ansi.Strip(m.View())
+	return v[strings.LastIndexByte(v, '\n')+1:]
+}
+
+// remoteSample is a poll of host answered with a CPU use of cpu at ts.
+func remoteSample(host string, ts int64, cpu float64) ui.RemoteMsg {
+	return ui.RemoteMsg{
+		Reading: ui.Reading{CPUTotal: cpu, CPUCores: []float64{cpu}, MemPercent: 40, Load1: 1, At: time.UnixMilli(ts)},
+		Info:    &ui.SysInfo{Hostname: host, NumCores: 4},
+		RTT:     2 * time.Millisecond,
+	}
+}
+
+func TestFrames(t *testing.T) {
+	node7 := &remoteSource{base: "http://node7:9804", host: "node7:9804"}
+	tests := []struct {
+		name   string
+		golden string
+		view   func(t *testing.T) string
+	}{
+		{"every panel", "frame_local.txt", func(t *testing.T) string {
+			return frameModel(t, 120, 100).View()
+		}},
+		{"every panel, short", "frame_local_short.txt", func(t *testing.T) string {
+			return frameModel(t, 100, 40).View()
+		}},
+		{"every panel, collapsed", "frame_local_collapsed.txt", func(t *testing.T) string {
+			return pressKeys(frameModel(t, 100, 60), "1357").View()
+		}},
+		{"tree and each disk", "frame_local_tree.txt", func(t *testing.T) string {
+			return pressKeys(frameModel(t, 120, 100), "tdnaMf").View()
+		}},
+		{"narrowest", "frame_narrow.txt", func(t *testing.T) string {
+			return sizedModel(72, 24).View()
+		}},
+		{"initialising", "frame_initialising.txt", func(t *testing.T) string {
+			return update(ui.New(), tea.WindowSizeMsg{Width: 100, Height: 30}).View()
+		}},
+		{"remote link down", "frame_remote_down.txt", func(t *testing.T) string {
+			return update(ui.New(ui.WithFeed(node7)),
+				tea.WindowSizeMsg{Width: 100, Height: 40},
+				remoteSample("node7", frameStart.UnixMilli(), 35),
+				ui.RemoteMsg{Err: errors.New("dial tcp 10.0.0.7:9804: connect: connection refused")},
+			).View()
+		}},
+		{"connecting", "frame_connecting.txt", func(t *testing.T) string {
+			return update(ui.New(ui.WithFeed(node7)), tea.WindowSizeMsg{Width: 100, Height: 40}).View()
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkGoldenText(t, tt.golden, ansi.Strip(tt.view(t)))
+		})
+	}
+}
+
+// The colours are part of the frame too.
+func TestFrameColours(t *testing.T) {
+	prev := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	t.Cleanup(func() { lipgloss.SetColorProfile(prev) })
+	checkGoldenText(t, "frame_local_colour.txt", frameModel(t, 120, 100).View())
+}
+
+// checkGoldenText compares rendered output against testdata/name.
+func checkGoldenText(t *testing.T, name, got string) {
+	t.Helper()
+	got += "\n"
+	path := filepath.Join("testdata", name)
+	if *updateGolden {
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("%s: screen differs from the golden file (rerun with -update to accept)\ngot:\n%s", name, got)
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+// ── Core frequencies ──────────────────────────────────────────────────────────
+//
+// The clock of each core, shown after its percentage in the CPU panel's
+// grid.  Linux re-reads /proc/cpuinfo every tick, so the grid follows the
+// governor; elsewhere the advertised frequency is all there is, and every
+// core shows it.  A machine that reports none, or not one per core, has
+// the grid as it was.
+
+// parseCPUInfoMHz is the "cpu MHz" of each processor in a /proc/cpuinfo,
+// in order; nil where it has none, as on most ARM kernels.
+func parseCPUInfoMHz(r io.Reader) []float64 {
+	var out []float64
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		key, val, ok := strings.Cut(sc.Text(), ":")
+		if !ok || strings.TrimSpace(key) != "cpu MHz" {
+			continue
+		}
+		mhz, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+		if err != nil {
+			return nil
+		}
+		out = append(out, mhz)
+	}
+	return out
+}
+
+// readFreqs reads the cores' frequencies into msg, with the CPU.  A
+// machine without them backs off, and its grid is left as it was.
+func (r *statsReader) readFreqs(ctx context.Context, msg *ui.Reading) {
+	if r.src.freqs == nil || !r.freq.due(r.now()) {
+		return
+	}
+	mhz, err := query(ctx, &r.freq, r.timeout, r.src.freqs)
+	if err != nil || len(mhz) == 0 {
+		r.freq.failed(r.now())
+		return
+	}
+	r.freq.recovered()
+	msg.CoreMHz = mhz
+}
@@ -0,0 +1,22 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+//go:build linux
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+)
+
+// readCoreMHz is the current frequency of each core from /proc/cpuinfo,
+// which the kernel fills in afresh on every read.
+func readCoreMHz(context.Context) ([]float64, error) {
+	b, err := os.ReadFile("/proc/cpuinfo")
+	if err != nil {
+		return nil, err
+	}
+	return parseCPUInfoMHz(bytes.NewReader(b)), nil
+}
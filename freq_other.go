@@ -0,0 +1,30 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+//go:build !linux
+
+package main
+
+import (
+	"context"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+)
+
+// readCoreMHz is the advertised frequency of the first processor for each
+// logical core; gopsutil has no current one outside Linux.
+func readCoreMHz(ctx context.Context) ([]float64, error) {
+	infos, err := cpu.InfoWithContext(ctx)
+	if err != nil || len(infos) == 0 || infos[0].Mhz <= 0 {
+		return nil, err
+	}
+	n, err := cpu.CountsWithContext(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = infos[0].Mhz
+	}
+	return out, nil
+}
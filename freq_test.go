@@ -0,0 +1,41 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+func TestParseCPUInfoMHz(t *testing.T) {
+	const cpuinfo = "processor\t: 0\nmodel name\t: Intel(R) Core(TM)\ncpu MHz\t\t: 3799.998\n\n" +
+		"processor\t: 1\ncpu MHz\t\t: 1200.000\n"
+	if got := parseCPUInfoMHz(strings.NewReader(cpuinfo)); !slices.Equal(got, []float64{3799.998, 1200}) {
+		t.Errorf("got %v", got)
+	}
+	if got := parseCPUInfoMHz(strings.NewReader("processor\t: 0\nBogoMIPS\t: 108.00\n")); got != nil {
+		t.Errorf("ARM: got %v", got)
+	}
+}
+
+func TestReadFreqs(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	r := fakeReader(&fakeSources{}, &now)
+	if msg := r.read(context.Background()); !slices.Equal(msg.CoreMHz, []float64{3800, 2400}) {
+		t.Errorf("got %v", msg.CoreMHz)
+	}
+	r.src.freqs = func(context.Context) ([]float64, error) { return nil, nil }
+	now = now.Add(ui.DefaultInterval)
+	if msg := r.read(context.Background()); msg.CoreMHz != nil || msg.Missing != 0 {
+		t.Errorf("none: got %v, missing %q", msg.CoreMHz, msg.Missing)
+	}
+	if r.freq.due(now) || !r.cpu.due(now) {
+		t.Error("the clocks did not back off on their own")
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ui"
+)
+
+// ── GPU panel ─────────────────────────────────────────────────────────────────
+//
+// The load, memory and temperature of each NVIDIA GPU, from nvidia-smi,
+// polled on the stats tick in a command of its own: a driver that is slow
+// to answer holds up the panel, never the reading of everything else.  A
+// machine without nvidia-smi is never polled.  A poll that fails, as it
+// does with no driver loaded, backs off, and the last reading stays shown,
+// marked stale; before any has succeeded there is no panel.  Each sample
+// the TUI or the collector records carries the last reading.
+
+// gpuQuery is what nvidia-smi is asked for, one line per GPU.
+const gpuQuery = "--query-gpu=index,name,utilization.gpu,memory.used,memory.total,temperature.gpu"
+
+// gpuTimeout bounds a poll; nvidia-smi takes well under a second when the
+// driver is healthy.
+const gpuTimeout = 3 * time.Second
+
+// gpuWatch polls the GPUs.
+type gpuWatch struct {
+	query func(ctx context.Context) ([]byte, error)
+	sub   subsystem
+	now   func() time.Time
+}
+
+// detectGPU returns a gpuWatch if nvidia-smi is installed, and nil if
+// not.
+func detectGPU() *gpuWatch {
+	path, err := exec.LookPath("nvidia-smi")
+	if err != nil {
+		return nil
+	}
+	return &gpuWatch{now: time.Now, query: func(ctx context.Context) ([]byte, error) {
+		return exec.CommandContext(ctx, path, gpuQuery, "--format=csv,noheader,nounits").Output()
+	}}
+}
+
+// parseNvidiaSMI parses nvidia-smi's CSV, in the order of gpuQuery.  A
+// value the GPU does not support, "[N/A]" or "[Not Supported]", reads as
+// zero.
+func parseNvidiaSMI(out string) ([]metrics.GpuUsage, error) {
+	var gpus []metrics.GpuUsage
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		f := strings.Split(line, ",")
+		if len(f) != 6 {
+			return nil, fmt.Errorf("nvidia-smi: %q has %d fields, want 6", line, len(f))
+		}
+		v := make([]float64, len(f))
+		for i := 2; i < len(f); i++ {
+			v[i], _ = strconv.ParseFloat(strings.TrimSpace(f[i]), 64)
+		}
+		gpus = append(gpus, metrics.GpuUsage{
+			Name:        strings.TrimSpace(f[1]),
+			UtilPercent: v[2],
+			MemUsedGB:   v[3] / 1024, // MiB
+			MemTotalGB:  v[4] / 1024,
+			TempC:       v[5],
+		})
+	}
+	if len(gpus) == 0 || gpus[0].Name == "" {
+		return nil, errors.New("nvidia-smi: no GPUs")
+	}
+	return gpus, nil
+}
+
+// pollCmd polls the GPUs off the Update goroutine, or returns nil while
+// the last poll runs or a failure is backed off.
+func (w *gpuWatch) pollCmd(ctx context.Context) tea.Cmd {
+	if !w.sub.due(w.now()) || w.sub.busy.Load() {
+		return nil
+	}
+	return func() tea.Msg {
+		out, err := query(ctx, &w.sub, gpuTimeout, w.query)
+		var gpus []metrics.GpuUsage
+		if err == nil {
+			gpus, err = parseNvidiaSMI(string(out))
+		}
+		if err != nil {
+			w.sub.failed(w.now())
+			return ui.GPUMsg{}
+		}
+		w.sub.recovered()
+		return ui.GPUMsg{GPUs: gpus}
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	syslogger "github.com/ALH477/infgo/logger"
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ui"
+)
+
+func TestParseNvidiaSMI(t *testing.T) {
+	out := "0, NVIDIA A100-SXM4-40GB, 87, 31232, 40960, 64\n" +
+		"1, NVIDIA A100-SXM4-40GB, [N/A], 256, 40960, 31\n"
+	got, err := parseNvidiaSMI(out)
+	want := []metrics.GpuUsage{
+		{Name: "NVIDIA A100-SXM4-40GB", UtilPercent: 87, MemUsedGB: 30.5, MemTotalGB: 40, TempC: 64},
+		{Name: "NVIDIA A100-SXM4-40GB", UtilPercent: 0, MemUsedGB: 0.25, MemTotalGB: 40, TempC: 31},
+	}
+	if err != nil || !slices.Equal(got, want) {
+		t.Errorf("got %v, %v", got, err)
+	}
+	for _, bad := range []string{"", "NVIDIA-SMI has failed because it couldn't communicate with the NVIDIA driver."} {
+		if got, err := parseNvidiaSMI(bad); err == nil {
+			t.Errorf("%q: got %v", bad, got)
+		}
+	}
+}
+
+// A poll runs in a command of its own; one that fails backs off.
+func TestGPUPoll(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	out, fail := "0, Tesla T4, 40, 1024, 15360, 50\n", false
+	w := &gpuWatch{now: func() time.Time { return now }, query: func(context.Context) ([]byte, error) {
+		if fail {
+			return nil, errFake
+		}
+		return []byte(out), nil
+	}}
+	if msg := w.pollCmd(context.Background())(); len(msg.(ui.GPUMsg).GPUs) != 1 {
+		t.Fatalf("got %v", msg)
+	}
+	fail = true
+	if msg := w.pollCmd(context.Background())(); msg.(ui.GPUMsg).GPUs != nil {
+		t.Errorf("failed: got %v", msg)
+	}
+	if w.pollCmd(context.Background()) != nil {
+		t.Error("polled again during the backoff")
+	}
+	fail, now = false, now.Add(statsBackoffMin)
+	if msg := w.pollCmd(context.Background())(); len(msg.(ui.GPUMsg).GPUs) != 1 {
+		t.Errorf("recovered: got %v", msg)
+	}
+}
+
+// The headless collector polls the GPUs off its loop and records the last
+// poll in each sample, as the TUI does.
+func TestHeadlessGPU(t *testing.T) {
+	out := "0, Tesla T4, 40, 1024, 15360, 50\n"
+	h := &headless{watched: make(chan tea.Msg, 1), gpu: &gpuWatch{now: time.Now, query: func(context.Context) ([]byte, error) {
+		return []byte(out), nil
+	}}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	h.poll(ctx)
+	select {
+	case msg := <-h.watched:
+		h.observe(msg)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no poll delivered")
+	}
+
+	var log bytes.Buffer
+	lgr, err := syslogger.NewWriter(&log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.logger, h.gpu, h.interval = lgr, nil, 10*time.Millisecond
+	reads := 0
+	h.read = func(context.Context) ui.Reading {
+		if reads++; reads > 1 {
+			cancel()
+			return ui.Reading{Missing: metrics.MissingAll}
+		}
+		return ui.Reading{CPUTotal: 10, CPUCores: []float64{10}}
+	}
+	if err := h.run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	lgr.Flush()
+	if _, samples := readLog(t, &log); len(samples) != 1 || len(samples[0].Gpus) != 1 || samples[0].Gpus[0].Name != "Tesla T4" {
+		t.Errorf("logged: got %+v", samples)
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ui"
+)
+
+// ── Graphite push (-graphite) ─────────────────────────────────────────────────
+
+// graphiteTimeout bounds each connection attempt and write.
+const graphiteTimeout = 2 * time.Second
+
+// errGraphiteBackoff drops a sample while a reconnect is pending; the
+// dial error that caused the wait stays the one reported.
+var errGraphiteBackoff = errors.New("graphite: waiting to reconnect")
+
+// graphiteWriter sends every sample to a Carbon plaintext listener over
+// one long-lived TCP connection.
+type graphiteWriter struct {
+	addr   string
+	prefix string
+	dial   func() (net.Conn, error)
+
+	host     string
+	conn     net.Conn // nil while disconnected
+	backoff  time.Duration
+	nextDial time.Time
+	buf      []byte
+	lost     atomic.Int64
+	lastFail atomic.Value // string
+}
+
+// newGraphiteWriter validates -graphite and -graphite-prefix.  A bare host
+// gets Carbon's default port, 2003.
+func newGraphiteWriter(addr, prefix string) (*graphiteWriter, error) {
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, "2003")
+	}
+	prefix = strings.Trim(prefix, ".")
+	if prefix == "" || strings.ContainsAny(prefix, " \t\n") || strings.Contains(prefix, "..") {
+		return nil, fmt.Errorf("-graphite-prefix: %q is not a valid metric path", prefix)
+	}
+	w := &graphiteWriter{addr: addr, prefix: prefix}
+	w.dial = func() (net.Conn, error) { return net.DialTimeout("tcp", w.addr, graphiteTimeout) }
+	return w, nil
+}
+
+func (w *graphiteWriter) dropped() int64 { return w.lost.Load() }
+
+func (w *graphiteWriter) lastError() string {
+	s, _ := w.lastFail.Load().(string)
+	return s
+}
+
+func (w *graphiteWriter) run(in <-chan pushRecord) {
+	for rec := range in {
+		if rec.header != nil {
+			w.host = rec.header.Hostname
+			continue
+		}
+		w.buf = metrics.AppendGraphite(w.buf[:0], w.prefix, w.host, &rec.sample)
+		if err := w.send(w.buf); err != nil {
+			w.lost.Add(1)
+			if err != errGraphiteBackoff {
+				w.lastFail.Store(err.Error())
+			}
+		}
+	}
+	if w.conn != nil {
+		w.conn.Close()
+	}
+}
+
+// send writes one sample's lines in a single write.  A connection the
+// server has closed is replaced first; one that breaks during the write is
+// replaced and the write tried once more.
+func (w *graphiteWriter) send(lines []byte) error {
+	if w.conn != nil && peerClosed(w.conn) {
+		w.conn.Close()
+		w.conn = nil
+	}
+	for attempt := 0; attempt < 2; attempt++ {
+		if err := w.connect(); err != nil {
+			return err
+		}
+		w.conn.SetWriteDeadline(time.Now().Add(graphiteTimeout))
+		_, err := w.conn.Write(lines)
+		if err == nil {
+			return nil
+		}
+		w.conn.Close()
+		w.conn = nil
+		if attempt == 1 {
+			return fmt.Errorf("graphite: %w", err)
+		}
+	}
+	return nil
+}
+
+// connect dials if there is no connection.  Failed dials back off like
+// -connect's reconnects, and samples are dropped in the meantime rather
+// than queued.
+func (w *graphiteWriter) connect() error {
+	if w.conn != nil {
+		return nil
+	}
+	if time.Now().Before(w.nextDial) {
+		return errGraphiteBackoff
+	}
+	conn, err := w.dial()
+	if err != nil {
+		w.backoff = ui.NextBackoff(w.backoff)
+		w.nextDial = time.Now().Add(w.backoff)
+		return fmt.Errorf("graphite: %w", err)
+	}
+	w.conn, w.backoff, w.nextDial = conn, 0, time.Time{}
+	return nil
+}
+
+// peerClosed reports whether the server has closed conn.  Carbon never
+// sends anything, so a read that fails other than by timing out means EOF
+// or a reset.  Catching that before writing avoids losing the sample that
+// a write into a half-closed connection would swallow.
+func peerClosed(conn net.Conn) bool {
+	conn.SetReadDeadline(time.Now().Add(time.Millisecond))
+	defer conn.SetReadDeadline(time.Time{})
+	var b [1]byte
+	_, err := conn.Read(b[:])
+	var ne net.Error
+	if errors.As(err, &ne) && ne.Timeout() {
+		return false
+	}
+	return err != nil
+}
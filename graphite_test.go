@@ -0,0 +1,192 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// countingConn counts Write calls on the underlying connection.
+type countingConn struct {
+	net.Conn
+	writes *atomic.Int64
+}
+
+func (c countingConn) Write(b []byte) (int, error) {
+	c.writes.Add(1)
+	return c.Conn.Write(b)
+}
+
+// carbonStub accepts connections and hands each one's reader to the test.
+func carbonStub(t *testing.T) (addr string, conns <-chan net.Conn) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	ch := make(chan net.Conn, 4)
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			ch <- c
+		}
+	}()
+	return ln.Addr().String(), ch
+}
+
+// readLines reads n lines from c.
+func readLines(t *testing.T, c net.Conn, n int) []string {
+	t.Helper()
+	c.SetReadDeadline(time.Now().Add(2 * time.Second))
+	br := bufio.NewReader(c)
+	var lines []string
+	for len(lines) < n {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			t.Fatalf("after %d lines: %v", len(lines), err)
+		}
+		lines = append(lines, strings.TrimSuffix(line, "\n"))
+	}
+	return lines
+}
+
+func TestGraphiteWriter(t *testing.T) {
+	addr, conns := carbonStub(t)
+	w, err := newGraphiteWriter(addr, "infgo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var writes atomic.Int64
+	dial := w.dial
+	w.dial = func() (net.Conn, error) {
+		c, err := dial()
+		if err != nil {
+			return nil, err
+		}
+		return countingConn{c, &writes}, nil
+	}
+
+	p := startPusher("graphite", w)
+	p.setHeader(metrics.Header{Hostname: "web01.example.com"})
+	p.setSample(metrics.Sample{
+		TimestampUnixMs: 1704067200500,
+		CpuTotal:        42.5,
+		CpuCores:        []float64{40},
+		MemPercent:      50,
+		MemUsedGB:       1,
+		MemTotalGB:      2,
+		Load1:           0.25,
+	})
+	c := <-conns
+	got := readLines(t, c, 8)
+	want := []string{
+		"infgo.web01_example_com.cpu.total 42.5 1704067200",
+		"infgo.web01_example_com.cpu.core.0 40 1704067200",
+		"infgo.web01_example_com.mem.percent 50 1704067200",
+		"infgo.web01_example_com.mem.used_bytes 1073741824 1704067200",
+		"infgo.web01_example_com.mem.total_bytes 2147483648 1704067200",
+		"infgo.web01_example_com.load.1m 0.25 1704067200",
+		"infgo.web01_example_com.load.5m 0 1704067200",
+		"infgo.web01_example_com.load.15m 0 1704067200",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if err := p.close(); err != nil {
+		t.Fatal(err)
+	}
+	if n := writes.Load(); n != 1 {
+		t.Errorf("got %d writes for one sample, want 1", n)
+	}
+}
+
+func TestGraphiteReconnect(t *testing.T) {
+	addr, conns := carbonStub(t)
+	w, err := newGraphiteWriter(addr, "infgo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := startPusher("graphite", w)
+	p.setHeader(metrics.Header{Hostname: "h"})
+
+	p.setSample(metrics.Sample{TimestampUnixMs: 1000})
+	first := <-conns
+	if got := readLines(t, first, 1)[0]; got != "infgo.h.cpu.total 0 1" {
+		t.Fatalf("first sample: got %q", got)
+	}
+
+	// Carbon restarts: the next sample arrives, complete, on a new connection.
+	first.Close()
+	time.Sleep(20 * time.Millisecond) // let the FIN arrive
+	p.setSample(metrics.Sample{TimestampUnixMs: 2000, CpuTotal: 7})
+	select {
+	case second := <-conns:
+		if got := readLines(t, second, 1)[0]; got != "infgo.h.cpu.total 7 2" {
+			t.Errorf("after reconnect: got %q", got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no reconnect after the server closed the connection")
+	}
+	if err := p.close(); err != nil {
+		t.Errorf("close: %v", err)
+	}
+}
+
+func TestGraphiteDialBackoff(t *testing.T) {
+	w, err := newGraphiteWriter("127.0.0.1:1", "infgo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dials int
+	w.dial = func() (net.Conn, error) {
+		dials++
+		return nil, errors.New("connection refused")
+	}
+	p := startPusher("graphite", w)
+	for i := 0; i < 5; i++ {
+		p.setSample(metrics.Sample{TimestampUnixMs: int64(i)})
+	}
+	err = p.close()
+	if dials != 1 || w.dropped() != 5 {
+		t.Errorf("got %d dials and %d dropped, want 1 dial (then backoff) and 5 dropped", dials, w.dropped())
+	}
+	if err == nil || !strings.Contains(err.Error(), "connection refused") {
+		t.Errorf("close: got %v, want the dial error", err)
+	}
+}
+
+func TestNewGraphiteWriter(t *testing.T) {
+	tests := []struct {
+		addr, prefix, wantAddr, wantPrefix string
+	}{
+		{"carbon", "infgo", "carbon:2003", "infgo"},
+		{"carbon:2013", "servers.infgo.", "carbon:2013", "servers.infgo"},
+		{"carbon", "bad prefix", "", ""},
+		{"carbon", "a..b", "", ""},
+	}
+	for _, tt := range tests {
+		w, err := newGraphiteWriter(tt.addr, tt.prefix)
+		if tt.wantAddr == "" {
+			if err == nil {
+				t.Errorf("newGraphiteWriter(%q, %q): got nil error", tt.addr, tt.prefix)
+			}
+			continue
+		}
+		if err != nil || w.addr != tt.wantAddr || w.prefix != tt.wantPrefix {
+			t.Errorf("newGraphiteWriter(%q, %q): got %v", tt.addr, tt.prefix, err)
+		}
+	}
+}
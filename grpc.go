@@ -0,0 +1,150 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	syslogger "github.com/ALH477/infgo/logger"
+	"github.com/ALH477/infgo/rpc"
+)
+
+// ── gRPC service ──────────────────────────────────────────────────────────────
+
+// grpcService implements rpc.InfgoServer for -grpc-listen.  Live data comes
+// from the same liveState fan-out as the HTTP streams; recorded data is
+// read back from this instance's -log capture.
+type grpcService struct {
+	rpc.UnimplementedInfgoServer
+
+	live    *liveState
+	logPath string // "" (or "-") when there is no capture to read
+}
+
+func (g *grpcService) GetInfo(ctx context.Context, _ *rpc.InfoRequest) (*rpc.Header, error) {
+	snap, _ := g.live.snapshot()
+	if snap.Header == nil {
+		return nil, status.Error(codes.Unavailable, "host information not collected yet")
+	}
+	return &rpc.Header{Header: *snap.Header}, nil
+}
+
+func (g *grpcService) StreamLive(req *rpc.LiveRequest, stream rpc.Infgo_StreamLiveServer) error {
+	after := int64(-1)
+	if req.SinceUnixMs > 0 {
+		after = req.SinceUnixMs
+	}
+	sub, replay := g.live.subscribeAfter(after)
+	if sub == nil {
+		return status.Error(codes.Unavailable, "server shutting down")
+	}
+	defer g.live.unsubscribe(sub)
+
+	for i := range replay {
+		if err := stream.Send(&rpc.Sample{Sample: replay[i]}); err != nil {
+			return err
+		}
+	}
+	ctx := stream.Context()
+	for {
+		select {
+		case u := <-sub.ch:
+			if u.Sample == nil {
+				continue // the service streams samples only
+			}
+			if err := stream.Send(&rpc.Sample{Sample: *u.Sample}); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-g.live.closing:
+			return status.Error(codes.Unavailable, "server shutting down")
+		}
+	}
+}
+
+func (g *grpcService) ReadLog(req *rpc.ReadLogRequest, stream rpc.Infgo_ReadLogServer) error {
+	if g.logPath == "" || g.logPath == stdinPath {
+		return status.Error(codes.FailedPrecondition, "this instance is not recording to a -log file")
+	}
+	if req.ToUnixMs != 0 && req.ToUnixMs < req.FromUnixMs {
+		return status.Errorf(codes.InvalidArgument, "to_unix_ms %d is before from_unix_ms %d", req.ToUnixMs, req.FromUnixMs)
+	}
+	rd, err := syslogger.Open(g.logPath)
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "%v", err)
+	}
+	defer rd.Close()
+
+	ctx := stream.Context()
+	for {
+		if err := ctx.Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+		rec, err := rd.Next()
+		// The capture is still being written, so it may end part-way
+		// through a record; everything before that has been sent.
+		if err == io.EOF || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil
+		}
+		if err != nil {
+			return status.Errorf(codes.DataLoss, "%v", err)
+		}
+		s := rec.Sample
+		if s == nil || s.TimestampUnixMs < req.FromUnixMs {
+			continue
+		}
+		if req.ToUnixMs != 0 && s.TimestampUnixMs > req.ToUnixMs {
+			return nil // a live capture is written in time order
+		}
+		if err := stream.Send(&rpc.Sample{Sample: *s}); err != nil {
+			return err
+		}
+	}
+}
+
+// ── Server lifecycle ──────────────────────────────────────────────────────────
+
+// startGRPC binds cfg.grpcAddr immediately, like startServer, and serves
+// the Infgo service in the background.
+func startGRPC(cfg serveConfig, live *liveState, logPath string) (stop func(), err error) {
+	opts, err := grpcServerOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+	ln, err := net.Listen("tcp", cfg.grpcAddr)
+	if err != nil {
+		return nil, fmt.Errorf("grpc listen %s: %w", cfg.grpcAddr, err)
+	}
+	return serveGRPC(ln, live, logPath, opts...), nil
+}
+
+// serveGRPC serves on ln until the returned stop function is called.  Live
+// streams are ended first so GracefulStop does not wait on them; a slow
+// ReadLog gets a moment to finish before being cut off.
+func serveGRPC(ln net.Listener, live *liveState, logPath string, opts ...grpc.ServerOption) (stop func()) {
+	srv := grpc.NewServer(append([]grpc.ServerOption{grpc.ForceServerCodec(rpc.Codec{})}, opts...)...)
+	rpc.RegisterInfgoServer(srv, &grpcService{live: live, logPath: logPath})
+	go func() { _ = srv.Serve(ln) }()
+
+	return func() {
+		live.closeStreams()
+		done := make(chan struct{})
+		go func() { srv.GracefulStop(); close(done) }()
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			srv.Stop()
+		}
+	}
+}
@@ -0,0 +1,240 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	syslogger "github.com/ALH477/infgo/logger"
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/rpc"
+)
+
+// startBufconn serves the Infgo service over an in-memory listener and
+// returns a connected client.  The server is stopped on test cleanup.
+func startBufconn(t *testing.T, live *liveState, logPath string) rpc.InfgoClient {
+	t.Helper()
+	ln := bufconn.Listen(1 << 20)
+	stop := serveGRPC(ln, live, logPath)
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return ln.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("grpc.NewClient: %v", err)
+	}
+	t.Cleanup(func() { conn.Close(); stop() })
+	return rpc.NewInfgoClient(conn)
+}
+
+func TestGRPCGetInfo(t *testing.T) {
+	live := newLiveState()
+	client := startBufconn(t, live, "")
+	ctx := context.Background()
+
+	if _, err := client.GetInfo(ctx, &rpc.InfoRequest{}); status.Code(err) != codes.Unavailable {
+		t.Errorf("before header: got %v, want Unavailable", err)
+	}
+	want := metrics.Header{Hostname: "node1", Platform: "linux", NumCores: 4, IntervalMs: 500}
+	live.setHeader(want)
+	got, err := client.GetInfo(ctx, &rpc.InfoRequest{})
+	if err != nil {
+		t.Fatalf("GetInfo: %v", err)
+	}
+	if !reflect.DeepEqual(got.Header, want) {
+		t.Errorf("GetInfo: got %+v, want %+v", got.Header, want)
+	}
+}
+
+func TestGRPCStreamLive(t *testing.T) {
+	live := newLiveState()
+	for ts := int64(1000); ts <= 3000; ts += 1000 {
+		live.setSample(metrics.Sample{TimestampUnixMs: ts}, 0)
+	}
+	client := startBufconn(t, live, "")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.StreamLive(ctx, &rpc.LiveRequest{SinceUnixMs: 1000})
+	if err != nil {
+		t.Fatalf("StreamLive: %v", err)
+	}
+	recv := func(want int64) {
+		t.Helper()
+		s, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Recv: %v", err)
+		}
+		if s.TimestampUnixMs != want {
+			t.Errorf("Recv: got sample %d, want %d", s.TimestampUnixMs, want)
+		}
+	}
+
+	// Replay of what was missed, then live samples.
+	recv(2000)
+	recv(3000)
+	waitClients(t, live, 1)
+	live.publishEvent(metrics.Event{TimestampUnixMs: 3500, Kind: "marker"}) // not streamed
+	live.setSample(metrics.Sample{TimestampUnixMs: 4000, CpuCores: []float64{1, 2}}, 0)
+	recv(4000)
+
+	// Cancelling mid-stream ends the server handler and releases its
+	// subscription.
+	cancel()
+	if _, err := stream.Recv(); status.Code(err) != codes.Canceled {
+		t.Errorf("Recv after cancel: got %v, want Canceled", err)
+	}
+	waitClients(t, live, 0)
+}
+
+func TestGRPCStreamLiveShutdown(t *testing.T) {
+	live := newLiveState()
+	ln := bufconn.Listen(1 << 20)
+	stop := serveGRPC(ln, live, "")
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return ln.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("grpc.NewClient: %v", err)
+	}
+	defer conn.Close()
+
+	stream, err := rpc.NewInfgoClient(conn).StreamLive(context.Background(), &rpc.LiveRequest{})
+	if err != nil {
+		t.Fatalf("StreamLive: %v", err)
+	}
+	waitClients(t, live, 1)
+
+	done := make(chan struct{})
+	go func() { stop(); close(done) }()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("stop waited on a live stream")
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.Unavailable {
+		t.Errorf("Recv after stop: got %v, want Unavailable", err)
+	}
+}
+
+// writeTestLog records samples at 1000, 2000, … n*1000 ms, with an event
+// between each, and returns the capture's path.
+func writeTestLog(t *testing.T, n int) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "live.infgo")
+	lgr, err := syslogger.New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := lgr.WriteHeader(metrics.Header{Hostname: "node1", StartedUnixMs: 1000}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= n; i++ {
+		ts := int64(i) * 1000
+		if err := lgr.WriteSample(metrics.Sample{TimestampUnixMs: ts, CpuTotal: float64(i)}); err != nil {
+			t.Fatal(err)
+		}
+		if err := lgr.WriteEvent(metrics.Event{TimestampUnixMs: ts + 500, Kind: "marker"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := lgr.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestGRPCReadLog(t *testing.T) {
+	client := startBufconn(t, newLiveState(), writeTestLog(t, 10))
+
+	tests := []struct {
+		from, to  int64
+		wantFirst int64
+		wantN     int
+	}{
+		{0, 0, 1000, 10},
+		{3000, 0, 3000, 8},
+		{0, 4500, 1000, 4},
+		{2500, 6000, 3000, 4},
+		{20000, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		stream, err := client.ReadLog(context.Background(), &rpc.ReadLogRequest{FromUnixMs: tt.from, ToUnixMs: tt.to})
+		if err != nil {
+			t.Fatalf("ReadLog(%d, %d): %v", tt.from, tt.to, err)
+		}
+		var got []int64
+		for {
+			s, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("ReadLog(%d, %d): Recv: %v", tt.from, tt.to, err)
+			}
+			got = append(got, s.TimestampUnixMs)
+		}
+		if len(got) != tt.wantN || (tt.wantN > 0 && got[0] != tt.wantFirst) {
+			t.Errorf("ReadLog(%d, %d): got %v, want %d samples from %d", tt.from, tt.to, got, tt.wantN, tt.wantFirst)
+		}
+	}
+}
+
+func TestGRPCReadLogCancel(t *testing.T) {
+	client := startBufconn(t, newLiveState(), writeTestLog(t, 5000))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := client.ReadLog(ctx, &rpc.ReadLogRequest{})
+	if err != nil {
+		t.Fatalf("ReadLog: %v", err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatalf("first Recv: %v", err)
+	}
+	cancel()
+	for {
+		_, err := stream.Recv()
+		if err == nil {
+			continue // samples already in flight before the cancel
+		}
+		if status.Code(err) != codes.Canceled {
+			t.Errorf("Recv after cancel: got %v, want Canceled", err)
+		}
+		break
+	}
+}
+
+func TestGRPCReadLogErrors(t *testing.T) {
+	ctx := context.Background()
+	recvErr := func(client rpc.InfgoClient, req *rpc.ReadLogRequest) error {
+		stream, err := client.ReadLog(ctx, req)
+		if err != nil {
+			return err
+		}
+		_, err = stream.Recv()
+		return err
+	}
+
+	noLog := startBufconn(t, newLiveState(), "")
+	if err := recvErr(noLog, &rpc.ReadLogRequest{}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("without -log: got %v, want FailedPrecondition", err)
+	}
+	withLog := startBufconn(t, newLiveState(), writeTestLog(t, 3))
+	if err := recvErr(withLog, &rpc.ReadLogRequest{FromUnixMs: 5000, ToUnixMs: 1000}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("inverted range: got %v, want InvalidArgument", err)
+	}
+}
@@ -0,0 +1,531 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"runtime"
+	"syscall"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	syslogger "github.com/ALH477/infgo/logger"
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ring"
+	"github.com/ALH477/infgo/ui"
+)
+
+// ── Headless collector ────────────────────────────────────────────────────────
+
+// headless is the collector used by `infgo -headless`: the same sampling
+// loop as the TUI, with no terminal attached.  Any sink may be nil.
+type headless struct {
+	logger   *syslogger.Logger
+	live     *liveState
+	pushers  []*pusher
+	alerts   *alertMonitor
+	notifier *alertNotifier
+	idle     *idleDetector
+	pi       *piWatch
+	sd       *sdNotifier
+	control  *controlServer
+	power    *powerWatch
+
+	// gpu polls nvidia-smi on each tick, and gpus is its last poll that
+	// worked, which each sample carries; nil without nvidia-smi.
+	gpu  *gpuWatch
+	gpus []metrics.GpuUsage
+
+	// throttleWatch reads the CPU's thermal throttling, and throttle is
+	// what it last found, each sample's cpu_throttled; nil where it cannot
+	// be read.
+	throttleWatch *throttleWatch
+	throttle      ui.ThrottleState
+
+	// watched delivers the readings the watches take off the loop, so a
+	// slow nvidia-smi or pmset does not hold up the sample.
+	watched chan tea.Msg
+
+	// read takes a reading; reader.read unless a test replaces it.  reader
+	// is localStats unless a test replaces it.
+	read   func(context.Context) ui.Reading
+	reader *statsReader
+
+	// text, if set, gets a plainLine per sample: stdout when it is not a
+	// terminal and no -headless was asked for.
+	text io.Writer
+
+	// stops delivers the terminal's ctrl+z; stopProcess then stops the
+	// collector, and is replaced in tests.
+	stops       <-chan os.Signal
+	stopProcess func() error
+
+	// interval is the time between readings; zero means ui.DefaultInterval.
+	// Faster than MinDisplayInterval, the log is flushed every
+	// MinDisplayInterval rather than after every sample.
+	interval time.Duration
+
+	// logAuto is set when -log auto named the log, which must then be a
+	// new file.
+	logAuto bool
+
+	// logAppend adds to an existing log rather than overwrite it
+	// (-log-append).
+	logAppend bool
+
+	// logSchema embeds metrics.proto after the header of the log, and of
+	// each segment it is rotated into (-log-schema).
+	logSchema bool
+
+	// fingerprint is stamped into the header; nil in tests.
+	fingerprint *fingerprint
+
+	// disk holds the log while its filesystem is short of -min-free.
+	disk *diskGuard
+
+	// collectErrs counts the failures of readings that repeat.
+	collectErrs ui.ErrStreaks
+
+	// CPUTimes is the last CPU times read, which each sample's modes are
+	// taken from.
+	cpuTimes ui.CPUTimes
+
+	// faults takes the page fault rates each sample carries from the
+	// counters read with it.
+	faults ui.FaultMeter
+
+	// The last HistoryLen readings, as the TUI's sparklines would hold
+	// them, for the history command and /api/v1/history.csv.
+	cpuHistory, memHistory ring.Buffer
+	histTrail              ui.HistTrail
+
+	// rotateEvery starts a new log segment on a timer; zero rotates only
+	// on request.  rotated, if set, is called with each closed segment.
+	rotateEvery time.Duration
+	rotated     func(path string)
+
+	// State reported by the control socket's status command.
+	hdr     metrics.Header
+	cores   int // in the latest per-core reading
+	taken   int
+	written int
+	dropped int  // readings the ticker let go by: collection fell behind
+	paused  bool // samples are not written to the log
+	last    metrics.Sample
+	flushed time.Time  // when the log was last flushed
+	piFlags ui.PiFlags // the Pi's throttle flags at the last reading
+	piNext  time.Time  // when they are next read
+}
+
+// run samples every interval until ctx is cancelled.  Each sample is
+// flushed to the log immediately, so a killed collector loses at most one
+// tick and `-log -` consumers see records as they happen.  Sampling faster
+// than MinDisplayInterval, the samples are flushed in batches instead, at
+// most MinDisplayInterval's worth at a time.
+//
+// Under systemd, READY=1 follows the first sample and WATCHDOG=1 is sent
+// from this same loop, so a collector stuck on a write stops petting the
+// watchdog and gets restarted.
+func (h *headless) run(ctx context.Context) error {
+	info := readSysInfo()
+	interval := h.every()
+	hdr := info.Header(time.Now(), runtime.NumCPU(), interval)
+	h.fingerprint.stamp(&hdr)
+	h.hdr = hdr
+	h.cores = int(hdr.NumCores)
+	if h.logger != nil {
+		if err := h.logger.WriteHeader(hdr); err != nil {
+			return fmt.Errorf("write header: %w", err)
+		}
+		if err := h.logger.Flush(); err != nil {
+			return err
+		}
+	}
+	if h.live != nil {
+		h.live.setHeader(hdr)
+	}
+	for _, p := range h.pushers {
+		p.setHeader(hdr)
+	}
+	h.alerts.setHost(hdr.Hostname)
+
+	tick := time.NewTicker(interval)
+	defer tick.Stop()
+	var watchdog <-chan time.Time
+	if iv := sdWatchdogInterval(); h.sd != nil && iv > 0 {
+		wd := time.NewTicker(iv)
+		defer wd.Stop()
+		watchdog = wd.C
+	}
+	var rotate <-chan time.Time
+	if h.rotateEvery > 0 && h.logger != nil {
+		rt := time.NewTicker(h.rotateEvery)
+		defer rt.Stop()
+		rotate = rt.C
+	}
+	if h.watched == nil {
+		h.watched = make(chan tea.Msg, 1)
+	}
+	var lastStatus, lastTick time.Time
+	for {
+		missed := 0
+		select {
+		case <-ctx.Done():
+			h.sd.notify("STOPPING=1")
+			return nil
+		case <-watchdog:
+			h.sd.notify("WATCHDOG=1")
+			continue
+		case now := <-rotate:
+			if _, err := h.rotate(now); err != nil {
+				fmt.Fprintf(os.Stderr, "infgo: rotate: %v\n", err)
+			}
+			continue
+		case req := <-h.control.requests():
+			req.reply <- h.command(req, time.Now())
+			continue
+		case msg := <-h.watched:
+			h.observe(msg)
+			continue
+		case <-h.stops:
+			if err := h.stopJob(); err != nil {
+				return err
+			}
+			// Read at once, and a full interval before the next tick.
+			tick.Reset(interval)
+			select {
+			case <-tick.C:
+			default:
+			}
+			lastTick = time.Time{}
+		case at := <-tick.C:
+			// A Ticker drops the ticks its reader is too slow for.
+			if !lastTick.IsZero() {
+				missed = max(0, int((at.Sub(lastTick)-interval/2)/interval))
+			}
+			lastTick = at
+		}
+
+		h.poll(ctx)
+		sw, slept := h.power.check(time.Now())
+		msg, ok := h.sample(ctx)
+		if !ok {
+			h.sd.notify("STOPPING=1")
+			return nil
+		}
+		if slept {
+			// The reading spans the suspend; log the gap instead.  The
+			// ticks lost to it were not dropped.
+			if err := h.event(sw.event()); err != nil {
+				return fmt.Errorf("write event: %w", err)
+			}
+			continue
+		}
+		h.dropped += missed
+		for _, e := range h.collectErrs.Observe(time.Now(), msg.Missing, msg.Errs) {
+			fmt.Fprintf(os.Stderr, "infgo: %s\n", e.Message)
+			if err := h.event(e); err != nil {
+				return fmt.Errorf("write event: %w", err)
+			}
+		}
+		if msg.Missing.Has(metrics.MissingAll) {
+			continue // nothing could be read; there is nothing to record
+		}
+		s := msg.Sample(time.Now())
+		s.Gpus = h.gpus
+		s.CpuThrottled = h.throttle.Active
+		if msg.HasTimes {
+			if modes, ok := ui.CPUBreakdown(h.cpuTimes, msg.Times); ok {
+				modes.Put(&s)
+			}
+			h.cpuTimes = msg.Times
+		}
+		h.faults.Observe(msg.Faults, msg.FaultsAt)
+		if !msg.FaultsAt.IsZero() && h.faults.OK {
+			h.faults.Put(&s)
+		}
+		if n := len(msg.CPUCores); n > 0 && n != h.cores {
+			e := ui.CoresEvent(s.Time(), h.cores, n, runtime.NumCPU())
+			fmt.Fprintf(os.Stderr, "infgo: %s\n", e.Message)
+			if err := h.event(e); err != nil {
+				return fmt.Errorf("write event: %w", err)
+			}
+			h.cores = n
+		}
+		if e, ok := h.disk.poll(s.Time(), h.logger, h.live); ok {
+			fmt.Fprintf(os.Stderr, "infgo: %s\n", e.Message)
+		}
+		if h.logger != nil && !h.paused && !h.logger.Held() {
+			if err := h.logger.WriteSample(s); err != nil {
+				return fmt.Errorf("write sample: %w", err)
+			}
+			if now := s.Time(); interval >= ui.MinDisplayInterval || now.Sub(h.flushed) >= ui.MinDisplayInterval {
+				if err := h.logger.Flush(); err != nil {
+					return err
+				}
+				h.flushed = now
+			}
+			h.written++
+		}
+		if h.text != nil {
+			if _, err := io.WriteString(h.text, plainLine(s)+"\n"); err != nil {
+				return fmt.Errorf("write: %w", err)
+			}
+		}
+		h.pushHistory(msg, s.Time())
+		if h.live != nil {
+			h.live.setSample(s, msg.Took)
+			h.live.setPoints(h.histTrail.Samples(&h.cpuHistory, &h.memHistory, true))
+		}
+		for _, p := range h.pushers {
+			p.setSample(s)
+		}
+		for _, ev := range h.alerts.observe(s) {
+			recordAlert(ev, h.logger, h.live, h.notifier)
+		}
+		if e, ok := h.idle.observe(s); ok {
+			if err := h.event(e); err != nil {
+				return fmt.Errorf("write event: %w", err)
+			}
+		}
+		if err := h.checkPi(ctx, s.Time()); err != nil {
+			return err
+		}
+
+		h.taken++
+		h.last = s
+		if h.taken == 1 {
+			h.sd.notify("READY=1\nSTATUS=" + h.status())
+			lastStatus = s.Time()
+		} else if s.Time().Sub(lastStatus) >= sdStatusEvery {
+			h.sd.notify("STATUS=" + h.status())
+			lastStatus = s.Time()
+		}
+	}
+}
+
+// stopJob stops the collector for ctrl+z, with the stop marked in the log
+// on either side, and leaves the reading taken straight after it without
+// its CPU delta.
+func (h *headless) stopJob() error {
+	from := time.Now()
+	if err := h.event(ui.StoppedEvent(from)); err != nil {
+		return fmt.Errorf("write event: %w", err)
+	}
+	stop := h.stopProcess
+	if stop == nil {
+		stop = stopProcess
+	}
+	if err := stop(); err != nil {
+		fmt.Fprintf(os.Stderr, "infgo: stop: %v\n", err)
+	}
+	now := time.Now()
+	h.power.restart(now)
+	h.stats().discardCPU()
+	if err := h.event(ui.ContinuedEvent(from, now)); err != nil {
+		return fmt.Errorf("write event: %w", err)
+	}
+	return nil
+}
+
+// checkPi reads a Raspberry Pi's throttle flags, at most every piInterval,
+// and logs the conditions raised or cleared since the last reading.
+func (h *headless) checkPi(ctx context.Context, now time.Time) error {
+	if h.pi == nil || now.Before(h.piNext) {
+		return nil
+	}
+	h.piNext = now.Add(piInterval)
+	st, err := h.pi.read(ctx)
+	if err != nil || !st.HasFlags {
+		return nil
+	}
+	for _, e := range ui.PiEvents(h.piFlags&ui.PiNow, st.Flags&ui.PiNow, now) {
+		fmt.Fprintf(os.Stderr, "infgo: %s\n", e.Message)
+		if err := h.event(e); err != nil {
+			return fmt.Errorf("write event: %w", err)
+		}
+	}
+	h.piFlags = st.Flags
+	return nil
+}
+
+// poll starts the watches that are due off the loop; run hands what they
+// read to observe.
+func (h *headless) poll(ctx context.Context) {
+	var cmds []tea.Cmd
+	if h.gpu != nil {
+		cmds = append(cmds, h.gpu.pollCmd(ctx))
+	}
+	if h.throttleWatch != nil {
+		cmds = append(cmds, h.throttleWatch.readCmd(ctx))
+	}
+	for _, cmd := range cmds {
+		if cmd == nil {
+			continue
+		}
+		go func() {
+			select {
+			case h.watched <- cmd():
+			case <-ctx.Done():
+			}
+		}()
+	}
+}
+
+// observe keeps a reading a watch took for the samples that follow.
+func (h *headless) observe(msg tea.Msg) {
+	switch msg := msg.(type) {
+	case ui.GPUMsg:
+		if msg.GPUs != nil {
+			h.gpus = msg.GPUs
+		}
+	case ui.ThrottleMsg:
+		h.throttle.Observe(msg)
+	}
+}
+
+func (h *headless) every() time.Duration {
+	if h.interval == 0 {
+		return ui.DefaultInterval
+	}
+	return h.interval
+}
+
+func (h *headless) stats() *statsReader {
+	if h.reader == nil {
+		return localStats
+	}
+	return h.reader
+}
+
+// sample takes a reading without holding up shutdown.  If ctx is cancelled
+// while gopsutil is blocked in a syscall, which the context cannot
+// interrupt, the reading is left to finish on its own and its result is
+// dropped; ok is then false.
+func (h *headless) sample(ctx context.Context) (msg ui.Reading, ok bool) {
+	read := h.read
+	if read == nil {
+		read = h.stats().read
+	}
+	done := make(chan ui.Reading, 1)
+	go func() { done <- read(ctx) }()
+	select {
+	case msg := <-done:
+		return msg, true
+	case <-ctx.Done():
+		return ui.Reading{}, false
+	}
+}
+
+// status is the STATUS= line for `systemctl status`.
+func (h *headless) status() string {
+	n, verb := h.taken, "taken"
+	if h.logger != nil {
+		n, verb = h.written, "written"
+	}
+	if h.paused {
+		verb += " (paused)"
+	}
+	out := fmt.Sprintf("%d samples %s; cpu %.1f%%, mem %.1f%%", n, verb, h.last.CpuTotal, h.last.MemPercent)
+	if h.dropped > 0 {
+		out += fmt.Sprintf("; %d dropped", h.dropped)
+	}
+	return out
+}
+
+// rate reports the readings taken against the interval asked for, e.g.
+// "6000 samples in 10m0s (10.0/s at 100ms), 2 dropped".
+func (h *headless) rate(now time.Time) string {
+	span := now.Sub(time.UnixMilli(h.hdr.StartedUnixMs)).Round(time.Second)
+	per := 0.0
+	if span > 0 {
+		per = float64(h.taken) / span.Seconds()
+	}
+	return fmt.Sprintf("%d samples in %v (%.1f/s at %v), %d dropped", h.taken, span, per, h.every(), h.dropped)
+}
+
+// runHeadless wires up the sinks, runs the collector until SIGINT or
+// SIGTERM and shuts everything down.  With `-log -` the capture goes to
+// stdout, so status messages are written to stderr throughout.
+func runHeadless(logPath string, cfg serveConfig, h headless) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	defer closePushers(h.pushers)
+	defer h.notifier.close()
+	defer h.control.close()
+	// A gap of a few readings at a high rate is a slow one, not a suspend.
+	h.power = startPowerWatch(max(h.every(), ui.DefaultInterval))
+	defer h.power.close()
+	stops := make(chan os.Signal, 1)
+	notifyStop(stops)
+	defer signal.Stop(stops)
+	h.stops = stops
+	sd, err := newSDNotifier()
+	if err != nil {
+		return err
+	}
+	defer sd.Close()
+	h.sd = sd
+	if logPath != "" {
+		var (
+			lgr *syslogger.Logger
+			err error
+		)
+		if logPath == stdinPath {
+			lgr, err = syslogger.NewWriter(os.Stdout)
+		} else {
+			lgr, err = openLog(logPath, h.logAuto, h.logAppend)
+		}
+		if err != nil {
+			return fmt.Errorf("open log: %w", err)
+		}
+		if h.logSchema {
+			lgr.EmbedSchema()
+		}
+		defer func() { h.logger.Close() }() // a rotate may have replaced lgr
+		h.logger = lgr
+	}
+	if cfg.enabled() {
+		h.live = newLiveState()
+		h.live.interval = h.every()
+	}
+	if cfg.addr != "" {
+		stopServer, err := startServer(cfg, h.live)
+		if err != nil {
+			return err
+		}
+		defer stopServer()
+		fmt.Fprintf(os.Stderr, "infgo: serving /metrics, /healthz and /api/v1 on %s\n", cfg.addr)
+	}
+	if cfg.grpcAddr != "" {
+		stopGRPC, err := startGRPC(cfg, h.live, logPath)
+		if err != nil {
+			return err
+		}
+		defer stopGRPC()
+		fmt.Fprintf(os.Stderr, "infgo: serving gRPC on %s\n", cfg.grpcAddr)
+	}
+
+	if err := h.run(ctx); err != nil {
+		return err
+	}
+	if h.every() < ui.MinDisplayInterval || h.dropped > 0 {
+		fmt.Fprintf(os.Stderr, "infgo: %s\n", h.rate(time.Now()))
+	}
+	if h.logger != nil {
+		if err := h.logger.Close(); err != nil {
+			return fmt.Errorf("close log: %w", err)
+		}
+		if logPath != stdinPath {
+			fmt.Fprintf(os.Stderr, "infgo: activity log written to %s\n", logPath)
+		}
+	}
+	return nil
+}
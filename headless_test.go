@@ -0,0 +1,143 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	syslogger "github.com/ALH477/infgo/logger"
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ui"
+)
+
+// A quit while the provider is stuck in a syscall must not wait for it.
+func TestHeadlessQuitDuringSlowRead(t *testing.T) {
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	defer close(release)
+	h := &headless{read: func(context.Context) ui.Reading {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-release // ignores ctx, like a read of a hung NFS /proc
+		return ui.Reading{CPUCores: []float64{1}}
+	}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- h.run(ctx) }()
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no reading was started")
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("run still waiting for the reading a second after cancel")
+	}
+	if h.taken != 0 {
+		t.Errorf("got %d samples taken, want the abandoned reading dropped", h.taken)
+	}
+}
+
+func TestFetchStatsDiscardsAfterCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if msg := fetchStats(ctx, new(atomic.Bool), time.Time{})(); msg != nil {
+		t.Errorf("got %T after cancel, want nil", msg)
+	}
+}
+
+// A subsystem that fails is left out of the logged sample, not logged as 0,
+// and a reading in which nothing could be read is not logged at all.
+func TestHeadlessLogsMissingFields(t *testing.T) {
+	var out bytes.Buffer
+	lgr, err := syslogger.NewWriter(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reads := 0
+	h := &headless{logger: lgr, read: func(context.Context) ui.Reading {
+		reads++
+		if reads == 1 {
+			return ui.Reading{CPUTotal: 30, CPUCores: []float64{30}, Load1: 2, Missing: metrics.MissingMem}
+		}
+		cancel()
+		return ui.Reading{Missing: metrics.MissingAll}
+	}}
+	if err := h.run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	lgr.Close()
+
+	rd, err := syslogger.NewReader(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var samples []metrics.Sample
+	for {
+		rec, err := rd.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rec.Sample != nil {
+			samples = append(samples, *rec.Sample)
+		}
+	}
+	if len(samples) != 1 {
+		t.Fatalf("got %d samples, want the one with a reading in it", len(samples))
+	}
+	if s := samples[0]; s.Missing != metrics.MissingMem || s.CpuTotal != 30 || s.Load1 != 2 {
+		t.Errorf("got %+v, want cpu and load with mem missing", s)
+	}
+}
+
+// A change in the number of per-core readings is logged as it happens.
+func TestHeadlessCoreCount(t *testing.T) {
+	var out bytes.Buffer
+	lgr, err := syslogger.NewWriter(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	counts := []int{runtime.NumCPU(), runtime.NumCPU() + 2, runtime.NumCPU() + 2}
+	reads := 0
+	h := &headless{logger: lgr, read: func(context.Context) ui.Reading {
+		n := counts[min(reads, len(counts)-1)]
+		if reads++; reads > len(counts) {
+			cancel()
+		}
+		return ui.Reading{CPUCores: make([]float64, n)}
+	}}
+	if err := h.run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	lgr.Close()
+	events, samples := readLog(t, &out)
+	if len(events) != 1 || events[0].Kind != "cores" {
+		t.Fatalf("got events %+v, want one for the change", events)
+	}
+	if n := len(samples[len(samples)-1].CpuCores); n != counts[2] {
+		t.Errorf("last sample has %d cores, want %d", n, counts[2])
+	}
+}
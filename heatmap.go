@@ -0,0 +1,142 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ALH477/infgo/analysis"
+	"github.com/ALH477/infgo/ui"
+)
+
+// ── analyze -heatmap ──────────────────────────────────────────────────────────
+
+// heatShades are the cell fills of the text heatmap, lightest first; each
+// covers a quarter of the scale.
+var heatShades = []string{"░", "▒", "▓", "█"}
+
+// heatScale is the value a full cell stands for: 100 for percentages,
+// otherwise the highest hourly mean.
+func heatScale(hm *analysis.Heatmap) float64 {
+	if hm.Metric.Unit == "%" {
+		return 100
+	}
+	if hi := hm.Max(); hi > 0 {
+		return hi
+	}
+	return 1
+}
+
+// heatZone names the zone hm was bucketed in, with each abbreviation its
+// days were in, e.g. "local time, EST/EDT".
+func heatZone(hm *analysis.Heatmap, zone timeZone) string {
+	if zone.loc == time.UTC {
+		return "UTC"
+	}
+	var abbrs []string
+	for _, d := range hm.Days {
+		for _, t := range []time.Time{d, d.Add(23 * time.Hour)} {
+			if a := t.Format("MST"); len(abbrs) == 0 || abbrs[len(abbrs)-1] != a {
+				abbrs = append(abbrs, a)
+			}
+		}
+	}
+	return zone.name + ", " + strings.Join(abbrs, "/")
+}
+
+// printHeatmap writes hm as a grid of days by hours of the day, each cell
+// shaded and heat-coloured by its mean, and hours without samples as dim
+// dots.
+func printHeatmap(w io.Writer, hm *analysis.Heatmap, zone timeZone) {
+	scale := heatScale(hm)
+	dim := lipgloss.NewStyle().Foreground(cGray500)
+	fmt.Fprintf(w, "  %s by hour of day  (mean, %s)\n\n", hm.Metric.Label, heatZone(hm, zone))
+	axis := fmt.Sprintf("  %-14s ", "")
+	for h := 0; h < 24; h += 3 {
+		axis += fmt.Sprintf("%-6s", fmt.Sprintf("%02d", h))
+	}
+	fmt.Fprintln(w, strings.TrimRight(axis, " "))
+	for d, row := range hm.Cells {
+		fmt.Fprintf(w, "  %-14s ", hm.Days[d].Format("Mon 2006-01-02"))
+		for _, c := range row {
+			if c.N == 0 {
+				fmt.Fprint(w, dim.Render("· "))
+				continue
+			}
+			pct := 100 * c.Mean / scale
+			shade := heatShades[min(max(int(pct/25), 0), len(heatShades)-1)]
+			fmt.Fprint(w, lipgloss.NewStyle().Foreground(ui.DefaultTheme.LoadColor(pct)).Render(shade+shade))
+		}
+		fmt.Fprintln(w)
+	}
+	prec := 2
+	if hm.Metric.Unit == "%" {
+		prec = 0
+	}
+	fmt.Fprintf(w, "\n  %s no samples", dim.Render("·"))
+	for i, s := range heatShades {
+		fmt.Fprintf(w, "   %s %s–%s", s, ui.FormatNumber(scale*float64(i)/4, prec), ui.FormatNumber(scale*float64(i+1)/4, prec))
+	}
+	fmt.Fprintf(w, " %s\n\n", hm.Metric.Unit)
+}
+
+// Layout of the SVG heatmap, in pixels.
+const (
+	heatCellPx  = 22
+	heatLabelPx = 110 // the day labels' column
+	heatAxisPx  = 20  // the hour labels' row
+)
+
+// writeHeatmapHTML writes hm as a self-contained HTML page around an SVG
+// table of the same cells as printHeatmap, each with a tooltip of its
+// mean and sample count.
+func writeHeatmapHTML(w io.Writer, host string, hm *analysis.Heatmap, zone timeZone) error {
+	title := fmt.Sprintf("%s by hour of day (mean, %s)", hm.Metric.Label, heatZone(hm, zone))
+	var b strings.Builder
+	fmt.Fprintf(&b, "<!DOCTYPE html>\n<html lang=\"en\">\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(&b, "<title>infgo · %s</title>\n</head>\n", html.EscapeString(host))
+	fmt.Fprintf(&b, "<body style=\"font-family: system-ui, sans-serif; color: #111827\">\n")
+	fmt.Fprintf(&b, "<h1>%s</h1>\n<p>%s</p>\n", html.EscapeString(host), html.EscapeString(title))
+	writeHeatmapSVG(&b, hm)
+	fmt.Fprintf(&b, "</body>\n</html>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeHeatmapSVG writes the <svg> element of writeHeatmapHTML.  Cells are
+// coloured like the text heatmap, and more opaque the higher their mean.
+func writeHeatmapSVG(b *strings.Builder, hm *analysis.Heatmap) {
+	scale := heatScale(hm)
+	width := heatLabelPx + 24*heatCellPx
+	height := heatAxisPx + len(hm.Days)*heatCellPx
+	fmt.Fprintf(b, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" font-size=\"11\" role=\"table\">\n", width, height)
+	for h := 0; h < 24; h += 3 {
+		fmt.Fprintf(b, "<text x=\"%d\" y=\"%d\">%02d</text>\n", heatLabelPx+h*heatCellPx+3, heatAxisPx-6, h)
+	}
+	for d, row := range hm.Cells {
+		y := heatAxisPx + d*heatCellPx
+		fmt.Fprintf(b, "<g role=\"row\">\n<text x=\"0\" y=\"%d\" role=\"rowheader\">%s</text>\n",
+			y+heatCellPx-7, hm.Days[d].Format("Mon 2006-01-02"))
+		for h, c := range row {
+			x := heatLabelPx + h*heatCellPx
+			if c.N == 0 {
+				fmt.Fprintf(b, "<circle cx=\"%d\" cy=\"%d\" r=\"1.5\" fill=\"%s\" role=\"cell\"><title>%02d:00 no samples</title></circle>\n",
+					x+heatCellPx/2, y+heatCellPx/2, cGray500, h)
+				continue
+			}
+			pct := 100 * c.Mean / scale
+			opacity := 0.25 + 0.75*min(max(pct/100, 0), 1)
+			fmt.Fprintf(b, "<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"%s\" fill-opacity=\"%.2f\" role=\"cell\"><title>%02d:00 %s %s, %d samples</title></rect>\n",
+				x+1, y+1, heatCellPx-2, heatCellPx-2, ui.DefaultTheme.LoadColor(pct), opacity, h, ui.FormatNumber(c.Mean, 1), html.EscapeString(hm.Metric.Unit), c.N)
+		}
+		fmt.Fprintf(b, "</g>\n")
+	}
+	fmt.Fprintf(b, "</svg>\n")
+}
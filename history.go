@@ -0,0 +1,79 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ring"
+	"github.com/ALH477/infgo/ui"
+)
+
+// ── Sparkline history as CSV ──────────────────────────────────────────────────
+//
+// The control socket's history command and /api/v1/history.csv dump the
+// points on the sparklines, so the last HistoryLen readings can be had
+// without a -log.  Each point carries the time of the tick that made it,
+// as it was observed: a late tick or a gap shows as it happened rather
+// than being back-computed from the interval.
+
+// historyColumns are the fields the histories hold.
+var historyColumns = []csvField{*lookupCSVField("cpu"), *lookupCSVField("mem")}
+
+// historyCSV is points as CSV, in the columns of export csv.
+func historyCSV(points []metrics.Sample) []byte {
+	var buf bytes.Buffer
+	writeCSVColumns(&buf, points, nil, historyColumns) // a bytes.Buffer does not fail
+	return buf.Bytes()
+}
+
+// handleHistoryCSV serves `/api/v1/history.csv[?cores=1]`: the points the
+// sampler last published with setPoints.
+func (l *liveState) handleHistoryCSV(w http.ResponseWriter, r *http.Request) {
+	var cores bool
+	if v := r.URL.Query().Get("cores"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, apiError{"bad cores " + strconv.Quote(v) + ": want true or false"})
+			return
+		}
+		cores = b
+	}
+	l.mu.RLock()
+	points := l.points
+	l.mu.RUnlock()
+	if !cores {
+		points = append([]metrics.Sample(nil), points...)
+		for i := range points {
+			points[i].CpuCores = nil
+		}
+	}
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Write(historyCSV(points))
+}
+
+// pushHistory adds the reading msg, taken at, to the headless collector's
+// histories.  A subsystem that failed repeats its previous point, as on
+// the TUI's sparklines.
+func (h *headless) pushHistory(msg ui.Reading, at time.Time) {
+	if h.cpuHistory.Cap() == 0 {
+		h.cpuHistory, h.memHistory = ring.New(ui.HistoryLen), ring.New(ui.HistoryLen)
+		h.histTrail = ui.NewHistTrail(ui.HistoryLen)
+	}
+	cpu, mem, cores := msg.CPUTotal, msg.MemPercent, msg.CPUCores
+	if msg.Missing.Has(metrics.MissingCPU) {
+		cpu, _ = h.cpuHistory.Last(0)
+		cores = nil
+	}
+	if msg.Missing.Has(metrics.MissingMem) {
+		mem, _ = h.memHistory.Last(0)
+	}
+	h.cpuHistory.Push(cpu)
+	h.memHistory.Push(mem)
+	h.histTrail.Push(at, cores)
+}
@@ -0,0 +1,153 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ring"
+	"github.com/ALH477/infgo/ui"
+)
+
+// The points keep the times they were pushed at, late ticks included,
+// and leave out the ones from before the trail began.
+func TestHistTrail(t *testing.T) {
+	cpu, mem := ring.New(4), ring.New(4)
+	cpu.Fill(0)
+	mem.Fill(0)
+	tr := ui.NewHistTrail(4)
+	t0 := time.UnixMilli(1_700_000_000_000)
+	push := func(at time.Duration, c, m float64, cores []float64) {
+		cpu.Push(c)
+		mem.Push(m)
+		tr.Push(t0.Add(at), cores)
+	}
+	push(0, 10, 50, []float64{10})
+	push(500*time.Millisecond, 20, 51, []float64{20})
+	push(1700*time.Millisecond, 30, 52, []float64{30, 31}) // late, and a core more
+
+	got := tr.Samples(&cpu, &mem, true)
+	if len(got) != 3 {
+		t.Fatalf("got %d points, want 3: %+v", len(got), got)
+	}
+	for i, wantMs := range []int64{0, 500, 1700} {
+		if got[i].TimestampUnixMs != t0.UnixMilli()+wantMs || got[i].CpuTotal != float64(10*(i+1)) || got[i].MemPercent != float64(50+i) {
+			t.Errorf("point %d: got %+v", i, got[i])
+		}
+	}
+	if got[0].CpuCores != nil || got[1].CpuCores != nil || len(got[2].CpuCores) != 2 {
+		t.Errorf("cores: got %v %v %v, want only the point after the change", got[0].CpuCores, got[1].CpuCores, got[2].CpuCores)
+	}
+
+	// A failed CPU reading repeats the cores; the oldest point drops off.
+	push(2*time.Second, 30, 53, nil)
+	push(3*time.Second, 40, 54, []float64{40, 41})
+	got = tr.Samples(&cpu, &mem, false)
+	if len(got) != 4 || got[0].TimestampUnixMs != t0.UnixMilli()+500 || got[3].CpuCores != nil {
+		t.Fatalf("got %+v", got)
+	}
+	if got = tr.Samples(&cpu, &mem, true); got[2].CpuCores[1] != 31 || got[3].CpuCores[1] != 41 {
+		t.Errorf("got cores %v %v", got[2].CpuCores, got[3].CpuCores)
+	}
+	if csv := string(historyCSV(got[3:])); csv != "timestamp,cpu_total,mem_percent,core_0,core_1\n1700000003000,40,54,40,41\n" {
+		t.Errorf("csv: got %q", csv)
+	}
+}
+
+// The TUI publishes its sparklines' points, stamped with their ticks.
+func TestModelHistoryCSV(t *testing.T) {
+	live := newLiveState()
+	var tm tea.Model = ui.New(ui.WithRecorder(&recorder{live: live}))
+	t0 := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i, at := range []time.Duration{0, 500 * time.Millisecond, 1300 * time.Millisecond} {
+		tm, _ = tm.Update(ui.Reading{CPUTotal: float64(i), CPUCores: []float64{1, 2}, MemPercent: 50, At: t0.Add(at)})
+	}
+	srv := httptest.NewServer(newServeMux(live, serveConfig{}))
+	defer srv.Close()
+	get := func(query string) string {
+		t.Helper()
+		resp, err := http.Get(srv.URL + "/api/v1/history.csv" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/csv; charset=utf-8" {
+			t.Errorf("%s: got %d %q", query, resp.StatusCode, resp.Header.Get("Content-Type"))
+		}
+		return string(body)
+	}
+	want := "timestamp,cpu_total,mem_percent\n" +
+		"1772366400000,0,50\n1772366400500,1,50\n1772366401300,2,50\n"
+	if got := get(""); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+	if got := get("?cores=1"); !strings.HasPrefix(got, "timestamp,cpu_total,mem_percent,core_0,core_1\n1772366400000,0,50,1,2\n") {
+		t.Errorf("cores: got\n%s", got)
+	}
+	if resp, err := http.Get(srv.URL + "/api/v1/history.csv?cores=maybe"); err != nil || resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("bad cores: got %v, %v", resp.StatusCode, err)
+	}
+}
+
+// The headless collector answers history on its control socket.
+func TestControlHistory(t *testing.T) {
+	sock := filepath.Join(shortTempDir(t), "infgo.sock")
+	ctl, err := startControl(sock, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ctl.close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reads := 0
+	h := &headless{control: ctl, read: func(context.Context) ui.Reading {
+		reads++
+		msg := ui.Reading{CPUTotal: float64(reads), CPUCores: []float64{1, 2, 3}, MemPercent: 40}
+		if reads == 2 {
+			msg.Missing = metrics.MissingMem
+		}
+		return msg
+	}}
+	done := make(chan error, 1)
+	go func() { done <- h.run(ctx) }()
+
+	deadline := time.Now().Add(5 * time.Second)
+	var rows []string
+	for len(rows) < 4 {
+		reply, err := controlCall(sock, "history cores", 5*time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows = strings.Split(reply, "\n")
+		if time.Now().After(deadline) {
+			t.Fatalf("got %q, want three points", reply)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	if rows[0] != "timestamp,cpu_total,mem_percent,core_0,core_1,core_2" ||
+		!strings.HasSuffix(rows[1], ",1,40,1,2,3") || !strings.HasSuffix(rows[2], ",2,40,1,2,3") {
+		t.Errorf("got rows %q", rows)
+	}
+	if _, err := controlCall(sock, "history all", 5*time.Second); err == nil || !strings.Contains(err.Error(), "usage") {
+		t.Errorf("history all: got %v", err)
+	}
+	if reply, err := controlCall(sock, "status", 5*time.Second); err != nil || !strings.HasPrefix(reply, "samples=") {
+		t.Errorf("status after history: got %q, %v", reply, err)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ALH477/infgo/analysis"
+	"github.com/ALH477/infgo/metrics"
+)
+
+// ── Idle detection ────────────────────────────────────────────────────────────
+
+// Defaults for -idle-floor and -idle-after.
+const (
+	idleFloor = 5.0
+	idleAfter = 2 * time.Minute
+)
+
+// idleDetector marks the stretches of a capture in which the CPU sat below
+// a floor, so that `infgo analyze` can tell a busy machine from an idle
+// one.  The machine goes idle once every reading for hold has been below
+// floor, and is busy again at the first reading at or above it.  A reading
+// exactly at the floor counts as activity.
+//
+// A nil *idleDetector is disabled: observe returns nothing.
+type idleDetector struct {
+	floor float64
+	hold  time.Duration
+
+	quiet time.Time // the first reading of the current run below floor; zero after one at or above it
+	idle  bool
+}
+
+// newIdleDetector returns nil, which is disabled, when hold is not
+// positive.
+func newIdleDetector(floor float64, hold time.Duration) *idleDetector {
+	if hold <= 0 {
+		return nil
+	}
+	return &idleDetector{floor: floor, hold: hold}
+}
+
+// observe feeds one sample to the detector and returns the idle_start or
+// idle_end event it causes, if any.  idle_start is stamped with the first
+// quiet reading, hold before the one that confirms it.  Samples without a
+// CPU reading are skipped.
+func (d *idleDetector) observe(s metrics.Sample) (metrics.Event, bool) {
+	if d == nil || s.Missing.Has(metrics.MissingCPU) {
+		return metrics.Event{}, false
+	}
+	at := s.Time()
+	if s.CpuTotal >= d.floor {
+		d.quiet = time.Time{}
+		if !d.idle {
+			return metrics.Event{}, false
+		}
+		d.idle = false
+		return metrics.Event{TimestampUnixMs: at.UnixMilli(), Kind: analysis.IdleEnd,
+			Message: fmt.Sprintf("busy: cpu %.1f%%", s.CpuTotal)}, true
+	}
+	if d.quiet.IsZero() {
+		d.quiet = at
+	}
+	if d.idle || at.Sub(d.quiet) < d.hold {
+		return metrics.Event{}, false
+	}
+	d.idle = true
+	return metrics.Event{TimestampUnixMs: d.quiet.UnixMilli(), Kind: analysis.IdleStart,
+		Message: fmt.Sprintf("idle: cpu below %.4g%% for %v", d.floor, at.Sub(d.quiet).Round(time.Second))}, true
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ALH477/infgo/analysis"
+	"github.com/ALH477/infgo/metrics"
+)
+
+// idleRun feeds cpu, one reading a second, to a detector with a 5% floor
+// and a 10 s hold, and returns the events as "kind@second".
+func idleRun(cpu []float64) []string {
+	d := newIdleDetector(5, 10*time.Second)
+	t0 := time.Unix(1_700_000_000, 0)
+	var got []string
+	for i, v := range cpu {
+		s := metrics.Sample{TimestampUnixMs: t0.Add(time.Duration(i) * time.Second).UnixMilli(), CpuTotal: v}
+		if e, ok := d.observe(s); ok {
+			got = append(got, e.Kind+"@"+time.UnixMilli(e.TimestampUnixMs).Sub(t0).String())
+		}
+	}
+	return got
+}
+
+// repeat returns n copies of v.
+func repeat(v float64, n int) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = v
+	}
+	return out
+}
+
+func TestIdleDetector(t *testing.T) {
+	cat := func(parts ...[]float64) []float64 {
+		var out []float64
+		for _, p := range parts {
+			out = append(out, p...)
+		}
+		return out
+	}
+	// Flapping between just below and exactly at the floor: every reading
+	// at the floor is activity, so no run is ever long enough.
+	var flap []float64
+	for i := 0; i < 60; i++ {
+		flap = append(flap, 4.99, 5)
+	}
+	tests := []struct {
+		name string
+		cpu  []float64
+		want []string
+	}{
+		{"busy", repeat(50, 30), nil},
+		{"short lull", cat(repeat(50, 3), repeat(1, 10), repeat(50, 3)), nil},
+		{"idle", cat(repeat(50, 3), repeat(1, 11), repeat(50, 1)),
+			[]string{analysis.IdleStart + "@3s", analysis.IdleEnd + "@14s"}},
+		{"idle to the end", cat(repeat(1, 30)), []string{analysis.IdleStart + "@0s"}},
+		{"flapping at the floor", flap, nil},
+		{"a reading at the floor ends idle", cat(repeat(4.99, 11), []float64{5}, repeat(4.99, 11)),
+			[]string{analysis.IdleStart + "@0s", analysis.IdleEnd + "@11s", analysis.IdleStart + "@12s"}},
+		{"idle holds below the floor", cat(repeat(4.99, 11), repeat(0, 30), repeat(4.9, 30)),
+			[]string{analysis.IdleStart + "@0s"}},
+	}
+	for _, tt := range tests {
+		got := idleRun(tt.cpu)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+// A reading without a CPU value neither breaks nor extends a quiet run.
+func TestIdleDetectorMissingCPU(t *testing.T) {
+	d := newIdleDetector(5, 10*time.Second)
+	t0 := time.Unix(1_700_000_000, 0)
+	for i := 0; i <= 10; i++ {
+		s := metrics.Sample{TimestampUnixMs: t0.Add(time.Duration(i) * time.Second).UnixMilli(), CpuTotal: 1}
+		if i == 5 {
+			s.CpuTotal, s.Missing = 80, metrics.MissingCPU
+		}
+		if e, ok := d.observe(s); ok != (i == 10) {
+			t.Fatalf("reading %d: got %v (%v)", i, ok, e)
+		}
+	}
+	if newIdleDetector(5, 0) != nil {
+		t.Error("-idle-after 0: got a detector, want it disabled")
+	}
+}
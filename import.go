@@ -0,0 +1,595 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ALH477/infgo/analysis"
+	syslogger "github.com/ALH477/infgo/logger"
+	"github.com/ALH477/infgo/metrics"
+)
+
+// ── import ────────────────────────────────────────────────────────────────────
+
+// importers lists the foreign formats `infgo import <format>` understands.
+var importers = []subcommand{
+	{"csv", "convert a CSV table of samples", runImportCSV},
+	{"pbstream", "convert a length-delimited protobuf stream", runImportPbstream},
+}
+
+// runImport implements `infgo import <format> [args]`.
+func runImport(args []string) error {
+	return runFormat("import", importers, args)
+}
+
+// ── import csv ────────────────────────────────────────────────────────────────
+
+// csvField is a Sample field that can be filled from a CSV column, and
+// that `infgo export csv` writes to it.
+type csvField struct {
+	key    string // name used on the left of -map
+	column string // column read when -map does not mention key
+	set    func(s *metrics.Sample, v float64)
+	get    func(s *metrics.Sample) float64
+	group  metrics.Missing // the group the field is part of
+}
+
+// csvTimestampKey is the -map key of the mandatory timestamp column.
+const csvTimestampKey = "ts"
+
+// csvFields lists the importable fields.  The default column names match the
+// Sample field names in snake case.
+var csvFields = []csvField{
+	{"cpu", "cpu_total", func(s *metrics.Sample, v float64) { s.CpuTotal = v },
+		func(s *metrics.Sample) float64 { return s.CpuTotal }, metrics.MissingCPU},
+	{"mem", "mem_percent", func(s *metrics.Sample, v float64) { s.MemPercent = v },
+		func(s *metrics.Sample) float64 { return s.MemPercent }, metrics.MissingMem},
+	{"mem_used", "mem_used_gb", func(s *metrics.Sample, v float64) { s.MemUsedGB = v },
+		func(s *metrics.Sample) float64 { return s.MemUsedGB }, metrics.MissingMem},
+	{"mem_total", "mem_total_gb", func(s *metrics.Sample, v float64) { s.MemTotalGB = v },
+		func(s *metrics.Sample) float64 { return s.MemTotalGB }, metrics.MissingMem},
+	{"load1", "load1", func(s *metrics.Sample, v float64) { s.Load1 = v },
+		func(s *metrics.Sample) float64 { return s.Load1 }, metrics.MissingLoad},
+	{"load5", "load5", func(s *metrics.Sample, v float64) { s.Load5 = v },
+		func(s *metrics.Sample) float64 { return s.Load5 }, metrics.MissingLoad},
+	{"load15", "load15", func(s *metrics.Sample, v float64) { s.Load15 = v },
+		func(s *metrics.Sample) float64 { return s.Load15 }, metrics.MissingLoad},
+}
+
+// maxReportedRows bounds how many malformed rows are listed individually.
+const maxReportedRows = 5
+
+// runImportCSV implements `infgo import csv <data.csv|-> -o <out.infgo>`.
+func runImportCSV(args []string) error {
+	fs := newFlagSet("import csv", "<data.csv|-> -o <out.infgo> [-map 'ts=COL,cpu=COL,...'] [-host NAME]")
+	out := fs.String("o", "", "write the capture to `file`")
+	mapSpec := fs.String("map", "", "comma-separated `key=column` pairs; keys: ts, "+csvFieldKeys())
+	host := fs.String("host", "imported", "hostname recorded in the synthesized header")
+	layout := fs.String("time-layout", "", "timestamp `layout`, strptime-style (%Y-%m-%d %H:%M:%S) or Go reference time; default auto-detects unix s/ms and RFC3339")
+	cores := fs.String("cores", "core_*", "glob `pattern` selecting per-core CPU columns")
+	delim := fs.String("delim", ",", "field delimiter; use \\t for tab")
+
+	pos, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 1 {
+		return usageErrorf(fs, "expected exactly one input CSV file")
+	}
+	if *out == "" {
+		return usageErrorf(fs, "-o is required")
+	}
+	opts := csvOptions{CorePattern: *cores, Loc: time.Local}
+	if opts.Mapping, err = parseColumnMap(*mapSpec); err != nil {
+		return usageErrorf(fs, "-map: %v", err)
+	}
+	if *layout != "" {
+		if opts.Layout, err = goTimeLayout(*layout); err != nil {
+			return usageErrorf(fs, "-time-layout: %v", err)
+		}
+	}
+	if _, err := path.Match(*cores, ""); err != nil {
+		return usageErrorf(fs, "-cores: %v", err)
+	}
+	delimRunes := []rune(strings.ReplaceAll(*delim, `\t`, "\t"))
+	if len(delimRunes) != 1 {
+		return usageErrorf(fs, "-delim must be a single character")
+	}
+	opts.Comma = delimRunes[0]
+
+	in := io.Reader(os.Stdin)
+	if pos[0] != stdinPath {
+		f, err := os.Open(pos[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	res, err := readCSVSamples(in, opts)
+	if err != nil {
+		return fmt.Errorf("%s: %w", inputName(pos[0]), err)
+	}
+	for _, msg := range res.Errors {
+		fmt.Fprintf(os.Stderr, "infgo import csv: skipped %s\n", msg)
+	}
+	if more := res.Skipped - len(res.Errors); more > 0 {
+		fmt.Fprintf(os.Stderr, "infgo import csv: … and %d more malformed rows\n", more)
+	}
+	if len(res.Samples) == 0 {
+		return fmt.Errorf("%s: no valid rows (%d skipped)", inputName(pos[0]), res.Skipped)
+	}
+
+	hdr := metrics.Header{
+		Hostname:      *host,
+		Platform:      "imported from CSV",
+		StartedUnixMs: res.Samples[0].TimestampUnixMs,
+		NumCores:      int32(res.Cores),
+		IntervalMs:    analysis.MedianInterval(res.Samples).Milliseconds(),
+	}
+	if err := writeCapture(*out, hdr, res.Samples); err != nil {
+		_ = os.Remove(*out)
+		return err
+	}
+	fmt.Printf("infgo: imported %d samples (%d skipped) → %s\n", len(res.Samples), res.Skipped, *out)
+	return nil
+}
+
+// csvFieldKeys lists the -map keys of csvFields for help text.
+func csvFieldKeys() string {
+	keys := make([]string, len(csvFields))
+	for i, f := range csvFields {
+		keys[i] = f.key
+	}
+	return strings.Join(keys, ", ")
+}
+
+// parseColumnMap parses "ts=timestamp,cpu=cpu_pct" into key → column.
+func parseColumnMap(spec string) (map[string]string, error) {
+	m := map[string]string{}
+	if strings.TrimSpace(spec) == "" {
+		return m, nil
+	}
+	for _, pair := range strings.Split(spec, ",") {
+		key, col, ok := strings.Cut(pair, "=")
+		key, col = strings.TrimSpace(key), strings.TrimSpace(col)
+		if !ok || key == "" || col == "" {
+			return nil, fmt.Errorf("%q is not key=column", pair)
+		}
+		if key != csvTimestampKey && lookupCSVField(key) == nil {
+			return nil, fmt.Errorf("unknown key %q (want ts, %s)", key, csvFieldKeys())
+		}
+		if _, dup := m[key]; dup {
+			return nil, fmt.Errorf("key %q mapped twice", key)
+		}
+		m[key] = col
+	}
+	return m, nil
+}
+
+func lookupCSVField(key string) *csvField {
+	for i := range csvFields {
+		if csvFields[i].key == key {
+			return &csvFields[i]
+		}
+	}
+	return nil
+}
+
+// csvOptions controls readCSVSamples.
+type csvOptions struct {
+	Mapping     map[string]string // -map overrides, key → column
+	Layout      string            // Go time layout; "" auto-detects
+	Loc         *time.Location    // zone for layouts without an offset
+	CorePattern string            // glob selecting per-core columns
+	Comma       rune
+}
+
+// csvResult is what readCSVSamples recovered from a table.
+type csvResult struct {
+	Samples []metrics.Sample // sorted by timestamp
+	Cores   int              // number of per-core columns found
+	Skipped int              // malformed rows
+	Errors  []string         // the first few malformed rows, with line numbers
+}
+
+// readCSVSamples converts a CSV table with a header row into Samples.
+// Malformed rows are counted and skipped rather than aborting the import;
+// only a missing timestamp column or an unreadable header is fatal.
+func readCSVSamples(r io.Reader, opts csvOptions) (*csvResult, error) {
+	cr := csv.NewReader(r)
+	if opts.Comma != 0 {
+		cr.Comma = opts.Comma
+	}
+	cr.FieldsPerRecord = -1 // ragged rows are reported per line below
+	cr.TrimLeadingSpace = true
+	cr.ReuseRecord = true
+
+	head, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header row: %w", err)
+	}
+	head = append([]string(nil), head...) // ReuseRecord would overwrite it
+	index := make(map[string]int, len(head))
+	for i, name := range head {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		head[i] = name
+		if _, dup := index[name]; !dup {
+			index[name] = i
+		}
+	}
+	column := func(key, def string) (int, bool, error) {
+		if col, ok := opts.Mapping[key]; ok {
+			i, found := index[col]
+			if !found {
+				return 0, false, fmt.Errorf("column %q (mapped to %s) not in header", col, key)
+			}
+			return i, true, nil
+		}
+		i, found := index[def]
+		return i, found, nil
+	}
+
+	tsCol, ok, err := column(csvTimestampKey, "timestamp")
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New(`no "timestamp" column; name it with -map ts=COLUMN`)
+	}
+	type binding struct {
+		col int
+		f   *csvField
+	}
+	var binds []binding
+	for i := range csvFields {
+		f := &csvFields[i]
+		c, ok, err := column(f.key, f.column)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			binds = append(binds, binding{c, f})
+		}
+	}
+	coreCols := matchCoreColumns(head, opts.CorePattern)
+
+	res := &csvResult{Cores: len(coreCols)}
+	skip := func(line int, format string, a ...any) {
+		res.Skipped++
+		if len(res.Errors) < maxReportedRows {
+			res.Errors = append(res.Errors, fmt.Sprintf("line %d: %s", line, fmt.Sprintf(format, a...)))
+		}
+	}
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var perr *csv.ParseError
+			if errors.As(err, &perr) {
+				skip(perr.Line, "%v", perr.Err)
+				continue
+			}
+			return nil, err
+		}
+		line, _ := cr.FieldPos(0)
+		if len(rec) != len(head) {
+			skip(line, "%d fields, header has %d", len(rec), len(head))
+			continue
+		}
+
+		ts, err := parseCSVTime(rec[tsCol], opts.Layout, opts.Loc)
+		if err != nil {
+			skip(line, "timestamp: %v", err)
+			continue
+		}
+		s := metrics.Sample{TimestampUnixMs: ts.UnixMilli()}
+		bad := false
+		for _, b := range binds {
+			v, err := parseCSVNumber(rec[b.col])
+			if err != nil {
+				skip(line, "%s: %v", head[b.col], err)
+				bad = true
+				break
+			}
+			b.f.set(&s, v)
+		}
+		if !bad && len(coreCols) > 0 {
+			s.CpuCores = make([]float64, len(coreCols))
+			for i, c := range coreCols {
+				v, err := parseCSVNumber(rec[c])
+				if err != nil {
+					skip(line, "%s: %v", head[c], err)
+					bad = true
+					break
+				}
+				s.CpuCores[i] = v
+			}
+		}
+		if !bad {
+			res.Samples = append(res.Samples, s)
+		}
+	}
+	sort.SliceStable(res.Samples, func(i, j int) bool {
+		return res.Samples[i].TimestampUnixMs < res.Samples[j].TimestampUnixMs
+	})
+	return res, nil
+}
+
+// matchCoreColumns returns the indices of the header columns matching
+// pattern, ordered by their numeric suffix when they have one (core_2
+// before core_10) and by position otherwise.
+func matchCoreColumns(head []string, pattern string) []int {
+	var cols []int
+	for i, name := range head {
+		if ok, _ := path.Match(pattern, name); ok {
+			cols = append(cols, i)
+		}
+	}
+	suffix := func(name string) (int, bool) {
+		i := strings.LastIndexFunc(name, func(r rune) bool { return r < '0' || r > '9' })
+		n, err := strconv.Atoi(name[i+1:])
+		return n, err == nil
+	}
+	sort.SliceStable(cols, func(a, b int) bool {
+		na, oka := suffix(head[cols[a]])
+		nb, okb := suffix(head[cols[b]])
+		return oka && okb && na < nb
+	})
+	return cols
+}
+
+// parseCSVNumber parses a cell; a trailing "%" is allowed and an empty cell
+// reads as zero.
+func parseCSVNumber(cell string) (float64, error) {
+	cell = strings.TrimSuffix(strings.TrimSpace(cell), "%")
+	if cell == "" {
+		return 0, nil
+	}
+	v, err := strconv.ParseFloat(cell, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", cell)
+	}
+	return v, nil
+}
+
+// unixMsThreshold separates unix seconds from unix milliseconds: 1e11
+// seconds is the year 5138, while 1e11 ms is early 1973.
+const unixMsThreshold = 1e11
+
+// parseCSVTime parses a timestamp cell.  With an explicit layout only that
+// layout is tried; otherwise the cell may be unix seconds (fractions
+// allowed), unix milliseconds, RFC3339, or "2006-01-02 15:04:05" in loc.
+func parseCSVTime(cell, layout string, loc *time.Location) (time.Time, error) {
+	cell = strings.TrimSpace(cell)
+	if layout != "" {
+		return time.ParseInLocation(layout, cell, loc)
+	}
+	if v, err := strconv.ParseFloat(cell, 64); err == nil && !math.IsNaN(v) && !math.IsInf(v, 0) {
+		if v >= unixMsThreshold {
+			return time.UnixMilli(int64(v)), nil
+		}
+		return time.UnixMilli(int64(v * 1000)), nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, cell); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02 15:04:05", cell, loc); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("%q is not unix time, RFC3339, or YYYY-MM-DD HH:MM:SS; set -time-layout", cell)
+}
+
+// strptimeDirectives maps strptime conversions to Go layout elements.
+var strptimeDirectives = map[byte]string{
+	'Y': "2006", 'y': "06", 'm': "01", 'd': "02", 'e': "_2", 'j': "002",
+	'H': "15", 'I': "03", 'M': "04", 'S': "05", 'p': "PM", 'f': "000000",
+	'b': "Jan", 'h': "Jan", 'B': "January", 'a': "Mon", 'A': "Monday",
+	'z': "-0700", 'Z': "MST", 'F': "2006-01-02", 'T': "15:04:05", '%': "%",
+}
+
+// goTimeLayout converts a strptime-style layout ("%Y-%m-%d %H:%M:%S") into a
+// Go layout.  Strings without any % are taken as Go layouts already.
+func goTimeLayout(spec string) (string, error) {
+	if !strings.Contains(spec, "%") {
+		return spec, nil
+	}
+	var sb strings.Builder
+	for i := 0; i < len(spec); i++ {
+		if spec[i] != '%' {
+			sb.WriteByte(spec[i])
+			continue
+		}
+		if i+1 == len(spec) {
+			return "", errors.New("trailing %")
+		}
+		i++
+		elem, ok := strptimeDirectives[spec[i]]
+		if !ok {
+			return "", fmt.Errorf("unsupported directive %%%c", spec[i])
+		}
+		sb.WriteString(elem)
+	}
+	return sb.String(), nil
+}
+
+// writeCapture writes hdr followed by samples as a new capture at path.
+func writeCapture(path string, hdr metrics.Header, samples []metrics.Sample) (err error) {
+	lgr, err := syslogger.New(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := lgr.Close(); err == nil {
+			err = cerr
+		}
+	}()
+	if err := lgr.WriteHeader(hdr); err != nil {
+		return err
+	}
+	for _, s := range samples {
+		if err := lgr.WriteSample(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ── import pbstream ───────────────────────────────────────────────────────────
+
+// runImportPbstream implements the inverse of `infgo export pbstream`.
+func runImportPbstream(args []string) error {
+	fs := newFlagSet("import pbstream", "<samples.pb|-> -o <out.infgo> [-with-header] [-header <header.pb>] [-host NAME]")
+	out := fs.String("o", "", "write the capture to `file`")
+	withHeader := fs.Bool("with-header", false, "the stream holds Record envelopes rather than bare Samples")
+	headerIn := fs.String("header", "", "read the Header message from `file`")
+	host := fs.String("host", "imported", "hostname for the synthesized header when the input carries none")
+
+	pos, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 1 {
+		return usageErrorf(fs, "expected exactly one input stream")
+	}
+	if *out == "" {
+		return usageErrorf(fs, "-o is required")
+	}
+
+	var hdr *metrics.Header
+	if *headerIn != "" {
+		b, err := os.ReadFile(*headerIn)
+		if err != nil {
+			return err
+		}
+		h, err := metrics.UnmarshalHeader(b)
+		if err != nil {
+			return fmt.Errorf("%s: %w", *headerIn, err)
+		}
+		hdr = &h
+	}
+
+	in := io.Reader(os.Stdin)
+	if pos[0] != stdinPath {
+		f, err := os.Open(pos[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	st, err := importPbstream(bufio.NewReaderSize(in, 64*1024), *out, *withHeader, hdr, *host)
+	if err != nil {
+		_ = os.Remove(*out)
+		return fmt.Errorf("%s: %w", inputName(pos[0]), err)
+	}
+	msg := fmt.Sprintf("infgo: imported %d samples, %d events", st.samples, st.events)
+	if st.processes > 0 {
+		msg += fmt.Sprintf(", %d process records", st.processes)
+	}
+	fmt.Println(msg + " → " + *out)
+	return nil
+}
+
+// importPbstream converts a delimited stream into a capture at dst.  hdr,
+// when non-nil, takes precedence over any header in the stream; without
+// either, a minimal header is synthesized from host and the first sample.
+func importPbstream(r *bufio.Reader, dst string, envelopes bool, hdr *metrics.Header, host string) (st pbstreamStats, err error) {
+	lgr, err := syslogger.New(dst)
+	if err != nil {
+		return st, err
+	}
+	defer func() {
+		if cerr := lgr.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	// The header must be the first record, so it is written lazily once the
+	// first sample or event shows what is known about the session.
+	wroteHeader := false
+	ensureHeader := func(firstMs int64, cores int) error {
+		if wroteHeader {
+			return nil
+		}
+		wroteHeader = true
+		h := metrics.Header{Hostname: host, StartedUnixMs: firstMs, NumCores: int32(cores)}
+		if hdr != nil {
+			h = *hdr
+		}
+		return lgr.WriteHeader(h)
+	}
+
+	for n := 1; ; n++ {
+		msg, err := metrics.ReadDelimited(r)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return st, fmt.Errorf("message %d: %w", n, err)
+		}
+		var env metrics.Envelope
+		if envelopes {
+			if env, err = metrics.UnmarshalEnvelope(msg); err != nil {
+				return st, fmt.Errorf("message %d: %w", n, err)
+			}
+		} else {
+			s, err := metrics.UnmarshalSample(msg)
+			if err != nil {
+				return st, fmt.Errorf("message %d: %w", n, err)
+			}
+			env.Sample = &s
+		}
+
+		switch {
+		case env.Header != nil:
+			st.headers++
+			if hdr == nil && !wroteHeader {
+				hdr = env.Header
+			}
+		case env.Sample != nil:
+			if err := ensureHeader(env.Sample.TimestampUnixMs, len(env.Sample.CpuCores)); err != nil {
+				return st, err
+			}
+			if err := lgr.WriteSample(*env.Sample); err != nil {
+				return st, err
+			}
+			st.samples++
+		case env.Event != nil:
+			if err := ensureHeader(env.Event.TimestampUnixMs, 0); err != nil {
+				return st, err
+			}
+			if err := lgr.WriteEvent(*env.Event); err != nil {
+				return st, err
+			}
+			st.events++
+		case env.Process != nil:
+			if err := ensureHeader(env.Process.TimestampUnixMs, 0); err != nil {
+				return st, err
+			}
+			if err := lgr.WriteProcess(*env.Process); err != nil {
+				return st, err
+			}
+			st.processes++
+		}
+	}
+	if st.samples == 0 {
+		return st, errors.New("stream contains no samples")
+	}
+	return st, nil
+}
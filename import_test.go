@@ -0,0 +1,99 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadCSVSamples(t *testing.T) {
+	const data = `time,cpu_pct,mem_pct,core_10,core_2,core_1,note
+1704067202,30%,50,10,2,1,x
+1704067200,10,40,10,2,1,y
+1704067201,bogus,45,10,2,1,z
+1704067203,40,55,10,2
+not-a-time,50,60,10,2,1,w
+1704067204,50,60,10,2,1,v
+`
+	mapping, err := parseColumnMap("ts=time, cpu=cpu_pct, mem=mem_pct")
+	if err != nil {
+		t.Fatalf("parseColumnMap failed: %v", err)
+	}
+	res, err := readCSVSamples(strings.NewReader(data), csvOptions{
+		Mapping: mapping, CorePattern: "core_*", Loc: time.UTC,
+	})
+	if err != nil {
+		t.Fatalf("readCSVSamples failed: %v", err)
+	}
+
+	if len(res.Samples) != 3 || res.Skipped != 3 {
+		t.Fatalf("got %d samples, %d skipped; want 3 and 3", len(res.Samples), res.Skipped)
+	}
+	if first := res.Samples[0]; first.TimestampUnixMs != 1704067200000 || first.CpuTotal != 10 || first.MemPercent != 40 {
+		t.Errorf("first sample (after sorting): got %+v", first)
+	}
+	if got := res.Samples[1].CpuTotal; got != 30 {
+		t.Errorf("percent-suffixed cell: got %v, want 30", got)
+	}
+	if res.Cores != 3 {
+		t.Errorf("cores: got %d, want 3", res.Cores)
+	}
+	if got := res.Samples[0].CpuCores; len(got) != 3 || got[0] != 1 || got[1] != 2 || got[2] != 10 {
+		t.Errorf("cores should be ordered by number: got %v", got)
+	}
+	wantLines := []string{"line 4:", "line 5:", "line 6:"}
+	for i, want := range wantLines {
+		if i >= len(res.Errors) || !strings.HasPrefix(res.Errors[i], want) {
+			t.Errorf("error %d: got %q, want prefix %q", i, res.Errors, want)
+		}
+	}
+}
+
+func TestReadCSVSamplesMissingColumn(t *testing.T) {
+	_, err := readCSVSamples(strings.NewReader("when,cpu_total\n1,2\n"), csvOptions{})
+	if err == nil || !strings.Contains(err.Error(), "timestamp") {
+		t.Errorf("missing timestamp column: got %v", err)
+	}
+	mapping, _ := parseColumnMap("cpu=nope")
+	_, err = readCSVSamples(strings.NewReader("timestamp,cpu_total\n1,2\n"), csvOptions{Mapping: mapping})
+	if err == nil || !strings.Contains(err.Error(), `"nope"`) {
+		t.Errorf("mapped column absent: got %v", err)
+	}
+}
+
+func TestParseCSVTime(t *testing.T) {
+	want := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		cell, layout string
+	}{
+		{"1704067200", ""},
+		{"1704067200000", ""},
+		{"1704067200.000", ""},
+		{"2024-01-01T00:00:00Z", ""},
+		{"2024-01-01T01:00:00+01:00", ""},
+		{"2024-01-01 00:00:00", ""},
+		{"01/01/2024 00:00", "%d/%m/%Y %H:%M"},
+		{"2024-01-01 00:00:00.000000", "%F %T.%f"},
+		{"Jan  1 2024", "Jan _2 2006"},
+	}
+	for _, tt := range tests {
+		layout, err := goTimeLayout(tt.layout)
+		if err != nil {
+			t.Fatalf("goTimeLayout(%q) failed: %v", tt.layout, err)
+		}
+		got, err := parseCSVTime(tt.cell, layout, time.UTC)
+		if err != nil {
+			t.Errorf("parseCSVTime(%q, %q): %v", tt.cell, tt.layout, err)
+			continue
+		}
+		if !got.Equal(want) {
+			t.Errorf("parseCSVTime(%q, %q): got %v, want %v", tt.cell, tt.layout, got, want)
+		}
+	}
+	if _, err := goTimeLayout("%Q"); err == nil {
+		t.Error("goTimeLayout accepted an unknown directive")
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// ── InfluxDB v2 push (-influx-url) ────────────────────────────────────────────
+
+const (
+	// influxFlushEvery and influxBatchPoints bound a batch: it is sent when
+	// either is reached, whichever comes first.
+	influxFlushEvery  = 10 * time.Second
+	influxBatchPoints = 100
+
+	// influxAttempts is how many times a batch is sent before it is
+	// dropped; retries back off from influxRetryMin up to influxRetryMax,
+	// or wait as long as a 429's Retry-After asks.
+	influxAttempts = 5
+	influxRetryMin = time.Second
+	influxRetryMax = 30 * time.Second
+
+	influxTimeout = 10 * time.Second
+)
+
+// influxConfig holds the -influx-* flags.
+type influxConfig struct {
+	url, token, org, bucket string
+}
+
+func (c influxConfig) enabled() bool { return c.url != "" }
+
+// influxWriter batches samples as line protocol and writes them to an
+// InfluxDB v2 /api/v2/write endpoint.
+type influxWriter struct {
+	endpoint string // full write URL, including org, bucket and precision
+	token    string
+	client   *http.Client
+
+	// Batching and retry policy; the influx* constants outside tests.
+	flushEvery  time.Duration
+	batchPoints int
+	attempts    int
+	retryMin    time.Duration
+	retryMax    time.Duration
+
+	host     string
+	buf      []byte // pending lines
+	points   int    // lines in buf
+	samples  int    // samples in buf
+	lost     atomic.Int64
+	lastFail atomic.Value // string: why the last batch was dropped
+}
+
+// newInfluxWriter validates the -influx-* flags.
+func newInfluxWriter(cfg influxConfig) (*influxWriter, error) {
+	u, err := url.Parse(cfg.url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("-influx-url: want http(s)://host:port, got %q", cfg.url)
+	}
+	if cfg.org == "" || cfg.bucket == "" {
+		return nil, errors.New("-influx-url needs -influx-org and -influx-bucket")
+	}
+	u.Path = strings.TrimRight(u.Path, "/") + "/api/v2/write"
+	u.RawQuery = url.Values{
+		"org":       {cfg.org},
+		"bucket":    {cfg.bucket},
+		"precision": {"ms"},
+	}.Encode()
+	return &influxWriter{
+		endpoint:    u.String(),
+		token:       cfg.token,
+		client:      &http.Client{Timeout: influxTimeout},
+		flushEvery:  influxFlushEvery,
+		batchPoints: influxBatchPoints,
+		attempts:    influxAttempts,
+		retryMin:    influxRetryMin,
+		retryMax:    influxRetryMax,
+	}, nil
+}
+
+func (w *influxWriter) dropped() int64 { return w.lost.Load() }
+
+func (w *influxWriter) lastError() string {
+	s, _ := w.lastFail.Load().(string)
+	return s
+}
+
+func (w *influxWriter) run(in <-chan pushRecord) {
+	tick := time.NewTicker(w.flushEvery)
+	defer tick.Stop()
+	for {
+		select {
+		case rec, ok := <-in:
+			if !ok {
+				w.flush(true)
+				return
+			}
+			if rec.header != nil {
+				w.host = rec.header.Hostname
+				continue
+			}
+			before := len(w.buf)
+			w.buf = metrics.AppendLineProtocol(w.buf, w.host, &rec.sample)
+			w.points += bytes.Count(w.buf[before:], []byte{'\n'})
+			w.samples++
+			if w.points >= w.batchPoints {
+				w.flush(false)
+			}
+		case <-tick.C:
+			w.flush(false)
+		}
+	}
+}
+
+// flush sends the pending batch, retrying transient failures, and drops
+// it if that does not succeed.  The final flush on shutdown is tried once,
+// so quitting never waits out the backoff.
+func (w *influxWriter) flush(final bool) {
+	if w.points == 0 {
+		return
+	}
+	body := gzipBytes(w.buf)
+	var err error
+	delay := w.retryMin
+	for attempt := 1; ; attempt++ {
+		var retryAfter time.Duration
+		retryAfter, err = w.post(body)
+		if err == nil || retryAfter < 0 || attempt >= w.attempts || final {
+			break
+		}
+		if retryAfter > 0 {
+			delay = retryAfter
+		}
+		time.Sleep(delay)
+		delay = min(delay*2, w.retryMax)
+	}
+	if err != nil {
+		w.lost.Add(int64(w.samples))
+		w.lastFail.Store(err.Error())
+	}
+	w.buf, w.points, w.samples = w.buf[:0], 0, 0
+}
+
+// post makes one write request.  retryAfter is negative when the failure
+// is permanent (a 4xx other than 429), and positive when the server asked
+// for a specific delay.
+func (w *influxWriter) post(body []byte) (retryAfter time.Duration, err error) {
+	req, err := http.NewRequest(http.MethodPost, w.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return -1, err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	req.Header.Set("Content-Encoding", "gzip")
+	if w.token != "" {
+		req.Header.Set("Authorization", "Token "+w.token)
+	}
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	switch {
+	case resp.StatusCode/100 == 2:
+		return 0, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode/100 == 5:
+		secs, _ := strconv.Atoi(resp.Header.Get("Retry-After"))
+		return time.Duration(secs) * time.Second, fmt.Errorf("influx: %s", resp.Status)
+	default:
+		return -1, fmt.Errorf("influx: %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+}
+
+func gzipBytes(b []byte) []byte {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write(b)
+	zw.Close()
+	return buf.Bytes()
+}
@@ -0,0 +1,182 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// influxStub records the line-protocol batches written to it.  status, if
+// set, picks the response to each request from its 1-based number.
+type influxStub struct {
+	mu      sync.Mutex
+	batches []string
+	queries []string
+	auth    []string
+	status  func(n int) int
+}
+
+func (s *influxStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	n := len(s.queries) + 1
+	s.queries = append(s.queries, r.URL.RawQuery)
+	s.auth = append(s.auth, r.Header.Get("Authorization"))
+	s.mu.Unlock()
+
+	if r.URL.Path != "/api/v2/write" || r.Header.Get("Content-Encoding") != "gzip" {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	if s.status != nil {
+		if code := s.status(n); code != http.StatusNoContent {
+			if code == http.StatusTooManyRequests {
+				w.Header().Set("Retry-After", "0")
+			}
+			http.Error(w, "nope", code)
+			return
+		}
+	}
+	zr, err := gzip.NewReader(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	body, _ := io.ReadAll(zr)
+	s.mu.Lock()
+	s.batches = append(s.batches, string(body))
+	s.mu.Unlock()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func testInfluxWriter(t *testing.T, stub *influxStub) *influxWriter {
+	t.Helper()
+	srv := httptest.NewServer(stub)
+	t.Cleanup(srv.Close)
+	w, err := newInfluxWriter(influxConfig{url: srv.URL, token: "s3cret", org: "ops", bucket: "hosts"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.retryMin, w.retryMax = time.Millisecond, 4*time.Millisecond
+	return w
+}
+
+// pushSamples sends a header and n single-core samples (4 points each)
+// through a pusher and closes it.
+func pushSamples(w pushWriter, n int) error {
+	p := startPusher("influx", w)
+	p.setHeader(metrics.Header{Hostname: "node7"})
+	for i := 1; i <= n; i++ {
+		for len(p.in) == cap(p.in) {
+			time.Sleep(time.Millisecond) // stay under the queue limit
+		}
+		p.setSample(metrics.Sample{TimestampUnixMs: int64(i) * 500, CpuTotal: 10, CpuCores: []float64{10}})
+	}
+	return p.close()
+}
+
+func TestInfluxBatching(t *testing.T) {
+	stub := &influxStub{}
+	w := testInfluxWriter(t, stub)
+
+	// 75 samples × 4 points: full batches of 100 points (25 samples), sent
+	// as soon as they fill.
+	if err := pushSamples(w, 75); err != nil {
+		t.Fatal(err)
+	}
+	if len(stub.batches) != 3 {
+		t.Fatalf("got %d batches, want 3", len(stub.batches))
+	}
+	for i, b := range stub.batches {
+		if n := strings.Count(b, "\n"); n != 100 {
+			t.Errorf("batch %d: got %d points, want 100", i, n)
+		}
+	}
+	if got := stub.queries[0]; got != "bucket=hosts&org=ops&precision=ms" {
+		t.Errorf("query: got %q", got)
+	}
+	if got := stub.auth[0]; got != "Token s3cret" {
+		t.Errorf("Authorization: got %q", got)
+	}
+	first := strings.SplitN(stub.batches[0], "\n", 2)[0]
+	if first != "infgo_cpu,host=node7 total=10 500" {
+		t.Errorf("first line: got %q", first)
+	}
+}
+
+func TestInfluxFlushOnTimerAndClose(t *testing.T) {
+	stub := &influxStub{}
+	w := testInfluxWriter(t, stub)
+	w.flushEvery = 20 * time.Millisecond
+
+	p := startPusher("influx", w)
+	p.setHeader(metrics.Header{Hostname: "node7"})
+	p.setSample(metrics.Sample{TimestampUnixMs: 1000})
+	time.Sleep(100 * time.Millisecond)
+	p.setSample(metrics.Sample{TimestampUnixMs: 2000})
+	if err := p.close(); err != nil {
+		t.Fatal(err)
+	}
+	if len(stub.batches) != 2 || !strings.HasSuffix(stub.batches[1], " 2000\n") {
+		t.Errorf("got batches %q, want one from the timer and one on close", stub.batches)
+	}
+}
+
+func TestInfluxRetry(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      func(n int) int
+		wantBatches int
+		wantDropped int64
+		wantCalls   int
+	}{
+		{"recovers", func(n int) int { return []int{0, 503, 429, 204}[min(n, 3)] }, 1, 0, 3},
+		{"persistent 5xx", func(int) int { return 500 }, 0, 25, influxAttempts},
+		{"permanent 4xx", func(int) int { return 400 }, 0, 25, 1},
+	}
+	for _, tt := range tests {
+		stub := &influxStub{status: tt.status}
+		w := testInfluxWriter(t, stub)
+		err := pushSamples(w, 25) // exactly one full batch
+		if len(stub.batches) != tt.wantBatches || w.dropped() != tt.wantDropped || len(stub.queries) != tt.wantCalls {
+			t.Errorf("%s: got %d batches, %d dropped, %d calls; want %d, %d, %d",
+				tt.name, len(stub.batches), w.dropped(), len(stub.queries), tt.wantBatches, tt.wantDropped, tt.wantCalls)
+		}
+		if (err != nil) != (tt.wantDropped > 0) {
+			t.Errorf("%s: close: got %v", tt.name, err)
+		}
+	}
+}
+
+func TestNewInfluxWriter(t *testing.T) {
+	tests := []struct {
+		cfg     influxConfig
+		wantURL string
+	}{
+		{influxConfig{url: "http://influx:8086", org: "o", bucket: "b"}, "http://influx:8086/api/v2/write?bucket=b&org=o&precision=ms"},
+		{influxConfig{url: "https://proxy/influx/", org: "o w", bucket: "b"}, "https://proxy/influx/api/v2/write?bucket=b&org=o+w&precision=ms"},
+		{influxConfig{url: "influx:8086", org: "o", bucket: "b"}, ""},
+		{influxConfig{url: "http://influx:8086", org: "o"}, ""},
+	}
+	for _, tt := range tests {
+		w, err := newInfluxWriter(tt.cfg)
+		if tt.wantURL == "" {
+			if err == nil {
+				t.Errorf("newInfluxWriter(%+v): got nil error", tt.cfg)
+			}
+			continue
+		}
+		if err != nil || w.endpoint != tt.wantURL {
+			t.Errorf("newInfluxWriter(%+v): got %v, %v; want %s", tt.cfg, w, err, tt.wantURL)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"io"
+)
+
+// ── Job control ───────────────────────────────────────────────────────────────
+
+// A stop by ctrl+z is marked in the log by a "stopped" event, written and
+// flushed before the process stops, and a "continued" event with the
+// length of the gap.  The first CPU reading after it would be a delta
+// across the whole stop, so it only restarts the count; the memory and
+// load in the same reading, taken at once, are kept.
+
+// jobStop is the "command" the TUI hands the terminal to on ctrl+z:
+// tea.Exec restores the terminal before running it and takes it back
+// after, which is what a stop needs too.
+type jobStop struct{}
+
+func (jobStop) Run() error          { return stopProcess() }
+func (jobStop) SetStdin(io.Reader)  {}
+func (jobStop) SetStdout(io.Writer) {}
+func (jobStop) SetStderr(io.Writer) {}
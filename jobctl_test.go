@@ -0,0 +1,116 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/load"
+
+	syslogger "github.com/ALH477/infgo/logger"
+	"github.com/ALH477/infgo/metrics"
+)
+
+// readLog returns the events and samples in a capture.
+func readLog(t *testing.T, b *bytes.Buffer) (events []metrics.Event, samples []metrics.Sample) {
+	t.Helper()
+	rd, err := syslogger.NewReader(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for {
+		rec, err := rd.Next()
+		if err == io.EOF {
+			return events, samples
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rec.Event != nil {
+			events = append(events, *rec.Event)
+		}
+		if rec.Sample != nil {
+			samples = append(samples, *rec.Sample)
+		}
+	}
+}
+
+// ctrl+z in the collector's terminal: the stop is marked on either side,
+// flushed before the process stops, and the reading taken on continuing
+// primes the CPU snapshot afresh rather than report a delta across the
+// stop.
+func TestHeadlessStopJob(t *testing.T) {
+	var out bytes.Buffer
+	lgr, err := syslogger.NewWriter(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	f := &fakeSources{}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	r := fakeReader(f, &now)
+	loads := 0
+	r.src.load = func(context.Context) (*load.AvgStat, error) {
+		if loads++; loads == 3 {
+			cancel()
+		}
+		return &load.AvgStat{Load1: 1}, nil
+	}
+
+	stops := make(chan os.Signal, 1)
+	stops <- os.Interrupt // stands in for SIGTSTP
+	var flushed []metrics.Event
+	h := &headless{logger: lgr, reader: r, stops: stops, stopProcess: func() error {
+		flushed, _ = readLog(t, bytes.NewBuffer(out.Bytes()))
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	}}
+	if err := h.run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	lgr.Close()
+
+	if len(flushed) != 1 || flushed[0].Kind != "stopped" {
+		t.Errorf("logged before stopping: got %+v, want the stopped event", flushed)
+	}
+	all, samples := readLog(t, &out)
+	var events []metrics.Event
+	for _, e := range all {
+		if e.Kind != "cores" { // the fake's two cores against this machine's
+			events = append(events, e)
+		}
+	}
+	if len(events) != 2 || events[0].Kind != "stopped" || events[1].Kind != "continued" {
+		t.Fatalf("got events %+v, want stopped then continued", events)
+	}
+	if gap := events[1].TimestampUnixMs - events[0].TimestampUnixMs; gap < 20 {
+		t.Errorf("the events are %dms apart, want at least the 20ms stop", gap)
+	}
+	if len(samples) < 2 {
+		t.Fatalf("got %d samples, want at least 2", len(samples))
+	}
+	if s := samples[0]; s.Missing != 0 || s.CpuTotal != 30 || s.Load1 != 1 || s.MemPercent != 50 {
+		t.Errorf("first sample after continuing: got %+v, want every reading", s)
+	}
+	if f.cpuCalls < 3 {
+		t.Errorf("cpu queried %d times, want the priming query too", f.cpuCalls)
+	}
+}
+
+// Restarting after a stop primes the next CPU reading afresh.
+func TestCollectorRestart(t *testing.T) {
+	defer localStats.unprimed.Store(localStats.unprimed.Load())
+	localStats.unprimed.Store(false)
+	(&localCollector{stats: localStats}).Restart(time.Now())
+	if !localStats.unprimed.Load() {
+		t.Error("the next CPU reading is not primed afresh")
+	}
+}
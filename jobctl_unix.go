@@ -0,0 +1,32 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// jobControl reports whether ctrl+z can stop the process.
+const jobControl = true
+
+// notifyStop relays SIGTSTP, the terminal's ctrl+z, to c.
+func notifyStop(c chan<- os.Signal) { signal.Notify(c, syscall.SIGTSTP) }
+
+// stopProcess stops the process group, as the shell's ctrl+z would, and
+// returns once the process is continued.  It sends SIGSTOP rather than
+// SIGTSTP, which the headless collector catches.
+func stopProcess() error {
+	cont := make(chan os.Signal, 1)
+	signal.Notify(cont, syscall.SIGCONT)
+	defer signal.Stop(cont)
+	if err := syscall.Kill(0, syscall.SIGSTOP); err != nil {
+		return err
+	}
+	<-cont
+	return nil
+}
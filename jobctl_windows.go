@@ -0,0 +1,17 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"errors"
+	"os"
+)
+
+// jobControl reports whether ctrl+z can stop the process.  Windows consoles
+// have no job control.
+const jobControl = false
+
+func notifyStop(chan<- os.Signal) {}
+
+func stopProcess() error { return errors.ErrUnsupported }
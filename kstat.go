@@ -0,0 +1,73 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+// ── Context switches and interrupts ───────────────────────────────────────────
+//
+// The SYSTEM panel's Kernel row: context switches and interrupts a second,
+// from the kernel's counters since boot.  A rate is the delta between two
+// readings over the time between them, so a tick that fires late does not
+// inflate it.  Where the counters cannot be read the row is left out.
+
+// errNoKernelCounters is a /proc/stat without the ctxt and intr lines.
+var errNoKernelCounters = errors.New("/proc/stat: no ctxt or intr line")
+
+// parseProcStat reads the ctxt and intr counters from a /proc/stat; the
+// intr line's first field is the total, the rest its breakdown by IRQ.
+func parseProcStat(r io.Reader) (ui.KernelCounters, error) {
+	var c ui.KernelCounters
+	var seen int
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<20) // intr lines run to thousands of IRQs
+	for sc.Scan() {
+		f := strings.Fields(sc.Text())
+		if len(f) < 2 || (f[0] != "ctxt" && f[0] != "intr") {
+			continue
+		}
+		v, err := strconv.ParseUint(f[1], 10, 64)
+		if err != nil {
+			return c, err
+		}
+		if f[0] == "ctxt" {
+			c.Ctxt = v
+		} else {
+			c.Intr = v
+		}
+		seen++
+	}
+	if err := sc.Err(); err != nil {
+		return c, err
+	}
+	if seen < 2 {
+		return c, errNoKernelCounters
+	}
+	return c, nil
+}
+
+// readKernel reads the counters into msg.  They back off on their own, and
+// on a platform without them the reader stops asking after the first
+// failure's backoff runs out, as with any subsystem.
+func (r *statsReader) readKernel(ctx context.Context, msg *ui.Reading) {
+	if r.src.kernel == nil || !r.kstat.due(r.now()) {
+		return
+	}
+	c, err := query(ctx, &r.kstat, r.timeout, r.src.kernel)
+	if err != nil {
+		r.kstat.failed(r.now())
+		return
+	}
+	r.kstat.recovered()
+	msg.Kernel, msg.KernelAt = c, r.now()
+}
@@ -0,0 +1,24 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+//go:build linux
+
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+// readKernelCounters reads the context switches and interrupts since boot
+// from /proc/stat.
+func readKernelCounters(context.Context) (ui.KernelCounters, error) {
+	f, err := os.Open("/proc/stat")
+	if err != nil {
+		return ui.KernelCounters{}, err
+	}
+	defer f.Close()
+	return parseProcStat(f)
+}
@@ -0,0 +1,16 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+//go:build !linux
+
+package main
+
+import (
+	"context"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+// readKernelCounters is nil here: the counters are read from /proc/stat
+// only, and the SYSTEM panel has no Kernel row.
+var readKernelCounters func(context.Context) (ui.KernelCounters, error)
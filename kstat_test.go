@@ -0,0 +1,47 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+func TestParseProcStat(t *testing.T) {
+	const stat = "cpu  10132153 290696 3084719 46828483 16683 0 25195 0 0 0\n" +
+		"cpu0 1393280 32966 572056 13343292 6130 0 17875 0 0 0\n" +
+		"intr 199292 4 9 0 0 0 0 3 0 1 0 0 0 0\n" +
+		"ctxt 1990473\n" +
+		"btime 1062191376\n"
+	if got, err := parseProcStat(strings.NewReader(stat)); err != nil || got != (ui.KernelCounters{Ctxt: 1990473, Intr: 199292}) {
+		t.Errorf("got %+v, %v", got, err)
+	}
+	if _, err := parseProcStat(strings.NewReader("cpu  1 2 3 4\n")); err == nil {
+		t.Error("no counters: got nil error")
+	}
+}
+
+func TestReadKernel(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	r := fakeReader(&fakeSources{}, &now)
+	if msg := r.read(context.Background()); !msg.KernelAt.IsZero() {
+		t.Errorf("without a source: got %+v at %v", msg.Kernel, msg.KernelAt)
+	}
+	r.src.kernel = func(context.Context) (ui.KernelCounters, error) { return ui.KernelCounters{Ctxt: 7, Intr: 3}, nil }
+	if msg := r.read(context.Background()); msg.Kernel != (ui.KernelCounters{Ctxt: 7, Intr: 3}) || !msg.KernelAt.Equal(now) {
+		t.Errorf("got %+v at %v", msg.Kernel, msg.KernelAt)
+	}
+	r.src.kernel = func(context.Context) (ui.KernelCounters, error) { return ui.KernelCounters{}, errNoKernelCounters }
+	now = now.Add(ui.DefaultInterval)
+	if msg := r.read(context.Background()); !msg.KernelAt.IsZero() || msg.Missing != 0 {
+		t.Errorf("failing: got %v, missing %q", msg.KernelAt, msg.Missing)
+	}
+	if r.kstat.due(now) {
+		t.Error("a failed reading did not back off")
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+// ── Saved layout ──────────────────────────────────────────────────────────────
+
+// layoutPath is the file -save-layout writes the collapsed panels to, and
+// that every session starts from: infgo/layout in the user's configuration
+// directory, ~/.config on Linux.
+func layoutPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "infgo", "layout"), nil
+}
+
+// readLayout returns the panels the layout file at path collapses; none if
+// there is no file.  The file has a line naming them,
+//
+//	collapsed memory system
+//
+// and may have blank lines and # comments.
+func readLayout(path string) (collapsed []ui.Panel, err error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; sc.Scan(); n++ {
+		f := strings.Fields(sc.Text())
+		if len(f) == 0 || strings.HasPrefix(f[0], "#") {
+			continue
+		}
+		if f[0] != "collapsed" {
+			return nil, fmt.Errorf("%s:%d: unknown setting %q", path, n, f[0])
+		}
+		for _, name := range f[1:] {
+			p, err := ui.ParsePanel(name)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", path, n, err)
+			}
+			collapsed = append(collapsed, p)
+		}
+	}
+	return collapsed, sc.Err()
+}
+
+// writeLayout saves collapsed to the layout file at path, creating its
+// directory if need be.
+func writeLayout(path string, collapsed []ui.Panel) error {
+	line := "collapsed"
+	for _, p := range collapsed {
+		line += " " + p.String()
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte("# infgo layout, written by -save-layout\n"+line+"\n"), 0o644)
+}
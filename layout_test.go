@@ -0,0 +1,38 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+func TestLayoutFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "infgo", "layout")
+	if got, err := readLayout(path); err != nil || got != nil {
+		t.Errorf("no file: got %v, %v", got, err)
+	}
+	want := []ui.Panel{ui.PanelMemory, ui.PanelUsers}
+	if err := writeLayout(path, want); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := readLayout(path); err != nil || !slices.Equal(got, want) {
+		t.Errorf("got %v, %v, want %v", got, err, want)
+	}
+
+	for _, text := range []string{"collapsed memory swap\n", "expanded cpu\n"} {
+		os.WriteFile(path, []byte(text), 0o644)
+		if got, err := readLayout(path); err == nil || got != nil {
+			t.Errorf("%q: got %v, %v, want an error", text, got, err)
+		}
+	}
+	os.WriteFile(path, []byte("# hand-written\n\ncollapsed cpu\n"), 0o644)
+	if got, err := readLayout(path); err != nil || !slices.Equal(got, []ui.Panel{ui.PanelCPU}) {
+		t.Errorf("comments: got %v, %v", got, err)
+	}
+}
@@ -7,9 +7,13 @@
 //
 //	[0:8]   Magic bytes: "INFGO\x01\x00"
 //	Then N records, each structured as:
-//	  [0]     Record type byte  (RecordTypeHeader=0x01 | RecordTypeSample=0x02)
+//	  [0]     Record type byte  (RecordTypeHeader=0x01 | RecordTypeSample=0x02 |
+//	                             RecordTypeEvent=0x03 | RecordTypeSchema=0x04 |
+//	                             RecordTypeProcess=0x05)
 //	  [1:5]   uint32 big-endian payload length
-//	  [5:5+N] protobuf-encoded payload (metrics.Header or metrics.Sample)
+//	  [5:5+N] protobuf-encoded payload (metrics.Header, metrics.Sample,
+//	          metrics.Event or metrics.Process), or for a Schema record the
+//	          gzip-compressed text of proto/metrics.proto
 //
 // The Logger type is safe to use from a single goroutine only (Bubble Tea's
 // Update method is single-threaded, so no synchronisation is needed there).
@@ -18,10 +22,14 @@ package logger
 
 import (
 	"bufio"
+	"bytes"
+	"compress/gzip"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"sync"
 
 	"github.com/ALH477/infgo/metrics"
 )
@@ -34,12 +42,16 @@ var magic = [8]byte{'I', 'N', 'F', 'G', 'O', 0x00, 0x01, 0x00}
 // corrupt files from causing unbounded memory allocation on read.
 const maxPayloadBytes = 10 * 1024 * 1024 // 10 MiB
 
-// RecordType discriminates the two record kinds in a log file.
+// RecordType discriminates the record kinds in a log file.
 type RecordType byte
 
 const (
 	RecordTypeHeader RecordType = 0x01
 	RecordTypeSample RecordType = 0x02
+	RecordTypeEvent  RecordType = 0x03
+	RecordTypeSchema RecordType = 0x04 // follows a Header, in captures that embed the schema
+
+	RecordTypeProcess RecordType = 0x05 // a process watched with -pid
 )
 
 // ── Logger (write) ────────────────────────────────────────────────────────────
@@ -49,14 +61,107 @@ const (
 // and Close when the session ends.
 type Logger struct {
 	w    *bufio.Writer
-	f    *os.File
+	f    io.Closer // nil once closed, or for writers that are not ours to close
 	path string
+	buf  []byte  // WriteSample's encoding buffer, reused from tick to tick
+	head [5]byte // a record's type and length; a local would escape to the heap
+
+	schema bool // follow each Header with a Schema record
+	held   bool // drop samples and events; see Hold
+	closed bool
+
+	appended int64 // the size of the capture Append opened
 }
 
 // New creates (or truncates) the file at path, writes the magic header, and
 // returns a Logger ready to accept records.  The caller must call Close.
 func New(path string) (*Logger, error) {
-	f, err := os.Create(path)
+	return create(path, os.O_TRUNC)
+}
+
+// NewExclusive is New for a file that must not exist yet: it fails with
+// an error wrapping fs.ErrExist rather than truncate one.
+func NewExclusive(path string) (*Logger, error) {
+	return create(path, os.O_EXCL)
+}
+
+// Append opens the capture at path to add records to it, creating it like
+// New if it does not exist or is empty.  A record cut off at the end, as
+// a crash leaves one, is dropped first.  Compressed captures cannot be
+// appended to.
+func Append(path string) (*Logger, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o666)
+	if err != nil {
+		return nil, fmt.Errorf("logger: append %q: %w", path, err)
+	}
+	end, err := recordsEnd(f)
+	if err == nil {
+		err = f.Truncate(end)
+	}
+	if err == nil {
+		_, err = f.Seek(end, io.SeekStart)
+	}
+	if err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("logger: append %q: %w", path, err)
+	}
+	lgr := &Logger{
+		f:        f,
+		w:        bufio.NewWriterSize(f, 64*1024),
+		path:     path,
+		appended: end,
+	}
+	if end == 0 {
+		if _, err := lgr.w.Write(magic[:]); err != nil {
+			_ = f.Close()
+			return nil, fmt.Errorf("logger: write magic: %w", err)
+		}
+	}
+	return lgr, nil
+}
+
+// recordsEnd is the offset just past the last whole record of the capture
+// in f, or 0 for a file holding no more than part of the magic bytes.
+// Only the record headers are read; the payloads are skipped.
+func recordsEnd(f *os.File) (int64, error) {
+	r := bufio.NewReaderSize(f, 64*1024)
+	var got [8]byte
+	n, err := io.ReadFull(r, got[:])
+	switch {
+	case err != nil && bytes.HasPrefix(magic[:], got[:n]):
+		return 0, nil
+	case bytes.HasPrefix(got[:n], gzipMagic), bytes.HasPrefix(got[:n], zstdMagic):
+		return 0, errors.New("a compressed capture cannot be appended to")
+	case err != nil || got != magic:
+		return 0, errors.New("not a valid infgo log file (bad magic bytes)")
+	}
+	off := int64(len(magic))
+	var head [5]byte
+	for {
+		if _, err := io.ReadFull(r, head[:]); err == io.EOF || err == io.ErrUnexpectedEOF {
+			return off, nil
+		} else if err != nil {
+			return 0, err
+		}
+		n := binary.BigEndian.Uint32(head[1:])
+		if n > maxPayloadBytes {
+			return 0, fmt.Errorf("corrupt record at offset %d", off)
+		}
+		if _, err := r.Discard(int(n)); err == io.EOF {
+			return off, nil
+		} else if err != nil {
+			return 0, err
+		}
+		off += int64(len(head)) + int64(n)
+	}
+}
+
+// Appended returns the size of the capture Append opened, which this
+// Logger's records follow: 0 for one created afresh.
+func (l *Logger) Appended() int64 { return l.appended }
+
+func create(path string, flag int) (*Logger, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|flag, 0o666)
 	if err != nil {
 		return nil, fmt.Errorf("logger: create %q: %w", path, err)
 	}
@@ -72,48 +177,119 @@ func New(path string) (*Logger, error) {
 	return lgr, nil
 }
 
-// Path returns the filesystem path of the underlying log file.
+// NewWriter returns a Logger that streams records to w, e.g. os.Stdout or a
+// network connection, after writing the magic header.  Close flushes but
+// never closes w itself.
+func NewWriter(w io.Writer) (*Logger, error) {
+	lgr := &Logger{w: bufio.NewWriterSize(w, 64*1024), path: "-"}
+	if _, err := lgr.w.Write(magic[:]); err != nil {
+		return nil, fmt.Errorf("logger: write magic: %w", err)
+	}
+	return lgr, nil
+}
+
+// Path returns the filesystem path of the underlying log file, or "-" for
+// Loggers created with NewWriter.
 func (l *Logger) Path() string { return l.path }
 
 // WriteHeader serialises hdr and appends it to the log as a Header record.
 // This should be called exactly once, immediately after the TUI receives
 // the first sysInfoMsg so that hostname and platform are known.
+//
+// After EmbedSchema the header is followed by a Schema record.
 func (l *Logger) WriteHeader(hdr metrics.Header) error {
-	return l.appendRecord(RecordTypeHeader, hdr.Marshal())
+	if err := l.appendRecord(RecordTypeHeader, hdr.Marshal()); err != nil || !l.schema {
+		return err
+	}
+	return l.appendRecord(RecordTypeSchema, compressedSchema())
 }
 
+// EmbedSchema makes the capture self-describing: every Header is followed
+// by a Schema record holding metrics.Schema, gzip-compressed (about a
+// kilobyte), so that tools without infgo's code can decode the samples.
+// Readers that predate it skip the record as an unknown type.
+func (l *Logger) EmbedSchema() { l.schema = true }
+
+// Hold makes WriteSample and WriteEvent drop their records, without error,
+// until Release: for a log whose filesystem is nearly full.  Headers are
+// still written, so that a segment started meanwhile is a valid capture.
+func (l *Logger) Hold() { l.held = true }
+
+// Release undoes Hold.
+func (l *Logger) Release() { l.held = false }
+
+// Held reports whether records are being dropped.
+func (l *Logger) Held() bool { return l.held }
+
 // WriteSample serialises s and appends it to the log as a Sample record.
+// The encoding buffer is kept between calls, so once it has grown to fit
+// the host's core count, logging a sample allocates nothing.
 func (l *Logger) WriteSample(s metrics.Sample) error {
-	return l.appendRecord(RecordTypeSample, s.Marshal())
+	if l.held {
+		return nil
+	}
+	l.buf = s.MarshalAppend(l.buf[:0])
+	return l.appendRecord(RecordTypeSample, l.buf)
 }
 
-// Close flushes any buffered data and closes the underlying file.
-// It is safe to call Close more than once; subsequent calls return nil.
-func (l *Logger) Close() error {
-	if l.f == nil {
+// WriteEvent serialises e and appends it to the log as an Event record.
+// Readers that predate events skip the record as an unknown type.
+func (l *Logger) WriteEvent(e metrics.Event) error {
+	if l.held {
+		return nil
+	}
+	return l.appendRecord(RecordTypeEvent, e.Marshal())
+}
+
+// WriteProcess serialises p and appends it to the log as a Process record.
+// Readers that predate them skip the record as an unknown type.
+func (l *Logger) WriteProcess(p metrics.Process) error {
+	if l.held {
 		return nil
 	}
+	return l.appendRecord(RecordTypeProcess, p.Marshal())
+}
+
+// Flush writes any buffered records through to the underlying writer, so a
+// consumer reading a stream sees every sample as soon as it is taken.
+func (l *Logger) Flush() error {
 	if err := l.w.Flush(); err != nil {
-		_ = l.f.Close()
-		l.f = nil
 		return fmt.Errorf("logger: flush %q: %w", l.path, err)
 	}
-	if err := l.f.Close(); err != nil {
+	return nil
+}
+
+// Close flushes any buffered data and closes the underlying file.
+// It is safe to call Close more than once; subsequent calls return nil.
+func (l *Logger) Close() error {
+	if l.closed {
+		return nil
+	}
+	l.closed = true
+	err := l.Flush()
+	if l.f != nil {
+		if cerr := l.f.Close(); cerr != nil && err == nil {
+			err = fmt.Errorf("logger: close %q: %w", l.path, cerr)
+		}
 		l.f = nil
-		return fmt.Errorf("logger: close %q: %w", l.path, err)
 	}
-	l.f = nil
-	return nil
+	return err
 }
 
+// compressedSchema is the payload of a Schema record.
+var compressedSchema = sync.OnceValue(func() []byte {
+	var buf bytes.Buffer
+	zw, _ := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	zw.Write([]byte(metrics.Schema))
+	zw.Close()
+	return buf.Bytes()
+})
+
 // appendRecord writes: [type:1][length:4][payload:N]
 func (l *Logger) appendRecord(rt RecordType, payload []byte) error {
-	if err := l.w.WriteByte(byte(rt)); err != nil {
-		return err
-	}
-	var lenBuf [4]byte
-	binary.BigEndian.PutUint32(lenBuf[:], uint32(len(payload)))
-	if _, err := l.w.Write(lenBuf[:]); err != nil {
+	l.head[0] = byte(rt)
+	binary.BigEndian.PutUint32(l.head[1:], uint32(len(payload)))
+	if _, err := l.w.Write(l.head[:]); err != nil {
 		return err
 	}
 	_, err := l.w.Write(payload)
@@ -123,42 +299,128 @@ func (l *Logger) appendRecord(rt RecordType, payload []byte) error {
 // ── Reader (read) ─────────────────────────────────────────────────────────────
 
 // Record is a decoded entry from a .infgo log file.
-// Exactly one of Header or Sample will be non-nil, depending on Type.
+// At most one of Header, Sample, Event or Process will be non-nil, depending
+// on Type; all are nil for Schema records, whose text Reader.Schema
+// returns, and for record types this version does not understand.
 type Record struct {
-	Type   RecordType
-	Header *metrics.Header
-	Sample *metrics.Sample
+	Type    RecordType
+	Header  *metrics.Header
+	Sample  *metrics.Sample
+	Event   *metrics.Event
+	Process *metrics.Process
 }
 
-// Reader reads records sequentially from a .infgo log file.
+// Reader reads records sequentially from a .infgo log file or stream.
 type Reader struct {
-	f *os.File
-	r *bufio.Reader
+	r       *bufio.Reader
+	closers []io.Closer // closed in reverse order by Close
+	seeker  bool        // the underlying source is a regular file
+	off     int64       // end of the last whole record, in the uncompressed stream
+	schema  *string     // the text of the last Schema record read
 }
 
+// gzipMagic and zstdMagic identify compressed captures.
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
 // Open opens path, validates the magic bytes, and returns a Reader
-// positioned at the first record.  The caller must call Close.
+// positioned at the first record.  Gzip-compressed captures are
+// decompressed transparently.  The caller must call Close.
 func Open(path string) (*Reader, error) {
 	f, err := os.Open(path)
 	if err != nil {
 		return nil, fmt.Errorf("reader: open %q: %w", path, err)
 	}
-	br := bufio.NewReaderSize(f, 64*1024)
+	rd, err := newReader(f, path)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	rd.closers = append([]io.Closer{f}, rd.closers...)
+	rd.seeker = len(rd.closers) == 1 // compressed streams cannot seek
+	return rd, nil
+}
+
+// NewReader returns a Reader over an arbitrary stream such as os.Stdin or a
+// network connection, validating the magic bytes first.  Gzip-compressed
+// streams are decompressed transparently.  Close releases the decompressor
+// but never closes r itself.
+func NewReader(r io.Reader) (*Reader, error) {
+	return newReader(r, "stream")
+}
+
+// newReader sniffs for compression, then validates the magic bytes.
+// name is only used in error messages.
+func newReader(src io.Reader, name string) (*Reader, error) {
+	rd := &Reader{r: bufio.NewReaderSize(src, 64*1024)}
+
+	head, _ := rd.r.Peek(len(zstdMagic))
+	switch {
+	case bytes.HasPrefix(head, gzipMagic):
+		zr, err := gzip.NewReader(rd.r)
+		if err != nil {
+			return nil, fmt.Errorf("reader: %q: gzip: %w", name, err)
+		}
+		rd.r = bufio.NewReaderSize(zr, 64*1024)
+		rd.closers = append(rd.closers, zr)
+	case bytes.HasPrefix(head, zstdMagic):
+		return nil, fmt.Errorf("reader: %q is zstd-compressed, which this build cannot decode; decompress it with `zstd -d` first", name)
+	}
 
 	var got [8]byte
-	if _, err := io.ReadFull(br, got[:]); err != nil {
-		_ = f.Close()
+	if _, err := io.ReadFull(rd.r, got[:]); err != nil {
+		_ = rd.Close()
 		return nil, fmt.Errorf("reader: read magic: %w", err)
 	}
 	if got != magic {
-		_ = f.Close()
-		return nil, fmt.Errorf("reader: %q is not a valid infgo log file (bad magic bytes)", path)
+		_ = rd.Close()
+		return nil, fmt.Errorf("reader: %q is not a valid infgo log file (bad magic bytes)", name)
+	}
+	rd.off = int64(len(magic))
+	return rd, nil
+}
+
+// Resume returns a Reader over the uncompressed capture rs, positioned at
+// off: the Offset of an earlier Reader over the same capture, so that a
+// file still being written can be read on from where that one stopped.
+// The magic bytes are validated first.  Close never closes rs.
+func Resume(rs io.ReadSeeker, off int64) (*Reader, error) {
+	if _, err := rs.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("reader: %w", err)
+	}
+	rd, err := newReader(rs, "stream")
+	if err != nil {
+		return nil, err
 	}
-	return &Reader{f: f, r: br}, nil
+	if len(rd.closers) > 0 {
+		_ = rd.Close()
+		return nil, errors.New("reader: a compressed capture cannot be resumed")
+	}
+	if off > rd.off {
+		if _, err := rs.Seek(off, io.SeekStart); err != nil {
+			return nil, fmt.Errorf("reader: %w", err)
+		}
+		rd.r.Reset(rs)
+		rd.off = off
+	}
+	return rd, nil
 }
 
+// Offset returns the position just past the last record Next returned, or
+// past the magic bytes before the first: where Resume would carry on.
+func (r *Reader) Offset() int64 { return r.off }
+
+// Seekable reports whether the Reader is backed by an uncompressed regular
+// file, i.e. whether tools may re-open or seek within it.  Readers created
+// with NewReader are never seekable.
+func (r *Reader) Seekable() bool { return r.seeker }
+
 // Next reads and decodes the next record from the log.
-// It returns (nil, io.EOF) when the file is exhausted.
+// It returns (nil, io.EOF) when the file is exhausted, and an error
+// wrapping io.ErrUnexpectedEOF when it ends partway through a record, as a
+// capture still being written may.
 func (r *Reader) Next() (*Record, error) {
 	// Read the 1-byte type tag.
 	typByte, err := r.r.ReadByte()
@@ -173,7 +435,7 @@ func (r *Reader) Next() (*Record, error) {
 	// Read the 4-byte big-endian payload length.
 	var lenBuf [4]byte
 	if _, err := io.ReadFull(r.r, lenBuf[:]); err != nil {
-		return nil, fmt.Errorf("reader: read length: %w", err)
+		return nil, fmt.Errorf("reader: read length: %w", noEOF(err))
 	}
 	payloadLen := binary.BigEndian.Uint32(lenBuf[:])
 
@@ -183,34 +445,97 @@ func (r *Reader) Next() (*Record, error) {
 
 	payload := make([]byte, payloadLen)
 	if _, err := io.ReadFull(r.r, payload); err != nil {
-		return nil, fmt.Errorf("reader: read payload: %w", err)
+		return nil, fmt.Errorf("reader: read payload: %w", noEOF(err))
 	}
+	r.off += int64(1 + len(lenBuf) + len(payload))
 
 	rec := &Record{Type: rt}
-	switch rt {
+	if err := rec.decode(payload); err != nil {
+		return nil, err
+	}
+	if rt == RecordTypeSchema {
+		zr, err := gzip.NewReader(bytes.NewReader(payload))
+		if err != nil {
+			return nil, fmt.Errorf("reader: schema: %w", err)
+		}
+		text, err := io.ReadAll(zr)
+		if err != nil {
+			return nil, fmt.Errorf("reader: schema: %w", err)
+		}
+		schema := string(text)
+		r.schema = &schema
+	}
+	return rec, nil
+}
+
+// decode fills in the Header, Sample, Event or Process that payload holds, by
+// rec.Type.
+func (rec *Record) decode(payload []byte) error {
+	switch rec.Type {
 	case RecordTypeHeader:
 		hdr, err := metrics.UnmarshalHeader(payload)
 		if err != nil {
-			return nil, fmt.Errorf("reader: unmarshal header: %w", err)
+			return fmt.Errorf("reader: unmarshal header: %w", err)
 		}
 		rec.Header = &hdr
 
 	case RecordTypeSample:
 		s, err := metrics.UnmarshalSample(payload)
 		if err != nil {
-			return nil, fmt.Errorf("reader: unmarshal sample: %w", err)
+			return fmt.Errorf("reader: unmarshal sample: %w", err)
 		}
 		rec.Sample = &s
 
+	case RecordTypeEvent:
+		e, err := metrics.UnmarshalEvent(payload)
+		if err != nil {
+			return fmt.Errorf("reader: unmarshal event: %w", err)
+		}
+		rec.Event = &e
+
+	case RecordTypeProcess:
+		p, err := metrics.UnmarshalProcess(payload)
+		if err != nil {
+			return fmt.Errorf("reader: unmarshal process: %w", err)
+		}
+		rec.Process = &p
+
 	default:
-		// Unknown record type — skip (forward-compatible with future versions).
-		// rec.Header and rec.Sample remain nil; callers should check for this.
+		// Schema records are read by Next.  Unknown record types are
+		// skipped (forward-compatible with future versions); the payload
+		// fields remain nil, and callers should check for this.
 	}
+	return nil
+}
 
-	return rec, nil
+// Schema returns the metrics.proto text embedded in the capture, once Next
+// has read past it: it follows the header, in captures written with
+// -log-schema.  ok is false for captures without one.
+func (r *Reader) Schema() (text string, ok bool) {
+	if r.schema == nil {
+		return "", false
+	}
+	return *r.schema, true
 }
 
-// Close closes the underlying file.
+// noEOF turns the io.EOF of a record cut off after its type byte into
+// io.ErrUnexpectedEOF.
+func noEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// Close closes any decompressor and, for Readers created by Open, the
+// underlying file.  It is safe to call Close more than once.
 func (r *Reader) Close() error {
-	return r.f.Close()
+	var first error
+	for i := len(r.closers) - 1; i >= 0; i-- {
+		if err := r.closers[i].Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	r.closers = nil
+	return first
 }
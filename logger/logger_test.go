@@ -0,0 +1,411 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package logger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// countSamples drains rd and returns the number of sample records.
+func countSamples(t *testing.T, rd *Reader) int {
+	t.Helper()
+	n := 0
+	for {
+		rec, err := rd.Next()
+		if err == io.EOF {
+			return n
+		}
+		if err != nil {
+			t.Fatalf("Next failed: %v", err)
+		}
+		if rec.Sample != nil {
+			n++
+		}
+	}
+}
+
+func TestNewReaderStreams(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "plain.infgo")
+	writeLog(t, path, metrics.Header{Hostname: "h"}, 1000, 2000, 3000)
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write(raw)
+	zw.Close()
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"plain", raw},
+		{"gzip", gz.Bytes()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rd, err := NewReader(bytes.NewReader(tt.data))
+			if err != nil {
+				t.Fatalf("NewReader failed: %v", err)
+			}
+			defer rd.Close()
+			if rd.Seekable() {
+				t.Error("stream Reader reports Seekable")
+			}
+			if n := countSamples(t, rd); n != 3 {
+				t.Errorf("samples: got %d, want 3", n)
+			}
+		})
+	}
+}
+
+func TestOpenSeekable(t *testing.T) {
+	dir := t.TempDir()
+	plain := filepath.Join(dir, "plain.infgo")
+	writeLog(t, plain, metrics.Header{}, 1000)
+
+	raw, _ := os.ReadFile(plain)
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write(raw)
+	zw.Close()
+	compressed := filepath.Join(dir, "c.infgo.gz")
+	if err := os.WriteFile(compressed, gz.Bytes(), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	for path, want := range map[string]bool{plain: true, compressed: false} {
+		rd, err := Open(path)
+		if err != nil {
+			t.Fatalf("Open(%s) failed: %v", path, err)
+		}
+		if got := rd.Seekable(); got != want {
+			t.Errorf("Seekable(%s): got %v, want %v", filepath.Base(path), got, want)
+		}
+		if n := countSamples(t, rd); n != 1 {
+			t.Errorf("samples in %s: got %d, want 1", filepath.Base(path), n)
+		}
+		if err := rd.Close(); err != nil {
+			t.Errorf("Close failed: %v", err)
+		}
+	}
+}
+
+func TestNewReaderRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"bad magic", []byte("NOTINFGO"), "bad magic"},
+		{"zstd", []byte{0x28, 0xb5, 0x2f, 0xfd, 0, 0, 0, 0}, "zstd"},
+		{"short", []byte("IN"), "read magic"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewReader(bytes.NewReader(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got %v, want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// WriteSample encodes into a buffer it keeps, so a record must not carry
+// anything over from a longer one before it.
+func TestWriteSampleReusesBuffer(t *testing.T) {
+	var out bytes.Buffer
+	lgr, err := NewWriter(&out)
+	if err != nil {
+		t.Fatalf("NewWriter failed: %v", err)
+	}
+	coreCounts := []int{4, 64, 2, 0, 8}
+	for i, n := range coreCounts {
+		cores := make([]float64, n)
+		for c := range cores {
+			cores[c] = float64(i*100 + c)
+		}
+		if err := lgr.WriteSample(metrics.Sample{TimestampUnixMs: int64(i), CpuCores: cores}); err != nil {
+			t.Fatalf("WriteSample failed: %v", err)
+		}
+	}
+	lgr.Close()
+
+	rd, err := NewReader(&out)
+	if err != nil {
+		t.Fatalf("NewReader failed: %v", err)
+	}
+	for i, n := range coreCounts {
+		rec, err := rd.Next()
+		if err != nil || rec.Sample == nil {
+			t.Fatalf("record %d: got %+v, %v", i, rec, err)
+		}
+		s := rec.Sample
+		if s.TimestampUnixMs != int64(i) || len(s.CpuCores) != n {
+			t.Fatalf("record %d: got timestamp %d and %d cores, want %d and %d", i, s.TimestampUnixMs, len(s.CpuCores), i, n)
+		}
+		for c, v := range s.CpuCores {
+			if v != float64(i*100+c) {
+				t.Errorf("record %d core %d: got %v, want %v", i, c, v, float64(i*100+c))
+			}
+		}
+	}
+
+	discard, err := NewWriter(io.Discard)
+	if err != nil {
+		t.Fatalf("NewWriter failed: %v", err)
+	}
+	s := metrics.Sample{CpuCores: make([]float64, 64)}
+	if allocs := testing.AllocsPerRun(100, func() { discard.WriteSample(s) }); allocs != 0 {
+		t.Errorf("WriteSample: got %v allocations, want 0", allocs)
+	}
+}
+
+func TestNewExclusive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "once.infgo")
+	lgr, err := NewExclusive(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := lgr.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewExclusive(path); !errors.Is(err, fs.ErrExist) {
+		t.Errorf("second NewExclusive: got %v, want fs.ErrExist", err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Size() != int64(len(magic)) {
+		t.Errorf("the first log was disturbed: %v, %v", info, err)
+	}
+}
+
+// A capture still being written is read on from the Offset of the last
+// Reader, and a record cut off at the end is not yet taken.
+func TestResume(t *testing.T) {
+	var buf bytes.Buffer
+	lgr, err := NewWriter(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lgr.WriteHeader(metrics.Header{})
+	lgr.WriteSample(metrics.Sample{TimestampUnixMs: 1})
+	lgr.WriteSample(metrics.Sample{TimestampUnixMs: 2})
+	lgr.Flush()
+	whole := append([]byte(nil), buf.Bytes()...)
+
+	rd, err := Resume(bytes.NewReader(whole[:len(whole)-3]), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := 0
+	for {
+		rec, err := rd.Next()
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+		if rec.Sample != nil {
+			n++
+		}
+	}
+	if n != 1 {
+		t.Fatalf("before the cut: got %d samples, want 1", n)
+	}
+
+	lgr.WriteSample(metrics.Sample{TimestampUnixMs: 3})
+	lgr.Flush()
+	rd, err = Resume(bytes.NewReader(buf.Bytes()), rd.Offset())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stamps []int64
+	for {
+		rec, err := rd.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+		stamps = append(stamps, rec.Sample.TimestampUnixMs)
+	}
+	if len(stamps) != 2 || stamps[0] != 2 || stamps[1] != 3 {
+		t.Errorf("resumed: got samples %v, want [2 3]", stamps)
+	}
+	if rd.Offset() != int64(buf.Len()) {
+		t.Errorf("got offset %d, want the end at %d", rd.Offset(), buf.Len())
+	}
+
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write(whole)
+	zw.Close()
+	if _, err := Resume(bytes.NewReader(gz.Bytes()), 0); err == nil {
+		t.Error("gzip: got no error")
+	}
+}
+
+func TestEmbedSchema(t *testing.T) {
+	for _, embed := range []bool{false, true} {
+		var buf bytes.Buffer
+		lgr, err := NewWriter(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if embed {
+			lgr.EmbedSchema()
+		}
+		lgr.WriteHeader(metrics.Header{Hostname: "h"})
+		lgr.WriteSample(metrics.Sample{TimestampUnixMs: 1000, CpuTotal: 12})
+		if err := lgr.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		rd, err := NewReader(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var types []RecordType
+		for {
+			rec, err := rd.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("embed %v: Next failed: %v", embed, err)
+			}
+			types = append(types, rec.Type)
+			if _, ok := rd.Schema(); ok != (embed && len(types) >= 2) {
+				t.Errorf("embed %v: after record %d Schema ok = %v", embed, len(types), ok)
+			}
+		}
+		want := []RecordType{RecordTypeHeader, RecordTypeSample}
+		if embed {
+			want = []RecordType{RecordTypeHeader, RecordTypeSchema, RecordTypeSample}
+		}
+		if fmt.Sprint(types) != fmt.Sprint(want) {
+			t.Errorf("embed %v: got records %v, want %v", embed, types, want)
+		}
+		if text, ok := rd.Schema(); embed && text != metrics.Schema {
+			t.Errorf("Schema: got %q, %v, want metrics.Schema", text, ok)
+		}
+	}
+	if n := len(compressedSchema()); n > 2048 {
+		t.Errorf("schema record is %d bytes, want it under 2 KiB", n)
+	}
+}
+
+// A held log drops samples and events but still takes headers.
+func TestHold(t *testing.T) {
+	var buf bytes.Buffer
+	lgr, err := NewWriter(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lgr.WriteHeader(metrics.Header{Hostname: "h"})
+	lgr.WriteSample(metrics.Sample{TimestampUnixMs: 1000})
+	lgr.Hold()
+	if err := lgr.WriteSample(metrics.Sample{TimestampUnixMs: 2000}); err != nil {
+		t.Errorf("held WriteSample: %v", err)
+	}
+	if err := lgr.WriteEvent(metrics.Event{TimestampUnixMs: 2000, Kind: "k"}); err != nil {
+		t.Errorf("held WriteEvent: %v", err)
+	}
+	if err := lgr.WriteProcess(metrics.Process{TimestampUnixMs: 2000, Pid: 1}); err != nil {
+		t.Errorf("held WriteProcess: %v", err)
+	}
+	lgr.WriteHeader(metrics.Header{Hostname: "h"})
+	if !lgr.Held() {
+		t.Error("Held: got false after Hold")
+	}
+	lgr.Release()
+	lgr.WriteSample(metrics.Sample{TimestampUnixMs: 3000})
+	if err := lgr.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	rd, err := NewReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for {
+		rec, err := rd.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch {
+		case rec.Header != nil:
+			got = append(got, "header")
+		case rec.Sample != nil:
+			got = append(got, fmt.Sprint(rec.Sample.TimestampUnixMs))
+		case rec.Event != nil:
+			got = append(got, "event")
+		case rec.Process != nil:
+			got = append(got, "process")
+		}
+	}
+	if want := "header 1000 header 3000"; strings.Join(got, " ") != want {
+		t.Errorf("got records %q, want %q", strings.Join(got, " "), want)
+	}
+}
+
+// Process records are read back between the samples they were written
+// among.
+func TestWriteProcess(t *testing.T) {
+	var buf bytes.Buffer
+	lgr, err := NewWriter(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := metrics.Process{TimestampUnixMs: 1500, Pid: 4242, StartedUnixMs: 900, Name: "nginx", CpuPercent: 3.5, RssBytes: 1 << 20, Threads: 4}
+	lgr.WriteHeader(metrics.Header{Hostname: "h"})
+	lgr.WriteSample(metrics.Sample{TimestampUnixMs: 1000})
+	lgr.WriteProcess(p)
+	lgr.WriteSample(metrics.Sample{TimestampUnixMs: 2000})
+	if err := lgr.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	rd, err := NewReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var types []RecordType
+	for {
+		rec, err := rd.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		types = append(types, rec.Type)
+		if rec.Type == RecordTypeProcess && (rec.Process == nil || *rec.Process != p) {
+			t.Errorf("process: got %+v, want %+v", rec.Process, p)
+		}
+	}
+	want := []RecordType{RecordTypeHeader, RecordTypeSample, RecordTypeProcess, RecordTypeSample}
+	if fmt.Sprint(types) != fmt.Sprint(want) {
+		t.Errorf("got records %v, want %v", types, want)
+	}
+}
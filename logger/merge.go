@@ -0,0 +1,214 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package logger
+
+import (
+	"container/heap"
+	"fmt"
+	"io"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// MergeOptions controls how Merge combines its sources.
+type MergeOptions struct {
+	// Hostname, when non-empty, replaces the hostname of every source
+	// header.  Use it when merging captures of the same machine whose
+	// hostname changed between restarts.
+	Hostname string
+}
+
+// SourceStats describes what Merge consumed from one source.
+type SourceStats struct {
+	Headers   int
+	Samples   int
+	Events    int
+	Processes int
+	Skipped   int // records of unknown type, not carried into the output
+
+	// FirstUnixMs / LastUnixMs bound the source's sample timestamps.
+	// Both are zero when the source contained no samples.
+	FirstUnixMs int64
+	LastUnixMs  int64
+}
+
+// Overlaps reports whether the sample spans of a and b intersect.
+func (a SourceStats) Overlaps(b SourceStats) bool {
+	if a.Samples == 0 || b.Samples == 0 {
+		return false
+	}
+	return a.FirstUnixMs <= b.LastUnixMs && b.FirstUnixMs <= a.LastUnixMs
+}
+
+// Merge performs a streaming k-way merge of srcs into dst, ordered by sample,
+// event and process timestamp.  Only one pending record per source is held in
+// memory, so the inputs may be arbitrarily large.
+//
+// A single combined header is written: the earliest StartedUnixMs, the
+// largest NumCores, and the platform and zone of the earliest source.  Sources whose
+// hostnames disagree are rejected unless opts.Hostname overrides them all.
+//
+// Merge does not close dst or the sources.  The returned stats are indexed
+// like srcs.
+func Merge(dst *Logger, srcs []*Reader, opts MergeOptions) ([]SourceStats, error) {
+	stats := make([]SourceStats, len(srcs))
+	h := make(mergeHeap, 0, len(srcs))
+	var (
+		hdr    *metrics.Header
+		hdrSrc int // index of the source hdr was first taken from
+	)
+
+	// Prime every source: consume its leading header(s) and first record.
+	for i, rd := range srcs {
+		ms := &mergeSource{rd: rd, idx: i, stats: &stats[i]}
+		src, err := ms.advance()
+		if err != nil {
+			return stats, fmt.Errorf("merge: source %d: %w", i, err)
+		}
+		for _, sh := range src {
+			if opts.Hostname != "" {
+				sh.Hostname = opts.Hostname
+			}
+			if hdr == nil {
+				c := sh
+				hdr, hdrSrc = &c, i
+				continue
+			}
+			if sh.Hostname != hdr.Hostname {
+				return stats, fmt.Errorf("merge: source %d is from host %q but source %d is from %q; relabel to merge them",
+					i, sh.Hostname, hdrSrc, hdr.Hostname)
+			}
+			if sh.StartedUnixMs != 0 && (hdr.StartedUnixMs == 0 || sh.StartedUnixMs < hdr.StartedUnixMs) {
+				hdr.StartedUnixMs = sh.StartedUnixMs
+				hdr.Platform = sh.Platform
+				hdr.Timezone, hdr.UTCOffsetS = sh.Timezone, sh.UTCOffsetS
+				hdr.Args, hdr.Collectors, hdr.ConfigPath = sh.Args, sh.Collectors, sh.ConfigPath
+			}
+			if sh.NumCores > hdr.NumCores {
+				hdr.NumCores = sh.NumCores
+			}
+		}
+		if ms.head != nil {
+			h = append(h, ms)
+		}
+	}
+	heap.Init(&h)
+
+	if hdr != nil {
+		if err := dst.WriteHeader(*hdr); err != nil {
+			return stats, fmt.Errorf("merge: write header: %w", err)
+		}
+	}
+
+	for h.Len() > 0 {
+		ms := h[0]
+		switch rec := ms.head; {
+		case rec.Sample != nil:
+			if err := dst.WriteSample(*rec.Sample); err != nil {
+				return stats, fmt.Errorf("merge: write sample: %w", err)
+			}
+		case rec.Event != nil:
+			if err := dst.WriteEvent(*rec.Event); err != nil {
+				return stats, fmt.Errorf("merge: write event: %w", err)
+			}
+		default:
+			if err := dst.WriteProcess(*rec.Process); err != nil {
+				return stats, fmt.Errorf("merge: write process: %w", err)
+			}
+		}
+		// Headers appearing mid-stream (a capture that was appended to)
+		// are folded away; the combined header already describes the host.
+		if _, err := ms.advance(); err != nil {
+			return stats, fmt.Errorf("merge: source %d: %w", ms.idx, err)
+		}
+		if ms.head == nil {
+			heap.Pop(&h)
+		} else {
+			heap.Fix(&h, 0)
+		}
+	}
+	return stats, nil
+}
+
+// mergeSource is one input to Merge with its next pending sample, event or
+// process.
+type mergeSource struct {
+	rd    *Reader
+	idx   int
+	head  *Record // nil once the source is exhausted
+	stats *SourceStats
+}
+
+// ts returns the timestamp of the pending record.
+func (ms *mergeSource) ts() int64 {
+	switch {
+	case ms.head.Sample != nil:
+		return ms.head.Sample.TimestampUnixMs
+	case ms.head.Event != nil:
+		return ms.head.Event.TimestampUnixMs
+	}
+	return ms.head.Process.TimestampUnixMs
+}
+
+// advance reads up to and including the next sample, event or process,
+// returning any headers encountered on the way.  head is nil afterwards if
+// the source hit EOF.
+func (ms *mergeSource) advance() ([]metrics.Header, error) {
+	var hdrs []metrics.Header
+	ms.head = nil
+	for {
+		rec, err := ms.rd.Next()
+		if err == io.EOF {
+			return hdrs, nil
+		}
+		if err != nil {
+			return hdrs, err
+		}
+		switch {
+		case rec.Header != nil:
+			ms.stats.Headers++
+			hdrs = append(hdrs, *rec.Header)
+		case rec.Sample != nil:
+			ts := rec.Sample.TimestampUnixMs
+			if ms.stats.Samples == 0 {
+				ms.stats.FirstUnixMs = ts
+			}
+			ms.stats.LastUnixMs = ts
+			ms.stats.Samples++
+			ms.head = rec
+			return hdrs, nil
+		case rec.Event != nil:
+			ms.stats.Events++
+			ms.head = rec
+			return hdrs, nil
+		case rec.Process != nil:
+			ms.stats.Processes++
+			ms.head = rec
+			return hdrs, nil
+		default:
+			ms.stats.Skipped++
+		}
+	}
+}
+
+// mergeHeap orders sources by their pending record's timestamp, breaking
+// ties by source index so the merge is deterministic.
+type mergeHeap []*mergeSource
+
+func (h mergeHeap) Len() int { return len(h) }
+func (h mergeHeap) Less(i, j int) bool {
+	a, b := h[i].ts(), h[j].ts()
+	if a != b {
+		return a < b
+	}
+	return h[i].idx < h[j].idx
+}
+func (h mergeHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *mergeHeap) Push(x any)   { *h = append(*h, x.(*mergeSource)) }
+func (h *mergeHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
@@ -0,0 +1,202 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package logger
+
+import (
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// writeLog writes a header followed by one sample per timestamp.
+func writeLog(t *testing.T, path string, hdr metrics.Header, stamps ...int64) {
+	t.Helper()
+	lgr, err := New(path)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if err := lgr.WriteHeader(hdr); err != nil {
+		t.Fatalf("WriteHeader failed: %v", err)
+	}
+	for _, ts := range stamps {
+		if err := lgr.WriteSample(metrics.Sample{TimestampUnixMs: ts}); err != nil {
+			t.Fatalf("WriteSample failed: %v", err)
+		}
+	}
+	if err := lgr.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+}
+
+// mergeFiles merges the named files into dir/out.infgo and returns the
+// merged records.
+func mergeFiles(t *testing.T, dir string, opts MergeOptions, names ...string) ([]SourceStats, []*Record, error) {
+	t.Helper()
+	var readers []*Reader
+	for _, n := range names {
+		rd, err := Open(filepath.Join(dir, n))
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+		defer rd.Close()
+		readers = append(readers, rd)
+	}
+
+	out := filepath.Join(dir, "out.infgo")
+	lgr, err := New(out)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	stats, mergeErr := Merge(lgr, readers, opts)
+	if err := lgr.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if mergeErr != nil {
+		return stats, nil, mergeErr
+	}
+
+	rd, err := Open(out)
+	if err != nil {
+		t.Fatalf("Open(out) failed: %v", err)
+	}
+	defer rd.Close()
+	var recs []*Record
+	for {
+		rec, err := rd.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next failed: %v", err)
+		}
+		recs = append(recs, rec)
+	}
+	return stats, recs, nil
+}
+
+func TestMergeOrdersByTimestamp(t *testing.T) {
+	dir := t.TempDir()
+	writeLog(t, filepath.Join(dir, "a.infgo"),
+		metrics.Header{Hostname: "node1", StartedUnixMs: 1000, NumCores: 4}, 1000, 3000, 5000)
+	writeLog(t, filepath.Join(dir, "b.infgo"),
+		metrics.Header{Hostname: "node1", StartedUnixMs: 2000, NumCores: 8}, 2000, 4000, 6000, 7000)
+
+	stats, recs, err := mergeFiles(t, dir, MergeOptions{}, "a.infgo", "b.infgo")
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+
+	if len(recs) != 8 {
+		t.Fatalf("records: got %d, want 8", len(recs))
+	}
+	hdr := recs[0].Header
+	if hdr == nil {
+		t.Fatal("first record is not a header")
+	}
+	if hdr.StartedUnixMs != 1000 || hdr.NumCores != 8 {
+		t.Errorf("header: got started=%d cores=%d, want 1000 and 8", hdr.StartedUnixMs, hdr.NumCores)
+	}
+	var prev int64
+	for i, rec := range recs[1:] {
+		if rec.Sample == nil {
+			t.Fatalf("record %d is not a sample", i+1)
+		}
+		if rec.Sample.TimestampUnixMs < prev {
+			t.Errorf("record %d out of order: %d after %d", i+1, rec.Sample.TimestampUnixMs, prev)
+		}
+		prev = rec.Sample.TimestampUnixMs
+	}
+
+	if stats[0].Samples != 3 || stats[1].Samples != 4 {
+		t.Errorf("samples per source: got %d/%d, want 3/4", stats[0].Samples, stats[1].Samples)
+	}
+	if !stats[0].Overlaps(stats[1]) {
+		t.Error("interleaved sources should be reported as overlapping")
+	}
+}
+
+func TestMergeHostnames(t *testing.T) {
+	dir := t.TempDir()
+	writeLog(t, filepath.Join(dir, "a.infgo"), metrics.Header{Hostname: "old-name"}, 1000)
+	writeLog(t, filepath.Join(dir, "b.infgo"), metrics.Header{Hostname: "new-name"}, 9000)
+
+	if _, _, err := mergeFiles(t, dir, MergeOptions{}, "a.infgo", "b.infgo"); err == nil {
+		t.Fatal("expected an error merging different hosts without relabel")
+	}
+
+	stats, recs, err := mergeFiles(t, dir, MergeOptions{Hostname: "box"}, "a.infgo", "b.infgo")
+	if err != nil {
+		t.Fatalf("Merge with relabel failed: %v", err)
+	}
+	if got := recs[0].Header.Hostname; got != "box" {
+		t.Errorf("Hostname: got %q, want %q", got, "box")
+	}
+	if stats[0].Overlaps(stats[1]) {
+		t.Error("disjoint sources should not overlap")
+	}
+}
+
+func TestMergeCarriesEvents(t *testing.T) {
+	dir := t.TempDir()
+	lgr, err := New(filepath.Join(dir, "a.infgo"))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	lgr.WriteHeader(metrics.Header{Hostname: "h"})
+	lgr.WriteSample(metrics.Sample{TimestampUnixMs: 1000})
+	lgr.WriteEvent(metrics.Event{TimestampUnixMs: 2500, Kind: "marker", Message: "deploy"})
+	lgr.WriteSample(metrics.Sample{TimestampUnixMs: 3000})
+	if err := lgr.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	writeLog(t, filepath.Join(dir, "b.infgo"), metrics.Header{Hostname: "h"}, 2000)
+
+	stats, recs, err := mergeFiles(t, dir, MergeOptions{}, "a.infgo", "b.infgo")
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	if stats[0].Events != 1 {
+		t.Errorf("events from a: got %d, want 1", stats[0].Events)
+	}
+	if len(recs) != 5 {
+		t.Fatalf("records: got %d, want 5", len(recs))
+	}
+	ev := recs[3].Event
+	if ev == nil || ev.TimestampUnixMs != 2500 || ev.Message != "deploy" {
+		t.Errorf("record 3: got %+v, want the deploy event", recs[3])
+	}
+}
+
+// Process records (-log-pids) are merged in timestamp order like events.
+func TestMergeCarriesProcesses(t *testing.T) {
+	dir := t.TempDir()
+	lgr, err := New(filepath.Join(dir, "a.infgo"))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	lgr.WriteHeader(metrics.Header{Hostname: "h"})
+	lgr.WriteSample(metrics.Sample{TimestampUnixMs: 1000})
+	lgr.WriteProcess(metrics.Process{TimestampUnixMs: 2500, Pid: 42, Name: "postgres", RssBytes: 1 << 20})
+	lgr.WriteSample(metrics.Sample{TimestampUnixMs: 3000})
+	if err := lgr.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	writeLog(t, filepath.Join(dir, "b.infgo"), metrics.Header{Hostname: "h"}, 2000)
+
+	stats, recs, err := mergeFiles(t, dir, MergeOptions{}, "a.infgo", "b.infgo")
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	if stats[0].Processes != 1 || stats[0].Skipped != 0 {
+		t.Errorf("stats of a: got %+v, want 1 process and none skipped", stats[0])
+	}
+	if len(recs) != 5 {
+		t.Fatalf("records: got %d, want 5", len(recs))
+	}
+	if p := recs[3].Process; p == nil || p.TimestampUnixMs != 2500 || p.Pid != 42 || p.Name != "postgres" {
+		t.Errorf("record 3: got %+v, want the postgres process", recs[3])
+	}
+}
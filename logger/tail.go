@@ -0,0 +1,77 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package logger
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+)
+
+// Tail returns the whole records among the last window bytes before end
+// in the uncompressed capture at path, oldest first.  end must fall
+// between two records, as a capture's size or a Logger's Appended does.
+//
+// Records carry no markers to read backwards by, so Tail reads the window
+// and takes the first offset in it from which records of the known types
+// chain exactly to end, decoding as they go, for a record boundary.  A
+// long capture costs no more to tail than a short one.
+func Tail(path string, end, window int64) ([]Record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("reader: open %q: %w", path, err)
+	}
+	defer f.Close()
+	var got [8]byte
+	if _, err := f.ReadAt(got[:], 0); err != nil || got != magic {
+		return nil, fmt.Errorf("reader: %q is not an uncompressed infgo log file", path)
+	}
+	start := max(int64(len(magic)), end-window)
+	if start >= end {
+		return nil, nil
+	}
+	buf := make([]byte, end-start)
+	if _, err := f.ReadAt(buf, start); err != nil {
+		return nil, fmt.Errorf("reader: tail %q: %w", path, err)
+	}
+	// The window taking in the whole capture starts on the first record.
+	last := len(buf)
+	if start == int64(len(magic)) {
+		last = 1
+	}
+	for off := range last {
+		if recs, ok := chain(buf[off:]); ok {
+			return recs, nil
+		}
+	}
+	return nil, fmt.Errorf("reader: tail %q: no whole records in the last %d bytes", path, len(buf))
+}
+
+// chain decodes b as a run of whole records of the known types, or
+// reports false where it is not one.
+func chain(b []byte) ([]Record, bool) {
+	n := 0
+	for pos := 0; pos < len(b); n++ {
+		if len(b)-pos < 5 {
+			return nil, false
+		}
+		rt := RecordType(b[pos])
+		size := binary.BigEndian.Uint32(b[pos+1:])
+		if rt < RecordTypeHeader || rt > RecordTypeProcess || size > maxPayloadBytes || int64(size) > int64(len(b)-pos-5) {
+			return nil, false
+		}
+		pos += 5 + int(size)
+	}
+	recs := make([]Record, n)
+	pos := 0
+	for i := range recs {
+		size := int(binary.BigEndian.Uint32(b[pos+1:]))
+		recs[i].Type = RecordType(b[pos])
+		if recs[i].decode(b[pos+5:pos+5+size]) != nil {
+			return nil, false
+		}
+		pos += 5 + size
+	}
+	return recs, true
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package logger
+
+import (
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// stamps is the timestamps of the samples among recs.
+func stamps(recs []Record) []int64 {
+	var out []int64
+	for _, rec := range recs {
+		if rec.Sample != nil {
+			out = append(out, rec.Sample.TimestampUnixMs)
+		}
+	}
+	return out
+}
+
+// The records are found from the end, whatever the window cuts into.
+func TestTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "node.infgo")
+	lgr, err := New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lgr.EmbedSchema()
+	lgr.WriteHeader(metrics.Header{Hostname: "node"})
+	for i := range 50 {
+		lgr.WriteSample(metrics.Sample{TimestampUnixMs: int64(i), CpuTotal: 2, CpuCores: []float64{2, 2}, MemPercent: 2})
+		if i == 20 {
+			lgr.WriteEvent(metrics.Event{Kind: "note", Message: "\x02\x00\x00\x00\x01"})
+		}
+	}
+	if err := lgr.Close(); err != nil {
+		t.Fatal(err)
+	}
+	info, _ := os.Stat(path)
+
+	recs, err := Tail(path, info.Size(), 1<<20)
+	if err != nil || len(stamps(recs)) != 50 || recs[0].Header == nil || recs[1].Type != RecordTypeSchema {
+		t.Fatalf("the whole capture: got %d records, %v", len(recs), err)
+	}
+	for window := int64(1); window < 300; window += 7 {
+		recs, err := Tail(path, info.Size(), window)
+		got := stamps(recs)
+		if err != nil && window >= 100 || len(got) > 0 && (got[len(got)-1] != 49 || !slices.IsSorted(got) || int64(len(got))*30 > window) {
+			t.Errorf("window %d: got %v, %v", window, got, err)
+		}
+	}
+	recs, _ = Tail(path, info.Size(), 200)
+	if len(recs) == 0 || len(recs) != len(stamps(recs)) {
+		t.Errorf("got %d records, want the last samples only", len(recs))
+	}
+
+	var gz []byte
+	if gz, err = os.ReadFile(path); err != nil {
+		t.Fatal(err)
+	}
+	zpath := filepath.Join(t.TempDir(), "node.infgo.gz")
+	zf, _ := os.Create(zpath)
+	zw := gzip.NewWriter(zf)
+	zw.Write(gz)
+	zw.Close()
+	zf.Close()
+	if _, err := Tail(zpath, int64(len(gz)), 1<<20); err == nil {
+		t.Error("gzip: got no error")
+	}
+}
+
+// Process records (-log-pids) are whole records like the rest.
+func TestTailProcesses(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "node.infgo")
+	lgr, err := New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lgr.WriteHeader(metrics.Header{Hostname: "node"})
+	for i := range 5 {
+		lgr.WriteSample(metrics.Sample{TimestampUnixMs: int64(i)})
+		lgr.WriteProcess(metrics.Process{TimestampUnixMs: int64(i), Pid: 42, Name: "postgres"})
+	}
+	if err := lgr.Close(); err != nil {
+		t.Fatal(err)
+	}
+	info, _ := os.Stat(path)
+	recs, err := Tail(path, info.Size(), 1<<20)
+	if err != nil || len(recs) != 11 || !slices.Equal(stamps(recs), []int64{0, 1, 2, 3, 4}) || recs[10].Process == nil {
+		t.Errorf("got %d records, %v", len(recs), err)
+	}
+}
+
+// Append drops a record cut off at the end, and the new records follow
+// the old ones.
+func TestAppend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "node.infgo")
+	lgr, err := Append(path)
+	if err != nil || lgr.Appended() != 0 {
+		t.Fatalf("a new capture: got %v, %v", lgr.Appended(), err)
+	}
+	lgr.WriteHeader(metrics.Header{Hostname: "node"})
+	lgr.WriteSample(metrics.Sample{TimestampUnixMs: 1})
+	lgr.WriteSample(metrics.Sample{TimestampUnixMs: 2})
+	lgr.Close()
+	info, _ := os.Stat(path)
+	if err := os.Truncate(path, info.Size()-2); err != nil {
+		t.Fatal(err)
+	}
+
+	lgr, err = Append(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	end := lgr.Appended()
+	lgr.WriteHeader(metrics.Header{Hostname: "node"})
+	lgr.WriteSample(metrics.Sample{TimestampUnixMs: 3})
+	lgr.Close()
+	if recs, err := Tail(path, end, 1<<20); err != nil || !slices.Equal(stamps(recs), []int64{1}) {
+		t.Errorf("before the restart: got %v, %v", stamps(recs), err)
+	}
+	rd, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rd.Close()
+	var got []int64
+	headers := 0
+	for {
+		rec, err := rd.Next()
+		if err != nil {
+			break
+		}
+		if rec.Header != nil {
+			headers++
+		}
+		if rec.Sample != nil {
+			got = append(got, rec.Sample.TimestampUnixMs)
+		}
+	}
+	if headers != 2 || !slices.Equal(got, []int64{1, 3}) {
+		t.Errorf("got %d headers and samples %v", headers, got)
+	}
+
+	if err := os.WriteFile(path, []byte("not a capture"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Append(path); err == nil {
+		t.Error("not a capture: got no error")
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	syslogger "github.com/ALH477/infgo/logger"
+)
+
+// ── Automatic log names ───────────────────────────────────────────────────────
+
+// autoLog is the -log value that names the capture after the host and the
+// time, in -log-dir: web01-20260114-143207.infgo.  The time is UTC, as in
+// the names rotation gives segments.
+const autoLog = "auto"
+
+// autoLogPath returns the path for a capture of hostname started at now,
+// creating dir if need be.  A leading ~/ in dir is the home directory,
+// for -log-dir=~/captures, which the shell leaves alone.
+func autoLogPath(dir, hostname string, now time.Time) (string, error) {
+	if dir == "" {
+		dir = "."
+	}
+	if rest, ok := strings.CutPrefix(dir, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, rest)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("-log-dir: %w", err)
+	}
+	name := fileSafe(hostname) + "-" + now.UTC().Format("20060102-150405") + ".infgo"
+	return filepath.Join(dir, name), nil
+}
+
+// fileSafe keeps the letters, digits, dots, dashes and underscores of a
+// hostname and replaces anything else with an underscore.
+func fileSafe(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		}
+		return '_'
+	}, s)
+	if s == "" || s == "." || s == ".." {
+		return "infgo"
+	}
+	return s
+}
+
+// targetHost is the host of a -connect url or -ssh target, which names
+// the capture of a remote machine.
+func targetHost(target string) string {
+	if _, rest, ok := strings.Cut(target, "://"); ok {
+		target = rest
+	}
+	target, _, _ = strings.Cut(target, "/")
+	if i := strings.LastIndex(target, "@"); i >= 0 {
+		target = target[i+1:]
+	}
+	if host, _, err := net.SplitHostPort(target); err == nil {
+		target = host
+	}
+	return target
+}
+
+// openLog creates the -log file, or with add opens it to append to.  One
+// that was named automatically is never overwritten: two captures started
+// in the same second fail rather than share a name.
+func openLog(path string, auto, add bool) (*syslogger.Logger, error) {
+	switch {
+	case add:
+		return syslogger.Append(path)
+	case auto:
+		return syslogger.NewExclusive(path)
+	default:
+		return syslogger.New(path)
+	}
+}
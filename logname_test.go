@@ -0,0 +1,73 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAutoLogPath(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "captures", "2026")
+	now := time.Date(2026, 1, 14, 14, 32, 7, 0, time.UTC)
+	got, err := autoLogPath(dir, "web01", now.In(time.FixedZone("EST", -5*3600)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dir, "web01-20260114-143207.infgo"); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	if fi, err := os.Stat(dir); err != nil || !fi.IsDir() {
+		t.Errorf("-log-dir was not created: %v", err)
+	}
+
+	// A second capture in the same second is refused, not overwritten.
+	lgr, err := openLog(got, true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lgr.Close()
+	if _, err := openLog(got, true, false); err == nil {
+		t.Error("openLog overwrote an automatically named capture")
+	}
+	if lgr, err := openLog(got, false, false); err != nil {
+		t.Errorf("an explicit -log path: got %v, want it truncated", err)
+	} else {
+		lgr.Close()
+	}
+}
+
+func TestFileSafe(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"web01", "web01"},
+		{"db-2.example.com", "db-2.example.com"},
+		{"fe80::1", "fe80__1"},
+		{"a/b c", "a_b_c"},
+		{"..", "infgo"},
+		{"", "infgo"},
+	}
+	for _, tt := range tests {
+		if got := fileSafe(tt.in); got != tt.want {
+			t.Errorf("fileSafe(%q): got %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTargetHost(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"http://web01:9100", "web01"},
+		{"https://web01.example.com/metrics", "web01.example.com"},
+		{"admin@db2", "db2"},
+		{"admin@db2:2222", "db2"},
+		{"[fe80::1]:9100", "fe80::1"},
+		{"web01", "web01"},
+	}
+	for _, tt := range tests {
+		if got := targetHost(tt.in); got != tt.want {
+			t.Errorf("targetHost(%q): got %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -8,761 +8,588 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
-	"math"
 	"os"
-	"runtime"
 	"strings"
+	"sync/atomic"
 	"time"
 
-	"github.com/charmbracelet/bubbles/progress"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
-	"github.com/shirou/gopsutil/v3/cpu"
 	"github.com/shirou/gopsutil/v3/host"
-	"github.com/shirou/gopsutil/v3/load"
-	"github.com/shirou/gopsutil/v3/mem"
 
-	syslogger "github.com/ALH477/infgo/logger"
-	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ui"
 )
 
-// ── Tuning constants ──────────────────────────────────────────────────────────
-
-const (
-	// statsInterval is how often gopsutil is queried for new readings.
-	statsInterval = 500 * time.Millisecond
-
-	// animInterval drives the spinner and live-dot pulse; kept well below the
-	// stats interval so animations stay smooth without any extra I/O.
-	animInterval = 110 * time.Millisecond
-
-	// historyLen is the number of samples retained for sparkline graphs.
-	// At 500 ms per sample this represents a 19-second rolling window.
-	historyLen = 38
-
-	// maxCoresShown caps the per-core grid so it doesn't overflow on
-	// machines with many logical CPUs (e.g. 32-core servers).
-	maxCoresShown = 8
-
-	// minWidth / maxWidth are the content-width bounds used by innerWidth().
-	minInnerWidth = 68
-	maxInnerWidth = 102
-)
+// ── Local readings ────────────────────────────────────────────────────────────
 
 // sparkChars is the Unicode block-element ramp used for sparklines.
-var sparkChars = []rune{'▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
-
-// spinnerFrames is a 10-frame braille spinner.
-var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
-
-// liveDotColors cycles to produce a breathing green pulse effect.
-var liveDotColors = []lipgloss.Color{"#10b981", "#34d399", "#6ee7b7", "#34d399"}
+var sparkChars = ui.SparkChars
 
-// ── Colour palette ────────────────────────────────────────────────────────────
-
-var (
-	cViolet  = lipgloss.Color("#a78bfa")
-	cViolet2 = lipgloss.Color("#7c3aed")
-	cCyan    = lipgloss.Color("#06b6d4")
-	cGreen   = lipgloss.Color("#10b981")
-	cAmber   = lipgloss.Color("#f59e0b")
-	cRed     = lipgloss.Color("#ef4444")
-	cGray700 = lipgloss.Color("#374151")
-	cGray500 = lipgloss.Color("#6b7280")
-	cGray50  = lipgloss.Color("#f9fafb")
-)
-
-// ── Package-level base styles ─────────────────────────────────────────────────
+// fetchStats runs in a Bubble Tea goroutine (returned as a tea.Cmd) so it
+// never blocks the event loop.
 //
-// These are intentionally immutable value types; every .Foreground() /
-// .Bold() call on them returns a *new* style, leaving the originals intact.
-
-var (
-	boldSt   = lipgloss.NewStyle().Bold(true)
-	dimSt    = lipgloss.NewStyle().Foreground(cGray500)
-	brightSt = lipgloss.NewStyle().Foreground(cGray50)
-	labelSt  = lipgloss.NewStyle().Bold(true).Foreground(cViolet)
-	accentSt = lipgloss.NewStyle().Foreground(cCyan)
-)
-
-// ── Tea messages ──────────────────────────────────────────────────────────────
-
-// animTickMsg is sent by the fast animation timer (110 ms).
-type animTickMsg time.Time
-
-// statsTickMsg is sent by the slower stats timer (500 ms).
-type statsTickMsg time.Time
-
-// statsMsg carries a fresh snapshot of system metrics.
-type statsMsg struct {
-	cpuTotal   float64   // aggregate CPU % (averaged across all cores)
-	cpuCores   []float64 // per-logical-core CPU %
-	memPercent float64
-	memUsedGB  float64
-	memTotalGB float64
-	load1      float64
-	load5      float64
-	load15     float64
+// FIX: Previously this called cpu.Percent(0, false) *and* cpu.Percent(0, true)
+// in sequence.  Because interval=0 means "delta since last call", the second
+// call measured a near-zero interval and returned garbage (0 % or 100 %).
+// We now call only the per-core variant and derive the aggregate by averaging,
+// which is consistent and requires a single kernel round-trip.
+//
+// A reading still in flight when ctx is cancelled (the user quit while
+// gopsutil was stuck on a slow /proc) is discarded rather than delivered.
+// busy, set by the caller, is cleared once the reading returns.  due is
+// the deadline of the tick the reading is for; zero for one off schedule.
+func fetchStats(ctx context.Context, busy *atomic.Bool, due time.Time) tea.Cmd {
+	return func() tea.Msg {
+		defer busy.Store(false)
+		msg := readStats(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		msg.Due = due
+		return msg
+	}
 }
 
-// sysInfoMsg carries one-time host metadata fetched on startup.
-type sysInfoMsg struct {
-	hostname string
-	platform string
-	uptime   uint64 // seconds since boot
+// readStats performs one blocking round of gopsutil queries.  It is shared
+// by the TUI (via fetchStats), the headless collector and `infgo check`.
+func readStats(ctx context.Context) ui.Reading {
+	return localStats.read(ctx)
 }
 
-// ── Model ─────────────────────────────────────────────────────────────────────
-
-// model is the single source of truth for the entire TUI (Elm Architecture).
-type model struct {
-	// Terminal geometry
-	width  int
-	height int
-
-	// CPU state
-	cpuTotal   float64
-	cpuPrev    float64   // reading from the previous tick; used for trend arrow
-	cpuCores   []float64 // per-core readings; may be nil before first fetch
-	cpuHistory []float64 // rolling ring of historyLen readings
-	cpuPeak    float64   // session high-watermark
-
-	// Memory state
-	memPercent float64
-	memUsedGB  float64
-	memTotalGB float64
-	memHistory []float64
-
-	// Load averages (unsupported on Windows; gopsutil returns 0 gracefully)
-	load1  float64
-	load5  float64
-	load15 float64
-
-	// Host info
-	hostname string
-	platform string
-	uptime   uint64
-	numCores int // logical CPU count, set once from runtime.NumCPU()
-
-	// Animation counters (driven by animTick, no I/O)
-	spinFrame  int
-	liveDotIdx int
-	frameCount int
-
-	// Bubbles progress bar for memory (handles its own easing animation).
-	memProgress progress.Model
-
-	// ready is false until the first statsMsg arrives; prevents a blank frame.
-	ready bool
-
-	// logger writes binary protobuf records to a .infgo file.
-	// nil when -log flag is not provided.
-	logger  *syslogger.Logger
-	logPath string // display-only; shown in the footer when active
+// fetchSysInfo is dispatched once at startup; result cached in model.
+func fetchSysInfo() tea.Cmd {
+	return func() tea.Msg { return readSysInfo() }
 }
 
-func initialModel() model {
-	p := progress.New(
-		progress.WithGradient("#7c3aed", "#06b6d4"),
-		progress.WithoutPercentage(), // we render our own value
-		progress.WithWidth(50),
-	)
-	return model{
-		width:       80,
-		height:      24,
-		cpuHistory:  make([]float64, historyLen),
-		memHistory:  make([]float64, historyLen),
-		numCores:    runtime.NumCPU(),
-		memProgress: p,
+// readSysInfo queries host metadata; shared with the headless collector.
+func readSysInfo() ui.SysInfo {
+	info, err := host.Info()
+	if err != nil {
+		return ui.SysInfo{Hostname: "unknown", Platform: "unknown"}
+	}
+	_, offset := time.Now().Zone()
+	return ui.SysInfo{
+		Hostname:  info.Hostname,
+		Platform:  info.Platform + " · " + info.KernelArch,
+		Uptime:    info.Uptime,
+		Zone:      localZoneName(),
+		UTCOffset: offset,
 	}
 }
 
-// ── Commands ──────────────────────────────────────────────────────────────────
-
-func animTick() tea.Cmd {
-	return tea.Tick(animInterval, func(t time.Time) tea.Msg {
-		return animTickMsg(t)
-	})
-}
-
-func statsTick() tea.Cmd {
-	return tea.Tick(statsInterval, func(t time.Time) tea.Msg {
-		return statsTickMsg(t)
-	})
-}
+// ── Entry ─────────────────────────────────────────────────────────────────────
 
-// fetchStats runs in a Bubble Tea goroutine (returned as a tea.Cmd) so it
-// never blocks the event loop.
-//
-// FIX: Previously this called cpu.Percent(0, false) *and* cpu.Percent(0, true)
-// in sequence.  Because interval=0 means "delta since last call", the second
-// call measured a near-zero interval and returned garbage (0 % or 100 %).
-// We now call only the per-core variant and derive the aggregate by averaging,
-// which is consistent and requires a single kernel round-trip.
-func fetchStats() tea.Cmd {
-	return func() tea.Msg {
-		// Per-core readings; interval=0 means delta since the previous call
-		// (gopsutil stores the last sample in package-level state).
-		cores, err := cpu.Percent(0, true)
-		if err != nil || len(cores) == 0 {
-			// Return a zero-value msg; model keeps its previous readings.
-			return statsMsg{}
+func main() {
+	// Offline tools (`infgo trim …`) bypass the TUI entirely.
+	if len(os.Args) > 1 {
+		if sc := lookupSubcommand(os.Args[1]); sc != nil {
+			runSubcommand(sc, os.Args[2:])
 		}
+	}
 
-		// Derive aggregate by averaging — avoids a second kernel round-trip
-		// and keeps both readings temporally consistent.
-		var total float64
-		for _, c := range cores {
-			total += c
+	logPath := flag.String("log", "", "write activity log to `file.infgo` (binary protobuf; - for stdout with -headless; auto for host-time.infgo in -log-dir)")
+	logAppend := flag.Bool("log-append", false, "add to an existing -log capture rather than overwrite it; the sparklines start from its last readings")
+	logSchema := flag.Bool("log-schema", false, "embed metrics.proto in the -log capture after its header, so that it can be decoded without infgo (see infgo schema)")
+	minFree := addMinFreeFlag(flag.CommandLine)
+	disks := flag.String("disks", "", "record the usage of the filesystems holding these comma-separated `paths` (default / and the -log file's; none for no disks)")
+	forceLog := flag.Bool("force", false, "start a -log even with less than -min-free free; nothing is written until there is more")
+	logDir := flag.String("log-dir", "", "with -log auto, or on its own, record to an automatically named capture in `dir`, created if need be")
+	listen := flag.String("listen", "", "serve Prometheus /metrics, /healthz and the /api/v1 endpoints on `addr`, e.g. :9804")
+	grpcListen := flag.String("grpc-listen", "", "serve the gRPC Infgo service on `addr`, e.g. :9805")
+	tlsCert := flag.String("tls-cert", "", "serve -listen and -grpc-listen over TLS with the certificate in `file` (PEM; needs -tls-key)")
+	tlsKey := flag.String("tls-key", "", "private key `file` for -tls-cert (PEM)")
+	clientCA := flag.String("client-ca", "", "require -grpc-listen clients to present a certificate signed by the CAs in `file` (PEM)")
+	basicAuth := flag.String("basic-auth", os.Getenv("INFGO_BASIC_AUTH"), "require `user:pass` on every endpoint except /healthz (default $INFGO_BASIC_AUTH)")
+	authToken := flag.String("auth-token", os.Getenv("INFGO_AUTH_TOKEN"), "require the bearer `token` on every endpoint except /healthz (default $INFGO_AUTH_TOKEN)")
+	cors := flag.String("cors", "", "allow browsers on `origin` (* for any) to read the -listen /api/v1 endpoints")
+	connect := flag.String("connect", "", "display another infgo's -listen `url` instead of this host, e.g. http://node7:9804; a comma-separated list shows a multi-host grid")
+	sshTarget := flag.String("ssh", "", "display `user@host[:port]` by running infgo (or reading /proc) over ssh; nothing needs to listen remotely")
+	replayPath := flag.String("replay", "", "play the capture `file.infgo` in the TUI instead of this host, a sample per half second")
+	baselinePath := flag.String("baseline", "", "with -replay, draw the capture `file.infgo` under its CPU and memory histories, lined up by time since the start, and compare the two")
+	sshKey := flag.String("ssh-key", "", "private key `file` for -ssh (default: ssh-agent, then ~/.ssh/id_*)")
+	influxURL := flag.String("influx-url", "", "push samples to the InfluxDB v2 server at `url`, e.g. http://influx:8086")
+	influxToken := flag.String("influx-token", os.Getenv("INFLUX_TOKEN"), "InfluxDB API `token` (default $INFLUX_TOKEN)")
+	influxOrg := flag.String("influx-org", "", "InfluxDB `organization` for -influx-url")
+	influxBucket := flag.String("influx-bucket", "", "InfluxDB `bucket` for -influx-url")
+	var remoteWrite remoteWriteConfig
+	flag.StringVar(&remoteWrite.url, "remote-write", "", "push samples to the Prometheus remote-write `url`, e.g. https://prom/api/v1/write")
+	flag.StringVar(&remoteWrite.token, "remote-write-token", os.Getenv("INFGO_REMOTE_WRITE_TOKEN"), "bearer `token` for -remote-write (default $INFGO_REMOTE_WRITE_TOKEN)")
+	flag.StringVar(&remoteWrite.user, "remote-write-user", "", "basic-auth user `name` for -remote-write")
+	flag.StringVar(&remoteWrite.pass, "remote-write-pass", os.Getenv("INFGO_REMOTE_WRITE_PASS"), "basic-auth `password` for -remote-write (default $INFGO_REMOTE_WRITE_PASS)")
+	graphite := flag.String("graphite", "", "push samples to the Carbon plaintext listener at `host[:port]` (default port 2003)")
+	graphitePrefix := flag.String("graphite-prefix", "infgo", "metric path `prefix` for -graphite; the hostname follows it")
+	ship := flag.String("ship", "", "stream samples to the \"infgo collect\" aggregator at `host[:port]` (default port "+collectPort+")")
+	var alertRules []alertRule
+	flag.Func("alert", "alert when `metric<op>N` holds on live samples, e.g. cpu>90 or load1>=8 (repeatable)", func(v string) error {
+		r, err := parseAlertRule(v)
+		if err != nil {
+			return err
 		}
-		total /= float64(len(cores))
-
-		vm, err := mem.VirtualMemory()
+		alertRules = append(alertRules, r)
+		return nil
+	})
+	idleFloorPct := flag.Float64("idle-floor", idleFloor, "CPU `percent` below which the machine counts as idle")
+	forecastFor := flag.Duration("forecast-window", ui.ForecastWindow, "fit the memory forecast to the readings of the last `D` (0 to disable)")
+	tickerSpec := flag.String("ticker", "", "rotate these comma-separated `items` on a line above the footer: process (with -users), disk, power, temp (on a Pi), or all")
+	anomalySigma := flag.Float64("anomaly-sigma", 3, "mark CPU and memory readings more than `K` standard deviations above those of the last few minutes as unusual (0 to disable)")
+	idleFor := flag.Duration("idle-after", idleAfter, "log idle_start once the CPU has been below -idle-floor this long, and idle_end when it rises again (0 to disable)")
+	alertFor := flag.Duration("alert-for", alertHold, "how long an -alert condition must hold (or stop holding) before the alert starts (or clears)")
+	var webhooks webhookConfig
+	flag.Func("alert-webhook", "POST alert start/clear events as JSON to `url` (repeatable)", func(v string) error {
+		webhooks.urls = append(webhooks.urls, v)
+		return nil
+	})
+	flag.Func("alert-slack", "post alerts to the Slack incoming webhook `url` (repeatable)", func(v string) error {
+		webhooks.slack = append(webhooks.slack, v)
+		return nil
+	})
+	flag.Func("alert-discord", "post alerts to the Discord webhook `url` (repeatable)", func(v string) error {
+		webhooks.discord = append(webhooks.discord, v)
+		return nil
+	})
+	flag.Func("alert-email", "email alerts to `address` (repeatable; needs -smtp-host)", func(v string) error {
+		webhooks.email.to = append(webhooks.email.to, v)
+		return nil
+	})
+	flag.StringVar(&webhooks.email.host, "smtp-host", "", "SMTP server `host[:port]` for -alert-email (default port 587, STARTTLS when offered)")
+	flag.StringVar(&webhooks.email.user, "smtp-user", "", "SMTP login `name`; the password is only sent over STARTTLS")
+	flag.StringVar(&webhooks.email.pass, "smtp-pass", os.Getenv("INFGO_SMTP_PASS"), "SMTP `password` (default $INFGO_SMTP_PASS)")
+	flag.StringVar(&webhooks.email.from, "smtp-from", "", "sender `address` for -alert-email (default -smtp-user if it is an address, else infgo@hostname)")
+	flag.DurationVar(&webhooks.cooldown, "alert-cooldown", alertCooldown, "report a rule that fires again within this window of its last chat or email message as flapping; 0 disables")
+	flag.StringVar(&webhooks.secret, "alert-secret", os.Getenv("INFGO_ALERT_SECRET"), "sign webhook bodies with HMAC-SHA256 using `secret` (default $INFGO_ALERT_SECRET)")
+	flag.DurationVar(&webhooks.timeout, "alert-timeout", alertTimeout, "timeout for each webhook request")
+	flag.IntVar(&webhooks.retries, "alert-retries", alertRetries, "retry a failed webhook delivery at most `N` times")
+	rotateEvery := flag.Duration("rotate-every", 0, "with -headless, move the -log aside and start a new segment every `d`, e.g. 1h")
+	var upload uploadConfig
+	flag.StringVar(&upload.target, "upload", "", "upload each rotated -log segment to `s3://bucket/prefix` (credentials from AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY)")
+	flag.StringVar(&upload.endpoint, "upload-endpoint", "", "S3-compatible service `url` for -upload, e.g. http://minio:9000 (default: AWS in $AWS_REGION)")
+	flag.IntVar(&upload.keep, "upload-keep", 0, "keep the newest `N` uploaded segments on disk; older ones are deleted once uploaded")
+	controlPath := flag.String("control", "", "with -headless, accept `infgo ctl` commands on the unix socket at `path`")
+	controlMode := os.FileMode(0o600)
+	flag.Func("control-mode", "permissions of the -control socket, in octal (default 0600)", func(v string) error {
+		m, err := parseFileMode(v)
+		controlMode = m
+		return err
+	})
+	usersPanelOn := flag.Bool("users", false, "add a USERS panel: CPU and memory summed by the user running each process")
+	tempPanelOn := flag.Bool("temp-panel", true, "show a TEMP panel of the hottest temperature sensor of each group, where the machine has any")
+	pressurePanelOn := flag.Bool("pressure-panel", true, "show a PRESSURE panel of the time tasks stalled for the CPU, memory and I/O, where the kernel reports it")
+	fahrenheit := flag.Bool("fahrenheit", false, "show temperatures in °F; f switches while running")
+	hostWide := flag.Bool("host", false, "read the whole host's CPU and memory even in a container whose cgroup limits them")
+	var pids []int32
+	flag.Func("pid", "add a PID panel following the process `pid`: its CPU, memory, threads and open files (repeatable)", func(v string) error {
+		pid, err := parsePid(v)
 		if err != nil {
-			return statsMsg{cpuTotal: total, cpuCores: cores}
+			return err
 		}
-
-		// load.Avg() is a no-op on Windows; gopsutil returns (nil, nil) there.
-		avg, _ := load.Avg()
-		var l1, l5, l15 float64
-		if avg != nil {
-			l1, l5, l15 = avg.Load1, avg.Load5, avg.Load15
+		pids = append(pids, pid)
+		return nil
+	})
+	logPids := flag.Bool("log-pids", false, "record each reading of the -pid processes in the -log capture, for infgo analyze")
+	gpuPanelOn := flag.Bool("gpu-panel", true, "show a GPU panel of each NVIDIA GPU's load, memory and temperature, from nvidia-smi, where it is installed")
+	procsPanelOn := flag.Bool("procs", false, "add a PROCESSES panel: the five busiest processes by CPU, with their memory")
+	diskPanelOn := flag.Bool("disk-panel", true, "show a DISK panel under MEMORY: the usage of each mounted filesystem, a bar for each of the fullest four")
+	diskIO := flag.Bool("disk-io", false, "add a DISK I/O panel: the disks' read and write throughput, summed, or each disk's (key d)")
+	netPanelOn := flag.Bool("net-panel", true, "show a NET panel: bytes received and sent a second by the network interfaces, summed, or each in turn (key n)")
+	retransWarnRate := flag.Float64("retrans-warn", ui.RetransWarn, "show the NET panel's TCP retransmits in amber from `N` segments a second")
+	retransCritRate := flag.Float64("retrans-crit", ui.RetransCrit, "show the NET panel's TCP retransmits in red from `N` segments a second")
+	pseudoFS := flag.Bool("pseudo-fs", false, "list tmpfs, overlay, squashfs and the other pseudo filesystems in the DISK panel too")
+	saveLayout := flag.Bool("save-layout", false, "on quit, save which panels are collapsed (keys 1-9, 0 and alt+1 on) for later sessions to start with")
+	interval := flag.Duration("interval", ui.DefaultInterval, "take a reading every `d`, down to 50ms; below 250ms the display and log flushes are batched")
+	cpuProfile := flag.String("profile", "", "write a CPU profile to `file` on exit, for go tool pprof")
+	memProfile := flag.String("memprofile", "", "write a heap profile to `file` on exit, for go tool pprof")
+	addFormatFlags(flag.CommandLine)
+	forceTUI := flag.Bool("force-tui", false, "start the TUI even when stdout is not a terminal, rather than printing a line per sample")
+	headlessMode := flag.Bool("headless", false, "collect without the TUI until SIGINT/SIGTERM (needs -log, -listen, -grpc-listen, a push target or an alert destination)")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: infgo [-log <file.infgo>] [-listen <addr>] [-grpc-listen <addr>] [-connect <url> | -ssh <user@host>] [-headless]\n       infgo <command> [args]\n\nFlags:\n")
+		flag.PrintDefaults()
+		printSubcommands()
+	}
+	flag.Parse()
+	serve := serveConfig{
+		addr: *listen, corsOrigin: *cors, grpcAddr: *grpcListen,
+		tlsCert: *tlsCert, tlsKey: *tlsKey, clientCA: *clientCA,
+		auth: listenAuth{token: *authToken},
+	}
+	push := pushConfig{
+		influx:         influxConfig{url: *influxURL, token: *influxToken, org: *influxOrg, bucket: *influxBucket},
+		remoteWrite:    remoteWrite,
+		graphite:       *graphite,
+		graphitePrefix: *graphitePrefix,
+		ship:           *ship,
+	}
+	if *logDir != "" && *logPath == "" {
+		*logPath = autoLog
+	}
+	// With stdout redirected, the TUI would only write escape sequences
+	// into it; print a line per sample instead.
+	plain := !*headlessMode && !*forceTUI && !stdoutIsTerminal()
+	sf := startFlags{
+		set:      visited(flag.CommandLine),
+		headless: *headlessMode, plain: plain,
+		logPath: *logPath, logDir: *logDir, logAppend: *logAppend, disks: *disks,
+		connect: *connect, ssh: *sshTarget, replay: *replayPath, baseline: *baselinePath,
+		interval: *interval,
+		serve:    serve, basicAuth: *basicAuth, push: push,
+		alerts: len(alertRules), alertFor: *alertFor, webhooks: webhooks,
+		idleFloor: *idleFloorPct, idleFor: *idleFor, forecastFor: *forecastFor,
+		anomalySigma: *anomalySigma, retransWarn: *retransWarnRate, retransCrit: *retransCritRate, ticker: *tickerSpec, users: *usersPanelOn, procs: *procsPanelOn, saveLayout: *saveLayout,
+		diskPanel: *diskPanelOn, diskIO: *diskIO, pids: len(pids), control: *controlPath, rotateEvery: *rotateEvery, upload: upload,
+	}
+	if reportProblems(os.Stderr, sf.problems()) {
+		os.Exit(2)
+	}
+	serve.auth.user, serve.auth.pass, _ = parseBasicAuth(*basicAuth)
+	ticker, _ := ui.ParseTicker(*tickerSpec)
+	sources := sf.sources()
+	stopProfiles, err := startProfiles(*cpuProfile, *memProfile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "infgo: %v\n", err)
+		os.Exit(1)
+	}
+	defer func() {
+		if err := stopProfiles(); err != nil {
+			fmt.Fprintf(os.Stderr, "infgo: %v\n", err)
 		}
-
-		const gb = 1 << 30
-		return statsMsg{
-			cpuTotal:   total,
-			cpuCores:   cores,
-			memPercent: vm.UsedPercent,
-			memUsedGB:  float64(vm.Used) / gb,
-			memTotalGB: float64(vm.Total) / gb,
-			load1:      l1,
-			load5:      l5,
-			load15:     l15,
+	}()
+	pushers, err := startPushers(push)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "infgo: %v\n", err)
+		os.Exit(2)
+	}
+	if *headlessMode || plain {
+		webhooks.errs = os.Stderr
+	}
+	notifier, err := newAlertNotifier(webhooks)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "infgo: %v\n", err)
+		os.Exit(2)
+	}
+	var alerts *alertMonitor
+	if len(alertRules) > 0 {
+		alerts = newAlertMonitor(alertRules, *alertFor)
+	}
+	var (
+		pi   *piWatch
+		note string
+	)
+	if *connect == "" && *sshTarget == "" {
+		localStats = newStatsReader(gopsutilSources, *interval)
+		pi = detectPi("/")
+		rapl, err := openRAPL(raplRoot)
+		localStats.power, note = rapl, raplNote(err)
+		if !*hostWide {
+			localStats.cg = detectCgroup(os.DirFS(cgroupRoot))
 		}
 	}
-}
-
-// fetchSysInfo is dispatched once at startup; result cached in model.
-func fetchSysInfo() tea.Cmd {
-	return func() tea.Msg {
-		info, err := host.Info()
+	fp := &fingerprint{
+		args:       sanitizeArgs(flag.CommandLine, os.Args[1:]),
+		collectors: []string{"cpu", "memory", "load"},
+		config:     "none",
+	}
+	if localStats.power != nil {
+		fp.collectors = append(fp.collectors, "rapl")
+	}
+	if pi != nil {
+		fp.collectors = append(fp.collectors, "pi")
+	}
+	if localStats.cg != nil {
+		fp.collectors = append(fp.collectors, "cgroup")
+	}
+	// The host's name is needed before the log is opened, so it is read
+	// here rather than waiting for the first ui.SysInfo, which keeps the
+	// header the first record as ever.
+	autoNamed := *logPath == autoLog
+	if autoNamed {
+		host := readSysInfo().Hostname
+		if target := *connect + *sshTarget; target != "" {
+			host = targetHost(target)
+		}
+		path, err := autoLogPath(*logDir, host, time.Now())
 		if err != nil {
-			return sysInfoMsg{hostname: "unknown", platform: "unknown"}
+			fmt.Fprintf(os.Stderr, "infgo: %v\n", err)
+			os.Exit(1)
 		}
-		return sysInfoMsg{
-			hostname: info.Hostname,
-			platform: info.Platform + " · " + info.KernelArch,
-			uptime:   info.Uptime,
+		*logPath = path
+		fmt.Fprintf(os.Stderr, "infgo: recording to %s\n", path)
+	}
+	if sources == 0 {
+		mounts, err := localStats.watchDisks(*disks, *logPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "infgo: %v\n", err)
+			os.Exit(2)
+		}
+		if len(mounts) > 0 {
+			fp.collectors = append(fp.collectors, "disk")
 		}
 	}
-}
-
-// ── Init ──────────────────────────────────────────────────────────────────────
-
-func (m model) Init() tea.Cmd {
-	return tea.Batch(fetchStats(), fetchSysInfo(), animTick(), statsTick())
-}
-
-// ── Update ────────────────────────────────────────────────────────────────────
-
-// pushHistory appends val to buf, evicting the oldest element.
-// The returned slice reuses the underlying array.
-func pushHistory(buf []float64, val float64) []float64 {
-	return append(buf[1:], val)
-}
-
-func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch msg := msg.(type) {
-
-	case tea.WindowSizeMsg:
-		m.width = msg.Width
-		m.height = msg.Height
-		// Keep the Bubbles progress bar in sync with the actual terminal width.
-		m.memProgress.Width = innerWidth(msg.Width) - 6
-		return m, nil
-
-	case tea.KeyMsg:
-		if msg.String() == "q" || msg.String() == "ctrl+c" {
-			return m, tea.Quit
+	disk := newDiskGuard(*logPath, *minFree)
+	if err := disk.preflight(*forceLog, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "infgo: %v\n", err)
+		os.Exit(1)
+	}
+	if *headlessMode || plain {
+		h := headless{pushers: pushers, alerts: alerts, notifier: notifier, interval: *interval, logAuto: autoNamed,
+			logAppend: *logAppend, logSchema: *logSchema, fingerprint: fp, disk: disk,
+			idle: newIdleDetector(*idleFloorPct, *idleFor), pi: pi}
+		if *gpuPanelOn {
+			if h.gpu = detectGPU(); h.gpu != nil {
+				fp.collectors = append(fp.collectors, "gpu")
+			}
 		}
-
-	// Fast tick — only mutates animation counters; no I/O whatsoever.
-	case animTickMsg:
-		m.frameCount++
-		m.spinFrame = m.frameCount % len(spinnerFrames)
-		m.liveDotIdx = (m.frameCount / 3) % len(liveDotColors)
-		return m, animTick()
-
-	// Slow tick — schedules a stats fetch goroutine for the next cycle.
-	case statsTickMsg:
-		return m, tea.Batch(fetchStats(), statsTick())
-
-	case statsMsg:
-		// Guard against zero-value msgs emitted when gopsutil returns an error.
-		if len(msg.cpuCores) == 0 && !m.ready {
-			return m, nil
-		}
-		m.cpuPrev = m.cpuTotal
-		m.cpuTotal = msg.cpuTotal
-		m.cpuCores = msg.cpuCores
-		m.cpuHistory = pushHistory(m.cpuHistory, msg.cpuTotal)
-		if msg.cpuTotal > m.cpuPeak {
-			m.cpuPeak = msg.cpuTotal
-		}
-		m.memPercent = msg.memPercent
-		m.memUsedGB = msg.memUsedGB
-		m.memTotalGB = msg.memTotalGB
-		m.memHistory = pushHistory(m.memHistory, msg.memPercent)
-		m.load1, m.load5, m.load15 = msg.load1, msg.load5, msg.load15
-		m.ready = true
-		// Persist the sample to the activity log if logging is active.
-		if m.logger != nil {
-			_ = m.logger.WriteSample(metrics.Sample{
-				TimestampUnixMs: time.Now().UnixMilli(),
-				CpuTotal:        m.cpuTotal,
-				CpuCores:        m.cpuCores,
-				MemPercent:      m.memPercent,
-				MemUsedGB:       m.memUsedGB,
-				MemTotalGB:      m.memTotalGB,
-				Load1:           m.load1,
-				Load5:           m.load5,
-				Load15:          m.load15,
-			})
-		}
-		// SetPercent returns a FrameMsg command that drives the easing loop.
-		return m, m.memProgress.SetPercent(msg.memPercent / 100)
-
-	case sysInfoMsg:
-		m.hostname = msg.hostname
-		m.platform = msg.platform
-		m.uptime = msg.uptime
-		// Write the session header now that we know hostname and platform.
-		if m.logger != nil {
-			_ = m.logger.WriteHeader(metrics.Header{
-				Hostname:      msg.hostname,
-				Platform:      msg.platform,
-				StartedUnixMs: time.Now().UnixMilli(),
-				NumCores:      int32(m.numCores),
-			})
-		}
-		return m, nil
-
-	// Forward Bubbles frame messages so the progress bar can animate smoothly.
-	case progress.FrameMsg:
-		pm, cmd := m.memProgress.Update(msg)
-		m.memProgress = pm.(progress.Model)
-		return m, cmd
+		if h.throttleWatch = detectThrottle(); h.throttleWatch != nil {
+			fp.collectors = append(fp.collectors, "throttle")
+		}
+		if plain {
+			h.text = os.Stdout
+		}
+		if *controlPath != "" {
+			ctl, err := startControl(*controlPath, controlMode)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "infgo: %v\n", err)
+				os.Exit(1)
+			}
+			h.control = ctl
+		}
+		h.rotateEvery = *rotateEvery
+		var up *uploader
+		if upload.enabled() {
+			var err error
+			if up, err = newUploader(upload, readSysInfo().Hostname, os.Stderr); err != nil {
+				fmt.Fprintf(os.Stderr, "infgo: %v\n", err)
+				os.Exit(2)
+			}
+			up.start()
+			h.rotated = up.enqueue
+		}
+		err := runHeadless(*logPath, serve, h)
+		up.close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "infgo: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if targets := strings.Split(*connect, ","); len(targets) > 1 {
+		var feeds []ui.Feed
+		for _, t := range targets {
+			src, err := newRemoteSource(strings.TrimSpace(t))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "infgo: %v\n", err)
+				os.Exit(2)
+			}
+			feeds = append(feeds, src)
+		}
+		if err := runDashboard(feeds); err != nil {
+			fmt.Fprintf(os.Stderr, "infgo: %v\n", err)
+			os.Exit(1)
+		}
+		return
 	}
 
-	return m, nil
-}
-
-// ── View helpers ──────────────────────────────────────────────────────────────
-
-// innerWidth returns the content width clamped to [minInnerWidth, maxInnerWidth].
-// The outer View wrapper adds 2 chars of horizontal padding on each side.
-func innerWidth(termW int) int {
-	w := termW - 4
-	if w < minInnerWidth {
-		return minInnerWidth
+	col := &localCollector{stats: localStats, pi: pi}
+	rec := &recorder{pushers: pushers, alerts: alerts, notifier: notifier,
+		idle: newIdleDetector(*idleFloorPct, *idleFor), fingerprint: fp}
+	opts := []ui.Option{
+		ui.WithInterval(*interval),
+		ui.WithForecast(*forecastFor),
+		ui.WithAnomalySigma(*anomalySigma),
+		ui.WithRAPLNote(note),
+		ui.WithTicker(ticker),
+		ui.WithRecorder(rec),
 	}
-	if w > maxInnerWidth {
-		return maxInnerWidth
+	if localStats.cg != nil {
+		opts = append(opts, ui.WithCgroup(localStats.cg.limits))
 	}
-	return w
-}
-
-// loadColor maps a 0-100 percentage to a traffic-light colour.
-func loadColor(pct float64) lipgloss.Color {
-	switch {
-	case pct >= 90:
-		return cRed
-	case pct >= 70:
-		return cAmber
-	default:
-		return cGreen
+	if *diskPanelOn && sources == 0 {
+		localStats.watchMounts(*pseudoFS)
+		opts = append(opts, ui.WithDiskPanel())
 	}
-}
-
-// heatPanel returns a rounded-border panel whose border colour reacts to load.
-// The border stays neutral (gray) below 70 % to avoid visual noise.
-func heatPanel(pct float64, totalW int) lipgloss.Style {
-	bc := cGray700
-	if pct >= 70 {
-		bc = loadColor(pct)
-	}
-	return lipgloss.NewStyle().
-		BorderStyle(lipgloss.RoundedBorder()).
-		BorderForeground(bc).
-		Padding(0, 2).
-		Width(totalW)
-}
-
-// filledBar renders a heat-coded full-width Unicode block bar.
-func filledBar(pct float64, width int) string {
-	filled := int(math.Round(pct / 100 * float64(width)))
-	if filled > width {
-		filled = width
-	}
-	empty := width - filled
-	fc := loadColor(pct)
-	return lipgloss.NewStyle().Foreground(fc).Render(strings.Repeat("█", filled)) +
-		lipgloss.NewStyle().Foreground(cGray700).Render(strings.Repeat("░", empty))
-}
-
-// miniBar renders a compact heat-coded block bar using ▮/▯ runes.
-func miniBar(pct float64, width int) string {
-	filled := int(math.Round(pct / 100 * float64(width)))
-	if filled > width {
-		filled = width
-	}
-	empty := width - filled
-	fc := loadColor(pct)
-	return lipgloss.NewStyle().Foreground(fc).Render(strings.Repeat("▮", filled)) +
-		lipgloss.NewStyle().Foreground(cGray700).Render(strings.Repeat("▯", empty))
-}
-
-// sparkline renders the history slice as Unicode spark characters.
-// col is the foreground colour applied to the entire rune sequence.
-func sparkline(history []float64, width int, col lipgloss.Color) string {
-	n := len(history)
-	start := 0
-	if n > width {
-		start = n - width
-	}
-	var sb strings.Builder
-	for i := start; i < n; i++ {
-		v := history[i]
-		idx := int(v/100*float64(len(sparkChars)-1) + 0.5)
-		if idx < 0 {
-			idx = 0
-		} else if idx >= len(sparkChars) {
-			idx = len(sparkChars) - 1
-		}
-		sb.WriteRune(sparkChars[idx])
-	}
-	return lipgloss.NewStyle().Foreground(col).Render(sb.String())
-}
-
-// trendArrow compares two consecutive readings and returns a directional glyph.
-// A deadband of ±3 % prevents jitter on stable loads.
-func trendArrow(curr, prev float64) string {
-	delta := curr - prev
-	switch {
-	case delta > 3:
-		return lipgloss.NewStyle().Foreground(cRed).Render("▲")
-	case delta < -3:
-		return lipgloss.NewStyle().Foreground(cGreen).Render("▼")
-	default:
-		return dimSt.Render("─")
+	if *netPanelOn && sources == 0 {
+		localStats.netOn = true
+		opts = append(opts, ui.WithNetPanel(*retransWarnRate, *retransCritRate))
 	}
-}
-
-// formatUptime converts a seconds-since-boot value to a human-readable string.
-func formatUptime(s uint64) string {
-	d := s / 86400
-	h := (s % 86400) / 3600
-	m := (s % 3600) / 60
-	switch {
-	case d > 0:
-		return fmt.Sprintf("%dd %dh %dm", d, h, m)
-	case h > 0:
-		return fmt.Sprintf("%dh %dm", h, m)
-	default:
-		return fmt.Sprintf("%dm", m)
+	if *tempPanelOn && sources == 0 {
+		localStats.tempOn = true
 	}
-}
-
-// sparkWindowSeconds returns the total seconds covered by the history buffer.
-func sparkWindowSeconds() int {
-	return int(statsInterval/time.Millisecond) * historyLen / 1000
-}
-
-// padVisual right-pads (or truncates) s to n *visible* columns, correctly
-// accounting for ANSI escape sequences in s by using lipgloss.Width().
-//
-// FIX: the previous implementation used padRunes() which counted raw bytes /
-// runes including escape codes, causing column misalignment in the per-core
-// grid where miniBar() embeds ANSI colour sequences.
-func padVisual(s string, n int) string {
-	vw := lipgloss.Width(s)
-	if vw >= n {
-		return s
-	}
-	return s + strings.Repeat(" ", n-vw)
-}
-
-// ── Section renderers ─────────────────────────────────────────────────────────
-
-func (m model) renderHeader(iw int) string {
-	spinner := lipgloss.NewStyle().Foreground(cViolet).Render(spinnerFrames[m.spinFrame])
-	title := boldSt.Copy().Foreground(cViolet).Render("INFGO")
-	dot := lipgloss.NewStyle().Foreground(liveDotColors[m.liveDotIdx]).Bold(true).Render("●")
-	liveLabel := dimSt.Render(" LIVE")
-
-	left := spinner + "  " + title
-	right := dimSt.Render(m.hostname+"  ") + dot + liveLabel
-
-	// innerLen is the renderable width inside the border+padding box.
-	innerLen := iw + 2
-	gap := innerLen - lipgloss.Width(left) - lipgloss.Width(right)
-	if gap < 1 {
-		gap = 1
+	if *pressurePanelOn && sources == 0 {
+		localStats.psiOn = true
 	}
-
-	return lipgloss.NewStyle().
-		BorderStyle(lipgloss.ThickBorder()).
-		BorderForeground(cViolet2).
-		Padding(0, 1).
-		Width(iw + 4).
-		Render(left + strings.Repeat(" ", gap) + right)
-}
-
-func (m model) renderCPU(iw int) string {
-	barW := iw - 20
-	if barW < 10 {
-		barW = 10
+	if *fahrenheit {
+		opts = append(opts, ui.WithFahrenheit())
 	}
-
-	// ── Title row ─────────────────────────────────────────────────────────
-	pctStr := boldSt.Copy().Foreground(loadColor(m.cpuTotal)).
-		Render(fmt.Sprintf("%5.1f%%", m.cpuTotal))
-	titleRow := labelSt.Render("CPU") + "  " + pctStr + "  " +
-		trendArrow(m.cpuTotal, m.cpuPrev) + "   " +
-		dimSt.Render(fmt.Sprintf("peak %4.1f%%", m.cpuPeak))
-
-	// ── Main bar ──────────────────────────────────────────────────────────
-	bar := filledBar(m.cpuTotal, barW)
-
-	// ── Sparkline ─────────────────────────────────────────────────────────
-	spark := sparkline(m.cpuHistory, barW, cViolet)
-	sparkRow := spark + "  " + dimSt.Render(fmt.Sprintf("←%ds", sparkWindowSeconds()))
-
-	// ── Per-core 2-column grid ────────────────────────────────────────────
-	// FIX: use padVisual() (lipgloss.Width-aware) instead of the old
-	// padRunes() which miscounted ANSI escape bytes as visible characters.
-	cores := m.cpuCores
-	if len(cores) > maxCoresShown {
-		cores = cores[:maxCoresShown]
-	}
-	const coreBarW = 8
-	colW := iw/2 - 1
-
-	var coreLines []string
-	for i := 0; i < len(cores); i += 2 {
-		lCell := dimSt.Render(fmt.Sprintf("[%d] ", i)) +
-			miniBar(cores[i], coreBarW) +
-			dimSt.Render(fmt.Sprintf(" %4.1f%%", cores[i]))
-
-		var rCell string
-		if i+1 < len(cores) {
-			rCell = dimSt.Render(fmt.Sprintf("[%d] ", i+1)) +
-				miniBar(cores[i+1], coreBarW) +
-				dimSt.Render(fmt.Sprintf(" %4.1f%%", cores[i+1]))
+	if *gpuPanelOn && sources == 0 {
+		if col.gpu = detectGPU(); col.gpu != nil {
+			fp.collectors = append(fp.collectors, "gpu")
 		}
-		coreLines = append(coreLines, padVisual(lCell, colW)+" "+rCell)
-	}
-	if len(m.cpuCores) > maxCoresShown {
-		coreLines = append(coreLines,
-			dimSt.Render(fmt.Sprintf("  (+%d more cores)", len(m.cpuCores)-maxCoresShown)))
 	}
-
-	sections := append(
-		[]string{titleRow, "", bar, "", sparkRow, "", dimSt.Render("CORES")},
-		coreLines...,
-	)
-	return heatPanel(m.cpuTotal, iw+4).Render(strings.Join(sections, "\n"))
-}
-
-func (m model) renderMemory(iw int) string {
-	freeGB := m.memTotalGB - m.memUsedGB
-
-	pctStr := boldSt.Copy().Foreground(loadColor(m.memPercent)).
-		Render(fmt.Sprintf("%5.1f%%", m.memPercent))
-	titleRow := labelSt.Render("MEMORY") + "  " + pctStr
-
-	// Update width on the local copy so the bar fills the panel correctly.
-	// (This is a value receiver so the stored model is unaffected.)
-	m.memProgress.Width = iw - 2
-
-	statsRow := dimSt.Render(fmt.Sprintf(
-		"%.2f GiB used  ╱  %.2f GiB total  ╱  %.2f GiB free",
-		m.memUsedGB, m.memTotalGB, freeGB,
-	))
-
-	sparkW := iw - 14
-	if sparkW < 5 {
-		sparkW = 5
+	if sources == 0 {
+		if col.numa = detectNUMA(numaFS()); col.numa != nil {
+			fp.collectors = append(fp.collectors, "numa")
+		}
+		if col.fans = detectFans(); col.fans != nil {
+			fp.collectors = append(fp.collectors, "fans")
+		}
+		if col.throttle = detectThrottle(); col.throttle != nil {
+			fp.collectors = append(fp.collectors, "throttle")
+		}
 	}
-	spark := sparkline(m.memHistory, sparkW, cCyan)
-	sparkRow := spark + "  " + dimSt.Render(fmt.Sprintf("←%ds", sparkWindowSeconds()))
-
-	body := strings.Join([]string{
-		titleRow, "",
-		m.memProgress.View(),
-		statsRow, "",
-		sparkRow,
-	}, "\n")
-	return heatPanel(m.memPercent, iw+4).Render(body)
-}
-
-func (m model) renderSystem(w int) string {
-	rows := []struct{ k, v string }{
-		{"Host  ", m.hostname},
-		{"OS    ", m.platform},
-		{"Uptime", formatUptime(m.uptime)},
-		{"Cores ", fmt.Sprintf("%d logical", m.numCores)},
-	}
-	lines := []string{labelSt.Render("SYSTEM"), ""}
-	for _, r := range rows {
-		lines = append(lines, dimSt.Render(r.k)+"  "+brightSt.Render(r.v))
-	}
-	return lipgloss.NewStyle().
-		BorderStyle(lipgloss.RoundedBorder()).
-		BorderForeground(cGray700).
-		Padding(0, 2).
-		Width(w).
-		Render(strings.Join(lines, "\n"))
-}
-
-func (m model) renderLoad(w int) string {
-	const lbW = 9
-	maxLoad := float64(m.numCores)
-
-	// barPct normalises a load-average value against the logical CPU count.
-	barPct := func(v float64) float64 {
-		p := v / maxLoad * 100
-		if p > 100 {
-			p = 100
+	if len(pids) > 0 {
+		col.pids, rec.logPids = &pidWatcher{read: readPid}, *logPids
+		for _, pid := range pids {
+			r, err := col.pids.watch(context.Background(), pid)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "infgo: %v\n", err)
+				os.Exit(1)
+			}
+			opts = append(opts, ui.WithPID(pid, r, time.Now()))
+		}
+		fp.collectors = append(fp.collectors, "pids")
+	}
+	if *diskIO {
+		localStats.ioOn = true
+		opts = append(opts, ui.WithDiskIO())
+	}
+	if *usersPanelOn || *procsPanelOn {
+		col.procs = newProcScanner()
+	}
+	if *usersPanelOn {
+		opts = append(opts, ui.WithUsers())
+		fp.collectors = append(fp.collectors, "users")
+	}
+	if *procsPanelOn {
+		opts = append(opts, ui.WithProcs())
+		fp.collectors = append(fp.collectors, "procs")
+	}
+	layout, err := layoutPath()
+	if err == nil {
+		var collapsed []ui.Panel
+		collapsed, err = readLayout(layout)
+		opts = append(opts, ui.WithCollapsed(collapsed...))
+		if _, statErr := os.Stat(layout); statErr == nil {
+			fp.config = layout
 		}
-		return p
 	}
-
-	// FIX: previously wrapped miniBar() output in a redundant Foreground().Render()
-	// which double-escaped the ANSI sequences already present inside miniBar.
-	// Now we call miniBar directly.
-	row := func(label string, v float64) string {
-		pct := barPct(v)
-		col := loadColor(pct)
-		num := lipgloss.NewStyle().Foreground(col).Bold(true).Render(fmt.Sprintf("%.2f", v))
-		return dimSt.Render(padVisual(label, 3)) + "  " + miniBar(pct, lbW) + "  " + num
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "infgo: layout: %v\n", err)
 	}
-
-	body := strings.Join([]string{
-		labelSt.Render("LOAD AVG"), "",
-		row("1m", m.load1),
-		row("5m", m.load5),
-		row("15m", m.load15),
-	}, "\n")
-
-	return lipgloss.NewStyle().
-		BorderStyle(lipgloss.RoundedBorder()).
-		BorderForeground(cGray700).
-		Padding(0, 2).
-		Width(w).
-		Render(body)
-}
-
-func (m model) renderFooter(iw int) string {
-	quit := accentSt.Copy().Bold(true).Render("q") + dimSt.Render(" · ") +
-		accentSt.Copy().Bold(true).Render("ctrl+c") + dimSt.Render("  quit")
-	badge := dimSt.Render("↺ 500ms")
-
-	// Show a recording indicator when the activity log is active.
-	if m.logPath != "" {
-		recDot := lipgloss.NewStyle().Foreground(cRed).Bold(true).Render("●")
-		recLabel := dimSt.Render(" REC  " + m.logPath)
-		badge = recDot + recLabel + "  " + badge
+	if jobControl {
+		opts = append(opts, ui.WithJobControl(jobStop{}))
 	}
 
-	totalW := iw + 4
-	gap := totalW - lipgloss.Width(quit) - lipgloss.Width(badge) - 4
-	if gap < 1 {
-		gap = 1
+	var feed ui.Feed
+	if *connect != "" {
+		src, err := newRemoteSource(*connect)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "infgo: %v\n", err)
+			os.Exit(2)
+		}
+		feed = src
 	}
-
-	return lipgloss.NewStyle().
-		BorderStyle(lipgloss.NormalBorder()).
-		BorderTop(true).
-		BorderForeground(cGray700).
-		Padding(0, 1).
-		Width(totalW).
-		Render(quit + strings.Repeat(" ", gap) + badge)
-}
-
-// ── View ──────────────────────────────────────────────────────────────────────
-
-func (m model) View() string {
-	if !m.ready {
-		sp := lipgloss.NewStyle().Foreground(cViolet).Render(spinnerFrames[m.spinFrame])
-		return "\n  " + sp + dimSt.Render("  Initialising…") + "\n"
+	if *sshTarget != "" {
+		src, err := newSSHSource(*sshTarget, sshOptions{identity: *sshKey})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "infgo: %v\n", err)
+			os.Exit(2)
+		}
+		feed = src
 	}
-
-	iw := innerWidth(m.width)
-
-	// Bottom row: system info (wider) and load averages (narrower) side-by-side.
-	sysW := (iw+4)*56/100 - 2
-	loadW := iw + 4 - sysW - 3
-	bottom := lipgloss.JoinHorizontal(lipgloss.Top,
-		m.renderSystem(sysW),
-		"  ",
-		m.renderLoad(loadW),
-	)
-
-	out := strings.Join([]string{
-		m.renderHeader(iw),
-		"",
-		m.renderCPU(iw),
-		"",
-		m.renderMemory(iw),
-		"",
-		bottom,
-		m.renderFooter(iw),
-	}, "\n")
-
-	return lipgloss.NewStyle().Padding(0, 1).Render(out)
-}
-
-// ── Entry ─────────────────────────────────────────────────────────────────────
-
-func main() {
-	logPath := flag.String("log", "", "write activity log to `file.infgo` (binary protobuf)")
-	flag.Usage = func() {
-		fmt.Fprintf(os.Stderr, "Usage: infgo [-log <file.infgo>]\n\nFlags:\n")
-		flag.PrintDefaults()
+	if *replayPath != "" {
+		src, err := newReplaySource(*replayPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "infgo: %v\n", err)
+			os.Exit(1)
+		}
+		feed = src
+		if *baselinePath != "" {
+			base, err := loadCapture(*baselinePath)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "infgo: -baseline: %v\n", err)
+				os.Exit(1)
+			}
+			track, err := ui.NewBaseline(*baselinePath, src.c, base)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "infgo: %v\n", err)
+				os.Exit(1)
+			}
+			opts = append(opts, ui.WithBaseline(track))
+		}
+	}
+	if feed != nil {
+		opts = append(opts, ui.WithFeed(feed))
+	} else {
+		// A gap of a few readings at a high rate is a slow one, not a suspend.
+		col.power = startPowerWatch(max(*interval, ui.DefaultInterval))
+		opts = append(opts, ui.WithCollector(col))
 	}
-	flag.Parse()
-
-	m := initialModel()
 
 	// Activate logging if -log was provided.
 	if *logPath != "" {
-		lgr, err := syslogger.New(*logPath)
+		lgr, err := openLog(*logPath, autoNamed, *logAppend)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "infgo: open log: %v\n", err)
 			os.Exit(1)
 		}
-		m.logger = lgr
-		m.logPath = *logPath
+		if *logSchema {
+			lgr.EmbedSchema()
+		}
+		rec.logger, rec.logPath, rec.disk = lgr, *logPath, disk
 	}
 
-	prog := tea.NewProgram(m, tea.WithAltScreen())
+	// Bind before the TUI starts so a port clash is reported on a sane terminal.
+	if serve.enabled() {
+		rec.live = newLiveState()
+		rec.live.interval = *interval
+	}
+	if serve.addr != "" {
+		stopServer, err := startServer(serve, rec.live)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "infgo: %v\n", err)
+			os.Exit(1)
+		}
+		defer stopServer()
+	}
+	if serve.grpcAddr != "" {
+		stopGRPC, err := startGRPC(serve, rec.live, *logPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "infgo: %v\n", err)
+			os.Exit(1)
+		}
+		defer stopGRPC()
+	}
+
+	prog := tea.NewProgram(ui.New(opts...), tea.WithAltScreen())
 	finalModel, err := prog.Run()
+	if feed != nil {
+		feed.Close() // stops an -ssh collector on the remote
+	}
+	col.power.close()
+	closePushers(pushers)
+	if err := notifier.close(); err != nil {
+		fmt.Fprintf(os.Stderr, "infgo: %v\n", err)
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "infgo: %v\n", err)
 		os.Exit(1)
 	}
 
+	if fm, ok := finalModel.(ui.Model); ok && *saveLayout && layout != "" {
+		if err := writeLayout(layout, fm.Collapsed()); err != nil {
+			fmt.Fprintf(os.Stderr, "infgo: save layout: %v\n", err)
+		}
+	}
+
 	// Close the logger after the TUI exits so the final buffer is flushed.
-	if fm, ok := finalModel.(model); ok && fm.logger != nil {
-		if err := fm.logger.Close(); err != nil {
+	if rec.logger != nil {
+		if err := rec.logger.Close(); err != nil {
 			fmt.Fprintf(os.Stderr, "infgo: close log: %v\n", err)
 			os.Exit(1)
 		}
-		fmt.Printf("infgo: activity log written to %s\n", fm.logPath)
-		fmt.Printf("        run `analyze %s` to generate a report\n", fm.logPath)
+		fmt.Printf("infgo: activity log written to %s\n", rec.logPath)
+		fmt.Printf("        run `infgo analyze %s` to generate a report\n", rec.logPath)
 	}
 }
@@ -0,0 +1,23 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/mem"
+)
+
+func TestReadMemBreakdown(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	r := fakeReader(&fakeSources{}, &now)
+	r.src.mem = func(context.Context) (*mem.VirtualMemoryStat, error) {
+		return &mem.VirtualMemoryStat{UsedPercent: 50, Used: 4 << 30, Total: 8 << 30, Available: 3 << 30, Cached: 2 << 30, Buffers: 1 << 29}, nil
+	}
+	if msg := r.read(context.Background()); msg.MemAvailGB != 3 || msg.MemCachedGB != 2 || msg.MemBuffersGB != 0.5 {
+		t.Errorf("got avail %v, cache %v, buf %v", msg.MemAvailGB, msg.MemCachedGB, msg.MemBuffersGB)
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	syslogger "github.com/ALH477/infgo/logger"
+)
+
+// ── merge ─────────────────────────────────────────────────────────────────────
+
+// runMerge implements `infgo merge <a.infgo> <b.infgo>… -o <out.infgo>`.
+func runMerge(args []string) error {
+	fs := newFlagSet("merge", "<capture.infgo>... -o <out.infgo> [-relabel host=NAME] [-force]")
+	out := fs.String("o", "", "write the merged capture to `file`")
+	force := fs.Bool("force", false, "overwrite the output file if it exists")
+	var opts syslogger.MergeOptions
+	fs.Func("relabel", "override a header field in every source (`host=NAME`)", func(v string) error {
+		key, val, ok := strings.Cut(v, "=")
+		if !ok || val == "" {
+			return fmt.Errorf("want key=value, got %q", v)
+		}
+		switch key {
+		case "host", "hostname":
+			opts.Hostname = val
+		default:
+			return fmt.Errorf("unknown field %q (supported: host)", key)
+		}
+		return nil
+	})
+
+	pos, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) < 2 {
+		return usageErrorf(fs, "need at least two input captures")
+	}
+	if *out == "" {
+		return usageErrorf(fs, "-o is required")
+	}
+	for _, p := range pos {
+		if samePath(p, *out) {
+			return fmt.Errorf("output %q is also an input", *out)
+		}
+	}
+	if _, err := os.Stat(*out); err == nil && !*force {
+		return fmt.Errorf("%q already exists (use -force to overwrite)", *out)
+	}
+
+	readers := make([]*syslogger.Reader, 0, len(pos))
+	defer func() {
+		for _, rd := range readers {
+			_ = rd.Close()
+		}
+	}()
+	stdin := 0
+	for _, p := range pos {
+		if p == stdinPath {
+			if stdin++; stdin > 1 {
+				return fmt.Errorf("stdin (-) can only be given once")
+			}
+		}
+		rd, err := openCapture(p)
+		if err != nil {
+			return err
+		}
+		readers = append(readers, rd)
+	}
+
+	lgr, err := syslogger.New(*out)
+	if err != nil {
+		return err
+	}
+	stats, err := syslogger.Merge(lgr, readers, opts)
+	if cerr := lgr.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(*out)
+		return err
+	}
+
+	printMergeReport(os.Stdout, pos, stats, *out)
+	return nil
+}
+
+// printMergeReport writes per-source counts, the merged span, and any
+// overlapping source pairs.
+func printMergeReport(w io.Writer, paths []string, stats []syslogger.SourceStats, out string) {
+	var (
+		total       int
+		first, last int64
+	)
+	for i, st := range stats {
+		fmt.Fprintf(w, "  %-32s %6d samples  %d header(s)", paths[i], st.Samples, st.Headers)
+		if st.Events > 0 {
+			fmt.Fprintf(w, "  %d event(s)", st.Events)
+		}
+		if st.Processes > 0 {
+			fmt.Fprintf(w, "  %d process record(s)", st.Processes)
+		}
+		if st.Skipped > 0 {
+			fmt.Fprintf(w, "  %d skipped", st.Skipped)
+		}
+		fmt.Fprintln(w)
+		if st.Samples == 0 {
+			continue
+		}
+		if total == 0 || st.FirstUnixMs < first {
+			first = st.FirstUnixMs
+		}
+		if total == 0 || st.LastUnixMs > last {
+			last = st.LastUnixMs
+		}
+		total += st.Samples
+	}
+
+	fmt.Fprintf(w, "\n  merged   %d samples → %s\n", total, out)
+	if total > 0 {
+		a, b := time.UnixMilli(first), time.UnixMilli(last)
+		fmt.Fprintf(w, "  span     %s – %s (%s)\n", fmtStamp(a), fmtStamp(b), b.Sub(a).Round(time.Second))
+	}
+
+	for i := range stats {
+		for j := i + 1; j < len(stats); j++ {
+			if stats[i].Overlaps(stats[j]) {
+				lo := max(stats[i].FirstUnixMs, stats[j].FirstUnixMs)
+				hi := min(stats[i].LastUnixMs, stats[j].LastUnixMs)
+				fmt.Fprintf(w, "  overlap  %s and %s for %s\n",
+					paths[i], paths[j], time.Duration(hi-lo)*time.Millisecond)
+			}
+		}
+	}
+}
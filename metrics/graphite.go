@@ -0,0 +1,68 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ── Graphite plaintext protocol ───────────────────────────────────────────────
+
+// AppendGraphite appends s to b in Carbon's plaintext protocol, one
+// "path value timestamp" line per metric with the timestamp in seconds:
+//
+//	infgo.web01.cpu.total 42.5 1704067200
+//	infgo.web01.cpu.core.0 40 1704067200
+//	infgo.web01.mem.percent 50 1704067200
+//	infgo.web01.load.1m 1.25 1704067200
+//
+// prefix, usually "infgo", may itself contain dots; host is sanitised with
+// GraphiteNode so a dotted hostname stays a single path component.
+func AppendGraphite(b []byte, prefix, host string, s *Sample) []byte {
+	base := prefix + "." + GraphiteNode(host) + "."
+	ts := " " + strconv.FormatInt(s.TimestampUnixMs/1000, 10) + "\n"
+	line := func(path string, v float64) {
+		b = append(b, base...)
+		b = append(b, path...)
+		b = append(b, ' ')
+		b = strconv.AppendFloat(b, v, 'f', -1, 64)
+		b = append(b, ts...)
+	}
+
+	// A missing group has no lines rather than zeros.
+	if !s.Missing.Has(MissingCPU) {
+		line("cpu.total", s.CpuTotal)
+		for i, c := range s.CpuCores {
+			line("cpu.core."+strconv.Itoa(i), c)
+		}
+	}
+	if !s.Missing.Has(MissingMem) {
+		line("mem.percent", s.MemPercent)
+		line("mem.used_bytes", s.MemUsedGB*bytesPerGB)
+		line("mem.total_bytes", s.MemTotalGB*bytesPerGB)
+	}
+	if !s.Missing.Has(MissingLoad) {
+		line("load.1m", s.Load1)
+		line("load.5m", s.Load5)
+		line("load.15m", s.Load15)
+	}
+	return b
+}
+
+// GraphiteNode makes name safe as one Graphite path component: dots would
+// split it, and whitespace would end the path, so both (and anything else
+// outside [A-Za-z0-9_-]) become underscores.
+func GraphiteNode(name string) string {
+	if name == "" {
+		return "unknown"
+	}
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		}
+		return '_'
+	}, name)
+}
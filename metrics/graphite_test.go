@@ -0,0 +1,57 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAppendGraphite(t *testing.T) {
+	s := &Sample{
+		TimestampUnixMs: 1704067200500,
+		CpuTotal:        42.5,
+		CpuCores:        []float64{40, 45},
+		MemPercent:      50,
+		MemUsedGB:       2,
+		MemTotalGB:      4,
+		Load1:           1.25,
+		Load5:           1,
+		Load15:          0.5,
+	}
+	want := `servers.infgo.web01_example_com.cpu.total 42.5 1704067200
+servers.infgo.web01_example_com.cpu.core.0 40 1704067200
+servers.infgo.web01_example_com.cpu.core.1 45 1704067200
+servers.infgo.web01_example_com.mem.percent 50 1704067200
+servers.infgo.web01_example_com.mem.used_bytes 2147483648 1704067200
+servers.infgo.web01_example_com.mem.total_bytes 4294967296 1704067200
+servers.infgo.web01_example_com.load.1m 1.25 1704067200
+servers.infgo.web01_example_com.load.5m 1 1704067200
+servers.infgo.web01_example_com.load.15m 0.5 1704067200
+`
+	if got := string(AppendGraphite(nil, "servers.infgo", "web01.example.com", s)); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	// A group that could not be read has no lines.
+	s.Missing = MissingMem
+	if got := string(AppendGraphite(nil, "infgo", "h", s)); strings.Contains(got, ".mem.") || !strings.Contains(got, "infgo.h.load.1m 1.25") {
+		t.Errorf("mem missing: got:\n%s", got)
+	}
+}
+
+func TestGraphiteNode(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"web01", "web01"},
+		{"web01.example.com", "web01_example_com"},
+		{"my host/ä", "my_host__"},
+		{"db-2_a", "db-2_a"},
+		{"", "unknown"},
+	}
+	for _, tt := range tests {
+		if got := GraphiteNode(tt.in); got != tt.want {
+			t.Errorf("GraphiteNode(%q): got %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
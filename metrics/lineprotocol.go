@@ -0,0 +1,68 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ── InfluxDB line protocol ────────────────────────────────────────────────────
+
+// AppendLineProtocol appends s to b as InfluxDB line protocol, one point
+// per line with millisecond timestamps (precision=ms):
+//
+//	infgo_cpu,host=web total=42.5 1704067200500
+//	infgo_cpu_core,core=0,host=web usage=40 1704067200500
+//	infgo_mem,host=web percent=50,used_bytes=2147483648,total_bytes=4294967296 1704067200500
+//	infgo_load,host=web load1=1.25,load5=1,load15=0.5 1704067200500
+//
+// Tags are in key order, as InfluxDB recommends for write performance.
+func AppendLineProtocol(b []byte, host string, s *Sample) []byte {
+	hostTag := "host=" + tagEscaper.Replace(host)
+	ts := strconv.FormatInt(s.TimestampUnixMs, 10)
+	type field struct {
+		key string
+		val float64
+	}
+	point := func(measurement, tags string, fields ...field) {
+		b = append(b, measurement...)
+		b = append(b, ',')
+		b = append(b, tags...)
+		for i, f := range fields {
+			if i == 0 {
+				b = append(b, ' ')
+			} else {
+				b = append(b, ',')
+			}
+			b = append(b, f.key...)
+			b = append(b, '=')
+			b = strconv.AppendFloat(b, f.val, 'f', -1, 64)
+		}
+		b = append(b, ' ')
+		b = append(b, ts...)
+		b = append(b, '\n')
+	}
+
+	// A missing group has no point rather than zeros.
+	if !s.Missing.Has(MissingCPU) {
+		point("infgo_cpu", hostTag, field{"total", s.CpuTotal})
+		for i, c := range s.CpuCores {
+			point("infgo_cpu_core", "core="+strconv.Itoa(i)+","+hostTag, field{"usage", c})
+		}
+	}
+	if !s.Missing.Has(MissingMem) {
+		point("infgo_mem", hostTag,
+			field{"percent", s.MemPercent},
+			field{"used_bytes", s.MemUsedGB * bytesPerGB},
+			field{"total_bytes", s.MemTotalGB * bytesPerGB})
+	}
+	if !s.Missing.Has(MissingLoad) {
+		point("infgo_load", hostTag, field{"load1", s.Load1}, field{"load5", s.Load5}, field{"load15", s.Load15})
+	}
+	return b
+}
+
+// tagEscaper applies line protocol's tag-value escaping.
+var tagEscaper = strings.NewReplacer(`,`, `\,`, `=`, `\=`, ` `, `\ `, "\n", `\n`)
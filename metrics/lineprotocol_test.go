@@ -0,0 +1,41 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import "testing"
+
+func TestAppendLineProtocol(t *testing.T) {
+	s := &Sample{
+		TimestampUnixMs: 1704067200500,
+		CpuTotal:        42.5,
+		CpuCores:        []float64{40, 45},
+		MemPercent:      50,
+		MemUsedGB:       2,
+		MemTotalGB:      4,
+		Load1:           1.25,
+		Load5:           1,
+		Load15:          0.5,
+	}
+	want := `infgo_cpu,host=web\ 1\,a\=b total=42.5 1704067200500
+infgo_cpu_core,core=0,host=web\ 1\,a\=b usage=40 1704067200500
+infgo_cpu_core,core=1,host=web\ 1\,a\=b usage=45 1704067200500
+infgo_mem,host=web\ 1\,a\=b percent=50,used_bytes=2147483648,total_bytes=4294967296 1704067200500
+infgo_load,host=web\ 1\,a\=b load1=1.25,load5=1,load15=0.5 1704067200500
+`
+	if got := string(AppendLineProtocol(nil, "web 1,a=b", s)); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	// A group that could not be read has no point.
+	s.Missing = MissingCPU | MissingLoad
+	want = "infgo_mem,host=h percent=50,used_bytes=2147483648,total_bytes=4294967296 1704067200500\n"
+	if got := string(AppendLineProtocol(nil, "h", s)); got != want {
+		t.Errorf("cpu and load missing: got:\n%s\nwant:\n%s", got, want)
+	}
+
+	// Appending keeps what is already in the buffer.
+	if got := string(AppendLineProtocol([]byte("x\n"), "h", &Sample{})); got[:2] != "x\n" {
+		t.Errorf("prefix lost: %q", got)
+	}
+}
@@ -6,15 +6,22 @@
 // use the official google.golang.org/protobuf/encoding/protowire package.
 //
 // The output of Marshal is byte-for-byte compatible with what protoc-gen-go
-// would produce for the same .proto schema, so the log files can be consumed
-// by any protobuf tooling.  The Makefile `proto` target shows how to
-// regenerate code from the schema if you prefer that workflow instead.
+// would produce for the same .proto schema, so every payload can be decoded by
+// any protobuf tooling.  The .infgo framing around the payloads is not
+// standard; see WriteDelimited for a stream stock tools can read.  The
+// Makefile `proto` target shows how to regenerate code from the schema if you
+// prefer that workflow instead.
+//
+// The struct tags give the JSON form used by the HTTP API, keyed by the
+// schema's field names.
 package metrics
 
 import (
 	"encoding/binary"
 	"fmt"
 	"math"
+	"slices"
+	"strings"
 	"time"
 
 	"google.golang.org/protobuf/encoding/protowire"
@@ -29,27 +36,97 @@ const (
 	hfPlatform      protowire.Number = 2
 	hfStartedUnixMs protowire.Number = 3
 	hfNumCores      protowire.Number = 4
+	hfIntervalMs    protowire.Number = 5
+	hfTimezone      protowire.Number = 6
+	hfUTCOffsetS    protowire.Number = 7
+	hfArgs          protowire.Number = 8 // repeated string
+	hfCollectors    protowire.Number = 9 // repeated string
+	hfConfigPath    protowire.Number = 10
 
 	// Sample fields
-	sfTimestampUnixMs protowire.Number = 1
-	sfCpuTotal        protowire.Number = 2
-	sfCpuCores        protowire.Number = 3 // packed repeated double
-	sfMemPercent      protowire.Number = 4
-	sfMemUsedGB       protowire.Number = 5
-	sfMemTotalGB      protowire.Number = 6
-	sfLoad1           protowire.Number = 7
-	sfLoad5           protowire.Number = 8
-	sfLoad15          protowire.Number = 9
+	sfTimestampUnixMs   protowire.Number = 1
+	sfCpuTotal          protowire.Number = 2
+	sfCpuCores          protowire.Number = 3 // packed repeated double
+	sfMemPercent        protowire.Number = 4
+	sfMemUsedGB         protowire.Number = 5
+	sfMemTotalGB        protowire.Number = 6
+	sfLoad1             protowire.Number = 7
+	sfLoad5             protowire.Number = 8
+	sfLoad15            protowire.Number = 9
+	sfPowerWatts        protowire.Number = 10
+	sfCollectMs         protowire.Number = 11
+	sfDisks             protowire.Number = 12 // repeated DiskUsage
+	sfSwapUsedGB        protowire.Number = 13
+	sfSwapTotalGB       protowire.Number = 14
+	sfSwapPercent       protowire.Number = 15
+	sfGpus              protowire.Number = 16 // repeated GpuUsage
+	sfCpuUserPercent    protowire.Number = 17
+	sfCpuSystemPercent  protowire.Number = 18
+	sfCpuIowaitPercent  protowire.Number = 19
+	sfCpuStealPercent   protowire.Number = 20
+	sfMemAvailableGB    protowire.Number = 21
+	sfMemCachedGB       protowire.Number = 22
+	sfMemBuffersGB      protowire.Number = 23
+	sfPageFaultsPerSec  protowire.Number = 24
+	sfMajorFaultsPerSec protowire.Number = 25
+	sfCpuThrottled      protowire.Number = 26
+
+	// DiskUsage fields
+	dfMount             protowire.Number = 1
+	dfUsedPercent       protowire.Number = 2
+	dfUsedGB            protowire.Number = 3
+	dfTotalGB           protowire.Number = 4
+	dfInodesUsedPercent protowire.Number = 5
+
+	// GpuUsage fields
+	gfName        protowire.Number = 1
+	gfUtilPercent protowire.Number = 2
+	gfMemUsedGB   protowire.Number = 3
+	gfMemTotalGB  protowire.Number = 4
+	gfTempC       protowire.Number = 5
+
+	// Event fields
+	efTimestampUnixMs protowire.Number = 1
+	efKind            protowire.Number = 2
+	efMessage         protowire.Number = 3
+
+	// Process fields
+	pfTimestampUnixMs protowire.Number = 1
+	pfPid             protowire.Number = 2
+	pfStartedUnixMs   protowire.Number = 3
+	pfName            protowire.Number = 4
+	pfCpuPercent      protowire.Number = 5
+	pfRssBytes        protowire.Number = 6
+	pfThreads         protowire.Number = 7
+	pfFds             protowire.Number = 8
+	pfExited          protowire.Number = 9
 )
 
 // ── Header ────────────────────────────────────────────────────────────────────
 
 // Header is written once as the first record of every .infgo log file.
 type Header struct {
-	Hostname      string
-	Platform      string
-	StartedUnixMs int64
-	NumCores      int32
+	Hostname      string `json:"hostname"`
+	Platform      string `json:"platform"`
+	StartedUnixMs int64  `json:"started_unix_ms"`
+	NumCores      int32  `json:"num_cores"`
+	IntervalMs    int64  `json:"interval_ms"` // nominal spacing between samples; 0 if unknown
+
+	// Timezone is the IANA name of the recording host's zone, and
+	// UTCOffsetS its offset east of UTC at the start, for readers that
+	// cannot look the name up.  Both are unset where the host's zone is
+	// unknown, and in captures from before they were recorded.
+	Timezone   string `json:"timezone,omitempty"`
+	UTCOffsetS int32  `json:"utc_offset_s,omitempty"`
+
+	// Args are the recorder's command-line arguments, with the values of
+	// secrets such as -smtp-pass replaced by "<redacted>".  Collectors name
+	// the readings it took beyond none, e.g. cpu, memory, load, users, and
+	// ConfigPath is the configuration file it read, or "none".  All three
+	// are unset in captures from before they were recorded.
+	Args       []string `json:"args,omitempty"`
+	Collectors []string `json:"collectors,omitempty"`
+	ConfigPath string   `json:"config_path,omitempty"`
 }
 
 // StartedTime converts StartedUnixMs to a time.Time in UTC.
@@ -57,6 +134,11 @@ func (h *Header) StartedTime() time.Time {
 	return time.UnixMilli(h.StartedUnixMs).UTC()
 }
 
+// Interval returns IntervalMs as a time.Duration (0 when unrecorded).
+func (h *Header) Interval() time.Duration {
+	return time.Duration(h.IntervalMs) * time.Millisecond
+}
+
 // Marshal serialises h to protobuf binary.  Fields that hold zero/empty values
 // are omitted to match the proto3 default-omit behaviour.
 func (h *Header) Marshal() []byte {
@@ -77,6 +159,30 @@ func (h *Header) Marshal() []byte {
 		b = protowire.AppendTag(b, hfNumCores, protowire.VarintType)
 		b = protowire.AppendVarint(b, uint64(h.NumCores))
 	}
+	if h.IntervalMs != 0 {
+		b = protowire.AppendTag(b, hfIntervalMs, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(h.IntervalMs))
+	}
+	if h.Timezone != "" {
+		b = protowire.AppendTag(b, hfTimezone, protowire.BytesType)
+		b = protowire.AppendString(b, h.Timezone)
+	}
+	if h.UTCOffsetS != 0 {
+		b = protowire.AppendTag(b, hfUTCOffsetS, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(int64(h.UTCOffsetS)))
+	}
+	for _, a := range h.Args {
+		b = protowire.AppendTag(b, hfArgs, protowire.BytesType)
+		b = protowire.AppendString(b, a)
+	}
+	for _, c := range h.Collectors {
+		b = protowire.AppendTag(b, hfCollectors, protowire.BytesType)
+		b = protowire.AppendString(b, c)
+	}
+	if h.ConfigPath != "" {
+		b = protowire.AppendTag(b, hfConfigPath, protowire.BytesType)
+		b = protowire.AppendString(b, h.ConfigPath)
+	}
 	return b
 }
 
@@ -123,6 +229,54 @@ func UnmarshalHeader(b []byte) (Header, error) {
 			h.NumCores = int32(v)
 			b = b[n:]
 
+		case num == hfIntervalMs && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			if n < 0 {
+				return h, fmt.Errorf("header: interval_ms: %w", protowire.ParseError(n))
+			}
+			h.IntervalMs = int64(v)
+			b = b[n:]
+
+		case num == hfTimezone && typ == protowire.BytesType:
+			v, n := protowire.ConsumeString(b)
+			if n < 0 {
+				return h, fmt.Errorf("header: timezone: %w", protowire.ParseError(n))
+			}
+			h.Timezone = v
+			b = b[n:]
+
+		case num == hfUTCOffsetS && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			if n < 0 {
+				return h, fmt.Errorf("header: utc_offset_s: %w", protowire.ParseError(n))
+			}
+			h.UTCOffsetS = int32(v)
+			b = b[n:]
+
+		case num == hfArgs && typ == protowire.BytesType:
+			v, n := protowire.ConsumeString(b)
+			if n < 0 {
+				return h, fmt.Errorf("header: args: %w", protowire.ParseError(n))
+			}
+			h.Args = append(h.Args, v)
+			b = b[n:]
+
+		case num == hfCollectors && typ == protowire.BytesType:
+			v, n := protowire.ConsumeString(b)
+			if n < 0 {
+				return h, fmt.Errorf("header: collectors: %w", protowire.ParseError(n))
+			}
+			h.Collectors = append(h.Collectors, v)
+			b = b[n:]
+
+		case num == hfConfigPath && typ == protowire.BytesType:
+			v, n := protowire.ConsumeString(b)
+			if n < 0 {
+				return h, fmt.Errorf("header: config_path: %w", protowire.ParseError(n))
+			}
+			h.ConfigPath = v
+			b = b[n:]
+
 		default:
 			// Skip unknown fields for forward-compatibility.
 			n := protowire.ConsumeFieldValue(num, typ, b)
@@ -139,15 +293,171 @@ func UnmarshalHeader(b []byte) (Header, error) {
 
 // Sample is one snapshot of system metrics written every ~500 ms.
 type Sample struct {
-	TimestampUnixMs int64
-	CpuTotal        float64   // aggregate 0-100 %
-	CpuCores        []float64 // per-logical-core 0-100 %
-	MemPercent      float64
-	MemUsedGB       float64
-	MemTotalGB      float64
-	Load1           float64
-	Load5           float64
-	Load15          float64
+	TimestampUnixMs int64     `json:"timestamp_unix_ms"`
+	CpuTotal        float64   `json:"cpu_total"` // aggregate 0-100 %
+	CpuCores        []float64 `json:"cpu_cores"` // per-logical-core 0-100 %
+	MemPercent      float64   `json:"mem_percent"`
+	MemUsedGB       float64   `json:"mem_used_gb"`
+	MemTotalGB      float64   `json:"mem_total_gb"`
+	Load1           float64   `json:"load_1"`
+	Load5           float64   `json:"load_5"`
+	Load15          float64   `json:"load_15"`
+
+	// Missing names the groups of fields the collector could not read.
+	// They are zero here and left out of the encoding.
+	Missing Missing `json:"missing,omitempty"`
+
+	// PowerWatts is the CPU package power measured since the previous
+	// sample; nil where the machine has no energy counters to read.
+	PowerWatts *float64 `json:"power_watts,omitempty"`
+
+	// CollectMs is how long the collector took to read the sample, in
+	// milliseconds; nil where it was not measured.  Readings that take a
+	// large part of the interval mean the monitor itself was starved.
+	CollectMs *float64 `json:"collect_ms,omitempty"`
+
+	// Disks is the usage of the filesystems the collector was asked to
+	// watch, one per mount that could be read.
+	Disks []DiskUsage `json:"disks,omitempty"`
+
+	// SwapUsedGB, SwapTotalGB and SwapPercent are the use of swap space,
+	// read with the memory.  Each is left out of the encoding when zero,
+	// as proto3 leaves out a scalar, so a machine without swap, a failed
+	// memory reading and a capture from before they were recorded all
+	// read back as zeros.
+	SwapUsedGB  float64 `json:"swap_used_gb,omitempty"`
+	SwapTotalGB float64 `json:"swap_total_gb,omitempty"`
+	SwapPercent float64 `json:"swap_percent,omitempty"`
+
+	// Gpus is the use of each GPU the collector could read, in the order
+	// the driver numbers them.
+	Gpus []GpuUsage `json:"gpus,omitempty"`
+
+	// CpuUserPercent, CpuSystemPercent, CpuIowaitPercent and
+	// CpuStealPercent split the CPU time since the previous sample by
+	// mode, as a percentage of all of it.  Like swap, each is left out of
+	// the encoding when zero, which is also how a sample reads where they
+	// were not measured.
+	CpuUserPercent   float64 `json:"cpu_user_percent,omitempty"`
+	CpuSystemPercent float64 `json:"cpu_system_percent,omitempty"`
+	CpuIowaitPercent float64 `json:"cpu_iowait_percent,omitempty"`
+	CpuStealPercent  float64 `json:"cpu_steal_percent,omitempty"`
+
+	// MemAvailableGB, MemCachedGB and MemBuffersGB are the memory the
+	// kernel reckons could be handed out without swapping, and the page
+	// cache and buffers counted in MemUsedGB's complement.  Like swap,
+	// each is left out of the encoding when zero, as they are where the
+	// platform does not report them.
+	MemAvailableGB float64 `json:"mem_available_gb,omitempty"`
+	MemCachedGB    float64 `json:"mem_cached_gb,omitempty"`
+	MemBuffersGB   float64 `json:"mem_buffers_gb,omitempty"`
+
+	// PageFaultsPerSec and MajorFaultsPerSec are the page faults a second
+	// since the previous reading, and the major ones among them that
+	// waited on the disk: a machine thrashing has many.  Left out of the
+	// encoding when zero, as they are where the platform has no counters.
+	PageFaultsPerSec  float64 `json:"page_faults_per_sec,omitempty"`
+	MajorFaultsPerSec float64 `json:"major_faults_per_sec,omitempty"`
+
+	// CpuThrottled is set while the CPU is thermally throttled, when its
+	// percentages understate how hard it is working.  Left out of the
+	// encoding when false, as it is where throttling cannot be detected.
+	CpuThrottled bool `json:"cpu_throttled,omitempty"`
+}
+
+// DiskUsage is the usage of the filesystem mounted at Mount.
+type DiskUsage struct {
+	Mount       string  `json:"mount"`
+	UsedPercent float64 `json:"used_percent"`
+	UsedGB      float64 `json:"used_gb"`
+	TotalGB     float64 `json:"total_gb"`
+
+	// InodesUsedPercent is the share of the filesystem's inodes in use,
+	// zero where it has no fixed number of them, as btrfs.
+	InodesUsedPercent float64 `json:"inodes_used_percent,omitempty"`
+}
+
+// Disk returns the usage of the filesystem mounted at mount, or false
+// where s has none.
+func (s *Sample) Disk(mount string) (DiskUsage, bool) {
+	for _, d := range s.Disks {
+		if d.Mount == mount {
+			return d, true
+		}
+	}
+	return DiskUsage{}, false
+}
+
+// GpuUsage is the load, memory and temperature of one GPU.
+type GpuUsage struct {
+	Name        string  `json:"name"`
+	UtilPercent float64 `json:"util_percent"`
+	MemUsedGB   float64 `json:"mem_used_gb"`
+	MemTotalGB  float64 `json:"mem_total_gb"`
+	TempC       float64 `json:"temp_c"`
+}
+
+// MemPercent is the share of g's memory in use; 0 where its total is
+// unknown.
+func (g GpuUsage) MemPercent() float64 {
+	if g.MemTotalGB <= 0 {
+		return 0
+	}
+	return 100 * g.MemUsedGB / g.MemTotalGB
+}
+
+// MaxGpus bounds the GpuUsage entries UnmarshalSample accepts in one
+// sample, as MaxDisks does the disks.
+const MaxGpus = 64
+
+// MaxDisks bounds the DiskUsage entries UnmarshalSample accepts in one
+// sample, so that a corrupt record cannot make it allocate without limit.
+const MaxDisks = 64
+
+// Missing is a set of Sample field groups that failed to read.
+type Missing uint8
+
+const (
+	MissingCPU  Missing = 1 << iota // CpuTotal and CpuCores
+	MissingMem                      // MemPercent, MemUsedGB and MemTotalGB
+	MissingLoad                     // Load1, Load5 and Load15
+
+	MissingAll = MissingCPU | MissingMem | MissingLoad
+)
+
+var missingNames = [...]string{"cpu", "mem", "load"}
+
+// Has reports whether every group in g is missing.
+func (m Missing) Has(g Missing) bool { return m&g == g }
+
+// String lists the missing groups, e.g. "mem,load".
+func (m Missing) String() string {
+	var names []string
+	for i, name := range missingNames {
+		if m.Has(1 << i) {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// MarshalText gives Missing its JSON form, the same as String.
+func (m Missing) MarshalText() ([]byte, error) { return []byte(m.String()), nil }
+
+// UnmarshalText parses the form String writes.
+func (m *Missing) UnmarshalText(text []byte) error {
+	*m = 0
+	if len(text) == 0 {
+		return nil
+	}
+	for _, name := range strings.Split(string(text), ",") {
+		i := slices.Index(missingNames[:], name)
+		if i < 0 {
+			return fmt.Errorf("sample: unknown missing group %q", name)
+		}
+		*m |= 1 << i
+	}
+	return nil
 }
 
 // Time converts TimestampUnixMs to a time.Time in UTC.
@@ -159,44 +469,316 @@ func (s *Sample) Time() time.Time {
 // CpuCores is encoded as a packed repeated double (field 3, wire type bytes),
 // matching the `repeated double cpu_cores = 3` proto3 packed default.
 func (s *Sample) Marshal() []byte {
-	var b []byte
+	return s.MarshalAppend(make([]byte, 0, s.Size()))
+}
+
+// Size is the length of s's encoding, so a caller can size the buffer it
+// passes to MarshalAppend.
+func (s *Sample) Size() int {
+	n := protowire.SizeTag(sfTimestampUnixMs) + protowire.SizeVarint(uint64(s.TimestampUnixMs))
+	const double = 1 + 8 // every double field has a one-byte tag
+	if !s.Missing.Has(MissingCPU) {
+		n += double
+		if len(s.CpuCores) > 0 {
+			n += protowire.SizeTag(sfCpuCores) + protowire.SizeBytes(len(s.CpuCores)*8)
+		}
+	}
+	if !s.Missing.Has(MissingMem) {
+		n += 3 * double
+	}
+	if !s.Missing.Has(MissingLoad) {
+		n += 3 * double
+	}
+	if s.PowerWatts != nil {
+		n += double
+	}
+	if s.CollectMs != nil {
+		n += double
+	}
+	for i := range s.Disks {
+		n += protowire.SizeTag(sfDisks) + protowire.SizeBytes(s.Disks[i].size())
+	}
+	for _, v := range [...]float64{s.SwapUsedGB, s.SwapTotalGB, s.SwapPercent} {
+		if v != 0 {
+			n += double
+		}
+	}
+	for i := range s.Gpus {
+		n += protowire.SizeTag(sfGpus) + protowire.SizeBytes(s.Gpus[i].size())
+	}
+	for _, v := range [...]float64{
+		s.CpuUserPercent, s.CpuSystemPercent, s.CpuIowaitPercent, s.CpuStealPercent,
+		s.MemAvailableGB, s.MemCachedGB, s.MemBuffersGB,
+		s.PageFaultsPerSec, s.MajorFaultsPerSec,
+	} {
+		if v != 0 {
+			n += protowire.SizeTag(sfCpuUserPercent) + 8 // two-byte tags from field 16
+		}
+	}
+	if s.CpuThrottled {
+		n += protowire.SizeTag(sfCpuThrottled) + 1
+	}
+	return n
+}
 
+// MarshalAppend appends the encoding of s to b and returns the extended
+// slice.  It allocates only if b lacks room for Size more bytes, so a
+// writer that passes the same buffer back each time encodes for free.
+func (s *Sample) MarshalAppend(b []byte) []byte {
 	// field 1: timestamp_unix_ms (int64 → varint)
 	b = protowire.AppendTag(b, sfTimestampUnixMs, protowire.VarintType)
 	b = protowire.AppendVarint(b, uint64(s.TimestampUnixMs))
 
-	// field 2: cpu_total (double → fixed64)
-	b = protowire.AppendTag(b, sfCpuTotal, protowire.Fixed64Type)
-	b = protowire.AppendFixed64(b, math.Float64bits(s.CpuTotal))
-
-	// field 3: cpu_cores (packed repeated double → bytes containing fixed64 values)
-	if len(s.CpuCores) > 0 {
-		packed := make([]byte, 0, len(s.CpuCores)*8)
-		for _, c := range s.CpuCores {
-			packed = binary.LittleEndian.AppendUint64(packed, math.Float64bits(c))
+	// fields 2-3: cpu_total (double → fixed64) and cpu_cores (packed
+	// repeated double → bytes containing fixed64 values, written in place
+	// after its length prefix)
+	if !s.Missing.Has(MissingCPU) {
+		b = appendDouble(b, sfCpuTotal, s.CpuTotal)
+		if len(s.CpuCores) > 0 {
+			b = protowire.AppendTag(b, sfCpuCores, protowire.BytesType)
+			b = protowire.AppendVarint(b, uint64(len(s.CpuCores)*8))
+			for _, c := range s.CpuCores {
+				b = binary.LittleEndian.AppendUint64(b, math.Float64bits(c))
+			}
 		}
-		b = protowire.AppendTag(b, sfCpuCores, protowire.BytesType)
-		b = protowire.AppendBytes(b, packed)
 	}
 
-	// fields 4-9: scalar doubles
-	appendDouble := func(num protowire.Number, v float64) {
-		b = protowire.AppendTag(b, num, protowire.Fixed64Type)
-		b = protowire.AppendFixed64(b, math.Float64bits(v))
+	// fields 4-9: scalar doubles, left out a group at a time
+	if !s.Missing.Has(MissingMem) {
+		b = appendDouble(b, sfMemPercent, s.MemPercent)
+		b = appendDouble(b, sfMemUsedGB, s.MemUsedGB)
+		b = appendDouble(b, sfMemTotalGB, s.MemTotalGB)
+	}
+	if !s.Missing.Has(MissingLoad) {
+		b = appendDouble(b, sfLoad1, s.Load1)
+		b = appendDouble(b, sfLoad5, s.Load5)
+		b = appendDouble(b, sfLoad15, s.Load15)
+	}
+
+	// field 10: power_watts, only where it was measured
+	if s.PowerWatts != nil {
+		b = appendDouble(b, sfPowerWatts, *s.PowerWatts)
+	}
+
+	// field 11: collect_ms, only where it was measured
+	if s.CollectMs != nil {
+		b = appendDouble(b, sfCollectMs, *s.CollectMs)
+	}
+
+	// field 12: disks (repeated message → one length-delimited record each)
+	for i := range s.Disks {
+		d := &s.Disks[i]
+		b = protowire.AppendTag(b, sfDisks, protowire.BytesType)
+		b = protowire.AppendVarint(b, uint64(d.size()))
+		b = d.appendTo(b)
+	}
+
+	// fields 13-15: swap, each only where it is not zero
+	if s.SwapUsedGB != 0 {
+		b = appendDouble(b, sfSwapUsedGB, s.SwapUsedGB)
+	}
+	if s.SwapTotalGB != 0 {
+		b = appendDouble(b, sfSwapTotalGB, s.SwapTotalGB)
+	}
+	if s.SwapPercent != 0 {
+		b = appendDouble(b, sfSwapPercent, s.SwapPercent)
+	}
+
+	// field 16: gpus (repeated message, as disks)
+	for i := range s.Gpus {
+		g := &s.Gpus[i]
+		b = protowire.AppendTag(b, sfGpus, protowire.BytesType)
+		b = protowire.AppendVarint(b, uint64(g.size()))
+		b = g.appendTo(b)
+	}
+
+	// fields 17-20: the CPU time by mode, each only where it is not zero
+	if s.CpuUserPercent != 0 {
+		b = appendDouble(b, sfCpuUserPercent, s.CpuUserPercent)
+	}
+	if s.CpuSystemPercent != 0 {
+		b = appendDouble(b, sfCpuSystemPercent, s.CpuSystemPercent)
+	}
+	if s.CpuIowaitPercent != 0 {
+		b = appendDouble(b, sfCpuIowaitPercent, s.CpuIowaitPercent)
+	}
+	if s.CpuStealPercent != 0 {
+		b = appendDouble(b, sfCpuStealPercent, s.CpuStealPercent)
+	}
+	if s.MemAvailableGB != 0 {
+		b = appendDouble(b, sfMemAvailableGB, s.MemAvailableGB)
+	}
+	if s.MemCachedGB != 0 {
+		b = appendDouble(b, sfMemCachedGB, s.MemCachedGB)
+	}
+	if s.MemBuffersGB != 0 {
+		b = appendDouble(b, sfMemBuffersGB, s.MemBuffersGB)
+	}
+	if s.PageFaultsPerSec != 0 {
+		b = appendDouble(b, sfPageFaultsPerSec, s.PageFaultsPerSec)
+	}
+	if s.MajorFaultsPerSec != 0 {
+		b = appendDouble(b, sfMajorFaultsPerSec, s.MajorFaultsPerSec)
+	}
+	if s.CpuThrottled {
+		b = protowire.AppendTag(b, sfCpuThrottled, protowire.VarintType)
+		b = protowire.AppendVarint(b, 1)
 	}
-	appendDouble(sfMemPercent, s.MemPercent)
-	appendDouble(sfMemUsedGB, s.MemUsedGB)
-	appendDouble(sfMemTotalGB, s.MemTotalGB)
-	appendDouble(sfLoad1, s.Load1)
-	appendDouble(sfLoad5, s.Load5)
-	appendDouble(sfLoad15, s.Load15)
 
 	return b
 }
 
+// size is the length of d's encoding, without its tag and length prefix.
+func (d *DiskUsage) size() int {
+	n := 3 * (1 + 8)
+	if d.Mount != "" {
+		n += protowire.SizeTag(dfMount) + protowire.SizeBytes(len(d.Mount))
+	}
+	if d.InodesUsedPercent != 0 {
+		n += 1 + 8
+	}
+	return n
+}
+
+func (d *DiskUsage) appendTo(b []byte) []byte {
+	if d.Mount != "" {
+		b = protowire.AppendTag(b, dfMount, protowire.BytesType)
+		b = protowire.AppendString(b, d.Mount)
+	}
+	b = appendDouble(b, dfUsedPercent, d.UsedPercent)
+	b = appendDouble(b, dfUsedGB, d.UsedGB)
+	b = appendDouble(b, dfTotalGB, d.TotalGB)
+	if d.InodesUsedPercent != 0 {
+		b = appendDouble(b, dfInodesUsedPercent, d.InodesUsedPercent)
+	}
+	return b
+}
+
+// unmarshalDiskUsage decodes the payload of a disks field.
+func unmarshalDiskUsage(b []byte) (DiskUsage, error) {
+	var d DiskUsage
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return d, fmt.Errorf("disk: consume tag: %w", protowire.ParseError(n))
+		}
+		b = b[n:]
+
+		var dst *float64
+		switch {
+		case num == dfMount && typ == protowire.BytesType:
+			v, n := protowire.ConsumeString(b)
+			if n < 0 {
+				return d, fmt.Errorf("disk: mount: %w", protowire.ParseError(n))
+			}
+			d.Mount = v
+			b = b[n:]
+			continue
+		case num == dfUsedPercent && typ == protowire.Fixed64Type:
+			dst = &d.UsedPercent
+		case num == dfUsedGB && typ == protowire.Fixed64Type:
+			dst = &d.UsedGB
+		case num == dfTotalGB && typ == protowire.Fixed64Type:
+			dst = &d.TotalGB
+		case num == dfInodesUsedPercent && typ == protowire.Fixed64Type:
+			dst = &d.InodesUsedPercent
+		default:
+			n := protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				return d, fmt.Errorf("disk: skip unknown field %d: %w", num, protowire.ParseError(n))
+			}
+			b = b[n:]
+			continue
+		}
+		v, n := protowire.ConsumeFixed64(b)
+		if n < 0 {
+			return d, fmt.Errorf("disk: field %d: %w", num, protowire.ParseError(n))
+		}
+		*dst = math.Float64frombits(v)
+		b = b[n:]
+	}
+	return d, nil
+}
+
+// size is the length of g's encoding, without its tag and length prefix.
+func (g *GpuUsage) size() int {
+	n := 4 * (1 + 8)
+	if g.Name != "" {
+		n += protowire.SizeTag(gfName) + protowire.SizeBytes(len(g.Name))
+	}
+	return n
+}
+
+func (g *GpuUsage) appendTo(b []byte) []byte {
+	if g.Name != "" {
+		b = protowire.AppendTag(b, gfName, protowire.BytesType)
+		b = protowire.AppendString(b, g.Name)
+	}
+	b = appendDouble(b, gfUtilPercent, g.UtilPercent)
+	b = appendDouble(b, gfMemUsedGB, g.MemUsedGB)
+	b = appendDouble(b, gfMemTotalGB, g.MemTotalGB)
+	return appendDouble(b, gfTempC, g.TempC)
+}
+
+// unmarshalGpuUsage decodes the payload of a gpus field.
+func unmarshalGpuUsage(b []byte) (GpuUsage, error) {
+	var g GpuUsage
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return g, fmt.Errorf("gpu: consume tag: %w", protowire.ParseError(n))
+		}
+		b = b[n:]
+
+		var dst *float64
+		switch {
+		case num == gfName && typ == protowire.BytesType:
+			v, n := protowire.ConsumeString(b)
+			if n < 0 {
+				return g, fmt.Errorf("gpu: name: %w", protowire.ParseError(n))
+			}
+			g.Name = v
+			b = b[n:]
+			continue
+		case num == gfUtilPercent && typ == protowire.Fixed64Type:
+			dst = &g.UtilPercent
+		case num == gfMemUsedGB && typ == protowire.Fixed64Type:
+			dst = &g.MemUsedGB
+		case num == gfMemTotalGB && typ == protowire.Fixed64Type:
+			dst = &g.MemTotalGB
+		case num == gfTempC && typ == protowire.Fixed64Type:
+			dst = &g.TempC
+		default:
+			n := protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				return g, fmt.Errorf("gpu: skip unknown field %d: %w", num, protowire.ParseError(n))
+			}
+			b = b[n:]
+			continue
+		}
+		v, n := protowire.ConsumeFixed64(b)
+		if n < 0 {
+			return g, fmt.Errorf("gpu: field %d: %w", num, protowire.ParseError(n))
+		}
+		*dst = math.Float64frombits(v)
+		b = b[n:]
+	}
+	return g, nil
+}
+
+// appendDouble appends a double field (wire type fixed64).
+func appendDouble(b []byte, num protowire.Number, v float64) []byte {
+	b = protowire.AppendTag(b, num, protowire.Fixed64Type)
+	return protowire.AppendFixed64(b, math.Float64bits(v))
+}
+
 // UnmarshalSample deserialises a Sample from protobuf binary.
+//
+// A group of fields with none present is reported in Missing: earlier
+// writers set every field, so only a failed reading leaves a group out.
 func UnmarshalSample(b []byte) (Sample, error) {
 	var s Sample
+	seen := Missing(0)
 	for len(b) > 0 {
 		num, typ, n := protowire.ConsumeTag(b)
 		if n < 0 {
@@ -219,6 +801,7 @@ func UnmarshalSample(b []byte) (Sample, error) {
 				return s, fmt.Errorf("sample: cpu_total: %w", protowire.ParseError(n))
 			}
 			s.CpuTotal = math.Float64frombits(v)
+			seen |= MissingCPU
 			b = b[n:]
 
 		case num == sfCpuCores && typ == protowire.BytesType:
@@ -231,6 +814,7 @@ func UnmarshalSample(b []byte) (Sample, error) {
 			if len(raw)%8 != 0 {
 				return s, fmt.Errorf("sample: cpu_cores packed length %d is not a multiple of 8", len(raw))
 			}
+			seen |= MissingCPU
 			s.CpuCores = make([]float64, 0, len(raw)/8)
 			for len(raw) >= 8 {
 				bits := binary.LittleEndian.Uint64(raw[:8])
@@ -245,6 +829,7 @@ func UnmarshalSample(b []byte) (Sample, error) {
 				return s, fmt.Errorf("sample: mem_percent: %w", protowire.ParseError(n))
 			}
 			s.MemPercent = math.Float64frombits(v)
+			seen |= MissingMem
 			b = b[n:]
 
 		case num == sfMemUsedGB && typ == protowire.Fixed64Type:
@@ -253,6 +838,7 @@ func UnmarshalSample(b []byte) (Sample, error) {
 				return s, fmt.Errorf("sample: mem_used_gb: %w", protowire.ParseError(n))
 			}
 			s.MemUsedGB = math.Float64frombits(v)
+			seen |= MissingMem
 			b = b[n:]
 
 		case num == sfMemTotalGB && typ == protowire.Fixed64Type:
@@ -261,6 +847,7 @@ func UnmarshalSample(b []byte) (Sample, error) {
 				return s, fmt.Errorf("sample: mem_total_gb: %w", protowire.ParseError(n))
 			}
 			s.MemTotalGB = math.Float64frombits(v)
+			seen |= MissingMem
 			b = b[n:]
 
 		case num == sfLoad1 && typ == protowire.Fixed64Type:
@@ -269,6 +856,7 @@ func UnmarshalSample(b []byte) (Sample, error) {
 				return s, fmt.Errorf("sample: load_1: %w", protowire.ParseError(n))
 			}
 			s.Load1 = math.Float64frombits(v)
+			seen |= MissingLoad
 			b = b[n:]
 
 		case num == sfLoad5 && typ == protowire.Fixed64Type:
@@ -277,6 +865,7 @@ func UnmarshalSample(b []byte) (Sample, error) {
 				return s, fmt.Errorf("sample: load_5: %w", protowire.ParseError(n))
 			}
 			s.Load5 = math.Float64frombits(v)
+			seen |= MissingLoad
 			b = b[n:]
 
 		case num == sfLoad15 && typ == protowire.Fixed64Type:
@@ -285,6 +874,159 @@ func UnmarshalSample(b []byte) (Sample, error) {
 				return s, fmt.Errorf("sample: load_15: %w", protowire.ParseError(n))
 			}
 			s.Load15 = math.Float64frombits(v)
+			seen |= MissingLoad
+			b = b[n:]
+
+		case num == sfPowerWatts && typ == protowire.Fixed64Type:
+			v, n := protowire.ConsumeFixed64(b)
+			if n < 0 {
+				return s, fmt.Errorf("sample: power_watts: %w", protowire.ParseError(n))
+			}
+			w := math.Float64frombits(v)
+			s.PowerWatts = &w
+			b = b[n:]
+
+		case num == sfCollectMs && typ == protowire.Fixed64Type:
+			v, n := protowire.ConsumeFixed64(b)
+			if n < 0 {
+				return s, fmt.Errorf("sample: collect_ms: %w", protowire.ParseError(n))
+			}
+			ms := math.Float64frombits(v)
+			s.CollectMs = &ms
+			b = b[n:]
+
+		case num == sfDisks && typ == protowire.BytesType:
+			raw, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return s, fmt.Errorf("sample: disks: %w", protowire.ParseError(n))
+			}
+			if len(s.Disks) == MaxDisks {
+				return s, fmt.Errorf("sample: more than %d disks", MaxDisks)
+			}
+			d, err := unmarshalDiskUsage(raw)
+			if err != nil {
+				return s, fmt.Errorf("sample: %w", err)
+			}
+			s.Disks = append(s.Disks, d)
+			b = b[n:]
+
+		case num == sfSwapUsedGB && typ == protowire.Fixed64Type:
+			v, n := protowire.ConsumeFixed64(b)
+			if n < 0 {
+				return s, fmt.Errorf("sample: swap_used_gb: %w", protowire.ParseError(n))
+			}
+			s.SwapUsedGB = math.Float64frombits(v)
+			b = b[n:]
+
+		case num == sfSwapTotalGB && typ == protowire.Fixed64Type:
+			v, n := protowire.ConsumeFixed64(b)
+			if n < 0 {
+				return s, fmt.Errorf("sample: swap_total_gb: %w", protowire.ParseError(n))
+			}
+			s.SwapTotalGB = math.Float64frombits(v)
+			b = b[n:]
+
+		case num == sfSwapPercent && typ == protowire.Fixed64Type:
+			v, n := protowire.ConsumeFixed64(b)
+			if n < 0 {
+				return s, fmt.Errorf("sample: swap_percent: %w", protowire.ParseError(n))
+			}
+			s.SwapPercent = math.Float64frombits(v)
+			b = b[n:]
+
+		case num == sfGpus && typ == protowire.BytesType:
+			raw, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return s, fmt.Errorf("sample: gpus: %w", protowire.ParseError(n))
+			}
+			if len(s.Gpus) == MaxGpus {
+				return s, fmt.Errorf("sample: more than %d GPUs", MaxGpus)
+			}
+			g, err := unmarshalGpuUsage(raw)
+			if err != nil {
+				return s, fmt.Errorf("sample: %w", err)
+			}
+			s.Gpus = append(s.Gpus, g)
+			b = b[n:]
+
+		case num == sfCpuUserPercent && typ == protowire.Fixed64Type:
+			v, n := protowire.ConsumeFixed64(b)
+			if n < 0 {
+				return s, fmt.Errorf("sample: cpu_user_percent: %w", protowire.ParseError(n))
+			}
+			s.CpuUserPercent = math.Float64frombits(v)
+			b = b[n:]
+
+		case num == sfCpuSystemPercent && typ == protowire.Fixed64Type:
+			v, n := protowire.ConsumeFixed64(b)
+			if n < 0 {
+				return s, fmt.Errorf("sample: cpu_system_percent: %w", protowire.ParseError(n))
+			}
+			s.CpuSystemPercent = math.Float64frombits(v)
+			b = b[n:]
+
+		case num == sfCpuIowaitPercent && typ == protowire.Fixed64Type:
+			v, n := protowire.ConsumeFixed64(b)
+			if n < 0 {
+				return s, fmt.Errorf("sample: cpu_iowait_percent: %w", protowire.ParseError(n))
+			}
+			s.CpuIowaitPercent = math.Float64frombits(v)
+			b = b[n:]
+
+		case num == sfCpuStealPercent && typ == protowire.Fixed64Type:
+			v, n := protowire.ConsumeFixed64(b)
+			if n < 0 {
+				return s, fmt.Errorf("sample: cpu_steal_percent: %w", protowire.ParseError(n))
+			}
+			s.CpuStealPercent = math.Float64frombits(v)
+			b = b[n:]
+
+		case num == sfMemAvailableGB && typ == protowire.Fixed64Type:
+			v, n := protowire.ConsumeFixed64(b)
+			if n < 0 {
+				return s, fmt.Errorf("sample: mem_available_gb: %w", protowire.ParseError(n))
+			}
+			s.MemAvailableGB = math.Float64frombits(v)
+			b = b[n:]
+
+		case num == sfMemCachedGB && typ == protowire.Fixed64Type:
+			v, n := protowire.ConsumeFixed64(b)
+			if n < 0 {
+				return s, fmt.Errorf("sample: mem_cached_gb: %w", protowire.ParseError(n))
+			}
+			s.MemCachedGB = math.Float64frombits(v)
+			b = b[n:]
+
+		case num == sfMemBuffersGB && typ == protowire.Fixed64Type:
+			v, n := protowire.ConsumeFixed64(b)
+			if n < 0 {
+				return s, fmt.Errorf("sample: mem_buffers_gb: %w", protowire.ParseError(n))
+			}
+			s.MemBuffersGB = math.Float64frombits(v)
+			b = b[n:]
+
+		case num == sfPageFaultsPerSec && typ == protowire.Fixed64Type:
+			v, n := protowire.ConsumeFixed64(b)
+			if n < 0 {
+				return s, fmt.Errorf("sample: page_faults_per_sec: %w", protowire.ParseError(n))
+			}
+			s.PageFaultsPerSec = math.Float64frombits(v)
+			b = b[n:]
+
+		case num == sfMajorFaultsPerSec && typ == protowire.Fixed64Type:
+			v, n := protowire.ConsumeFixed64(b)
+			if n < 0 {
+				return s, fmt.Errorf("sample: major_faults_per_sec: %w", protowire.ParseError(n))
+			}
+			s.MajorFaultsPerSec = math.Float64frombits(v)
+			b = b[n:]
+
+		case num == sfCpuThrottled && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			if n < 0 {
+				return s, fmt.Errorf("sample: cpu_throttled: %w", protowire.ParseError(n))
+			}
+			s.CpuThrottled = v != 0
 			b = b[n:]
 
 		default:
@@ -296,5 +1038,200 @@ func UnmarshalSample(b []byte) (Sample, error) {
 			b = b[n:]
 		}
 	}
+	s.Missing = MissingAll &^ seen
 	return s, nil
 }
+
+// ── Event ─────────────────────────────────────────────────────────────────────
+
+// Event is a timestamped annotation interleaved with the samples of a log: a
+// user marker, an alert transition, a collector notice, and so on.
+type Event struct {
+	TimestampUnixMs int64  `json:"timestamp_unix_ms"`
+	Kind            string `json:"kind"`    // short machine-readable tag, e.g. "marker"
+	Message         string `json:"message"` // free-form human-readable text
+}
+
+// Time converts TimestampUnixMs to a time.Time in UTC.
+func (e *Event) Time() time.Time {
+	return time.UnixMilli(e.TimestampUnixMs).UTC()
+}
+
+// Marshal serialises e to protobuf binary.
+func (e *Event) Marshal() []byte {
+	var b []byte
+	b = protowire.AppendTag(b, efTimestampUnixMs, protowire.VarintType)
+	b = protowire.AppendVarint(b, uint64(e.TimestampUnixMs))
+	if e.Kind != "" {
+		b = protowire.AppendTag(b, efKind, protowire.BytesType)
+		b = protowire.AppendString(b, e.Kind)
+	}
+	if e.Message != "" {
+		b = protowire.AppendTag(b, efMessage, protowire.BytesType)
+		b = protowire.AppendString(b, e.Message)
+	}
+	return b
+}
+
+// UnmarshalEvent deserialises an Event from protobuf binary.
+func UnmarshalEvent(b []byte) (Event, error) {
+	var e Event
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return e, fmt.Errorf("event: consume tag: %w", protowire.ParseError(n))
+		}
+		b = b[n:]
+
+		switch {
+		case num == efTimestampUnixMs && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			if n < 0 {
+				return e, fmt.Errorf("event: timestamp_unix_ms: %w", protowire.ParseError(n))
+			}
+			e.TimestampUnixMs = int64(v)
+			b = b[n:]
+
+		case num == efKind && typ == protowire.BytesType:
+			v, n := protowire.ConsumeString(b)
+			if n < 0 {
+				return e, fmt.Errorf("event: kind: %w", protowire.ParseError(n))
+			}
+			e.Kind = v
+			b = b[n:]
+
+		case num == efMessage && typ == protowire.BytesType:
+			v, n := protowire.ConsumeString(b)
+			if n < 0 {
+				return e, fmt.Errorf("event: message: %w", protowire.ParseError(n))
+			}
+			e.Message = v
+			b = b[n:]
+
+		default:
+			n := protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				return e, fmt.Errorf("event: skip unknown field %d: %w", num, protowire.ParseError(n))
+			}
+			b = b[n:]
+		}
+	}
+	return e, nil
+}
+
+// ── Process ───────────────────────────────────────────────────────────────────
+
+// Process is a reading of one process watched with -pid, logged as a record
+// of its own between the samples.
+type Process struct {
+	TimestampUnixMs int64 `json:"timestamp_unix_ms"`
+	Pid             int32 `json:"pid"`
+
+	// StartedUnixMs is when the process started, which tells it from a
+	// later one given the same pid.
+	StartedUnixMs int64  `json:"started_unix_ms"`
+	Name          string `json:"name"`
+
+	CpuPercent float64 `json:"cpu_percent"` // of one core, since the previous reading
+	RssBytes   int64   `json:"rss_bytes"`
+	Threads    int32   `json:"threads"`
+	Fds        int32   `json:"fds,omitempty"` // 0 where they could not be counted
+
+	// Exited marks the last record of a process, written once it has
+	// gone; its readings are unset.
+	Exited bool `json:"exited,omitempty"`
+}
+
+// Time converts TimestampUnixMs to a time.Time in UTC.
+func (p *Process) Time() time.Time {
+	return time.UnixMilli(p.TimestampUnixMs).UTC()
+}
+
+// Marshal serialises p to protobuf binary, omitting zero fields.
+func (p *Process) Marshal() []byte {
+	var b []byte
+	varint := func(num protowire.Number, v uint64) {
+		if v != 0 {
+			b = protowire.AppendTag(b, num, protowire.VarintType)
+			b = protowire.AppendVarint(b, v)
+		}
+	}
+	varint(pfTimestampUnixMs, uint64(p.TimestampUnixMs))
+	varint(pfPid, uint64(p.Pid))
+	varint(pfStartedUnixMs, uint64(p.StartedUnixMs))
+	if p.Name != "" {
+		b = protowire.AppendTag(b, pfName, protowire.BytesType)
+		b = protowire.AppendString(b, p.Name)
+	}
+	if p.CpuPercent != 0 {
+		b = appendDouble(b, pfCpuPercent, p.CpuPercent)
+	}
+	varint(pfRssBytes, uint64(p.RssBytes))
+	varint(pfThreads, uint64(p.Threads))
+	varint(pfFds, uint64(p.Fds))
+	if p.Exited {
+		varint(pfExited, 1)
+	}
+	return b
+}
+
+// UnmarshalProcess deserialises a Process from protobuf binary.
+func UnmarshalProcess(b []byte) (Process, error) {
+	var p Process
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return p, fmt.Errorf("process: consume tag: %w", protowire.ParseError(n))
+		}
+		b = b[n:]
+
+		switch {
+		case num == pfName && typ == protowire.BytesType:
+			v, n := protowire.ConsumeString(b)
+			if n < 0 {
+				return p, fmt.Errorf("process: name: %w", protowire.ParseError(n))
+			}
+			p.Name = v
+			b = b[n:]
+
+		case num == pfCpuPercent && typ == protowire.Fixed64Type:
+			v, n := protowire.ConsumeFixed64(b)
+			if n < 0 {
+				return p, fmt.Errorf("process: cpu_percent: %w", protowire.ParseError(n))
+			}
+			p.CpuPercent = math.Float64frombits(v)
+			b = b[n:]
+
+		case typ == protowire.VarintType && num >= pfTimestampUnixMs && num <= pfExited:
+			v, n := protowire.ConsumeVarint(b)
+			if n < 0 {
+				return p, fmt.Errorf("process: field %d: %w", num, protowire.ParseError(n))
+			}
+			switch num {
+			case pfTimestampUnixMs:
+				p.TimestampUnixMs = int64(v)
+			case pfPid:
+				p.Pid = int32(v)
+			case pfStartedUnixMs:
+				p.StartedUnixMs = int64(v)
+			case pfRssBytes:
+				p.RssBytes = int64(v)
+			case pfThreads:
+				p.Threads = int32(v)
+			case pfFds:
+				p.Fds = int32(v)
+			case pfExited:
+				p.Exited = v != 0
+			}
+			b = b[n:]
+
+		default:
+			n := protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				return p, fmt.Errorf("process: skip unknown field %d: %w", num, protowire.ParseError(n))
+			}
+			b = b[n:]
+		}
+	}
+	return p, nil
+}
@@ -4,7 +4,13 @@
 package metrics
 
 import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
 	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
 )
 
 func TestHeaderMarshalUnmarshal(t *testing.T) {
@@ -19,6 +25,12 @@ func TestHeaderMarshalUnmarshal(t *testing.T) {
 				Platform:      "linux · amd64",
 				StartedUnixMs: 1704067200000,
 				NumCores:      8,
+				IntervalMs:    500,
+				Timezone:      "America/New_York",
+				UTCOffsetS:    -5 * 3600,
+				Args:          []string{"-log", "auto", "-smtp-pass=<redacted>", ""},
+				Collectors:    []string{"cpu", "memory", "load"},
+				ConfigPath:    "none",
 			},
 		},
 		{
@@ -52,6 +64,16 @@ func TestHeaderMarshalUnmarshal(t *testing.T) {
 			if parsed.NumCores != tt.header.NumCores {
 				t.Errorf("NumCores: got %d, want %d", parsed.NumCores, tt.header.NumCores)
 			}
+			if parsed.IntervalMs != tt.header.IntervalMs {
+				t.Errorf("IntervalMs: got %d, want %d", parsed.IntervalMs, tt.header.IntervalMs)
+			}
+			if parsed.Timezone != tt.header.Timezone || parsed.UTCOffsetS != tt.header.UTCOffsetS {
+				t.Errorf("zone: got %q at %d, want %q at %d", parsed.Timezone, parsed.UTCOffsetS, tt.header.Timezone, tt.header.UTCOffsetS)
+			}
+			if !slices.Equal(parsed.Args, tt.header.Args) || !slices.Equal(parsed.Collectors, tt.header.Collectors) || parsed.ConfigPath != tt.header.ConfigPath {
+				t.Errorf("fingerprint: got %q %q %q, want %q %q %q", parsed.Args, parsed.Collectors, parsed.ConfigPath,
+					tt.header.Args, tt.header.Collectors, tt.header.ConfigPath)
+			}
 		})
 	}
 }
@@ -182,6 +204,9 @@ func TestSampleRoundTrip(t *testing.T) {
 		Load1:           2.41,
 		Load5:           1.89,
 		Load15:          1.42,
+		SwapUsedGB:      0.75,
+		SwapTotalGB:     2,
+		SwapPercent:     37.5,
 	}
 
 	data := original.Marshal()
@@ -223,6 +248,10 @@ func TestSampleRoundTrip(t *testing.T) {
 	if restored.Load15 != original.Load15 {
 		t.Errorf("Load15 mismatch: got %f, want %f", restored.Load15, original.Load15)
 	}
+	if restored.SwapUsedGB != original.SwapUsedGB || restored.SwapTotalGB != original.SwapTotalGB || restored.SwapPercent != original.SwapPercent {
+		t.Errorf("swap mismatch: got %f / %f (%f%%), want %f / %f (%f%%)", restored.SwapUsedGB, restored.SwapTotalGB, restored.SwapPercent,
+			original.SwapUsedGB, original.SwapTotalGB, original.SwapPercent)
+	}
 }
 
 func TestUnmarshalHeaderTruncation(t *testing.T) {
@@ -305,3 +334,404 @@ func TestUnmarshalSampleUnknownField(t *testing.T) {
 		t.Errorf("CpuTotal lost when unknown field present: got %f, want %f", parsed.CpuTotal, original.CpuTotal)
 	}
 }
+
+func TestEventRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		ev   Event
+	}{
+		{"full", Event{TimestampUnixMs: 1704067200000, Kind: "marker", Message: "deploy v2.3"}},
+		{"no message", Event{TimestampUnixMs: 1704067200500, Kind: "resume"}},
+		{"zero", Event{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UnmarshalEvent(tt.ev.Marshal())
+			if err != nil {
+				t.Fatalf("round trip failed: %v", err)
+			}
+			if got != tt.ev {
+				t.Errorf("got %+v, want %+v", got, tt.ev)
+			}
+		})
+	}
+}
+
+func TestProcessRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		p    Process
+	}{
+		{"full", Process{TimestampUnixMs: 1704067200000, Pid: 4242, StartedUnixMs: 1704060000000, Name: "nginx",
+			CpuPercent: 12.5, RssBytes: 48 << 20, Threads: 9, Fds: 31}},
+		{"exited", Process{TimestampUnixMs: 1704067200500, Pid: 4242, StartedUnixMs: 1704060000000, Name: "nginx", Exited: true}},
+		{"zero", Process{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UnmarshalProcess(tt.p.Marshal())
+			if err != nil {
+				t.Fatalf("round trip failed: %v", err)
+			}
+			if got != tt.p {
+				t.Errorf("got %+v, want %+v", got, tt.p)
+			}
+		})
+	}
+}
+
+func TestSampleMarshalAppend(t *testing.T) {
+	tests := []struct {
+		name string
+		s    Sample
+	}{
+		{"zero", Sample{}},
+		{"no cores", Sample{TimestampUnixMs: 1704067200000, CpuTotal: 12.5, Load15: 0.5}},
+		{"negative timestamp", Sample{TimestampUnixMs: -1, CpuCores: []float64{1}}},
+		{"many cores", Sample{TimestampUnixMs: 1704067200000, CpuCores: make([]float64, 256), MemTotalGB: 512}},
+		{"power", Sample{TimestampUnixMs: 1704067200000, CpuTotal: 3, PowerWatts: new(float64)}},
+		{"collect time", Sample{TimestampUnixMs: 1704067200000, Missing: MissingAll, CollectMs: new(float64)}},
+		{"disks", Sample{TimestampUnixMs: 1704067200000, Disks: []DiskUsage{{Mount: "/", UsedPercent: 40}, {}}}},
+		{"gpus", Sample{TimestampUnixMs: 1704067200000, Gpus: []GpuUsage{{Name: "RTX 4090", UtilPercent: 99, TempC: 70}, {}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := tt.s.Marshal()
+			if len(data) != tt.s.Size() {
+				t.Errorf("got %d bytes, Size %d", len(data), tt.s.Size())
+			}
+			prefix := []byte("prefix")
+			got := tt.s.MarshalAppend(prefix)
+			if string(got[:len(prefix)]) != "prefix" || !bytes.Equal(got[len(prefix):], data) {
+				t.Errorf("MarshalAppend did not append Marshal's encoding to the prefix")
+			}
+			back, err := UnmarshalSample(data)
+			if err != nil {
+				t.Fatalf("round trip failed: %v", err)
+			}
+			if back.TimestampUnixMs != tt.s.TimestampUnixMs || len(back.CpuCores) != len(tt.s.CpuCores) || back.MemTotalGB != tt.s.MemTotalGB {
+				t.Errorf("got %+v, want %+v", back, tt.s)
+			}
+			if (back.PowerWatts == nil) != (tt.s.PowerWatts == nil) {
+				t.Errorf("power_watts: got %v, want %v", back.PowerWatts, tt.s.PowerWatts)
+			}
+			if (back.CollectMs == nil) != (tt.s.CollectMs == nil) {
+				t.Errorf("collect_ms: got %v, want %v", back.CollectMs, tt.s.CollectMs)
+			}
+			if !slices.Equal(back.Disks, tt.s.Disks) {
+				t.Errorf("disks: got %v, want %v", back.Disks, tt.s.Disks)
+			}
+			if !slices.Equal(back.Gpus, tt.s.Gpus) {
+				t.Errorf("gpus: got %v, want %v", back.Gpus, tt.s.Gpus)
+			}
+
+			buf := make([]byte, 0, tt.s.Size())
+			if allocs := testing.AllocsPerRun(100, func() { buf = tt.s.MarshalAppend(buf[:0]) }); allocs != 0 {
+				t.Errorf("MarshalAppend into a buffer with room: got %v allocations, want 0", allocs)
+			}
+		})
+	}
+}
+
+// Each disk is a nested message of its own; an unknown field inside one is
+// skipped, a corrupt one fails the sample, and the count is capped.
+func TestSampleDisks(t *testing.T) {
+	s := Sample{TimestampUnixMs: 1704067200000, CpuTotal: 5, Disks: []DiskUsage{
+		{Mount: "/", UsedPercent: 61.5, UsedGB: 123, TotalGB: 200},
+		{Mount: "/var/log", UsedPercent: 12.25, UsedGB: 6.125, TotalGB: 50, InodesUsedPercent: 99.5},
+	}}
+	back, err := UnmarshalSample(s.Marshal())
+	if err != nil || !slices.Equal(back.Disks, s.Disks) || back.CpuTotal != 5 {
+		t.Fatalf("got %+v, %v", back, err)
+	}
+
+	// A later writer's field 9 in the disk, then a disk that ends early.
+	d := s.Disks[0]
+	inner := d.appendTo(nil)
+	inner = protowire.AppendTag(inner, 9, protowire.VarintType)
+	inner = protowire.AppendVarint(inner, 7)
+	b := protowire.AppendTag(nil, sfDisks, protowire.BytesType)
+	b = protowire.AppendBytes(b, inner)
+	if back, err := UnmarshalSample(b); err != nil || len(back.Disks) != 1 || back.Disks[0] != d {
+		t.Errorf("unknown field: got %+v, %v", back.Disks, err)
+	}
+	b = protowire.AppendTag(nil, sfDisks, protowire.BytesType)
+	b = protowire.AppendBytes(b, d.appendTo(nil)[:5])
+	if _, err := UnmarshalSample(b); err == nil || !strings.Contains(err.Error(), "disk") {
+		t.Errorf("truncated disk: got %v", err)
+	}
+
+	many := Sample{Disks: make([]DiskUsage, MaxDisks)}
+	if back, err := UnmarshalSample(many.Marshal()); err != nil || len(back.Disks) != MaxDisks {
+		t.Errorf("%d disks: got %d, %v", MaxDisks, len(back.Disks), err)
+	}
+	many.Disks = append(many.Disks, DiskUsage{})
+	if _, err := UnmarshalSample(many.Marshal()); err == nil {
+		t.Errorf("%d disks: got nil error", MaxDisks+1)
+	}
+
+	j, err := json.Marshal(s)
+	if err != nil || !strings.Contains(string(j), `"disks":[{"mount":"/","used_percent":61.5,"used_gb":123,"total_gb":200},`) {
+		t.Errorf("json: got %s, %v", j, err)
+	}
+}
+
+// Swap is written only where the machine has some.  A reader that
+// predates it skips the fields, and decoding a sample written before them
+// leaves them zero.
+func TestSampleSwap(t *testing.T) {
+	s := Sample{TimestampUnixMs: 1704067200000, CpuTotal: 5, MemPercent: 40, MemUsedGB: 4, MemTotalGB: 10,
+		SwapUsedGB: 1.5, SwapTotalGB: 8, SwapPercent: 18.75}
+	b := s.Marshal()
+	if len(b) != s.Size() {
+		t.Errorf("Size %d, encoded %d bytes", s.Size(), len(b))
+	}
+	back, err := UnmarshalSample(b)
+	if err != nil || back.SwapUsedGB != 1.5 || back.SwapTotalGB != 8 || back.SwapPercent != 18.75 || back.MemPercent != 40 {
+		t.Fatalf("got %+v, %v", back, err)
+	}
+
+	none := s
+	none.SwapUsedGB, none.SwapTotalGB, none.SwapPercent = 0, 0, 0
+	older := none.Marshal()
+	if len(b)-len(older) != 3*(1+8) {
+		t.Errorf("no swap: got %d bytes, want %d", len(older), len(b)-3*(1+8))
+	}
+	if back, err := UnmarshalSample(older); err != nil || back.SwapTotalGB != 0 || back.MemPercent != 40 {
+		t.Errorf("older sample: got %+v, %v", back, err)
+	}
+
+	// A reader that knows fields 1-12 only, as those before swap did.
+	var known []byte
+	for rest := b; len(rest) > 0; {
+		num, typ, n := protowire.ConsumeTag(rest)
+		m := protowire.ConsumeFieldValue(num, typ, rest[n:])
+		if num <= sfDisks {
+			known = append(known, rest[:n+m]...)
+		}
+		rest = rest[n+m:]
+	}
+	if !bytes.Equal(known, older) {
+		t.Errorf("swap fields are not a suffix of the encoding")
+	}
+
+	j, err := json.Marshal(none)
+	if err != nil || strings.Contains(string(j), "swap") {
+		t.Errorf("json without swap: got %s, %v", j, err)
+	}
+}
+
+// Each GPU is a nested message, as each disk is; an unknown field inside
+// one is skipped, a corrupt one fails the sample, and the count is capped.
+func TestSampleGpus(t *testing.T) {
+	s := Sample{TimestampUnixMs: 1704067200000, CpuTotal: 5, Gpus: []GpuUsage{
+		{Name: "NVIDIA A100", UtilPercent: 87, MemUsedGB: 30.5, MemTotalGB: 40, TempC: 64},
+		{Name: "NVIDIA A100", UtilPercent: 0, MemUsedGB: 0.25, MemTotalGB: 40, TempC: 31},
+	}}
+	back, err := UnmarshalSample(s.Marshal())
+	if err != nil || !slices.Equal(back.Gpus, s.Gpus) || back.CpuTotal != 5 {
+		t.Fatalf("got %+v, %v", back, err)
+	}
+	if got := s.Gpus[0].MemPercent(); got != 76.25 {
+		t.Errorf("MemPercent: got %v, want 76.25", got)
+	}
+	if got := (GpuUsage{MemUsedGB: 1}).MemPercent(); got != 0 {
+		t.Errorf("MemPercent without a total: got %v", got)
+	}
+
+	g := s.Gpus[0]
+	inner := g.appendTo(nil)
+	inner = protowire.AppendTag(inner, 9, protowire.VarintType)
+	inner = protowire.AppendVarint(inner, 7)
+	b := protowire.AppendTag(nil, sfGpus, protowire.BytesType)
+	b = protowire.AppendBytes(b, inner)
+	if back, err := UnmarshalSample(b); err != nil || len(back.Gpus) != 1 || back.Gpus[0] != g {
+		t.Errorf("unknown field: got %+v, %v", back.Gpus, err)
+	}
+	b = protowire.AppendTag(nil, sfGpus, protowire.BytesType)
+	b = protowire.AppendBytes(b, g.appendTo(nil)[:5])
+	if _, err := UnmarshalSample(b); err == nil || !strings.Contains(err.Error(), "gpu") {
+		t.Errorf("truncated gpu: got %v", err)
+	}
+
+	many := Sample{Gpus: make([]GpuUsage, MaxGpus+1)}
+	if _, err := UnmarshalSample(many.Marshal()); err == nil {
+		t.Errorf("%d GPUs: got nil error", MaxGpus+1)
+	}
+
+	j, err := json.Marshal(Sample{})
+	if err != nil || strings.Contains(string(j), "gpus") {
+		t.Errorf("json without GPUs: got %s, %v", j, err)
+	}
+}
+
+// The CPU modes are written only where they are not zero, after every
+// field before them.
+func TestSampleCPUModes(t *testing.T) {
+	s := Sample{TimestampUnixMs: 1704067200000, CpuTotal: 51, CpuUserPercent: 34, CpuSystemPercent: 12, CpuIowaitPercent: 5}
+	b := s.Marshal()
+	if len(b) != s.Size() {
+		t.Errorf("Size %d, encoded %d bytes", s.Size(), len(b))
+	}
+	back, err := UnmarshalSample(b)
+	if err != nil || back.CpuUserPercent != 34 || back.CpuSystemPercent != 12 || back.CpuIowaitPercent != 5 || back.CpuStealPercent != 0 {
+		t.Fatalf("got %+v, %v", back, err)
+	}
+	none := Sample{TimestampUnixMs: s.TimestampUnixMs, CpuTotal: 51}
+	if older := none.Marshal(); !bytes.Equal(b[:len(older)], older) {
+		t.Errorf("the modes are not a suffix of the encoding")
+	}
+	if j, err := json.Marshal(none); err != nil || strings.Contains(string(j), "cpu_user") {
+		t.Errorf("json without the modes: got %s, %v", j, err)
+	}
+}
+
+// The memory breakdown round-trips, and is written only where it is not
+// zero, after every field before it.
+func TestSampleMemBreakdown(t *testing.T) {
+	s := Sample{TimestampUnixMs: 1704067200000, MemPercent: 40, CpuUserPercent: 3, MemAvailableGB: 9.1, MemCachedGB: 4.2, MemBuffersGB: 0.3}
+	b := s.Marshal()
+	if len(b) != s.Size() {
+		t.Errorf("Size %d, encoded %d bytes", s.Size(), len(b))
+	}
+	back, err := UnmarshalSample(b)
+	if err != nil || back.MemAvailableGB != 9.1 || back.MemCachedGB != 4.2 || back.MemBuffersGB != 0.3 || back.CpuUserPercent != 3 {
+		t.Fatalf("got %+v, %v", back, err)
+	}
+	none := Sample{TimestampUnixMs: s.TimestampUnixMs, MemPercent: 40, CpuUserPercent: 3}
+	if older := none.Marshal(); !bytes.Equal(b[:len(older)], older) {
+		t.Errorf("the breakdown is not a suffix of the encoding")
+	}
+	if j, err := json.Marshal(none); err != nil || strings.Contains(string(j), "mem_available") {
+		t.Errorf("json without the breakdown: got %s, %v", j, err)
+	}
+}
+
+// The fault rates round-trip, and are written only where they are not
+// zero.
+func TestSampleFaults(t *testing.T) {
+	s := Sample{TimestampUnixMs: 1704067200000, MemPercent: 40, MemBuffersGB: 0.3, PageFaultsPerSec: 120, MajorFaultsPerSec: 4}
+	b := s.Marshal()
+	if len(b) != s.Size() {
+		t.Errorf("Size %d, encoded %d bytes", s.Size(), len(b))
+	}
+	back, err := UnmarshalSample(b)
+	if err != nil || back.PageFaultsPerSec != 120 || back.MajorFaultsPerSec != 4 || back.MemBuffersGB != 0.3 {
+		t.Fatalf("got %+v, %v", back, err)
+	}
+	none := Sample{TimestampUnixMs: s.TimestampUnixMs, MemPercent: 40, MemBuffersGB: 0.3}
+	if older := none.Marshal(); !bytes.Equal(b[:len(older)], older) {
+		t.Errorf("the fault rates are not a suffix of the encoding")
+	}
+	if j, err := json.Marshal(none); err != nil || strings.Contains(string(j), "faults") {
+		t.Errorf("json without the fault rates: got %s, %v", j, err)
+	}
+}
+
+// Throttling round-trips, and is written only while it is set.
+func TestSampleThrottled(t *testing.T) {
+	s := Sample{TimestampUnixMs: 1704067200000, MemPercent: 40, MajorFaultsPerSec: 4, CpuThrottled: true}
+	b := s.Marshal()
+	if len(b) != s.Size() {
+		t.Errorf("Size %d, encoded %d bytes", s.Size(), len(b))
+	}
+	back, err := UnmarshalSample(b)
+	if err != nil || !back.CpuThrottled || back.MajorFaultsPerSec != 4 {
+		t.Fatalf("got %+v, %v", back, err)
+	}
+	none := s
+	none.CpuThrottled = false
+	if older := none.Marshal(); !bytes.Equal(b[:len(older)], older) || len(older) != none.Size() {
+		t.Errorf("throttling is not a suffix of the encoding")
+	}
+	if j, err := json.Marshal(none); err != nil || strings.Contains(string(j), "throttled") {
+		t.Errorf("json without throttling: got %s, %v", j, err)
+	}
+}
+
+func BenchmarkSampleMarshal(b *testing.B) {
+	s := Sample{TimestampUnixMs: 1704067200000, CpuCores: make([]float64, 64)}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		s.Marshal()
+	}
+}
+
+func BenchmarkSampleMarshalAppend(b *testing.B) {
+	s := Sample{TimestampUnixMs: 1704067200000, CpuCores: make([]float64, 64)}
+	var buf []byte
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf = s.MarshalAppend(buf[:0])
+	}
+}
+
+func TestSampleMissing(t *testing.T) {
+	full := Sample{TimestampUnixMs: 1000, CpuTotal: 42, CpuCores: []float64{40, 44},
+		MemPercent: 50, MemUsedGB: 4, MemTotalGB: 8, Load1: 1, Load5: 2, Load15: 3}
+	tests := []struct {
+		missing Missing
+		text    string
+	}{
+		{0, ""},
+		{MissingCPU, "cpu"},
+		{MissingMem, "mem"},
+		{MissingLoad, "load"},
+		{MissingMem | MissingLoad, "mem,load"},
+		{MissingAll, "cpu,mem,load"},
+	}
+	for _, tt := range tests {
+		s := full
+		s.Missing = tt.missing
+		data := s.Marshal()
+		if len(data) != s.Size() {
+			t.Errorf("%q: got %d bytes, Size %d", tt.missing, len(data), s.Size())
+		}
+		got, err := UnmarshalSample(data)
+		if err != nil {
+			t.Fatalf("%q: round trip failed: %v", tt.missing, err)
+		}
+		if got.Missing != tt.missing {
+			t.Errorf("%q: got Missing %q back", tt.missing, got.Missing)
+		}
+		// The missing fields are not in the encoding at all.
+		if tt.missing.Has(MissingMem) && (got.MemPercent != 0 || got.MemTotalGB != 0) ||
+			tt.missing.Has(MissingCPU) && (got.CpuTotal != 0 || got.CpuCores != nil) ||
+			!tt.missing.Has(MissingLoad) && got.Load15 != 3 {
+			t.Errorf("%q: got %+v", tt.missing, got)
+		}
+
+		if text := tt.missing.String(); text != tt.text {
+			t.Errorf("%q: String got %q, want %q", tt.missing, text, tt.text)
+		}
+		var back Missing
+		if err := back.UnmarshalText([]byte(tt.text)); err != nil || back != tt.missing {
+			t.Errorf("UnmarshalText(%q): got %q, %v", tt.text, back, err)
+		}
+	}
+
+	// A sample from a writer that always set every field, even to zero,
+	// has nothing missing.
+	if got, _ := UnmarshalSample((&Sample{}).Marshal()); got.Missing != 0 {
+		t.Errorf("all-zero sample: got Missing %q", got.Missing)
+	}
+	var m Missing
+	if err := m.UnmarshalText([]byte("disk")); err == nil {
+		t.Error("UnmarshalText accepted an unknown group")
+	}
+
+	// The JSON form names the groups and is left out when none is missing.
+	s := full
+	s.Missing = MissingMem
+	b, _ := json.Marshal(s)
+	if !strings.Contains(string(b), `"missing":"mem"`) {
+		t.Errorf("JSON: got %s", b)
+	}
+	var back Sample
+	if err := json.Unmarshal(b, &back); err != nil || back.Missing != MissingMem {
+		t.Errorf("JSON round trip: got %q, %v", back.Missing, err)
+	}
+	if b, _ := json.Marshal(full); strings.Contains(string(b), "missing") {
+		t.Errorf("JSON with nothing missing: got %s", b)
+	}
+}
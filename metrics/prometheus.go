@@ -0,0 +1,119 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ── Prometheus exposition ─────────────────────────────────────────────────────
+
+// PromGauge is an extra gauge for WritePrometheus, used for collector
+// metadata that is not part of a Sample (scrape duration, sample age, …).
+type PromGauge struct {
+	Name  string // full metric name, e.g. "infgo_sample_age_seconds"
+	Help  string
+	Value float64
+}
+
+// bytesPerGB converts the GiB values stored in a Sample back to bytes, the
+// base unit Prometheus conventions ask for.
+const bytesPerGB = 1 << 30
+
+// PromLabel is one label of a PromSeries.
+type PromLabel struct{ Name, Value string }
+
+// PromSeries is one value of the exposition.  WritePrometheus and the
+// remote-write push both render PromSeriesOf, so a dashboard built on
+// one works against the other.
+type PromSeries struct {
+	Name   string
+	Help   string
+	Labels []PromLabel
+	Value  float64
+}
+
+// PromSeriesOf lists the series for s in exposition order; series of one
+// metric are adjacent.  Host metadata from h, when non-nil, is exposed as
+// the labels of an infgo_info gauge rather than repeated on every series.
+func PromSeriesOf(h *Header, s *Sample) []PromSeries {
+	var out []PromSeries
+	add := func(name, help string, v float64, labels ...PromLabel) {
+		out = append(out, PromSeries{Name: name, Help: help, Labels: labels, Value: v})
+	}
+
+	if h != nil {
+		add("infgo_info", "Host metadata; always 1.", 1,
+			PromLabel{"hostname", h.Hostname}, PromLabel{"platform", h.Platform})
+		add("infgo_cpu_logical_cores", "Number of logical CPU cores.", float64(h.NumCores))
+	}
+
+	add("infgo_sample_timestamp_seconds", "Unix time at which the sample was taken.", float64(s.TimestampUnixMs)/1000)
+
+	// A missing group has no series rather than zeros, so Prometheus
+	// marks it stale instead of graphing a drop.
+	if !s.Missing.Has(MissingCPU) {
+		add("infgo_cpu_usage_percent", "Aggregate CPU utilisation across all logical cores, 0-100.", s.CpuTotal)
+		for i, c := range s.CpuCores {
+			add("infgo_cpu_core_usage_percent", "Per-logical-core CPU utilisation, 0-100.", c,
+				PromLabel{"core", strconv.Itoa(i)})
+		}
+	}
+
+	if !s.Missing.Has(MissingMem) {
+		add("infgo_memory_used_percent", "Used virtual memory, 0-100.", s.MemPercent)
+		add("infgo_memory_used_bytes", "Used virtual memory in bytes.", s.MemUsedGB*bytesPerGB)
+		add("infgo_memory_total_bytes", "Total virtual memory in bytes.", s.MemTotalGB*bytesPerGB)
+	}
+
+	if !s.Missing.Has(MissingLoad) {
+		const loadHelp = "System load average over the window given by the period label."
+		add("infgo_load_average", loadHelp, s.Load1, PromLabel{"period", "1m"})
+		add("infgo_load_average", loadHelp, s.Load5, PromLabel{"period", "5m"})
+		add("infgo_load_average", loadHelp, s.Load15, PromLabel{"period", "15m"})
+	}
+
+	if s.PowerWatts != nil {
+		add("infgo_power_watts", "CPU package power since the previous sample, from RAPL.", *s.PowerWatts)
+	}
+	return out
+}
+
+// WritePrometheus renders s in the Prometheus text exposition format
+// (version 0.0.4): the series of PromSeriesOf, then the extra gauges.
+func WritePrometheus(w io.Writer, h *Header, s *Sample, extra ...PromGauge) error {
+	series := PromSeriesOf(h, s)
+	for _, g := range extra {
+		series = append(series, PromSeries{Name: g.Name, Help: g.Help, Value: g.Value})
+	}
+
+	bw := bufio.NewWriter(w)
+	for i, ps := range series {
+		if i == 0 || series[i-1].Name != ps.Name {
+			fmt.Fprintf(bw, "# HELP %s %s\n# TYPE %s gauge\n", ps.Name, ps.Help, ps.Name)
+		}
+		bw.WriteString(ps.Name)
+		if len(ps.Labels) > 0 {
+			bw.WriteByte('{')
+			for j, l := range ps.Labels {
+				if j > 0 {
+					bw.WriteByte(',')
+				}
+				fmt.Fprintf(bw, `%s="%s"`, l.Name, escapeLabel(l.Value))
+			}
+			bw.WriteByte('}')
+		}
+		bw.WriteString(" " + strconv.FormatFloat(ps.Value, 'g', -1, 64) + "\n")
+	}
+	return bw.Flush()
+}
+
+// labelEscaper applies the exposition format's label-value escaping.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(v string) string { return labelEscaper.Replace(v) }
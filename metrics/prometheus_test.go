@@ -0,0 +1,73 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWritePrometheus(t *testing.T) {
+	h := &Header{Hostname: `web "1"`, Platform: "linux · amd64", NumCores: 2}
+	s := &Sample{
+		TimestampUnixMs: 1704067200500,
+		CpuTotal:        42.5,
+		CpuCores:        []float64{40, 45},
+		MemPercent:      50,
+		MemUsedGB:       2,
+		MemTotalGB:      4,
+		Load1:           1.25,
+	}
+	var buf bytes.Buffer
+	err := WritePrometheus(&buf, h, s, PromGauge{Name: "infgo_sample_age_seconds", Help: "Age.", Value: 0.25})
+	if err != nil {
+		t.Fatalf("WritePrometheus failed: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		`infgo_info{hostname="web \"1\"",platform="linux · amd64"} 1`,
+		"infgo_sample_timestamp_seconds 1.7040672005e+09",
+		"infgo_cpu_usage_percent 42.5",
+		`infgo_cpu_core_usage_percent{core="1"} 45`,
+		"infgo_memory_used_bytes 2.147483648e+09",
+		`infgo_load_average{period="1m"} 1.25`,
+		"# TYPE infgo_sample_age_seconds gauge\ninfgo_sample_age_seconds 0.25",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	// Every HELP line is followed by its TYPE line, and each family appears once.
+	seen := map[string]bool{}
+	lines := strings.Split(out, "\n")
+	for i, line := range lines {
+		if name, ok := strings.CutPrefix(line, "# HELP "); ok {
+			name, _, _ = strings.Cut(name, " ")
+			if seen[name] {
+				t.Errorf("family %s declared twice", name)
+			}
+			seen[name] = true
+			if i+1 >= len(lines) || lines[i+1] != "# TYPE "+name+" gauge" {
+				t.Errorf("HELP for %s not followed by its TYPE", name)
+			}
+		}
+	}
+
+	buf.Reset()
+	if err := WritePrometheus(&buf, nil, s); err != nil || strings.Contains(buf.String(), "infgo_info") {
+		t.Errorf("without a header: err=%v, output has infgo_info=%v", err, strings.Contains(buf.String(), "infgo_info"))
+	}
+
+	// A group that could not be read has no series, so Prometheus marks
+	// it stale rather than recording a drop to zero.
+	buf.Reset()
+	s.Missing = MissingMem
+	if err := WritePrometheus(&buf, nil, s); err != nil || strings.Contains(buf.String(), "infgo_memory") ||
+		!strings.Contains(buf.String(), "infgo_cpu_usage_percent 42.5") {
+		t.Errorf("mem missing: err=%v, output:\n%s", err, buf.String())
+	}
+}
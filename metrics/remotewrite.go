@@ -0,0 +1,179 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// ── Prometheus remote write ───────────────────────────────────────────────────
+//
+// The wire format is prometheus.WriteRequest from the remote-write 1.0
+// spec, encoded by hand like the capture records:
+//
+//	message WriteRequest { repeated TimeSeries timeseries = 1; }
+//	message TimeSeries   { repeated Label labels = 1; repeated Sample samples = 2; }
+//	message Label        { string name = 1; string value = 2; }
+//	message Sample       { double value = 1; int64 timestamp = 2; }
+
+const (
+	wrTimeseries protowire.Number = 1
+
+	tsLabels  protowire.Number = 1
+	tsSamples protowire.Number = 2
+
+	lbName  protowire.Number = 1
+	lbValue protowire.Number = 2
+
+	smValue     protowire.Number = 1
+	smTimestamp protowire.Number = 2
+)
+
+// RemoteSample is one point of a RemoteSeries.
+type RemoteSample struct {
+	Value       float64
+	TimestampMs int64
+}
+
+// RemoteSeries is one TimeSeries of a WriteRequest: its labels, including
+// __name__, sorted by name as the spec requires, and its samples in time
+// order.
+type RemoteSeries struct {
+	Labels  []PromLabel
+	Samples []RemoteSample
+}
+
+// RemoteLabels is the label set remote write sends for ps: __name__, the
+// series' own labels and extra (typically job and instance), sorted by
+// name.
+func RemoteLabels(ps *PromSeries, extra ...PromLabel) []PromLabel {
+	out := make([]PromLabel, 0, 1+len(ps.Labels)+len(extra))
+	out = append(out, PromLabel{"__name__", ps.Name})
+	out = append(out, ps.Labels...)
+	out = append(out, extra...)
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// MarshalWriteRequest serialises series as a WriteRequest.  The result is
+// sent snappy-compressed.
+func MarshalWriteRequest(series []RemoteSeries) []byte {
+	var b, ts, msg []byte
+	for _, s := range series {
+		ts = ts[:0]
+		for _, l := range s.Labels {
+			msg = msg[:0]
+			msg = protowire.AppendTag(msg, lbName, protowire.BytesType)
+			msg = protowire.AppendString(msg, l.Name)
+			msg = protowire.AppendTag(msg, lbValue, protowire.BytesType)
+			msg = protowire.AppendString(msg, l.Value)
+			ts = protowire.AppendTag(ts, tsLabels, protowire.BytesType)
+			ts = protowire.AppendBytes(ts, msg)
+		}
+		for _, p := range s.Samples {
+			msg = msg[:0]
+			msg = protowire.AppendTag(msg, smValue, protowire.Fixed64Type)
+			msg = protowire.AppendFixed64(msg, math.Float64bits(p.Value))
+			msg = protowire.AppendTag(msg, smTimestamp, protowire.VarintType)
+			msg = protowire.AppendVarint(msg, uint64(p.TimestampMs))
+			ts = protowire.AppendTag(ts, tsSamples, protowire.BytesType)
+			ts = protowire.AppendBytes(ts, msg)
+		}
+		b = protowire.AppendTag(b, wrTimeseries, protowire.BytesType)
+		b = protowire.AppendBytes(b, ts)
+	}
+	return b
+}
+
+// UnmarshalWriteRequest deserialises a WriteRequest, skipping the fields
+// infgo does not send (exemplars, histograms, metadata).
+func UnmarshalWriteRequest(b []byte) ([]RemoteSeries, error) {
+	var out []RemoteSeries
+	err := consumeMessage(b, "write request", func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		if num != wrTimeseries || typ != protowire.BytesType {
+			return protowire.ConsumeFieldValue(num, typ, b), nil
+		}
+		raw, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return n, nil
+		}
+		s, err := unmarshalTimeSeries(raw)
+		out = append(out, s)
+		return n, err
+	})
+	return out, err
+}
+
+func unmarshalTimeSeries(b []byte) (RemoteSeries, error) {
+	var s RemoteSeries
+	err := consumeMessage(b, "timeseries", func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		if typ != protowire.BytesType || (num != tsLabels && num != tsSamples) {
+			return protowire.ConsumeFieldValue(num, typ, b), nil
+		}
+		raw, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return n, nil
+		}
+		if num == tsLabels {
+			var l PromLabel
+			err := consumeMessage(raw, "label", func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+				if typ != protowire.BytesType || (num != lbName && num != lbValue) {
+					return protowire.ConsumeFieldValue(num, typ, b), nil
+				}
+				v, n := protowire.ConsumeString(b)
+				if num == lbName {
+					l.Name = v
+				} else {
+					l.Value = v
+				}
+				return n, nil
+			})
+			s.Labels = append(s.Labels, l)
+			return n, err
+		}
+		var p RemoteSample
+		err := consumeMessage(raw, "sample", func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+			switch {
+			case num == smValue && typ == protowire.Fixed64Type:
+				v, n := protowire.ConsumeFixed64(b)
+				p.Value = math.Float64frombits(v)
+				return n, nil
+			case num == smTimestamp && typ == protowire.VarintType:
+				v, n := protowire.ConsumeVarint(b)
+				p.TimestampMs = int64(v)
+				return n, nil
+			}
+			return protowire.ConsumeFieldValue(num, typ, b), nil
+		})
+		s.Samples = append(s.Samples, p)
+		return n, err
+	})
+	return s, err
+}
+
+// consumeMessage calls field for each field of the message b.  field
+// returns how many bytes of the value it consumed, negative for a
+// protowire parse error.
+func consumeMessage(b []byte, what string, field func(protowire.Number, protowire.Type, []byte) (int, error)) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return fmt.Errorf("%s: consume tag: %w", what, protowire.ParseError(n))
+		}
+		b = b[n:]
+		n, err := field(num, typ, b)
+		if err != nil {
+			return err
+		}
+		if n < 0 {
+			return fmt.Errorf("%s: field %d: %w", what, num, protowire.ParseError(n))
+		}
+		b = b[n:]
+	}
+	return nil
+}
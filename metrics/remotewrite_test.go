@@ -0,0 +1,41 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWriteRequestRoundTrip(t *testing.T) {
+	in := []RemoteSeries{
+		{
+			Labels:  []PromLabel{{"__name__", "infgo_cpu_usage_percent"}, {"instance", "node7"}, {"job", "infgo"}},
+			Samples: []RemoteSample{{42.5, 1704067200000}, {-1, 1704067200500}},
+		},
+		{
+			Labels:  []PromLabel{{"__name__", "infgo_info"}, {"hostname", ""}},
+			Samples: []RemoteSample{{1, 0}},
+		},
+	}
+	out, err := UnmarshalWriteRequest(MarshalWriteRequest(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(out, in) {
+		t.Errorf("got %+v, want %+v", out, in)
+	}
+	if _, err := UnmarshalWriteRequest([]byte{0x0a, 0x05, 0x0a}); err == nil {
+		t.Error("truncated request: got nil error")
+	}
+}
+
+func TestRemoteLabels(t *testing.T) {
+	ps := PromSeries{Name: "infgo_load_average", Labels: []PromLabel{{"period", "5m"}}}
+	got := RemoteLabels(&ps, PromLabel{"instance", "node7"}, PromLabel{"job", "infgo"})
+	want := []PromLabel{{"__name__", "infgo_load_average"}, {"instance", "node7"}, {"job", "infgo"}, {"period", "5m"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
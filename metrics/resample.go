@@ -0,0 +1,249 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// ── Downsampling ──────────────────────────────────────────────────────────────
+
+// Agg selects how the samples falling into one resampling bucket are
+// combined into a single output Sample.
+type Agg int
+
+const (
+	AggMean Agg = iota // arithmetic mean of every field
+	AggMax             // per-field maximum; preserves peaks
+)
+
+// String returns the flag spelling of a.
+func (a Agg) String() string {
+	switch a {
+	case AggMean:
+		return "mean"
+	case AggMax:
+		return "max"
+	default:
+		return fmt.Sprintf("Agg(%d)", int(a))
+	}
+}
+
+// ParseAgg parses the flag spelling of an aggregation ("mean" or "max").
+func ParseAgg(s string) (Agg, error) {
+	switch s {
+	case "mean", "avg":
+		return AggMean, nil
+	case "max":
+		return AggMax, nil
+	default:
+		return 0, fmt.Errorf("unknown aggregation %q (want mean or max)", s)
+	}
+}
+
+// Resampler folds a time-ordered stream of Samples into fixed-width buckets
+// aligned to multiples of the bucket width since the Unix epoch.  Each output
+// Sample is stamped with its bucket's start time.
+//
+// Use Add for every input sample in order and Flush once at the end.
+type Resampler struct {
+	everyMs int64
+	agg     Agg
+
+	bucket int64 // start of the open bucket (ms); valid when n > 0
+	n      int   // samples folded into the open bucket
+	acc    Sample
+	coreN  []int // per-core contribution counts (cores may come and go)
+	gpuN   []int // per-GPU contribution counts, likewise
+}
+
+// NewResampler returns a Resampler producing one Sample per every.
+// every must be at least one millisecond.
+func NewResampler(every time.Duration, agg Agg) (*Resampler, error) {
+	if every < time.Millisecond {
+		return nil, fmt.Errorf("resample interval %v is below 1ms", every)
+	}
+	return &Resampler{everyMs: every.Milliseconds(), agg: agg}, nil
+}
+
+// Add folds s into the open bucket.  When s belongs to a later bucket the
+// open one is completed and returned with ok == true.  Samples older than
+// the open bucket (out-of-order input) are folded into it rather than
+// reopening a finished bucket.
+func (r *Resampler) Add(s Sample) (out Sample, ok bool) {
+	b := s.TimestampUnixMs - mod(s.TimestampUnixMs, r.everyMs)
+	if r.n > 0 && b > r.bucket {
+		out, ok = r.finish(), true
+	}
+	if r.n == 0 {
+		r.bucket = b
+		r.acc = Sample{TimestampUnixMs: b}
+		r.coreN = r.coreN[:0]
+		r.gpuN = r.gpuN[:0]
+	}
+	r.fold(s)
+	return out, ok
+}
+
+// Flush completes and returns the open bucket, if any.
+func (r *Resampler) Flush() (Sample, bool) {
+	if r.n == 0 {
+		return Sample{}, false
+	}
+	return r.finish(), true
+}
+
+// fold accumulates s into the open bucket.
+func (r *Resampler) fold(s Sample) {
+	a := &r.acc
+	first := r.n == 0
+	r.n++
+
+	combine := func(dst *float64, v float64) {
+		switch {
+		case first:
+			*dst = v
+		case r.agg == AggMax:
+			*dst = math.Max(*dst, v)
+		default:
+			*dst += v
+		}
+	}
+	combine(&a.CpuTotal, s.CpuTotal)
+	combine(&a.MemPercent, s.MemPercent)
+	combine(&a.MemUsedGB, s.MemUsedGB)
+	combine(&a.MemTotalGB, s.MemTotalGB)
+	combine(&a.Load1, s.Load1)
+	combine(&a.Load5, s.Load5)
+	combine(&a.Load15, s.Load15)
+	combine(&a.SwapUsedGB, s.SwapUsedGB)
+	combine(&a.SwapTotalGB, s.SwapTotalGB)
+	combine(&a.SwapPercent, s.SwapPercent)
+	combine(&a.CpuUserPercent, s.CpuUserPercent)
+	combine(&a.CpuSystemPercent, s.CpuSystemPercent)
+	combine(&a.CpuIowaitPercent, s.CpuIowaitPercent)
+	combine(&a.CpuStealPercent, s.CpuStealPercent)
+	combine(&a.MemAvailableGB, s.MemAvailableGB)
+	combine(&a.MemCachedGB, s.MemCachedGB)
+	combine(&a.MemBuffersGB, s.MemBuffersGB)
+	combine(&a.PageFaultsPerSec, s.PageFaultsPerSec)
+	combine(&a.MajorFaultsPerSec, s.MajorFaultsPerSec)
+	// A bucket with any reading throttled is throttled.
+	a.CpuThrottled = s.CpuThrottled || (!first && a.CpuThrottled)
+
+	// Disk usage moves slowly; a bucket keeps the latest reported.
+	if s.Disks != nil {
+		a.Disks = s.Disks
+	}
+
+	// Per-core values are combined index by index; a core missing from
+	// some samples is averaged only over the samples that reported it.
+	for i, v := range s.CpuCores {
+		if i >= len(a.CpuCores) {
+			a.CpuCores = append(a.CpuCores, v)
+			r.coreN = append(r.coreN, 1)
+			continue
+		}
+		r.coreN[i]++
+		if r.agg == AggMax {
+			a.CpuCores[i] = math.Max(a.CpuCores[i], v)
+		} else {
+			a.CpuCores[i] += v
+		}
+	}
+
+	// GPUs likewise, field by field; the name is the latest reported.
+	add := func(dst *float64, v float64) {
+		if r.agg == AggMax {
+			*dst = math.Max(*dst, v)
+		} else {
+			*dst += v
+		}
+	}
+	for i, g := range s.Gpus {
+		if i >= len(a.Gpus) {
+			a.Gpus = append(a.Gpus, g)
+			r.gpuN = append(r.gpuN, 1)
+			continue
+		}
+		r.gpuN[i]++
+		d := &a.Gpus[i]
+		d.Name = g.Name
+		add(&d.UtilPercent, g.UtilPercent)
+		add(&d.MemUsedGB, g.MemUsedGB)
+		add(&d.MemTotalGB, g.MemTotalGB)
+		add(&d.TempC, g.TempC)
+	}
+}
+
+// finish closes the open bucket and returns its aggregate.
+func (r *Resampler) finish() Sample {
+	out := r.acc
+	if r.agg == AggMean {
+		n := float64(r.n)
+		out.CpuTotal /= n
+		out.MemPercent /= n
+		out.MemUsedGB /= n
+		out.MemTotalGB /= n
+		out.Load1 /= n
+		out.Load5 /= n
+		out.Load15 /= n
+		out.SwapUsedGB /= n
+		out.SwapTotalGB /= n
+		out.SwapPercent /= n
+		out.CpuUserPercent /= n
+		out.CpuSystemPercent /= n
+		out.CpuIowaitPercent /= n
+		out.CpuStealPercent /= n
+		out.MemAvailableGB /= n
+		out.MemCachedGB /= n
+		out.MemBuffersGB /= n
+		out.PageFaultsPerSec /= n
+		out.MajorFaultsPerSec /= n
+		for i := range out.CpuCores {
+			out.CpuCores[i] /= float64(r.coreN[i])
+		}
+		for i := range out.Gpus {
+			g, n := &out.Gpus[i], float64(r.gpuN[i])
+			g.UtilPercent /= n
+			g.MemUsedGB /= n
+			g.MemTotalGB /= n
+			g.TempC /= n
+		}
+	}
+	r.n = 0
+	r.acc = Sample{}
+	return out
+}
+
+// Downsample is a convenience wrapper around Resampler for in-memory slices.
+// samples must be ordered by timestamp.
+func Downsample(samples []Sample, every time.Duration, agg Agg) ([]Sample, error) {
+	r, err := NewResampler(every, agg)
+	if err != nil {
+		return nil, err
+	}
+	var out []Sample
+	for _, s := range samples {
+		if o, ok := r.Add(s); ok {
+			out = append(out, o)
+		}
+	}
+	if o, ok := r.Flush(); ok {
+		out = append(out, o)
+	}
+	return out, nil
+}
+
+// mod is the Euclidean remainder, so buckets align correctly for
+// timestamps before the epoch too.
+func mod(a, b int64) int64 {
+	m := a % b
+	if m < 0 {
+		m += b
+	}
+	return m
+}
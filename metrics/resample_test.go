@@ -0,0 +1,168 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// rampSamples returns n samples spaced stepMs apart starting at startMs, with
+// CpuTotal rising by one per sample (wrapping every 50) and a single spike at
+// index spike.
+func rampSamples(startMs, stepMs int64, n, spike int) []Sample {
+	out := make([]Sample, n)
+	for i := range out {
+		cpu := float64(i % 50)
+		if i == spike {
+			cpu = 99.5
+		}
+		out[i] = Sample{
+			TimestampUnixMs: startMs + int64(i)*stepMs,
+			CpuTotal:        cpu,
+			CpuCores:        []float64{cpu, 2 * cpu},
+			MemPercent:      50,
+			MemTotalGB:      16,
+		}
+	}
+	return out
+}
+
+func TestDownsampleMean(t *testing.T) {
+	// 40 samples at 500 ms = 20 s of data → two 10 s buckets.
+	in := rampSamples(1704067200000, 500, 40, -1)
+	out, err := Downsample(in, 10*time.Second, AggMean)
+	if err != nil {
+		t.Fatalf("Downsample failed: %v", err)
+	}
+	if len(out) != 2 {
+		t.Fatalf("buckets: got %d, want 2", len(out))
+	}
+	if out[0].TimestampUnixMs != 1704067200000 || out[1].TimestampUnixMs != 1704067210000 {
+		t.Errorf("bucket stamps: got %d, %d", out[0].TimestampUnixMs, out[1].TimestampUnixMs)
+	}
+	// Mean of 0..19 is 9.5; mean of 20..39 is 29.5.
+	if out[0].CpuTotal != 9.5 || out[1].CpuTotal != 29.5 {
+		t.Errorf("CpuTotal: got %f, %f, want 9.5, 29.5", out[0].CpuTotal, out[1].CpuTotal)
+	}
+	if len(out[0].CpuCores) != 2 || out[0].CpuCores[1] != 19 {
+		t.Errorf("CpuCores: got %v, want [9.5 19]", out[0].CpuCores)
+	}
+	if out[0].MemTotalGB != 16 {
+		t.Errorf("MemTotalGB: got %f, want 16", out[0].MemTotalGB)
+	}
+}
+
+func TestDownsampleMaxPreservesPeaks(t *testing.T) {
+	in := rampSamples(1704067200000, 500, 120, 37)
+	for _, agg := range []Agg{AggMean, AggMax} {
+		out, err := Downsample(in, 30*time.Second, agg)
+		if err != nil {
+			t.Fatalf("Downsample(%v) failed: %v", agg, err)
+		}
+		var peak, corePeak float64
+		for _, s := range out {
+			peak = max(peak, s.CpuTotal)
+			corePeak = max(corePeak, s.CpuCores[0])
+		}
+		switch agg {
+		case AggMax:
+			if peak != 99.5 || corePeak != 99.5 {
+				t.Errorf("max aggregation lost the peak: got %f (core %f), want 99.5", peak, corePeak)
+			}
+		case AggMean:
+			if peak >= 99.5 {
+				t.Errorf("mean aggregation should smooth the spike, got %f", peak)
+			}
+		}
+	}
+}
+
+func TestResamplerVaryingCores(t *testing.T) {
+	r, err := NewResampler(time.Second, AggMean)
+	if err != nil {
+		t.Fatalf("NewResampler failed: %v", err)
+	}
+	r.Add(Sample{TimestampUnixMs: 0, CpuCores: []float64{10}})
+	r.Add(Sample{TimestampUnixMs: 500, CpuCores: []float64{30, 40}})
+	out, ok := r.Flush()
+	if !ok {
+		t.Fatal("Flush returned no bucket")
+	}
+	if len(out.CpuCores) != 2 || out.CpuCores[0] != 20 || out.CpuCores[1] != 40 {
+		t.Errorf("CpuCores: got %v, want [20 40]", out.CpuCores)
+	}
+	if _, ok := r.Flush(); ok {
+		t.Error("second Flush should be empty")
+	}
+}
+
+func TestResamplerKeepsLatestDisks(t *testing.T) {
+	r, err := NewResampler(time.Second, AggMean)
+	if err != nil {
+		t.Fatalf("NewResampler failed: %v", err)
+	}
+	r.Add(Sample{TimestampUnixMs: 0, Disks: []DiskUsage{{Mount: "/", UsedPercent: 40}}})
+	r.Add(Sample{TimestampUnixMs: 300, Disks: []DiskUsage{{Mount: "/", UsedPercent: 42}}})
+	r.Add(Sample{TimestampUnixMs: 600}) // the disks could not be read
+	out, _ := r.Flush()
+	if len(out.Disks) != 1 || out.Disks[0].UsedPercent != 42 {
+		t.Errorf("Disks: got %v, want the latest, at 42%%", out.Disks)
+	}
+}
+
+// A bucket is throttled if any reading in it was, and the next starts
+// afresh.
+func TestResamplerThrottled(t *testing.T) {
+	r, err := NewResampler(time.Second, AggMean)
+	if err != nil {
+		t.Fatalf("NewResampler failed: %v", err)
+	}
+	r.Add(Sample{TimestampUnixMs: 0, CpuThrottled: true})
+	r.Add(Sample{TimestampUnixMs: 500})
+	out, ok := r.Add(Sample{TimestampUnixMs: 1000})
+	if !ok || !out.CpuThrottled {
+		t.Errorf("first bucket: got %+v, %v", out, ok)
+	}
+	if out, _ := r.Flush(); out.CpuThrottled {
+		t.Error("second bucket: got throttled")
+	}
+}
+
+func TestResamplerGpus(t *testing.T) {
+	for _, tt := range []struct {
+		agg  Agg
+		want []GpuUsage
+	}{
+		{AggMean, []GpuUsage{{Name: "b", UtilPercent: 60, MemUsedGB: 3, MemTotalGB: 8, TempC: 55}, {Name: "c", UtilPercent: 10}}},
+		{AggMax, []GpuUsage{{Name: "b", UtilPercent: 80, MemUsedGB: 4, MemTotalGB: 8, TempC: 60}, {Name: "c", UtilPercent: 10}}},
+	} {
+		r, err := NewResampler(time.Second, tt.agg)
+		if err != nil {
+			t.Fatalf("NewResampler failed: %v", err)
+		}
+		r.Add(Sample{TimestampUnixMs: 0, Gpus: []GpuUsage{{Name: "a", UtilPercent: 40, MemUsedGB: 2, MemTotalGB: 8, TempC: 50}}})
+		r.Add(Sample{TimestampUnixMs: 500, Gpus: []GpuUsage{{Name: "b", UtilPercent: 80, MemUsedGB: 4, MemTotalGB: 8, TempC: 60}, {Name: "c", UtilPercent: 10}}})
+		out, _ := r.Flush()
+		if !slices.Equal(out.Gpus, tt.want) {
+			t.Errorf("%v: got %v, want %v", tt.agg, out.Gpus, tt.want)
+		}
+	}
+}
+
+func TestParseAgg(t *testing.T) {
+	for _, s := range []string{"mean", "max"} {
+		a, err := ParseAgg(s)
+		if err != nil || a.String() != s {
+			t.Errorf("ParseAgg(%q): got %v, %v", s, a, err)
+		}
+	}
+	if _, err := ParseAgg("median"); err == nil {
+		t.Error("ParseAgg(median): expected error")
+	}
+	if _, err := NewResampler(0, AggMean); err == nil {
+		t.Error("NewResampler(0): expected error")
+	}
+}
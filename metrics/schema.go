@@ -0,0 +1,138 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+// ── Schema ────────────────────────────────────────────────────────────────────
+
+// Schema is the text of proto/metrics.proto, the schema this package
+// encodes by hand: what `infgo schema` prints, and what -log-schema
+// embeds in captures so that tools which have never heard of infgo can
+// decode them.  The tests hold it to the file and to the field numbers in
+// metrics.go.
+const Schema = `// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+// Schema of the records in an .infgo log.  metrics/metrics.go implements the
+// encoding by hand, and metrics/schema.go embeds a copy of this file; the
+// tests fail if the three drift apart.
+syntax = "proto3";
+package metrics;
+
+option go_package = "github.com/ALH477/infgo/metrics";
+
+message Header {
+  string hostname        = 1;
+  string platform        = 2;
+  int64  started_unix_ms = 3;
+  int32  num_cores       = 4;
+  int64  interval_ms     = 5;
+  // The recording host's zone: its IANA name, and its offset east of UTC
+  // in seconds at the start.  Unset where the host's zone is unknown.
+  string timezone        = 6;
+  int32  utc_offset_s    = 7;
+  // How the capture was taken: the recorder's arguments with the values of
+  // secrets redacted, the readings it took, and the configuration file it
+  // read ("none" if it read none).  Unset in older captures.
+  repeated string args        = 8;
+  repeated string collectors  = 9;
+  string          config_path = 10;
+}
+
+// A collector that fails to read CPU, memory or the load averages leaves
+// that group's fields out rather than writing zeros.  Writers before this
+// always set every field, so an unset group means a failed reading.
+message Sample {
+  int64           timestamp_unix_ms = 1;
+  optional double cpu_total         = 2;
+  repeated double cpu_cores         = 3;
+  optional double mem_percent       = 4;
+  optional double mem_used_gb       = 5;
+  optional double mem_total_gb      = 6;
+  optional double load_1            = 7;
+  optional double load_5            = 8;
+  optional double load_15           = 9;
+  // CPU package power since the previous sample, where RAPL can be read.
+  optional double power_watts       = 10;
+  // How long the collector took to read the sample, where it was measured.
+  optional double collect_ms        = 11;
+  // Usage of the -disks filesystems that could be read.
+  repeated DiskUsage disks          = 12;
+  // Swap space in use and in all, and the percentage used; unset where
+  // the machine has none, and in older captures.
+  double swap_used_gb               = 13;
+  double swap_total_gb              = 14;
+  double swap_percent               = 15;
+  // Use of each GPU that could be read, in the driver's order.
+  repeated GpuUsage gpus            = 16;
+  // The CPU time since the previous sample by mode, as a percentage of
+  // all of it; unset where zero or not measured, and in older captures.
+  double cpu_user_percent           = 17;
+  double cpu_system_percent         = 18;
+  double cpu_iowait_percent         = 19;
+  double cpu_steal_percent          = 20;
+  // Memory available without swapping, and the page cache and buffers;
+  // unset where the platform does not report them, and in older captures.
+  double mem_available_gb           = 21;
+  double mem_cached_gb              = 22;
+  double mem_buffers_gb             = 23;
+  // Page faults a second, and the major ones among them; unset where
+  // the platform has no counters, and in older captures.
+  double page_faults_per_sec        = 24;
+  double major_faults_per_sec       = 25;
+  // Set while the CPU is thermally throttled; unset where that cannot be
+  // detected, and in older captures.
+  bool   cpu_throttled              = 26;
+}
+
+message DiskUsage {
+  string mount               = 1;
+  double used_percent        = 2;
+  double used_gb             = 3;
+  double total_gb            = 4;
+  // Unset where the filesystem has no fixed number of inodes.
+  double inodes_used_percent = 5;
+}
+
+message GpuUsage {
+  string name         = 1;
+  double util_percent = 2;
+  double mem_used_gb  = 3;
+  double mem_total_gb = 4;
+  double temp_c       = 5;
+}
+
+message Event {
+  int64  timestamp_unix_ms = 1;
+  string kind              = 2;
+  string message           = 3;
+}
+
+// A reading of one process watched with -pid, in a record of its own.
+message Process {
+  int64  timestamp_unix_ms = 1;
+  int32  pid               = 2;
+  // Tells the process from a later one given the same pid.
+  int64  started_unix_ms   = 3;
+  string name              = 4;
+  // Percent of one core since the previous reading.
+  double cpu_percent       = 5;
+  int64  rss_bytes         = 6;
+  int32  threads           = 7;
+  // Unset where the open file descriptors could not be counted.
+  int32  fds               = 8;
+  // Set on the last record of a process, once it has exited; the
+  // readings are unset.
+  bool   exited            = 9;
+}
+
+// Element type of -with-header pbstream exports.
+message Record {
+  oneof payload {
+    Header  header  = 1;
+    Sample  sample  = 2;
+    Event   event   = 3;
+    Process process = 4;
+  }
+}
+`
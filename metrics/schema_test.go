@@ -0,0 +1,309 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// parseSchema compiles the subset of proto3 that Schema is written in —
+// messages of scalar, optional, repeated and oneof message fields — into a
+// file descriptor, so the tests can check the schema as protobuf-go sees
+// it rather than as text.
+func parseSchema(src string) (protoreflect.FileDescriptor, error) {
+	var toks []string
+	for _, line := range strings.Split(src, "\n") {
+		line, _, _ = strings.Cut(line, "//")
+		for _, p := range "{}=;" {
+			line = strings.ReplaceAll(line, string(p), " "+string(p)+" ")
+		}
+		toks = append(toks, strings.Fields(line)...)
+	}
+	next := func() string {
+		if len(toks) == 0 {
+			return ""
+		}
+		t := toks[0]
+		toks = toks[1:]
+		return t
+	}
+	scalars := map[string]descriptorpb.FieldDescriptorProto_Type{
+		"string": descriptorpb.FieldDescriptorProto_TYPE_STRING,
+		"int64":  descriptorpb.FieldDescriptorProto_TYPE_INT64,
+		"int32":  descriptorpb.FieldDescriptorProto_TYPE_INT32,
+		"double": descriptorpb.FieldDescriptorProto_TYPE_DOUBLE,
+		"bool":   descriptorpb.FieldDescriptorProto_TYPE_BOOL,
+	}
+
+	fdp := &descriptorpb.FileDescriptorProto{Name: proto.String("metrics.proto")}
+	// field parses "[label] type name = number ;" after its first token.
+	field := func(msg *descriptorpb.DescriptorProto, first string) (*descriptorpb.FieldDescriptorProto, error) {
+		f := &descriptorpb.FieldDescriptorProto{Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()}
+		typ := first
+		switch first {
+		case "optional":
+			f.Proto3Optional = proto.Bool(true)
+			typ = next()
+		case "repeated":
+			f.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+			typ = next()
+		}
+		name, eq, num, semi := next(), next(), next(), next()
+		n, err := strconv.Atoi(num)
+		if eq != "=" || semi != ";" || err != nil {
+			return nil, fmt.Errorf("message %s: bad field %q", msg.GetName(), name)
+		}
+		f.Name, f.JsonName, f.Number = proto.String(name), proto.String(name), proto.Int32(int32(n))
+		if st, ok := scalars[typ]; ok {
+			f.Type = st.Enum()
+		} else {
+			f.Type = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+			f.TypeName = proto.String("." + fdp.GetPackage() + "." + typ)
+		}
+		msg.Field = append(msg.Field, f)
+		return f, nil
+	}
+
+	for tok := next(); tok != ""; tok = next() {
+		switch tok {
+		case "syntax":
+			next() // =
+			fdp.Syntax = proto.String(strings.Trim(next(), `"`))
+			next() // ;
+		case "package":
+			fdp.Package = proto.String(next())
+			next()
+		case "option":
+			for next() != ";" {
+			}
+		case "message":
+			msg := &descriptorpb.DescriptorProto{Name: proto.String(next())}
+			next() // {
+			var optional []*descriptorpb.FieldDescriptorProto
+			for t := next(); t != "}"; t = next() {
+				if t == "" {
+					return nil, fmt.Errorf("message %s: unterminated", msg.GetName())
+				}
+				if t == "oneof" {
+					idx := int32(len(msg.OneofDecl))
+					msg.OneofDecl = append(msg.OneofDecl, &descriptorpb.OneofDescriptorProto{Name: proto.String(next())})
+					next() // {
+					for t := next(); t != "}"; t = next() {
+						f, err := field(msg, t)
+						if err != nil {
+							return nil, err
+						}
+						f.OneofIndex = proto.Int32(idx)
+					}
+					continue
+				}
+				f, err := field(msg, t)
+				if err != nil {
+					return nil, err
+				}
+				if f.GetProto3Optional() {
+					optional = append(optional, f)
+				}
+			}
+			// Each proto3 optional field sits alone in a synthetic oneof,
+			// declared after the real ones.
+			for _, f := range optional {
+				f.OneofIndex = proto.Int32(int32(len(msg.OneofDecl)))
+				msg.OneofDecl = append(msg.OneofDecl, &descriptorpb.OneofDescriptorProto{Name: proto.String("_" + f.GetName())})
+			}
+			fdp.MessageType = append(fdp.MessageType, msg)
+		default:
+			return nil, fmt.Errorf("unexpected %q", tok)
+		}
+	}
+	return protodesc.NewFile(fdp, nil)
+}
+
+func TestSchemaMatchesProtoFile(t *testing.T) {
+	file, err := os.ReadFile("../proto/metrics.proto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(file) != Schema {
+		t.Error("Schema differs from proto/metrics.proto; copy the file into schema.go")
+	}
+}
+
+// The field numbers of the schema must be the ones the hand-rolled
+// encoding writes, field for field, with none missing on either side.
+func TestSchemaFieldNumbers(t *testing.T) {
+	fd, err := parseSchema(Schema)
+	if err != nil {
+		t.Fatalf("parse schema: %v", err)
+	}
+	want := map[protoreflect.Name]map[protoreflect.Name]protowire.Number{
+		"Header": {
+			"hostname": hfHostname, "platform": hfPlatform, "started_unix_ms": hfStartedUnixMs,
+			"num_cores": hfNumCores, "interval_ms": hfIntervalMs,
+			"timezone": hfTimezone, "utc_offset_s": hfUTCOffsetS,
+			"args": hfArgs, "collectors": hfCollectors, "config_path": hfConfigPath,
+		},
+		"Sample": {
+			"timestamp_unix_ms": sfTimestampUnixMs, "cpu_total": sfCpuTotal, "cpu_cores": sfCpuCores,
+			"mem_percent": sfMemPercent, "mem_used_gb": sfMemUsedGB, "mem_total_gb": sfMemTotalGB,
+			"load_1": sfLoad1, "load_5": sfLoad5, "load_15": sfLoad15,
+			"power_watts": sfPowerWatts, "collect_ms": sfCollectMs, "disks": sfDisks,
+			"swap_used_gb": sfSwapUsedGB, "swap_total_gb": sfSwapTotalGB, "swap_percent": sfSwapPercent,
+			"gpus": sfGpus, "cpu_user_percent": sfCpuUserPercent, "cpu_system_percent": sfCpuSystemPercent,
+			"cpu_iowait_percent": sfCpuIowaitPercent, "cpu_steal_percent": sfCpuStealPercent,
+			"mem_available_gb": sfMemAvailableGB, "mem_cached_gb": sfMemCachedGB, "mem_buffers_gb": sfMemBuffersGB,
+			"page_faults_per_sec": sfPageFaultsPerSec, "major_faults_per_sec": sfMajorFaultsPerSec,
+			"cpu_throttled": sfCpuThrottled,
+		},
+		"DiskUsage": {
+			"mount": dfMount, "used_percent": dfUsedPercent, "used_gb": dfUsedGB, "total_gb": dfTotalGB,
+			"inodes_used_percent": dfInodesUsedPercent,
+		},
+		"GpuUsage": {
+			"name": gfName, "util_percent": gfUtilPercent, "mem_used_gb": gfMemUsedGB,
+			"mem_total_gb": gfMemTotalGB, "temp_c": gfTempC,
+		},
+		"Event": {
+			"timestamp_unix_ms": efTimestampUnixMs, "kind": efKind, "message": efMessage,
+		},
+		"Process": {
+			"timestamp_unix_ms": pfTimestampUnixMs, "pid": pfPid, "started_unix_ms": pfStartedUnixMs,
+			"name": pfName, "cpu_percent": pfCpuPercent, "rss_bytes": pfRssBytes,
+			"threads": pfThreads, "fds": pfFds, "exited": pfExited,
+		},
+		"Record": {"header": 1, "sample": 2, "event": 3, "process": 4},
+	}
+	msgs := fd.Messages()
+	if msgs.Len() != len(want) {
+		t.Errorf("got %d messages, want %d", msgs.Len(), len(want))
+	}
+	for name, fields := range want {
+		md := msgs.ByName(name)
+		if md == nil {
+			t.Errorf("message %s missing from the schema", name)
+			continue
+		}
+		if md.Fields().Len() != len(fields) {
+			t.Errorf("%s: got %d fields, want %d", name, md.Fields().Len(), len(fields))
+		}
+		for fname, num := range fields {
+			f := md.Fields().ByName(fname)
+			if f == nil {
+				t.Errorf("%s.%s missing from the schema", name, fname)
+			} else if f.Number() != num {
+				t.Errorf("%s.%s: got field %d, want %d", name, fname, f.Number(), num)
+			}
+		}
+	}
+}
+
+// A sample encoded as the logger writes it decodes with stock protobuf-go
+// from nothing but the schema, leaving no field it does not know.
+func TestSchemaDecodesSample(t *testing.T) {
+	fd, err := parseSchema(Schema)
+	if err != nil {
+		t.Fatalf("parse schema: %v", err)
+	}
+	watts, took := 17.25, 3.5
+	s := Sample{
+		TimestampUnixMs:   1704067200000,
+		CpuTotal:          42.5,
+		CpuCores:          []float64{31.2, 52.4},
+		MemPercent:        61.8,
+		MemUsedGB:         9.88,
+		MemTotalGB:        15.99,
+		Load1:             2.41,
+		Load5:             1.89,
+		Load15:            1.42,
+		PowerWatts:        &watts,
+		CollectMs:         &took,
+		Disks:             []DiskUsage{{Mount: "/", UsedPercent: 61.5, UsedGB: 123, TotalGB: 200}},
+		SwapUsedGB:        0.5,
+		SwapTotalGB:       4,
+		SwapPercent:       12.5,
+		Gpus:              []GpuUsage{{Name: "NVIDIA A100", UtilPercent: 87, MemUsedGB: 30.5, MemTotalGB: 40, TempC: 64}},
+		CpuUserPercent:    34,
+		CpuSystemPercent:  12,
+		CpuIowaitPercent:  5,
+		CpuStealPercent:   0.5,
+		MemAvailableGB:    9.1,
+		MemCachedGB:       4.2,
+		MemBuffersGB:      0.3,
+		PageFaultsPerSec:  120,
+		MajorFaultsPerSec: 4,
+		CpuThrottled:      true,
+	}
+	md := fd.Messages().ByName("Sample")
+	msg := dynamicpb.NewMessage(md)
+	if err := proto.Unmarshal(s.MarshalAppend(nil), msg); err != nil {
+		t.Fatalf("proto.Unmarshal: %v", err)
+	}
+	if n := len(msg.GetUnknown()); n > 0 {
+		t.Errorf("%d bytes of fields the schema does not declare", n)
+	}
+	get := func(name protoreflect.Name) protoreflect.Value { return msg.Get(md.Fields().ByName(name)) }
+	if got := get("timestamp_unix_ms").Int(); got != s.TimestampUnixMs {
+		t.Errorf("timestamp_unix_ms: got %d, want %d", got, s.TimestampUnixMs)
+	}
+	doubles := []struct {
+		name protoreflect.Name
+		want float64
+	}{
+		{"cpu_total", s.CpuTotal}, {"mem_percent", s.MemPercent}, {"mem_used_gb", s.MemUsedGB},
+		{"mem_total_gb", s.MemTotalGB}, {"load_1", s.Load1}, {"load_5", s.Load5}, {"load_15", s.Load15},
+		{"power_watts", watts}, {"collect_ms", took},
+		{"swap_used_gb", s.SwapUsedGB}, {"swap_total_gb", s.SwapTotalGB}, {"swap_percent", s.SwapPercent},
+		{"cpu_user_percent", s.CpuUserPercent}, {"cpu_system_percent", s.CpuSystemPercent},
+		{"cpu_iowait_percent", s.CpuIowaitPercent}, {"cpu_steal_percent", s.CpuStealPercent},
+		{"mem_available_gb", s.MemAvailableGB}, {"mem_cached_gb", s.MemCachedGB}, {"mem_buffers_gb", s.MemBuffersGB},
+		{"page_faults_per_sec", s.PageFaultsPerSec}, {"major_faults_per_sec", s.MajorFaultsPerSec},
+	}
+	for _, d := range doubles {
+		if got := get(d.name).Float(); got != d.want {
+			t.Errorf("%s: got %v, want %v", d.name, got, d.want)
+		}
+	}
+	if !get("cpu_throttled").Bool() {
+		t.Error("cpu_throttled: got false")
+	}
+	cores := get("cpu_cores").List()
+	if cores.Len() != len(s.CpuCores) {
+		t.Fatalf("cpu_cores: got %d values, want %d", cores.Len(), len(s.CpuCores))
+	}
+	for i, want := range s.CpuCores {
+		if got := cores.Get(i).Float(); got != want {
+			t.Errorf("cpu_cores[%d]: got %v, want %v", i, got, want)
+		}
+	}
+	disks := get("disks").List()
+	if disks.Len() != 1 {
+		t.Fatalf("disks: got %d, want 1", disks.Len())
+	}
+	dm := disks.Get(0).Message()
+	dget := func(name protoreflect.Name) protoreflect.Value { return dm.Get(dm.Descriptor().Fields().ByName(name)) }
+	if dget("mount").String() != "/" || dget("used_percent").Float() != 61.5 || dget("used_gb").Float() != 123 || dget("total_gb").Float() != 200 {
+		t.Errorf("disks[0]: got %v", dm)
+	}
+	gpus := get("gpus").List()
+	if gpus.Len() != 1 {
+		t.Fatalf("gpus: got %d, want 1", gpus.Len())
+	}
+	gm := gpus.Get(0).Message()
+	gget := func(name protoreflect.Name) protoreflect.Value { return gm.Get(gm.Descriptor().Fields().ByName(name)) }
+	if gget("name").String() != "NVIDIA A100" || gget("util_percent").Float() != 87 || gget("mem_used_gb").Float() != 30.5 ||
+		gget("mem_total_gb").Float() != 40 || gget("temp_c").Float() != 64 {
+		t.Errorf("gpus[0]: got %v", gm)
+	}
+}
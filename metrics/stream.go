@@ -0,0 +1,158 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// ── Length-delimited streams ──────────────────────────────────────────────────
+//
+// The .infgo framing ([type][uint32 length][payload]) is specific to infgo.
+// For interchange with stock protobuf tooling the helpers below produce the
+// conventional varint-length-delimited stream instead — the format of Java's
+// writeDelimitedTo and Go's protodelim package.
+
+// maxDelimitedBytes caps a single message read by ReadDelimited so a corrupt
+// length prefix cannot trigger an unbounded allocation.
+const maxDelimitedBytes = 10 * 1024 * 1024 // 10 MiB
+
+// WriteDelimited writes msg preceded by its length as a protobuf varint.
+func WriteDelimited(w io.Writer, msg []byte) error {
+	b := protowire.AppendVarint(make([]byte, 0, binary.MaxVarintLen64+len(msg)), uint64(len(msg)))
+	_, err := w.Write(append(b, msg...))
+	return err
+}
+
+// ReadDelimited reads one varint-length-prefixed message.  It returns io.EOF
+// only at a clean message boundary; a stream that ends mid-message yields
+// io.ErrUnexpectedEOF.
+func ReadDelimited(r *bufio.Reader) ([]byte, error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	if n > maxDelimitedBytes {
+		return nil, fmt.Errorf("delimited message too large (%d bytes); possible corruption", n)
+	}
+	msg := make([]byte, n)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return msg, nil
+}
+
+// ── Envelope ──────────────────────────────────────────────────────────────────
+
+// Envelope field numbers; see Envelope.
+const (
+	rfHeader  protowire.Number = 1
+	rfSample  protowire.Number = 2
+	rfEvent   protowire.Number = 3
+	rfProcess protowire.Number = 4
+)
+
+// Envelope wraps exactly one Header, Sample, Event or Process so that a
+// single delimited stream can carry them all.  It corresponds to
+//
+//	message Record {
+//	  oneof payload {
+//	    Header  header  = 1;
+//	    Sample  sample  = 2;
+//	    Event   event   = 3;
+//	    Process process = 4;
+//	  }
+//	}
+type Envelope struct {
+	Header  *Header
+	Sample  *Sample
+	Event   *Event
+	Process *Process
+}
+
+// Marshal serialises the one non-nil member of e as a length-delimited
+// sub-message field.
+func (e *Envelope) Marshal() []byte {
+	var b []byte
+	switch {
+	case e.Header != nil:
+		b = protowire.AppendTag(b, rfHeader, protowire.BytesType)
+		b = protowire.AppendBytes(b, e.Header.Marshal())
+	case e.Sample != nil:
+		b = protowire.AppendTag(b, rfSample, protowire.BytesType)
+		b = protowire.AppendBytes(b, e.Sample.Marshal())
+	case e.Event != nil:
+		b = protowire.AppendTag(b, rfEvent, protowire.BytesType)
+		b = protowire.AppendBytes(b, e.Event.Marshal())
+	case e.Process != nil:
+		b = protowire.AppendTag(b, rfProcess, protowire.BytesType)
+		b = protowire.AppendBytes(b, e.Process.Marshal())
+	}
+	return b
+}
+
+// UnmarshalEnvelope deserialises an Envelope.  As with a protobuf oneof, the
+// last member present wins; an envelope with no known member is returned
+// empty rather than as an error, for forward compatibility.
+func UnmarshalEnvelope(b []byte) (Envelope, error) {
+	var e Envelope
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return e, fmt.Errorf("envelope: consume tag: %w", protowire.ParseError(n))
+		}
+		b = b[n:]
+
+		if typ != protowire.BytesType || num < rfHeader || num > rfProcess {
+			n := protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				return e, fmt.Errorf("envelope: skip unknown field %d: %w", num, protowire.ParseError(n))
+			}
+			b = b[n:]
+			continue
+		}
+		v, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return e, fmt.Errorf("envelope: field %d: %w", num, protowire.ParseError(n))
+		}
+		b = b[n:]
+
+		e = Envelope{}
+		switch num {
+		case rfHeader:
+			h, err := UnmarshalHeader(v)
+			if err != nil {
+				return e, fmt.Errorf("envelope: %w", err)
+			}
+			e.Header = &h
+		case rfSample:
+			s, err := UnmarshalSample(v)
+			if err != nil {
+				return e, fmt.Errorf("envelope: %w", err)
+			}
+			e.Sample = &s
+		case rfEvent:
+			ev, err := UnmarshalEvent(v)
+			if err != nil {
+				return e, fmt.Errorf("envelope: %w", err)
+			}
+			e.Event = &ev
+		case rfProcess:
+			p, err := UnmarshalProcess(v)
+			if err != nil {
+				return e, fmt.Errorf("envelope: %w", err)
+			}
+			e.Process = &p
+		}
+	}
+	return e, nil
+}
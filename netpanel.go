@@ -0,0 +1,78 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	psnet "github.com/shirou/gopsutil/v3/net"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+// ── NET panel ─────────────────────────────────────────────────────────────────
+//
+// The bytes received and sent a second by the network interfaces that are
+// up, from the deltas of their counters between readings.  The panel shows
+// their sum, or with n each interface in turn.  An interface that goes
+// away is forgotten, and has a rate again from the second reading after it
+// is back: its counters may have started again from zero, and a delta
+// across the gap would not be its rate anyway.
+//
+// Under the rates, the packets in error and dropped a second by all the
+// interfaces up, dim at zero and red once they have been above it for
+// two readings in a row, since one bad packet is noise and a steady trickle
+// is a cable or a full ring buffer.  Their counts since infgo started stay
+// beside them, so a burst between glances is not lost.
+
+// netVirtual are the prefixes of the interfaces that container runtimes
+// and hypervisors make, whose traffic the physical ones carry as well.
+var netVirtual = []string{"veth", "docker", "br-", "virbr"}
+
+// netNICs picks the interfaces that are up, and not loopback or
+// netVirtual, from the counters of each, sorted by name.
+func netNICs(counters []psnet.IOCountersStat, ifaces psnet.InterfaceStatList) []ui.NetCounter {
+	up := map[string]bool{}
+	for _, i := range ifaces {
+		up[i.Name] = slices.Contains(i.Flags, "up") && !slices.Contains(i.Flags, "loopback")
+	}
+	out := []ui.NetCounter{}
+	for _, c := range counters {
+		if !up[c.Name] || slices.ContainsFunc(netVirtual, func(p string) bool { return strings.HasPrefix(c.Name, p) }) {
+			continue
+		}
+		out = append(out, ui.NetCounter{Name: c.Name, Rx: c.BytesRecv, Tx: c.BytesSent,
+			Errs: c.Errin + c.Errout, Drops: c.Dropin + c.Dropout})
+	}
+	slices.SortFunc(out, func(a, b ui.NetCounter) int { return cmp.Compare(a.Name, b.Name) })
+	return out
+}
+
+// readNet reads the interfaces' counters into msg, if the NET panel is
+// shown and the subsystem is due.
+func (r *statsReader) readNet(ctx context.Context, msg *ui.Reading) {
+	if !r.netOn || !r.net.due(r.now()) {
+		return
+	}
+	nics, err := query(ctx, &r.net, r.timeout, func(ctx context.Context) ([]ui.NetCounter, error) {
+		counters, err := r.src.nics(ctx, true)
+		if err != nil {
+			return nil, err
+		}
+		ifaces, err := r.src.ifaces(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return netNICs(counters, ifaces), nil
+	})
+	if err != nil {
+		r.net.failed(r.now())
+		return
+	}
+	r.net.recovered()
+	msg.NICs, msg.NetAt = nics, r.now()
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	psnet "github.com/shirou/gopsutil/v3/net"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+func TestNetNICs(t *testing.T) {
+	counters := []psnet.IOCountersStat{
+		{Name: "wlan0", BytesRecv: 1}, {Name: "lo"}, {Name: "eth0"}, {Name: "eth1"},
+		{Name: "docker0"}, {Name: "veth12ab"},
+	}
+	ifaces := psnet.InterfaceStatList{
+		{Name: "lo", Flags: []string{"up", "loopback"}},
+		{Name: "eth0", Flags: []string{"up", "broadcast"}},
+		{Name: "eth1", Flags: []string{"broadcast"}}, // down
+		{Name: "wlan0", Flags: []string{"up"}},
+		{Name: "docker0", Flags: []string{"up"}},
+		{Name: "veth12ab", Flags: []string{"up"}},
+	}
+	var got []string
+	for _, c := range netNICs(counters, ifaces) {
+		got = append(got, c.Name)
+	}
+	if want := []string{"eth0", "wlan0"}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestReadNet(t *testing.T) {
+	var f fakeSources
+	now := time.Unix(1700000000, 0)
+	r := fakeReader(&f, &now)
+	r.src.nics = func(context.Context, bool) ([]psnet.IOCountersStat, error) {
+		return []psnet.IOCountersStat{{Name: "eth0", BytesRecv: 7, BytesSent: 3}}, nil
+	}
+	r.src.ifaces = func(context.Context) (psnet.InterfaceStatList, error) {
+		return psnet.InterfaceStatList{{Name: "eth0", Flags: []string{"up"}}}, nil
+	}
+	if msg := r.read(context.Background()); msg.NICs != nil {
+		t.Errorf("without the panel: got %v", msg.NICs)
+	}
+	r.netOn = true
+	if msg := r.read(context.Background()); !slices.Equal(msg.NICs, []ui.NetCounter{{Name: "eth0", Rx: 7, Tx: 3}}) || !msg.NetAt.Equal(now) {
+		t.Errorf("got %v at %v", msg.NICs, msg.NetAt)
+	}
+}
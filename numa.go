@@ -0,0 +1,144 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+// ── NUMA nodes ────────────────────────────────────────────────────────────────
+//
+// On a machine with more than one NUMA node, as a dual-socket server, one
+// node can be full while the other is empty, which a single memory bar
+// hides.  There the MEMORY panel has a row for each node, read from
+// /sys/devices/system/node on the stats tick in a command of its own.  The
+// nodes are listed again on every reading, so one taken offline drops out
+// and one brought online appears; a reading that fails leaves the rows of
+// the last.  A machine with one node shows nothing.
+
+// numaTimeout bounds a reading of the nodes.
+const numaTimeout = 2 * time.Second
+
+// errNoMemTotal is a node meminfo without its MemTotal line.
+var errNoMemTotal = errors.New("no MemTotal line")
+
+// parseNodeMeminfo reads a node's meminfo, lines like
+//
+//	Node 0 MemTotal:       65842012 kB
+//
+// into its memory used and in all.  The used leaves out the page cache
+// and the reclaimable slab, as the MEMORY panel's does.
+func parseNodeMeminfo(r io.Reader) (used, total float64, err error) {
+	kb := map[string]uint64{}
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		f := strings.Fields(sc.Text())
+		if len(f) < 4 || f[0] != "Node" {
+			continue
+		}
+		key := strings.TrimSuffix(f[2], ":")
+		switch key {
+		case "MemTotal", "MemFree", "FilePages", "SReclaimable":
+			v, err := strconv.ParseUint(f[3], 10, 64)
+			if err != nil {
+				return 0, 0, fmt.Errorf("%s: %w", key, err)
+			}
+			kb[key] = v
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return 0, 0, err
+	}
+	t, ok := kb["MemTotal"]
+	if !ok {
+		return 0, 0, errNoMemTotal
+	}
+	free := kb["MemFree"] + kb["FilePages"] + kb["SReclaimable"]
+	u := t - min(free, t)
+	return float64(u) / (1 << 20), float64(t) / (1 << 20), nil
+}
+
+// readNUMA reads the nodes under fsys, a /sys/devices/system/node, in the
+// order of their ids.  A node gone between the listing and its reading is
+// left out.
+func readNUMA(fsys fs.FS) ([]ui.NUMANode, error) {
+	dirs, err := fs.Glob(fsys, "node[0-9]*")
+	if err != nil {
+		return nil, err
+	}
+	var nodes []ui.NUMANode
+	for _, dir := range dirs {
+		id, err := strconv.Atoi(strings.TrimPrefix(dir, "node"))
+		if err != nil {
+			continue
+		}
+		f, err := fsys.Open(dir + "/meminfo")
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		used, total, err := parseNodeMeminfo(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s/meminfo: %w", dir, err)
+		}
+		nodes = append(nodes, ui.NUMANode{ID: id, UsedGB: used, TotalGB: total})
+	}
+	slices.SortFunc(nodes, func(a, b ui.NUMANode) int { return cmp.Compare(a.ID, b.ID) })
+	return nodes, nil
+}
+
+// numaWatch reads the nodes.
+type numaWatch struct {
+	fsys fs.FS
+	sub  subsystem
+	now  func() time.Time
+}
+
+// detectNUMA returns a numaWatch of the nodes under fsys if there are
+// more than one, and nil if not; fsys is nil where there are none to read.
+func detectNUMA(fsys fs.FS) *numaWatch {
+	if fsys == nil {
+		return nil
+	}
+	if nodes, err := readNUMA(fsys); err != nil || len(nodes) < 2 {
+		return nil
+	}
+	return &numaWatch{fsys: fsys, now: time.Now}
+}
+
+// readCmd reads the nodes off the Update goroutine, or returns nil while
+// the last reading runs or a failure is backed off.
+func (w *numaWatch) readCmd(ctx context.Context) tea.Cmd {
+	if !w.sub.due(w.now()) || w.sub.busy.Load() {
+		return nil
+	}
+	return func() tea.Msg {
+		nodes, err := query(ctx, &w.sub, numaTimeout, func(context.Context) ([]ui.NUMANode, error) {
+			return readNUMA(w.fsys)
+		})
+		if err != nil {
+			w.sub.failed(w.now())
+			return ui.NUMAMsg{}
+		}
+		w.sub.recovered()
+		return ui.NUMAMsg{Nodes: nodes}
+	}
+}
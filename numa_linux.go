@@ -0,0 +1,14 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+//go:build linux
+
+package main
+
+import (
+	"io/fs"
+	"os"
+)
+
+// numaFS is where the NUMA nodes are listed.
+func numaFS() fs.FS { return os.DirFS("/sys/devices/system/node") }
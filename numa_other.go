@@ -0,0 +1,14 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+//go:build !linux
+
+package main
+
+import (
+	"io/fs"
+)
+
+// numaFS is nil here: the nodes are read from Linux's sysfs only, and the
+// MEMORY panel has no rows for them.
+func numaFS() fs.FS { return nil }
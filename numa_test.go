@@ -0,0 +1,63 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+func TestParseNodeMeminfo(t *testing.T) {
+	f, err := os.Open("testdata/numa/node0/meminfo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if used, total, err := parseNodeMeminfo(f); err != nil || used != 60 || total != 64 {
+		t.Errorf("got %v of %v GiB, %v", used, total, err)
+	}
+	for _, bad := range []string{"", "Node 0 MemFree:  12 kB\n", "Node 0 MemTotal:  lots kB\n"} {
+		if used, total, err := parseNodeMeminfo(strings.NewReader(bad)); err == nil {
+			t.Errorf("%q: got %v of %v", bad, used, total)
+		}
+	}
+}
+
+// The nodes are read in the order of their ids; one without a meminfo, as
+// one going offline, is left out, and a machine of one node has no watch.
+func TestReadNUMA(t *testing.T) {
+	nodes, err := readNUMA(os.DirFS("testdata/numa"))
+	want := []ui.NUMANode{{ID: 0, UsedGB: 60, TotalGB: 64}, {ID: 1, UsedGB: 4, TotalGB: 64}}
+	if err != nil || !slices.Equal(nodes, want) {
+		t.Errorf("got %+v, %v", nodes, err)
+	}
+	if w := detectNUMA(os.DirFS("testdata/numa-one")); w != nil {
+		t.Error("a watch of one node")
+	}
+	if w := detectNUMA(nil); w != nil {
+		t.Error("a watch of no nodes")
+	}
+
+	// A node brought online since appears on the next reading.
+	meminfo, err := os.ReadFile("testdata/numa/node1/meminfo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fsys := fstest.MapFS{"node0/meminfo": {Data: meminfo}, "node1/meminfo": {Data: meminfo}}
+	w := detectNUMA(fsys)
+	if w == nil {
+		t.Fatal("no watch of two nodes")
+	}
+	fsys["node10/meminfo"] = &fstest.MapFile{Data: meminfo}
+	msg := w.readCmd(context.Background())().(ui.NUMAMsg)
+	if len(msg.Nodes) != 3 || msg.Nodes[2].ID != 10 {
+		t.Errorf("got %+v", msg.Nodes)
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+// ── Raspberry Pi ──────────────────────────────────────────────────────────────
+
+// On a Raspberry Pi the firmware reports whether the supply has sagged
+// below 4.63 V and whether the SoC is being slowed down to protect it:
+// the first thing to look at when a Pi misbehaves.  infgo reads the flags
+// from sysfs, or from `vcgencmd get_throttled` on kernels without the
+// file, shows them in the SYSTEM panel and logs a "throttle" event each
+// time one is raised or cleared.
+
+const (
+	piModelPath     = "proc/device-tree/model"
+	piThrottledPath = "sys/devices/platform/soc/soc:firmware/get_throttled"
+	piTempPath      = "sys/class/thermal/thermal_zone0/temp"
+
+	// piInterval is the time between readings of the flags.
+	piInterval = time.Second
+)
+
+// parseThrottled parses get_throttled as the sysfs file ("50005") or
+// vcgencmd ("throttled=0x50005") prints it.
+func parseThrottled(s string) (ui.PiFlags, error) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(s, "throttled=")
+	s = strings.TrimPrefix(strings.ToLower(s), "0x")
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("get_throttled: %q is not a hex word", s)
+	}
+	return ui.PiFlags(v), nil
+}
+
+// piWatch reads the flags of the Pi whose root filesystem is at root.
+type piWatch struct {
+	root     string
+	model    string
+	vcgencmd func(ctx context.Context) (string, error)
+}
+
+// detectPi returns a piWatch if the device tree under root names a
+// Raspberry Pi, and nil on any other machine.
+func detectPi(root string) *piWatch {
+	b, err := os.ReadFile(filepath.Join(root, piModelPath))
+	if err != nil {
+		return nil
+	}
+	model := string(bytes.TrimRight(b, "\x00\n"))
+	if !strings.HasPrefix(model, "Raspberry Pi") {
+		return nil
+	}
+	return &piWatch{root: root, model: model, vcgencmd: runVcgencmd}
+}
+
+func runVcgencmd(ctx context.Context) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "vcgencmd", "get_throttled").Output()
+	return string(out), err
+}
+
+// read takes a reading.  It fails only if nothing at all could be read.
+func (w *piWatch) read(ctx context.Context) (ui.PiStatus, error) {
+	var st ui.PiStatus
+	b, err := os.ReadFile(filepath.Join(w.root, piThrottledPath))
+	if errors.Is(err, os.ErrNotExist) && w.vcgencmd != nil {
+		var out string
+		out, err = w.vcgencmd(ctx)
+		b = []byte(out)
+	}
+	if err == nil {
+		st.Flags, err = parseThrottled(string(b))
+		st.HasFlags = err == nil
+	}
+	if t, terr := os.ReadFile(filepath.Join(w.root, piTempPath)); terr == nil {
+		if milli, perr := strconv.Atoi(strings.TrimSpace(string(t))); perr == nil {
+			st.TempC, st.HasTemp = float64(milli)/1000, true
+		}
+	}
+	if !st.HasFlags && !st.HasTemp {
+		return st, err
+	}
+	return st, nil
+}
+
+type piTickMsg time.Time
+
+func piTick() tea.Cmd {
+	return tea.Tick(piInterval, func(t time.Time) tea.Msg { return piTickMsg(t) })
+}
+
+// readCmd takes a reading off the Update goroutine.  A failed one is
+// dropped: the status row keeps the last.
+func (w *piWatch) readCmd(ctx context.Context) tea.Cmd {
+	return func() tea.Msg {
+		st, err := w.read(ctx)
+		if err != nil {
+			return nil
+		}
+		return ui.PiMsg{Status: st, At: time.Now()}
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+// piRoot lays out files, by path under the root, as a Pi's sysfs and
+// device tree would have them.
+func piRoot(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for path, body := range files {
+		path = filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestParseThrottled(t *testing.T) {
+	tests := []struct {
+		in   string
+		want ui.PiFlags
+	}{
+		// From vcgencmd get_throttled.
+		{"throttled=0x0\n", 0},
+		{"throttled=0x50005\n", 0x50005},
+		{"throttled=0x50000\n", 0x50000},
+		{"throttled=0xE0000\n", 0xE0000},
+		// From sysfs, which leaves out the 0x.
+		{"50005\n", 0x50005},
+		{"80008\n", 0x80008},
+		{"0\n", 0},
+	}
+	for _, tt := range tests {
+		got, err := parseThrottled(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("parseThrottled(%q): got %#x, %v, want %#x", tt.in, got, err, tt.want)
+		}
+	}
+	for _, bad := range []string{"", "throttled=", "throttled=zz", "VCHI initialization failed"} {
+		if _, err := parseThrottled(bad); err == nil {
+			t.Errorf("parseThrottled(%q): got no error", bad)
+		}
+	}
+}
+
+func TestDetectPi(t *testing.T) {
+	pi := detectPi(piRoot(t, map[string]string{piModelPath: "Raspberry Pi 4 Model B Rev 1.4\x00"}))
+	if pi == nil || pi.model != "Raspberry Pi 4 Model B Rev 1.4" {
+		t.Errorf("a Pi: got %+v", pi)
+	}
+	if pi := detectPi(piRoot(t, map[string]string{piModelPath: "Pine64 RockPro64 v2.1\x00"})); pi != nil {
+		t.Errorf("another board: got %+v, want nil", pi)
+	}
+	if pi := detectPi(t.TempDir()); pi != nil {
+		t.Errorf("no device tree: got %+v, want nil", pi)
+	}
+}
+
+func TestPiRead(t *testing.T) {
+	model := "Raspberry Pi 5 Model B Rev 1.0\x00"
+	pi := detectPi(piRoot(t, map[string]string{
+		piModelPath:     model,
+		piThrottledPath: "50005\n",
+		piTempPath:      "52616\n",
+	}))
+	pi.vcgencmd = func(context.Context) (string, error) {
+		t.Error("vcgencmd run although sysfs has the flags")
+		return "", nil
+	}
+	st, err := pi.read(context.Background())
+	if err != nil || !st.HasFlags || st.Flags != 0x50005 || !st.HasTemp || st.TempC != 52.616 {
+		t.Errorf("sysfs: got %+v, %v", st, err)
+	}
+
+	// Older kernels have no get_throttled file; vcgencmd reads it instead.
+	pi = detectPi(piRoot(t, map[string]string{piModelPath: model}))
+	pi.vcgencmd = func(context.Context) (string, error) { return "throttled=0x20002\n", nil }
+	st, err = pi.read(context.Background())
+	if err != nil || !st.HasFlags || st.Flags != 0x20002 || st.HasTemp {
+		t.Errorf("vcgencmd: got %+v, %v", st, err)
+	}
+
+	pi.vcgencmd = func(context.Context) (string, error) { return "", errors.New("not found") }
+	if _, err := pi.read(context.Background()); err == nil {
+		t.Error("nothing readable: got no error")
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shirou/gopsutil/v3/process"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+// ── Watched processes ─────────────────────────────────────────────────────────
+//
+// -pid, once for each process, adds a PID panel following them: CPU,
+// resident memory, threads and open descriptors, read on the stats tick in
+// a command of its own, with a sparkline of the CPU.  A process is known
+// by its pid and start time together, so one that exits, or whose pid has
+// gone to another, shows "exited" in red with its history frozen and is
+// not read again.  With -log-pids each reading also goes to the -log
+// capture as a Process record, which infgo analyze reports on.
+
+// parsePid parses a -pid value.
+func parsePid(v string) (int32, error) {
+	pid, err := strconv.ParseInt(v, 10, 32)
+	if err != nil || pid <= 0 {
+		return 0, fmt.Errorf("%q is not a process id", v)
+	}
+	return int32(pid), nil
+}
+
+// readPid reads the process pid, or returns ErrPIDGone if there is none.
+func readPid(ctx context.Context, pid int32) (ui.PIDReading, error) {
+	p, err := process.NewProcessWithContext(ctx, pid)
+	if err != nil {
+		return ui.PIDReading{}, pidErr(err)
+	}
+	var r ui.PIDReading
+	if r.Create, err = p.CreateTimeWithContext(ctx); err != nil {
+		return r, pidErr(err)
+	}
+	t, err := p.TimesWithContext(ctx)
+	if err != nil {
+		return r, pidErr(err)
+	}
+	mem, err := p.MemoryInfoWithContext(ctx)
+	if err != nil {
+		return r, pidErr(err)
+	}
+	r.CPUSecs, r.RSS = t.User+t.System, mem.RSS
+	r.Name, _ = p.NameWithContext(ctx)
+	r.Threads, _ = p.NumThreadsWithContext(ctx)
+	r.FDs, _ = p.NumFDsWithContext(ctx)
+	return r, nil
+}
+
+// pidErr is ErrPIDGone for an error that says the process is not there,
+// as gopsutil reports it or as /proc does once it has exited between two
+// reads; any other error, a timeout or EACCES, is returned as it is.
+func pidErr(err error) error {
+	if errors.Is(err, process.ErrorProcessNotRunning) || errors.Is(err, fs.ErrNotExist) || errors.Is(err, syscall.ESRCH) {
+		return ui.ErrPIDGone
+	}
+	return err
+}
+
+// pidWatcher reads the watched processes, one set of readings at a time,
+// off the Update goroutine.  A process that has exited, or whose pid has
+// been taken by another since it was first read, is not read again.
+type pidWatcher struct {
+	read   func(context.Context, int32) (ui.PIDReading, error)
+	busy   atomic.Bool
+	pids   []int32
+	create []int64 // of each first reading; the pid is zeroed once it exits
+}
+
+// watch reads pid for the first time, which it returns.
+func (pw *pidWatcher) watch(ctx context.Context, pid int32) (ui.PIDReading, error) {
+	r, err := pw.read(ctx, pid)
+	if err != nil {
+		return r, fmt.Errorf("-pid %d: %w", pid, err)
+	}
+	pw.pids, pw.create = append(pw.pids, pid), append(pw.create, r.Create)
+	return r, nil
+}
+
+// readCmd reads the processes that have not exited, or returns nil while
+// the last reading runs.
+func (pw *pidWatcher) readCmd(ctx context.Context) tea.Cmd {
+	if len(pw.pids) == 0 || !pw.busy.CompareAndSwap(false, true) {
+		return nil
+	}
+	return func() tea.Msg {
+		defer pw.busy.Store(false)
+		msg := ui.PIDsMsg{At: time.Now(), Readings: make([]ui.PIDReading, len(pw.pids)), Errs: make([]error, len(pw.pids))}
+		for i, pid := range pw.pids {
+			if pid == 0 {
+				msg.Errs[i] = ui.ErrPIDGone
+				continue
+			}
+			msg.Readings[i], msg.Errs[i] = pw.read(ctx, pid)
+			if errors.Is(msg.Errs[i], ui.ErrPIDGone) || msg.Errs[i] == nil && msg.Readings[i].Create != pw.create[i] {
+				pw.pids[i] = 0
+			}
+		}
+		return msg
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/shirou/gopsutil/v3/process"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+func TestParsePid(t *testing.T) {
+	if pid, err := parsePid("4242"); err != nil || pid != 4242 {
+		t.Errorf("got %d, %v", pid, err)
+	}
+	for _, bad := range []string{"", "0", "-1", "nginx", "99999999999"} {
+		if pid, err := parsePid(bad); err == nil {
+			t.Errorf("%q: got %d", bad, pid)
+		}
+	}
+}
+
+// fakePid reads as a process started at 1000 s that has used cpuSecs,
+// until gone or create changes.
+type fakePid struct {
+	create  int64
+	cpuSecs float64
+	gone    bool
+}
+
+func (f *fakePid) read(context.Context, int32) (ui.PIDReading, error) {
+	if f.gone {
+		return ui.PIDReading{}, pidErr(os.ErrNotExist)
+	}
+	return ui.PIDReading{Create: f.create, Name: "nginx", CPUSecs: f.cpuSecs, RSS: 48 << 20, Threads: 9, FDs: 31}, nil
+}
+
+// A watcher reads each process until it exits or its pid is taken by
+// another, and then no more.
+func TestPidWatcher(t *testing.T) {
+	ctx := context.Background()
+	f := &fakePid{create: 1000_000, cpuSecs: 10}
+	pw := &pidWatcher{read: f.read}
+	first, err := pw.watch(ctx, 4242)
+	if err != nil || first.Name != "nginx" || first.Create != f.create {
+		t.Fatalf("got %+v, %v", first, err)
+	}
+
+	f.cpuSecs = 11
+	msg := pw.readCmd(ctx)().(ui.PIDsMsg)
+	if msg.Errs[0] != nil || msg.Readings[0].CPUSecs != 11 {
+		t.Errorf("got %+v, %v", msg.Readings[0], msg.Errs[0])
+	}
+	if pw.busy.Load() {
+		t.Error("still busy after the reading")
+	}
+
+	// The pid given to a new process.
+	f.create = 1200_000
+	if msg := pw.readCmd(ctx)().(ui.PIDsMsg); msg.Errs[0] != nil || msg.Readings[0].Create != f.create {
+		t.Errorf("recycled: got %+v, %v", msg.Readings[0], msg.Errs[0])
+	}
+	if msg := pw.readCmd(ctx)().(ui.PIDsMsg); msg.Errs[0] != ui.ErrPIDGone {
+		t.Error("a recycled pid was read again")
+	}
+
+	f.gone = true
+	if _, err := pw.watch(ctx, 4243); err == nil || !strings.Contains(err.Error(), "-pid 4243") {
+		t.Errorf("no process: got %v", err)
+	}
+	if len(pw.pids) != 1 {
+		t.Errorf("got %d pids watched, want the one that was read", len(pw.pids))
+	}
+}
+
+func TestReadPid(t *testing.T) {
+	r, err := readPid(context.Background(), int32(os.Getpid()))
+	if err != nil || r.Create == 0 || r.RSS == 0 || r.Threads == 0 || r.Name == "" {
+		t.Errorf("got %+v, %v", r, err)
+	}
+}
+
+func TestPidErr(t *testing.T) {
+	for _, err := range []error{process.ErrorProcessNotRunning, os.ErrNotExist, fmt.Errorf("open /proc/4242/stat: %w", syscall.ENOENT), syscall.ESRCH} {
+		if got := pidErr(err); got != ui.ErrPIDGone {
+			t.Errorf("%v: got %v, want errPidGone", err, got)
+		}
+	}
+	for _, err := range []error{context.DeadlineExceeded, context.Canceled, os.ErrPermission, syscall.EACCES} {
+		if got := pidErr(err); got != err {
+			t.Errorf("%v: got %v, want it kept", err, got)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// ── Plain output ──────────────────────────────────────────────────────────────
+
+// When stdout is not a terminal (`infgo > out.txt`, cron), the TUI would
+// only fill the file with escape sequences.  infgo runs the headless
+// collector instead and prints a line per sample, e.g.
+//
+//	2026-03-01T12:00:00.500Z cpu=12.3% mem=45.6% used=7.21GiB total=16.00GiB load=0.52/0.61/0.70
+//
+// Fields that could not be read are left out and named in missing=.
+
+// plainTimeFormat is RFC 3339 with milliseconds, since two samples can
+// share a second.
+const plainTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// stdoutIsTerminal reports whether the TUI has somewhere to draw.
+func stdoutIsTerminal() bool { return term.IsTerminal(os.Stdout.Fd()) }
+
+// plainLine is the line printed for s, without a newline.
+func plainLine(s metrics.Sample) string {
+	var b strings.Builder
+	b.WriteString(s.Time().Format(plainTimeFormat))
+	if !s.Missing.Has(metrics.MissingCPU) {
+		fmt.Fprintf(&b, " cpu=%.1f%%", s.CpuTotal)
+	}
+	if !s.Missing.Has(metrics.MissingMem) {
+		fmt.Fprintf(&b, " mem=%.1f%% used=%.2fGiB total=%.2fGiB", s.MemPercent, s.MemUsedGB, s.MemTotalGB)
+	}
+	if !s.Missing.Has(metrics.MissingLoad) {
+		fmt.Fprintf(&b, " load=%.2f/%.2f/%.2f", s.Load1, s.Load5, s.Load15)
+	}
+	if s.Missing != 0 {
+		b.WriteString(" missing=" + s.Missing.String())
+	}
+	return b.String()
+}
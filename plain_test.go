@@ -0,0 +1,74 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ui"
+)
+
+func TestPlainLine(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 500e6, time.UTC)
+	full := metrics.Sample{
+		TimestampUnixMs: at.UnixMilli(), CpuTotal: 12.34,
+		MemPercent: 45.6, MemUsedGB: 7.211, MemTotalGB: 16,
+		Load1: 0.52, Load5: 0.61, Load15: 0.7,
+	}
+	tests := []struct {
+		name    string
+		missing metrics.Missing
+		want    string
+	}{
+		{"full", 0, "cpu=12.3% mem=45.6% used=7.21GiB total=16.00GiB load=0.52/0.61/0.70"},
+		{"no mem", metrics.MissingMem, "cpu=12.3% load=0.52/0.61/0.70 missing=mem"},
+		{"cpu only", metrics.MissingMem | metrics.MissingLoad, "cpu=12.3% missing=mem,load"},
+	}
+	for _, tt := range tests {
+		s := full
+		s.Missing = tt.missing
+		got := plainLine(s)
+		ts, rest, _ := strings.Cut(got, " ")
+		if parsed, err := time.Parse(plainTimeFormat, ts); err != nil || !parsed.Equal(at) {
+			t.Errorf("%s: timestamp %q does not read back as %v", tt.name, ts, at)
+		}
+		if rest != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, rest, tt.want)
+		}
+	}
+}
+
+// The collector writes a line per sample to text, with no escape codes.
+func TestHeadlessPlainText(t *testing.T) {
+	var out bytes.Buffer
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reads := 0
+	h := &headless{text: &out, read: func(context.Context) ui.Reading {
+		if reads++; reads == 3 {
+			cancel()
+		}
+		return ui.Reading{CPUTotal: float64(reads), CPUCores: []float64{1}, MemPercent: 50}
+	}}
+	if err := h.run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) < 2 {
+		t.Fatalf("got %q, want a line per sample", out.String())
+	}
+	for i, line := range lines {
+		if !strings.Contains(line, " cpu="+[...]string{"1.0%", "2.0%", "3.0%"}[i]+" ") {
+			t.Errorf("line %d: got %q", i, line)
+		}
+		if strings.ContainsRune(line, '\x1b') {
+			t.Errorf("line %d has escape codes: %q", i, line)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	syslogger "github.com/ALH477/infgo/logger"
+	"github.com/ALH477/infgo/metrics"
+)
+
+// ── Suspend and resume ────────────────────────────────────────────────────────
+
+// suspendJump is how many sampling intervals the wall clock must advance
+// between two samples before the gap is taken for a suspend.  The
+// monotonic clock cannot tell: on Linux it stops while the machine sleeps.
+const suspendJump = 5
+
+// powerWatch notices when the machine slept between two local samples.
+// The first CPU reading after a resume spans the whole suspend, so it is
+// discarded and a "suspend" event marks the gap in the log instead.
+//
+// On Linux systemd-logind announces sleep with PrepareForSleep on the
+// system bus, which gives the exact start of the suspend.  Elsewhere, and
+// when the bus is unavailable, a jump of the wall clock between samples
+// is the only sign.
+type powerWatch struct {
+	interval time.Duration
+	last     time.Time // wall-clock time of the previous check
+	stop     func()
+
+	mu      sync.Mutex
+	sleptAt time.Time // from PrepareForSleep(true); zero while awake
+	woke    bool      // PrepareForSleep(false) arrived since
+}
+
+// suspendWindow is a gap in sampling found by check.
+type suspendWindow struct {
+	from, to time.Time
+	native   bool // announced by the OS rather than inferred from the clock
+}
+
+func newPowerWatch(interval time.Duration) *powerWatch {
+	return &powerWatch{interval: interval, stop: func() {}}
+}
+
+// startPowerWatch returns a powerWatch subscribed to the OS's sleep
+// notifications where there are any.  Call close when done.
+func startPowerWatch(interval time.Duration) *powerWatch {
+	p := newPowerWatch(interval)
+	if stop, err := watchLogindSleep(p.sleeping, p.resumed); err == nil {
+		p.stop = stop
+	}
+	return p
+}
+
+func (p *powerWatch) close() {
+	if p != nil {
+		p.stop()
+	}
+}
+
+func (p *powerWatch) sleeping(at time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sleptAt, p.woke = at.Round(0), false
+}
+
+func (p *powerWatch) resumed() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.woke = !p.sleptAt.IsZero()
+}
+
+// restart forgets the previous sample, so that a gap in sampling the
+// process made itself, by being stopped, is not taken for a suspend.
+func (p *powerWatch) restart(now time.Time) {
+	if p != nil {
+		p.last = now.Round(0)
+	}
+}
+
+// check is called with the time of every local sample and reports whether
+// the machine slept since the previous one.
+func (p *powerWatch) check(now time.Time) (suspendWindow, bool) {
+	if p == nil {
+		return suspendWindow{}, false
+	}
+	now = now.Round(0) // wall clock only
+	last := p.last
+	p.last = now
+	jumped := !last.IsZero() && now.Sub(last) > suspendJump*p.interval
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	switch {
+	case p.woke || (jumped && !p.sleptAt.IsZero()):
+		w := suspendWindow{from: p.sleptAt, to: now, native: true}
+		p.sleptAt, p.woke = time.Time{}, false
+		return w, true
+	case jumped:
+		return suspendWindow{from: last, to: now}, true
+	}
+	return suspendWindow{}, false
+}
+
+// event is the log record marking w, stamped with the resume.
+func (w suspendWindow) event() metrics.Event {
+	span := fmt.Sprintf("%s to %s (%s)", w.from.UTC().Format(time.TimeOnly), w.to.UTC().Format(time.TimeOnly),
+		w.to.Sub(w.from).Round(time.Second))
+	msg := "system suspended " + span
+	if !w.native {
+		msg = "no samples " + span + ": the clock jumped, so the system was suspended or infgo stopped"
+	}
+	return metrics.Event{TimestampUnixMs: w.to.UnixMilli(), Kind: "suspend", Message: msg}
+}
+
+// recordSuspend writes w to the log and the live feeds, as recordAlert
+// does for alerts.
+func recordSuspend(w suspendWindow, lgr *syslogger.Logger, live *liveState) {
+	recordEvent(w.event(), lgr, live)
+}
+
+// recordEvent writes e to the log and the live feeds.
+func recordEvent(e metrics.Event, lgr *syslogger.Logger, live *liveState) {
+	if lgr != nil {
+		_ = lgr.WriteEvent(e)
+	}
+	if live != nil {
+		live.publishEvent(e)
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+//go:build linux
+
+package main
+
+import (
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// watchLogindSleep subscribes to systemd-logind's PrepareForSleep signal,
+// sent with true just before a suspend and false after the resume.
+func watchLogindSleep(sleeping func(time.Time), resumed func()) (stop func(), err error) {
+	conn, err := dbus.ConnectSystemBus()
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.AddMatchSignal(
+		dbus.WithMatchObjectPath("/org/freedesktop/login1"),
+		dbus.WithMatchInterface("org.freedesktop.login1.Manager"),
+		dbus.WithMatchMember("PrepareForSleep"),
+	); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	signals := make(chan *dbus.Signal, 4)
+	conn.Signal(signals)
+	go func() {
+		for sig := range signals { // closed by conn.Close
+			if sig.Name != "org.freedesktop.login1.Manager.PrepareForSleep" || len(sig.Body) != 1 {
+				continue
+			}
+			start, ok := sig.Body[0].(bool)
+			switch {
+			case !ok:
+			case start:
+				sleeping(time.Now())
+			default:
+				resumed()
+			}
+		}
+	}()
+	return func() { conn.Close() }, nil
+}
@@ -0,0 +1,17 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+//go:build !linux
+
+package main
+
+import (
+	"errors"
+	"time"
+)
+
+// watchLogindSleep fails here: logind is Linux's only, so a suspend is
+// found from the jump of the wall clock alone.
+func watchLogindSleep(sleeping func(time.Time), resumed func()) (stop func(), err error) {
+	return nil, errors.New("suspend notifications unsupported on this platform")
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPowerWatchClockJump(t *testing.T) {
+	const iv = 500 * time.Millisecond
+	t0 := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		ticks []time.Duration // offsets from t0 of successive checks
+		want  []int           // indexes of the checks that report a suspend
+	}{
+		{"steady", []time.Duration{0, iv, 2 * iv, 3 * iv}, nil},
+		{"late but not asleep", []time.Duration{0, iv, 6 * iv}, nil}, // exactly 5 intervals
+		{"suspend", []time.Duration{0, iv, iv + 45*time.Minute, iv + 45*time.Minute + iv}, []int{2}},
+		{"two suspends", []time.Duration{0, time.Hour, time.Hour + iv, 2 * time.Hour}, []int{1, 3}},
+		{"first check", []time.Duration{time.Hour}, nil},
+	}
+	for _, tt := range tests {
+		p := newPowerWatch(iv)
+		var got []int
+		for i, off := range tt.ticks {
+			w, ok := p.check(t0.Add(off))
+			if !ok {
+				continue
+			}
+			got = append(got, i)
+			if !w.from.Equal(t0.Add(tt.ticks[i-1])) || !w.to.Equal(t0.Add(off)) || w.native {
+				t.Errorf("%s: check %d: got window %v to %v (native %v), want the previous check to this one",
+					tt.name, i, w.from, w.to, w.native)
+			}
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: got suspends at %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPowerWatchNative(t *testing.T) {
+	const iv = 500 * time.Millisecond
+	t0 := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	slept := t0.Add(200 * time.Millisecond)
+
+	// A short suspend the clock alone would miss.
+	p := newPowerWatch(iv)
+	p.check(t0)
+	p.sleeping(slept)
+	p.resumed()
+	w, ok := p.check(t0.Add(2 * iv))
+	if !ok || !w.native || !w.from.Equal(slept) || !w.to.Equal(t0.Add(2*iv)) {
+		t.Errorf("short suspend: got %+v, %v", w, ok)
+	}
+	if _, ok := p.check(t0.Add(3 * iv)); ok {
+		t.Error("the suspend was reported twice")
+	}
+
+	// The clock jump is seen before the resume signal arrives: the window
+	// still starts at PrepareForSleep, and the late signal is ignored.
+	p = newPowerWatch(iv)
+	p.check(t0)
+	p.sleeping(slept)
+	w, ok = p.check(t0.Add(time.Hour))
+	if !ok || !w.native || !w.from.Equal(slept) {
+		t.Errorf("jump before resume: got %+v, %v", w, ok)
+	}
+	p.resumed()
+	if _, ok := p.check(t0.Add(time.Hour + iv)); ok {
+		t.Error("a late resume signal was reported as another suspend")
+	}
+
+	// A resume without a PrepareForSleep(true) is ignored.
+	p = newPowerWatch(iv)
+	p.check(t0)
+	p.resumed()
+	if _, ok := p.check(t0.Add(iv)); ok {
+		t.Error("resume without sleep: got a suspend")
+	}
+}
+
+func TestSuspendEvent(t *testing.T) {
+	from := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	to := from.Add(45*time.Minute + 300*time.Millisecond)
+	tests := []struct {
+		native bool
+		want   string
+	}{
+		{true, "system suspended 12:00:00 to 12:45:00 (45m0s)"},
+		{false, "no samples 12:00:00 to 12:45:00 (45m0s): the clock jumped"},
+	}
+	for _, tt := range tests {
+		e := suspendWindow{from: from, to: to, native: tt.native}.event()
+		if e.Kind != "suspend" || e.TimestampUnixMs != to.UnixMilli() || !strings.HasPrefix(e.Message, tt.want) {
+			t.Errorf("native %v: got %+v, want a suspend event %q", tt.native, e, tt.want)
+		}
+	}
+
+	// A nil watch, as with -connect, never reports anything.
+	var p *powerWatch
+	if _, ok := p.check(to); ok {
+		t.Error("nil powerWatch reported a suspend")
+	}
+	p.close()
+}
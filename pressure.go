@@ -0,0 +1,116 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strconv"
+	"strings"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+// ── PRESSURE panel ────────────────────────────────────────────────────────────
+//
+// Pressure Stall Information: the share of time that some task, or all of
+// them ("full"), waited for the CPU, memory or I/O, over the last 10, 60
+// and 300 seconds, from /proc/pressure on Linux 4.20 and later.  It says
+// that a machine is short of something well before the load average does.
+// The panel is hidden until a reading has found the files, so an older
+// kernel, one booted with psi=0, or another platform shows none.
+
+// errNoSomeLine is a pressure file without its some line.
+var errNoSomeLine = errors.New("no some line")
+
+// parsePressure reads one /proc/pressure file:
+//
+//	some avg10=0.12 avg60=0.05 avg300=0.01 total=123456
+//	full avg10=0.00 avg60=0.00 avg300=0.00 total=4567
+func parsePressure(r io.Reader) (ui.PSIResource, error) {
+	var res ui.PSIResource
+	var hasSome bool
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		f := strings.Fields(sc.Text())
+		if len(f) == 0 || (f[0] != "some" && f[0] != "full") {
+			continue
+		}
+		var a ui.PSIAvgs
+		for _, kv := range f[1:] {
+			k, v, ok := strings.Cut(kv, "=")
+			if !ok {
+				return res, fmt.Errorf("%s: %q is not key=value", f[0], kv)
+			}
+			var dst *float64
+			switch k {
+			case "avg10":
+				dst = &a.Avg10
+			case "avg60":
+				dst = &a.Avg60
+			case "avg300":
+				dst = &a.Avg300
+			default:
+				continue // total, in microseconds
+			}
+			x, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return res, fmt.Errorf("%s %s: %w", f[0], k, err)
+			}
+			*dst = x
+		}
+		if f[0] == "some" {
+			res.Some, hasSome = a, true
+		} else {
+			res.Full, res.HasFull = a, true
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return res, err
+	}
+	if !hasSome {
+		return res, errNoSomeLine
+	}
+	return res, nil
+}
+
+// readPressureFS reads the cpu, memory and io files at the root of fsys,
+// a /proc/pressure.
+func readPressureFS(fsys fs.FS) (ui.Pressure, error) {
+	var p ui.Pressure
+	for _, f := range []struct {
+		name string
+		dst  *ui.PSIResource
+	}{{"cpu", &p.CPU}, {"memory", &p.Memory}, {"io", &p.IO}} {
+		file, err := fsys.Open(f.name)
+		if err != nil {
+			return p, err
+		}
+		*f.dst, err = parsePressure(file)
+		file.Close()
+		if err != nil {
+			return p, fmt.Errorf("/proc/pressure/%s: %w", f.name, err)
+		}
+	}
+	return p, nil
+}
+
+// readPressure reads the pressure into msg, if the panel is shown and the
+// subsystem is due.
+func (r *statsReader) readPressure(ctx context.Context, msg *ui.Reading) {
+	if !r.psiOn || r.src.pressure == nil || !r.psi.due(r.now()) {
+		return
+	}
+	p, err := query(ctx, &r.psi, r.timeout, r.src.pressure)
+	if err != nil {
+		r.psi.failed(r.now())
+		return
+	}
+	r.psi.recovered()
+	msg.Pressure, msg.HasPressure = p, true
+}
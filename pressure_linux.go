@@ -0,0 +1,19 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+//go:build linux
+
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+// readPressureInfo reads the pressure on the CPU, memory and I/O from
+// /proc/pressure.
+func readPressureInfo(context.Context) (ui.Pressure, error) {
+	return readPressureFS(os.DirFS("/proc/pressure"))
+}
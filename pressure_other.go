@@ -0,0 +1,16 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+//go:build !linux
+
+package main
+
+import (
+	"context"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+// readPressureInfo is nil here: pressure stall information is Linux's
+// only, and there is no PRESSURE panel.
+var readPressureInfo func(context.Context) (ui.Pressure, error)
@@ -0,0 +1,61 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+func TestParsePressure(t *testing.T) {
+	tests := []struct {
+		file string
+		want ui.PSIResource
+	}{
+		{"pressure/cpu", ui.PSIResource{Some: ui.PSIAvgs{Avg10: 3.41, Avg60: 2.05, Avg300: 0.87}, HasFull: true}},
+		{"pressure/memory", ui.PSIResource{Some: ui.PSIAvgs{Avg10: 18.52, Avg60: 9.31, Avg300: 3.02}, Full: ui.PSIAvgs{Avg10: 12.07, Avg60: 6.44, Avg300: 1.95}, HasFull: true}},
+		// The CPU had no full line before Linux 5.13.
+		{"pressure-5.4/cpu", ui.PSIResource{Some: ui.PSIAvgs{Avg10: 3.41, Avg60: 2.05, Avg300: 0.87}}},
+	}
+	for _, tt := range tests {
+		f, err := os.Open("testdata/" + tt.file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := parsePressure(f)
+		f.Close()
+		if err != nil || got != tt.want {
+			t.Errorf("%s: got %+v, %v, want %+v", tt.file, got, err, tt.want)
+		}
+	}
+	for _, bad := range []string{"", "full avg10=0.00 avg60=0.00 avg300=0.00 total=0\n", "some avg10=lots\n", "some avg10\n"} {
+		if got, err := parsePressure(strings.NewReader(bad)); err == nil {
+			t.Errorf("%q: got %+v", bad, got)
+		}
+	}
+	if _, err := readPressureFS(os.DirFS("testdata/missing")); err == nil {
+		t.Error("no /proc/pressure: got nil error")
+	}
+}
+
+// A reading has the pressure once there is a source for it.
+func TestPressureRead(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	r := fakeReader(&fakeSources{}, &now)
+	r.psiOn = true
+	if msg := r.read(context.Background()); msg.HasPressure {
+		t.Fatal("read without a source")
+	}
+	r.src.pressure = func(context.Context) (ui.Pressure, error) { return readPressureFS(os.DirFS("testdata/pressure-5.4")) }
+	now = now.Add(ui.DefaultInterval)
+	msg := r.read(context.Background())
+	if p := msg.Pressure; !msg.HasPressure || p.Memory.Full.Avg10 != 12.07 || p.CPU.Some.Avg10 != 3.41 || p.CPU.HasFull {
+		t.Errorf("got %+v, %v", p, msg.HasPressure)
+	}
+}
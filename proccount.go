@@ -0,0 +1,123 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/fs"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shirou/gopsutil/v3/load"
+	"github.com/shirou/gopsutil/v3/process"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+// ── Process counts ────────────────────────────────────────────────────────────
+//
+// The SYSTEM panel's Procs row: the processes on the machine, and the
+// zombies among them in amber, since a parent that never reaps its
+// children leaks them until the pid space runs out.  On Linux the total
+// comes from load.Misc and the zombies from the states in /proc/*/stat;
+// elsewhere both come from gopsutil's listing of each process's state,
+// which costs more.  Either way they are read on the FDs' tick, every
+// fdInterval, and where neither can be read the row is left out.
+
+// procCountSources are where the counts are read from: misc and procFS,
+// a /proc, on Linux, where procFS is non-nil, and states for the rest.
+type procCountSources struct {
+	misc   func(context.Context) (*load.MiscStat, error)
+	procFS fs.FS
+	states func(context.Context) ([]string, error)
+}
+
+var procCountSrc = procCountSources{
+	misc:   load.MiscWithContext,
+	procFS: procFS(),
+	states: processStates,
+}
+
+// errNoState is a /proc/N/stat without the state after its command.
+var errNoState = errors.New("no state")
+
+// statState is the state in a /proc/N/stat, the field after the command,
+// which is in parentheses and may itself hold spaces and parentheses.
+func statState(stat []byte) (byte, error) {
+	i := bytes.LastIndexByte(stat, ')')
+	if i < 0 || i+2 >= len(stat) {
+		return 0, errNoState
+	}
+	return stat[i+2], nil
+}
+
+// countZombies counts the processes under fsys, a /proc, in state Z.  A
+// process gone between the listing and its reading is left out.
+func countZombies(fsys fs.FS) (int, error) {
+	stats, err := fs.Glob(fsys, "[0-9]*/stat")
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, name := range stats {
+		b, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			continue
+		}
+		if s, err := statState(b); err == nil && s == 'Z' {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// processStates is the state of each process, as gopsutil names them; ""
+// for one whose state cannot be read, as on Windows, which has none.
+func processStates(ctx context.Context) ([]string, error) {
+	ps, err := process.ProcessesWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	states := make([]string, len(ps))
+	for i, p := range ps {
+		if s, err := p.StatusWithContext(ctx); err == nil && len(s) > 0 {
+			states[i] = s[0]
+		}
+	}
+	return states, nil
+}
+
+// readProcCounts reads the counts from src: from misc and procFS where
+// they can be read, and from states where not.
+func readProcCounts(ctx context.Context, src procCountSources) (ui.ProcCounts, error) {
+	if src.procFS != nil {
+		if misc, err := src.misc(ctx); err == nil {
+			if z, err := countZombies(src.procFS); err == nil {
+				return ui.ProcCounts{Total: misc.ProcsTotal, Zombies: z}, nil
+			}
+		}
+	}
+	states, err := src.states(ctx)
+	if err != nil {
+		return ui.ProcCounts{}, err
+	}
+	c := ui.ProcCounts{Total: len(states)}
+	for _, s := range states {
+		if s == process.Zombie {
+			c.Zombies++
+		}
+	}
+	return c, nil
+}
+
+// procCountsCmd takes a reading off the Update goroutine.
+func procCountsCmd(ctx context.Context) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, fdInterval)
+		defer cancel()
+		c, err := readProcCounts(ctx, procCountSrc)
+		return ui.ProcCountsMsg{Counts: c, OK: err == nil}
+	}
+}
@@ -0,0 +1,14 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+//go:build linux
+
+package main
+
+import (
+	"io/fs"
+	"os"
+)
+
+// procFS is /proc, where the zombies are counted.
+func procFS() fs.FS { return os.DirFS("/proc") }
@@ -0,0 +1,14 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+//go:build !linux
+
+package main
+
+import (
+	"io/fs"
+)
+
+// procFS is nil: there is no /proc here, and the counts come from
+// gopsutil's listing of the processes instead.
+func procFS() fs.FS { return nil }
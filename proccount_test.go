@@ -0,0 +1,67 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/shirou/gopsutil/v3/load"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+func TestStatState(t *testing.T) {
+	for _, tt := range []struct {
+		stat string
+		want byte
+		ok   bool
+	}{
+		{"1 (systemd) S 0 1 1 0 -1", 'S', true},
+		{"4242 (defunct) Z 1 4242", 'Z', true},
+		{"77 (a) b (c)) R 1 77", 'R', true}, // a command with spaces and parentheses
+		{"77 (cut", 0, false},
+		{"77 (cut)", 0, false},
+	} {
+		if got, err := statState([]byte(tt.stat)); got != tt.want || (err == nil) != tt.ok {
+			t.Errorf("%q: got %q, %v", tt.stat, got, err)
+		}
+	}
+}
+
+// On Linux the total is load.Misc's and the zombies those in state Z under
+// /proc; elsewhere both come from the states, and where neither can be
+// read there is no count.
+func TestReadProcCounts(t *testing.T) {
+	proc := fstest.MapFS{
+		"1/stat":    {Data: []byte("1 (init) S 0 1 1")},
+		"200/stat":  {Data: []byte("200 (make) Z 1 200")},
+		"201/stat":  {Data: []byte("201 (cc1 (x)) Z 200 200")},
+		"self/stat": {Data: []byte("300 (infgo) Z 1 300")}, // not a process of its own
+		"uptime":    {Data: []byte("1.0 1.0")},
+	}
+	misc := func(context.Context) (*load.MiscStat, error) { return &load.MiscStat{ProcsTotal: 412}, nil }
+	states := func(context.Context) ([]string, error) { return []string{"running", "zombie", "", "sleep"}, nil }
+	failing := errors.New("unsupported")
+	noMisc := func(context.Context) (*load.MiscStat, error) { return nil, failing }
+	noStates := func(context.Context) ([]string, error) { return nil, failing }
+	for _, tt := range []struct {
+		name string
+		src  procCountSources
+		want ui.ProcCounts
+		ok   bool
+	}{
+		{"linux", procCountSources{misc: misc, procFS: proc, states: noStates}, ui.ProcCounts{Total: 412, Zombies: 2}, true},
+		{"no /proc", procCountSources{misc: misc, states: states}, ui.ProcCounts{Total: 4, Zombies: 1}, true},
+		{"no misc", procCountSources{misc: noMisc, procFS: proc, states: states}, ui.ProcCounts{Total: 4, Zombies: 1}, true},
+		{"neither", procCountSources{misc: noMisc, states: noStates}, ui.ProcCounts{}, false},
+	} {
+		got, err := readProcCounts(context.Background(), tt.src)
+		if got != tt.want || (err == nil) != tt.ok {
+			t.Errorf("%s: got %+v, %v", tt.name, got, err)
+		}
+	}
+}
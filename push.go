@@ -0,0 +1,160 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"sync/atomic"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// ── Push writers ──────────────────────────────────────────────────────────────
+
+// pushQueue is how many samples a push writer may fall behind (a slow or
+// retrying endpoint) before new ones are dropped.
+const pushQueue = 64
+
+// pushRecord is one item on a push writer's input channel: the header once
+// the host is known, then every sample.
+type pushRecord struct {
+	header *metrics.Header
+	sample metrics.Sample
+}
+
+// pushWriter delivers records to an external system such as InfluxDB.
+type pushWriter interface {
+	// run consumes in until it is closed, then flushes what it holds.
+	run(in <-chan pushRecord)
+	// dropped is how many samples were given up on after delivery failed,
+	// and lastError why the most recent of them was.
+	dropped() int64
+	lastError() string
+}
+
+// pusher runs a pushWriter on its own goroutine and feeds it from the TUI or
+// headless loop without ever blocking them.
+type pusher struct {
+	name string // for the footer and stderr, e.g. "influx"
+	w    pushWriter
+	in   chan pushRecord
+	done chan struct{}
+
+	overflow atomic.Int64 // samples dropped because the queue was full
+}
+
+func startPusher(name string, w pushWriter) *pusher {
+	p := &pusher{name: name, w: w, in: make(chan pushRecord, pushQueue), done: make(chan struct{})}
+	go func() {
+		defer close(p.done)
+		w.run(p.in)
+	}()
+	return p
+}
+
+// setHeader queues the session header.  It blocks if the queue is full, as
+// a writer must not see samples for a host it has not been told about; it
+// is sent before any sample, so in practice the queue is empty.
+func (p *pusher) setHeader(h metrics.Header) {
+	p.in <- pushRecord{header: &h}
+}
+
+// setSample queues s, or counts it as dropped if the writer is behind.
+func (p *pusher) setSample(s metrics.Sample) {
+	select {
+	case p.in <- pushRecord{sample: s}:
+	default:
+		p.overflow.Add(1)
+	}
+}
+
+// dropped is the total number of samples that never reached the endpoint.
+func (p *pusher) dropped() int64 { return p.overflow.Load() + p.w.dropped() }
+
+// close stops the writer after it has flushed everything queued, and
+// summarises any losses.
+func (p *pusher) close() error {
+	close(p.in)
+	<-p.done
+	if n := p.dropped(); n > 0 {
+		if reason := p.w.lastError(); reason != "" {
+			return fmt.Errorf("%s: dropped %d samples; last error: %s", p.name, n, reason)
+		}
+		return fmt.Errorf("%s: dropped %d samples; the writer fell behind", p.name, n)
+	}
+	return nil
+}
+
+// pushConfig holds the flags of every push writer.
+type pushConfig struct {
+	influx         influxConfig
+	remoteWrite    remoteWriteConfig
+	graphite       string // host[:port]
+	graphitePrefix string
+	ship           string // host[:port] of an `infgo collect`
+}
+
+func (c pushConfig) enabled() bool {
+	return c.influx.enabled() || c.remoteWrite.enabled() || c.graphite != "" || c.ship != ""
+}
+
+// startPushers validates the push flags and starts a writer for each
+// endpoint configured.
+func startPushers(cfg pushConfig) ([]*pusher, error) {
+	var pushers []*pusher
+	if cfg.influx.enabled() {
+		w, err := newInfluxWriter(cfg.influx)
+		if err != nil {
+			return nil, err
+		}
+		pushers = append(pushers, startPusher("influx", w))
+	}
+	if cfg.remoteWrite.enabled() {
+		w, err := newRemoteWriter(cfg.remoteWrite)
+		if err != nil {
+			return nil, err
+		}
+		pushers = append(pushers, startPusher("remote write", w))
+	}
+	if cfg.graphite != "" {
+		w, err := newGraphiteWriter(cfg.graphite, cfg.graphitePrefix)
+		if err != nil {
+			return nil, err
+		}
+		pushers = append(pushers, startPusher("graphite", w))
+	}
+	if cfg.ship != "" {
+		w, err := newShipWriter(cfg.ship)
+		if err != nil {
+			return nil, err
+		}
+		pushers = append(pushers, startPusher("ship", w))
+	}
+	return pushers, nil
+}
+
+// closePushers flushes and stops every writer, reporting losses on stderr.
+func closePushers(pushers []*pusher) {
+	for _, p := range pushers {
+		if err := p.close(); err != nil {
+			fmt.Fprintf(os.Stderr, "infgo: %v\n", err)
+		}
+	}
+}
+
+// renderPushWarnings is the footer badges for push writers that have had
+// to drop samples, one for each.
+func renderPushWarnings(pushers []*pusher) []string {
+	var out []string
+	for _, p := range pushers {
+		if n := p.dropped(); n > 0 {
+			out = append(out, lipgloss.NewStyle().Foreground(cAmber).Render("⚠")+
+				dimSt.Render(fmt.Sprintf(" %s dropped %d", p.name, n)))
+		}
+	}
+	return out
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+// ── Remote source (-connect) ──────────────────────────────────────────────────
+
+// remoteTimeout bounds one poll, so a black-holed link is noticed within
+// a few ticks rather than after the OS TCP timeout.
+const remoteTimeout = 2 * time.Second
+
+// remoteSource polls another infgo's -listen API in place of gopsutil.
+type remoteSource struct {
+	base   string // scheme://host[:port][/prefix], no trailing slash
+	host   string // for display
+	client *http.Client
+}
+
+// newRemoteSource validates a -connect URL.  A bare host:port is taken to
+// mean http.
+func newRemoteSource(raw string) (*remoteSource, error) {
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("-connect: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("-connect: unsupported scheme %q (want http or https)", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("-connect: %q has no host", raw)
+	}
+	u.RawQuery, u.Fragment = "", ""
+	return &remoteSource{
+		base:   strings.TrimRight(u.String(), "/"),
+		host:   u.Host,
+		client: &http.Client{Timeout: remoteTimeout},
+	}, nil
+}
+
+func (r *remoteSource) Name() string   { return r.host }
+func (r *remoteSource) Target() string { return r.base }
+
+func (r *remoteSource) Close() error {
+	r.client.CloseIdleConnections()
+	return nil
+}
+
+// Fetch polls the remote in a Bubble Tea goroutine.
+func (r *remoteSource) Fetch() tea.Cmd {
+	return func() tea.Msg { return r.poll(context.Background()) }
+}
+
+// poll performs one request.  Every failure, from a refused connection to a
+// remote that has no sample yet, is reported in ui.RemoteMsg.Err.
+func (r *remoteSource) poll(ctx context.Context) ui.RemoteMsg {
+	start := time.Now()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.base+"/api/v1/now", nil)
+	if err != nil {
+		return ui.RemoteMsg{Err: err}
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return ui.RemoteMsg{Err: err}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var e apiError
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if json.Unmarshal(body, &e) != nil || e.Error == "" {
+			e.Error = resp.Status
+		}
+		return ui.RemoteMsg{Err: fmt.Errorf("%s: %s", r.host, e.Error)}
+	}
+
+	var now nowJSON
+	if err := json.NewDecoder(resp.Body).Decode(&now); err != nil {
+		return ui.RemoteMsg{Err: fmt.Errorf("%s: decode: %w", r.host, err)}
+	}
+	rtt := time.Since(start)
+
+	msg := ui.RemoteMsg{
+		Reading: ui.SampleReading(now.Sample, rtt),
+		RTT:     rtt,
+		Age:     time.Duration(now.AgeS * float64(time.Second)),
+	}
+	if now.Header != nil {
+		msg.Info = ui.HeaderInfo(*now.Header)
+	}
+	return msg
+}
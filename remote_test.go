@@ -0,0 +1,81 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+func TestNewRemoteSource(t *testing.T) {
+	tests := []struct {
+		in, wantBase string
+		wantErr      bool
+	}{
+		{"http://node7:9804", "http://node7:9804", false},
+		{"http://node7:9804/", "http://node7:9804", false},
+		{"node7:9804", "http://node7:9804", false},
+		{"https://proxy.example/infgo/?x=1", "https://proxy.example/infgo", false},
+		{"ftp://node7", "", true},
+		{"http://", "", true},
+	}
+	for _, tt := range tests {
+		src, err := newRemoteSource(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("newRemoteSource(%q): got err %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if err == nil && src.base != tt.wantBase {
+			t.Errorf("newRemoteSource(%q): got base %q, want %q", tt.in, src.base, tt.wantBase)
+		}
+	}
+}
+
+func TestRemotePoll(t *testing.T) {
+	live := newLiveState()
+	srv := httptest.NewServer(newServeMux(live, serveConfig{}))
+	defer srv.Close()
+	src, err := newRemoteSource(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Before the remote has a sample the poll fails with its reason.
+	msg := src.poll(context.Background())
+	if msg.Err == nil || !strings.Contains(msg.Err.Error(), "no sample collected yet") {
+		t.Errorf("poll before first sample: got err %v", msg.Err)
+	}
+
+	live.setHeader(metrics.Header{Hostname: "node7", Platform: "linux · arm64", NumCores: 4})
+	live.setSample(metrics.Sample{
+		TimestampUnixMs: 1704067200500,
+		CpuTotal:        37.5,
+		CpuCores:        []float64{30, 45},
+		MemPercent:      61.8,
+		Load5:           1.5,
+	}, 0)
+	msg = src.poll(context.Background())
+	if msg.Err != nil {
+		t.Fatalf("poll: %v", msg.Err)
+	}
+	if msg.Info == nil || msg.Info.Hostname != "node7" || msg.Info.NumCores != 4 {
+		t.Errorf("poll info: got %+v", msg.Info)
+	}
+	st := msg.Reading
+	if st.CPUTotal != 37.5 || len(st.CPUCores) != 2 || st.MemPercent != 61.8 || st.Load5 != 1.5 {
+		t.Errorf("poll stats: got %+v", st)
+	}
+	if st.At.UnixMilli() != 1704067200500 {
+		t.Errorf("poll stats time: got %d, want the remote timestamp", st.At.UnixMilli())
+	}
+
+	srv.Close()
+	if msg := src.poll(context.Background()); msg.Err == nil {
+		t.Error("poll after the remote went away: got nil error")
+	}
+}
@@ -0,0 +1,218 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/snappy"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// ── Prometheus remote write (-remote-write) ───────────────────────────────────
+
+const (
+	// remoteWriteFlushEvery and remoteWriteBatchSamples bound a batch: it
+	// is sent when either is reached, whichever comes first.
+	remoteWriteFlushEvery   = 10 * time.Second
+	remoteWriteBatchSamples = 100
+
+	// remoteWriteAttempts is how many times a batch is sent before it is
+	// dropped; retries back off from remoteWriteRetryMin up to
+	// remoteWriteRetryMax, or wait as long as a 429's Retry-After asks.
+	remoteWriteAttempts = 5
+	remoteWriteRetryMin = time.Second
+	remoteWriteRetryMax = 30 * time.Second
+
+	remoteWriteTimeout = 10 * time.Second
+
+	// remoteWriteJob is the job label of every series, standing in for
+	// the scrape job a Prometheus server would add on /metrics.
+	remoteWriteJob = "infgo"
+)
+
+// remoteWriteConfig holds the -remote-write* flags.
+type remoteWriteConfig struct {
+	url, token, user, pass string
+}
+
+func (c remoteWriteConfig) enabled() bool { return c.url != "" }
+
+// remoteWriter batches samples as the series /metrics exposes and sends
+// them to a Prometheus remote-write endpoint.
+type remoteWriter struct {
+	endpoint string
+	auth     string // Authorization header value, if any
+	client   *http.Client
+
+	// Batching and retry policy; the remoteWrite* constants outside tests.
+	flushEvery   time.Duration
+	batchSamples int
+	attempts     int
+	retryMin     time.Duration
+	retryMax     time.Duration
+
+	header   *metrics.Header
+	extra    []metrics.PromLabel // job and instance
+	series   []metrics.RemoteSeries
+	index    map[string]int // label set → position in series
+	samples  int            // samples in the batch
+	lost     atomic.Int64
+	lastFail atomic.Value // string: why the last batch was dropped
+}
+
+// newRemoteWriter validates the -remote-write* flags.
+func newRemoteWriter(cfg remoteWriteConfig) (*remoteWriter, error) {
+	u, err := url.Parse(cfg.url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("-remote-write: want http(s)://host/path, got %q", cfg.url)
+	}
+	w := &remoteWriter{
+		endpoint:     u.String(),
+		client:       &http.Client{Timeout: remoteWriteTimeout},
+		flushEvery:   remoteWriteFlushEvery,
+		batchSamples: remoteWriteBatchSamples,
+		attempts:     remoteWriteAttempts,
+		retryMin:     remoteWriteRetryMin,
+		retryMax:     remoteWriteRetryMax,
+		index:        make(map[string]int),
+	}
+	switch {
+	case cfg.token != "" && cfg.user != "":
+		return nil, errors.New("-remote-write-token and -remote-write-user are alternatives; pass one")
+	case cfg.token != "":
+		w.auth = "Bearer " + cfg.token
+	case cfg.user != "":
+		w.auth = "Basic " + base64.StdEncoding.EncodeToString([]byte(cfg.user+":"+cfg.pass))
+	case cfg.pass != "":
+		return nil, errors.New("-remote-write-pass needs -remote-write-user")
+	}
+	return w, nil
+}
+
+func (w *remoteWriter) dropped() int64 { return w.lost.Load() }
+
+func (w *remoteWriter) lastError() string {
+	s, _ := w.lastFail.Load().(string)
+	return s
+}
+
+func (w *remoteWriter) run(in <-chan pushRecord) {
+	tick := time.NewTicker(w.flushEvery)
+	defer tick.Stop()
+	for {
+		select {
+		case rec, ok := <-in:
+			if !ok {
+				w.flush(true)
+				return
+			}
+			if rec.header != nil {
+				w.header = rec.header
+				w.extra = []metrics.PromLabel{{Name: "instance", Value: rec.header.Hostname}, {Name: "job", Value: remoteWriteJob}}
+				continue
+			}
+			w.add(&rec.sample)
+			if w.samples >= w.batchSamples {
+				w.flush(false)
+			}
+		case <-tick.C:
+			w.flush(false)
+		}
+	}
+}
+
+// add appends s to the batch, one point per series of /metrics (without
+// the scrape-time gauges, which describe the exporter rather than the
+// host).
+func (w *remoteWriter) add(s *metrics.Sample) {
+	for _, ps := range metrics.PromSeriesOf(w.header, s) {
+		labels := metrics.RemoteLabels(&ps, w.extra...)
+		var key strings.Builder
+		for _, l := range labels {
+			key.WriteString(l.Name + "\xff" + l.Value + "\xff")
+		}
+		i, ok := w.index[key.String()]
+		if !ok {
+			i = len(w.series)
+			w.index[key.String()] = i
+			w.series = append(w.series, metrics.RemoteSeries{Labels: labels})
+		}
+		w.series[i].Samples = append(w.series[i].Samples, metrics.RemoteSample{Value: ps.Value, TimestampMs: s.TimestampUnixMs})
+	}
+	w.samples++
+}
+
+// flush sends the pending batch, retrying transient failures, and drops
+// it if that does not succeed.  The final flush on shutdown is tried once,
+// so quitting never waits out the backoff.
+func (w *remoteWriter) flush(final bool) {
+	if w.samples == 0 {
+		return
+	}
+	body := snappy.Encode(nil, metrics.MarshalWriteRequest(w.series))
+	var err error
+	delay := w.retryMin
+	for attempt := 1; ; attempt++ {
+		var retryAfter time.Duration
+		retryAfter, err = w.post(body)
+		if err == nil || retryAfter < 0 || attempt >= w.attempts || final {
+			break
+		}
+		if retryAfter > 0 {
+			delay = retryAfter
+		}
+		time.Sleep(delay)
+		delay = min(delay*2, w.retryMax)
+	}
+	if err != nil {
+		w.lost.Add(int64(w.samples))
+		w.lastFail.Store(err.Error())
+	}
+	w.series, w.samples = w.series[:0], 0
+	clear(w.index)
+}
+
+// post makes one write request.  retryAfter is negative when the failure
+// is permanent (a 4xx other than 429), and positive when the server asked
+// for a specific delay.
+func (w *remoteWriter) post(body []byte) (retryAfter time.Duration, err error) {
+	req, err := http.NewRequest(http.MethodPost, w.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return -1, err
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("User-Agent", "infgo")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	if w.auth != "" {
+		req.Header.Set("Authorization", w.auth)
+	}
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	switch {
+	case resp.StatusCode/100 == 2:
+		return 0, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode/100 == 5:
+		secs, _ := strconv.Atoi(resp.Header.Get("Retry-After"))
+		return time.Duration(secs) * time.Second, fmt.Errorf("remote write: %s", resp.Status)
+	default:
+		return -1, fmt.Errorf("remote write: %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+}
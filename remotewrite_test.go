@@ -0,0 +1,254 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// remoteWriteStub decodes the WriteRequests sent to it.  status, if set,
+// picks the response to each request from its 1-based number.
+type remoteWriteStub struct {
+	mu       sync.Mutex
+	requests [][]metrics.RemoteSeries
+	auth     []string
+	calls    int
+	status   func(n int) int
+}
+
+func (s *remoteWriteStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.calls++
+	n := s.calls
+	s.auth = append(s.auth, r.Header.Get("Authorization"))
+	s.mu.Unlock()
+
+	if r.URL.Path != "/api/v1/write" || r.Header.Get("Content-Encoding") != "snappy" ||
+		r.Header.Get("Content-Type") != "application/x-protobuf" || r.Header.Get("X-Prometheus-Remote-Write-Version") != "0.1.0" {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	if s.status != nil {
+		if code := s.status(n); code != http.StatusNoContent {
+			if code == http.StatusTooManyRequests {
+				w.Header().Set("Retry-After", "0")
+			}
+			http.Error(w, "nope", code)
+			return
+		}
+	}
+	raw, _ := io.ReadAll(r.Body)
+	body, err := snappy.Decode(nil, raw)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	series, err := metrics.UnmarshalWriteRequest(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	s.requests = append(s.requests, series)
+	s.mu.Unlock()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func testRemoteWriter(t *testing.T, stub *remoteWriteStub, cfg remoteWriteConfig) *remoteWriter {
+	t.Helper()
+	srv := httptest.NewServer(stub)
+	t.Cleanup(srv.Close)
+	cfg.url = srv.URL + "/api/v1/write"
+	w, err := newRemoteWriter(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.retryMin, w.retryMax = time.Millisecond, 4*time.Millisecond
+	return w
+}
+
+// seriesName renders a label set the way /metrics writes the series, with
+// the job and instance labels left out.
+func seriesName(labels []metrics.PromLabel) string {
+	var name string
+	var rest []string
+	for _, l := range labels {
+		switch l.Name {
+		case "__name__":
+			name = l.Value
+		case "job", "instance":
+		default:
+			rest = append(rest, l.Name+`="`+l.Value+`"`)
+		}
+	}
+	if len(rest) > 0 {
+		name += "{" + strings.Join(rest, ",") + "}"
+	}
+	return name
+}
+
+func TestRemoteWriteSeries(t *testing.T) {
+	stub := &remoteWriteStub{}
+	w := testRemoteWriter(t, stub, remoteWriteConfig{token: "s3cret"})
+
+	hdr := metrics.Header{Hostname: "node7", Platform: "linux", NumCores: 2}
+	p := startPusher("remote write", w)
+	p.setHeader(hdr)
+	samples := []metrics.Sample{
+		{TimestampUnixMs: 1704067200000, CpuTotal: 40, CpuCores: []float64{30, 50}, MemPercent: 50, MemUsedGB: 4, MemTotalGB: 8, Load1: 1.5, Load5: 1, Load15: 0.5},
+		{TimestampUnixMs: 1704067200500, CpuTotal: 60, CpuCores: []float64{55, 65}, MemPercent: 51, MemUsedGB: 4, MemTotalGB: 8, Load1: 1.75, Load5: 1, Load15: 0.5},
+	}
+	for _, s := range samples {
+		p.setSample(s)
+	}
+	if err := p.close(); err != nil {
+		t.Fatal(err)
+	}
+	if len(stub.requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(stub.requests))
+	}
+	if got := stub.auth[0]; got != "Bearer s3cret" {
+		t.Errorf("Authorization: got %q", got)
+	}
+
+	// Every series of /metrics is there, named the same, with the host
+	// labels and one point per sample.
+	var exposition bytes.Buffer
+	metrics.WritePrometheus(&exposition, &hdr, &samples[0])
+	var want []string
+	for _, line := range strings.Split(exposition.String(), "\n") {
+		if line != "" && !strings.HasPrefix(line, "#") {
+			want = append(want, line[:strings.LastIndexByte(line, ' ')])
+		}
+	}
+	got := stub.requests[0]
+	if len(got) != len(want) {
+		t.Fatalf("got %d series, want %d", len(got), len(want))
+	}
+	for i, ts := range got {
+		if name := seriesName(ts.Labels); name != want[i] {
+			t.Errorf("series %d: got %s, want %s", i, name, want[i])
+		}
+		for j := 1; j < len(ts.Labels); j++ {
+			if ts.Labels[j-1].Name >= ts.Labels[j].Name {
+				t.Errorf("%s: labels not sorted: %v", want[i], ts.Labels)
+			}
+		}
+		if !hasLabel(ts.Labels, "instance", "node7") || !hasLabel(ts.Labels, "job", "infgo") {
+			t.Errorf("%s: got labels %v, want instance and job", want[i], ts.Labels)
+		}
+		if len(ts.Samples) != 2 || ts.Samples[0].TimestampMs != 1704067200000 || ts.Samples[1].TimestampMs != 1704067200500 {
+			t.Errorf("%s: got samples %v", want[i], ts.Samples)
+		}
+	}
+	for _, tt := range []struct {
+		series string
+		want   [2]float64
+	}{
+		{"infgo_cpu_usage_percent", [2]float64{40, 60}},
+		{`infgo_cpu_core_usage_percent{core="1"}`, [2]float64{50, 65}},
+		{"infgo_memory_total_bytes", [2]float64{8 << 30, 8 << 30}},
+		{`infgo_load_average{period="1m"}`, [2]float64{1.5, 1.75}},
+	} {
+		for _, ts := range got {
+			if seriesName(ts.Labels) == tt.series && (ts.Samples[0].Value != tt.want[0] || ts.Samples[1].Value != tt.want[1]) {
+				t.Errorf("%s: got %v, want %v", tt.series, ts.Samples, tt.want)
+			}
+		}
+	}
+}
+
+func hasLabel(labels []metrics.PromLabel, name, value string) bool {
+	for _, l := range labels {
+		if l.Name == name && l.Value == value {
+			return true
+		}
+	}
+	return false
+}
+
+func TestRemoteWriteBatching(t *testing.T) {
+	stub := &remoteWriteStub{}
+	w := testRemoteWriter(t, stub, remoteWriteConfig{user: "ops", pass: "hunter2"})
+	w.batchSamples = 10
+
+	if err := pushSamples(w, 25); err != nil {
+		t.Fatal(err)
+	}
+	// Two full batches, and the rest on close.
+	if len(stub.requests) != 3 {
+		t.Fatalf("got %d requests, want 3", len(stub.requests))
+	}
+	for i, want := range []int{10, 10, 5} {
+		if n := len(stub.requests[i][0].Samples); n != want {
+			t.Errorf("request %d: got %d samples per series, want %d", i, n, want)
+		}
+	}
+	if got := stub.auth[0]; got != "Basic b3BzOmh1bnRlcjI=" {
+		t.Errorf("Authorization: got %q", got)
+	}
+}
+
+func TestRemoteWriteRetry(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       func(n int) int
+		wantRequests int
+		wantDropped  int64
+		wantCalls    int
+	}{
+		{"recovers", func(n int) int { return []int{0, 503, 429, 204}[min(n, 3)] }, 1, 0, 3},
+		{"persistent 5xx", func(int) int { return 500 }, 0, 10, remoteWriteAttempts},
+		{"permanent 4xx", func(int) int { return 400 }, 0, 10, 1},
+	}
+	for _, tt := range tests {
+		stub := &remoteWriteStub{status: tt.status}
+		w := testRemoteWriter(t, stub, remoteWriteConfig{})
+		w.batchSamples = 10
+		err := pushSamples(w, 10) // exactly one full batch
+		if len(stub.requests) != tt.wantRequests || w.dropped() != tt.wantDropped || stub.calls != tt.wantCalls {
+			t.Errorf("%s: got %d requests, %d dropped, %d calls; want %d, %d, %d",
+				tt.name, len(stub.requests), w.dropped(), stub.calls, tt.wantRequests, tt.wantDropped, tt.wantCalls)
+		}
+		if (err != nil) != (tt.wantDropped > 0) {
+			t.Errorf("%s: close: got %v", tt.name, err)
+		}
+	}
+}
+
+func TestNewRemoteWriter(t *testing.T) {
+	tests := []struct {
+		cfg      remoteWriteConfig
+		wantAuth string
+		wantErr  bool
+	}{
+		{remoteWriteConfig{url: "https://prom/api/v1/write"}, "", false},
+		{remoteWriteConfig{url: "https://prom/api/v1/write", token: "t"}, "Bearer t", false},
+		{remoteWriteConfig{url: "https://prom/api/v1/write", user: "u", pass: "p"}, "Basic dTpw", false},
+		{remoteWriteConfig{url: "https://prom/api/v1/write", token: "t", user: "u"}, "", true},
+		{remoteWriteConfig{url: "https://prom/api/v1/write", pass: "p"}, "", true},
+		{remoteWriteConfig{url: "prom:9090"}, "", true},
+	}
+	for _, tt := range tests {
+		w, err := newRemoteWriter(tt.cfg)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("newRemoteWriter(%+v): got err %v, wantErr %v", tt.cfg, err, tt.wantErr)
+			continue
+		}
+		if err == nil && w.auth != tt.wantAuth {
+			t.Errorf("newRemoteWriter(%+v): got auth %q, want %q", tt.cfg, w.auth, tt.wantAuth)
+		}
+	}
+}
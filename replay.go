@@ -0,0 +1,70 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ALH477/infgo/analysis"
+	"github.com/ALH477/infgo/ui"
+)
+
+// ── Replay (-replay) and its baseline (-baseline) ─────────────────────────────
+
+// -replay plays a capture through the TUI in place of this host, a sample
+// per tick, as -connect plays another machine's.  -baseline lays a second
+// capture under it, an earlier run of the same job say: its CPU and memory
+// are resampled onto the replay's timeline, lined up by time since the
+// start of each, drawn as dim sparklines under the replay's own, and
+// compared with them in the banner.
+
+// replaySource feeds a model the samples of a capture.
+type replaySource struct {
+	path string
+	c    *analysis.Capture
+	info *ui.SysInfo
+	next int // of the sample the next fetch delivers
+}
+
+// newReplaySource reads the capture at path.
+func newReplaySource(path string) (*replaySource, error) {
+	if path == stdinPath {
+		return nil, fmt.Errorf("-replay: the TUI reads its keys from stdin; give a capture file")
+	}
+	c, err := loadCapture(path)
+	if err != nil {
+		return nil, fmt.Errorf("-replay: %w", err)
+	}
+	if len(c.Samples) == 0 {
+		return nil, fmt.Errorf("-replay: %s has no samples", path)
+	}
+	// The samples are only applied once the host is known; a capture
+	// without a header goes by its file name.
+	info := &ui.SysInfo{Hostname: filepath.Base(path)}
+	if c.Header != nil {
+		info = ui.HeaderInfo(*c.Header)
+	}
+	return &replaySource{path: path, c: c, info: info}, nil
+}
+
+func (r *replaySource) Name() string   { return filepath.Base(r.path) }
+func (r *replaySource) Target() string { return r.path }
+func (r *replaySource) Close() error   { return nil }
+
+// Fetch delivers the next sample, and once the capture is played out its
+// last one again, which the model applies only once.  It advances at once
+// rather than in the command, so that the model's goroutine alone
+// touches next.
+func (r *replaySource) Fetch() tea.Cmd {
+	s := r.c.Samples[min(r.next, len(r.c.Samples)-1)]
+	r.next = min(r.next+1, len(r.c.Samples))
+	msg := ui.RemoteMsg{Reading: ui.SampleReading(s, 0), Info: r.info}
+	return func() tea.Msg { return msg }
+}
+
+// Progress is how many of the samples have been played, of how many.
+func (r *replaySource) Progress() (played, total int) { return r.next, len(r.c.Samples) }
@@ -0,0 +1,112 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/ALH477/infgo/analysis"
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ui"
+)
+
+// rampCapture is n samples every step from start, CPU and memory rising
+// by one point a sample from cpu and mem.
+func rampCapture(start time.Time, step time.Duration, n int, cpu, mem float64) *analysis.Capture {
+	h := ui.SysInfo{Hostname: "box", Platform: "linux"}.Header(start, 4, step)
+	c := &analysis.Capture{Header: &h}
+	for i := range n {
+		c.Samples = append(c.Samples, metrics.Sample{
+			TimestampUnixMs: start.Add(time.Duration(i) * step).UnixMilli(),
+			CpuTotal:        cpu + float64(i),
+			MemPercent:      mem + float64(i),
+			CpuCores:        []float64{10, 20, 30, 40},
+			MemUsedGB:       8,
+			MemTotalGB:      16,
+		})
+	}
+	return c
+}
+
+// replayFile writes c to a file in a temporary directory.
+func replayFile(t *testing.T, c *analysis.Capture) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "new.infgo")
+	if err := writeCapture(path, *c.Header, c.Samples); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReplaySource(t *testing.T) {
+	c := rampCapture(time.UnixMilli(1_700_000_000_000), time.Second, 3, 50, 50)
+	r, err := newReplaySource(replayFile(t, c))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []float64
+	for range 5 {
+		msg := r.Fetch()().(ui.RemoteMsg)
+		if msg.Info == nil || msg.Info.Hostname != "box" {
+			t.Fatalf("got host info %+v, want box's", msg.Info)
+		}
+		got = append(got, msg.Reading.CPUTotal)
+	}
+	// Played out, the last sample is delivered again.
+	if want := []float64{50, 51, 52, 52, 52}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if played, total := r.Progress(); played != 3 || total != 3 {
+		t.Errorf("got progress %d/%d, want 3/3", played, total)
+	}
+
+	if _, err := newReplaySource("-"); err == nil {
+		t.Error("-replay -: got no error")
+	}
+}
+
+// replayModel plays n samples of a replay of primary, with base under it.
+func replayModel(t *testing.T, primary, base *analysis.Capture, n int) ui.Model {
+	t.Helper()
+	src, err := newReplaySource(replayFile(t, primary))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ui.NewBaseline("old.infgo", primary, base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tm tea.Model = ui.New(ui.WithFeed(src), ui.WithBaseline(b))
+	tm, _ = tm.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	for range n {
+		tm, _ = tm.Update(src.Fetch()())
+	}
+	return tm.(ui.Model)
+}
+
+// The baseline is drawn under the replay's sparklines and compared with it
+// in the banner; a shorter one at a coarser interval leaves a gap at the
+// end.
+func TestReplayBaselineFrame(t *testing.T) {
+	t0 := time.UnixMilli(1_700_000_000_000)
+	primary := rampCapture(t0, time.Second, 30, 20, 40)
+	base := rampCapture(t0.Add(-24*time.Hour), 2*time.Second, 10, 10, 45)
+	m := replayModel(t, primary, base, 25)
+	checkGoldenText(t, "replay_baseline.txt", ansi.Strip(m.View()))
+
+	m = replayModel(t, primary, base, 12)
+	view := ansi.Strip(m.View())
+	for _, want := range []string{"BASELINE old.infgo at +11s", "CPU 31.0% vs 15.0% +16.0", "MEM 51.0% vs 50.0% +1.0", "▶ REPLAY 12/30"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("missing %q in\n%s", want, view)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ALH477/infgo/analysis"
+	"github.com/ALH477/infgo/ui"
+)
+
+// ── report ────────────────────────────────────────────────────────────────────
+
+// minReportWidth keeps room for a label, a useful sparkline and its peak.
+const minReportWidth = 40
+
+// runReport implements `infgo report <capture.infgo> [-o md] [-width N] [-tz ZONE]`.
+func runReport(args []string) error {
+	fs := newFlagSet("report", "<capture.infgo|-> [-o md] [-width N] [-sigma K] [-tz ZONE]")
+	format := fs.String("o", "md", "output `format`; md is GitHub-flavored Markdown")
+	width := fs.Int("width", 80, "wrap lines and size sparklines to `N` columns")
+	sigma := fs.Float64("sigma", 3, "flag samples more than `K` standard deviations above the mean as anomalies")
+	tz := addTZFlag(fs)
+	addFormatFlags(fs)
+
+	pos, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 1 {
+		return usageErrorf(fs, "expected exactly one input capture")
+	}
+	if *format != "md" {
+		return usageErrorf(fs, "-o: unsupported format %q (supported: md)", *format)
+	}
+	if *width < minReportWidth {
+		return usageErrorf(fs, "-width must be at least %d", minReportWidth)
+	}
+	if *sigma <= 0 {
+		return usageErrorf(fs, "-sigma must be positive")
+	}
+
+	capture, err := loadCapture(pos[0])
+	if err != nil {
+		return err
+	}
+	if len(capture.Samples) == 0 {
+		return fmt.Errorf("%s contains no samples", inputName(pos[0]))
+	}
+	writeMarkdownReport(os.Stdout, capture, *width, *sigma, resolveZone(*tz, capture.Header))
+	return nil
+}
+
+// reportMetrics are the series drawn as sparklines and scanned for
+// anomalies in the Markdown report.
+var reportMetrics = []string{"cpu", "mem"}
+
+// writeMarkdownReport renders c as GitHub-flavored Markdown, with times in
+// zone.  Every line outside the tables fits in width columns, and the
+// sparklines use plain block characters so they survive any Markdown
+// viewer.
+func writeMarkdownReport(w io.Writer, c *analysis.Capture, width int, sigma float64, zone timeZone) {
+	first, last := c.Samples[0].Time(), c.Samples[len(c.Samples)-1].Time()
+	stamp := reportStamper(first, last, zone.loc)
+
+	host := "unknown host"
+	if c.Header != nil && c.Header.Hostname != "" {
+		host = c.Header.Hostname
+	}
+	fmt.Fprintf(w, "# infgo report: %s\n\n", mdEscape(host))
+
+	// Metadata.
+	fmt.Fprintf(w, "| Field | Value |\n|---|---|\n")
+	row := func(k, v string) { fmt.Fprintf(w, "| %s | %s |\n", k, v) }
+	if h := c.Header; h != nil {
+		row("Host", mdEscape(h.Hostname))
+		row("Platform", mdEscape(h.Platform))
+		if h.NumCores > 0 {
+			row("Cores", fmt.Sprintf("%d logical", h.NumCores))
+		}
+		if iv := h.Interval(); iv > 0 {
+			row("Interval", iv.String())
+		}
+	}
+	row("Started", first.In(zone.loc).Format("2006-01-02 15:04:05 MST"))
+	row("Ended", last.In(zone.loc).Format("2006-01-02 15:04:05 MST"))
+	row("Zone", mdEscape(zone.name))
+	row("Duration", formatDuration(last.Sub(first)))
+	row("Samples", fmt.Sprintf("%d", len(c.Samples)))
+	row("Events", fmt.Sprintf("%d", len(c.Events)))
+
+	// Summary statistics.
+	sum := analysis.SummarizeSamples(c.Samples)
+	fmt.Fprintf(w, "\n## Summary\n\n")
+	fmt.Fprintf(w, "| Metric | min | mean | p50 | p95 | p99 | max |\n")
+	fmt.Fprintf(w, "|---|---:|---:|---:|---:|---:|---:|\n")
+	for _, m := range analysis.Metrics {
+		st := sum[m.Name]
+		fmt.Fprintf(w, "| %s |", m.Label)
+		for _, v := range []float64{st.Min, st.Mean, st.P50, st.P95, st.P99, st.Max} {
+			fmt.Fprintf(w, " %s |", formatValue(m, v))
+		}
+		fmt.Fprintln(w)
+	}
+
+	// Whole-capture sparklines: "cpu  ▁▂▃…  max  91.4%".
+	const labelW = 5
+	peakW := len("  max ") + 6
+	sparkW := width - labelW - peakW
+	fmt.Fprintf(w, "\n## Timeline\n\n```text\n")
+	for _, name := range reportMetrics {
+		m, _ := analysis.LookupMetric(name)
+		vals := analysis.Series(c.Samples, m)
+		line := analysis.Sparkline(vals, sparkW, analysis.Ceil(m, vals))
+		pad := strings.Repeat(" ", sparkW-utf8.RuneCountInString(line))
+		fmt.Fprintf(w, "%-*s%s%s  max %6s\n", labelW, name, line, pad, formatValue(m, sum[name].Max))
+	}
+	from, to := stamp(first), stamp(last)
+	gap := max(sparkW-len(from)-len(to), 1)
+	fmt.Fprintf(w, "%s%s%s%s\n```\n", strings.Repeat(" ", labelW), from, strings.Repeat(" ", gap), to)
+
+	// Events.
+	fmt.Fprintf(w, "\n## Events\n\n")
+	if len(c.Events) == 0 {
+		fmt.Fprintf(w, "_No events recorded._\n")
+	}
+	for _, e := range c.Events {
+		item := fmt.Sprintf("`%s` **%s**", stamp(e.Time()), mdEscape(e.Kind))
+		if e.Message != "" {
+			item += " " + mdEscape(e.Message)
+		}
+		writeWrapped(w, "- ", item, width)
+	}
+
+	// Anomalies.
+	var found []analysis.Anomaly
+	for _, name := range reportMetrics {
+		m, _ := analysis.LookupMetric(name)
+		found = append(found, analysis.DetectAnomalies(c.Samples, m, sigma)...)
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].Start.Before(found[j].Start) })
+
+	fmt.Fprintf(w, "\n## Anomalies\n\n")
+	writeWrapped(w, "", fmt.Sprintf("Runs of samples more than %gσ above the capture mean.", sigma), width)
+	fmt.Fprintln(w)
+	if len(found) == 0 {
+		fmt.Fprintf(w, "_None detected._\n")
+	}
+	for _, a := range found {
+		span := "`" + stamp(a.Start) + "`"
+		if a.End.After(a.Start) {
+			span += "–`" + stamp(a.End) + "` (" + formatDuration(a.Duration()) + ")"
+		}
+		item := fmt.Sprintf("%s %s peaked at %s at `%s` (mean %s, σ %s)",
+			span, a.Metric.Label, formatValue(a.Metric, a.Peak), stamp(a.PeakAt),
+			formatValue(a.Metric, a.Mean), formatValue(a.Metric, a.Sigma))
+		writeWrapped(w, "- ", item, width)
+	}
+}
+
+// reportStamper returns a timestamp formatter in loc for the span
+// first..last: the clock time alone when the capture stays within one day
+// there, otherwise the date as well.
+func reportStamper(first, last time.Time, loc *time.Location) func(time.Time) string {
+	layout := "2006-01-02 15:04:05"
+	if first.In(loc).Format("2006-01-02") == last.In(loc).Format("2006-01-02") {
+		layout = "15:04:05"
+	}
+	return func(t time.Time) string { return t.In(loc).Format(layout) }
+}
+
+// formatValue renders v in m's unit: "38.7%" or "2.41".
+func formatValue(m analysis.Metric, v float64) string {
+	if m.Unit == "%" {
+		return ui.FormatPercent(v)
+	}
+	return ui.FormatNumber(v, 2)
+}
+
+// mdReplacer backslash-escapes the characters that would otherwise start
+// Markdown emphasis, code, links or HTML in free text.
+var mdReplacer = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`,
+	"[", `\[`, "]", `\]`, "<", `\<`, ">", `\>`, "|", `\|`,
+)
+
+func mdEscape(s string) string { return mdReplacer.Replace(s) }
+
+// writeWrapped word-wraps text to width columns.  The first line starts
+// with prefix; continuation lines are indented by the same amount so list
+// items stay together.
+func writeWrapped(w io.Writer, prefix, text string, width int) {
+	indent := strings.Repeat(" ", utf8.RuneCountInString(prefix))
+	line, n := prefix, utf8.RuneCountInString(prefix)
+	empty := true
+	for _, word := range strings.Fields(text) {
+		wn := utf8.RuneCountInString(word)
+		if !empty && n+1+wn > width {
+			fmt.Fprintln(w, line)
+			line, n, empty = indent, len(indent), true
+		}
+		if !empty {
+			line += " "
+			n++
+		}
+		line += word
+		n += wn
+		empty = false
+	}
+	fmt.Fprintln(w, line)
+}
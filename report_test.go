@@ -0,0 +1,66 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/ALH477/infgo/analysis"
+	"github.com/ALH477/infgo/metrics"
+)
+
+func TestWriteMarkdownReport(t *testing.T) {
+	const startMs = 1704067200000
+	c := &analysis.Capture{Header: &metrics.Header{Hostname: "web_1", Platform: "linux · amd64", NumCores: 4}}
+	for i := 0; i < 300; i++ {
+		cpu := 20 + float64(i%5)
+		if i == 150 {
+			cpu = 99
+		}
+		c.Samples = append(c.Samples, metrics.Sample{
+			TimestampUnixMs: startMs + int64(i)*500,
+			CpuTotal:        cpu,
+			MemPercent:      50,
+		})
+	}
+	c.Events = []metrics.Event{{
+		TimestampUnixMs: startMs + 60_000,
+		Kind:            "marker",
+		Message:         strings.Repeat("a rather long deploy note ", 8),
+	}}
+
+	for _, width := range []int{40, 80, 120} {
+		var buf bytes.Buffer
+		writeMarkdownReport(&buf, c, width, 3, resolveZone("utc", nil))
+		out := buf.String()
+
+		if strings.Contains(out, "\x1b") {
+			t.Fatalf("width %d: report contains ANSI escapes", width)
+		}
+		for _, want := range []string{"# infgo report: web\\_1", "## Summary", "```text\ncpu  ", "**marker**", "CPU % peaked at 99.0%"} {
+			if !strings.Contains(out, want) {
+				t.Errorf("width %d: report missing %q", width, want)
+			}
+		}
+		for i, line := range strings.Split(out, "\n") {
+			if strings.HasPrefix(line, "|") {
+				continue // tables cannot wrap
+			}
+			if n := utf8.RuneCountInString(line); n > width {
+				t.Errorf("width %d: line %d is %d columns: %q", width, i+1, n, line)
+			}
+		}
+	}
+}
+
+func TestWriteWrapped(t *testing.T) {
+	var buf bytes.Buffer
+	writeWrapped(&buf, "- ", "one two three four five", 10)
+	if got, want := buf.String(), "- one two\n  three\n  four\n  five\n"; got != want {
+		t.Errorf("writeWrapped: got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"time"
+
+	syslogger "github.com/ALH477/infgo/logger"
+	"github.com/ALH477/infgo/metrics"
+)
+
+// ── resample ──────────────────────────────────────────────────────────────────
+
+// runResample implements `infgo resample <in.infgo> -every D [-agg A] -o <out>`.
+func runResample(args []string) error {
+	fs := newFlagSet("resample", "<capture.infgo|-> -every <duration> [-agg mean|max] -o <out.infgo>")
+	every := fs.Duration("every", 0, "output resolution, e.g. 30s")
+	aggName := fs.String("agg", "mean", "bucket aggregation: mean or max (max preserves peaks)")
+	out := fs.String("o", "", "write the resampled capture to `file`")
+
+	pos, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 1 {
+		return usageErrorf(fs, "expected exactly one input capture")
+	}
+	if *out == "" {
+		return usageErrorf(fs, "-o is required")
+	}
+	if *every <= 0 {
+		return usageErrorf(fs, "-every is required")
+	}
+	agg, err := metrics.ParseAgg(*aggName)
+	if err != nil {
+		return usageErrorf(fs, "-agg: %v", err)
+	}
+	if samePath(pos[0], *out) {
+		return fmt.Errorf("output %q would overwrite the input", *out)
+	}
+
+	in, written, err := resampleCapture(pos[0], *out, *every, agg)
+	if err != nil {
+		_ = os.Remove(*out)
+		return err
+	}
+	fmt.Printf("infgo: %d samples → %d at %s (%s) → %s\n", in, written, *every, agg, *out)
+	return nil
+}
+
+// resampleCapture streams src through a metrics.Resampler into dst and
+// returns the number of samples read and written.  The output header
+// records the new effective interval.  Events and process records are
+// copied verbatim, at their own resolution, held back just long enough to
+// stay in timestamp order with the bucketed samples.
+func resampleCapture(src, dst string, every time.Duration, agg metrics.Agg) (in, written int, err error) {
+	rs, err := metrics.NewResampler(every, agg)
+	if err != nil {
+		return 0, 0, err
+	}
+	rd, err := openCapture(src)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer rd.Close()
+
+	lgr, err := syslogger.New(dst)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer func() {
+		if cerr := lgr.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	// Each bucket sample is stamped with the bucket start, so events and
+	// processes are written around it: earlier ones before, those inside
+	// the bucket after.
+	var pending []*syslogger.Record
+	writeEventsBefore := func(ms int64) error {
+		i := 0
+		for ; i < len(pending) && recordTime(pending[i]).UnixMilli() < ms; i++ {
+			if err := writeAside(lgr, pending[i]); err != nil {
+				return err
+			}
+		}
+		pending = pending[i:]
+		return nil
+	}
+	emit := func(s metrics.Sample) error {
+		if err := writeEventsBefore(s.TimestampUnixMs); err != nil {
+			return err
+		}
+		if err := lgr.WriteSample(s); err != nil {
+			return err
+		}
+		written++
+		return writeEventsBefore(s.TimestampUnixMs + every.Milliseconds())
+	}
+
+	for {
+		rec, err := rd.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return in, written, err
+		}
+		switch {
+		case rec.Header != nil:
+			h := *rec.Header
+			h.IntervalMs = every.Milliseconds()
+			if err := lgr.WriteHeader(h); err != nil {
+				return in, written, err
+			}
+		case rec.Sample != nil:
+			in++
+			if s, ok := rs.Add(*rec.Sample); ok {
+				if err := emit(s); err != nil {
+					return in, written, err
+				}
+			}
+		case rec.Event != nil || rec.Process != nil:
+			pending = append(pending, rec)
+		}
+	}
+	if s, ok := rs.Flush(); ok {
+		if err := emit(s); err != nil {
+			return in, written, err
+		}
+	}
+	if err := writeEventsBefore(math.MaxInt64); err != nil {
+		return in, written, err
+	}
+	if in == 0 {
+		return 0, 0, fmt.Errorf("%s contains no samples", inputName(src))
+	}
+	return in, written, nil
+}
@@ -0,0 +1,40 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// Process records (-log-pids) are copied at their own resolution, in
+// timestamp order with the buckets around them.
+func TestResampleCaptureProcesses(t *testing.T) {
+	dir := t.TempDir()
+	const startMs = 1704067200000
+	src := writeProcessCapture(t, dir, startMs, 10)
+	dst := filepath.Join(dir, "out.infgo")
+	in, written, err := resampleCapture(src, dst, 5*time.Second, metrics.AggMean)
+	if err != nil {
+		t.Fatalf("resampleCapture failed: %v", err)
+	}
+	if in != 10 || written != 2 {
+		t.Errorf("got %d samples → %d, want 10 → 2", in, written)
+	}
+	if got, want := readProcesses(t, dst), readProcesses(t, src); !reflect.DeepEqual(got, want) {
+		t.Errorf("processes: got %+v, want %+v", got, want)
+	}
+
+	c, err := loadCapture(dst)
+	if err != nil {
+		t.Fatalf("loadCapture failed: %v", err)
+	}
+	if len(c.Samples) != 2 || c.Samples[1].TimestampUnixMs != startMs+5000 {
+		t.Errorf("samples: got %+v", c.Samples)
+	}
+}
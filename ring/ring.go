@@ -0,0 +1,91 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+// Package ring provides the fixed-capacity history buffers behind the TUI's
+// sparklines.  Pushing a reading overwrites the oldest one in place, so a
+// history costs one allocation for the life of the program however often
+// it is updated.
+package ring
+
+// Buffer holds the most recent Cap float64 readings.  The zero value has no
+// capacity; use New.  Copies of a Buffer share storage, so once one of them
+// has been pushed to the others are stale, as with Bubble Tea's models.
+type Buffer struct {
+	buf  []float64
+	head int // index of the oldest reading
+	n    int
+}
+
+// New returns an empty Buffer that keeps the last capacity readings.
+func New(capacity int) Buffer {
+	if capacity < 0 {
+		panic("ring: negative capacity")
+	}
+	return Buffer{buf: make([]float64, capacity)}
+}
+
+// Cap is the number of readings the Buffer keeps.
+func (b *Buffer) Cap() int { return len(b.buf) }
+
+// Len is the number of readings held, at most Cap.
+func (b *Buffer) Len() int { return b.n }
+
+// Push appends v, evicting the oldest reading if the Buffer is full.
+func (b *Buffer) Push(v float64) {
+	if len(b.buf) == 0 {
+		return
+	}
+	if b.n < len(b.buf) {
+		b.buf[(b.head+b.n)%len(b.buf)] = v
+		b.n++
+		return
+	}
+	b.buf[b.head] = v
+	b.head++
+	if b.head == len(b.buf) {
+		b.head = 0
+	}
+}
+
+// Fill sets every slot to v, leaving the Buffer full.  The TUI starts its
+// histories full of zeros so the sparklines have their final width.
+func (b *Buffer) Fill(v float64) {
+	for i := range b.buf {
+		b.buf[i] = v
+	}
+	b.head, b.n = 0, len(b.buf)
+}
+
+// At returns the i-th reading, oldest first.  It panics if i is out of
+// range, like a slice index.
+func (b *Buffer) At(i int) float64 {
+	if i < 0 || i >= b.n {
+		panic("ring: index out of range")
+	}
+	return b.buf[(b.head+i)%len(b.buf)]
+}
+
+// Last returns the reading n pushes before the newest: Last(0) is the
+// newest.  ok is false if fewer than n+1 readings are held.
+func (b *Buffer) Last(n int) (v float64, ok bool) {
+	if n < 0 || n >= b.n {
+		return 0, false
+	}
+	return b.At(b.n - 1 - n), true
+}
+
+// Slice copies the readings, oldest first, into dst and returns it,
+// growing dst only if its capacity is short.  Passing the previous
+// result back in makes repeated calls allocation-free.
+func (b *Buffer) Slice(dst []float64) []float64 {
+	dst = dst[:0]
+	if b.n == 0 {
+		return dst
+	}
+	end := b.head + b.n
+	if end <= len(b.buf) {
+		return append(dst, b.buf[b.head:end]...)
+	}
+	dst = append(dst, b.buf[b.head:]...)
+	return append(dst, b.buf[:end-len(b.buf)]...)
+}
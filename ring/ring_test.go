@@ -0,0 +1,129 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ring
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBuffer(t *testing.T) {
+	tests := []struct {
+		name   string
+		cap    int
+		pushes int // pushes 1, 2, … pushes
+		want   []float64
+	}{
+		{"empty", 3, 0, []float64{}},
+		{"partial", 3, 2, []float64{1, 2}},
+		{"exactly full", 3, 3, []float64{1, 2, 3}},
+		{"wrapped once", 3, 4, []float64{2, 3, 4}},
+		{"wrapped to the start", 3, 6, []float64{4, 5, 6}},
+		{"wrapped many times", 3, 3001, []float64{2999, 3000, 3001}},
+		{"capacity one", 1, 5, []float64{5}},
+		{"capacity zero", 0, 5, []float64{}},
+	}
+	for _, tt := range tests {
+		b := New(tt.cap)
+		for i := 1; i <= tt.pushes; i++ {
+			b.Push(float64(i))
+		}
+		if got := b.Slice(nil); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: Slice got %v, want %v", tt.name, got, tt.want)
+		}
+		if b.Len() != len(tt.want) || b.Cap() != tt.cap {
+			t.Errorf("%s: got Len %d Cap %d, want %d, %d", tt.name, b.Len(), b.Cap(), len(tt.want), tt.cap)
+		}
+		for i, want := range tt.want {
+			if got := b.At(i); got != want {
+				t.Errorf("%s: At(%d) got %v, want %v", tt.name, i, got, want)
+			}
+			if got, ok := b.Last(len(tt.want) - 1 - i); !ok || got != want {
+				t.Errorf("%s: Last(%d) got %v, %v, want %v", tt.name, len(tt.want)-1-i, got, ok, want)
+			}
+		}
+		if _, ok := b.Last(len(tt.want)); ok {
+			t.Errorf("%s: Last(%d) beyond the readings held: got ok", tt.name, len(tt.want))
+		}
+	}
+}
+
+func TestBufferFill(t *testing.T) {
+	b := New(4)
+	b.Push(9)
+	b.Fill(0)
+	if got := b.Slice(nil); !slices.Equal(got, []float64{0, 0, 0, 0}) {
+		t.Fatalf("after Fill: got %v", got)
+	}
+	b.Push(1)
+	b.Push(2)
+	if got := b.Slice(nil); !slices.Equal(got, []float64{0, 0, 1, 2}) {
+		t.Errorf("after Fill and two pushes: got %v", got)
+	}
+}
+
+func TestBufferSliceReusesScratch(t *testing.T) {
+	b := New(5)
+	scratch := make([]float64, 0, 5)
+	for i := range 12 {
+		b.Push(float64(i))
+		got := b.Slice(scratch)
+		if &got[0] != &scratch[:1][0] {
+			t.Fatalf("push %d: Slice reallocated a scratch slice with room", i)
+		}
+		if got[len(got)-1] != float64(i) {
+			t.Fatalf("push %d: newest got %v", i, got[len(got)-1])
+		}
+	}
+	if allocs := testing.AllocsPerRun(100, func() { scratch = b.Slice(scratch) }); allocs != 0 {
+		t.Errorf("Slice into scratch: got %v allocations, want 0", allocs)
+	}
+}
+
+func TestBufferAtPanics(t *testing.T) {
+	b := New(3)
+	b.Push(1)
+	for _, i := range []int{-1, 1, 3} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("At(%d) with one reading: no panic", i)
+				}
+			}()
+			b.At(i)
+		}()
+	}
+}
+
+// BenchmarkPush is the history update the TUI does every tick.
+func BenchmarkPush(b *testing.B) {
+	r := New(38)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		r.Push(float64(i))
+	}
+}
+
+// BenchmarkAppendShift is the append(buf[1:], v) it replaces, which walks
+// the slice along its backing array and reallocates whenever it reaches
+// the end.
+func BenchmarkAppendShift(b *testing.B) {
+	buf := make([]float64, 38)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf = append(buf[1:], float64(i))
+	}
+}
+
+func BenchmarkSlice(b *testing.B) {
+	r := New(38)
+	for i := range 50 {
+		r.Push(float64(i))
+	}
+	var scratch []float64
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		scratch = r.Slice(scratch)
+	}
+}
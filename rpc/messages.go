@@ -0,0 +1,153 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+// Package rpc implements the gRPC service described by
+// proto/infgo_service.proto without generated code.  Messages are encoded
+// with the same hand-authored protowire marshalling as package metrics, and
+// Codec plugs that encoding into grpc-go.  The wire format is standard, so
+// stock clients such as grpcurl work when given the .proto files.
+//
+// The API mirrors what protoc-gen-go-grpc would produce (InfgoServer,
+// RegisterInfgoServer, NewInfgoClient, …) so switching to generated code
+// later only changes imports.
+package rpc
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// ── Message wrappers ──────────────────────────────────────────────────────────
+
+// message is what Codec needs from every type sent over the service.
+type message interface {
+	Marshal() []byte
+	Unmarshal(b []byte) error
+}
+
+// Header wraps metrics.Header as a service message.
+type Header struct{ metrics.Header }
+
+// Unmarshal replaces h with the header encoded in b.
+func (h *Header) Unmarshal(b []byte) (err error) {
+	h.Header, err = metrics.UnmarshalHeader(b)
+	return err
+}
+
+// Sample wraps metrics.Sample as a service message.
+type Sample struct{ metrics.Sample }
+
+// Unmarshal replaces s with the sample encoded in b.
+func (s *Sample) Unmarshal(b []byte) (err error) {
+	s.Sample, err = metrics.UnmarshalSample(b)
+	return err
+}
+
+// InfoRequest is the (empty) argument of GetInfo.
+type InfoRequest struct{}
+
+func (*InfoRequest) Marshal() []byte { return nil }
+
+// Unmarshal accepts and ignores any fields, for forward compatibility.
+func (*InfoRequest) Unmarshal(b []byte) error { return unmarshalInt64s(b, "info request", nil) }
+
+// LiveRequest is the argument of StreamLive.
+type LiveRequest struct {
+	// SinceUnixMs, when non-zero, asks for the buffered samples newer than
+	// it before the live ones.  Zero means live samples only.
+	SinceUnixMs int64
+}
+
+func (r *LiveRequest) Marshal() []byte {
+	return appendInt64(nil, 1, r.SinceUnixMs)
+}
+
+func (r *LiveRequest) Unmarshal(b []byte) error {
+	*r = LiveRequest{}
+	return unmarshalInt64s(b, "live request", map[protowire.Number]*int64{1: &r.SinceUnixMs})
+}
+
+// ReadLogRequest is the argument of ReadLog.
+type ReadLogRequest struct {
+	// Inclusive time range in Unix milliseconds; zero leaves that end open.
+	FromUnixMs int64
+	ToUnixMs   int64
+}
+
+func (r *ReadLogRequest) Marshal() []byte {
+	b := appendInt64(nil, 1, r.FromUnixMs)
+	return appendInt64(b, 2, r.ToUnixMs)
+}
+
+func (r *ReadLogRequest) Unmarshal(b []byte) error {
+	*r = ReadLogRequest{}
+	return unmarshalInt64s(b, "read log request", map[protowire.Number]*int64{1: &r.FromUnixMs, 2: &r.ToUnixMs})
+}
+
+// ── protowire helpers ─────────────────────────────────────────────────────────
+
+// appendInt64 appends an int64 field, omitting it when zero as proto3 does.
+func appendInt64(b []byte, num protowire.Number, v int64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, uint64(v))
+}
+
+// unmarshalInt64s decodes a message made only of int64 fields into the
+// targets keyed by field number, skipping anything else.
+func unmarshalInt64s(b []byte, what string, fields map[protowire.Number]*int64) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return fmt.Errorf("%s: consume tag: %w", what, protowire.ParseError(n))
+		}
+		b = b[n:]
+
+		if dst, ok := fields[num]; ok && typ == protowire.VarintType {
+			v, n := protowire.ConsumeVarint(b)
+			if n < 0 {
+				return fmt.Errorf("%s: field %d: %w", what, num, protowire.ParseError(n))
+			}
+			*dst = int64(v)
+			b = b[n:]
+			continue
+		}
+		n = protowire.ConsumeFieldValue(num, typ, b)
+		if n < 0 {
+			return fmt.Errorf("%s: skip unknown field %d: %w", what, num, protowire.ParseError(n))
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+// ── Codec ─────────────────────────────────────────────────────────────────────
+
+// Codec is a grpc encoding.Codec for the messages of this package.  It
+// registers nothing globally: servers opt in with grpc.ForceServerCodec, and
+// the client stubs pass grpc.ForceCodec on every call.
+type Codec struct{}
+
+// Name reports "proto": on the wire this is ordinary protobuf.
+func (Codec) Name() string { return "proto" }
+
+func (Codec) Marshal(v any) ([]byte, error) {
+	m, ok := v.(message)
+	if !ok {
+		return nil, fmt.Errorf("rpc: cannot marshal %T", v)
+	}
+	return m.Marshal(), nil
+}
+
+func (Codec) Unmarshal(b []byte, v any) error {
+	m, ok := v.(message)
+	if !ok {
+		return fmt.Errorf("rpc: cannot unmarshal into %T", v)
+	}
+	return m.Unmarshal(b)
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package rpc
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+func TestCodecRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		in   message
+		out  message
+	}{
+		{"live", &LiveRequest{SinceUnixMs: 1704067200000}, new(LiveRequest)},
+		{"live zero", &LiveRequest{}, new(LiveRequest)},
+		{"read log", &ReadLogRequest{FromUnixMs: 1000, ToUnixMs: 2000}, new(ReadLogRequest)},
+		{"read log open end", &ReadLogRequest{FromUnixMs: 1000}, new(ReadLogRequest)},
+		{"info", &InfoRequest{}, new(InfoRequest)},
+		{"header", &Header{metrics.Header{Hostname: "node1", NumCores: 8, IntervalMs: 500}}, new(Header)},
+	}
+	var c Codec
+	for _, tt := range tests {
+		b, err := c.Marshal(tt.in)
+		if err != nil {
+			t.Fatalf("%s: Marshal: %v", tt.name, err)
+		}
+		if err := c.Unmarshal(b, tt.out); err != nil {
+			t.Fatalf("%s: Unmarshal: %v", tt.name, err)
+		}
+		if got, want := string(tt.out.Marshal()), string(b); got != want {
+			t.Errorf("%s: round trip changed the encoding: got %x, want %x", tt.name, got, want)
+		}
+	}
+
+	s := &Sample{metrics.Sample{TimestampUnixMs: 42, CpuTotal: 12.5, CpuCores: []float64{10, 15}}}
+	b, _ := c.Marshal(s)
+	var got Sample
+	if err := c.Unmarshal(b, &got); err != nil {
+		t.Fatalf("sample: %v", err)
+	}
+	if got.TimestampUnixMs != 42 || got.CpuTotal != 12.5 || len(got.CpuCores) != 2 {
+		t.Errorf("sample: got %+v", got.Sample)
+	}
+}
+
+func TestRequestSkipsUnknownFields(t *testing.T) {
+	// A newer client may send fields this server does not know about.
+	b := protowire.AppendTag(nil, 9, protowire.BytesType)
+	b = protowire.AppendString(b, "future")
+	b = append(b, (&ReadLogRequest{ToUnixMs: 7}).Marshal()...)
+
+	var r ReadLogRequest
+	if err := r.Unmarshal(b); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if r.ToUnixMs != 7 || r.FromUnixMs != 0 {
+		t.Errorf("got %+v, want {ToUnixMs:7}", r)
+	}
+}
+
+func TestCodecRejectsForeignTypes(t *testing.T) {
+	var c Codec
+	if _, err := c.Marshal("nope"); err == nil {
+		t.Error("Marshal(string): got nil error")
+	}
+	if err := c.Unmarshal(nil, new(int)); err == nil {
+		t.Error("Unmarshal(*int): got nil error")
+	}
+}
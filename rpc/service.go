@@ -0,0 +1,188 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package rpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ServiceName is the fully-qualified name of the Infgo service.
+const ServiceName = "infgo.v1.Infgo"
+
+// ── Server ────────────────────────────────────────────────────────────────────
+
+// InfgoServer is the server API for the Infgo service.
+type InfgoServer interface {
+	GetInfo(context.Context, *InfoRequest) (*Header, error)
+	StreamLive(*LiveRequest, Infgo_StreamLiveServer) error
+	ReadLog(*ReadLogRequest, Infgo_ReadLogServer) error
+}
+
+// Infgo_StreamLiveServer is the server side of a StreamLive call.
+type Infgo_StreamLiveServer interface {
+	Send(*Sample) error
+	grpc.ServerStream
+}
+
+// Infgo_ReadLogServer is the server side of a ReadLog call.
+type Infgo_ReadLogServer interface {
+	Send(*Sample) error
+	grpc.ServerStream
+}
+
+// UnimplementedInfgoServer can be embedded to satisfy InfgoServer with
+// Unimplemented errors for the methods not overridden.
+type UnimplementedInfgoServer struct{}
+
+func (UnimplementedInfgoServer) GetInfo(context.Context, *InfoRequest) (*Header, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetInfo not implemented")
+}
+
+func (UnimplementedInfgoServer) StreamLive(*LiveRequest, Infgo_StreamLiveServer) error {
+	return status.Error(codes.Unimplemented, "method StreamLive not implemented")
+}
+
+func (UnimplementedInfgoServer) ReadLog(*ReadLogRequest, Infgo_ReadLogServer) error {
+	return status.Error(codes.Unimplemented, "method ReadLog not implemented")
+}
+
+// RegisterInfgoServer registers srv on s.  The grpc.Server must have been
+// created with grpc.ForceServerCodec(Codec{}).
+func RegisterInfgoServer(s grpc.ServiceRegistrar, srv InfgoServer) {
+	s.RegisterService(&serviceDesc, srv)
+}
+
+// sampleStream adapts a grpc.ServerStream to both streaming interfaces.
+type sampleStream struct{ grpc.ServerStream }
+
+func (x sampleStream) Send(s *Sample) error { return x.ServerStream.SendMsg(s) }
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*InfgoServer)(nil),
+	Methods: []grpc.MethodDesc{{
+		MethodName: "GetInfo",
+		Handler: func(srv any, ctx context.Context, dec func(any) error, ic grpc.UnaryServerInterceptor) (any, error) {
+			in := new(InfoRequest)
+			if err := dec(in); err != nil {
+				return nil, err
+			}
+			if ic == nil {
+				return srv.(InfgoServer).GetInfo(ctx, in)
+			}
+			info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + ServiceName + "/GetInfo"}
+			return ic(ctx, in, info, func(ctx context.Context, req any) (any, error) {
+				return srv.(InfgoServer).GetInfo(ctx, req.(*InfoRequest))
+			})
+		},
+	}},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamLive",
+			ServerStreams: true,
+			Handler: func(srv any, stream grpc.ServerStream) error {
+				in := new(LiveRequest)
+				if err := stream.RecvMsg(in); err != nil {
+					return err
+				}
+				return srv.(InfgoServer).StreamLive(in, sampleStream{stream})
+			},
+		},
+		{
+			StreamName:    "ReadLog",
+			ServerStreams: true,
+			Handler: func(srv any, stream grpc.ServerStream) error {
+				in := new(ReadLogRequest)
+				if err := stream.RecvMsg(in); err != nil {
+					return err
+				}
+				return srv.(InfgoServer).ReadLog(in, sampleStream{stream})
+			},
+		},
+	},
+	Metadata: "proto/infgo_service.proto",
+}
+
+// ── Client ────────────────────────────────────────────────────────────────────
+
+// InfgoClient is the client API for the Infgo service.
+type InfgoClient interface {
+	GetInfo(ctx context.Context, in *InfoRequest, opts ...grpc.CallOption) (*Header, error)
+	StreamLive(ctx context.Context, in *LiveRequest, opts ...grpc.CallOption) (Infgo_StreamLiveClient, error)
+	ReadLog(ctx context.Context, in *ReadLogRequest, opts ...grpc.CallOption) (Infgo_ReadLogClient, error)
+}
+
+// Infgo_StreamLiveClient is the client side of a StreamLive call.
+type Infgo_StreamLiveClient interface {
+	Recv() (*Sample, error)
+	grpc.ClientStream
+}
+
+// Infgo_ReadLogClient is the client side of a ReadLog call.
+type Infgo_ReadLogClient interface {
+	Recv() (*Sample, error)
+	grpc.ClientStream
+}
+
+type infgoClient struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewInfgoClient returns a client for the Infgo service on cc.  Every call
+// uses Codec; no dial option is needed for it.
+func NewInfgoClient(cc grpc.ClientConnInterface) InfgoClient {
+	return &infgoClient{cc}
+}
+
+func (c *infgoClient) GetInfo(ctx context.Context, in *InfoRequest, opts ...grpc.CallOption) (*Header, error) {
+	out := new(Header)
+	opts = append([]grpc.CallOption{grpc.ForceCodec(Codec{})}, opts...)
+	if err := c.cc.Invoke(ctx, "/"+ServiceName+"/GetInfo", in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *infgoClient) StreamLive(ctx context.Context, in *LiveRequest, opts ...grpc.CallOption) (Infgo_StreamLiveClient, error) {
+	return c.serverStream(ctx, 0, "StreamLive", in, opts)
+}
+
+func (c *infgoClient) ReadLog(ctx context.Context, in *ReadLogRequest, opts ...grpc.CallOption) (Infgo_ReadLogClient, error) {
+	return c.serverStream(ctx, 1, "ReadLog", in, opts)
+}
+
+// serverStream opens the server-streaming method serviceDesc.Streams[i]
+// and sends its single request.  Both stream interfaces have the same
+// method set, so the result serves either.
+func (c *infgoClient) serverStream(ctx context.Context, i int, name string, in message, opts []grpc.CallOption) (Infgo_StreamLiveClient, error) {
+	opts = append([]grpc.CallOption{grpc.ForceCodec(Codec{})}, opts...)
+	stream, err := c.cc.NewStream(ctx, &serviceDesc.Streams[i], "/"+ServiceName+"/"+name, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &sampleClientStream{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// sampleClientStream adapts a grpc.ClientStream to both streaming
+// interfaces.
+type sampleClientStream struct{ grpc.ClientStream }
+
+func (x *sampleClientStream) Recv() (*Sample, error) {
+	m := new(Sample)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
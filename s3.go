@@ -0,0 +1,252 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ── S3 client (PUT and HEAD, Signature Version 4) ─────────────────────────────
+
+const s3Timeout = 5 * time.Minute // a whole segment goes up in one request
+
+// awsCredentials are read from the standard AWS environment variables.
+type awsCredentials struct {
+	id, secret, token string
+}
+
+func awsCredentialsFromEnv() (awsCredentials, error) {
+	c := awsCredentials{
+		id:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		secret: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		token:  os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	if c.id == "" || c.secret == "" {
+		return c, errors.New("-upload needs AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY in the environment")
+	}
+	return c, nil
+}
+
+// s3Client writes objects under one bucket and key prefix.
+type s3Client struct {
+	base      *url.URL // scheme and host of the endpoint
+	pathStyle bool     // bucket in the path (S3-compatible endpoints), not the host
+	bucket    string
+	prefix    string // key prefix without a trailing slash; may be empty
+	region    string
+	creds     awsCredentials
+	client    *http.Client
+	now       func() time.Time
+}
+
+// newS3Client parses an s3://bucket/prefix target.  endpoint, if set, is an
+// S3-compatible service such as MinIO, addressed path-style; otherwise
+// the bucket's AWS regional endpoint is used.
+func newS3Client(target, endpoint string, creds awsCredentials) (*s3Client, error) {
+	u, err := url.Parse(target)
+	if err != nil || u.Scheme != "s3" || u.Host == "" {
+		return nil, fmt.Errorf("-upload: want s3://bucket/prefix, got %q", target)
+	}
+	c := &s3Client{
+		bucket: u.Host,
+		prefix: strings.Trim(u.Path, "/"),
+		region: cmp.Or(os.Getenv("AWS_REGION"), os.Getenv("AWS_DEFAULT_REGION"), "us-east-1"),
+		creds:  creds,
+		client: &http.Client{Timeout: s3Timeout},
+		now:    time.Now,
+	}
+	if endpoint != "" {
+		c.base, err = url.Parse(endpoint)
+		if err != nil || (c.base.Scheme != "http" && c.base.Scheme != "https") || c.base.Host == "" {
+			return nil, fmt.Errorf("-upload-endpoint: want http(s)://host[:port], got %q", endpoint)
+		}
+		c.pathStyle = true
+	} else {
+		c.base = &url.URL{Scheme: "https", Host: c.bucket + ".s3." + c.region + ".amazonaws.com"}
+	}
+	return c, nil
+}
+
+// key is the object key for name under the prefix.
+func (c *s3Client) key(name string) string {
+	if c.prefix == "" {
+		return name
+	}
+	return c.prefix + "/" + name
+}
+
+// objectURL addresses key, keeping the path escaped the way SigV4 expects.
+func (c *s3Client) objectURL(key string) *url.URL {
+	path := "/" + key
+	if c.pathStyle {
+		path = "/" + c.bucket + path
+	}
+	u := *c.base
+	u.Path, u.RawPath = path, s3Escape(path)
+	return &u
+}
+
+// s3Error is a response other than 2xx.
+type s3Error struct {
+	status int
+	code   string // the <Code> of the XML error body, if any
+	msg    string
+}
+
+func (e *s3Error) Error() string {
+	if e.code != "" {
+		return fmt.Sprintf("s3: %d %s: %s", e.status, e.code, e.msg)
+	}
+	return fmt.Sprintf("s3: %d %s", e.status, http.StatusText(e.status))
+}
+
+// s3Retryable reports whether err is worth another attempt: network errors,
+// throttling and server errors are; a bad key or a missing bucket is not.
+func s3Retryable(err error) bool {
+	var se *s3Error
+	if !errors.As(err, &se) {
+		return true
+	}
+	return se.status >= 500 || se.status == http.StatusTooManyRequests || se.status == http.StatusRequestTimeout
+}
+
+// put uploads body as key and returns the ETag the service assigned.
+// Content-MD5 makes the service reject a body damaged in transit.
+func (c *s3Client) put(ctx context.Context, key string, body []byte, contentType string) (etag string, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.objectURL(key).String(), bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	sum := md5.Sum(body)
+	req.Header.Set("Content-MD5", base64.StdEncoding.EncodeToString(sum[:]))
+	req.Header.Set("Content-Type", contentType)
+	c.sign(req, sha256Hex(body))
+	resp, err := c.do(req)
+	if err != nil {
+		return "", err
+	}
+	return resp.Header.Get("ETag"), nil
+}
+
+// size returns the stored length of key.
+func (c *s3Client) size(ctx context.Context, key string) (int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, c.objectURL(key).String(), nil)
+	if err != nil {
+		return 0, err
+	}
+	c.sign(req, sha256Hex(nil))
+	resp, err := c.do(req)
+	if err != nil {
+		return 0, err
+	}
+	return resp.ContentLength, nil
+}
+
+// do sends req and turns a non-2xx response into an *s3Error.
+func (c *s3Client) do(req *http.Request) (*http.Response, error) {
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode/100 == 2 {
+		return resp, nil
+	}
+	var body struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	_ = xml.Unmarshal(raw, &body)
+	return nil, &s3Error{status: resp.StatusCode, code: body.Code, msg: body.Message}
+}
+
+// sign adds the AWS Signature Version 4 Authorization header to req,
+// covering the host and every header already set.
+func (c *s3Client) sign(req *http.Request, payloadHash string) {
+	now := c.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if c.creds.token != "" {
+		req.Header.Set("X-Amz-Security-Token", c.creds.token)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for k, v := range req.Header {
+		headers[strings.ToLower(k)] = strings.TrimSpace(strings.Join(v, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var canonHeaders strings.Builder
+	for _, k := range names {
+		canonHeaders.WriteString(k + ":" + headers[k] + "\n")
+	}
+	signed := strings.Join(names, ";")
+
+	canonical := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		"", // no query string: only object PUT and HEAD are used
+		canonHeaders.String(),
+		signed,
+		payloadHash,
+	}, "\n")
+	scope := now.Format("20060102") + "/" + c.region + "/s3/aws4_request"
+	toSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonical))
+
+	key := []byte("AWS4" + c.creds.secret)
+	for _, part := range []string{now.Format("20060102"), c.region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	sig := hex.EncodeToString(hmacSHA256(key, toSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		c.creds.id, scope, signed, sig))
+}
+
+// s3Escape percent-encodes everything but the unreserved characters and
+// "/", as SigV4 requires for S3 object paths.
+func s3Escape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		if 'A' <= ch && ch <= 'Z' || 'a' <= ch && ch <= 'z' || '0' <= ch && ch <= '9' || strings.IndexByte("-_.~/", ch) >= 0 {
+			b.WriteByte(ch)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", ch)
+		}
+	}
+	return b.String()
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
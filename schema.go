@@ -0,0 +1,64 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// ── infgo schema ──────────────────────────────────────────────────────────────
+
+func runSchema(args []string) error { return schema(args, os.Stdout) }
+
+// schema prints metrics.proto to w: this build's own copy, or with a
+// capture argument the copy -log-schema embedded in it, which describes
+// the samples as they were written.
+func schema(args []string, w io.Writer) error {
+	fs := newFlagSet("schema", "[file.infgo]")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	switch fs.NArg() {
+	case 0:
+		_, err := io.WriteString(w, metrics.Schema)
+		return err
+	case 1:
+	default:
+		return usageErrorf(fs, "unexpected argument %q", fs.Arg(1))
+	}
+
+	path := fs.Arg(0)
+	rd, err := openCapture(path)
+	if err != nil {
+		return err
+	}
+	defer rd.Close()
+	// The schema follows the header, so reading stops at the first sample.
+	for {
+		rec, err := rd.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if text, ok := rd.Schema(); ok {
+			_, err := io.WriteString(w, text)
+			return err
+		}
+		if rec.Sample != nil {
+			break
+		}
+	}
+	return fmt.Errorf("%s has no embedded schema; it was not recorded with -log-schema", inputName(path))
+}
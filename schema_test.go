@@ -0,0 +1,55 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	syslogger "github.com/ALH477/infgo/logger"
+	"github.com/ALH477/infgo/metrics"
+)
+
+func TestSchemaCommand(t *testing.T) {
+	dir := t.TempDir()
+	plain := writeTestCapture(t, dir, 1000, 3)
+	embedded := filepath.Join(dir, "embedded.infgo")
+	lgr, err := syslogger.New(embedded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lgr.EmbedSchema()
+	lgr.WriteHeader(metrics.Header{Hostname: "h", NumCores: 2})
+	lgr.WriteSample(metrics.Sample{TimestampUnixMs: 1000})
+	if err := lgr.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    string // the output, if err is empty
+		wantErr string
+	}{
+		{"built in", nil, metrics.Schema, ""},
+		{"embedded", []string{embedded}, metrics.Schema, ""},
+		{"not embedded", []string{plain}, "", "has no embedded schema"},
+		{"two captures", []string{plain, embedded}, "", "usage"},
+	}
+	for _, tt := range tests {
+		var out strings.Builder
+		err := schema(tt.args, &out)
+		switch {
+		case tt.wantErr != "":
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: got error %v, want %q", tt.name, err, tt.wantErr)
+			}
+		case err != nil:
+			t.Errorf("%s: %v", tt.name, err)
+		case out.String() != tt.want:
+			t.Errorf("%s: got %d bytes of output, want the %d of metrics.Schema", tt.name, out.Len(), len(tt.want))
+		}
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+// ── systemd notify (Type=notify) ──────────────────────────────────────────────
+
+// sdStatusEvery is how often the headless collector refreshes the STATUS=
+// line shown by `systemctl status`.
+const sdStatusEvery = 10 * time.Second
+
+// sdNotifier sends state changes to the service manager over the datagram
+// socket named by $NOTIFY_SOCKET.  A nil notifier, as returned when not
+// started by systemd, ignores everything.
+type sdNotifier struct {
+	conn   *net.UnixConn
+	failed bool // a send has failed and been reported
+}
+
+// newSDNotifier connects to $NOTIFY_SOCKET.  A leading "@" names a socket
+// in the abstract namespace.
+func newSDNotifier() (*sdNotifier, error) {
+	path := os.Getenv("NOTIFY_SOCKET")
+	if path == "" {
+		return nil, nil
+	}
+	if path[0] == '@' {
+		path = "\x00" + path[1:]
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		return nil, fmt.Errorf("NOTIFY_SOCKET: %w", err)
+	}
+	return &sdNotifier{conn: conn}, nil
+}
+
+// notify sends newline-separated assignments such as "READY=1".  Only
+// the first failure is reported; systemd going away must not spam stderr
+// twice a second.
+func (n *sdNotifier) notify(state string) {
+	if n == nil {
+		return
+	}
+	if _, err := n.conn.Write([]byte(state)); err != nil && !n.failed {
+		n.failed = true
+		fmt.Fprintf(os.Stderr, "infgo: sd_notify: %v\n", err)
+	}
+}
+
+func (n *sdNotifier) Close() error {
+	if n == nil {
+		return nil
+	}
+	return n.conn.Close()
+}
+
+// sdWatchdogInterval is how often to send WATCHDOG=1: half of the
+// WatchdogSec= that systemd passes as $WATCHDOG_USEC.  It is zero when the
+// watchdog is off or meant for another process.
+func sdWatchdogInterval() time.Duration {
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0
+	}
+	return time.Duration(usec) * time.Microsecond / 2
+}
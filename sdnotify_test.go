@@ -0,0 +1,156 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeSystemd listens where $NOTIFY_SOCKET points.  Socket paths are
+// limited to about 100 bytes, so it lives in a short temp directory rather
+// than t.TempDir.
+func fakeSystemd(t *testing.T) *net.UnixConn {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "sd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	path := filepath.Join(dir, "notify")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	t.Setenv("NOTIFY_SOCKET", path)
+	return conn
+}
+
+// nextState returns the next datagram, failing after timeout.
+func nextState(t *testing.T, conn *net.UnixConn, timeout time.Duration) string {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(timeout))
+	buf := make([]byte, 4096)
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("no notification: %v", err)
+	}
+	return string(buf[:n])
+}
+
+func TestSDNotify(t *testing.T) {
+	sys := fakeSystemd(t)
+	sd, err := newSDNotifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sd.Close()
+	sd.notify("READY=1\nSTATUS=hello")
+	if got := nextState(t, sys, time.Second); got != "READY=1\nSTATUS=hello" {
+		t.Errorf("got %q", got)
+	}
+
+	t.Setenv("NOTIFY_SOCKET", "")
+	sd, err = newSDNotifier()
+	if sd != nil || err != nil {
+		t.Errorf("without NOTIFY_SOCKET: got %v, %v; want nil, nil", sd, err)
+	}
+	sd.notify("READY=1") // a nil notifier is a no-op
+	sd.Close()
+}
+
+func TestSDNotifyAbstractSocket(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("abstract sockets are Linux-only")
+	}
+	name := "infgo-test-" + strconv.Itoa(os.Getpid())
+	sys, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: "\x00" + name, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sys.Close()
+	t.Setenv("NOTIFY_SOCKET", "@"+name)
+	sd, err := newSDNotifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sd.Close()
+	sd.notify("WATCHDOG=1")
+	if got := nextState(t, sys, time.Second); got != "WATCHDOG=1" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestSDWatchdogInterval(t *testing.T) {
+	self := strconv.Itoa(os.Getpid())
+	tests := []struct {
+		usec, pid string
+		want      time.Duration
+	}{
+		{"", "", 0},
+		{"0", "", 0},
+		{"junk", "", 0},
+		{"30000000", "", 15 * time.Second},
+		{"30000000", self, 15 * time.Second},
+		{"30000000", "1", 0}, // meant for another process
+	}
+	for _, tt := range tests {
+		t.Setenv("WATCHDOG_USEC", tt.usec)
+		t.Setenv("WATCHDOG_PID", tt.pid)
+		if got := sdWatchdogInterval(); got != tt.want {
+			t.Errorf("WATCHDOG_USEC=%q WATCHDOG_PID=%q: got %s, want %s", tt.usec, tt.pid, got, tt.want)
+		}
+	}
+}
+
+func TestHeadlessNotifiesSystemd(t *testing.T) {
+	sys := fakeSystemd(t)
+	t.Setenv("WATCHDOG_USEC", "200000")
+	t.Setenv("WATCHDOG_PID", "")
+	sd, err := newSDNotifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sd.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	h := headless{sd: sd}
+	go func() { done <- h.run(ctx) }()
+
+	// WATCHDOG=1 every 100 ms, with READY=1 once the first sample is in.
+	var ready string
+	var pings int
+	for ready == "" || pings < 2 {
+		switch st := nextState(t, sys, 5*time.Second); {
+		case st == "WATCHDOG=1":
+			pings++
+		case strings.HasPrefix(st, "READY=1\n"):
+			ready = st
+		default:
+			t.Fatalf("unexpected notification %q", st)
+		}
+	}
+	if !strings.Contains(ready, "STATUS=1 samples taken; cpu ") {
+		t.Errorf("ready: got %q, want a status line", ready)
+	}
+
+	cancel()
+	for {
+		if st := nextState(t, sys, 5*time.Second); st == "STOPPING=1" {
+			break
+		}
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,275 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/signal"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ui"
+)
+
+// ── selftest ──────────────────────────────────────────────────────────────────
+
+// `infgo selftest` checks the collector against a load it makes itself:
+// it samples the idle machine, then keeps N goroutines busy at a duty
+// cycle and holds M GiB while it samples again, and reports whether CPU
+// and memory rose by what was asked of them.  The load average cannot be
+// moved by so short a test, so that subsystem only has to be readable.
+// Its exit status is 1 if any subsystem failed, which suits a CI job on a
+// real runner.
+
+const (
+	// selftestBaseline is how long the idle machine is sampled.
+	selftestBaseline = 2 * time.Second
+
+	// dutyPeriod is the time over which a worker keeps its duty cycle.
+	dutyPeriod = 10 * time.Millisecond
+
+	// selftestHeadroom is the share of the free memory -mem may take.
+	selftestHeadroom = 0.5
+)
+
+// selftestLoad is the load under test: busy goroutines and a held
+// allocation.  stop releases both.
+type selftestLoad struct {
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	mem    []byte
+	once   sync.Once
+}
+
+// startLoad allocates memBytes and writes to every page, each something
+// different so that no page can be shared with another, then starts workers goroutines, each locked to its own thread
+// and busy for duty (0–1) of every dutyPeriod, until ctx is done or stop
+// is called.
+func startLoad(ctx context.Context, workers int, duty float64, memBytes int64) *selftestLoad {
+	ctx, cancel := context.WithCancel(ctx)
+	l := &selftestLoad{cancel: cancel}
+	if memBytes > 0 {
+		l.mem = make([]byte, memBytes)
+		for i := 0; i+8 <= len(l.mem); i += os.Getpagesize() {
+			binary.LittleEndian.PutUint64(l.mem[i:], uint64(i))
+		}
+	}
+	busy := time.Duration(duty * float64(dutyPeriod))
+	for range workers {
+		l.wg.Add(1)
+		go func() {
+			defer l.wg.Done()
+			runtime.LockOSThread()
+			defer runtime.UnlockOSThread()
+			for ctx.Err() == nil {
+				start := time.Now()
+				for time.Since(start) < busy {
+					// spin
+				}
+				if busy < dutyPeriod {
+					time.Sleep(dutyPeriod - busy)
+				}
+			}
+		}()
+	}
+	return l
+}
+
+// stop ends the workers, waits for them and returns the memory to the
+// operating system.  It may be called more than once.
+func (l *selftestLoad) stop() {
+	l.once.Do(func() {
+		l.cancel()
+		l.wg.Wait()
+		l.mem = nil
+		debug.FreeOSMemory()
+	})
+}
+
+// selftestTarget is the load asked for.
+type selftestTarget struct {
+	workers   int
+	duty      float64 // 0–1
+	memGiB    float64
+	cpuTol    float64 // percentage points
+	memTolPct float64 // percent of memGiB
+}
+
+// selftestResult is the verdict on one subsystem.
+type selftestResult struct {
+	name   string
+	pass   bool
+	detail string
+}
+
+// evaluateSelftest compares the readings taken under load with those of
+// the idle machine.  CPU should rise by workers × duty of the cores the
+// readings count, and memory use by memGiB; a subsystem that was not
+// asked to move (no workers, no memory) only has to be read.
+func evaluateSelftest(idle, loaded []ui.Reading, want selftestTarget) []selftestResult {
+	mean := func(rs []ui.Reading, g metrics.Missing, v func(ui.Reading) float64) (float64, int) {
+		var sum float64
+		n := 0
+		for _, r := range rs {
+			if !r.Missing.Has(g) {
+				sum += v(r)
+				n++
+			}
+		}
+		if n == 0 {
+			return 0, 0
+		}
+		return sum / float64(n), n
+	}
+	unread := func(name string) selftestResult {
+		return selftestResult{name, false, "could not be read"}
+	}
+	var out []selftestResult
+
+	cpuPct := func(r ui.Reading) float64 { return r.CPUTotal }
+	idleCPU, ni := mean(idle, metrics.MissingCPU, cpuPct)
+	gotCPU, nl := mean(loaded, metrics.MissingCPU, cpuPct)
+	cores := 0
+	for _, r := range loaded {
+		cores = max(cores, len(r.CPUCores))
+	}
+	switch {
+	case ni == 0 || nl == 0 || cores == 0:
+		out = append(out, unread("cpu"))
+	case want.workers == 0:
+		out = append(out, selftestResult{"cpu", true, fmt.Sprintf("read %d times; no load asked for", ni+nl)})
+	default:
+		wantCPU := math.Min(100, idleCPU+100*float64(want.workers)*want.duty/float64(cores))
+		out = append(out, selftestResult{"cpu", math.Abs(gotCPU-wantCPU) <= want.cpuTol,
+			fmt.Sprintf("measured %s, expected %s ± %s (%s idle, %d cores)",
+				ui.FormatPercent(gotCPU), ui.FormatPercent(wantCPU), ui.FormatNumber(want.cpuTol, 0), ui.FormatPercent(idleCPU), cores)})
+	}
+
+	memGiB := func(r ui.Reading) float64 { return r.MemUsedGB }
+	idleMem, ni := mean(idle, metrics.MissingMem, memGiB)
+	gotMem, nl := mean(loaded, metrics.MissingMem, memGiB)
+	switch {
+	case ni == 0 || nl == 0:
+		out = append(out, unread("mem"))
+	case want.memGiB == 0:
+		out = append(out, selftestResult{"mem", true, fmt.Sprintf("read %d times; no load asked for", ni+nl)})
+	default:
+		rise, tol := gotMem-idleMem, want.memGiB*want.memTolPct/100
+		out = append(out, selftestResult{"mem", math.Abs(rise-want.memGiB) <= tol,
+			fmt.Sprintf("rose %s, expected %s ± %s%%",
+				ui.FormatBytes(rise*ui.BytesPerGiB), ui.FormatBytes(want.memGiB*ui.BytesPerGiB), ui.FormatNumber(want.memTolPct, 0))})
+	}
+
+	_, ni = mean(idle, metrics.MissingLoad, func(r ui.Reading) float64 { return r.Load1 })
+	_, nl = mean(loaded, metrics.MissingLoad, func(r ui.Reading) float64 { return r.Load1 })
+	n := len(idle) + len(loaded)
+	return append(out, selftestResult{"load", ni+nl == n, fmt.Sprintf("read %d of %d times", ni+nl, n)})
+}
+
+// sampleUntil takes a reading every ui.DefaultInterval for d, or until ctx is
+// done.  The first, a delta that may straddle a change in load, is only
+// the CPU's starting point.
+func sampleUntil(ctx context.Context, d time.Duration, read func() ui.Reading) []ui.Reading {
+	read()
+	tick := time.NewTicker(min(ui.DefaultInterval, d))
+	defer tick.Stop()
+	deadline := time.Now().Add(d)
+	var out []ui.Reading
+	for {
+		select {
+		case <-ctx.Done():
+			return out
+		case <-tick.C:
+		}
+		out = append(out, read())
+		if !time.Now().Before(deadline) {
+			return out
+		}
+	}
+}
+
+// runSelftest implements `infgo selftest`.
+func runSelftest(args []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return selftest(ctx, args, os.Stdout, func() ui.Reading { return readStats(ctx) })
+}
+
+// selftest is runSelftest with the output and the source of readings
+// injected.
+func selftest(ctx context.Context, args []string, w io.Writer, read func() ui.Reading) error {
+	fs := newFlagSet("selftest", "[-cpu N] [-duty P] [-mem GiB] [-duration d]")
+	workers := fs.Int("cpu", max(1, runtime.NumCPU()/2), "keep `N` goroutines busy (0: no CPU load)")
+	dutyPct := fs.Float64("duty", 50, "busy `percent` of each worker's time")
+	memGiB := fs.Float64("mem", 1, "hold `GiB` of memory (0: no memory load)")
+	duration := fs.Duration("duration", 10*time.Second, "sample under load for `d`")
+	cpuTol := fs.Float64("cpu-tolerance", 10, "pass CPU within `N` percentage points of the expected use")
+	memTol := fs.Float64("mem-tolerance", 25, "pass memory within `N` percent of -mem")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	switch {
+	case fs.NArg() > 0:
+		return usageErrorf(fs, "unexpected argument %q", fs.Arg(0))
+	case *workers < 0 || *memGiB < 0:
+		return usageErrorf(fs, "-cpu and -mem must not be negative")
+	case *dutyPct <= 0 || *dutyPct > 100:
+		return usageErrorf(fs, "-duty must be a percentage above 0")
+	case *duration <= 0:
+		return usageErrorf(fs, "-duration must be positive")
+	case *cpuTol < 0 || *memTol < 0:
+		return usageErrorf(fs, "tolerances must not be negative")
+	}
+	want := selftestTarget{workers: *workers, duty: *dutyPct / 100, memGiB: *memGiB, cpuTol: *cpuTol, memTolPct: *memTol}
+
+	fmt.Fprintf(w, "infgo selftest: sampling the idle machine for %s\n", selftestBaseline)
+	idle := sampleUntil(ctx, selftestBaseline, read)
+	if ctx.Err() != nil {
+		return errors.New("interrupted")
+	}
+	if len(idle) > 0 {
+		last := idle[len(idle)-1]
+		if free := last.MemTotalGB - last.MemUsedGB; !last.Missing.Has(metrics.MissingMem) && want.memGiB > free*selftestHeadroom {
+			return fmt.Errorf("-mem %s is more than half of the %s free", ui.FormatBytes(want.memGiB*ui.BytesPerGiB), ui.FormatBytes(free*ui.BytesPerGiB))
+		}
+	}
+
+	fmt.Fprintf(w, "infgo selftest: %d workers at %s duty, %s held, for %s\n",
+		want.workers, ui.FormatPercent(*dutyPct), ui.FormatBytes(want.memGiB*ui.BytesPerGiB), *duration)
+	load := startLoad(ctx, want.workers, want.duty, int64(want.memGiB*ui.BytesPerGiB))
+	loaded := sampleUntil(ctx, *duration, read)
+	load.stop()
+	if ctx.Err() != nil {
+		return errors.New("interrupted; the load has been released")
+	}
+
+	results := evaluateSelftest(idle, loaded, want)
+	failed := 0
+	for _, r := range results {
+		verdict := "PASS"
+		if !r.pass {
+			verdict = "FAIL"
+			failed++
+		}
+		fmt.Fprintf(w, "  %-5s %s  %s\n", r.name, verdict, r.detail)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d subsystems failed", failed, len(results))
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ui"
+)
+
+func TestEvaluateSelftest(t *testing.T) {
+	// Four cores at 5 % and 2 GiB used while idle.
+	idle := []ui.Reading{
+		{CPUTotal: 4, CPUCores: make([]float64, 4), MemUsedGB: 2, Load1: 0.1},
+		{CPUTotal: 6, CPUCores: make([]float64, 4), MemUsedGB: 2, Load1: 0.1},
+	}
+	loaded := func(cpu, memGB float64, missing metrics.Missing) []ui.Reading {
+		return []ui.Reading{{CPUTotal: cpu, CPUCores: make([]float64, 4), MemUsedGB: memGB, Load1: 0.5, Missing: missing}}
+	}
+	// Two workers at half duty are a quarter of four cores: 30 % in all.
+	want := selftestTarget{workers: 2, duty: 0.5, memGiB: 1, cpuTol: 10, memTolPct: 25}
+	tests := []struct {
+		name   string
+		loaded []ui.Reading
+		want   selftestTarget
+		pass   [3]bool // cpu, mem, load
+		detail string
+	}{
+		{"on target", loaded(31, 2.95, 0), want, [3]bool{true, true, true}, "measured 31.0%, expected 30.0% ± 10 (5.0% idle, 4 cores)"},
+		{"cpu low", loaded(12, 3, 0), want, [3]bool{false, true, true}, ""},
+		{"mem short", loaded(30, 2.5, 0), want, [3]bool{true, false, true}, "rose 512.00 MiB, expected 1.00 GiB ± 25%"},
+		{"no load asked for", loaded(5, 2, 0), selftestTarget{}, [3]bool{true, true, true}, "read 3 times; no load asked for"},
+		{"unreadable", loaded(0, 0, metrics.MissingCPU|metrics.MissingLoad), want, [3]bool{false, false, false}, "could not be read"},
+		{"nothing under load", nil, want, [3]bool{false, false, true}, "read 2 of 2 times"},
+	}
+	for _, tt := range tests {
+		got := evaluateSelftest(idle, tt.loaded, tt.want)
+		if len(got) != 3 {
+			t.Fatalf("%s: got %d results, want 3", tt.name, len(got))
+		}
+		var details []string
+		for i, r := range got {
+			if r.name != [...]string{"cpu", "mem", "load"}[i] || r.pass != tt.pass[i] {
+				t.Errorf("%s: got %+v", tt.name, r)
+			}
+			details = append(details, r.detail)
+		}
+		if all := strings.Join(details, "\n"); !strings.Contains(all, tt.detail) {
+			t.Errorf("%s: missing %q in\n%s", tt.name, tt.detail, all)
+		}
+	}
+}
+
+// The load is released by stop, or by its context ending, and the
+// workers are gone once it returns.
+func TestSelftestLoad(t *testing.T) {
+	before := runtime.NumGoroutine()
+	l := startLoad(context.Background(), 3, 0.2, 1<<20)
+	if len(l.mem) != 1<<20 || runtime.NumGoroutine() < before+3 {
+		t.Fatalf("got %d bytes, %d goroutines", len(l.mem), runtime.NumGoroutine())
+	}
+	l.stop()
+	l.stop()
+	if l.mem != nil || runtime.NumGoroutine() > before {
+		t.Errorf("after stop: %d bytes, %d goroutines, want none over %d", len(l.mem), runtime.NumGoroutine(), before)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	l = startLoad(ctx, 2, 1, 0)
+	cancel()
+	done := make(chan struct{})
+	go func() { l.wg.Wait(); close(done) }()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("workers still running after their context ended")
+	}
+	l.stop()
+}
+
+func TestSelftestFlags(t *testing.T) {
+	read := func() ui.Reading { return ui.Reading{} }
+	for _, args := range [][]string{{"-cpu", "-1"}, {"-duty", "0"}, {"-duty", "101"}, {"-duration", "0"}, {"-mem-tolerance", "-5"}, {"extra"}} {
+		var out bytes.Buffer
+		if err := selftest(context.Background(), args, &out, read); err != errUsage {
+			t.Errorf("%v: got %v, want errUsage", args, err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var out bytes.Buffer
+	if err := selftest(ctx, nil, &out, read); err == nil || !strings.Contains(err.Error(), "interrupted") {
+		t.Errorf("interrupted: got %v", err)
+	}
+}
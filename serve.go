@@ -0,0 +1,485 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ui"
+)
+
+// ── Live state ────────────────────────────────────────────────────────────────
+
+// liveState is the hand-off point between the sampler (the TUI's Update
+// loop or the headless collector) and the HTTP server.  Writers and readers
+// only copy values while holding the mutex, so a slow scrape can never hold
+// up sampling.
+type liveState struct {
+	mu   sync.RWMutex
+	hdr  *metrics.Header
+	hist []metrics.Sample // oldest first; the last one is the latest sample
+	took time.Duration    // collection time of the latest sample
+	at   time.Time        // when it was published; zero before the first
+
+	// points are the sampler's sparkline histories, for
+	// /api/v1/history.csv; see HistTrail.
+	points []metrics.Sample
+
+	// Streaming clients.  closing is closed on server shutdown; streams
+	// counts the handlers still running so shutdown can wait for them.
+	subs      map[*subscriber]struct{}
+	closing   chan struct{}
+	closeOnce sync.Once
+	streams   sync.WaitGroup
+
+	clock func() time.Time // time.Now outside tests
+
+	// interval is the sampling interval, which sizes the history; set it
+	// before the first sample.
+	interval time.Duration
+}
+
+func newLiveState() *liveState {
+	return &liveState{
+		subs:     make(map[*subscriber]struct{}),
+		closing:  make(chan struct{}),
+		clock:    time.Now,
+		interval: ui.DefaultInterval,
+	}
+}
+
+// liveSnapshot is a consistent copy of liveState.
+type liveSnapshot struct {
+	Header *metrics.Header // nil until host info is known
+	Sample metrics.Sample
+	Took   time.Duration
+	Age    time.Duration
+}
+
+func (l *liveState) setHeader(h metrics.Header) {
+	l.mu.Lock()
+	l.hdr = &h
+	l.mu.Unlock()
+}
+
+// liveHistoryLen bounds the samples kept for /api/v1/history: five minutes
+// at the default interval.  This is independent of the TUI's sparkline
+// buffers, which hold only HistoryLen readings without timestamps.
+const liveHistoryLen = int(5 * time.Minute / ui.DefaultInterval)
+
+// HistoryLen is the bound at l's interval, which keeps five minutes too.
+func (l *liveState) historyLen() int {
+	return int(time.Duration(liveHistoryLen) * ui.DefaultInterval / l.interval)
+}
+
+func (l *liveState) setSample(s metrics.Sample, took time.Duration) {
+	s.CpuCores = append([]float64(nil), s.CpuCores...)
+	now := l.clock()
+	l.mu.Lock()
+	if len(l.hist) == l.historyLen() {
+		copy(l.hist, l.hist[1:])
+		l.hist = l.hist[:len(l.hist)-1]
+	}
+	l.hist = append(l.hist, s)
+	l.took, l.at = took, now
+	for sub := range l.subs {
+		sub.offer(liveUpdate{Sample: &s})
+	}
+	l.mu.Unlock()
+}
+
+// setPoints publishes the points of the sampler's histories, which are
+// not mutated afterwards.
+func (l *liveState) setPoints(points []metrics.Sample) {
+	l.mu.Lock()
+	l.points = points
+	l.mu.Unlock()
+}
+
+// publishEvent forwards ev (a marker, an alert transition, …) to streaming
+// clients.  Events are not buffered for /api/v1/history.
+func (l *liveState) publishEvent(ev metrics.Event) {
+	l.mu.RLock()
+	for sub := range l.subs {
+		sub.offer(liveUpdate{Event: &ev})
+	}
+	l.mu.RUnlock()
+}
+
+// snapshot returns the latest sample, or false before the first one.
+func (l *liveState) snapshot() (liveSnapshot, bool) {
+	l.mu.RLock()
+	snap := liveSnapshot{Header: l.hdr, Took: l.took}
+	if n := len(l.hist); n > 0 {
+		snap.Sample = l.hist[n-1]
+	}
+	at := l.at
+	l.mu.RUnlock()
+	if at.IsZero() {
+		return snap, false
+	}
+	snap.Age = l.clock().Sub(at)
+	return snap, true
+}
+
+// history returns the buffered samples taken within window of the latest
+// one, oldest first; window <= 0 means everything buffered.  Samples are
+// never mutated after setSample, so sharing their CpuCores is safe.
+func (l *liveState) history(window time.Duration) []metrics.Sample {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if len(l.hist) == 0 {
+		return nil
+	}
+	i := 0
+	if window > 0 {
+		cutoff := l.hist[len(l.hist)-1].TimestampUnixMs - window.Milliseconds()
+		for i < len(l.hist) && l.hist[i].TimestampUnixMs < cutoff {
+			i++
+		}
+	}
+	return append([]metrics.Sample(nil), l.hist[i:]...)
+}
+
+// ── Subscribers ───────────────────────────────────────────────────────────────
+
+// subscriberQueue is how many updates a streaming client may fall behind
+// before the oldest are dropped.
+const subscriberQueue = 16
+
+// liveUpdate is one item of a streaming client's queue: exactly one of
+// Sample and Event is set.
+type liveUpdate struct {
+	Sample *metrics.Sample
+	Event  *metrics.Event
+}
+
+// subscriber is one streaming client's queue of updates not yet sent.
+type subscriber struct {
+	ch      chan liveUpdate
+	dropped atomic.Int64
+}
+
+// offer queues u without ever blocking: when the client is behind, the
+// oldest queued update makes room.  Concurrent producers cannot starve each
+// other for long, as each pass through the loop either queues or drops.
+func (sub *subscriber) offer(u liveUpdate) {
+	for {
+		select {
+		case sub.ch <- u:
+			return
+		default:
+		}
+		select {
+		case <-sub.ch:
+			sub.dropped.Add(1)
+		default:
+		}
+	}
+}
+
+// subscribe registers a streaming client, or returns nil once the server is
+// shutting down.  Every successful call must be paired with unsubscribe.
+func (l *liveState) subscribe() *subscriber {
+	sub, _ := l.subscribeAfter(-1)
+	return sub
+}
+
+// subscribeAfter is subscribe for a reconnecting client: it also returns
+// the buffered samples newer than afterMs, taken under the same lock so
+// none is both replayed and queued, or neither.  afterMs < 0 replays none.
+func (l *liveState) subscribeAfter(afterMs int64) (*subscriber, []metrics.Sample) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	select {
+	case <-l.closing:
+		return nil, nil
+	default:
+	}
+	sub := &subscriber{ch: make(chan liveUpdate, subscriberQueue)}
+	l.subs[sub] = struct{}{}
+	l.streams.Add(1)
+
+	var replay []metrics.Sample
+	if afterMs >= 0 {
+		i := len(l.hist)
+		for i > 0 && l.hist[i-1].TimestampUnixMs > afterMs {
+			i--
+		}
+		replay = append(replay, l.hist[i:]...)
+	}
+	return sub, replay
+}
+
+func (l *liveState) unsubscribe(sub *subscriber) {
+	l.mu.Lock()
+	delete(l.subs, sub)
+	l.mu.Unlock()
+	l.streams.Done()
+}
+
+// clientCount is the number of attached streaming clients, for the footer.
+func (l *liveState) clientCount() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return len(l.subs)
+}
+
+// closeStreams tells every streaming handler to say goodbye and waits for
+// them to finish.  http.Server.Shutdown does not track hijacked
+// connections, so this is their equivalent.
+func (l *liveState) closeStreams() {
+	l.closeOnce.Do(func() { close(l.closing) })
+	l.streams.Wait()
+}
+
+// ── HTTP endpoints ────────────────────────────────────────────────────────────
+
+// staleAfter is how old the latest sample may get before /healthz fails:
+// several missed ticks, so one slow gopsutil call does not flap the check.
+const staleAfter = 5 * ui.DefaultInterval
+
+// serveConfig holds the flags that shape the network listeners.
+type serveConfig struct {
+	addr       string // -listen
+	corsOrigin string // -cors; empty sends no CORS headers
+	grpcAddr   string // -grpc-listen
+
+	tlsCert, tlsKey string     // serve both listeners over TLS
+	clientCA        string     // -grpc-listen requires client certificates signed by this CA
+	auth            listenAuth // required on everything but /healthz
+}
+
+// enabled reports whether any listener was requested.
+func (cfg serveConfig) enabled() bool { return cfg.addr != "" || cfg.grpcAddr != "" }
+
+// newServeMux routes the -listen endpoints.  Everything except /healthz,
+// which load balancers probe anonymously, and CORS preflights, which
+// browsers send without credentials, is behind cfg.auth.
+func newServeMux(live *liveState, cfg serveConfig) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", cfg.auth.guard(http.HandlerFunc(live.handleMetrics)))
+	mux.HandleFunc("GET /healthz", live.handleHealthz)
+
+	api := func(pattern string, h http.HandlerFunc) {
+		mux.Handle(pattern, withCORS(cfg.corsOrigin, cfg.auth.guard(h)))
+	}
+	api("GET /api/v1/now", live.handleNow)
+	api("GET /api/v1/history", live.handleHistory)
+	api("GET /api/v1/history.csv", live.handleHistoryCSV)
+	api("GET /api/v1/sse", live.handleSSE(sseHeartbeat))
+	mux.Handle("GET /api/v1/stream", cfg.auth.guard(live.handleStream(cfg.corsOrigin)))
+	if cfg.corsOrigin != "" {
+		mux.Handle("OPTIONS /api/v1/", withCORS(cfg.corsOrigin, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		})))
+	}
+	return mux
+}
+
+// withCORS lets browsers on origin read the API's responses and answers
+// preflight requests.  With origin empty it returns h unchanged.
+func withCORS(origin string, h http.Handler) http.Handler {
+	if origin == "" {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Authorization")
+		if origin != "*" {
+			w.Header().Add("Vary", "Origin")
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// handleMetrics serves the latest sample in Prometheus text format.
+func (l *liveState) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	snap, ok := l.snapshot()
+	if !ok {
+		http.Error(w, "no sample collected yet", http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = metrics.WritePrometheus(w, snap.Header, &snap.Sample,
+		metrics.PromGauge{
+			Name:  "infgo_scrape_duration_seconds",
+			Help:  "Time taken to collect the latest sample.",
+			Value: snap.Took.Seconds(),
+		},
+		metrics.PromGauge{
+			Name:  "infgo_sample_age_seconds",
+			Help:  "Seconds since the latest sample was collected; large values mean sampling has stalled.",
+			Value: snap.Age.Seconds(),
+		},
+	)
+}
+
+// handleHealthz reports 200 while samples keep arriving, 503 otherwise.
+func (l *liveState) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	snap, ok := l.snapshot()
+	switch {
+	case !ok:
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintln(w, "starting: no sample collected yet")
+	case snap.Age > staleAfter:
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintf(w, "stale: last sample %s ago\n", snap.Age.Round(time.Millisecond))
+	default:
+		fmt.Fprintln(w, "ok")
+	}
+}
+
+// ── JSON API ──────────────────────────────────────────────────────────────────
+
+// writeJSON sends v with the given status.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// apiError is the body of every non-2xx /api response.
+type apiError struct {
+	Error string `json:"error"`
+}
+
+// handleNow serves the latest header and sample.
+func (l *liveState) handleNow(w http.ResponseWriter, r *http.Request) {
+	snap, ok := l.snapshot()
+	if !ok {
+		writeJSON(w, http.StatusServiceUnavailable, apiError{"no sample collected yet"})
+		return
+	}
+	writeJSON(w, http.StatusOK, nowJSON{snap.Header, snap.Sample, snap.Age.Seconds()})
+}
+
+// nowJSON is the /api/v1/now response; -connect decodes it too.
+type nowJSON struct {
+	Header *metrics.Header `json:"header"` // null until host info is known
+	Sample metrics.Sample  `json:"sample"`
+	AgeS   float64         `json:"age_s"`
+}
+
+// historyJSON is the /api/v1/history response: parallel arrays, one entry
+// per buffered sample.
+type historyJSON struct {
+	WindowS    float64     `json:"window_s"`   // as requested; 0 for everything
+	CoveredS   float64     `json:"covered_s"`  // span actually returned
+	CapacityS  float64     `json:"capacity_s"` // the most the buffer ever holds
+	IntervalMs int64       `json:"interval_ms"`
+	Timestamps []int64     `json:"timestamps_unix_ms"`
+	CPU        []float64   `json:"cpu"`
+	Mem        []float64   `json:"mem"`
+	Cores      [][]float64 `json:"cores,omitempty"` // cores[i][j]: core i at Timestamps[j]
+}
+
+// handleHistory serves `?window=5m[&cores=1]`.  A window longer than the
+// buffer is not an error; covered_s and capacity_s say what was available.
+func (l *liveState) handleHistory(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	var window time.Duration
+	if v := q.Get("window"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			writeJSON(w, http.StatusBadRequest, apiError{fmt.Sprintf("bad window %q: want a positive duration such as 5m", v)})
+			return
+		}
+		window = d
+	}
+	var cores bool
+	if v := q.Get("cores"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, apiError{fmt.Sprintf("bad cores %q: want true or false", v)})
+			return
+		}
+		cores = b
+	}
+
+	hist := l.history(window)
+	resp := historyJSON{
+		WindowS:    window.Seconds(),
+		CapacityS:  (time.Duration(l.historyLen()) * l.interval).Seconds(),
+		IntervalMs: l.interval.Milliseconds(),
+		Timestamps: make([]int64, len(hist)),
+		CPU:        make([]float64, len(hist)),
+		Mem:        make([]float64, len(hist)),
+	}
+	if n := len(hist); n > 0 {
+		resp.CoveredS = float64(hist[n-1].TimestampUnixMs-hist[0].TimestampUnixMs) / 1000
+		if cores {
+			resp.Cores = make([][]float64, len(hist[n-1].CpuCores))
+			for i := range resp.Cores {
+				resp.Cores[i] = make([]float64, n)
+			}
+		}
+	}
+	for j, s := range hist {
+		resp.Timestamps[j] = s.TimestampUnixMs
+		resp.CPU[j] = s.CpuTotal
+		resp.Mem[j] = s.MemPercent
+		for i := range resp.Cores {
+			if i < len(s.CpuCores) {
+				resp.Cores[i][j] = s.CpuCores[i]
+			}
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// ── Server lifecycle ──────────────────────────────────────────────────────────
+
+// startServer binds addr immediately, so a port clash is reported before
+// the TUI takes over the terminal, and then serves in the background.  The
+// returned stop function shuts the server down, streaming clients included.
+func startServer(cfg serveConfig, live *liveState) (stop func(), err error) {
+	conf, err := serverTLS(cfg.tlsCert, cfg.tlsKey, "")
+	if err != nil {
+		return nil, err
+	}
+	ln, err := net.Listen("tcp", cfg.addr)
+	if err != nil {
+		return nil, fmt.Errorf("listen %s: %w", cfg.addr, err)
+	}
+	return serveHTTP(ln, conf, cfg, live), nil
+}
+
+// serveHTTP serves the -listen endpoints on ln, over TLS when conf is
+// non-nil, until the returned stop function is called.
+func serveHTTP(ln net.Listener, conf *tls.Config, cfg serveConfig, live *liveState) (stop func()) {
+	if conf != nil {
+		ln = tls.NewListener(ln, conf)
+	}
+	srv := &http.Server{
+		Handler:           newServeMux(live, cfg),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() { _ = srv.Serve(ln) }() // returns ErrServerClosed on shutdown
+	return func() { stopServer(srv, live) }
+}
+
+// stopServer ends the streaming clients first, since Shutdown would
+// otherwise wait on the SSE handlers, then gives in-flight requests a
+// moment to finish before closing.
+func stopServer(srv *http.Server, live *liveState) {
+	live.closeStreams()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		_ = srv.Close()
+	}
+}
@@ -0,0 +1,217 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ui"
+)
+
+func TestServeEndpoints(t *testing.T) {
+	now := time.Unix(1704067200, 0)
+	live := newLiveState()
+	live.clock = func() time.Time { return now }
+	srv := httptest.NewServer(newServeMux(live, serveConfig{}))
+	defer srv.Close()
+
+	get := func(path string) (int, string) {
+		t.Helper()
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	// Before the first sample both endpoints report unavailable.
+	for _, path := range []string{"/metrics", "/healthz"} {
+		if code, _ := get(path); code != http.StatusServiceUnavailable {
+			t.Errorf("%s before first sample: got %d, want 503", path, code)
+		}
+	}
+
+	live.setHeader(metrics.Header{Hostname: "node1", Platform: "linux", NumCores: 2})
+	live.setSample(metrics.Sample{
+		TimestampUnixMs: now.UnixMilli(),
+		CpuTotal:        42.5,
+		CpuCores:        []float64{30, 55},
+		MemPercent:      61.8,
+	}, 12*time.Millisecond)
+	now = now.Add(time.Second)
+
+	code, body := get("/metrics")
+	if code != http.StatusOK {
+		t.Fatalf("/metrics: got %d, want 200", code)
+	}
+	for _, want := range []string{
+		`infgo_info{hostname="node1",platform="linux"} 1`,
+		"infgo_cpu_usage_percent 42.5",
+		`infgo_cpu_core_usage_percent{core="1"} 55`,
+		"infgo_scrape_duration_seconds 0.012",
+		"infgo_sample_age_seconds 1",
+	} {
+		if !strings.Contains(body, want+"\n") {
+			t.Errorf("/metrics: missing %q in\n%s", want, body)
+		}
+	}
+
+	if code, body := get("/healthz"); code != http.StatusOK || body != "ok\n" {
+		t.Errorf("/healthz fresh: got %d %q, want 200 \"ok\\n\"", code, body)
+	}
+	now = now.Add(staleAfter)
+	if code, body := get("/healthz"); code != http.StatusServiceUnavailable || !strings.HasPrefix(body, "stale") {
+		t.Errorf("/healthz stale: got %d %q, want 503 stale", code, body)
+	}
+
+	if code, _ := get("/nope"); code != http.StatusNotFound {
+		t.Errorf("/nope: got %d, want 404", code)
+	}
+}
+
+func TestStartServerPortInUse(t *testing.T) {
+	stop, err := startServer(serveConfig{addr: "127.0.0.1:0"}, newLiveState())
+	if err != nil {
+		t.Fatalf("startServer: %v", err)
+	}
+	defer stop()
+
+	ln := httptest.NewUnstartedServer(nil).Listener
+	defer ln.Close()
+	if _, err := startServer(serveConfig{addr: ln.Addr().String()}, newLiveState()); err == nil {
+		t.Error("startServer on a bound port: got nil error")
+	}
+}
+
+func TestAPIEndpoints(t *testing.T) {
+	start := time.Unix(1704067200, 0)
+	now := start
+	live := newLiveState()
+	live.clock = func() time.Time { return now }
+	srv := httptest.NewServer(newServeMux(live, serveConfig{corsOrigin: "*"}))
+	defer srv.Close()
+
+	getJSON := func(path string, v any) (int, http.Header) {
+		t.Helper()
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		defer resp.Body.Close()
+		if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("%s Content-Type: got %q, want application/json", path, ct)
+		}
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatalf("%s: decode: %v", path, err)
+		}
+		return resp.StatusCode, resp.Header
+	}
+
+	var e apiError
+	if code, _ := getJSON("/api/v1/now", &e); code != http.StatusServiceUnavailable || e.Error == "" {
+		t.Errorf("/api/v1/now before first sample: got %d %+v, want 503 with error", code, e)
+	}
+
+	// 20 s of samples, one every 500 ms.
+	live.setHeader(metrics.Header{Hostname: "node1", NumCores: 2})
+	for i := 0; i < 40; i++ {
+		now = start.Add(time.Duration(i) * ui.DefaultInterval)
+		live.setSample(metrics.Sample{
+			TimestampUnixMs: now.UnixMilli(),
+			CpuTotal:        float64(i),
+			CpuCores:        []float64{float64(i), float64(2 * i)},
+			MemPercent:      50,
+		}, time.Millisecond)
+	}
+
+	var snap struct {
+		Header *metrics.Header `json:"header"`
+		Sample metrics.Sample  `json:"sample"`
+	}
+	code, hdr := getJSON("/api/v1/now", &snap)
+	if code != http.StatusOK {
+		t.Fatalf("/api/v1/now: got %d, want 200", code)
+	}
+	if got := hdr.Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("/api/v1/now CORS: got %q, want *", got)
+	}
+	if snap.Header == nil || snap.Header.Hostname != "node1" || snap.Sample.CpuTotal != 39 || len(snap.Sample.CpuCores) != 2 {
+		t.Errorf("/api/v1/now: got %+v %+v", snap.Header, snap.Sample)
+	}
+
+	tests := []struct {
+		query    string
+		wantN    int
+		wantCov  float64
+		wantCore bool
+	}{
+		{"", 40, 19.5, false},
+		{"?window=5s", 11, 5, false},
+		{"?window=5s&cores=1", 11, 5, true},
+		{"?window=1h", 40, 19.5, false}, // only what the buffer holds
+	}
+	for _, tt := range tests {
+		var h historyJSON
+		if code, _ := getJSON("/api/v1/history"+tt.query, &h); code != http.StatusOK {
+			t.Errorf("history%s: got %d, want 200", tt.query, code)
+			continue
+		}
+		if len(h.Timestamps) != tt.wantN || len(h.CPU) != tt.wantN || len(h.Mem) != tt.wantN {
+			t.Errorf("history%s: got %d/%d/%d points, want %d", tt.query, len(h.Timestamps), len(h.CPU), len(h.Mem), tt.wantN)
+			continue
+		}
+		if h.CoveredS != tt.wantCov {
+			t.Errorf("history%s covered_s: got %v, want %v", tt.query, h.CoveredS, tt.wantCov)
+		}
+		if h.CPU[tt.wantN-1] != 39 {
+			t.Errorf("history%s: last cpu got %v, want 39", tt.query, h.CPU[tt.wantN-1])
+		}
+		if tt.wantCore {
+			if len(h.Cores) != 2 || len(h.Cores[1]) != tt.wantN || h.Cores[1][tt.wantN-1] != 78 {
+				t.Errorf("history%s cores: got %v", tt.query, h.Cores)
+			}
+		} else if h.Cores != nil {
+			t.Errorf("history%s: got cores %v, want none", tt.query, h.Cores)
+		}
+	}
+
+	for _, q := range []string{"?window=soon", "?window=-5m", "?cores=maybe"} {
+		if code, _ := getJSON("/api/v1/history"+q, &e); code != http.StatusBadRequest {
+			t.Errorf("history%s: got %d, want 400", q, code)
+		}
+	}
+
+	req, _ := http.NewRequest(http.MethodOptions, srv.URL+"/api/v1/history", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("OPTIONS: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent || resp.Header.Get("Access-Control-Allow-Methods") == "" {
+		t.Errorf("preflight: got %d %v, want 204 with CORS headers", resp.StatusCode, resp.Header)
+	}
+}
+
+func TestLiveHistoryBounded(t *testing.T) {
+	live := newLiveState()
+	for i := 0; i < liveHistoryLen+10; i++ {
+		live.setSample(metrics.Sample{TimestampUnixMs: int64(i)}, 0)
+	}
+	hist := live.history(0)
+	if len(hist) != liveHistoryLen {
+		t.Fatalf("history length: got %d, want %d", len(hist), liveHistoryLen)
+	}
+	if hist[0].TimestampUnixMs != 10 {
+		t.Errorf("oldest sample: got %d, want 10", hist[0].TimestampUnixMs)
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"time"
+
+	syslogger "github.com/ALH477/infgo/logger"
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ui"
+)
+
+// ── Ship to a collector (-ship) ───────────────────────────────────────────────
+
+const (
+	// collectPort is where `infgo collect` listens by default.
+	collectPort = "7070"
+
+	// shipTimeout bounds each connection attempt and write.
+	shipTimeout = 5 * time.Second
+)
+
+// errShipBackoff drops a sample while a reconnect is pending; the dial
+// error that caused the wait stays the one reported.
+var errShipBackoff = errors.New("ship: waiting to reconnect")
+
+// shipWriter streams the capture to an `infgo collect` aggregator over one
+// long-lived TCP connection, in the same format as `-log -`.  Each new
+// connection starts with the magic bytes and the header, so the collector
+// needs nothing else to name the host's files.
+type shipWriter struct {
+	addr string
+	dial func() (net.Conn, error)
+
+	header   *metrics.Header
+	conn     net.Conn // nil while disconnected
+	lgr      *syslogger.Logger
+	backoff  time.Duration
+	nextDial time.Time
+	lost     atomic.Int64
+	lastFail atomic.Value // string
+}
+
+// newShipWriter validates -ship.  A bare host gets collectPort.
+func newShipWriter(addr string) (*shipWriter, error) {
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, collectPort)
+	}
+	if host, _, _ := net.SplitHostPort(addr); host == "" {
+		return nil, fmt.Errorf("-ship: want host[:port], got %q", addr)
+	}
+	w := &shipWriter{addr: addr}
+	w.dial = func() (net.Conn, error) { return net.DialTimeout("tcp", w.addr, shipTimeout) }
+	return w, nil
+}
+
+func (w *shipWriter) dropped() int64 { return w.lost.Load() }
+
+func (w *shipWriter) lastError() string {
+	s, _ := w.lastFail.Load().(string)
+	return s
+}
+
+func (w *shipWriter) run(in <-chan pushRecord) {
+	for rec := range in {
+		if rec.header != nil {
+			w.header = rec.header
+			continue
+		}
+		if err := w.send(&rec.sample); err != nil {
+			w.lost.Add(1)
+			if err != errShipBackoff {
+				w.lastFail.Store(err.Error())
+			}
+		}
+	}
+	w.disconnect()
+}
+
+// send writes one sample.  A connection the collector has closed is
+// replaced first; one that breaks during the write is replaced and the
+// write tried once more.
+func (w *shipWriter) send(s *metrics.Sample) error {
+	if w.conn != nil && peerClosed(w.conn) {
+		w.disconnect()
+	}
+	for attempt := 0; attempt < 2; attempt++ {
+		if err := w.connect(); err != nil {
+			return err
+		}
+		w.conn.SetWriteDeadline(time.Now().Add(shipTimeout))
+		err := w.lgr.WriteSample(*s)
+		if err == nil {
+			err = w.lgr.Flush()
+		}
+		if err == nil {
+			return nil
+		}
+		w.disconnect()
+		if attempt == 1 {
+			return fmt.Errorf("ship: %w", err)
+		}
+	}
+	return nil
+}
+
+// connect dials if there is no connection and sends the stream preamble.
+// Failed dials back off like -graphite's, dropping samples meanwhile.
+func (w *shipWriter) connect() error {
+	if w.conn != nil {
+		return nil
+	}
+	if w.header == nil {
+		return errors.New("ship: no header yet")
+	}
+	if time.Now().Before(w.nextDial) {
+		return errShipBackoff
+	}
+	conn, err := w.dial()
+	if err == nil {
+		conn.SetWriteDeadline(time.Now().Add(shipTimeout))
+		var lgr *syslogger.Logger
+		if lgr, err = syslogger.NewWriter(conn); err == nil {
+			if err = lgr.WriteHeader(*w.header); err == nil {
+				err = lgr.Flush()
+			}
+		}
+		if err == nil {
+			w.conn, w.lgr, w.backoff, w.nextDial = conn, lgr, 0, time.Time{}
+			return nil
+		}
+		conn.Close()
+	}
+	w.backoff = ui.NextBackoff(w.backoff)
+	w.nextDial = time.Now().Add(w.backoff)
+	return fmt.Errorf("ship: %w", err)
+}
+
+func (w *shipWriter) disconnect() {
+	if w.conn == nil {
+		return
+	}
+	w.conn.Close()
+	w.conn, w.lgr = nil, nil
+}
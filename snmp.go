@@ -0,0 +1,100 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+// ── TCP retransmits ───────────────────────────────────────────────────────────
+//
+// The NET panel's retrans badge: the TCP segments retransmitted a second,
+// from the RetransSegs counter in /proc/net/snmp, read with the interfaces'
+// counters.  A burst of them explains many a slow moment that throughput
+// does not: a lossy link, a full queue, a peer gone quiet.  The badge is
+// dim at zero, amber from -retrans-warn and red from -retrans-crit, and
+// the count since infgo started stays beside the errors and drops.  Off
+// Linux there is no counter to read, and no badge.
+
+// errSNMPFormat is a /proc/net/snmp whose lines do not pair up.
+var errSNMPFormat = errors.New("/proc/net/snmp: malformed")
+
+// parseSNMP reads the counters of proto, as "Tcp", from a /proc/net/snmp.
+// Each protocol has two lines, both led by its name and a colon: one
+// naming its counters and the next with their values.  Some counters, as
+// Tcp's MaxConn, can be -1.
+func parseSNMP(r io.Reader, proto string) (map[string]int64, error) {
+	prefix := proto + ":"
+	var names []string
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		f := strings.Fields(sc.Text())
+		if len(f) == 0 || f[0] != prefix {
+			if names != nil {
+				return nil, fmt.Errorf("%w: %s names without values", errSNMPFormat, proto)
+			}
+			continue
+		}
+		if names == nil {
+			names = f[1:]
+			continue
+		}
+		if len(f)-1 != len(names) {
+			return nil, fmt.Errorf("%w: %d %s names, %d values", errSNMPFormat, len(names), proto, len(f)-1)
+		}
+		out := make(map[string]int64, len(names))
+		for i, v := range f[1:] {
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("/proc/net/snmp: %s %s: %w", proto, names[i], err)
+			}
+			out[names[i]] = n
+		}
+		return out, nil
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if names != nil {
+		return nil, fmt.Errorf("%w: %s names without values", errSNMPFormat, proto)
+	}
+	return nil, fmt.Errorf("/proc/net/snmp: no %s lines", proto)
+}
+
+// tcpRetrans is the TCP segments retransmitted since boot, from a
+// /proc/net/snmp.
+func tcpRetrans(r io.Reader) (uint64, error) {
+	tcp, err := parseSNMP(r, "Tcp")
+	if err != nil {
+		return 0, err
+	}
+	v, ok := tcp["RetransSegs"]
+	if !ok || v < 0 {
+		return 0, errors.New("/proc/net/snmp: no Tcp RetransSegs")
+	}
+	return uint64(v), nil
+}
+
+// readRetrans reads the retransmits into msg, with the NET panel's
+// counters.  They back off on their own, and off Linux the source is nil.
+func (r *statsReader) readRetrans(ctx context.Context, msg *ui.Reading) {
+	if !r.netOn || r.src.retrans == nil || !r.snmp.due(r.now()) {
+		return
+	}
+	n, err := query(ctx, &r.snmp, r.timeout, r.src.retrans)
+	if err != nil {
+		r.snmp.failed(r.now())
+		return
+	}
+	r.snmp.recovered()
+	msg.Retrans, msg.RetransAt = n, r.now()
+}
@@ -0,0 +1,22 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+//go:build linux
+
+package main
+
+import (
+	"context"
+	"os"
+)
+
+// readTCPRetrans reads the TCP segments retransmitted since boot from
+// /proc/net/snmp.
+func readTCPRetrans(context.Context) (uint64, error) {
+	f, err := os.Open("/proc/net/snmp")
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	return tcpRetrans(f)
+}
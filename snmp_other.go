@@ -0,0 +1,14 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+//go:build !linux
+
+package main
+
+import (
+	"context"
+)
+
+// readTCPRetrans is nil here: the counter is read from /proc/net/snmp
+// only, and the NET panel has no retrans badge.
+var readTCPRetrans func(context.Context) (uint64, error)
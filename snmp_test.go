@@ -0,0 +1,73 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	psnet "github.com/shirou/gopsutil/v3/net"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+func TestParseSNMP(t *testing.T) {
+	f, err := os.Open("testdata/snmp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	tcp, err := parseSNMP(f, "Tcp")
+	if err != nil || tcp["RetransSegs"] != 184467 || tcp["MaxConn"] != -1 || tcp["InCsumErrors"] != 0 || len(tcp) != 15 {
+		t.Errorf("got %v, %v", tcp, err)
+	}
+	for _, tt := range []struct {
+		name, in string
+	}{
+		{"no Tcp lines", "Ip: Forwarding\nIp: 1\n"},
+		{"names without values", "Tcp: RtoAlgorithm RetransSegs\nUdp: InDatagrams\nUdp: 4\n"},
+		{"names at the end", "Ip: Forwarding\nIp: 1\nTcp: RtoAlgorithm RetransSegs\n"},
+		{"too few values", "Tcp: RtoAlgorithm RetransSegs\nTcp: 1\n"},
+		{"too many values", "Tcp: RtoAlgorithm RetransSegs\nTcp: 1 2 3\n"},
+		{"not a number", "Tcp: RtoAlgorithm RetransSegs\nTcp: 1 many\n"},
+		{"empty", ""},
+	} {
+		if got, err := parseSNMP(strings.NewReader(tt.in), "Tcp"); err == nil {
+			t.Errorf("%s: got %v", tt.name, got)
+		}
+	}
+	if n, err := tcpRetrans(strings.NewReader("Tcp: RtoAlgorithm RetransSegs\nTcp: 1 62\n")); n != 62 || err != nil {
+		t.Errorf("tcpRetrans: got %d, %v", n, err)
+	}
+	if n, err := tcpRetrans(strings.NewReader("Tcp: RtoAlgorithm\nTcp: 1\n")); err == nil {
+		t.Errorf("no RetransSegs: got %d", n)
+	}
+}
+
+func TestReadRetrans(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	r := fakeReader(&fakeSources{}, &now)
+	r.src.retrans = func(context.Context) (uint64, error) { return 62, nil }
+	r.src.nics = func(context.Context, bool) ([]psnet.IOCountersStat, error) { return nil, nil }
+	r.src.ifaces = func(context.Context) (psnet.InterfaceStatList, error) { return nil, nil }
+	if msg := r.read(context.Background()); !msg.RetransAt.IsZero() {
+		t.Errorf("without the NET panel: got %d", msg.Retrans)
+	}
+	r.netOn = true
+	if msg := r.read(context.Background()); msg.Retrans != 62 || !msg.RetransAt.Equal(now) {
+		t.Errorf("got %d at %v", msg.Retrans, msg.RetransAt)
+	}
+	r.src.retrans = func(context.Context) (uint64, error) { return 0, errors.New("gone") }
+	now = now.Add(ui.DefaultInterval)
+	if msg := r.read(context.Background()); !msg.RetransAt.IsZero() || msg.Missing != 0 {
+		t.Errorf("failing: got %v, missing %q", msg.RetransAt, msg.Missing)
+	}
+	if r.snmp.due(now) {
+		t.Error("a failed reading did not back off")
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ── Server-Sent Events stream ─────────────────────────────────────────────────
+
+// sseHeartbeat is the interval between keep-alive comments; proxies tend to
+// close connections that have been idle for 30-60 s.
+const sseHeartbeat = 15 * time.Second
+
+// handleSSE serves /api/v1/sse: an `event: sample` message for every new
+// sample, with the sample timestamp as its id, and an `event: event` message
+// for every marker or alert.  A reconnecting client's Last-Event-ID replays
+// the buffered samples it missed before the live feed resumes.
+func (l *liveState) handleSSE(heartbeat time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		fl, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming not supported", http.StatusInternalServerError)
+			return
+		}
+
+		// An id we did not issue is treated as a fresh connection.
+		after := int64(-1)
+		if v := r.Header.Get("Last-Event-ID"); v != "" {
+			if n, err := strconv.ParseInt(v, 10, 64); err == nil && n >= 0 {
+				after = n
+			}
+		}
+		sub, replay := l.subscribeAfter(after)
+		if sub == nil {
+			http.Error(w, "server shutting down", http.StatusServiceUnavailable)
+			return
+		}
+		defer l.unsubscribe(sub)
+
+		h := w.Header()
+		h.Set("Content-Type", "text/event-stream")
+		h.Set("Cache-Control", "no-cache")
+		h.Set("X-Accel-Buffering", "no") // keep nginx from buffering the stream
+		w.WriteHeader(http.StatusOK)
+		fl.Flush()
+
+		// Every message is flushed as soon as it is written; without that the
+		// client would see nothing until net/http's buffer filled up.
+		send := func(event string, id int64, v any) error {
+			if err := writeSSE(w, event, id, v); err != nil {
+				return err
+			}
+			fl.Flush()
+			return nil
+		}
+		for i := range replay {
+			if err := send("sample", replay[i].TimestampUnixMs, &replay[i]); err != nil {
+				return
+			}
+		}
+
+		hb := time.NewTicker(heartbeat)
+		defer hb.Stop()
+		for {
+			var err error
+			select {
+			case u := <-sub.ch:
+				if u.Sample != nil {
+					err = send("sample", u.Sample.TimestampUnixMs, u.Sample)
+				} else {
+					err = send("event", -1, u.Event)
+				}
+			case <-hb.C:
+				if _, err = io.WriteString(w, ": keepalive\n\n"); err == nil {
+					fl.Flush()
+				}
+			case <-r.Context().Done():
+				return
+			case <-l.closing:
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+// writeSSE writes one message.  JSON never contains a raw newline, so the
+// data always fits on a single data: line.  id < 0 omits the id field.
+func writeSSE(w io.Writer, event string, id int64, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if id >= 0 {
+		if _, err := fmt.Fprintf(w, "id: %d\n", id); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+	return err
+}
@@ -0,0 +1,184 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// flushRecorder is a ResponseWriter that only exposes what has been
+// flushed, the way a client on the other end of the connection would.
+type flushRecorder struct {
+	header http.Header
+
+	mu      sync.Mutex
+	buf     bytes.Buffer // everything written
+	flushed string       // buf as of the last Flush
+	flushes int
+	code    int
+}
+
+func newFlushRecorder() *flushRecorder {
+	return &flushRecorder{header: http.Header{}}
+}
+
+func (fr *flushRecorder) Header() http.Header { return fr.header }
+
+func (fr *flushRecorder) WriteHeader(code int) {
+	fr.mu.Lock()
+	fr.code = code
+	fr.mu.Unlock()
+}
+
+func (fr *flushRecorder) Write(p []byte) (int, error) {
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+	return fr.buf.Write(p)
+}
+
+func (fr *flushRecorder) Flush() {
+	fr.mu.Lock()
+	fr.flushed = fr.buf.String()
+	fr.flushes++
+	fr.mu.Unlock()
+}
+
+// waitFor polls until the flushed output contains want.
+func (fr *flushRecorder) waitFor(t *testing.T, want string) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		fr.mu.Lock()
+		got := fr.flushed
+		fr.mu.Unlock()
+		if strings.Contains(got, want) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("flushed output never contained %q; got:\n%s", want, got)
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+}
+
+// startSSE runs the SSE handler against fr until the returned cancel is
+// called, which waits for the handler to return.
+func startSSE(live *liveState, fr *flushRecorder, heartbeat time.Duration, lastID string) (cancel func()) {
+	ctx, stop := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/sse", nil).WithContext(ctx)
+	if lastID != "" {
+		req.Header.Set("Last-Event-ID", lastID)
+	}
+	done := make(chan struct{})
+	go func() {
+		live.handleSSE(heartbeat)(fr, req)
+		close(done)
+	}()
+	return func() { stop(); <-done }
+}
+
+func TestSSEFlushesEachMessage(t *testing.T) {
+	live := newLiveState()
+	fr := newFlushRecorder()
+	cancel := startSSE(live, fr, time.Hour, "")
+	waitClients(t, live, 1)
+
+	live.setSample(metrics.Sample{TimestampUnixMs: 1000, CpuTotal: 12.5}, 0)
+	fr.waitFor(t, "id: 1000\nevent: sample\ndata: {\"timestamp_unix_ms\":1000,\"cpu_total\":12.5,")
+	live.publishEvent(metrics.Event{TimestampUnixMs: 1200, Kind: "alert", Message: "cpu > 90"})
+	fr.waitFor(t, "event: event\ndata: {\"timestamp_unix_ms\":1200,\"kind\":\"alert\",\"message\":\"cpu \\u003e 90\"}\n\n")
+	live.setSample(metrics.Sample{TimestampUnixMs: 1500}, 0)
+	fr.waitFor(t, "id: 1500\n")
+	cancel()
+
+	if fr.code != http.StatusOK {
+		t.Errorf("status: got %d, want 200", fr.code)
+	}
+	if ct := fr.header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type: got %q", ct)
+	}
+	if fr.buf.String() != fr.flushed {
+		t.Errorf("unflushed output left behind: %q", strings.TrimPrefix(fr.buf.String(), fr.flushed))
+	}
+	// One flush for the headers, then one per message.
+	if fr.flushes != 4 {
+		t.Errorf("flushes: got %d, want 4", fr.flushes)
+	}
+	if strings.Contains(fr.flushed, "id: 1200") {
+		t.Error("event messages must not carry an id")
+	}
+	if n := live.clientCount(); n != 0 {
+		t.Errorf("clients after disconnect: got %d, want 0", n)
+	}
+}
+
+func TestSSEReplaysAfterLastEventID(t *testing.T) {
+	live := newLiveState()
+	for ts := int64(1000); ts <= 5000; ts += 1000 {
+		live.setSample(metrics.Sample{TimestampUnixMs: ts}, 0)
+	}
+
+	tests := []struct {
+		lastID string
+		want   []string
+	}{
+		{"3000", []string{"id: 4000", "id: 5000"}},
+		{"5000", nil},
+		{"0", []string{"id: 1000", "id: 2000", "id: 3000", "id: 4000", "id: 5000"}},
+		{"", nil},
+		{"bogus", nil},
+	}
+	for _, tt := range tests {
+		fr := newFlushRecorder()
+		cancel := startSSE(live, fr, time.Hour, tt.lastID)
+		waitClients(t, live, 1)
+		live.setSample(metrics.Sample{TimestampUnixMs: 9000}, 0)
+		fr.waitFor(t, "id: 9000")
+		cancel()
+
+		var got []string
+		for _, line := range strings.Split(fr.flushed, "\n") {
+			if strings.HasPrefix(line, "id: ") && line != "id: 9000" {
+				got = append(got, line)
+			}
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("Last-Event-ID %q: replayed %v, want %v", tt.lastID, got, tt.want)
+		}
+		live.hist = live.hist[:5] // forget the 9000 sample for the next case
+	}
+}
+
+func TestSSEHeartbeat(t *testing.T) {
+	live := newLiveState()
+	fr := newFlushRecorder()
+	cancel := startSSE(live, fr, 5*time.Millisecond, "")
+	fr.waitFor(t, ": keepalive\n\n")
+	cancel()
+}
+
+func TestSSEShutdown(t *testing.T) {
+	live := newLiveState()
+	fr := newFlushRecorder()
+	cancel := startSSE(live, fr, time.Hour, "")
+	defer cancel()
+	waitClients(t, live, 1)
+
+	done := make(chan struct{})
+	go func() { live.closeStreams(); close(done) }()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("closeStreams did not end the SSE handler")
+	}
+}
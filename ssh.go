@@ -0,0 +1,641 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	syslogger "github.com/ALH477/infgo/logger"
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ui"
+)
+
+// ── Remote source (-ssh) ──────────────────────────────────────────────────────
+
+const (
+	// sshDialTimeout bounds the TCP connect and SSH handshake.
+	sshDialTimeout = 10 * time.Second
+
+	// sshKeepalive is how often the link is probed.  A probe that is not
+	// answered within the same interval drops the connection, so a
+	// black-holed link is noticed even though the record stream just stalls.
+	sshKeepalive = 5 * time.Second
+
+	// sshRemoteInfgo is run on the remote when infgo is on its PATH.
+	sshRemoteInfgo = "infgo -headless -log -"
+)
+
+// sshProcScript is the degraded mode used when the remote has no infgo: a
+// shell loop that prints the host info once, then dumps /proc/stat,
+// /proc/meminfo and /proc/loadavg after an "@" line every ui.DefaultInterval.
+// The CPU percentages are derived locally from successive dumps.
+var sshProcScript = fmt.Sprintf(`exec sh -c 'cat /proc/sys/kernel/hostname; `+
+	`(. /etc/os-release 2>/dev/null; echo "${ID:-linux}"); uname -m; cut -d" " -f1 /proc/uptime; `+
+	`while :; do echo @; cat /proc/stat /proc/meminfo /proc/loadavg || exit; sleep %g; done'`,
+	ui.DefaultInterval.Seconds())
+
+// sshOptions configures authentication and host-key verification.  Empty
+// fields use the same defaults as OpenSSH.
+type sshOptions struct {
+	identity   string   // private key file; default ~/.ssh/id_{ed25519,ecdsa,rsa}
+	knownHosts []string // default ~/.ssh/known_hosts and /etc/ssh/ssh_known_hosts
+}
+
+// sshSource collects samples over SSH without a daemon on the remote.
+// Each connection runs `infgo -headless -log -` and decodes the record
+// stream from its stdout, or falls back to sshProcScript.
+type sshSource struct {
+	user, addr string // addr is host:port
+	config     *ssh.ClientConfig
+	agentConn  net.Conn // nil without SSH_AUTH_SOCK
+
+	mu        sync.Mutex
+	conn      *sshConn // nil between connections
+	delivered uint64   // seq of the last sample handed to the model
+	closed    bool
+}
+
+// newSSHSource parses an -ssh target, [user@]host[:port], and prepares
+// the client configuration.  Nothing is dialled until the first fetch.
+func newSSHSource(target string, opts sshOptions) (*sshSource, error) {
+	s := &sshSource{}
+	host := target
+	if i := strings.LastIndex(target, "@"); i >= 0 {
+		s.user, host = target[:i], target[i+1:]
+	}
+	if s.user == "" {
+		u, err := user.Current()
+		if err != nil {
+			return nil, fmt.Errorf("-ssh: no user in %q and %v", target, err)
+		}
+		s.user = u.Username
+	}
+	if host == "" {
+		return nil, fmt.Errorf("-ssh: %q has no host", target)
+	}
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(strings.Trim(host, "[]"), "22")
+	}
+	s.addr = host
+
+	auth, err := s.authMethods(opts.identity)
+	if err != nil {
+		return nil, err
+	}
+	hostKey, algos, err := hostKeyCheck(opts.knownHosts, s.addr)
+	if err != nil {
+		return nil, err
+	}
+	s.config = &ssh.ClientConfig{
+		User:              s.user,
+		Auth:              auth,
+		HostKeyCallback:   hostKey,
+		HostKeyAlgorithms: algos,
+		Timeout:           sshDialTimeout,
+	}
+	return s, nil
+}
+
+// authMethods offers the agent's keys first, then key files.  A
+// passphrase-protected file cannot be unlocked from inside the TUI, so it
+// must be loaded into the agent instead.
+func (s *sshSource) authMethods(identity string) ([]ssh.AuthMethod, error) {
+	var methods []ssh.AuthMethod
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		if conn, err := net.Dial("unix", sock); err == nil {
+			s.agentConn = conn
+			methods = append(methods, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+		}
+	}
+
+	files := []string{identity}
+	if identity == "" {
+		home, _ := os.UserHomeDir()
+		files = []string{
+			filepath.Join(home, ".ssh", "id_ed25519"),
+			filepath.Join(home, ".ssh", "id_ecdsa"),
+			filepath.Join(home, ".ssh", "id_rsa"),
+		}
+	}
+	var signers []ssh.Signer
+	for _, f := range files {
+		pem, err := os.ReadFile(f)
+		if errors.Is(err, os.ErrNotExist) && identity == "" {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("-ssh: %w", err)
+		}
+		signer, err := ssh.ParsePrivateKey(pem)
+		var missing *ssh.PassphraseMissingError
+		if errors.As(err, &missing) {
+			if identity != "" {
+				return nil, fmt.Errorf("-ssh: %s is passphrase-protected; add it to ssh-agent instead", f)
+			}
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("-ssh: %s: %w", f, err)
+		}
+		signers = append(signers, signer)
+	}
+	if len(signers) > 0 {
+		methods = append(methods, ssh.PublicKeys(signers...))
+	}
+	if len(methods) == 0 {
+		return nil, errors.New("-ssh: no ssh-agent and no usable key in ~/.ssh; start an agent or pass -ssh-key")
+	}
+	return methods, nil
+}
+
+// hostKeyCheck verifies host keys against known_hosts, like OpenSSH with
+// StrictHostKeyChecking=yes: there is no prompt, so an unknown host must be
+// added with a plain ssh first.  The algorithms returned restrict the
+// handshake to key types known_hosts has for addr; otherwise the server may
+// offer a type with no entry and fail verification.
+func hostKeyCheck(files []string, addr string) (ssh.HostKeyCallback, []string, error) {
+	if files == nil {
+		home, _ := os.UserHomeDir()
+		files = []string{filepath.Join(home, ".ssh", "known_hosts"), "/etc/ssh/ssh_known_hosts"}
+	}
+	var existing []string
+	for _, f := range files {
+		if _, err := os.Stat(f); err == nil {
+			existing = append(existing, f)
+		}
+	}
+	if len(existing) == 0 {
+		return nil, nil, fmt.Errorf("-ssh: no known_hosts file; connect once with ssh to verify %s", addr)
+	}
+	cb, err := knownhosts.New(existing...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("-ssh: %w", err)
+	}
+
+	// Asking about a key that cannot match lists the known ones.
+	var algos []string
+	var keyErr *knownhosts.KeyError
+	if errors.As(cb(addr, &net.TCPAddr{}, probeKey{}), &keyErr) {
+		for _, k := range keyErr.Want {
+			if t := k.Key.Type(); t == ssh.KeyAlgoRSA {
+				// One RSA key serves all three signature algorithms.
+				algos = append(algos, ssh.KeyAlgoRSASHA512, ssh.KeyAlgoRSASHA256, t)
+			} else {
+				algos = append(algos, t)
+			}
+		}
+	}
+
+	check := func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		err := cb(hostname, remote, key)
+		var keyErr *knownhosts.KeyError
+		if errors.As(err, &keyErr) {
+			if len(keyErr.Want) == 0 {
+				return fmt.Errorf("%s is not in known_hosts; connect once with ssh to verify its key", hostname)
+			}
+			return fmt.Errorf("host key for %s does not match known_hosts (%s:%d); refusing to connect",
+				hostname, keyErr.Want[0].Filename, keyErr.Want[0].Line)
+		}
+		return err
+	}
+	return check, algos, nil
+}
+
+// probeKey is a public key no known_hosts entry can match.
+type probeKey struct{}
+
+func (probeKey) Type() string                        { return "infgo-probe" }
+func (probeKey) Marshal() []byte                     { return []byte("infgo-probe") }
+func (probeKey) Verify([]byte, *ssh.Signature) error { return errors.New("probe key") }
+
+func (s *sshSource) Name() string   { return s.user + "@" + strings.TrimSuffix(s.addr, ":22") }
+func (s *sshSource) Target() string { return "ssh://" + s.user + "@" + s.addr }
+
+// Fetch hands the model the newest sample, dialling first if the previous
+// connection was lost.  The model's backoff paces reconnection attempts.
+func (s *sshSource) Fetch() tea.Cmd {
+	return func() tea.Msg { return s.next() }
+}
+
+func (s *sshSource) next() ui.RemoteMsg {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return ui.RemoteMsg{Err: errors.New("closed")}
+	}
+	c := s.conn
+	s.mu.Unlock()
+
+	if c == nil {
+		var err error
+		if c, err = s.dial(); err != nil {
+			return ui.RemoteMsg{Err: err}
+		}
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			c.close()
+			return ui.RemoteMsg{Err: errors.New("closed")}
+		}
+		s.conn, s.delivered = c, 0
+		s.mu.Unlock()
+	}
+
+	msg, seq, err := c.wait(s.delivered, remoteTimeout)
+	if err != nil {
+		c.close()
+		s.mu.Lock()
+		if s.conn == c {
+			s.conn = nil
+		}
+		s.mu.Unlock()
+		return ui.RemoteMsg{Err: fmt.Errorf("%s: %w", s.Name(), err)}
+	}
+	s.mu.Lock()
+	s.delivered = seq
+	s.mu.Unlock()
+	return msg
+}
+
+// dial connects, picks the collection mode and starts the reader.
+func (s *sshSource) dial() (*sshConn, error) {
+	client, err := ssh.Dial("tcp", s.addr, s.config)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", s.Name(), err)
+	}
+	c := &sshConn{client: client, notify: make(chan struct{}, 1), done: make(chan struct{})}
+
+	// `command -v` is POSIX; a non-zero exit means infgo is not installed.
+	probe, err := client.NewSession()
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("%s: %w", s.Name(), err)
+	}
+	c.proc = probe.Run("command -v infgo >/dev/null") != nil
+	probe.Close()
+
+	cmd := sshRemoteInfgo
+	if c.proc {
+		cmd = sshProcScript
+	}
+	if err := c.start(cmd); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("%s: %w", s.Name(), err)
+	}
+	return c, nil
+}
+
+// Close stops the remote collector and disconnects.
+func (s *sshSource) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	c := s.conn
+	s.conn = nil
+	s.mu.Unlock()
+	if c != nil {
+		c.close()
+	}
+	if s.agentConn != nil {
+		s.agentConn.Close()
+	}
+	return nil
+}
+
+// sshConn is one connection and the session streaming samples over it.
+type sshConn struct {
+	client  *ssh.Client
+	session *ssh.Session
+	proc    bool         // degraded /proc mode
+	stderr  bytes.Buffer // remote diagnostics, for error messages
+
+	mu      sync.Mutex
+	info    *ui.SysInfo
+	last    ui.RemoteMsg
+	lastAt  time.Time // when last arrived
+	seq     uint64    // samples received; 0 until the first
+	rtt     time.Duration
+	err     error         // why the stream ended
+	notify  chan struct{} // signalled on every sample
+	done    chan struct{} // closed when the stream ends
+	closing sync.Once
+}
+
+func (c *sshConn) start(cmd string) error {
+	sess, err := c.client.NewSession()
+	if err != nil {
+		return err
+	}
+	stdout, err := sess.StdoutPipe()
+	if err != nil {
+		sess.Close()
+		return err
+	}
+	sess.Stderr = &limitedWriter{w: &c.stderr, n: 4096}
+	if err := sess.Start(cmd); err != nil {
+		sess.Close()
+		return err
+	}
+	c.session = sess
+	go c.read(stdout)
+	go c.keepalive()
+	return nil
+}
+
+// read decodes the session's stdout until it ends.
+func (c *sshConn) read(stdout io.Reader) {
+	var err error
+	if c.proc {
+		err = c.readProc(stdout)
+	} else {
+		err = c.readRecords(stdout)
+	}
+	if err == nil || errors.Is(err, io.ErrUnexpectedEOF) {
+		err = errors.New("remote collector exited")
+	}
+	if werr := c.session.Wait(); werr != nil {
+		err = fmt.Errorf("remote collector: %w", werr)
+	}
+	if msg := strings.TrimSpace(c.stderr.String()); msg != "" {
+		err = fmt.Errorf("%w: %s", err, lastLine(msg))
+	}
+	c.fail(err)
+}
+
+// readRecords consumes `infgo -log -` output, the same framing as a
+// capture file.
+func (c *sshConn) readRecords(stdout io.Reader) error {
+	rd, err := syslogger.NewReader(stdout)
+	if err != nil {
+		return err
+	}
+	for {
+		rec, err := rd.Next()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		switch {
+		case rec.Header != nil:
+			c.mu.Lock()
+			c.info = ui.HeaderInfo(*rec.Header)
+			c.mu.Unlock()
+		case rec.Sample != nil:
+			c.publish(ui.SampleReading(*rec.Sample, 0))
+		}
+	}
+}
+
+// readProc consumes sshProcScript output.
+func (c *sshConn) readProc(stdout io.Reader) error {
+	sc := bufio.NewScanner(stdout)
+	var head []string
+	for len(head) < 4 && sc.Scan() {
+		head = append(head, strings.TrimSpace(sc.Text()))
+	}
+	if len(head) < 4 {
+		return sc.Err()
+	}
+	info := ui.SysInfo{Hostname: head[0], Platform: head[1] + " · " + head[2]}
+	if up, err := strconv.ParseFloat(head[3], 64); err == nil {
+		info.Uptime = uint64(up)
+	}
+
+	var p procSampler
+	var frame []string
+	flush := func() {
+		if len(frame) == 0 {
+			return
+		}
+		if st, ok := p.parse(frame, time.Now()); ok {
+			c.mu.Lock()
+			if c.info == nil {
+				info.NumCores = len(st.CPUCores)
+				c.info = &info
+			}
+			c.mu.Unlock()
+			c.publish(st)
+		}
+		frame = frame[:0]
+	}
+	for sc.Scan() {
+		if line := sc.Text(); line == "@" {
+			flush()
+		} else {
+			frame = append(frame, line)
+		}
+	}
+	flush()
+	return sc.Err()
+}
+
+// publish makes st the newest sample.  Samples are only published once the
+// host info is known, as with -connect.
+func (c *sshConn) publish(st ui.Reading) {
+	c.mu.Lock()
+	if c.info == nil {
+		c.mu.Unlock()
+		return
+	}
+	c.seq++
+	c.last = ui.RemoteMsg{Reading: st, Info: c.info}
+	c.lastAt = time.Now()
+	c.mu.Unlock()
+	select {
+	case c.notify <- struct{}{}:
+	default:
+	}
+}
+
+// wait returns the first sample newer than seq.  If none arrives within
+// timeout the previous one is returned again with its age, so a remote
+// whose sampling has stalled shows as stale rather than offline.
+func (c *sshConn) wait(seq uint64, timeout time.Duration) (ui.RemoteMsg, uint64, error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		c.mu.Lock()
+		msg, cur, err := c.last, c.seq, c.err
+		msg.RTT, msg.Age = c.rtt, time.Since(c.lastAt)
+		c.mu.Unlock()
+		if err != nil {
+			return ui.RemoteMsg{}, 0, err
+		}
+		if cur != seq {
+			return msg, cur, nil
+		}
+		select {
+		case <-c.notify:
+		case <-c.done:
+		case <-timer.C:
+			if cur == 0 {
+				return ui.RemoteMsg{}, 0, errors.New("no sample collected yet")
+			}
+			return msg, cur, nil
+		}
+	}
+}
+
+// keepalive measures the round-trip time and drops a link that has gone
+// quiet.
+func (c *sshConn) keepalive() {
+	t := time.NewTicker(sshKeepalive)
+	defer t.Stop()
+	for {
+		start := time.Now()
+		reply := make(chan error, 1)
+		go func() {
+			_, _, err := c.client.SendRequest("keepalive@openssh.com", true, nil)
+			reply <- err
+		}()
+		select {
+		case err := <-reply:
+			if err != nil {
+				c.fail(err)
+				return
+			}
+			c.mu.Lock()
+			c.rtt = time.Since(start)
+			c.mu.Unlock()
+		case <-time.After(sshKeepalive):
+			c.fail(errors.New("keepalive timed out"))
+			c.client.Close() // unblocks the reader
+			return
+		case <-c.done:
+			return
+		}
+		select {
+		case <-t.C:
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// fail records why the stream ended; the first reason wins.
+func (c *sshConn) fail(err error) {
+	c.mu.Lock()
+	if c.err == nil {
+		c.err = err
+	}
+	c.mu.Unlock()
+	c.closing.Do(func() { close(c.done) })
+}
+
+// close asks the remote collector to exit and disconnects.  OpenSSH
+// servers before 7.9 ignore the signal; closing the channel then ends the
+// collector with SIGPIPE on its next write.
+func (c *sshConn) close() {
+	if c.session != nil {
+		c.session.Signal(ssh.SIGTERM)
+		c.session.Close()
+	}
+	c.client.Close()
+	c.fail(errors.New("closed"))
+}
+
+// limitedWriter keeps the first n bytes written to it and discards the rest.
+type limitedWriter struct {
+	mu sync.Mutex
+	w  *bytes.Buffer
+	n  int
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if room := l.n - l.w.Len(); room > 0 {
+		l.w.Write(p[:min(len(p), room)])
+	}
+	return len(p), nil
+}
+
+func lastLine(s string) string {
+	return s[strings.LastIndexByte(s, '\n')+1:]
+}
+
+// ── /proc parsing for the degraded mode ───────────────────────────────────────
+
+// procSampler turns successive /proc dumps into statsMsgs.  CPU usage is
+// the share of non-idle jiffies between two dumps, as gopsutil computes
+// it locally, so the first dump only primes it.
+type procSampler struct {
+	prev [][2]uint64 // per-core {busy, total}; index 0 is the aggregate
+}
+
+// parse reads one dump and reports whether it produced a sample.
+func (p *procSampler) parse(lines []string, at time.Time) (ui.Reading, bool) {
+	var cur [][2]uint64
+	var memTotal, memAvail float64
+	st := ui.Reading{At: at, Missing: metrics.MissingLoad}
+	for _, line := range lines {
+		f := strings.Fields(line)
+		switch {
+		case len(f) > 4 && strings.HasPrefix(f[0], "cpu"):
+			var total, idle uint64
+			for i, v := range f[1:min(len(f), 9)] { // guest time is already in user
+				n, _ := strconv.ParseUint(v, 10, 64)
+				total += n
+				if i == 3 || i == 4 { // idle, iowait
+					idle += n
+				}
+			}
+			cur = append(cur, [2]uint64{total - idle, total})
+		case len(f) >= 2 && f[0] == "MemTotal:":
+			memTotal, _ = strconv.ParseFloat(f[1], 64)
+		case len(f) >= 2 && f[0] == "MemAvailable:":
+			memAvail, _ = strconv.ParseFloat(f[1], 64)
+		case len(f) == 5 && strings.Contains(f[3], "/"): // loadavg
+			st.Load1, _ = strconv.ParseFloat(f[0], 64)
+			st.Load5, _ = strconv.ParseFloat(f[1], 64)
+			st.Load15, _ = strconv.ParseFloat(f[2], 64)
+			st.Missing &^= metrics.MissingLoad
+		}
+	}
+
+	prev := p.prev
+	p.prev = cur
+	if len(cur) < 2 || len(prev) != len(cur) {
+		return ui.Reading{}, false
+	}
+	pct := func(i int) float64 {
+		busy, total := float64(cur[i][0]-prev[i][0]), float64(cur[i][1]-prev[i][1])
+		if total <= 0 || cur[i][0] < prev[i][0] {
+			return 0
+		}
+		return min(busy/total*100, 100)
+	}
+	st.CPUTotal = pct(0)
+	st.CPUCores = make([]float64, 0, len(cur)-1)
+	for i := 1; i < len(cur); i++ {
+		st.CPUCores = append(st.CPUCores, pct(i))
+	}
+	if memTotal > 0 {
+		const kbPerGB = 1 << 20
+		st.MemPercent = (memTotal - memAvail) / memTotal * 100
+		st.MemUsedGB = (memTotal - memAvail) / kbPerGB
+		st.MemTotalGB = memTotal / kbPerGB
+	} else {
+		st.Missing |= metrics.MissingMem // /proc/meminfo was not in the dump
+	}
+	return st, true
+}
@@ -0,0 +1,360 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	syslogger "github.com/ALH477/infgo/logger"
+	"github.com/ALH477/infgo/metrics"
+)
+
+// testSSHD is a minimal SSH server that answers exec requests with
+// handle, which returns the exit status.
+type testSSHD struct {
+	addr       string
+	hostKey    ssh.PublicKey
+	clientKey  string // private key file accepted by the server
+	knownHosts string
+
+	mu      sync.Mutex
+	signals []string
+}
+
+func startTestSSHD(t *testing.T, handle func(cmd string, ch ssh.Channel, stop <-chan struct{}) uint32) *testSSHD {
+	t.Helper()
+	dir := t.TempDir()
+	_, hostPriv, _ := ed25519.GenerateKey(rand.Reader)
+	hostSigner, _ := ssh.NewSignerFromKey(hostPriv)
+	clientPub, clientPriv, _ := ed25519.GenerateKey(rand.Reader)
+	sshClientPub, _ := ssh.NewPublicKey(clientPub)
+
+	block, err := ssh.MarshalPrivateKey(clientPriv, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &testSSHD{hostKey: hostSigner.PublicKey(), clientKey: filepath.Join(dir, "id_ed25519")}
+	if err := os.WriteFile(d.clientKey, pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if string(key.Marshal()) != string(sshClientPub.Marshal()) {
+				return nil, fmt.Errorf("unknown key")
+			}
+			return nil, nil
+		},
+	}
+	cfg.AddHostKey(hostSigner)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	d.addr = ln.Addr().String()
+	d.knownHosts = filepath.Join(dir, "known_hosts")
+	line := knownhosts.Line([]string{knownhosts.Normalize(d.addr)}, d.hostKey) + "\n"
+	if err := os.WriteFile(d.knownHosts, []byte(line), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		for {
+			nc, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go d.serve(nc, cfg, handle)
+		}
+	}()
+	return d
+}
+
+func (d *testSSHD) serve(nc net.Conn, cfg *ssh.ServerConfig, handle func(string, ssh.Channel, <-chan struct{}) uint32) {
+	_, chans, reqs, err := ssh.NewServerConn(nc, cfg)
+	if err != nil {
+		nc.Close()
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+	for nch := range chans {
+		ch, reqs, err := nch.Accept()
+		if err != nil {
+			continue
+		}
+		go func() {
+			stop := make(chan struct{})
+			var once sync.Once
+			for req := range reqs {
+				switch req.Type {
+				case "exec":
+					n := binary.BigEndian.Uint32(req.Payload)
+					cmd := string(req.Payload[4 : 4+n])
+					req.Reply(true, nil)
+					go func() {
+						status := handle(cmd, ch, stop)
+						ch.SendRequest("exit-status", false, binary.BigEndian.AppendUint32(nil, status))
+						ch.Close()
+					}()
+				case "signal":
+					d.mu.Lock()
+					d.signals = append(d.signals, string(req.Payload[4:]))
+					d.mu.Unlock()
+					once.Do(func() { close(stop) })
+				default:
+					req.Reply(false, nil)
+				}
+			}
+			once.Do(func() { close(stop) })
+		}()
+	}
+}
+
+func (d *testSSHD) source(t *testing.T) *sshSource {
+	t.Helper()
+	t.Setenv("SSH_AUTH_SOCK", "")
+	src, err := newSSHSource("tester@"+d.addr, sshOptions{identity: d.clientKey, knownHosts: []string{d.knownHosts}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { src.Close() })
+	return src
+}
+
+// streamInfgo plays `infgo -headless -log -`: a header, then a sample every
+// 10ms until stopped.
+func streamInfgo(ch ssh.Channel, stop <-chan struct{}, n int) uint32 {
+	lgr, _ := syslogger.NewWriter(ch)
+	lgr.WriteHeader(metrics.Header{Hostname: "node7", Platform: "linux · arm64", NumCores: 2})
+	lgr.Flush()
+	for i := 1; n <= 0 || i <= n; i++ {
+		select {
+		case <-stop:
+			return 143
+		case <-time.After(10 * time.Millisecond):
+		}
+		s := metrics.Sample{TimestampUnixMs: int64(i) * 500, CpuTotal: float64(i), CpuCores: []float64{1, 2}}
+		if lgr.WriteSample(s) != nil || lgr.Flush() != nil {
+			return 141
+		}
+	}
+	return 0
+}
+
+func TestNewSSHSource(t *testing.T) {
+	d := startTestSSHD(t, func(string, ssh.Channel, <-chan struct{}) uint32 { return 0 })
+	t.Setenv("SSH_AUTH_SOCK", "")
+	opts := sshOptions{identity: d.clientKey, knownHosts: []string{d.knownHosts}}
+
+	tests := []struct {
+		in, wantName, wantAddr string
+	}{
+		{"ops@node7", "ops@node7", "node7:22"},
+		{"ops@node7:2222", "ops@node7:2222", "node7:2222"},
+		{"ops@[fe80::1]", "ops@[fe80::1]", "[fe80::1]:22"},
+		{"ops@fe80::1", "ops@[fe80::1]", "[fe80::1]:22"},
+	}
+	for _, tt := range tests {
+		src, err := newSSHSource(tt.in, opts)
+		if err != nil {
+			t.Errorf("newSSHSource(%q): %v", tt.in, err)
+			continue
+		}
+		if src.Name() != tt.wantName || src.addr != tt.wantAddr {
+			t.Errorf("newSSHSource(%q): got %q at %q, want %q at %q", tt.in, src.Name(), src.addr, tt.wantName, tt.wantAddr)
+		}
+	}
+	if _, err := newSSHSource("ops@", opts); err == nil {
+		t.Error(`newSSHSource("ops@"): got nil error`)
+	}
+	if _, err := newSSHSource("ops@node7", sshOptions{identity: filepath.Join(t.TempDir(), "nope"), knownHosts: opts.knownHosts}); err == nil {
+		t.Error("missing -ssh-key: got nil error")
+	}
+}
+
+func TestSSHInfgoMode(t *testing.T) {
+	var d *testSSHD
+	d = startTestSSHD(t, func(cmd string, ch ssh.Channel, stop <-chan struct{}) uint32 {
+		switch cmd {
+		case "command -v infgo >/dev/null":
+			return 0
+		case sshRemoteInfgo:
+			return streamInfgo(ch, stop, 0)
+		}
+		return 127
+	})
+	src := d.source(t)
+
+	var last int64
+	for i := 0; i < 3; i++ {
+		msg := src.next()
+		if msg.Err != nil {
+			t.Fatalf("next: %v", msg.Err)
+		}
+		if msg.Info == nil || msg.Info.Hostname != "node7" || msg.Info.NumCores != 2 {
+			t.Errorf("info: got %+v", msg.Info)
+		}
+		ts := msg.Reading.At.UnixMilli()
+		if ts <= last {
+			t.Errorf("sample %d: got timestamp %d after %d, want a newer sample each time", i, ts, last)
+		}
+		last = ts
+	}
+
+	// Quitting asks the remote collector to stop.
+	src.Close()
+	deadline := time.Now().Add(time.Second)
+	for {
+		d.mu.Lock()
+		sigs := strings.Join(d.signals, ",")
+		d.mu.Unlock()
+		if sigs == "TERM" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("signals after Close: got %q, want TERM", sigs)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestSSHReconnect(t *testing.T) {
+	var runs sync.WaitGroup
+	runs.Add(2)
+	d := startTestSSHD(t, func(cmd string, ch ssh.Channel, stop <-chan struct{}) uint32 {
+		if cmd != sshRemoteInfgo {
+			return 0
+		}
+		defer runs.Done()
+		streamInfgo(ch, stop, 2)
+		return 1 // the collector dies after two samples
+	})
+	src := d.source(t)
+
+	var errs, samples int
+	for i := 0; i < 8 && errs < 2; i++ {
+		if msg := src.next(); msg.Err != nil {
+			errs++
+		} else {
+			samples++
+		}
+	}
+	if errs != 2 || samples < 2 {
+		t.Errorf("got %d samples and %d errors, want samples from both connections", samples, errs)
+	}
+	runs.Wait() // the second run was dialled after the first ended
+}
+
+func TestSSHProcMode(t *testing.T) {
+	stat := func(user, idle uint64) string {
+		return fmt.Sprintf("cpu  %d 0 0 %d 0 0 0 0 0 0\ncpu0 %d 0 0 %d 0 0 0 0 0 0\ncpu1 %d 0 0 %d 0 0 0 0 0 0\nintr 1 2 3\n",
+			2*user, 2*idle, user, idle, user, idle)
+	}
+	d := startTestSSHD(t, func(cmd string, ch ssh.Channel, stop <-chan struct{}) uint32 {
+		if cmd != sshProcScript {
+			return 127 // no infgo on this host
+		}
+		fmt.Fprint(ch, "node9\ndebian\nx86_64\n12345.67\n")
+		for i := uint64(1); ; i++ {
+			fmt.Fprintf(ch, "@\n%sMemTotal:       8388608 kB\nMemAvailable:   2097152 kB\n0.50 0.40 0.30 1/200 999\n", stat(i*25, i*75))
+			select {
+			case <-stop:
+				return 143
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+	})
+	src := d.source(t)
+
+	msg := src.next()
+	if msg.Err != nil {
+		t.Fatalf("next: %v", msg.Err)
+	}
+	info := msg.Info
+	if info == nil || info.Hostname != "node9" || info.Platform != "debian · x86_64" || info.NumCores != 2 || info.Uptime != 12345 {
+		t.Errorf("info: got %+v", info)
+	}
+	st := msg.Reading
+	if st.CPUTotal != 25 || len(st.CPUCores) != 2 || st.MemPercent != 75 || st.MemTotalGB != 8 || st.Load1 != 0.5 {
+		t.Errorf("stats: got %+v", st)
+	}
+}
+
+func TestSSHHostKeyVerification(t *testing.T) {
+	d := startTestSSHD(t, func(string, ssh.Channel, <-chan struct{}) uint32 { return 0 })
+	t.Setenv("SSH_AUTH_SOCK", "")
+	dir := t.TempDir()
+
+	_, otherPriv, _ := ed25519.GenerateKey(rand.Reader)
+	other, _ := ssh.NewSignerFromKey(otherPriv)
+	mismatch := filepath.Join(dir, "mismatch")
+	os.WriteFile(mismatch, []byte(knownhosts.Line([]string{knownhosts.Normalize(d.addr)}, other.PublicKey())+"\n"), 0o600)
+	unknown := filepath.Join(dir, "unknown")
+	os.WriteFile(unknown, []byte(knownhosts.Line([]string{"elsewhere"}, d.hostKey)+"\n"), 0o600)
+
+	tests := []struct {
+		knownHosts, wantErr string
+	}{
+		{mismatch, "does not match known_hosts"},
+		{unknown, "not in known_hosts"},
+	}
+	for _, tt := range tests {
+		src, err := newSSHSource("tester@"+d.addr, sshOptions{identity: d.clientKey, knownHosts: []string{tt.knownHosts}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if msg := src.next(); msg.Err == nil || !strings.Contains(msg.Err.Error(), tt.wantErr) {
+			t.Errorf("%s: got %v, want %q", filepath.Base(tt.knownHosts), msg.Err, tt.wantErr)
+		}
+		src.Close()
+	}
+}
+
+func TestProcSamplerParse(t *testing.T) {
+	dump := func(user, steal, idle, iowait uint64, memAvailKB int) []string {
+		return []string{
+			fmt.Sprintf("cpu  %d 0 0 %d %d 0 0 %d 50 0", user, idle, iowait, steal),
+			fmt.Sprintf("cpu0 %d 0 0 %d %d 0 0 %d 50 0", user, idle, iowait, steal),
+			"ctxt 123",
+			"MemTotal:        4194304 kB",
+			fmt.Sprintf("MemAvailable:    %d kB", memAvailKB),
+			"1.25 0.75 0.50 3/150 4242",
+		}
+	}
+	var p procSampler
+	if _, ok := p.parse(dump(100, 0, 100, 0, 0), time.Time{}); ok {
+		t.Fatal("first dump produced a sample; it only primes the deltas")
+	}
+	// +60 user, +20 steal, +10 idle, +10 iowait: 80% busy.  Guest time (the
+	// 50s) is part of user and must not be counted twice.
+	st, ok := p.parse(dump(160, 20, 110, 10, 1048576), time.Time{})
+	if !ok {
+		t.Fatal("second dump produced no sample")
+	}
+	if st.CPUTotal != 80 || len(st.CPUCores) != 1 || st.CPUCores[0] != 80 {
+		t.Errorf("cpu: got %v %v, want 80 [80]", st.CPUTotal, st.CPUCores)
+	}
+	if st.MemPercent != 75 || st.MemUsedGB != 3 || st.MemTotalGB != 4 {
+		t.Errorf("mem: got %v%% %v/%v GB, want 75%% 3/4 GB", st.MemPercent, st.MemUsedGB, st.MemTotalGB)
+	}
+	if st.Load1 != 1.25 || st.Load5 != 0.75 || st.Load15 != 0.5 {
+		t.Errorf("load: got %v %v %v", st.Load1, st.Load5, st.Load15)
+	}
+}
@@ -0,0 +1,394 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/shirou/gopsutil/v3/host"
+	"github.com/shirou/gopsutil/v3/load"
+	"github.com/shirou/gopsutil/v3/mem"
+	psnet "github.com/shirou/gopsutil/v3/net"
+
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ui"
+)
+
+// ── Local readings ────────────────────────────────────────────────────────────
+
+// statsBackoffMin / statsBackoffMax bound the wait before a failed
+// subsystem is queried again.  The first retry skips one tick.
+// statsCallTimeout bounds each query of the background watches; a
+// statsReader allows twice its own interval.
+const (
+	statsBackoffMin  = 2 * ui.DefaultInterval
+	statsBackoffMax  = 30 * time.Second
+	statsCallTimeout = 2 * ui.DefaultInterval
+)
+
+// cpuPrimeWindow is the span of the first CPU delta: the wait between the
+// throwaway query that primes gopsutil's snapshot and the first reading
+// reported.  A delta over less than a few scheduler ticks reads as 0% or
+// 100% a core.
+const cpuPrimeWindow = 100 * time.Millisecond
+
+// errTimedOut is a query abandoned at its deadline, or one not started
+// because an abandoned query to the same subsystem has not returned yet.
+var errTimedOut = errors.New("collection timed out")
+
+// statsSources are the queries behind a local reading, one per subsystem,
+// so that tests can make any of them fail.
+type statsSources struct {
+	cpu func(context.Context) ([]float64, error)
+
+	// freqs reads each core's clock in MHz, for the CPU panel's grid.
+	freqs func(context.Context) ([]float64, error)
+
+	// times reads the CPU time by mode, summed over the cores.
+	times func(context.Context) ([]cpu.TimesStat, error)
+
+	mem  func(context.Context) (*mem.VirtualMemoryStat, error)
+	swap func(context.Context) (*mem.SwapMemoryStat, error)
+	load func(context.Context) (*load.AvgStat, error)
+	disk func(ctx context.Context, path string) (*disk.UsageStat, error)
+
+	// partitions lists the mounts, to find those of the -disks paths and
+	// for the DISK panel.
+	partitions func(all bool) ([]disk.PartitionStat, error)
+
+	// io reads the disks' I/O counters, for -disk-io.
+	io func(ctx context.Context, names ...string) (map[string]disk.IOCountersStat, error)
+
+	// nics and ifaces read the network interfaces' counters and flags,
+	// for the NET panel.
+	nics   func(ctx context.Context, pernic bool) ([]psnet.IOCountersStat, error)
+	ifaces func(context.Context) (psnet.InterfaceStatList, error)
+
+	// retrans reads the TCP segments retransmitted since boot, for the
+	// NET panel; nil where they cannot be read.
+	retrans func(context.Context) (uint64, error)
+
+	// kernel reads the context switches and interrupts since boot, for
+	// the SYSTEM panel; nil where they cannot be read.
+	kernel func(context.Context) (ui.KernelCounters, error)
+
+	// faults reads the page faults since boot, for the MEMORY panel; nil
+	// where they cannot be read.
+	faults func(context.Context) (ui.FaultCounters, error)
+
+	// temps reads the temperature sensors, for the TEMP panel.
+	temps func(context.Context) ([]host.TemperatureStat, error)
+
+	// pressure reads the pressure stall information, for the PRESSURE
+	// panel; nil where there is none.
+	pressure func(context.Context) (ui.Pressure, error)
+}
+
+// gopsutilSources read this machine.  The CPU query passes interval 0,
+// which means the delta since the previous call (gopsutil keeps the last
+// sample in package-level state).
+var gopsutilSources = statsSources{
+	cpu: func(ctx context.Context) ([]float64, error) {
+		return cpu.PercentWithContext(ctx, 0, true)
+	},
+	freqs: readCoreMHz,
+	times: func(ctx context.Context) ([]cpu.TimesStat, error) {
+		return cpu.TimesWithContext(ctx, false)
+	},
+	mem:  mem.VirtualMemoryWithContext,
+	swap: mem.SwapMemoryWithContext,
+	load: load.AvgWithContext,
+	disk: disk.UsageWithContext,
+
+	partitions: disk.Partitions,
+	io:         disk.IOCountersWithContext,
+	nics:       psnet.IOCountersWithContext,
+	ifaces:     psnet.InterfacesWithContext,
+	retrans:    readTCPRetrans,
+	temps:      host.SensorsTemperaturesWithContext,
+	kernel:     readKernelCounters,
+	faults:     readFaultCounters,
+	pressure:   readPressureInfo,
+}
+
+// subsystem is the query state of one of CPU, memory and load.
+type subsystem struct {
+	backoff time.Duration // 0 while the subsystem is healthy
+	next    time.Time     // no query before this
+	busy    atomic.Bool   // a query is running, possibly abandoned
+}
+
+func (s *subsystem) due(now time.Time) bool { return !now.Before(s.next) }
+
+func (s *subsystem) failed(now time.Time) {
+	s.backoff = min(max(2*s.backoff, statsBackoffMin), statsBackoffMax)
+	s.next = now.Add(s.backoff)
+}
+
+func (s *subsystem) recovered() { s.backoff, s.next = 0, time.Time{} }
+
+// query runs q against s with a deadline of timeout.  gopsutil cannot
+// always honour its context (a read of /proc behind a hung NFS mount
+// blocks in the kernel), so q runs on a goroutine of its own and is
+// abandoned at the deadline.  Until it returns, s is not queried again:
+// a hung subsystem holds one goroutine, however many ticks it stays hung.
+func query[T any](ctx context.Context, s *subsystem, timeout time.Duration, q func(context.Context) (T, error)) (T, error) {
+	var zero T
+	if !s.busy.CompareAndSwap(false, true) {
+		return zero, errTimedOut
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	type result struct {
+		v   T
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		v, err := q(ctx)
+		s.busy.Store(false)
+		done <- result{v, err}
+	}()
+	select {
+	case res := <-done:
+		if errors.Is(res.err, context.DeadlineExceeded) {
+			res.err = errTimedOut
+		}
+		return res.v, res.err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return zero, errTimedOut
+		}
+		return zero, ctx.Err()
+	}
+}
+
+// statsReader takes local readings with each subsystem queried on its own.
+// One that fails, say mem.VirtualMemory in a locked-down container, is
+// reported in the reading's missing set rather than as zeros, and is not
+// queried again until its backoff has passed; the others carry on.
+//
+// Each query is also bounded by timeout, twice the sampling interval.  One
+// that runs past it is reported as timed out, and the subsystem backs off
+// as for any failure.
+type statsReader struct {
+	src     statsSources
+	now     func() time.Time
+	timeout time.Duration
+
+	// unprimed is set while there is no CPU snapshot to take a delta from:
+	// at start, and when the last one spans a stop of the process.  The
+	// next reading then primes one first and waits primeWait.
+	unprimed  atomic.Bool
+	primeWait time.Duration
+
+	// power reads the RAPL energy counters; nil where they cannot be.
+	power *raplReader
+
+	mu             sync.Mutex // serialises readings
+	cpu, mem, load subsystem
+
+	// swap is read with the memory, but backs off on its own: a machine
+	// whose swap cannot be read still has its memory shown.
+	swap subsystem
+
+	// freq is read with the CPU, and backs off on its own like swap.
+	freq subsystem
+
+	// times is read with the CPU, and backs off on its own too.
+	times subsystem
+
+	// disks are the filesystems recorded with -disks, and mounts lists
+	// every one for the DISK panel; nil without it.
+	disks  []diskMount
+	mounts *mountWatch
+
+	// ioOn reads the disks' I/O counters for -disk-io.
+	ioOn bool
+	io   subsystem
+
+	// netOn reads the network interfaces' counters for the NET panel.
+	netOn bool
+	net   subsystem
+	snmp  subsystem // the TCP retransmits, with them
+
+	// kstat is the kernel's context switch and interrupt counters.
+	kstat subsystem
+
+	// vmstat is the kernel's page fault counters.
+	vmstat subsystem
+
+	// tempOn reads the temperature sensors for the TEMP panel.
+	tempOn bool
+	temp   subsystem
+
+	// psiOn reads the pressure stall information for the PRESSURE panel.
+	psiOn bool
+	psi   subsystem
+
+	// cg, where infgo runs in a cgroup with limits, reads the CPU and
+	// memory against them in place of the host's.
+	cg *cgroup
+}
+
+// newStatsReader returns a reader over src for readings taken every
+// interval.
+func newStatsReader(src statsSources, interval time.Duration) *statsReader {
+	r := &statsReader{src: src, now: time.Now, timeout: 2 * interval, primeWait: cpuPrimeWindow}
+	r.unprimed.Store(true)
+	return r
+}
+
+// localStats is the reader behind readStats.  Like gopsutil's CPU state it
+// is per process, and replaced at start for the -interval chosen.
+var localStats = newStatsReader(gopsutilSources, ui.DefaultInterval)
+
+// read takes one reading.  Subsystems that fail or are waiting out their
+// backoff are left zero and named in the result's missing set.
+func (r *statsReader) read(ctx context.Context) ui.Reading {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.unprimed.Load() && r.cpu.due(r.now()) {
+		r.primeCPU(ctx)
+	}
+	start := r.now()
+	msg := ui.Reading{Missing: metrics.MissingAll}
+
+	if r.cpu.due(start) {
+		cores, err := query(ctx, &r.cpu, r.timeout, r.src.cpu)
+		switch {
+		case err != nil:
+			r.failed(&r.cpu, metrics.MissingCPU, err, &msg)
+		case len(cores) == 0:
+			// Nothing to report yet, which is not worth a backoff.
+		default:
+			r.cpu.recovered()
+			// Derive the aggregate by averaging: it avoids a second kernel
+			// round-trip and keeps both readings temporally consistent.
+			var total float64
+			for _, c := range cores {
+				total += c
+			}
+			msg.CPUTotal, msg.CPUCores = total/float64(len(cores)), cores
+			msg.Missing &^= metrics.MissingCPU
+			r.readFreqs(ctx, &msg)
+			r.readTimes(ctx, &msg)
+		}
+	}
+
+	if r.mem.due(start) {
+		vm, err := query(ctx, &r.mem, r.timeout, r.src.mem)
+		if err != nil {
+			r.failed(&r.mem, metrics.MissingMem, err, &msg)
+		} else {
+			r.mem.recovered()
+			const gb = 1 << 30
+			msg.MemPercent = vm.UsedPercent
+			msg.MemUsedGB = float64(vm.Used) / gb
+			msg.MemTotalGB = float64(vm.Total) / gb
+			msg.MemAvailGB = float64(vm.Available) / gb
+			msg.MemCachedGB = float64(vm.Cached) / gb
+			msg.MemBuffersGB = float64(vm.Buffers) / gb
+			msg.Missing &^= metrics.MissingMem
+			r.readSwap(ctx, &msg)
+		}
+	}
+	r.readCgroup(&msg)
+
+	if r.load.due(start) {
+		// load.Avg is a no-op on Windows, where gopsutil returns (nil, nil):
+		// the averages read as zero there, as they always have.
+		avg, err := query(ctx, &r.load, r.timeout, r.src.load)
+		if err != nil {
+			r.failed(&r.load, metrics.MissingLoad, err, &msg)
+		} else {
+			r.load.recovered()
+			if avg != nil {
+				msg.Load1, msg.Load5, msg.Load15 = avg.Load1, avg.Load5, avg.Load15
+			}
+			msg.Missing &^= metrics.MissingLoad
+		}
+	}
+
+	r.readDisks(ctx, &msg)
+	r.readMounts(ctx, &msg)
+	r.readIO(ctx, &msg)
+	r.readNet(ctx, &msg)
+	r.readRetrans(ctx, &msg)
+	r.readTemps(ctx, &msg)
+	r.readPressure(ctx, &msg)
+	r.readKernel(ctx, &msg)
+	r.readFaults(ctx, &msg)
+
+	if r.power != nil {
+		if w, ok := r.power.read(start); ok {
+			msg.Watts, msg.HasWatts, msg.Joules = w, true, r.power.joules
+		}
+	}
+
+	msg.Took = r.now().Sub(start)
+	msg.Collect, msg.Begun = msg.Took, start
+	return msg
+}
+
+// primeCPU takes the throwaway CPU query that gives the next one a
+// snapshot to measure from, then waits primeWait so that the delta spans
+// more than an instant.  gopsutil's first query in a process measures
+// from the snapshot it took at init, and one after a stop across the
+// stop: either would report 0% or a spike, and set the session's peak.
+// A failed query leaves the reader unprimed, to try again next time.
+func (r *statsReader) primeCPU(ctx context.Context) {
+	if _, err := query(ctx, &r.cpu, r.timeout, r.src.cpu); err != nil {
+		return
+	}
+	r.unprimed.Store(false)
+	if r.cg != nil {
+		r.cg.prime(r.now())
+	}
+	select {
+	case <-time.After(r.primeWait):
+	case <-ctx.Done():
+	}
+}
+
+// discardCPU makes the next reading prime the CPU snapshot afresh rather
+// than report a delta from the last one; call it when the process is
+// continued after a stop, or has been suspended.
+func (r *statsReader) discardCPU() { r.unprimed.Store(true) }
+
+// failed records err from the query of s, whose fields are group g.
+func (r *statsReader) failed(s *subsystem, g metrics.Missing, err error, msg *ui.Reading) {
+	s.failed(r.now())
+	if msg.Errs == nil {
+		msg.Errs = map[metrics.Missing]error{}
+	}
+	msg.Errs[g] = err
+	if errors.Is(err, errTimedOut) {
+		msg.TimedOut |= g
+	}
+}
+
+// readSwap reads the use of swap space into msg, if it is due.
+func (r *statsReader) readSwap(ctx context.Context, msg *ui.Reading) {
+	if !r.swap.due(r.now()) {
+		return
+	}
+	sw, err := query(ctx, &r.swap, r.timeout, r.src.swap)
+	if err != nil {
+		r.swap.failed(r.now())
+		return
+	}
+	r.swap.recovered()
+	const gb = 1 << 30
+	msg.SwapUsedGB, msg.SwapTotalGB, msg.SwapPercent = float64(sw.Used)/gb, float64(sw.Total)/gb, sw.UsedPercent
+	msg.HasSwap = true
+}
+
+// ── Core count ────────────────────────────────────────────────────────────────
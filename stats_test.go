@@ -0,0 +1,311 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"runtime"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/load"
+	"github.com/shirou/gopsutil/v3/mem"
+
+	syslogger "github.com/ALH477/infgo/logger"
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ui"
+)
+
+// fakeSources is a statsSources whose subsystems fail while their flag is
+// set, counting the queries each one gets.
+type fakeSources struct {
+	cpuDown, memDown, loadDown    bool
+	cpuCalls, memCalls, loadCalls int
+}
+
+var errFake = errors.New("permission denied")
+
+func (f *fakeSources) sources() statsSources {
+	return statsSources{
+		cpu: func(context.Context) ([]float64, error) {
+			f.cpuCalls++
+			if f.cpuDown {
+				return nil, errFake
+			}
+			return []float64{20, 40}, nil
+		},
+		freqs: func(context.Context) ([]float64, error) {
+			return []float64{3800, 2400}, nil
+		},
+		times: func(context.Context) ([]cpu.TimesStat, error) {
+			return []cpu.TimesStat{{User: 30, System: 10, Idle: 60}}, nil
+		},
+		mem: func(context.Context) (*mem.VirtualMemoryStat, error) {
+			f.memCalls++
+			if f.memDown {
+				return nil, errFake
+			}
+			return &mem.VirtualMemoryStat{UsedPercent: 50, Used: 4 << 30, Total: 8 << 30}, nil
+		},
+		swap: func(context.Context) (*mem.SwapMemoryStat, error) {
+			return &mem.SwapMemoryStat{UsedPercent: 25, Used: 1 << 30, Total: 4 << 30}, nil
+		},
+		load: func(context.Context) (*load.AvgStat, error) {
+			f.loadCalls++
+			if f.loadDown {
+				return nil, errFake
+			}
+			return &load.AvgStat{Load1: 1.5, Load5: 1, Load15: 0.5}, nil
+		},
+	}
+}
+
+// fakeReader returns a statsReader over f whose clock is *now, already
+// primed, as after its first reading.
+func fakeReader(f *fakeSources, now *time.Time) *statsReader {
+	r := newStatsReader(f.sources(), ui.DefaultInterval)
+	r.now = func() time.Time { return *now }
+	r.unprimed.Store(false)
+	r.primeWait = 0
+	return r
+}
+
+func TestStatsReaderSubsystemFails(t *testing.T) {
+	tests := []struct {
+		name    string
+		down    func(f *fakeSources, down bool)
+		missing metrics.Missing
+		calls   func(f *fakeSources) int
+	}{
+		{"cpu", func(f *fakeSources, d bool) { f.cpuDown = d }, metrics.MissingCPU, func(f *fakeSources) int { return f.cpuCalls }},
+		{"mem", func(f *fakeSources, d bool) { f.memDown = d }, metrics.MissingMem, func(f *fakeSources) int { return f.memCalls }},
+		{"load", func(f *fakeSources, d bool) { f.loadDown = d }, metrics.MissingLoad, func(f *fakeSources) int { return f.loadCalls }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &fakeSources{}
+			now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+			r := fakeReader(f, &now)
+
+			tt.down(f, true)
+			msg := r.read(context.Background())
+			if msg.Missing != tt.missing {
+				t.Fatalf("failing: got missing %q, want %q", msg.Missing, tt.missing)
+			}
+			// The others are read as usual; the failed one reads as zero.
+			got := [...]float64{msg.CPUTotal, msg.MemPercent, msg.Load1}
+			for i, g := range [...]metrics.Missing{metrics.MissingCPU, metrics.MissingMem, metrics.MissingLoad} {
+				want := [...]float64{30, 50, 1.5}[i]
+				if tt.missing.Has(g) {
+					want = 0
+				}
+				if got[i] != want {
+					t.Errorf("failing: %q got %v, want %v", g, got[i], want)
+				}
+			}
+
+			// Waiting out the backoff, it is not queried at all.
+			before := tt.calls(f)
+			now = now.Add(ui.DefaultInterval)
+			if msg := r.read(context.Background()); msg.Missing != tt.missing || tt.calls(f) != before {
+				t.Errorf("in backoff: got missing %q after %d more queries", msg.Missing, tt.calls(f)-before)
+			}
+
+			// After it, the subsystem is retried and recovers.
+			tt.down(f, false)
+			now = now.Add(statsBackoffMin)
+			if msg := r.read(context.Background()); msg.Missing != 0 || tt.calls(f) != before+1 {
+				t.Errorf("recovered: got missing %q after %d more queries", msg.Missing, tt.calls(f)-before)
+			}
+		})
+	}
+}
+
+func TestStatsReaderBackoff(t *testing.T) {
+	f := &fakeSources{memDown: true}
+	t0 := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	now := t0
+	r := fakeReader(f, &now)
+
+	// Tick for two minutes and note when mem was queried.
+	var queried []time.Duration
+	for now.Sub(t0) <= 2*time.Minute {
+		calls := f.memCalls
+		r.read(context.Background())
+		if f.memCalls > calls {
+			queried = append(queried, now.Sub(t0))
+		}
+		if f.cpuCalls != int(now.Sub(t0)/ui.DefaultInterval)+1 {
+			t.Fatalf("at %v: cpu got %d queries; it should be read every tick", now.Sub(t0), f.cpuCalls)
+		}
+		now = now.Add(ui.DefaultInterval)
+	}
+	want := []time.Duration{0, time.Second, 3 * time.Second, 7 * time.Second, 15 * time.Second,
+		31 * time.Second, 61 * time.Second, 91 * time.Second}
+	if !slices.Equal(queried, want) {
+		t.Errorf("mem queried at %v, want %v (doubling from 1s, capped at 30s)", queried, want)
+	}
+
+	// Recovery resets the backoff: the next failure waits the minimum again.
+	f.memDown = false
+	now = t0.Add(121 * time.Second)
+	r.read(context.Background())
+	f.memDown = true
+	now = now.Add(ui.DefaultInterval)
+	r.read(context.Background())
+	calls := f.memCalls
+	now = now.Add(statsBackoffMin)
+	r.read(context.Background())
+	if f.memCalls != calls+1 {
+		t.Error("after recovering, a new failure was not retried after the minimum backoff")
+	}
+}
+
+func TestStatsReaderEmptyCPU(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	r := fakeReader(&fakeSources{}, &now)
+	r.src.cpu = func(context.Context) ([]float64, error) { return nil, nil }
+	if msg := r.read(context.Background()); msg.Missing != metrics.MissingCPU {
+		t.Errorf("got missing %q, want cpu", msg.Missing)
+	}
+	if !r.cpu.due(now.Add(ui.DefaultInterval)) {
+		t.Error("a reading with no cores started a backoff")
+	}
+}
+
+// Swap is read with the memory; a machine whose swap cannot be read still
+// has its memory, and swap backs off on its own.
+func TestStatsReaderSwap(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	r := fakeReader(&fakeSources{}, &now)
+	if msg := r.read(context.Background()); !msg.HasSwap || msg.SwapTotalGB != 4 || msg.SwapPercent != 25 {
+		t.Errorf("got swap %v / %v (%v%%), %v", msg.SwapUsedGB, msg.SwapTotalGB, msg.SwapPercent, msg.HasSwap)
+	}
+	r.src.swap = func(context.Context) (*mem.SwapMemoryStat, error) { return nil, errFake }
+	now = now.Add(ui.DefaultInterval)
+	if msg := r.read(context.Background()); msg.HasSwap || msg.Missing.Has(metrics.MissingMem) {
+		t.Errorf("swap down: got hasSwap %v, missing %q", msg.HasSwap, msg.Missing)
+	}
+	if r.swap.due(now) || !r.mem.due(now) {
+		t.Error("swap did not back off on its own")
+	}
+}
+
+// A query that hangs, as a read of /proc behind a dead NFS mount does, is
+// abandoned at its deadline and never has a second goroutine stacked on it.
+func TestStatsReaderHungQuery(t *testing.T) {
+	f := &fakeSources{}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	r := fakeReader(f, &now)
+	r.timeout = 20 * time.Millisecond
+	release := make(chan struct{})
+	var started atomic.Int32
+	r.src.mem = func(context.Context) (*mem.VirtualMemoryStat, error) {
+		started.Add(1)
+		<-release // ignores its context
+		return &mem.VirtualMemoryStat{UsedPercent: 50}, nil
+	}
+
+	goroutines := runtime.NumGoroutine()
+	for i := range 20 {
+		begin := time.Now()
+		msg := r.read(context.Background())
+		if took := time.Since(begin); took > time.Second {
+			t.Fatalf("read %d: took %v with mem hung", i, took)
+		}
+		if msg.Missing != metrics.MissingMem || msg.TimedOut != metrics.MissingMem {
+			t.Fatalf("read %d: got missing %q, timed out %q, want mem for both", i, msg.Missing, msg.TimedOut)
+		}
+		now = now.Add(statsBackoffMax) // every read finds mem due again
+	}
+	if n := started.Load(); n != 1 {
+		t.Errorf("the hung query was started %d times, want 1", n)
+	}
+	if n := runtime.NumGoroutine(); n > goroutines+1 {
+		t.Errorf("got %d goroutines after 20 reads, had %d before", n, goroutines)
+	}
+
+	// Once it returns, mem is queried again as usual.
+	close(release)
+	for deadline := time.Now().Add(5 * time.Second); r.mem.busy.Load(); {
+		if time.Now().After(deadline) {
+			t.Fatal("the abandoned query never finished")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if msg := r.read(context.Background()); msg.Missing != 0 || msg.TimedOut != 0 {
+		t.Errorf("after the hang: got missing %q, timed out %q", msg.Missing, msg.TimedOut)
+	}
+}
+
+// The first CPU query after start, and after a stop, only primes the
+// snapshot: what is shown and logged is the delta from it, not the spike
+// gopsutil reports from its init-time snapshot.
+func TestStatsReaderPrimesCPU(t *testing.T) {
+	var calls int
+	src := (&fakeSources{}).sources()
+	src.cpu = func(context.Context) ([]float64, error) {
+		if calls++; calls == 1 {
+			return []float64{100, 100}, nil
+		}
+		return []float64{20, 40}, nil
+	}
+	r := newStatsReader(src, ui.DefaultInterval)
+	r.primeWait = time.Millisecond
+
+	msg := r.read(context.Background())
+	if msg.Missing != 0 || msg.CPUTotal != 30 || calls != 2 {
+		t.Fatalf("first reading: got cpu %v, missing %q after %d queries, want 30 from the second", msg.CPUTotal, msg.Missing, calls)
+	}
+	if msg.Took >= r.primeWait {
+		t.Errorf("first reading: took %v, want the priming wait left out", msg.Took)
+	}
+
+	var out bytes.Buffer
+	lgr, err := syslogger.NewWriter(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = ui.New(ui.WithRecorder(&recorder{logger: lgr})).Update(msg)
+	lgr.Flush()
+	if _, samples := readLog(t, &out); len(samples) != 1 || samples[0].CpuTotal != 30 {
+		t.Errorf("logged: got %+v, want one sample of cpu 30", samples)
+	}
+
+	if r.read(context.Background()); calls != 3 {
+		t.Errorf("primed: got %d queries, want 3", calls)
+	}
+	r.discardCPU()
+	if msg := r.read(context.Background()); msg.CPUTotal != 30 || calls != 5 {
+		t.Errorf("after a stop: got cpu %v after %d queries, want 30 after 5", msg.CPUTotal, calls)
+	}
+}
+
+// The deadline of each query follows the sampling interval: a slow but
+// healthy query that fits in two intervals is not timed out.
+func TestStatsReaderTimeoutFollowsInterval(t *testing.T) {
+	src := (&fakeSources{}).sources()
+	src.mem = func(context.Context) (*mem.VirtualMemoryStat, error) {
+		time.Sleep(30 * time.Millisecond)
+		return &mem.VirtualMemoryStat{UsedPercent: 50}, nil
+	}
+	for _, tt := range []struct {
+		interval time.Duration
+		timedOut bool
+	}{
+		{10 * time.Millisecond, true},
+		{200 * time.Millisecond, false},
+	} {
+		r := newStatsReader(src, tt.interval)
+		r.unprimed.Store(false)
+		if msg := r.read(context.Background()); msg.TimedOut.Has(metrics.MissingMem) != tt.timedOut {
+			t.Errorf("every %v: got timed out %q, want mem timed out %v", tt.interval, msg.TimedOut, tt.timedOut)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/shirou/gopsutil/v3/host"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+// ── TEMP panel ────────────────────────────────────────────────────────────────
+//
+// The hottest sensor of each group the kernel reports, coretemp, acpitz,
+// nvme and so on, read with the stats on every tick.  The panel is hidden
+// until a reading has found a sensor, so that a VM, or Windows without
+// the drivers, shows none rather than an empty box; f switches between
+// Celsius and Fahrenheit.
+
+// groupTemps groups stats by chip, the part of each sensor key before its
+// first underscore, and keeps the hottest of each, in the order of their
+// names.  A reading of zero or below is a sensor with nothing attached.
+func groupTemps(stats []host.TemperatureStat) []ui.TempGroup {
+	var out []ui.TempGroup
+	for _, s := range stats {
+		if s.Temperature <= 0 {
+			continue
+		}
+		name, sensor, _ := strings.Cut(s.SensorKey, "_")
+		i := slices.IndexFunc(out, func(g ui.TempGroup) bool { return g.Name == name })
+		if i < 0 {
+			out = append(out, ui.TempGroup{Name: name})
+			i = len(out) - 1
+		}
+		g := &out[i]
+		g.Sensors++
+		if g.Sensors == 1 || s.Temperature > g.Celsius {
+			g.Sensor, g.Celsius = sensor, s.Temperature
+		}
+	}
+	slices.SortFunc(out, func(a, b ui.TempGroup) int { return cmp.Compare(a.Name, b.Name) })
+	return out
+}
+
+// readTemps reads the sensors into msg, if the panel is shown and the
+// subsystem is due.  gopsutil reports the sensors it could not read as an
+// error beside those it could; only a reading with none fails.
+func (r *statsReader) readTemps(ctx context.Context, msg *ui.Reading) {
+	if !r.tempOn || !r.temp.due(r.now()) {
+		return
+	}
+	stats, _ := query(ctx, &r.temp, r.timeout, r.src.temps)
+	if len(stats) == 0 {
+		r.temp.failed(r.now())
+		return
+	}
+	r.temp.recovered()
+	msg.Temps = groupTemps(stats)
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/host"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+func TestGroupTemps(t *testing.T) {
+	got := groupTemps([]host.TemperatureStat{
+		{SensorKey: "nvme_composite", Temperature: 41},
+		{SensorKey: "coretemp_core_0", Temperature: 55},
+		{SensorKey: "coretemp_package_id_0", Temperature: 62},
+		{SensorKey: "coretemp_core_1", Temperature: 58},
+		{SensorKey: "acpitz", Temperature: 27.8},
+		{SensorKey: "iwlwifi_1", Temperature: 0},
+	})
+	want := []ui.TempGroup{
+		{Name: "acpitz", Celsius: 27.8, Sensors: 1},
+		{Name: "coretemp", Sensor: "package_id_0", Celsius: 62, Sensors: 3},
+		{Name: "nvme", Sensor: "composite", Celsius: 41, Sensors: 1},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got := groupTemps(nil); got != nil {
+		t.Errorf("no sensors: got %v", got)
+	}
+}
+
+// Sensors are read only for the panel; gopsutil's partial errors are
+// ignored, and a machine with none backs off.
+func TestReadTemps(t *testing.T) {
+	var f fakeSources
+	now := time.Unix(1700000000, 0)
+	r := fakeReader(&f, &now)
+	r.src.temps = func(context.Context) ([]host.TemperatureStat, error) {
+		return []host.TemperatureStat{{SensorKey: "acpitz", Temperature: 30}}, errFake
+	}
+	if msg := r.read(context.Background()); msg.Temps != nil {
+		t.Errorf("without the panel: got %v", msg.Temps)
+	}
+	r.tempOn = true
+	if msg := r.read(context.Background()); !slices.Equal(msg.Temps, []ui.TempGroup{{Name: "acpitz", Celsius: 30, Sensors: 1}}) {
+		t.Errorf("got %v", msg.Temps)
+	}
+	r.src.temps = func(context.Context) ([]host.TemperatureStat, error) { return nil, errFake }
+	now = now.Add(ui.DefaultInterval)
+	if msg := r.read(context.Background()); msg.Temps != nil || msg.Missing != 0 {
+		t.Errorf("no sensors: got %v, missing %q", msg.Temps, msg.Missing)
+	}
+	if r.temp.due(now) {
+		t.Error("a reading with no sensors did not back off")
+	}
+}
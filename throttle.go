@@ -0,0 +1,132 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"io/fs"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+// ── Thermal throttling ────────────────────────────────────────────────────────
+//
+// A CPU throttled for heat runs slower than its clock, and its percentages
+// understate how hard it is working.  While it is, the CPU panel's title
+// has a red THROTTLED badge, and each sample has cpu_throttled set.  On
+// Linux the kernel counts the throttling events of each core and package
+// under /sys/devices/system/cpu/cpu*/thermal_throttle, read on every stats
+// tick: the CPU is throttled while a count went up since the last reading,
+// and the badge clears at the first reading after that without.  On macOS
+// pmset -g therm reports the speed limit the system has set, read every
+// pmsetInterval since it runs a command.  Elsewhere, and on a machine
+// without the counters, nothing is shown.
+
+// pmsetInterval is how often pmset is run.
+const pmsetInterval = 5 * time.Second
+
+// readThrottleCounts sums the thermal throttle counts under fsys, a
+// /sys/devices/system/cpu.  Every CPU of a package repeats the package's
+// count, which leaves the sum rising when it does.  A count that cannot
+// be read, as that of a CPU going offline, is left out.
+func readThrottleCounts(fsys fs.FS) (uint64, int, error) {
+	files, err := fs.Glob(fsys, "cpu[0-9]*/thermal_throttle/*_throttle_count")
+	if err != nil {
+		return 0, 0, err
+	}
+	var sum uint64
+	n := 0
+	for _, name := range files {
+		b, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			continue
+		}
+		v, err := strconv.ParseUint(strings.TrimSpace(string(b)), 10, 64)
+		if err != nil {
+			continue
+		}
+		sum += v
+		n++
+	}
+	return sum, n, nil
+}
+
+// parsePmsetTherm reads the output of pmset -g therm: the CPU is limited
+// where the speed or scheduler limit is below 100, or a thermal or
+// performance warning level is set above zero.
+func parsePmsetTherm(out string) bool {
+	sc := bufio.NewScanner(strings.NewReader(out))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if key, val, ok := strings.Cut(line, "="); ok {
+			key = strings.TrimSpace(key)
+			if key != "CPU_Speed_Limit" && key != "CPU_Scheduler_Limit" {
+				continue
+			}
+			if v, err := strconv.Atoi(strings.TrimSpace(val)); err == nil && v < 100 {
+				return true
+			}
+			continue
+		}
+		if _, level, ok := strings.Cut(line, "warning level set to "); ok {
+			if v, err := strconv.Atoi(strings.TrimSuffix(level, ".")); err == nil && v > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// throttleWatch reads the throttling.
+type throttleWatch struct {
+	read  func(context.Context) (ui.ThrottleReading, error)
+	every time.Duration // between readings; 0 for every stats tick
+	sub   subsystem
+	now   func() time.Time
+	last  time.Time // of the last reading started
+}
+
+// sysfsThrottle returns a throttleWatch of the counts under fsys, a
+// /sys/devices/system/cpu, if there are any, and nil if not.
+func sysfsThrottle(fsys fs.FS) *throttleWatch {
+	if fsys == nil {
+		return nil
+	}
+	if _, n, err := readThrottleCounts(fsys); err != nil || n == 0 {
+		return nil
+	}
+	return &throttleWatch{
+		read: func(context.Context) (ui.ThrottleReading, error) {
+			sum, _, err := readThrottleCounts(fsys)
+			return ui.ThrottleReading{Events: sum, Counted: true}, err
+		},
+		now: time.Now,
+	}
+}
+
+// readCmd reads the throttling off the Update goroutine, or returns nil
+// until every has passed since the last reading, while it runs, or while
+// a failure is backed off.
+func (w *throttleWatch) readCmd(ctx context.Context) tea.Cmd {
+	now := w.now()
+	if now.Sub(w.last) < w.every || !w.sub.due(now) || w.sub.busy.Load() {
+		return nil
+	}
+	w.last = now
+	return func() tea.Msg {
+		r, err := query(ctx, &w.sub, statsCallTimeout, w.read)
+		if err != nil {
+			w.sub.failed(w.now())
+			return ui.ThrottleMsg{}
+		}
+		w.sub.recovered()
+		return ui.ThrottleMsg{Reading: r, OK: true}
+	}
+}
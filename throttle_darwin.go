@@ -0,0 +1,33 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+//go:build darwin
+
+package main
+
+import (
+	"context"
+	"os/exec"
+	"time"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+// detectThrottle watches the speed limit pmset reports, where pmset is.
+func detectThrottle() *throttleWatch {
+	path, err := exec.LookPath("pmset")
+	if err != nil {
+		return nil
+	}
+	return &throttleWatch{
+		read: func(ctx context.Context) (ui.ThrottleReading, error) {
+			out, err := exec.CommandContext(ctx, path, "-g", "therm").Output()
+			if err != nil {
+				return ui.ThrottleReading{}, err
+			}
+			return ui.ThrottleReading{Limited: parsePmsetTherm(string(out))}, nil
+		},
+		every: pmsetInterval,
+		now:   time.Now,
+	}
+}
@@ -0,0 +1,16 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+//go:build linux
+
+package main
+
+import (
+	"os"
+)
+
+// detectThrottle watches the throttle counts under
+// /sys/devices/system/cpu, where the kernel has them.
+func detectThrottle() *throttleWatch {
+	return sysfsThrottle(os.DirFS("/sys/devices/system/cpu"))
+}
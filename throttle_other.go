@@ -0,0 +1,10 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+//go:build !linux && !darwin
+
+package main
+
+// detectThrottle is nil here: there is no throttling to read, and the CPU
+// panel has no badge.
+func detectThrottle() *throttleWatch { return nil }
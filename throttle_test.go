@@ -0,0 +1,167 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+
+	syslogger "github.com/ALH477/infgo/logger"
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ui"
+)
+
+// throttleFS is a /sys/devices/system/cpu of two CPUs in one package, the
+// second without a core count that can be read.
+func throttleFS() fstest.MapFS {
+	return fstest.MapFS{
+		"cpu0/thermal_throttle/core_throttle_count":    {Data: []byte("3\n")},
+		"cpu0/thermal_throttle/package_throttle_count": {Data: []byte("10\n")},
+		"cpu1/thermal_throttle/core_throttle_count":    {Data: []byte("lots\n")},
+		"cpu1/thermal_throttle/package_throttle_count": {Data: []byte("10\n")},
+		"cpufreq/policy0/scaling_cur_freq":             {Data: []byte("2400000\n")},
+	}
+}
+
+func TestReadThrottleCounts(t *testing.T) {
+	if sum, n, err := readThrottleCounts(throttleFS()); sum != 23 || n != 3 || err != nil {
+		t.Errorf("got %d of %d, %v", sum, n, err)
+	}
+	if w := sysfsThrottle(fstest.MapFS{"cpu0/online": {Data: []byte("1\n")}}); w != nil {
+		t.Error("a watch without the counters")
+	}
+	if w := sysfsThrottle(nil); w != nil {
+		t.Error("a watch without sysfs")
+	}
+}
+
+func TestParsePmsetTherm(t *testing.T) {
+	for _, tt := range []struct {
+		name, out string
+		want      bool
+	}{
+		{"nothing recorded", "Note: No thermal warning level has been recorded\nNote: No performance warning level has been recorded\nNote: No CPU power status has been recorded\n", false},
+		{"full speed", "2026-10-17 09:12:01 +0000 CPU Power notify\n\tCPU_Scheduler_Limit \t= 100\n\tCPU_Available_CPUs \t= 10\n\tCPU_Speed_Limit \t= 100\n", false},
+		{"speed limited", "2026-10-17 09:12:01 +0000 CPU Power notify\n\tCPU_Scheduler_Limit \t= 100\n\tCPU_Available_CPUs \t= 10\n\tCPU_Speed_Limit \t= 62\n", true},
+		{"scheduler limited", "\tCPU_Scheduler_Limit \t= 50\n\tCPU_Speed_Limit \t= 100\n", true},
+		{"thermal warning", "Thermal warning level set to 1.\nNote: No performance warning level has been recorded\n", true},
+		{"warning cleared", "Thermal warning level set to 0.\n", false},
+		{"empty", "", false},
+	} {
+		if got := parsePmsetTherm(tt.out); got != tt.want {
+			t.Errorf("%s: got %v", tt.name, got)
+		}
+	}
+}
+
+// The CPU is throttled while a count went up since the last reading, and
+// the badge clears at the first reading without; where the platform says
+// whether it is limited, it is throttled while it is.
+func TestThrottleState(t *testing.T) {
+	var s ui.ThrottleState
+	for i, tt := range []struct {
+		msg  ui.ThrottleMsg
+		want bool
+	}{
+		{ui.ThrottleMsg{Reading: ui.ThrottleReading{Events: 23, Counted: true}, OK: true}, false}, // the first
+		{ui.ThrottleMsg{Reading: ui.ThrottleReading{Events: 23, Counted: true}, OK: true}, false},
+		{ui.ThrottleMsg{Reading: ui.ThrottleReading{Events: 31, Counted: true}, OK: true}, true},
+		{ui.ThrottleMsg{Reading: ui.ThrottleReading{Events: 40, Counted: true}, OK: true}, true},
+		{ui.ThrottleMsg{Reading: ui.ThrottleReading{Events: 40, Counted: true}, OK: true}, false},
+		{ui.ThrottleMsg{Reading: ui.ThrottleReading{Events: 41, Counted: true}, OK: true}, true},
+		{ui.ThrottleMsg{}, false}, // failed
+		{ui.ThrottleMsg{Reading: ui.ThrottleReading{Limited: true}, OK: true}, true},
+		{ui.ThrottleMsg{Reading: ui.ThrottleReading{}, OK: true}, false},
+	} {
+		if s.Observe(tt.msg); s.Active != tt.want {
+			t.Errorf("reading %d: got %v", i, s.Active)
+		}
+	}
+}
+
+// The watch reads on every tick, or every every, and a reading of the
+// counters makes its way to the badge.
+func TestThrottleWatch(t *testing.T) {
+	fsys := throttleFS()
+	w := sysfsThrottle(fsys)
+	if w == nil {
+		t.Fatal("no watch")
+	}
+	now := time.Unix(1700000000, 0)
+	w.now = func() time.Time { return now }
+	m := sizedModel(100, 50)
+	for i, count := range []string{"3", "3", "9"} {
+		fsys["cpu0/thermal_throttle/core_throttle_count"] = &fstest.MapFile{Data: []byte(count + "\n")}
+		cmd := w.readCmd(context.Background())
+		if cmd == nil {
+			t.Fatalf("reading %d: no command", i)
+		}
+		tm, _ := m.Update(cmd())
+		m = tm.(ui.Model)
+		now = now.Add(ui.DefaultInterval)
+	}
+	if got := ansi.Strip(m.View()); !strings.Contains(got, "THROTTLED") {
+		t.Errorf("got\n%s", got)
+	}
+	if got := ansi.Strip(pressKeys(m, "1").View()); !strings.Contains(got, "THROTTLED") {
+		t.Errorf("CPU collapsed: got\n%s", got)
+	}
+
+	w.every = pmsetInterval
+	if cmd := w.readCmd(context.Background()); cmd != nil {
+		t.Error("read again before every")
+	}
+}
+
+// The headless collector reads the throttling off its loop and records it
+// as the TUI does.
+func TestHeadlessThrottled(t *testing.T) {
+	fsys := throttleFS()
+	w := sysfsThrottle(fsys)
+	now := time.Unix(1700000000, 0)
+	w.now = func() time.Time { return now }
+	h := &headless{watched: make(chan tea.Msg, 1), throttleWatch: w}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for _, count := range []string{"3", "9"} {
+		fsys["cpu0/thermal_throttle/core_throttle_count"] = &fstest.MapFile{Data: []byte(count + "\n")}
+		h.poll(ctx)
+		select {
+		case msg := <-h.watched:
+			h.observe(msg)
+		case <-time.After(5 * time.Second):
+			t.Fatal("no reading delivered")
+		}
+		now = now.Add(ui.DefaultInterval)
+	}
+
+	var out bytes.Buffer
+	lgr, err := syslogger.NewWriter(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.logger, h.throttleWatch, h.interval = lgr, nil, 10*time.Millisecond
+	reads := 0
+	h.read = func(context.Context) ui.Reading {
+		if reads++; reads > 1 {
+			cancel()
+			return ui.Reading{Missing: metrics.MissingAll}
+		}
+		return ui.Reading{CPUTotal: 60, CPUCores: []float64{60}}
+	}
+	if err := h.run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	lgr.Flush()
+	if _, samples := readLog(t, &out); len(samples) != 1 || !samples[0].CpuThrottled {
+		t.Errorf("logged: got %+v", samples)
+	}
+}
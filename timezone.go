@@ -0,0 +1,126 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// ── Time zones (-tz) ──────────────────────────────────────────────────────────
+
+// localZoneName is the IANA name of this host's zone, e.g. Europe/Berlin,
+// or "" where it cannot be told: Go's time.Local does not keep the name
+// it was loaded under.
+func localZoneName() string {
+	name, set := os.LookupEnv("TZ")
+	name = strings.TrimPrefix(name, ":")
+	switch {
+	case set && name == "":
+		return "UTC" // as Go reads an empty $TZ
+	case !set && runtime.GOOS != "windows":
+		dst, err := os.Readlink("/etc/localtime")
+		if errors.Is(err, fs.ErrNotExist) {
+			return "UTC" // Go's fallback too
+		}
+		_, name, _ = strings.Cut(dst, "zoneinfo/")
+	}
+	if name == "" {
+		return ""
+	}
+	if _, err := time.LoadLocation(name); err != nil {
+		return ""
+	}
+	return name
+}
+
+// timeZone is the zone the offline tools give times in: a -tz choice
+// resolved against a capture.
+type timeZone struct {
+	loc  *time.Location
+	name string // as the report names it
+}
+
+// tzUsage is the help text of every -tz flag.
+const tzUsage = "give times in `zone`: local, utc, capture (that of the host which recorded it) or an IANA name such as Europe/Berlin"
+
+// addTZFlag registers -tz on fs.  Names are checked as they are parsed,
+// so resolveZone only fails over for a capture that does not say.
+func addTZFlag(fs *flag.FlagSet) *string {
+	tz := "local"
+	fs.Func("tz", tzUsage+" (default local)", func(v string) error {
+		switch v {
+		case "local", "utc", "capture":
+		default:
+			if _, err := time.LoadLocation(v); err != nil {
+				return fmt.Errorf("unknown zone %q", v)
+			}
+		}
+		tz = v
+		return nil
+	})
+	return &tz
+}
+
+// resolveZone is the zone the -tz choice spec stands for in a capture
+// with header h.  "capture" falls back to local time for captures from
+// before the header recorded a zone.
+func resolveZone(spec string, h *metrics.Header) timeZone {
+	switch spec {
+	case "", "local":
+		if name := localZoneName(); name != "" {
+			return timeZone{time.Local, "local time (" + name + ")"}
+		}
+		return timeZone{time.Local, "local time"}
+	case "utc":
+		return timeZone{time.UTC, "UTC"}
+	case "capture":
+		if loc, name := captureZone(h); loc != nil {
+			return timeZone{loc, name + " (capture host)"}
+		}
+		return timeZone{time.Local, "local time (the capture does not record its zone)"}
+	}
+	loc, err := time.LoadLocation(spec)
+	if err != nil {
+		return timeZone{time.UTC, "UTC"} // checked by addTZFlag
+	}
+	return timeZone{loc, spec}
+}
+
+// captureZone is the zone h was recorded in and its name, or nil if h
+// does not record one.  A name this host's zone database lacks falls
+// back to the offset at the start, which is right until the next change
+// of daylight saving.
+func captureZone(h *metrics.Header) (*time.Location, string) {
+	if h == nil {
+		return nil, ""
+	}
+	if h.Timezone != "" {
+		if loc, err := time.LoadLocation(h.Timezone); err == nil {
+			return loc, h.Timezone
+		}
+	}
+	if h.Timezone == "" && h.UTCOffsetS == 0 {
+		return nil, "" // unrecorded, rather than UTC
+	}
+	name := formatUTCOffset(int(h.UTCOffsetS))
+	return time.FixedZone(name, int(h.UTCOffsetS)), name
+}
+
+// formatUTCOffset writes an offset east of UTC in seconds as UTC+02:00.
+func formatUTCOffset(s int) string {
+	sign := '+'
+	if s < 0 {
+		sign, s = '-', -s
+	}
+	return fmt.Sprintf("UTC%c%02d:%02d", sign, s/3600, s%3600/60)
+}
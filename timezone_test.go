@@ -0,0 +1,184 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ALH477/infgo/analysis"
+	syslogger "github.com/ALH477/infgo/logger"
+	"github.com/ALH477/infgo/metrics"
+)
+
+// inBerlin makes this process's local zone Europe/Berlin for the rest of
+// the test.
+func inBerlin(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("no zone database: %v", err)
+	}
+	t.Setenv("TZ", "Europe/Berlin")
+	prev := time.Local
+	time.Local = loc
+	t.Cleanup(func() { time.Local = prev })
+}
+
+// dstCapture is an hour recorded in New York across the start of daylight
+// saving, 01:30 EST to 03:30 EDT on 2026-03-08, one sample a minute.
+func dstCapture() *analysis.Capture {
+	start := time.Date(2026, 3, 8, 6, 30, 0, 0, time.UTC)
+	c := &analysis.Capture{Header: &metrics.Header{
+		Hostname: "nyc1", StartedUnixMs: start.UnixMilli(), Timezone: "America/New_York", UTCOffsetS: -5 * 3600,
+	}}
+	for i := 0; i <= 60; i++ {
+		at := start.Add(time.Duration(i) * time.Minute)
+		c.Samples = append(c.Samples, metrics.Sample{TimestampUnixMs: at.UnixMilli(), CpuTotal: 20, MemPercent: 50})
+	}
+	return c
+}
+
+func TestZoneModesAcrossDST(t *testing.T) {
+	inBerlin(t)
+	c := dstCapture()
+	tests := []struct {
+		tz           string
+		name         string
+		started      string
+		ended        string
+		firstStamp   string // the report's timeline axis
+		csvFirst     string
+		csvLast      string
+		summaryStart string
+	}{
+		{"local", "local time (Europe/Berlin)", "2026-03-08 07:30:00 CET", "2026-03-08 08:30:00 CET", "07:30:00",
+			"2026-03-08T07:30:00.000+01:00", "2026-03-08T08:30:00.000+01:00", "Started    2026-03-08 07:30:00 CET"},
+		{"utc", "UTC", "2026-03-08 06:30:00 UTC", "2026-03-08 07:30:00 UTC", "06:30:00",
+			"2026-03-08T06:30:00.000Z", "2026-03-08T07:30:00.000Z", "Started    2026-03-08 06:30:00 UTC"},
+		{"capture", "America/New_York (capture host)", "2026-03-08 01:30:00 EST", "2026-03-08 03:30:00 EDT", "01:30:00",
+			"2026-03-08T01:30:00.000-05:00", "2026-03-08T03:30:00.000-04:00", "Started    2026-03-08 01:30:00 EST"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tz, func(t *testing.T) {
+			zone := resolveZone(tt.tz, c.Header)
+			if zone.name != tt.name {
+				t.Errorf("zone: got %q, want %q", zone.name, tt.name)
+			}
+
+			var md bytes.Buffer
+			writeMarkdownReport(&md, c, 80, 3, zone)
+			for _, want := range []string{
+				"| Started | " + tt.started + " |", "| Ended | " + tt.ended + " |",
+				"| Zone | " + mdEscape(tt.name) + " |", "     " + tt.firstStamp + " ",
+			} {
+				if !strings.Contains(md.String(), want) {
+					t.Errorf("report missing %q:\n%s", want, md.String())
+				}
+			}
+
+			var text bytes.Buffer
+			printSummary(&text, c, analysis.SummarizeSamples(c.Samples), nil, zone)
+			for _, want := range []string{tt.summaryStart, "Zone       " + tt.name} {
+				if !strings.Contains(text.String(), want) {
+					t.Errorf("analyze missing %q:\n%s", want, text.String())
+				}
+			}
+
+			var csv bytes.Buffer
+			if err := writeSamplesCSV(&csv, c.Samples, &zone); err != nil {
+				t.Fatal(err)
+			}
+			lines := strings.Split(strings.TrimSpace(csv.String()), "\n")
+			first, last := lines[1], lines[len(lines)-1]
+			if want := "1772951400000," + tt.csvFirst + ",20,"; !strings.HasPrefix(first, want) {
+				t.Errorf("first row: got %q, want it to start %q", first, want)
+			}
+			if want := "1772955000000," + tt.csvLast + ",20,"; !strings.HasPrefix(last, want) {
+				t.Errorf("last row: got %q, want it to start %q", last, want)
+			}
+		})
+	}
+}
+
+func TestCaptureZoneFallbacks(t *testing.T) {
+	inBerlin(t)
+	tests := []struct {
+		h    *metrics.Header
+		name string
+	}{
+		{nil, "local time (the capture does not record its zone)"},
+		{&metrics.Header{}, "local time (the capture does not record its zone)"},
+		{&metrics.Header{Timezone: "Mars/Olympus_Mons", UTCOffsetS: 5*3600 + 1800}, "UTC+05:30 (capture host)"},
+		{&metrics.Header{UTCOffsetS: -3 * 3600}, "UTC-03:00 (capture host)"},
+		{&metrics.Header{Timezone: "UTC"}, "UTC (capture host)"},
+	}
+	for _, tt := range tests {
+		if got := resolveZone("capture", tt.h); got.name != tt.name {
+			t.Errorf("%+v: got %q, want %q", tt.h, got.name, tt.name)
+		}
+	}
+	if z := resolveZone("Asia/Tokyo", nil); z.name != "Asia/Tokyo" || z.loc.String() != "Asia/Tokyo" {
+		t.Errorf("named zone: got %q in %v", z.name, z.loc)
+	}
+
+	fs := flag.NewFlagSet("t", flag.ContinueOnError)
+	fs.SetOutput(new(bytes.Buffer))
+	addTZFlag(fs)
+	if err := fs.Parse([]string{"-tz", "Nowhere/Land"}); err == nil {
+		t.Error("-tz Nowhere/Land: got no error")
+	}
+}
+
+// export csv keeps the Unix timestamps of import csv's round trip and
+// only adds the time column when -tz is given.
+func TestExportCSVTimeColumn(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "in.infgo")
+	lgr, err := syslogger.New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := dstCapture()
+	lgr.WriteHeader(*c.Header)
+	for _, s := range c.Samples[:2] {
+		lgr.WriteSample(s)
+	}
+	if err := lgr.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	out := filepath.Join(dir, "out.csv")
+	read := func(args ...string) string {
+		t.Helper()
+		if err := runExportCSV(append([]string{path, "-o", out}, args...)); err != nil {
+			t.Fatal(err)
+		}
+		b, err := os.ReadFile(out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+	if got := read(); !strings.HasPrefix(got, "timestamp,cpu_total,") || !strings.Contains(got, "\n1772951400000,20,") {
+		t.Errorf("without -tz: got\n%s", got)
+	}
+	got := read("-tz", "capture")
+	if !strings.HasPrefix(got, "timestamp,time,cpu_total,") ||
+		!strings.Contains(got, "\n1772951400000,2026-03-08T01:30:00.000-05:00,20,") {
+		t.Errorf("-tz capture: got\n%s", got)
+	}
+
+	res, err := readCSVSamples(strings.NewReader(got), csvOptions{CorePattern: "core_*", Loc: time.UTC})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Samples) != 2 || res.Skipped != 0 || res.Samples[0].TimestampUnixMs != c.Samples[0].TimestampUnixMs || res.Samples[1].MemPercent != 50 {
+		t.Errorf("imported back: got %+v", res)
+	}
+}
@@ -0,0 +1,288 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	syslogger "github.com/ALH477/infgo/logger"
+	"github.com/ALH477/infgo/metrics"
+)
+
+// ── trim ──────────────────────────────────────────────────────────────────────
+
+// runTrim implements `infgo trim <in.infgo> [-from T] [-to T] -o <out.infgo>`.
+func runTrim(args []string) error {
+	fs := newFlagSet("trim", "<capture.infgo> [-from T] [-to T] -o <out.infgo>")
+	from := fs.String("from", "", "start of the kept range (RFC3339, HH:MM[:SS], or +/-offset); default capture start")
+	to := fs.String("to", "", "end of the kept range (RFC3339, HH:MM[:SS], or +/-offset); default capture end")
+	out := fs.String("o", "", "write the trimmed capture to `file`")
+
+	pos, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 1 {
+		return usageErrorf(fs, "expected exactly one input capture")
+	}
+	if *out == "" {
+		return usageErrorf(fs, "-o is required")
+	}
+	if pos[0] == stdinPath {
+		return errNeedsFile("trim", "it reads the capture twice to resolve the time range")
+	}
+	if samePath(pos[0], *out) {
+		return fmt.Errorf("output %q would overwrite the input", *out)
+	}
+
+	span, err := scanSpan(pos[0])
+	if err != nil {
+		return err
+	}
+	start, end := span.first, span.last
+	lo, err := resolveTimeSpec(*from, start, end, time.Local, start)
+	if err != nil {
+		return fmt.Errorf("-from: %w", err)
+	}
+	hi, err := resolveTimeSpec(*to, start, end, time.Local, end)
+	if err != nil {
+		return fmt.Errorf("-to: %w", err)
+	}
+	if hi.Before(lo) {
+		return fmt.Errorf("range is inverted: -from %s is after -to %s", fmtStamp(lo), fmtStamp(hi))
+	}
+
+	n, err := trimCapture(pos[0], *out, lo, hi)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("infgo: kept %d of %d samples (%s – %s) → %s\n",
+		n, span.samples, fmtStamp(lo), fmtStamp(hi), *out)
+	return nil
+}
+
+// captureSpan is the outline of a capture gathered by a single pass over it.
+type captureSpan struct {
+	header  *metrics.Header // first header record; nil if the file has none
+	first   time.Time       // timestamp of the first sample
+	last    time.Time       // timestamp of the last sample
+	samples int
+}
+
+// scanSpan reads path once and returns its header and sample time span.
+// A capture without any samples is an error since it has no span.
+func scanSpan(path string) (captureSpan, error) {
+	var span captureSpan
+	rd, err := syslogger.Open(path)
+	if err != nil {
+		return span, err
+	}
+	defer rd.Close()
+
+	for {
+		rec, err := rd.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return span, err
+		}
+		switch {
+		case rec.Header != nil && span.header == nil:
+			span.header = rec.Header
+		case rec.Sample != nil:
+			t := rec.Sample.Time()
+			if span.samples == 0 {
+				span.first = t
+			}
+			span.last = t
+			span.samples++
+		}
+	}
+	if span.samples == 0 {
+		return span, fmt.Errorf("%q contains no samples", path)
+	}
+	return span, nil
+}
+
+// trimCapture copies the header and every sample, event and process record
+// whose timestamp lies in [lo, hi] from src to dst, returning the number of
+// samples written.
+//
+// dst is created only once the first in-range sample is found, so an empty
+// range fails without leaving a header-only file behind.
+func trimCapture(src, dst string, lo, hi time.Time) (int, error) {
+	rd, err := syslogger.Open(src)
+	if err != nil {
+		return 0, err
+	}
+	defer rd.Close()
+
+	var (
+		hdr     *metrics.Header
+		lgr     *syslogger.Logger
+		kept    int
+		pending []*syslogger.Record // in-range events and processes seen before the first sample
+	)
+	for {
+		rec, err := rd.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if lgr != nil {
+				_ = lgr.Close()
+			}
+			return kept, err
+		}
+		switch {
+		case rec.Header != nil:
+			if hdr == nil {
+				hdr = rec.Header
+			}
+		case rec.Event != nil || rec.Process != nil:
+			t := recordTime(rec)
+			if t.Before(lo) || t.After(hi) {
+				continue
+			}
+			if lgr == nil {
+				pending = append(pending, rec)
+				continue
+			}
+			if err := writeAside(lgr, rec); err != nil {
+				_ = lgr.Close()
+				return kept, err
+			}
+		case rec.Sample != nil:
+			t := rec.Sample.Time()
+			if t.Before(lo) || t.After(hi) {
+				continue
+			}
+			if lgr == nil {
+				if lgr, err = syslogger.New(dst); err != nil {
+					return 0, err
+				}
+				if hdr != nil {
+					h := *hdr
+					// The trimmed session effectively starts at the range start.
+					if ms := lo.UnixMilli(); ms > h.StartedUnixMs {
+						h.StartedUnixMs = ms
+					}
+					if err := lgr.WriteHeader(h); err != nil {
+						_ = lgr.Close()
+						return 0, err
+					}
+				}
+				for _, rec := range pending {
+					if err := writeAside(lgr, rec); err != nil {
+						_ = lgr.Close()
+						return 0, err
+					}
+				}
+			}
+			if err := lgr.WriteSample(*rec.Sample); err != nil {
+				_ = lgr.Close()
+				return kept, err
+			}
+			kept++
+		}
+	}
+	if lgr == nil {
+		return 0, fmt.Errorf("no samples between %s and %s", fmtStamp(lo), fmtStamp(hi))
+	}
+	return kept, lgr.Close()
+}
+
+// recordTime is the timestamp of rec, an event or a process.
+func recordTime(rec *syslogger.Record) time.Time {
+	if rec.Event != nil {
+		return rec.Event.Time()
+	}
+	return rec.Process.Time()
+}
+
+// writeAside appends rec, an event or a process, to lgr.
+func writeAside(lgr *syslogger.Logger, rec *syslogger.Record) error {
+	if rec.Event != nil {
+		return lgr.WriteEvent(*rec.Event)
+	}
+	return lgr.WriteProcess(*rec.Process)
+}
+
+// ── Time specifications ───────────────────────────────────────────────────────
+
+// clockLayouts are the date-less wall-clock forms accepted by resolveTimeSpec.
+var clockLayouts = []string{"15:04:05", "15:04"}
+
+// resolveTimeSpec turns a user-supplied time into an absolute instant for a
+// capture spanning [start, end].  Accepted forms:
+//
+//	""                    → def
+//	"+10m"                → start + 10m
+//	"-5m"                 → end - 5m
+//	"2026-01-14T14:30:00Z" (RFC3339)
+//	"14:30" / "14:30:05"  → that wall-clock time in loc on the capture's date
+//
+// A clock time earlier than start is moved to the following day when that
+// lands inside the capture, so overnight captures can be cut naturally.
+func resolveTimeSpec(spec string, start, end time.Time, loc *time.Location, def time.Time) (time.Time, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return def, nil
+	}
+
+	if spec[0] == '+' || spec[0] == '-' {
+		d, err := time.ParseDuration(spec[1:])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("bad offset %q: %w", spec, err)
+		}
+		if spec[0] == '+' {
+			return start.Add(d), nil
+		}
+		return end.Add(-d), nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, spec); err == nil {
+		return t, nil
+	}
+
+	for _, layout := range clockLayouts {
+		c, err := time.ParseInLocation(layout, spec, loc)
+		if err != nil {
+			continue
+		}
+		day := start.In(loc)
+		t := time.Date(day.Year(), day.Month(), day.Day(),
+			c.Hour(), c.Minute(), c.Second(), 0, loc)
+		if t.Before(start) {
+			if next := t.AddDate(0, 0, 1); !next.After(end) {
+				t = next
+			}
+		}
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("unrecognised time %q (want RFC3339, HH:MM[:SS], +offset or -offset)", spec)
+}
+
+// fmtStamp formats t for CLI messages in the local zone.
+func fmtStamp(t time.Time) string {
+	return t.Local().Format("2006-01-02 15:04:05")
+}
+
+// samePath reports whether a and b name the same file, comparing cleaned
+// absolute paths (the output file may not exist yet, so os.SameFile is
+// not an option).
+func samePath(a, b string) bool {
+	aa, err1 := filepath.Abs(a)
+	bb, err2 := filepath.Abs(b)
+	if err1 != nil || err2 != nil {
+		return filepath.Clean(a) == filepath.Clean(b)
+	}
+	return aa == bb
+}
@@ -0,0 +1,199 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	syslogger "github.com/ALH477/infgo/logger"
+	"github.com/ALH477/infgo/metrics"
+)
+
+func TestResolveTimeSpec(t *testing.T) {
+	loc := time.FixedZone("CET", 3600)
+	start := time.Date(2026, 1, 14, 14, 0, 0, 0, loc)
+	end := time.Date(2026, 1, 14, 15, 0, 0, 0, loc)
+	overnightEnd := time.Date(2026, 1, 15, 2, 0, 0, 0, loc)
+
+	tests := []struct {
+		name string
+		spec string
+		end  time.Time
+		want time.Time
+	}{
+		{"empty uses default", "", end, start},
+		{"relative to start", "+10m", end, start.Add(10 * time.Minute)},
+		{"relative to end", "-5m", end, end.Add(-5 * time.Minute)},
+		{"rfc3339", "2026-01-14T13:45:00Z", end, time.Date(2026, 1, 14, 13, 45, 0, 0, time.UTC)},
+		{"clock minutes", "14:30", end, time.Date(2026, 1, 14, 14, 30, 0, 0, loc)},
+		{"clock seconds", "14:30:15", end, time.Date(2026, 1, 14, 14, 30, 15, 0, loc)},
+		{"clock after midnight", "01:00", overnightEnd, time.Date(2026, 1, 15, 1, 0, 0, 0, loc)},
+		{"clock before capture stays on day", "13:00", end, time.Date(2026, 1, 14, 13, 0, 0, 0, loc)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveTimeSpec(tt.spec, start, tt.end, loc, start)
+			if err != nil {
+				t.Fatalf("resolveTimeSpec(%q) failed: %v", tt.spec, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("resolveTimeSpec(%q): got %v, want %v", tt.spec, got, tt.want)
+			}
+		})
+	}
+
+	for _, bad := range []string{"yesterday", "+10x", "25:99"} {
+		if _, err := resolveTimeSpec(bad, start, end, loc, start); err == nil {
+			t.Errorf("resolveTimeSpec(%q): expected error", bad)
+		}
+	}
+}
+
+// writeTestCapture writes a header plus one sample per second starting at
+// startMs and returns the file path.
+func writeTestCapture(t *testing.T, dir string, startMs int64, n int) string {
+	t.Helper()
+	path := filepath.Join(dir, "in.infgo")
+	lgr, err := syslogger.New(path)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if err := lgr.WriteHeader(metrics.Header{Hostname: "h", StartedUnixMs: startMs, NumCores: 2}); err != nil {
+		t.Fatalf("WriteHeader failed: %v", err)
+	}
+	for i := 0; i < n; i++ {
+		s := metrics.Sample{TimestampUnixMs: startMs + int64(i)*1000, CpuTotal: float64(i)}
+		if err := lgr.WriteSample(s); err != nil {
+			t.Fatalf("WriteSample failed: %v", err)
+		}
+	}
+	if err := lgr.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	return path
+}
+
+func TestTrimCapture(t *testing.T) {
+	dir := t.TempDir()
+	const startMs = 1704067200000
+	src := writeTestCapture(t, dir, startMs, 60)
+	dst := filepath.Join(dir, "out.infgo")
+
+	lo := time.UnixMilli(startMs + 10_000)
+	hi := time.UnixMilli(startMs + 19_000)
+	n, err := trimCapture(src, dst, lo, hi)
+	if err != nil {
+		t.Fatalf("trimCapture failed: %v", err)
+	}
+	if n != 10 {
+		t.Errorf("kept: got %d, want 10", n)
+	}
+
+	span, err := scanSpan(dst)
+	if err != nil {
+		t.Fatalf("scanSpan(out) failed: %v", err)
+	}
+	if span.header == nil {
+		t.Fatal("trimmed capture has no header")
+	}
+	if span.header.StartedUnixMs != lo.UnixMilli() {
+		t.Errorf("StartedUnixMs: got %d, want %d", span.header.StartedUnixMs, lo.UnixMilli())
+	}
+	if !span.first.Equal(lo) || !span.last.Equal(hi) {
+		t.Errorf("span: got %v – %v, want %v – %v", span.first, span.last, lo, hi)
+	}
+}
+
+func TestTrimCaptureEmptyRange(t *testing.T) {
+	dir := t.TempDir()
+	const startMs = 1704067200000
+	src := writeTestCapture(t, dir, startMs, 5)
+	dst := filepath.Join(dir, "out.infgo")
+
+	lo := time.UnixMilli(startMs + 60_000)
+	if _, err := trimCapture(src, dst, lo, lo.Add(time.Minute)); err == nil {
+		t.Fatal("expected error for a range with no samples")
+	}
+	if _, err := os.Stat(dst); !os.IsNotExist(err) {
+		t.Errorf("empty trim left an output file behind (stat err: %v)", err)
+	}
+}
+
+// readProcesses returns the process records in the capture at path.
+func readProcesses(t *testing.T, path string) []metrics.Process {
+	t.Helper()
+	rd, err := syslogger.Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer rd.Close()
+	var procs []metrics.Process
+	for {
+		rec, err := rd.Next()
+		if err == io.EOF {
+			return procs
+		}
+		if err != nil {
+			t.Fatalf("Next failed: %v", err)
+		}
+		if rec.Process != nil {
+			procs = append(procs, *rec.Process)
+		}
+	}
+}
+
+// writeProcessCapture writes n samples a second apart, each followed by a
+// process record half a second later.
+func writeProcessCapture(t *testing.T, dir string, startMs int64, n int) string {
+	t.Helper()
+	path := filepath.Join(dir, "in.infgo")
+	lgr, err := syslogger.New(path)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if err := lgr.WriteHeader(metrics.Header{Hostname: "h", StartedUnixMs: startMs, NumCores: 2}); err != nil {
+		t.Fatalf("WriteHeader failed: %v", err)
+	}
+	for i := 0; i < n; i++ {
+		ts := startMs + int64(i)*1000
+		if err := lgr.WriteSample(metrics.Sample{TimestampUnixMs: ts, CpuTotal: float64(i)}); err != nil {
+			t.Fatalf("WriteSample failed: %v", err)
+		}
+		p := metrics.Process{TimestampUnixMs: ts + 500, Pid: 42, Name: "postgres", CpuPercent: float64(i)}
+		if err := lgr.WriteProcess(p); err != nil {
+			t.Fatalf("WriteProcess failed: %v", err)
+		}
+	}
+	if err := lgr.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	return path
+}
+
+// Process records (-log-pids) in the range are kept, the ones before the
+// first kept sample too.
+func TestTrimCaptureProcesses(t *testing.T) {
+	dir := t.TempDir()
+	const startMs = 1704067200000
+	src := writeProcessCapture(t, dir, startMs, 10)
+	dst := filepath.Join(dir, "out.infgo")
+
+	if _, err := trimCapture(src, dst, time.UnixMilli(startMs+3200), time.UnixMilli(startMs+6000)); err != nil {
+		t.Fatalf("trimCapture failed: %v", err)
+	}
+	procs := readProcesses(t, dst)
+	var got []int64
+	for _, p := range procs {
+		got = append(got, p.TimestampUnixMs-startMs)
+	}
+	if !slices.Equal(got, []int64{3500, 4500, 5500}) || procs[0].Name != "postgres" {
+		t.Errorf("kept processes at %v (%+v), want 3500, 4500 and 5500", got, procs)
+	}
+}
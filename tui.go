@@ -0,0 +1,247 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	syslogger "github.com/ALH477/infgo/logger"
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ui"
+)
+
+// ── TUI ───────────────────────────────────────────────────────────────────────
+//
+// The TUI is a ui.Model.  The binary hands it a collector of this
+// machine's readings, or a remote feed in its place, and a recorder that
+// logs each reading, serves it, pushes it and checks the alerts on it.
+
+// The badges of the footer, and the few other things main draws, take the
+// panels' palette, which the binary never changes.
+var (
+	cAmber   = ui.DefaultTheme.Warn
+	cRed     = ui.DefaultTheme.Crit
+	cGray500 = ui.DefaultTheme.Dim
+	dimSt    = lipgloss.NewStyle().Foreground(ui.DefaultTheme.Dim)
+	accentSt = lipgloss.NewStyle().Foreground(ui.DefaultTheme.Memory)
+)
+
+// localCollector takes the readings of this machine.  Any of its watches
+// may be nil where the machine has nothing to watch.
+type localCollector struct {
+	stats    *statsReader
+	fetching atomic.Bool // a reading is running
+
+	procs    *procScanner // -users or -procs
+	pi       *piWatch
+	power    *powerWatch
+	gpu      *gpuWatch
+	numa     *numaWatch
+	fans     *fanWatch
+	throttle *throttleWatch
+	pids     *pidWatcher // -pid
+}
+
+func (c *localCollector) Init(ctx context.Context) tea.Cmd {
+	cmds := []tea.Cmd{fetchSysInfo()}
+	if c.procs != nil {
+		cmds = append(cmds, c.procs.scanCmd(ctx), procTick())
+	}
+	if c.pi != nil {
+		cmds = append(cmds, c.pi.readCmd(ctx), piTick())
+	}
+	cmds = append(cmds, fdsCmd(), procCountsCmd(ctx), fdTick())
+	return tea.Batch(cmds...)
+}
+
+func (c *localCollector) Read(ctx context.Context, due time.Time) tea.Cmd {
+	if !c.fetching.CompareAndSwap(false, true) {
+		return nil
+	}
+	return fetchStats(ctx, &c.fetching, due)
+}
+
+func (c *localCollector) Poll(ctx context.Context) tea.Cmd {
+	var gpu, pids, numa, fans, throttle tea.Cmd
+	if c.gpu != nil {
+		gpu = c.gpu.pollCmd(ctx)
+	}
+	if c.pids != nil {
+		pids = c.pids.readCmd(ctx)
+	}
+	if c.numa != nil {
+		numa = c.numa.readCmd(ctx)
+	}
+	if c.fans != nil {
+		fans = c.fans.readCmd(ctx)
+	}
+	if c.throttle != nil {
+		throttle = c.throttle.readCmd(ctx)
+	}
+	return tea.Batch(gpu, pids, numa, fans, throttle)
+}
+
+// Update runs the timers of the readings slower than the stats tick.  A
+// reading still running when its timer fires is not queued behind.
+func (c *localCollector) Update(ctx context.Context, msg tea.Msg) tea.Cmd {
+	switch msg.(type) {
+	case procTickMsg:
+		return tea.Batch(c.procs.scanCmd(ctx), procTick())
+	case fdTickMsg:
+		return tea.Batch(fdsCmd(), procCountsCmd(ctx), fdTick())
+	case piTickMsg:
+		return tea.Batch(c.pi.readCmd(ctx), piTick())
+	}
+	return nil
+}
+
+// Slept spots a suspend between local readings.  The first reading after
+// a resume spans it: it is dropped, and a fresh one taken at once, primed
+// like the first.
+func (c *localCollector) Slept(now time.Time) (metrics.Event, bool) {
+	w, slept := c.power.check(now)
+	if !slept {
+		return metrics.Event{}, false
+	}
+	c.stats.discardCPU()
+	return w.event(), true
+}
+
+func (c *localCollector) Restart(now time.Time) {
+	c.power.restart(now)
+	c.stats.discardCPU()
+}
+
+// recorder hands the TUI's readings to the sinks.  Any of them may be nil
+// where its flag was not given.
+type recorder struct {
+	// logger writes the -log capture, logPath, while disk says its
+	// filesystem has room; logPids adds the -pid readings to it.
+	logger  *syslogger.Logger
+	logPath string
+	disk    *diskGuard
+	logPids bool
+
+	// fingerprint is stamped into the session header; nil in tests.
+	fingerprint *fingerprint
+
+	// live shares the latest sample with the -listen and -grpc-listen
+	// servers.
+	live *liveState
+
+	// pushers forward every sample to -influx-url and similar endpoints.
+	pushers []*pusher
+
+	// alerts evaluates -alert rules on every sample; notifier delivers
+	// their transitions.
+	alerts   *alertMonitor
+	notifier *alertNotifier
+
+	// idle writes idle_start and idle_end events; nil with -idle-after 0.
+	idle *idleDetector
+}
+
+// Init reads back the end of the capture -log-append adds to.
+func (r *recorder) Init(ctx context.Context) tea.Cmd {
+	if r.logger == nil || r.logger.Appended() == 0 {
+		return nil
+	}
+	return warmStart(ctx, r.logPath, r.logger.Appended())
+}
+
+func (r *recorder) Header(h metrics.Header) {
+	r.fingerprint.stamp(&h)
+	if r.logger != nil {
+		_ = r.logger.WriteHeader(h)
+	}
+	if r.live != nil {
+		r.live.setHeader(h)
+	}
+	for _, p := range r.pushers {
+		p.setHeader(h)
+	}
+	r.alerts.setHost(h.Hostname)
+}
+
+// Sample hands a reading to the sinks: every reading, including those the
+// panels skip when sampling faster than the display.
+func (r *recorder) Sample(s metrics.Sample, took time.Duration) {
+	// Persist the sample to the activity log if logging is active and
+	// its filesystem has room.
+	r.disk.poll(s.Time(), r.logger, r.live)
+	if r.logger != nil {
+		_ = r.logger.WriteSample(s)
+	}
+	// Publish it to the HTTP endpoints; this only copies under a mutex.
+	if r.live != nil {
+		r.live.setSample(s, took)
+	}
+	// Queue it for the push writers; this never blocks.
+	for _, p := range r.pushers {
+		p.setSample(s)
+	}
+	for _, ev := range r.alerts.observe(s) {
+		recordAlert(ev, r.logger, r.live, r.notifier)
+	}
+	if e, ok := r.idle.observe(s); ok {
+		recordEvent(e, r.logger, r.live)
+	}
+}
+
+func (r *recorder) Event(e metrics.Event) { recordEvent(e, r.logger, r.live) }
+
+func (r *recorder) Process(p metrics.Process) {
+	if r.logPids && r.logger != nil {
+		_ = r.logger.WriteProcess(p)
+	}
+}
+
+func (r *recorder) Points(ps []metrics.Sample) {
+	if r.live != nil {
+		r.live.setPoints(ps)
+	}
+}
+
+func (r *recorder) Flush() {
+	if r.logger != nil {
+		_ = r.logger.Flush()
+	}
+}
+
+// Badges are the alerts, the push writers that drop samples, the
+// streaming clients and the recording, with its path.
+func (r *recorder) Badges() []ui.Badge {
+	firing, warning := alertBadges(r.alerts, r.notifier)
+	bs := []ui.Badge{{Text: firing, Prio: 8}, {Text: warning, Prio: 6}}
+	for _, w := range renderPushWarnings(r.pushers) {
+		bs = append(bs, ui.Badge{Text: w, Prio: 4})
+	}
+	// Show how many -listen streaming clients are attached, if any.
+	if r.live != nil {
+		if n := r.live.clientCount(); n > 0 {
+			label := "clients"
+			if n == 1 {
+				label = "client"
+			}
+			bs = append(bs, ui.Badge{Text: accentSt.Render("⇄") + dimSt.Render(fmt.Sprintf(" %d %s", n, label)), Prio: 5})
+		}
+	}
+	// Show a recording indicator when the activity log is active.
+	if r.disk.isLow() {
+		bs = append(bs, ui.Badge{Text: r.disk.badge(), Prio: 7})
+	} else if r.logPath != "" {
+		path := r.logPath
+		bs = append(bs,
+			ui.Badge{Text: lipgloss.NewStyle().Foreground(cRed).Bold(true).Render("●") + dimSt.Render(" REC"), Prio: 7},
+			ui.Badge{Text: dimSt.Render(path), Prio: 2, Min: 16,
+				Shrink: func(w int) string { return dimSt.Render(ui.ElideLeft(path, w)) }})
+	}
+	return bs
+}
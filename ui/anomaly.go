@@ -0,0 +1,116 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ALH477/infgo/analysis"
+)
+
+// ── Unusual readings ──────────────────────────────────────────────────────────
+
+// The CPU and MEMORY panels mark a reading "unusual" when it is more than
+// -anomaly-sigma standard deviations above the mean of the last few
+// minutes, as `infgo report` flags anomalies in a whole capture.  Unlike
+// an -alert it has no threshold of its own: 60 % CPU is unusual on an idle
+// machine and not on a busy one.
+
+// anomalyWindow is the span of readings a new one is measured against,
+// and anomalyWarmup how much of it must be seen before any is marked.
+const (
+	anomalyWindow = 5 * time.Minute
+	anomalyWarmup = time.Minute
+)
+
+// anomalyFloor is the least rise above the mean, in percentage points,
+// that is marked however steady the readings before it: on an idle
+// machine σ is near zero, and a blip of a point or two is not news.
+const anomalyFloor = 5
+
+// unusualSt is the badge and the sparkline tint: amber, apart from the
+// red of alerts and session peaks.
+var unusualSt lipgloss.Style
+
+// anomalyTracker keeps the mean and σ of one metric over the last
+// anomalyWindow, a reading at a time.  It is shared by the model's copies,
+// like memTrend.
+//
+// A nil *anomalyTracker is disabled: add keeps nothing and no reading is
+// unusual.
+type anomalyTracker struct {
+	k     float64
+	stats analysis.Welford
+	times []time.Time
+	vals  []float64
+	first time.Time // of the first reading, for the warm-up
+
+	unusual bool // the latest reading
+	run     int  // history points in a row pushed while unusual
+}
+
+// newAnomalyTracker returns nil, which is disabled, when k is not positive.
+func newAnomalyTracker(k float64) *anomalyTracker {
+	if k <= 0 {
+		return nil
+	}
+	return &anomalyTracker{k: k}
+}
+
+// add measures v against the readings before it, then counts it and drops
+// those that have left the window.
+func (a *anomalyTracker) add(at time.Time, v float64) {
+	if a == nil {
+		return
+	}
+	if a.first.IsZero() {
+		a.first = at
+	}
+	a.unusual = at.Sub(a.first) >= anomalyWarmup && a.stats.N() > 1 &&
+		v-a.stats.Mean() > max(a.k*a.stats.Sigma(), anomalyFloor)
+
+	a.stats.Add(v)
+	a.times = append(a.times, at)
+	a.vals = append(a.vals, v)
+	drop := 0
+	for drop < len(a.times) && at.Sub(a.times[drop]) > anomalyWindow {
+		a.stats.Remove(a.vals[drop])
+		drop++
+	}
+	a.times, a.vals = a.times[drop:], a.vals[drop:]
+}
+
+// pushed notes that a history point was pushed, showing the latest
+// reading.
+func (a *anomalyTracker) pushed() {
+	if a == nil {
+		return
+	}
+	if a.unusual {
+		a.run++
+	} else {
+		a.run = 0
+	}
+}
+
+// isUnusual reports whether the latest reading is unusual.
+func (a *anomalyTracker) isUnusual() bool { return a != nil && a.unusual }
+
+// tint is how many of the newest history points to draw in unusualSt.
+func (a *anomalyTracker) tint() int {
+	if a == nil {
+		return 0
+	}
+	return a.run
+}
+
+// badge is the title row's mark for an unusual reading, or "".
+func (a *anomalyTracker) badge() string {
+	if !a.isUnusual() {
+		return ""
+	}
+	return unusualSt.Render("  ◆ unusual")
+}
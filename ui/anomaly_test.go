@@ -0,0 +1,147 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// anomalySeries feeds vals a second apart from t0 and returns the seconds
+// at which the reading was unusual.
+func anomalySeries(a *anomalyTracker, t0 time.Time, vals []float64) []int {
+	var at []int
+	for i, v := range vals {
+		a.add(t0.Add(time.Duration(i)*time.Second), v)
+		if a.isUnusual() {
+			at = append(at, i)
+		}
+	}
+	return at
+}
+
+func TestAnomalyTracker(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	with := func(base []float64, at int, v float64) []float64 {
+		vals := append([]float64(nil), base...)
+		vals[at] = v
+		return vals
+	}
+	flat := make([]float64, 120)
+	for i := range flat {
+		flat[i] = 10
+	}
+	tests := []struct {
+		name string
+		vals []float64
+		want []int
+	}{
+		{"noise", noisy(120), nil},
+		{"spike in warm-up", with(noisy(120), 30, 95), nil},
+		// 40 + 3·2 = 46 is the bar; 47 clears it, 45 does not.
+		{"spike", with(noisy(120), 90, 95), []int{90}},
+		{"just over", with(noisy(120), 90, 47), []int{90}},
+		{"just under", with(noisy(120), 90, 45), nil},
+		// σ is 0, so the floor of 5 points is the bar.
+		{"flat blip", with(flat, 90, 14), nil},
+		{"flat rise", with(flat, 90, 16), []int{90}},
+		{"drop", with(noisy(120), 90, 0), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := anomalySeries(newAnomalyTracker(3), t0, tt.vals)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("unusual at %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// A new level stops being unusual once it is most of the window, and
+// readings older than the window are no longer counted.
+func TestAnomalyTrackerWindow(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	a := newAnomalyTracker(3)
+	anomalySeries(a, t0, noisy(300))
+	a.add(t0.Add(300*time.Second), 80)
+	if !a.isUnusual() {
+		t.Fatal("a jump from 40 to 80: not unusual")
+	}
+	for i := 301; i < 900; i++ {
+		a.add(t0.Add(time.Duration(i)*time.Second), 80)
+	}
+	if a.isUnusual() {
+		t.Error("80 for ten minutes: still unusual")
+	}
+	if n, mean := a.stats.N(), a.stats.Mean(); n != 301 || math.Abs(mean-80) > 1e-9 {
+		t.Errorf("after ten minutes at 80: %d readings of mean %g, want the window's 301 of 80", n, mean)
+	}
+	if len(a.times) != a.stats.N() || len(a.vals) != a.stats.N() {
+		t.Errorf("kept %d times and %d values for %d readings", len(a.times), len(a.vals), a.stats.N())
+	}
+}
+
+func TestAnomalyTrackerRun(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	a := newAnomalyTracker(3)
+	vals := append(noisy(120), 90, 90, 90, 40)
+	for i, v := range vals {
+		a.add(t0.Add(time.Duration(i)*time.Second), v)
+		a.pushed()
+		if i == 122 && a.tint() != 3 {
+			t.Errorf("after three unusual points: tint %d, want 3", a.tint())
+		}
+	}
+	if a.tint() != 0 || a.badge() != "" {
+		t.Errorf("back to normal: tint %d, badge %q", a.tint(), a.badge())
+	}
+}
+
+func TestAnomalyTrackerDisabled(t *testing.T) {
+	a := newAnomalyTracker(0)
+	if a != nil {
+		t.Fatal("-anomaly-sigma 0: got a tracker")
+	}
+	a.add(time.Now(), 100)
+	a.pushed()
+	if a.isUnusual() || a.tint() != 0 || a.badge() != "" {
+		t.Error("nil tracker marks readings")
+	}
+}
+
+// A spike on the live dashboard puts the badge on the CPU panel only.
+func TestUpdateUnusualBadge(t *testing.T) {
+	m := New()
+	m.cpuUsual, m.memUsual = newAnomalyTracker(3), newAnomalyTracker(3)
+	var tm tea.Model = m
+	tm, _ = tm.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	t0 := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i, v := range append(noisy(120), 97) {
+		tm, _ = tm.Update(Reading{CPUTotal: v, CPUCores: []float64{v}, MemPercent: 50, At: t0.Add(time.Duration(i) * time.Second)})
+	}
+	view := ansi.Strip(tm.View())
+	if n := strings.Count(view, "◆ unusual"); n != 1 {
+		t.Fatalf("got %d badges, want 1:\n%s", n, view)
+	}
+	for _, line := range strings.Split(view, "\n") {
+		if strings.Contains(line, "◆ unusual") && !strings.Contains(line, "CPU") {
+			t.Errorf("badge outside the CPU title: %q", line)
+		}
+	}
+}
+
+// noisy is n readings alternating 38 and 42: a mean of 40 and a σ of 2.
+func noisy(n int) []float64 {
+	vals := make([]float64, n)
+	for i := range vals {
+		vals[i] = 38 + 4*float64(i%2)
+	}
+	return vals
+}
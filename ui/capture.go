@@ -0,0 +1,58 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"time"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// ── High-rate capture ─────────────────────────────────────────────────────────
+
+// MinInterval is the shortest -interval: 20 Hz.
+const MinInterval = 50 * time.Millisecond
+
+// MinDisplayInterval is how often, at most, the TUI shows a new reading and
+// the headless collector flushes its log.  Sampling faster than this, say
+// -interval 100ms for a short profiling burst, every reading still goes to
+// the log and the other sinks, but the panels move on every few readings
+// and the log is written through in batches.
+const MinDisplayInterval = 250 * time.Millisecond
+
+// displayEvery is how many readings at interval make one displayed reading
+// and one history point.
+func displayEvery(interval time.Duration) int {
+	return max(1, int((MinDisplayInterval+interval-1)/interval))
+}
+
+// pendingReadings folds the readings behind one displayed reading into the
+// histories, so that the sparklines show the mean of every reading rather
+// than a sample of them.
+type pendingReadings struct {
+	n          int     // readings, including any with nothing to fold
+	cpu, mem   float64 // sums
+	nCPU, nMem int
+}
+
+func (p *pendingReadings) add(msg Reading) {
+	p.n++
+	if !msg.Missing.Has(metrics.MissingCPU) {
+		p.cpu += msg.CPUTotal
+		p.nCPU++
+	}
+	if !msg.Missing.Has(metrics.MissingMem) {
+		p.mem += msg.MemPercent
+		p.nMem++
+	}
+}
+
+// point is the history point for a sum over n readings, or kept, the value
+// shown, if none of them had it.
+func point(sum float64, n int, kept float64) float64 {
+	if n == 0 {
+		return kept
+	}
+	return sum / float64(n)
+}
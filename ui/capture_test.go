@@ -0,0 +1,71 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	syslogger "github.com/ALH477/infgo/logger"
+)
+
+func TestDisplayEvery(t *testing.T) {
+	tests := []struct {
+		interval time.Duration
+		want     int
+	}{
+		{DefaultInterval, 1},
+		{MinDisplayInterval, 1},
+		{200 * time.Millisecond, 2},
+		{100 * time.Millisecond, 3},
+		{MinInterval, 5},
+	}
+	for _, tt := range tests {
+		if got := displayEvery(tt.interval); got != tt.want {
+			t.Errorf("displayEvery(%v): got %d, want %d", tt.interval, got, tt.want)
+		}
+	}
+}
+
+// BenchmarkCapture is one second of capture at 10 and 20 Hz: the readings
+// applied to the model and written to a log, and the nine frames the TUI
+// renders in that second.  Allocations per op stay flat as b.N grows.
+func BenchmarkCapture(b *testing.B) {
+	for _, hz := range []int{10, 20} {
+		b.Run(fmt.Sprintf("%dHz", hz), func(b *testing.B) {
+			lgr, err := syslogger.NewWriter(io.Discard)
+			if err != nil {
+				b.Fatal(err)
+			}
+			m := New()
+			m.sched = newStatsSchedule(time.Second / time.Duration(hz))
+			m.rec = &sinks{log: lgr}
+			var tm tea.Model = m
+			tm, _ = tm.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+			msg := benchStats()
+			t0 := time.Now()
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				for r := range hz {
+					msg.CPUTotal = float64((i + r) % 100)
+					msg.At = t0.Add(time.Duration(i*hz+r) * m.sched.interval)
+					tm, _ = tm.Update(msg)
+					if r*9/hz != (r+1)*9/hz {
+						tm, _ = tm.Update(animTickMsg{})
+						_ = tm.View()
+					}
+				}
+			}
+			b.StopTimer()
+			// The share of one core a second of capture costs.
+			b.ReportMetric(100*float64(b.Elapsed())/float64(b.N)/float64(time.Second), "%core")
+			_ = lgr.Close()
+		})
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// CgroupLimits are the limits of a cgroup, zero where it has none.
+type CgroupLimits struct {
+	CPUs     float64 // cores' worth of CPU time: the quota over its period
+	MemBytes uint64
+}
+
+func (l CgroupLimits) Limited() bool { return l.CPUs > 0 || l.MemBytes > 0 }
+
+// badge is the header's note of the limits, e.g. "cgroup-limited: 2.0
+// CPUs / 4 GiB".
+func (l CgroupLimits) badge() string {
+	var parts []string
+	if l.CPUs > 0 {
+		parts = append(parts, fmt.Sprintf("%.1f CPUs", l.CPUs))
+	}
+	if l.MemBytes > 0 {
+		gib := math.Round(float64(l.MemBytes)/BytesPerGiB*100) / 100
+		parts = append(parts, strconv.FormatFloat(gib, 'f', -1, 64)+" GiB")
+	}
+	return "cgroup-limited: " + strings.Join(parts, " / ")
+}
@@ -0,0 +1,25 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestCgroupBadge(t *testing.T) {
+	m := sizedModel(100, 50)
+	if got := ansi.Strip(m.renderHeader(innerWidth(100))); strings.Contains(got, "cgroup") {
+		t.Errorf("on a host: got %q", got)
+	}
+	m.cgroup = CgroupLimits{CPUs: 2, MemBytes: 4 << 30}
+	if got := ansi.Strip(m.renderHeader(innerWidth(100))); !strings.Contains(got, "cgroup-limited: 2.0 CPUs / 4 GiB") {
+		t.Errorf("got %q", got)
+	}
+	if got := (CgroupLimits{MemBytes: 1536 << 20}).badge(); got != "cgroup-limited: 1.5 GiB" {
+		t.Errorf("memory only: got %q", got)
+	}
+}
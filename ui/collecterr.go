@@ -0,0 +1,99 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// ── Repeated collection errors ────────────────────────────────────────────────
+
+// A subsystem that fails usually fails the same way on every reading: a
+// container without /proc/loadavg says so twice a second for as long as it
+// runs.  Its failures are counted rather than reported one by one.  The
+// log gets a collect_error event when a failure starts or its message
+// changes, and a collect_ok event with the count and duration when the
+// readings come back; meanwhile the banner shows the message with its
+// count, e.g. "(×418 since 14:02)".
+
+// errGroups are the subsystems whose failures are tracked, in the order
+// their events are written.
+var errGroups = []metrics.Missing{metrics.MissingCPU, metrics.MissingMem, metrics.MissingLoad}
+
+// errStreak is the failure of one subsystem.
+type errStreak struct {
+	msg   string    // of the latest failure
+	since time.Time // of the first failure with msg in a row
+	count int       // failures with msg in a row
+
+	began time.Time // of the first failure, whatever its message
+	total int       // failures since began
+}
+
+// ErrStreaks tracks the failures of the subsystems.  The zero value is
+// ready to use.
+type ErrStreaks struct {
+	by map[metrics.Missing]*errStreak
+}
+
+// observe takes the errors of a reading at now, and returns the events to
+// record.  A subsystem in missing without an error was not queried, as it
+// waits out its backoff, and its streak goes on as it was.
+func (d *ErrStreaks) Observe(now time.Time, missing metrics.Missing, errs map[metrics.Missing]error) []metrics.Event {
+	var out []metrics.Event
+	for _, g := range errGroups {
+		st := d.by[g]
+		err, failed := errs[g]
+		switch {
+		case failed:
+			msg := err.Error()
+			if st != nil && st.msg == msg {
+				st.count++
+				st.total++
+				continue
+			}
+			text := fmt.Sprintf("%s readings failing: %s", g, msg)
+			if st == nil {
+				if d.by == nil {
+					d.by = map[metrics.Missing]*errStreak{}
+				}
+				st = &errStreak{began: now}
+				d.by[g] = st
+			} else {
+				text += fmt.Sprintf(" (was %q ×%d)", st.msg, st.count)
+			}
+			st.msg, st.since, st.count = msg, now, 1
+			st.total++
+			out = append(out, metrics.Event{TimestampUnixMs: now.UnixMilli(), Kind: "collect_error", Message: text})
+		case st != nil && !missing.Has(g):
+			delete(d.by, g)
+			out = append(out, metrics.Event{TimestampUnixMs: now.UnixMilli(), Kind: "collect_ok",
+				Message: fmt.Sprintf("%s readings back after %s: %d failed", g, now.Sub(st.began).Round(time.Second), st.total)})
+		}
+	}
+	return out
+}
+
+// failing reports whether any subsystem is failing.
+func (d *ErrStreaks) failing() bool { return d != nil && len(d.by) > 0 }
+
+// render is the banner while subsystems fail: each one's latest error
+// with its count, e.g. "⚠ load: no such file (×418 since 14:02)".
+func (d *ErrStreaks) render(iw int) string {
+	var parts []string
+	for _, g := range errGroups {
+		if st := d.by[g]; st != nil {
+			parts = append(parts, fmt.Sprintf("%s: %s (×%d since %s)", g, st.msg, st.count, st.since.Local().Format("15:04")))
+		}
+	}
+	msg := ansi.Truncate("⚠ "+strings.Join(parts, "  ·  "), iw, "…")
+	return lipgloss.NewStyle().Foreground(cAmber).Padding(0, 1).Render(msg)
+}
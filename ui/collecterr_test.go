@@ -0,0 +1,107 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+var (
+	errNoLoad = errors.New("open /proc/loadavg: no such file or directory")
+	errBusy   = errors.New("device busy")
+)
+
+func kinds(events []metrics.Event) string {
+	var out []string
+	for _, e := range events {
+		out = append(out, e.Kind)
+	}
+	return strings.Join(out, ",")
+}
+
+// The same error is reported once, counted, and closed with its total.
+func TestErrStreaksRepeat(t *testing.T) {
+	var d ErrStreaks
+	t0 := time.Date(2026, 3, 1, 14, 2, 0, 0, time.Local)
+	fail := map[metrics.Missing]error{metrics.MissingLoad: errNoLoad}
+	var got []metrics.Event
+	for i := range 3 {
+		got = append(got, d.Observe(t0.Add(time.Duration(i)*time.Second), metrics.MissingLoad, fail)...)
+	}
+	if kinds(got) != "collect_error" || !strings.Contains(got[0].Message, "load readings failing: open /proc/loadavg") {
+		t.Fatalf("got %+v, want one collect_error", got)
+	}
+	if st := d.by[metrics.MissingLoad]; st.count != 3 || !st.since.Equal(t0) {
+		t.Errorf("got %+v, want 3 since %v", st, t0)
+	}
+	banner := ansi.Strip(d.render(120))
+	if !strings.Contains(banner, "load: open /proc/loadavg: no such file or directory (×3 since 14:02)") {
+		t.Errorf("banner: got %q", banner)
+	}
+
+	got = d.Observe(t0.Add(90*time.Second), 0, nil)
+	if kinds(got) != "collect_ok" || got[0].Message != "load readings back after 1m30s: 3 failed" {
+		t.Errorf("got %+v, want the recovery with its count", got)
+	}
+	if d.failing() {
+		t.Error("still failing after the recovery")
+	}
+	if got = d.Observe(t0.Add(91*time.Second), 0, nil); len(got) != 0 {
+		t.Errorf("got %+v after the recovery", got)
+	}
+}
+
+// Two errors alternating each restart the count, but not the streak.
+func TestErrStreaksAlternate(t *testing.T) {
+	var d ErrStreaks
+	t0 := time.Date(2026, 3, 1, 14, 2, 0, 0, time.Local)
+	var got []metrics.Event
+	for i, err := range []error{errNoLoad, errBusy, errNoLoad, errNoLoad} {
+		at := t0.Add(time.Duration(i) * time.Minute)
+		got = append(got, d.Observe(at, metrics.MissingLoad, map[metrics.Missing]error{metrics.MissingLoad: err})...)
+	}
+	if kinds(got) != "collect_error,collect_error,collect_error" {
+		t.Fatalf("got %q, want an event per change", kinds(got))
+	}
+	if !strings.Contains(got[1].Message, "device busy (was \"open /proc/loadavg: no such file or directory\" ×1)") {
+		t.Errorf("got %q", got[1].Message)
+	}
+	st := d.by[metrics.MissingLoad]
+	if st.count != 2 || !st.since.Equal(t0.Add(2*time.Minute)) || st.total != 4 || !st.began.Equal(t0) {
+		t.Errorf("got %+v, want 2 in a row of 4 since the start", st)
+	}
+	got = d.Observe(t0.Add(5*time.Minute), 0, nil)
+	if len(got) != 1 || got[0].Message != "load readings back after 5m0s: 4 failed" {
+		t.Errorf("got %+v", got)
+	}
+}
+
+// A subsystem waiting out its backoff is missing without an error, and its
+// streak carries on; another failing alongside keeps its own.
+func TestErrStreaksBackoff(t *testing.T) {
+	var d ErrStreaks
+	t0 := time.Now()
+	d.Observe(t0, metrics.MissingMem, map[metrics.Missing]error{metrics.MissingMem: errBusy})
+	if got := d.Observe(t0.Add(time.Second), metrics.MissingMem, nil); len(got) != 0 {
+		t.Errorf("backoff: got %+v", got)
+	}
+	got := d.Observe(t0.Add(2*time.Second), metrics.MissingMem|metrics.MissingLoad,
+		map[metrics.Missing]error{metrics.MissingMem: errBusy, metrics.MissingLoad: errNoLoad})
+	if kinds(got) != "collect_error" || !strings.HasPrefix(got[0].Message, "load") {
+		t.Errorf("got %+v, want load's first failure only", got)
+	}
+	if st := d.by[metrics.MissingMem]; st.count != 2 {
+		t.Errorf("mem: got %+v, want 2", st)
+	}
+	if banner := ansi.Strip(d.render(200)); !strings.Contains(banner, "mem: device busy (×2") || !strings.Contains(banner, "  ·  load: ") {
+		t.Errorf("banner: got %q", banner)
+	}
+}
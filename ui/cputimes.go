@@ -0,0 +1,81 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// CPUTimes is the CPU time spent in each mode since boot, in seconds,
+// summed over the cores.
+type CPUTimes struct {
+	User, Nice, System, Idle, Iowait, Irq, Softirq, Steal float64
+}
+
+func (t CPUTimes) fields() [8]float64 {
+	return [...]float64{t.User, t.Nice, t.System, t.Idle, t.Iowait, t.Irq, t.Softirq, t.Steal}
+}
+
+// cpuModes is the share of the CPU time between two readings spent in
+// each mode, in percent: user with nice, system with the interrupts.
+type cpuModes struct {
+	user, system, iowait, steal float64
+}
+
+// CPUBreakdown is the share of each mode between prev and cur.  It is
+// false for the first reading, whose prev is zero, and where a counter
+// went backwards, as after a CPU is taken offline, or no time passed.
+func CPUBreakdown(prev, cur CPUTimes) (cpuModes, bool) {
+	var d [8]float64
+	var total float64
+	p, c := prev.fields(), cur.fields()
+	if p == [8]float64{} {
+		return cpuModes{}, false
+	}
+	for i := range d {
+		if d[i] = c[i] - p[i]; d[i] < 0 {
+			return cpuModes{}, false
+		}
+		total += d[i]
+	}
+	if total <= 0 {
+		return cpuModes{}, false
+	}
+	pct := func(v float64) float64 { return 100 * v / total }
+	return cpuModes{
+		user:   pct(d[0] + d[1]),
+		system: pct(d[2] + d[5] + d[6]),
+		iowait: pct(d[4]),
+		steal:  pct(d[7]),
+	}, true
+}
+
+// put records m in s.
+func (m cpuModes) Put(s *metrics.Sample) {
+	s.CpuUserPercent, s.CpuSystemPercent = m.user, m.system
+	s.CpuIowaitPercent, s.CpuStealPercent = m.iowait, m.steal
+}
+
+// render is the row under the CPU bar, with iowait and steal in amber
+// while there is any.
+func (m cpuModes) render() string {
+	part := func(name string, v float64, warn bool) string {
+		st := dimSt
+		if warn && v >= 0.5 { // shown as more than 0%
+			st = lipgloss.NewStyle().Foreground(cAmber)
+		}
+		return st.Render(fmt.Sprintf("%s %.0f%%", name, v))
+	}
+	return strings.Join([]string{
+		part("usr", m.user, false),
+		part("sys", m.system, false),
+		part("io", m.iowait, true),
+		part("steal", m.steal, true),
+	}, dimSt.Render(" · "))
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestCPUBreakdown(t *testing.T) {
+	prev := CPUTimes{User: 100, Nice: 10, System: 50, Idle: 800, Iowait: 20, Irq: 5, Softirq: 5, Steal: 10}
+	tests := []struct {
+		name string
+		prev CPUTimes
+		cur  CPUTimes
+		want cpuModes
+		ok   bool
+	}{
+		{"first reading", CPUTimes{}, prev, cpuModes{}, false},
+		{"no time passed", prev, prev, cpuModes{}, false},
+		{
+			"between two",
+			prev,
+			CPUTimes{User: 130, Nice: 20, System: 60, Idle: 830, Iowait: 30, Irq: 6, Softirq: 9, Steal: 15},
+			cpuModes{user: 40, system: 15, iowait: 10, steal: 5},
+			true,
+		},
+		{
+			"a counter went backwards",
+			prev,
+			CPUTimes{User: 130, Nice: 20, System: 60, Idle: 700, Iowait: 30, Irq: 6, Softirq: 9, Steal: 15},
+			cpuModes{},
+			false,
+		},
+	}
+	for _, tt := range tests {
+		got, ok := CPUBreakdown(tt.prev, tt.cur)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("%s: got %+v, %v, want %+v, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+// The modes appear under the CPU bar from the second reading of the
+// times, and are recorded in its sample.
+func TestCPUModesRow(t *testing.T) {
+	m := sizedModel(100, 50)
+	rec := new(sinks)
+	m.rec = rec
+	for i, ts := range []CPUTimes{
+		{User: 100, System: 50, Idle: 800},
+		{User: 140, System: 60, Idle: 840, Iowait: 10},
+	} {
+		tm, _ := m.Update(Reading{CPUTotal: 60, MemPercent: 40, Times: ts, HasTimes: true})
+		m = tm.(Model)
+		if got := ansi.Strip(m.renderCPU(innerWidth(100))); (i == 0) == strings.Contains(got, "usr") {
+			t.Errorf("reading %d: got\n%s", i, got)
+		}
+	}
+	if got := ansi.Strip(m.renderCPU(innerWidth(100))); !strings.Contains(got, "usr 40% · sys 10% · io 10% · steal 0%") {
+		t.Errorf("got\n%s", got)
+	}
+	samples := rec.samples
+	if len(samples) != 2 || samples[0].CpuUserPercent != 0 || samples[1].CpuUserPercent != 40 || samples[1].CpuIowaitPercent != 10 {
+		t.Errorf("logged: got %+v", samples)
+	}
+}
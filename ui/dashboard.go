@@ -0,0 +1,299 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Dashboard shows several -connect hosts as a compact grid.  Each host is
+// a complete single-host Model with its own Feed, so it polls, backs off
+// and keeps history independently, and zooming in just renders that
+// Model's View.
+type Dashboard struct {
+	hosts  []Model
+	cursor int  // selected row
+	zoomed bool // showing hosts[cursor] full-screen
+
+	width, height int
+	frameCount    int
+}
+
+// hostMsg routes a message produced by hosts[idx]'s commands back to it.
+type hostMsg struct {
+	idx int
+	msg tea.Msg
+}
+
+// NewDashboard returns a Dashboard of a host for each of feeds.
+func NewDashboard(feeds ...Feed) Dashboard {
+	d := Dashboard{width: 80, height: 24}
+	for _, f := range feeds {
+		d.hosts = append(d.hosts, New(WithFeed(f)))
+	}
+	return d
+}
+
+// animTick is the Dashboard's animation tick, which it hands on to each
+// host as the host's own.
+func (d Dashboard) animTick() tea.Cmd {
+	return tea.Tick(animInterval, func(t time.Time) tea.Msg {
+		return animTickMsg{t: t}
+	})
+}
+
+// tagCmd wraps cmd so whatever it produces is delivered as a hostMsg for
+// host idx.  Batches are unpacked so each member is tagged too.
+func tagCmd(idx int, cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		switch msg := cmd().(type) {
+		case nil:
+			return nil
+		case tea.BatchMsg:
+			cmds := make([]tea.Cmd, len(msg))
+			for i, c := range msg {
+				cmds[i] = tagCmd(idx, c)
+			}
+			return tea.BatchMsg(cmds)
+		case tea.QuitMsg:
+			return msg
+		default:
+			return hostMsg{idx, msg}
+		}
+	}
+}
+
+// Init starts every host's poll loop plus one shared animation tick; the
+// hosts' own Init would start an animation tick each.
+func (d Dashboard) Init() tea.Cmd {
+	cmds := []tea.Cmd{d.animTick()}
+	for i, h := range d.hosts {
+		cmds = append(cmds, tagCmd(i, h.feed.Fetch()))
+	}
+	return tea.Batch(cmds...)
+}
+
+func (d Dashboard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		d.width, d.height = msg.Width, msg.Height
+		for i := range d.hosts {
+			d.hosts[i] = d.hosts[i].resized(msg.Width, msg.Height)
+		}
+		return d, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c":
+			return d, tea.Quit
+		case "esc":
+			d.zoomed = false
+		case "enter":
+			d.zoomed = true
+		case "up", "k":
+			if !d.zoomed && d.cursor > 0 {
+				d.cursor--
+			}
+		case "down", "j":
+			if !d.zoomed && d.cursor < len(d.hosts)-1 {
+				d.cursor++
+			}
+		case "1", "2", "3", "4":
+			// Collapse a panel of the zoomed host's full view.
+			if d.zoomed {
+				next, _ := d.hosts[d.cursor].Update(msg)
+				d.hosts[d.cursor] = next.(Model)
+			}
+		}
+		return d, nil
+
+	case animTickMsg:
+		d.frameCount++
+		for i := range d.hosts {
+			// Drop their re-arm; ours suffices.
+			next, _ := d.hosts[i].Update(animTickMsg{d.hosts[i].id, msg.t})
+			d.hosts[i] = next.(Model)
+		}
+		return d, d.animTick()
+
+	case hostMsg:
+		if msg.idx < 0 || msg.idx >= len(d.hosts) {
+			return d, nil
+		}
+		next, cmd := d.hosts[msg.idx].Update(msg.msg)
+		d.hosts[msg.idx] = next.(Model)
+		return d, tagCmd(msg.idx, cmd)
+	}
+	return d, nil
+}
+
+const (
+	gridHostMin   = 8
+	gridHostMax   = 18
+	gridBarMin    = 4
+	gridBarPref   = 10 // bars grow to this before the sparkline appears
+	gridBarMax    = 24
+	gridSparkMin  = 6 // narrower than this and the column is dropped
+	gridSparkMax  = 24
+	gridStatusW   = 14 // "seen 12m ago", "⇄ 123ms"
+	gridPctW      = 7  // " 100.0%"
+	gridLoadW     = 6  // " 12.34"
+	gridMarkW     = 2  // "▸ "
+	gridGap       = 2
+	gridMinTotalW = 40
+)
+
+// gridColumns budgets a row of width w: the host column fits the longest
+// name within bounds, bars come next, then the sparkline, and whatever is
+// left widens the bars.
+func gridColumns(w, longestHost int) (hostW, barW, sparkW int) {
+	hostW = min(max(longestHost, gridHostMin), gridHostMax)
+	fixed := gridMarkW + hostW + 2*(gridGap+gridPctW) + gridGap + gridLoadW + gridGap + gridStatusW
+	rest := w - fixed
+
+	barW = min(max(rest/2, gridBarMin), gridBarPref)
+	rest -= 2 * barW
+	if rest-gridGap >= gridSparkMin {
+		sparkW = min(rest-gridGap, gridSparkMax)
+		rest -= gridGap + sparkW
+	}
+	if rest > 0 {
+		barW = min(barW+rest/2, gridBarMax)
+	}
+	return hostW, barW, sparkW
+}
+
+// worstPct is the highest of CPU, memory and load (relative to the core
+// count), which picks the row's colour.
+func (m Model) worstPct() float64 {
+	worst := math.Max(m.cpuTotal, m.memPercent)
+	if m.numCores > 0 {
+		worst = math.Max(worst, m.load1/float64(m.numCores)*100)
+	}
+	return worst
+}
+
+// hostLabel is the hostname once known, else the -connect address.
+func (m Model) hostLabel() string {
+	if m.hostname != "" {
+		return m.hostname
+	}
+	return m.feed.Name()
+}
+
+// lastSeen describes how long ago host m last answered.
+func (m Model) lastSeen() string {
+	if m.remoteSeen.IsZero() {
+		return "connecting…"
+	}
+	age := time.Since(m.remoteSeen)
+	switch {
+	case age < time.Minute:
+		return fmt.Sprintf("seen %ds ago", int(age.Seconds()))
+	case age < time.Hour:
+		return fmt.Sprintf("seen %dm ago", int(age.Minutes()))
+	default:
+		return fmt.Sprintf("seen %dh ago", int(age.Hours()))
+	}
+}
+
+func (d Dashboard) renderRow(i int, hostW, barW, sparkW int) string {
+	h := d.hosts[i]
+	gap := strings.Repeat(" ", gridGap)
+
+	mark := "  "
+	if i == d.cursor {
+		mark = accentSt.Copy().Bold(true).Render("▸ ")
+	}
+	name := ansi.Truncate(h.hostLabel(), hostW, "…")
+	row := PadVisual(lipgloss.NewStyle().Foreground(theme.LoadColor(h.worstPct())).Bold(true).Render(name), hostW)
+
+	if !h.ready {
+		return mark + row + gap + dimSt.Render(h.lastSeen())
+	}
+
+	pct := func(v float64) string {
+		return lipgloss.NewStyle().Foreground(theme.LoadColor(v)).Render(fmt.Sprintf("%7s", FormatPercent(v)))
+	}
+	row += gap + theme.Bar(h.cpuTotal, barW) + pct(h.cpuTotal)
+	row += gap + theme.Bar(h.memPercent, barW) + pct(h.memPercent)
+	loadPct := 0.0
+	if h.numCores > 0 {
+		loadPct = h.load1 / float64(h.numCores) * 100
+	}
+	row += gap + lipgloss.NewStyle().Foreground(theme.LoadColor(loadPct)).Render(fmt.Sprintf("%6s", FormatNumber(h.load1, 2)))
+	if sparkW > 0 {
+		row += gap + sparkline(&h.cpuHistory, sparkW, cViolet)
+	}
+
+	status := dimSt.Render(fmt.Sprintf("⇄ %dms", h.linkRTT.Milliseconds()))
+	if h.remoteStale() {
+		status = dimSt.Render(h.lastSeen())
+	}
+	row += gap + status
+
+	if h.remoteStale() {
+		row = dimPanel(row)
+	}
+	return mark + row
+}
+
+func (d Dashboard) View() string {
+	if d.zoomed {
+		return d.hosts[d.cursor].View() + "\n" + dimSt.Render("   esc  back to all hosts")
+	}
+
+	w := max(d.width-4, gridMinTotalW)
+	longest := 0
+	for _, h := range d.hosts {
+		longest = max(longest, lipgloss.Width(h.hostLabel()))
+	}
+	hostW, barW, sparkW := gridColumns(w-4, longest)
+
+	spinner := lipgloss.NewStyle().Foreground(cViolet).Render(spinnerFrames[d.frameCount%len(spinnerFrames)])
+	title := spinner + "  " + boldSt.Copy().Foreground(cViolet).Render("INFGO") +
+		dimSt.Render(fmt.Sprintf("  ·  %d hosts", len(d.hosts)))
+	header := lipgloss.NewStyle().
+		BorderStyle(lipgloss.ThickBorder()).
+		BorderForeground(cViolet2).
+		Padding(0, 1).
+		Width(w).
+		Render(title)
+
+	gap := strings.Repeat(" ", gridGap)
+	cols := strings.Repeat(" ", gridMarkW) + PadVisual("HOST", hostW) +
+		gap + PadVisual("CPU", barW+gridPctW) +
+		gap + PadVisual("MEM", barW+gridPctW) +
+		gap + PadVisual("LOAD1", gridLoadW)
+	if sparkW > 0 {
+		cols += gap + PadVisual("CPU HISTORY", sparkW)
+	}
+	lines := []string{labelSt.Render(cols)}
+	for i := range d.hosts {
+		lines = append(lines, ansi.Truncate(d.renderRow(i, hostW, barW, sparkW), w-4, ""))
+	}
+	grid := lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(cGray700).
+		Padding(0, 1).
+		Width(w).
+		Render(strings.Join(lines, "\n"))
+
+	keys := accentSt.Copy().Bold(true).Render("↑/↓") + dimSt.Render(" select  ") +
+		accentSt.Copy().Bold(true).Render("enter") + dimSt.Render(" zoom  ") +
+		accentSt.Copy().Bold(true).Render("q") + dimSt.Render(" quit")
+
+	out := strings.Join([]string{header, "", grid, " " + keys}, "\n")
+	return lipgloss.NewStyle().Padding(0, 1).Render(out)
+}
@@ -0,0 +1,194 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// stubFeed is a Feed of the host it names that is never polled.
+type stubFeed string
+
+func (f stubFeed) Fetch() tea.Cmd { return nil }
+func (f stubFeed) Name() string   { return string(f) }
+func (f stubFeed) Target() string { return "http://" + string(f) }
+func (f stubFeed) Close() error   { return nil }
+
+func testDashboard(hosts ...string) Dashboard {
+	var feeds []Feed
+	for _, h := range hosts {
+		feeds = append(feeds, stubFeed(h))
+	}
+	d := NewDashboard(feeds...)
+	next, _ := d.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	return next.(Dashboard)
+}
+
+func (d Dashboard) feed(idx int, msg tea.Msg) Dashboard {
+	next, _ := d.Update(hostMsg{idx, msg})
+	return next.(Dashboard)
+}
+
+func (d Dashboard) key(k string) Dashboard {
+	var msg tea.KeyMsg
+	switch k {
+	case "up":
+		msg = tea.KeyMsg{Type: tea.KeyUp}
+	case "down":
+		msg = tea.KeyMsg{Type: tea.KeyDown}
+	case "enter":
+		msg = tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		msg = tea.KeyMsg{Type: tea.KeyEsc}
+	default:
+		msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+	}
+	next, _ := d.Update(msg)
+	return next.(Dashboard)
+}
+
+func remoteSample(host string, ts int64, cpu float64) RemoteMsg {
+	return RemoteMsg{
+		Reading: Reading{CPUTotal: cpu, CPUCores: []float64{cpu}, MemPercent: 40, Load1: 1, At: time.UnixMilli(ts)},
+		Info:    &SysInfo{Hostname: host, NumCores: 4},
+		RTT:     2 * time.Millisecond,
+	}
+}
+
+func TestDashboardRouting(t *testing.T) {
+	d := testDashboard("a:9804", "b:9804")
+	d = d.feed(1, remoteSample("beta", 1000, 55))
+
+	if d.hosts[0].ready || d.hosts[0].cpuTotal != 0 {
+		t.Errorf("host 0 received host 1's sample: cpu %v", d.hosts[0].cpuTotal)
+	}
+	if d.hosts[1].hostname != "beta" || d.hosts[1].cpuTotal != 55 {
+		t.Errorf("host 1: got %q at %v%%, want beta at 55%%", d.hosts[1].hostname, d.hosts[1].cpuTotal)
+	}
+}
+
+func TestTagCmd(t *testing.T) {
+	if tagCmd(3, nil) != nil {
+		t.Error("tagCmd(nil): got a command")
+	}
+	ping := func() tea.Msg { return statsTickMsg{} }
+	got := tagCmd(3, tea.Batch(ping, ping))()
+	batch, ok := got.(tea.BatchMsg)
+	if !ok || len(batch) != 2 {
+		t.Fatalf("tagged batch: got %T %v", got, got)
+	}
+	for i, c := range batch {
+		if hm, ok := c().(hostMsg); !ok || hm.idx != 3 {
+			t.Errorf("batch member %d: got %#v, want hostMsg for host 3", i, c())
+		}
+	}
+	if _, ok := tagCmd(3, tea.Quit)().(tea.QuitMsg); !ok {
+		t.Error("tea.Quit was tagged instead of passed through")
+	}
+}
+
+func TestDashboardZoom(t *testing.T) {
+	d := testDashboard("a:9804", "b:9804", "c:9804")
+	for i, h := range []string{"alpha", "beta", "gamma"} {
+		d = d.feed(i, remoteSample(h, 1000, 10))
+	}
+
+	d = d.key("down").key("down").key("down")
+	if d.cursor != 2 {
+		t.Errorf("cursor after 3×down over 3 hosts: got %d, want 2", d.cursor)
+	}
+	d = d.key("k")
+	if d.cursor != 1 {
+		t.Errorf("cursor after k: got %d, want 1", d.cursor)
+	}
+
+	d = d.key("enter")
+	view := ansi.Strip(d.View())
+	if !d.zoomed || !strings.Contains(view, "beta") || strings.Contains(view, "gamma") {
+		t.Errorf("zoomed view is not beta's full panel:\n%s", view)
+	}
+	// Selection is frozen while zoomed.
+	if d = d.key("down"); d.cursor != 1 {
+		t.Errorf("cursor moved while zoomed: got %d", d.cursor)
+	}
+
+	d = d.key("esc")
+	view = ansi.Strip(d.View())
+	if d.zoomed || !strings.Contains(view, "alpha") || !strings.Contains(view, "gamma") {
+		t.Errorf("esc did not return to the grid:\n%s", view)
+	}
+}
+
+func TestGridColumns(t *testing.T) {
+	tests := []struct {
+		w, longest             int
+		wantHost, wantSparkGT0 int // wantSparkGT0 is 0 or 1
+	}{
+		{200, 10, 10, 1},
+		{200, 40, gridHostMax, 1},
+		{80, 5, gridHostMin, 1},
+		{60, 12, 12, 0},
+		{40, 12, 12, 0},
+	}
+	for _, tt := range tests {
+		hostW, barW, sparkW := gridColumns(tt.w, tt.longest)
+		if hostW != tt.wantHost {
+			t.Errorf("gridColumns(%d, %d): got host %d, want %d", tt.w, tt.longest, hostW, tt.wantHost)
+		}
+		if (sparkW > 0) != (tt.wantSparkGT0 == 1) {
+			t.Errorf("gridColumns(%d, %d): got sparkline %d", tt.w, tt.longest, sparkW)
+		}
+		if sparkW > 0 && sparkW < gridSparkMin {
+			t.Errorf("gridColumns(%d, %d): sparkline %d is below the minimum", tt.w, tt.longest, sparkW)
+		}
+		if barW < gridBarMin || barW > gridBarMax {
+			t.Errorf("gridColumns(%d, %d): bar %d out of bounds", tt.w, tt.longest, barW)
+		}
+	}
+}
+
+func TestDashboardStaleRow(t *testing.T) {
+	d := testDashboard("a:9804", "b:9804")
+	d = d.feed(0, remoteSample("alpha", 1000, 10))
+	d = d.feed(1, remoteSample("beta", 1000, 10))
+	d.hosts[1].remoteSeen = time.Now().Add(-42 * time.Second)
+	d = d.feed(1, RemoteMsg{Err: errors.New("connection refused")})
+
+	var alpha, beta string
+	for _, line := range strings.Split(ansi.Strip(d.View()), "\n") {
+		switch {
+		case strings.Contains(line, "alpha"):
+			alpha = line
+		case strings.Contains(line, "beta"):
+			beta = line
+		}
+	}
+	if !strings.Contains(alpha, "⇄ 2ms") {
+		t.Errorf("healthy row: got %q, want the link latency", alpha)
+	}
+	if !strings.Contains(beta, "seen 42s ago") {
+		t.Errorf("stale row: got %q, want the last-seen age", beta)
+	}
+	// Every width in the grid fits the terminal.
+	for _, line := range strings.Split(d.View(), "\n") {
+		if w := ansi.StringWidth(line); w > 120 {
+			t.Errorf("line is %d cells wide on a 120-column terminal: %q", w, ansi.Strip(line))
+		}
+	}
+}
+
+// The grid as -connect a,b,c shows it, with c yet to answer.
+func TestDashboardFrame(t *testing.T) {
+	ts := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC).UnixMilli()
+	d := testDashboard("a:9804", "b:9804", "c:9804")
+	d = d.feed(0, remoteSample("alpha", ts, 22))
+	d = d.feed(1, remoteSample("beta", ts, 93))
+	checkGoldenText(t, "frame_dashboard.txt", ansi.Strip(d.View()))
+}
@@ -0,0 +1,308 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/ALH477/infgo/ring"
+)
+
+const (
+	// ioSectorWrap is where a 32-bit count of 512-byte sectors wraps, as
+	// the kernel's do on 32-bit machines.
+	ioSectorWrap = 1 << 41
+
+	// ioMaxRate is more than any disk moves; a delta that would mean
+	// more is a counter reset, as when a device is detached and another
+	// takes its name, and gives no rate.
+	ioMaxRate = 1 << 36
+
+	// ioSparkFloor is the least the sparklines are scaled to, so that an
+	// idle disk's trickle draws as the trickle it is.
+	ioSparkFloor = 1 << 20
+
+	// ioAwaitWarnMs and ioAwaitCritMs are the awaits, in milliseconds,
+	// past which the slowest disk's is amber and red.
+	ioAwaitWarnMs = 10
+	ioAwaitCritMs = 50
+)
+
+// IOCounter is a disk's counters, cumulative since boot: of bytes, of
+// the reads and writes completed, and of the milliseconds spent on them.
+type IOCounter struct {
+	Name            string
+	Read, Written   uint64
+	Reads, Writes   uint64
+	ReadMs, WriteMs uint64
+}
+
+// ioRate is a disk's throughput between two readings, in bytes a second.
+type ioRate struct {
+	name        string
+	read, write float64
+}
+
+// counterDelta is how far a counter moved from prev to cur, across a
+// wrap where cur is the lower: at ioSectorWrap where prev is below it,
+// and at 2^64 otherwise.
+func counterDelta(prev, cur uint64) uint64 {
+	switch {
+	case cur >= prev:
+		return cur - prev
+	case prev < ioSectorWrap:
+		return ioSectorWrap - prev + cur
+	}
+	return math.MaxUint64 - prev + cur + 1
+}
+
+// ioRates are the throughputs of the disks read both times, dt apart, in
+// the order of cur.  A disk new in cur, or whose counters were reset, has
+// none yet.  false if dt is not positive.
+func ioRates(prev, cur []IOCounter, dt time.Duration) ([]ioRate, bool) {
+	if dt <= 0 {
+		return nil, false
+	}
+	secs := dt.Seconds()
+	var out []ioRate
+	for _, c := range cur {
+		i := slices.IndexFunc(prev, func(p IOCounter) bool { return p.Name == c.Name })
+		if i < 0 {
+			continue
+		}
+		r := ioRate{name: c.Name,
+			read:  float64(counterDelta(prev[i].Read, c.Read)) / secs,
+			write: float64(counterDelta(prev[i].Written, c.Written)) / secs,
+		}
+		if r.read > ioMaxRate || r.write > ioMaxRate {
+			continue
+		}
+		out = append(out, r)
+	}
+	return out, true
+}
+
+// ioLatency is a disk's await between two readings, in milliseconds, of
+// its reads and of its writes; readOK and writeOK are false where none
+// completed.
+type ioLatency struct {
+	name            string
+	read, write     float64
+	readOK, writeOK bool
+}
+
+// ioAwait is the average time the I/Os completed between two readings
+// took, in milliseconds: the time spent on them, from prevMs to curMs,
+// over their count, from prevOps to curOps.  false where none completed,
+// which has no average, and where a counter went backwards, as when a
+// device is detached and another takes its name.
+func ioAwait(prevMs, curMs, prevOps, curOps uint64) (float64, bool) {
+	if curOps <= prevOps || curMs < prevMs {
+		return 0, false
+	}
+	return float64(curMs-prevMs) / float64(curOps-prevOps), true
+}
+
+// ioLatencies are the awaits of the disks read both times, in the order of
+// cur.  A disk gone from cur, or new in it, has none.
+func ioLatencies(prev, cur []IOCounter) []ioLatency {
+	var out []ioLatency
+	for _, c := range cur {
+		i := slices.IndexFunc(prev, func(p IOCounter) bool { return p.Name == c.Name })
+		if i < 0 {
+			continue
+		}
+		l := ioLatency{name: c.Name}
+		l.read, l.readOK = ioAwait(prev[i].ReadMs, c.ReadMs, prev[i].Reads, c.Reads)
+		l.write, l.writeOK = ioAwait(prev[i].WriteMs, c.WriteMs, prev[i].Writes, c.Writes)
+		out = append(out, l)
+	}
+	return out
+}
+
+// worst is the longer of the disk's read and write awaits, and which it
+// is; false where it had neither.
+func (l ioLatency) worst() (ms float64, op string, ok bool) {
+	switch {
+	case l.readOK && (!l.writeOK || l.read >= l.write):
+		return l.read, "read", true
+	case l.writeOK:
+		return l.write, "write", true
+	}
+	return 0, "", false
+}
+
+// ioMeter turns the counters of successive readings into rates, and keeps
+// the history of their sums for the sparklines.
+type ioMeter struct {
+	prev   []IOCounter
+	prevAt time.Time
+
+	// total is the latest throughput summed over the disks and last the
+	// one before, for the trend arrows; ok once there has been one.
+	// disks is the latest of each disk.
+	total, last ioRate
+	ok          bool
+	disks       []ioRate
+
+	// lats is the latest await of each disk read both times.
+	lats []ioLatency
+
+	read, write ring.Buffer // total, a point a displayed reading
+	expanded    bool        // d: a row for each disk
+}
+
+func newIOMeter() *ioMeter {
+	io := &ioMeter{read: ring.New(HistoryLen), write: ring.New(HistoryLen)}
+	io.read.Fill(0)
+	io.write.Fill(0)
+	return io
+}
+
+// observe takes the counters cur read at at, nil where they were not, and
+// pushes the throughput since the previous ones; the last is repeated
+// where there is none.
+func (io *ioMeter) observe(cur []IOCounter, at time.Time) {
+	if cur != nil {
+		if io.prev != nil {
+			if disks, ok := ioRates(io.prev, cur, at.Sub(io.prevAt)); ok {
+				total := ioRate{}
+				for _, d := range disks {
+					total.read += d.read
+					total.write += d.write
+				}
+				io.last, io.total, io.disks, io.ok = io.total, total, disks, true
+				io.lats = ioLatencies(io.prev, cur)
+			}
+		}
+		io.prev, io.prevAt = cur, at
+	}
+	io.read.Push(io.total.read)
+	io.write.Push(io.total.write)
+}
+
+// ioSparkline draws the newest width points of h, bytes a second, scaled
+// to the highest of them.
+func ioSparkline(h *ring.Buffer, width int, col lipgloss.Color) string {
+	n := h.Len()
+	start := max(0, n-width)
+	top := float64(ioSparkFloor)
+	for i := start; i < n; i++ {
+		top = max(top, h.At(i))
+	}
+	scaled := ring.New(n - start)
+	for i := start; i < n; i++ {
+		scaled.Push(100 * h.At(i) / top)
+	}
+	return sparkline(&scaled, width, col)
+}
+
+// slowest is the disk with the longest await, its await and whether it
+// was of reads or writes; false where no disk completed an I/O.
+func (io *ioMeter) slowest() (name string, ms float64, op string, ok bool) {
+	for _, l := range io.lats {
+		if w, o, has := l.worst(); has && (!ok || w > ms) {
+			name, ms, op, ok = l.name, w, o, true
+		}
+	}
+	return name, ms, op, ok
+}
+
+// awaitText is an await in milliseconds, amber past ioAwaitWarnMs and red
+// past ioAwaitCritMs, or "—" where there is none.
+func awaitText(ms float64, ok bool) string {
+	if !ok {
+		return brightSt.Render("—")
+	}
+	st := brightSt
+	switch {
+	case ms > ioAwaitCritMs:
+		st = lipgloss.NewStyle().Foreground(cRed).Bold(true)
+	case ms > ioAwaitWarnMs:
+		st = lipgloss.NewStyle().Foreground(cAmber)
+	}
+	return st.Render(FormatNumber(ms, 1) + " ms")
+}
+
+// ioText is a throughput, or "—" before there is one.
+func (io *ioMeter) ioText(rate float64) string {
+	if !io.ok {
+		return "—"
+	}
+	return FormatBytes(rate) + "/s"
+}
+
+func (m Model) renderIO(iw int) string {
+	io := m.io
+	title := labelSt.Render("DISK I/O")
+	if io.ok {
+		title += "  " + dimSt.Render(fmt.Sprintf("%d disks", len(io.disks)))
+	}
+	if io.expanded {
+		title += dimSt.Render("   d total")
+	} else {
+		title += dimSt.Render("   d per disk")
+	}
+
+	const valueW = 16
+	sparkW := max(iw-2-6-2-valueW, 5)
+	const mb = 1e6
+	row := func(label string, h *ring.Buffer, col lipgloss.Color, now, prev float64) string {
+		value := brightSt.Render(io.ioText(now))
+		if io.ok {
+			value += " " + trendArrow(now/mb, prev/mb)
+		}
+		return dimSt.Render(PadVisual(label, 6)) + ioSparkline(h, sparkW, col) + "  " + value
+	}
+	lines := []string{title, "",
+		row("read", &io.read, cCyan, io.total.read, io.last.read),
+		row("write", &io.write, cViolet, io.total.write, io.last.write),
+	}
+	name, ms, op, ok := io.slowest()
+	await := dimSt.Render(PadVisual("await", 6)) + awaitText(ms, ok)
+	if ok && len(io.lats) > 1 {
+		await += dimSt.Render("  " + name + " " + op)
+	} else if ok {
+		await += dimSt.Render("  " + op)
+	}
+	lines = append(lines, await)
+	if io.expanded && io.ok {
+		nameW := 4
+		for _, d := range io.disks {
+			nameW = max(nameW, ansi.StringWidth(d.name))
+		}
+		lines = append(lines, "")
+		for _, d := range io.disks {
+			lines = append(lines, brightSt.Render(PadVisual(d.name, nameW))+"  "+
+				dimSt.Render("read ")+PadVisual(io.ioText(d.read), valueW)+
+				dimSt.Render("write ")+PadVisual(io.ioText(d.write), valueW)+
+				dimSt.Render("await ")+awaitText(io.diskAwait(d.name)))
+		}
+	}
+	return theme.HeatPanel(0, iw+4).Render(strings.Join(lines, "\n"))
+}
+
+// diskAwait is the longer of the named disk's awaits; false where it had
+// none.
+func (io *ioMeter) diskAwait(name string) (float64, bool) {
+	i := slices.IndexFunc(io.lats, func(l ioLatency) bool { return l.name == name })
+	if i < 0 {
+		return 0, false
+	}
+	ms, _, ok := io.lats[i].worst()
+	return ms, ok
+}
+
+// ioHeadline is the collapsed panel's value: the throughput summed.
+func (m Model) ioHeadline() string {
+	return dimSt.Render("read ") + brightSt.Render(m.io.ioText(m.io.total.read)) + "  " +
+		dimSt.Render("write ") + brightSt.Render(m.io.ioText(m.io.total.write))
+}
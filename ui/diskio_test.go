@@ -0,0 +1,173 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestCounterDelta(t *testing.T) {
+	for _, tt := range []struct {
+		prev, cur, want uint64
+	}{
+		{100, 100, 0},
+		{100, 612, 512},
+		{ioSectorWrap - 1024, 512, 1536},  // 32-bit sectors wrapped
+		{math.MaxUint64 - 511, 512, 1024}, // 64 bits wrapped
+		{ioSectorWrap + 4096, 0, math.MaxUint64 - ioSectorWrap - 4095}, // reset, left to ioRates
+	} {
+		if got := counterDelta(tt.prev, tt.cur); got != tt.want {
+			t.Errorf("%d to %d: got %d, want %d", tt.prev, tt.cur, got, tt.want)
+		}
+	}
+}
+
+// A sequence of readings, some late, one across a wrap and one across a
+// reset, gives the rates of the bytes over the time they were read in.
+func TestIORates(t *testing.T) {
+	const mb = 1 << 20
+	seq := []struct {
+		dt       time.Duration
+		counters []IOCounter
+		want     []ioRate
+		ok       bool
+	}{
+		{0, []IOCounter{{"sda", 0, 0, 0, 0, 0, 0}}, nil, false},
+		{time.Second, []IOCounter{{"sda", 10 * mb, 2 * mb, 0, 0, 0, 0}}, []ioRate{{"sda", 10 * mb, 2 * mb}}, true},
+		{3 * time.Second, []IOCounter{{"sda", 16 * mb, 2 * mb, 0, 0, 0, 0}}, []ioRate{{"sda", 2 * mb, 0}}, true},                                     // late
+		{time.Second, []IOCounter{{"sda", 16 * mb, 2 * mb, 0, 0, 0, 0}, {"sdb", ioSectorWrap - 4*mb, 5, 0, 0, 0, 0}}, []ioRate{{"sda", 0, 0}}, true}, // sdb new
+		{time.Second, []IOCounter{{"sda", 16 * mb, 2 * mb, 0, 0, 0, 0}, {"sdb", ioSectorWrap - 3*mb, 5, 0, 0, 0, 0}}, []ioRate{{"sda", 0, 0}, {"sdb", mb, 0}}, true},
+		{2 * time.Second, []IOCounter{{"sda", 16 * mb, 2 * mb, 0, 0, 0, 0}, {"sdb", ioSectorWrap - mb, 5, 0, 0, 0, 0}}, []ioRate{{"sda", 0, 0}, {"sdb", mb, 0}}, true},
+		{time.Second, []IOCounter{{"sda", 16 * mb, 2 * mb, 0, 0, 0, 0}, {"sdb", 3 * mb, 5, 0, 0, 0, 0}}, []ioRate{{"sda", 0, 0}, {"sdb", 4 * mb, 0}}, true}, // wrapped
+		{time.Second, []IOCounter{{"sda", mb, 0, 0, 0, 0, 0}, {"sdb", 3 * mb, 5, 0, 0, 0, 0}}, []ioRate{{"sdb", 0, 0}}, true},                               // sda reset
+		{0, []IOCounter{{"sda", 2 * mb, 0, 0, 0, 0, 0}}, nil, false},
+	}
+	var prev []IOCounter
+	for i, s := range seq {
+		got, ok := ioRates(prev, s.counters, s.dt)
+		if ok != s.ok || !slices.Equal(got, s.want) {
+			t.Errorf("reading %d: got %v, %v, want %v, %v", i, got, ok, s.want, s.ok)
+		}
+		prev = s.counters
+	}
+}
+
+func TestIOAwait(t *testing.T) {
+	for _, tt := range []struct {
+		name                           string
+		prevMs, curMs, prevOps, curOps uint64
+		ms                             float64
+		ok                             bool
+	}{
+		{"idle", 500, 500, 100, 100, 0, false},
+		{"fast", 500, 520, 100, 140, 0.5, true},
+		{"saturated", 500, 8500, 100, 200, 80, true},
+		{"queued but none completed", 500, 900, 100, 100, 0, false},
+		{"ops reset", 500, 520, 100, 4, 0, false},
+		{"time reset", 500, 20, 100, 140, 0, false},
+	} {
+		if ms, ok := ioAwait(tt.prevMs, tt.curMs, tt.prevOps, tt.curOps); ms != tt.ms || ok != tt.ok {
+			t.Errorf("%s: got %v, %v, want %v, %v", tt.name, ms, ok, tt.ms, tt.ok)
+		}
+	}
+}
+
+// A disk unplugged between readings, or new in the second, has no await;
+// one that only wrote has no read await, and the slowest is the longest
+// of any.
+func TestIOLatencies(t *testing.T) {
+	prev := []IOCounter{
+		{Name: "sda", Reads: 100, ReadMs: 200, Writes: 50, WriteMs: 100},
+		{Name: "sdb", Reads: 10, ReadMs: 10},
+		{Name: "sdc", Writes: 10, WriteMs: 10},
+	}
+	cur := []IOCounter{
+		{Name: "sda", Reads: 200, ReadMs: 700, Writes: 60, WriteMs: 700},
+		{Name: "sdc", Writes: 30, WriteMs: 50},
+		{Name: "sdd", Reads: 5, ReadMs: 5000},
+	}
+	io := newIOMeter()
+	io.lats = ioLatencies(prev, cur)
+	want := []ioLatency{
+		{name: "sda", read: 5, write: 60, readOK: true, writeOK: true},
+		{name: "sdc", write: 2, writeOK: true},
+	}
+	if !slices.Equal(io.lats, want) {
+		t.Errorf("got %+v", io.lats)
+	}
+	if name, ms, op, ok := io.slowest(); name != "sda" || ms != 60 || op != "write" || !ok {
+		t.Errorf("slowest: got %s %v %s %v", name, ms, op, ok)
+	}
+	io.lats = ioLatencies(cur, cur)
+	if name, _, _, ok := io.slowest(); ok {
+		t.Errorf("no I/O: got %s", name)
+	}
+}
+
+// The await is amber past ioAwaitWarnMs and red past ioAwaitCritMs.
+func TestAwaitText(t *testing.T) {
+	inColour(t)
+	if got := ansi.Strip(awaitText(0, false)); got != "—" {
+		t.Errorf("none: got %q", got)
+	}
+	for _, tt := range []struct {
+		ms   float64
+		want string
+	}{
+		{ioAwaitWarnMs, brightSt.Render("10.0 ms")},
+		{ioAwaitWarnMs + 1, lipgloss.NewStyle().Foreground(cAmber).Render("11.0 ms")},
+		{ioAwaitCritMs + 1, lipgloss.NewStyle().Foreground(cRed).Bold(true).Render("51.0 ms")},
+	} {
+		if got := awaitText(tt.ms, true); got != tt.want {
+			t.Errorf("%v ms: got %q, want %q", tt.ms, got, tt.want)
+		}
+	}
+}
+
+// The panel shows a dash until two readings have been compared, then the
+// summed rates, and each disk with d.
+func TestRenderIO(t *testing.T) {
+	inColour(t)
+	m := sizedModel(100, 50)
+	m.io = newIOMeter()
+	at := time.Unix(1700000000, 0)
+	update := func(read uint64) {
+		tm, _ := m.Update(Reading{CPUTotal: 10, MemPercent: 40, IOAt: at,
+			IO: []IOCounter{{"nvme0n1", read, 0, read >> 12, 0, read >> 16, 0}, {"sda", read / 2, 4 << 20, read >> 13, 0, read >> 14, 0}}})
+		m = tm.(Model)
+		at = at.Add(time.Second)
+	}
+
+	update(0)
+	if got := ansi.Strip(m.panel(PanelIO, innerWidth(100))); strings.Count(got, "—") != 3 {
+		t.Errorf("first tick: got\n%s", got)
+	}
+	update(20 << 20)
+	got := ansi.Strip(m.panel(PanelIO, innerWidth(100)))
+	if !strings.Contains(got, FormatBytes(30<<20)+"/s") || !strings.Contains(got, "await 0.5 ms  sda read") || strings.Contains(got, "—") {
+		t.Errorf("second tick: got\n%s", got)
+	}
+	if strings.Contains(got, "nvme0n1") {
+		t.Errorf("aggregated: got\n%s", got)
+	}
+	m = pressKeys(m, "d")
+	got = ansi.Strip(m.panel(PanelIO, innerWidth(100)))
+	if !strings.Contains(got, "nvme0n1") || !strings.Contains(got, "sda") {
+		t.Errorf("per disk: got\n%s", got)
+	}
+
+	// A reading without the counters repeats the last rate.
+	tm, _ := m.Update(Reading{CPUTotal: 10, MemPercent: 40})
+	m = tm.(Model)
+	if n := m.io.read.Len(); m.io.read.At(n-1) != m.io.read.At(n-2) {
+		t.Errorf("no counters: history ends %v, %v", m.io.read.At(n-2), m.io.read.At(n-1))
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// diskPanelRows is how many filesystems the panel shows, and diskNameW
+// the widest their mount points are shown.
+const (
+	diskPanelRows = 4
+	diskNameW     = 24
+)
+
+// inodeWarnPct and inodeCritPct are where the inodes in use turn amber
+// and red; inodeCellW is how wide fmtInodes writes them.
+const (
+	inodeWarnPct = 80
+	inodeCritPct = 95
+	inodeCellW   = len("ino 100.0%")
+)
+
+// shownMounts are the filesystems the panel shows, the fullest, in the
+// order of their mount points, and the number left out.
+func shownMounts(mounts []metrics.DiskUsage) ([]metrics.DiskUsage, int) {
+	if len(mounts) <= diskPanelRows {
+		return mounts, 0
+	}
+	shown := slices.Clone(mounts)
+	slices.SortStableFunc(shown, func(a, b metrics.DiskUsage) int { return cmp.Compare(b.UsedPercent, a.UsedPercent) })
+	shown = shown[:diskPanelRows]
+	slices.SortFunc(shown, func(a, b metrics.DiskUsage) int { return cmp.Compare(a.Mount, b.Mount) })
+	return shown, len(mounts) - diskPanelRows
+}
+
+// fullestMount is the filesystem with the most of it used; false if none
+// has been read.
+func fullestMount(mounts []metrics.DiskUsage) (metrics.DiskUsage, bool) {
+	if len(mounts) == 0 {
+		return metrics.DiskUsage{}, false
+	}
+	return slices.MaxFunc(mounts, func(a, b metrics.DiskUsage) int { return cmp.Compare(a.UsedPercent, b.UsedPercent) }), true
+}
+
+func (m Model) renderDisk(iw int) string {
+	title := labelSt.Render("DISK")
+	full, ok := fullestMount(m.mounts)
+	if !ok {
+		return theme.HeatPanel(0, iw+4).Render(title + "\n\n" + dimSt.Render("reading filesystems…"))
+	}
+	shown, hidden := shownMounts(m.mounts)
+	title += "  " + dimSt.Render(fmt.Sprintf("%d mounted", len(m.mounts)))
+
+	// The mount points share a column, as wide as the longest of them up
+	// to diskNameW, and the bars take what the sizes leave.
+	sizes := make([]string, len(shown))
+	nameW, sizeW, inodeW := 4, 0, 0
+	for i, u := range shown {
+		sizes[i] = FormatBytes(u.UsedGB*BytesPerGiB) + " / " + FormatBytes(u.TotalGB*BytesPerGiB)
+		sizeW = max(sizeW, ansi.StringWidth(sizes[i]))
+		nameW = max(nameW, min(ansi.StringWidth(u.Mount), diskNameW))
+		if u.InodesUsedPercent > 0 {
+			inodeW = inodeCellW + 2
+		}
+	}
+	barW := max(iw-nameW-sizeW-inodeW-6-7, 10) // the percentage, and the gaps
+
+	lines := []string{title, ""}
+	for i, u := range shown {
+		name := u.Mount
+		if ansi.StringWidth(name) > nameW {
+			name = ansi.Truncate(name, nameW, "…")
+		}
+		pct := lipgloss.NewStyle().Foreground(theme.LoadColor(u.UsedPercent)).Render(fmt.Sprintf("%6s", FormatPercent(u.UsedPercent)))
+		row := brightSt.Render(PadVisual(name, nameW)) + "  " +
+			theme.MiniBar(u.UsedPercent, barW) + "  " + dimSt.Render(PadVisual(sizes[i], sizeW)) + " " + pct
+		if inodeW > 0 {
+			row += "  " + fmtInodes(u)
+		}
+		lines = append(lines, row)
+	}
+	if hidden > 0 {
+		lines = append(lines, dimSt.Render(fmt.Sprintf("  (+%d more mounts)", hidden)))
+	}
+	return theme.HeatPanel(full.UsedPercent, iw+4).Render(strings.Join(lines, "\n"))
+}
+
+// fmtInodes is the inodes column of the panel for u, inodeCellW wide:
+// blank where the filesystem has no count of them.
+func fmtInodes(u metrics.DiskUsage) string {
+	if u.InodesUsedPercent <= 0 {
+		return strings.Repeat(" ", inodeCellW)
+	}
+	st := dimSt
+	switch {
+	case u.InodesUsedPercent > inodeCritPct:
+		st = lipgloss.NewStyle().Foreground(cRed)
+	case u.InodesUsedPercent > inodeWarnPct:
+		st = lipgloss.NewStyle().Foreground(cAmber)
+	}
+	return st.Render(fmt.Sprintf("%*s", inodeCellW, "ino "+FormatPercent(u.InodesUsedPercent)))
+}
+
+// diskHeadline is the collapsed panel's value: the fullest filesystem.
+func (m Model) diskHeadline() string {
+	full, ok := fullestMount(m.mounts)
+	if !ok {
+		return dimSt.Render("reading filesystems…")
+	}
+	return brightSt.Render(full.Mount) + "  " +
+		lipgloss.NewStyle().Foreground(theme.LoadColor(full.UsedPercent)).Render(FormatPercent(full.UsedPercent))
+}
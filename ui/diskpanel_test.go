@@ -0,0 +1,83 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// The fullest four are shown, in the order of their mount points, and the
+// rest counted.
+func TestRenderDisk(t *testing.T) {
+	inColour(t)
+	m := sizedModel(100, 50)
+	m.diskPanel = true
+	if got := ansi.Strip(m.renderDisk(innerWidth(100))); !strings.Contains(got, "reading filesystems…") {
+		t.Errorf("nothing read: got\n%s", got)
+	}
+	tm, _ := m.Update(Reading{CPUTotal: 10, MemPercent: 40, Mounts: []metrics.DiskUsage{
+		diskUsage("/", 62), diskUsage("/boot", 30), diskUsage("/home", 81),
+		diskUsage("/media/usb", 12), diskUsage("/srv", 45), diskUsage("/var", 97),
+	}})
+	m = tm.(Model)
+	view := ansi.Strip(m.View())
+	checkGoldenText(t, "layout_disk.txt", view)
+	order := []int{strings.Index(view, "/home"), strings.Index(view, "/srv"), strings.Index(view, "/var")}
+	if !slices.IsSorted(order) || strings.Contains(view, "/boot") || strings.Contains(view, "/media/usb") {
+		t.Errorf("got\n%s", view)
+	}
+
+	// 5 is the DISK panel and 3 SYSTEM, whatever else is shown.
+	m = pressKeys(m, "53")
+	if !m.collapsed[PanelDisk] || !m.collapsed[PanelSystem] {
+		t.Fatalf("got collapsed %v", m.collapsed)
+	}
+	if got := ansi.Strip(m.panel(PanelDisk, innerWidth(100))); !strings.Contains(got, "DISK  /var  "+FormatPercent(97)) {
+		t.Errorf("collapsed: got %q", got)
+	}
+}
+
+// The inodes column is as wide whatever it holds, blank for a filesystem
+// without a count of them, so the rows stay aligned.
+func TestFmtInodes(t *testing.T) {
+	inColour(t)
+	tests := []struct {
+		pct  float64
+		want string
+	}{
+		{0, ""},
+		{12, "ino 12.0%"},
+		{99.96, "ino 100.0%"},
+	}
+	for _, tt := range tests {
+		got := fmtInodes(metrics.DiskUsage{InodesUsedPercent: tt.pct})
+		if w := ansi.StringWidth(got); w != inodeCellW || strings.TrimSpace(ansi.Strip(got)) != tt.want {
+			t.Errorf("%v: got %q, %d wide", tt.pct, ansi.Strip(got), w)
+		}
+	}
+	if a, b := fmtInodes(metrics.DiskUsage{InodesUsedPercent: 85}), fmtInodes(metrics.DiskUsage{InodesUsedPercent: 97}); a == b || !strings.Contains(a, "85.0%") {
+		t.Errorf("amber and red: got %q and %q", a, b)
+	}
+
+	m := sizedModel(100, 50)
+	m.diskPanel = true
+	full := diskUsage("/var", 40)
+	full.InodesUsedPercent = 99.95
+	tm, _ := m.Update(Reading{CPUTotal: 10, MemPercent: 40, Mounts: []metrics.DiskUsage{diskUsage("/", 62), full}})
+	m = tm.(Model)
+	lines := strings.Split(ansi.Strip(m.renderDisk(innerWidth(100))), "\n")
+	if !strings.HasSuffix(strings.TrimRight(lines[4], " │"), "ino 100.0%") || ansi.StringWidth(lines[3]) != ansi.StringWidth(lines[4]) {
+		t.Errorf("got\n%s", strings.Join(lines, "\n"))
+	}
+}
+
+func diskUsage(mount string, pct float64) metrics.DiskUsage {
+	return metrics.DiskUsage{Mount: mount, UsedPercent: pct, UsedGB: pct, TotalGB: 100}
+}
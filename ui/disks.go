@@ -0,0 +1,22 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// diskUsages is the ticker's disk item: each filesystem's usage.
+type diskUsages []metrics.DiskUsage
+
+func (d diskUsages) tickerLine() (string, bool) {
+	parts := make([]string, len(d))
+	for i, u := range d {
+		parts[i] = fmt.Sprintf("%s %s of %s", u.Mount, FormatPercent(u.UsedPercent), FormatBytes(u.TotalGB*BytesPerGiB))
+	}
+	return "disk " + strings.Join(parts, " · "), len(d) > 0
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"math"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ALH477/infgo/ring"
+)
+
+// ── Bars and sparklines ───────────────────────────────────────────────────────
+
+// SparkChars is the Unicode block-element ramp of the sparklines.
+var SparkChars = []rune{'▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
+
+// SparkRune is the spark character for v percent.
+func SparkRune(v float64) rune {
+	idx := int(v/100*float64(len(SparkChars)-1) + 0.5)
+	if idx < 0 {
+		idx = 0
+	} else if idx >= len(SparkChars) {
+		idx = len(SparkChars) - 1
+	}
+	return SparkChars[idx]
+}
+
+// Sparkline draws the newest width readings of history in col.
+func Sparkline(history *ring.Buffer, width int, col lipgloss.Color) string {
+	n := history.Len()
+	var sb strings.Builder
+	for i := max(0, n-width); i < n; i++ {
+		sb.WriteRune(SparkRune(history.At(i)))
+	}
+	return lipgloss.NewStyle().Foreground(col).Render(sb.String())
+}
+
+// Bar is a heat-coloured full-width block bar of pct percent.
+func (t Theme) Bar(pct float64, width int) string {
+	return t.bar(pct, width, "█", "░")
+}
+
+// MiniBar is the compact ▮▯ bar of the per-core grid and load averages.
+func (t Theme) MiniBar(pct float64, width int) string {
+	return t.bar(pct, width, "▮", "▯")
+}
+
+func (t Theme) bar(pct float64, width int, full, empty string) string {
+	filled := int(math.Round(pct / 100 * float64(width)))
+	if filled > width {
+		filled = width
+	}
+	return lipgloss.NewStyle().Foreground(t.LoadColor(pct)).Render(strings.Repeat(full, filled)) +
+		lipgloss.NewStyle().Foreground(t.Border).Render(strings.Repeat(empty, width-filled))
+}
+
+// PadVisual right-pads s to n visible columns, not counting its ANSI
+// escape sequences.  A longer s is returned as it is.
+func PadVisual(s string, n int) string {
+	vw := lipgloss.Width(s)
+	if vw >= n {
+		return s
+	}
+	return s + strings.Repeat(" ", n-vw)
+}
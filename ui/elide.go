@@ -0,0 +1,125 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// segment is one item of the header or footer line.  When the line is
+// longer than the terminal, segments give up room in order of prio, the
+// lowest first: one that can shrink is shortened, down to min columns,
+// and any other dropped.
+type segment struct {
+	text  string // rendered
+	prio  int    // alwaysShown is never dropped
+	right bool   // after the gap, rather than before it
+
+	// shrink renders the segment in at most w columns, w >= min; nil if
+	// it cannot be shortened.
+	shrink func(w int) string
+	min    int
+}
+
+// alwaysShown is the prio of a segment that is never dropped: the title,
+// the quit keys.  Should even those not fit, the line is cut short.
+const alwaysShown = 1 << 30
+
+// fitLine lays segs out in room columns: the left-aligned ones, a gap of
+// at least a column, then the right-aligned ones, each side's separated
+// by sep.  The result is never wider than room.
+func fitLine(room int, sep string, segs []segment) string {
+	segs = append([]segment(nil), segs...)
+	order := make([]int, len(segs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return segs[order[a]].prio < segs[order[b]].prio })
+
+	// Each segment in turn gives up what it can: a shrinkable one down to
+	// its min, any other all of it.  Should that not be enough, the ones
+	// shortened go too.
+	over := lineWidth(sep, segs) - room
+	for _, i := range order {
+		if over <= 0 || segs[i].prio == alwaysShown {
+			break
+		}
+		s := &segs[i]
+		switch {
+		case s.shrink == nil:
+			s.text = ""
+		case lipgloss.Width(s.text) > s.min:
+			s.text = s.shrink(max(s.min, lipgloss.Width(s.text)-over))
+		}
+		over = lineWidth(sep, segs) - room
+	}
+	for _, i := range order {
+		if over <= 0 || segs[i].prio == alwaysShown {
+			break
+		}
+		segs[i].text = ""
+		over = lineWidth(sep, segs) - room
+	}
+
+	left, right := joinSide(sep, segs, false), joinSide(sep, segs, true)
+	gap := max(1, room-lipgloss.Width(left)-lipgloss.Width(right))
+	line := left + strings.Repeat(" ", gap) + right
+	if lipgloss.Width(line) > room {
+		line = ansi.Truncate(line, room, "…")
+	}
+	return line
+}
+
+// lineWidth is the width of segs laid out with the narrowest gap.
+func lineWidth(sep string, segs []segment) int {
+	return lipgloss.Width(joinSide(sep, segs, false)) + 1 + lipgloss.Width(joinSide(sep, segs, true))
+}
+
+// joinSide joins the segments of one side of the line, skipping dropped
+// ones.
+func joinSide(sep string, segs []segment, right bool) string {
+	var parts []string
+	for _, s := range segs {
+		if s.right == right && s.text != "" {
+			parts = append(parts, s.text)
+		}
+	}
+	return strings.Join(parts, sep)
+}
+
+// ElideLeft shortens s to at most w columns by cutting from the left, so
+// that the end of a path, its file name, is what stays.
+func ElideLeft(s string, w int) string {
+	if lipgloss.Width(s) <= w {
+		return s
+	}
+	if w < 1 {
+		return ""
+	}
+	r := []rune(s)
+	for i := range r {
+		if lipgloss.Width(string(r[i:])) <= w-1 {
+			return "…" + string(r[i:])
+		}
+	}
+	return "…"
+}
+
+// abbrevHost shortens host to at most w columns, dropping whole labels
+// from the right ("node1.lab.example.com" to "node1.lab") and cutting
+// the first label short only if it alone is too wide.
+func abbrevHost(host string, w int) string {
+	for lipgloss.Width(host) > w {
+		i := strings.LastIndexByte(host, '.')
+		if i <= 0 {
+			return ansi.Truncate(host, w, "…")
+		}
+		host = host[:i]
+	}
+	return host
+}
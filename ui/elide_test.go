@@ -0,0 +1,45 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"testing"
+)
+
+func TestAbbrevHost(t *testing.T) {
+	tests := []struct {
+		host string
+		w    int
+		want string
+	}{
+		{"node1.lab.example.com", 30, "node1.lab.example.com"},
+		{"node1.lab.example.com", 12, "node1.lab"},
+		{"node1.lab.example.com", 6, "node1"},
+		{"averyveryverylonghost.lab", 8, "averyve…"},
+	}
+	for _, tt := range tests {
+		if got := abbrevHost(tt.host, tt.w); got != tt.want {
+			t.Errorf("abbrevHost(%q, %d): got %q, want %q", tt.host, tt.w, got, tt.want)
+		}
+	}
+}
+
+func TestElideLeft(t *testing.T) {
+	tests := []struct {
+		s    string
+		w    int
+		want string
+	}{
+		{"captures/node1-143207.infgo", 40, "captures/node1-143207.infgo"},
+		{"/var/log/captures/node1-143207.infgo", 25, "…tures/node1-143207.infgo"},
+		{"日本語.infgo", 9, "…語.infgo"},
+		{"日本語.infgo", 8, "….infgo"},
+		{"abc", 0, ""},
+	}
+	for _, tt := range tests {
+		if got := ElideLeft(tt.s, tt.w); got != tt.want {
+			t.Errorf("elideLeft(%q, %d): got %q, want %q", tt.s, tt.w, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"time"
+)
+
+// coreMeter integrates CPU use into core-seconds: the sum of
+// cpu_total × numCores / 100 over the time between readings.
+type coreMeter struct {
+	seconds float64
+	last    time.Time
+}
+
+// add counts the time since the previous reading at pct percent of cores.
+// A gap longer than maxGap (a suspend, or a stopped process) is not
+// counted: nothing ran that infgo saw.
+func (c *coreMeter) add(pct float64, cores int, at time.Time, maxGap time.Duration) {
+	if !c.last.IsZero() {
+		if dt := at.Sub(c.last); dt > 0 && dt <= maxGap {
+			c.seconds += pct / 100 * float64(cores) * dt.Seconds()
+		}
+	}
+	c.last = at
+}
+
+// energyText is the Energy row: core-seconds, then, where RAPL was read,
+// the energy used and the power now.
+func (m Model) energyText() string {
+	out := brightSt.Render(fmtCoreSeconds(m.coreSecs.seconds))
+	switch {
+	case m.hasWatts:
+		out += dimSt.Render(" · ") + brightSt.Render(fmtEnergy(m.joules)) +
+			dimSt.Render(" · ") + brightSt.Render(FormatNumber(m.watts, 1)+" W")
+	case m.raplNote != "":
+		out += dimSt.Render(" · " + m.raplNote)
+	}
+	return out
+}
+
+// packagePower is the ticker's power item: the RAPL reading, if ok.
+type packagePower struct {
+	watts, joules float64
+	ok            bool
+}
+
+func (p packagePower) tickerLine() (string, bool) {
+	return "CPU package " + FormatNumber(p.watts, 1) + " W · " + fmtEnergy(p.joules) + " since start", p.ok
+}
+
+// fmtCoreSeconds gives core-seconds, or core-hours past an hour.
+func fmtCoreSeconds(s float64) string {
+	if s >= 3600 {
+		return FormatNumber(s/3600, 2) + " core-h"
+	}
+	return FormatNumber(s, 1) + " core-s"
+}
+
+// fmtEnergy gives joules, or watt-hours past one.
+func fmtEnergy(j float64) string {
+	if j >= 3600 {
+		return FormatNumber(j/3600, 2) + " Wh"
+	}
+	return FormatNumber(j, 0) + " J"
+}
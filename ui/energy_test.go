@@ -0,0 +1,36 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestCoreMeter(t *testing.T) {
+	t0 := time.Unix(1_700_000_000, 0)
+	const gap = 5 * DefaultInterval
+	var c coreMeter
+	steps := []struct {
+		after time.Duration // since t0
+		pct   float64
+		want  float64 // core-seconds so far
+	}{
+		{0, 50, 0},                          // the first reading only starts the clock
+		{500 * time.Millisecond, 50, 1},     // 4 cores × 50 % × 0.5 s
+		{time.Second, 100, 3},               // 4 × 100 % × 0.5 s
+		{time.Hour, 100, 3},                 // a suspend-sized gap is left out
+		{time.Hour + 2*time.Second, 25, 5},  // 4 × 25 % × 2 s
+		{time.Hour + 2*time.Second, 100, 5}, // no time passed
+		{time.Hour + time.Second, 100, 5},   // nor backwards
+		{time.Hour + 1500*time.Millisecond, 0, 5},
+	}
+	for i, st := range steps {
+		c.add(st.pct, 4, t0.Add(st.after), gap)
+		if math.Abs(c.seconds-st.want) > 1e-9 {
+			t.Fatalf("step %d: got %v core-seconds, want %v", i, c.seconds, st.want)
+		}
+	}
+}
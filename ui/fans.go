@@ -0,0 +1,38 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// FanReading is the speed of one fan.
+type FanReading struct {
+	Chip  string // the driver, e.g. nct6775
+	Num   int    // the N of fanN
+	Label string // fanN_label, or fanN
+	RPM   int
+}
+
+// FansMsg carries a reading of the fans to Update; fans is nil if it
+// failed.
+type FansMsg struct{ Fans []FanReading }
+
+// fansText is the Fans row's value, "cpu_fan 1180 rpm · fan2 0 rpm", a
+// stopped fan in red while the machine runs hot.
+func (m Model) fansText() string {
+	hot, ok := hottestTemp(m.temps)
+	parts := make([]string, len(m.fans))
+	for i, f := range m.fans {
+		rpm := brightSt
+		if f.RPM == 0 && ok && hot.Celsius >= tempWarnC {
+			rpm = lipgloss.NewStyle().Foreground(cRed).Bold(true)
+		}
+		parts[i] = dimSt.Render(f.Label+" ") + rpm.Render(fmt.Sprintf("%d", f.RPM)) + dimSt.Render(" rpm")
+	}
+	return strings.Join(parts, dimSt.Render(" · "))
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// The SYSTEM panel has a Fans row once they are read, with a stopped fan
+// in red only while the machine runs hot.
+func TestFansRow(t *testing.T) {
+	inColour(t)
+	m := sizedModel(100, 50)
+	if got := ansi.Strip(m.renderSystem(60)); strings.Contains(got, "Fans") {
+		t.Errorf("without fans: got\n%s", got)
+	}
+	tm, _ := m.Update(FansMsg{Fans: []FanReading{
+		{Chip: "dell_smm", Num: 1, Label: "fan1", RPM: 2400},
+		{Chip: "nct6775", Num: 1, Label: "fan1", RPM: 760},
+		{Chip: "nct6775", Num: 2, Label: "CPU Fan", RPM: 1180},
+		{Chip: "nct6775", Num: 10, Label: "fan10"},
+	}})
+	m = tm.(Model)
+	if got := ansi.Strip(m.fansText()); got != "fan1 2400 rpm · fan1 760 rpm · CPU Fan 1180 rpm · fan10 0 rpm" {
+		t.Errorf("got %q", got)
+	}
+	if got := ansi.Strip(m.renderSystem(60)); !strings.Contains(got, "Fans    fan1 2400 rpm") {
+		t.Errorf("got\n%s", got)
+	}
+	cool := m.fansText()
+	m.temps = []TempGroup{{Name: "coretemp", Celsius: 85}}
+	if hot := m.fansText(); hot == cool {
+		t.Error("a stopped fan in a hot machine is not marked")
+	}
+	m.temps = []TempGroup{{Name: "coretemp", Celsius: 45}}
+	if got := m.fansText(); got != cool {
+		t.Error("a stopped fan in a cool machine is marked")
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"fmt"
+)
+
+// FDUsage is one reading of the descriptors.
+type FDUsage struct {
+	Allocated, Max uint64 // system-wide
+	Self           int    // infgo's own
+}
+
+// FDsMsg carries a reading to Update; ok is false if it failed.
+type FDsMsg struct {
+	Usage FDUsage
+	OK    bool
+}
+
+// render is the FDs row's value: a bar of the system's share of its limit,
+// the counts, and infgo's own.
+func (u FDUsage) render() string {
+	var pct float64
+	if u.Max > 0 {
+		pct = min(100, 100*float64(u.Allocated)/float64(u.Max))
+	}
+	return theme.MiniBar(pct, 10) + "  " + brightSt.Render(fmtCount(u.Allocated)+" / "+fmtCount(u.Max)) +
+		dimSt.Render(fmt.Sprintf(" · infgo %d", u.Self))
+}
@@ -0,0 +1,35 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// The FDs row is shown once the descriptors are read, and left out again
+// when a reading fails.
+func TestFDsRow(t *testing.T) {
+	m := sizedModel(100, 50)
+	for _, tt := range []struct {
+		msg  FDsMsg
+		want string
+	}{
+		{FDsMsg{FDUsage{Allocated: 12431, Max: 1048576, Self: 23}, true}, "FDs     ▯▯▯▯▯▯▯▯▯▯  12,431 / 1,048,576 · infgo 23"},
+		{FDsMsg{}, ""},
+	} {
+		tm, _ := m.Update(tt.msg)
+		m = tm.(Model)
+		got := ansi.Strip(m.renderSystem(innerWidth(100) + 4))
+		if tt.want == "" {
+			if strings.Contains(got, "FDs") {
+				t.Errorf("after a failed reading: got\n%s", got)
+			}
+		} else if !strings.Contains(got, tt.want) {
+			t.Errorf("want %q: got\n%s", tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ALH477/infgo/analysis"
+)
+
+// ── Memory forecast ───────────────────────────────────────────────────────────
+
+// While memory climbs steadily the MEMORY panel says when, at that rate, it
+// will reach 95 % and 100 %: a line fitted to the readings of the last few
+// minutes (analysis.ForecastSeries), shown only while it rises and fits
+// them well.  `infgo analyze` makes the same forecast at the end of a
+// capture.
+
+// ForecastWindow is the default for -forecast-window.
+const ForecastWindow = 5 * time.Minute
+
+// forecastNear is the level the forecast row gives a time to before 100 %.
+const forecastNear = 95
+
+// forecastHorizon is the furthest ahead a forecast is shown: a climb that
+// would take longer to fill memory is too slow to matter.
+const forecastHorizon = 24 * time.Hour
+
+// memTrend keeps the memory readings of the last window.  It is shared by
+// the model's copies, like the histories.
+//
+// A nil *memTrend is disabled: add keeps nothing and forecast finds
+// nothing.
+type memTrend struct {
+	window time.Duration
+	times  []time.Time
+	vals   []float64
+}
+
+// newMemTrend returns nil, which is disabled, when window is not positive.
+func newMemTrend(window time.Duration) *memTrend {
+	if window <= 0 {
+		return nil
+	}
+	return &memTrend{window: window}
+}
+
+// add appends a reading and drops those that have left the window.
+func (t *memTrend) add(at time.Time, pct float64) {
+	if t == nil {
+		return
+	}
+	t.times = append(t.times, at)
+	t.vals = append(t.vals, pct)
+	drop := 0
+	for drop < len(t.times) && at.Sub(t.times[drop]) > t.window {
+		drop++
+	}
+	t.times, t.vals = t.times[drop:], t.vals[drop:]
+}
+
+// forecast fits the readings in the window.
+func (t *memTrend) forecast() (analysis.Forecast, bool) {
+	if t == nil {
+		return analysis.Forecast{}, false
+	}
+	return ShownForecast(analysis.ForecastSeries(t.times, t.vals, t.window, analysis.MinForecastR2))
+}
+
+// ShownForecast passes on a forecast that fills memory within
+// forecastHorizon.
+func ShownForecast(f analysis.Forecast, ok bool) (analysis.Forecast, bool) {
+	if !ok || f.Until(100) > forecastHorizon {
+		return analysis.Forecast{}, false
+	}
+	return f, true
+}
+
+// ForecastText is the forecast row: the time to forecastNear, unless
+// memory is past it already, and to 100 %.
+func ForecastText(f analysis.Forecast) string {
+	out := "at this rate, "
+	if near := f.Until(forecastNear); near > 0 {
+		out += fmt.Sprintf("%d%% in %s · ", forecastNear, fmtETA(near))
+	}
+	return out + "full in " + fmtETA(f.Until(100))
+}
+
+// fmtETA rounds a forecast's d to the minute, as befits a straight line.
+func fmtETA(d time.Duration) string {
+	d = d.Round(time.Minute)
+	switch {
+	case d < time.Minute:
+		return "<1 min"
+	case d < time.Hour:
+		return fmt.Sprintf("~%d min", d/time.Minute)
+	default:
+		return fmt.Sprintf("~%dh %02dm", d/time.Hour, d%time.Hour/time.Minute)
+	}
+}
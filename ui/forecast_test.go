@@ -0,0 +1,84 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/ALH477/infgo/analysis"
+)
+
+// climb feeds tr five minutes of readings a second apart rising from 50 %
+// by perMin points a minute.
+func climb(tr *memTrend, perMin float64) time.Time {
+	t0 := time.Unix(1_700_000_000, 0)
+	for i := 0; i <= 300; i++ {
+		tr.add(t0.Add(time.Duration(i)*time.Second), 50+perMin*float64(i)/60)
+	}
+	return t0.Add(300 * time.Second)
+}
+
+func TestMemTrend(t *testing.T) {
+	tr := newMemTrend(time.Minute)
+	last := climb(tr, 1)
+	if len(tr.times) != 61 || !tr.times[0].Equal(last.Add(-time.Minute)) {
+		t.Errorf("kept %d readings from %v, want the last minute's", len(tr.times), tr.times[0])
+	}
+	// 55 % and rising a point a minute.
+	f, ok := tr.forecast()
+	if !ok || f.Until(100).Round(time.Minute) != 45*time.Minute {
+		t.Errorf("got %+v, %v; want full in 45 min", f, ok)
+	}
+	if got, want := ForecastText(f), "at this rate, 95% in ~40 min · full in ~45 min"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// Too slow to matter: 50 points at 0.01 a minute.
+	tr = newMemTrend(5 * time.Minute)
+	climb(tr, 0.01)
+	if f, ok := tr.forecast(); ok {
+		t.Errorf("a crawl: got %+v", f)
+	}
+
+	off := newMemTrend(0)
+	off.add(time.Now(), 50)
+	if _, ok := off.forecast(); ok || off != nil {
+		t.Error("-forecast-window 0: got a forecast")
+	}
+}
+
+func TestFmtETA(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{20 * time.Second, "<1 min"},
+		{43*time.Minute + 20*time.Second, "~43 min"},
+		{59*time.Minute + 40*time.Second, "~1h 00m"},
+		{3*time.Hour + 7*time.Minute, "~3h 07m"},
+	}
+	for _, tt := range tests {
+		if got := fmtETA(tt.d); got != tt.want {
+			t.Errorf("fmtETA(%v): got %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+// The MEMORY panel has a forecast row while memory climbs, and only then;
+// past 95 % it gives the time to full alone.
+func TestForecastRow(t *testing.T) {
+	m := sizedModel(100, 40)
+	if view := ansi.Strip(m.View()); strings.Contains(view, "at this rate") {
+		t.Fatalf("no climb: got\n%s", view)
+	}
+	m.rev++
+	m.forecast, m.hasForecast = analysis.Forecast{Fit: analysis.Fit{Slope: 1.0 / 60}, Level: 96}, true
+	if view := ansi.Strip(m.View()); !strings.Contains(view, "at this rate, full in ~4 min") {
+		t.Errorf("got\n%s", view)
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"fmt"
+)
+
+// freqW is the width a frequency takes in a core's cell: a space and
+// "3.8GHz".
+const freqW = 7
+
+// fmtFreq is mhz in GHz to one decimal, as "3.8GHz".
+func fmtFreq(mhz float64) string {
+	return fmt.Sprintf("%.1fGHz", mhz/1000)
+}
+
+// coreFreqs are the frequencies to show after each of the m.cpuCores,
+// or nil when there is not one for each, or the grid has no room for
+// them in a column colW wide.
+func (m Model) coreFreqs(colW int) []float64 {
+	if len(m.coreMHz) != len(m.cpuCores) || colW < coreCellW(len(m.cpuCores))+freqW {
+		return nil
+	}
+	for _, f := range m.coreMHz {
+		if f <= 0 {
+			return nil
+		}
+	}
+	return m.coreMHz
+}
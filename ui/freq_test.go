@@ -0,0 +1,61 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestFmtFreq(t *testing.T) {
+	for mhz, want := range map[float64]string{
+		3800:    "3.8GHz",
+		3799.99: "3.8GHz",
+		3849.9:  "3.8GHz",
+		3850.1:  "3.9GHz",
+		800:     "0.8GHz",
+		2400.0:  "2.4GHz",
+		10000:   "10.0GHz",
+	} {
+		if got := fmtFreq(mhz); got != want {
+			t.Errorf("fmtFreq(%v) = %q, want %q", mhz, got, want)
+		}
+	}
+}
+
+// The grid shows each core's clock after its percentage, and without
+// them is as it was.
+func TestCoreGridFreqs(t *testing.T) {
+	m := sizedModel(100, 50)
+	tm, _ := m.Update(Reading{CPUTotal: 30, CPUCores: []float64{20, 40}, MemPercent: 40})
+	plain := ansi.Strip(tm.(Model).renderCPU(innerWidth(100)))
+	tm, _ = m.Update(Reading{CPUTotal: 30, CPUCores: []float64{20, 40}, CoreMHz: []float64{3800, 2400}, MemPercent: 40})
+	got := ansi.Strip(tm.(Model).renderCPU(innerWidth(100)))
+	for _, want := range []string{"20.0% 3.8GHz", "40.0% 2.4GHz"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in\n%s", want, got)
+		}
+	}
+	if strings.Count(got, "\n") != strings.Count(plain, "\n") {
+		t.Errorf("the clocks changed the grid's rows:\n%s", got)
+	}
+
+	// Not one for each core, or no room for them: none are shown.
+	tm, _ = m.Update(Reading{CPUTotal: 30, CPUCores: []float64{20, 40}, CoreMHz: []float64{3800}, MemPercent: 40})
+	if got := ansi.Strip(tm.(Model).renderCPU(innerWidth(100))); got != plain {
+		t.Errorf("one clock for two cores: got\n%s\nwant\n%s", got, plain)
+	}
+	if m.coreFreqs(coreCellW(2)+freqW-1) != nil {
+		t.Error("clocks shown in a column too narrow for them")
+	}
+
+	// A reading without them, while they back off, leaves the last shown.
+	tm, _ = m.Update(Reading{CPUTotal: 30, CPUCores: []float64{20, 40}, CoreMHz: []float64{3800, 2400}, MemPercent: 40})
+	tm, _ = tm.Update(Reading{CPUTotal: 30, CPUCores: []float64{20, 40}, MemPercent: 40})
+	if got := ansi.Strip(tm.(Model).renderCPU(innerWidth(100))); !strings.Contains(got, "20.0% 3.8GHz") {
+		t.Errorf("backing off: got\n%s", got)
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// GPUMsg carries a poll to Update; gpus is nil if it failed.
+type GPUMsg struct{ GPUs []metrics.GpuUsage }
+
+// busiestGPU is the index of the GPU in gpus, which must not be empty,
+// with the highest utilisation.
+func busiestGPU(gpus []metrics.GpuUsage) int {
+	best := 0
+	for i, g := range gpus {
+		if g.UtilPercent > gpus[best].UtilPercent {
+			best = i
+		}
+	}
+	return best
+}
+
+// renderGPU is the GPU panel: a row for each device.
+func (m Model) renderGPU(iw int) string {
+	const (
+		nameW = 20
+		barW  = 10
+	)
+	title := labelSt.Render("GPU") + dimSt.Render(fmt.Sprintf("  %d devices", len(m.gpus)))
+	if len(m.gpus) == 1 {
+		title = labelSt.Render("GPU")
+	}
+	if m.gpuStale {
+		title += "  " + dimSt.Render("stale")
+	}
+	lines := []string{title, ""}
+	for i, g := range m.gpus {
+		name := g.Name
+		if ansi.StringWidth(name) > nameW {
+			name = ansi.Truncate(name, nameW, "…")
+		}
+		memPct := g.MemPercent()
+		lines = append(lines, dimSt.Render(PadVisual(fmt.Sprintf("[%d]", i), 4))+
+			brightSt.Render(PadVisual(name, nameW))+"  "+
+			PadVisual(theme.MiniBar(g.UtilPercent, barW), barW)+"  "+
+			lipgloss.NewStyle().Foreground(theme.LoadColor(g.UtilPercent)).Render(fmt.Sprintf("%6s", FormatPercent(g.UtilPercent)))+"  "+
+			lipgloss.NewStyle().Foreground(theme.LoadColor(memPct)).Render(fmt.Sprintf("%5.1f", g.MemUsedGB))+
+			dimSt.Render(fmt.Sprintf("/%.1f GiB", g.MemTotalGB))+"  "+
+			lipgloss.NewStyle().Foreground(theme.LoadColor(tempLoad(g.TempC))).Render(m.tempText(g.TempC)))
+	}
+	return theme.HeatPanel(m.gpus[busiestGPU(m.gpus)].UtilPercent, iw+4).Render(strings.Join(lines, "\n"))
+}
+
+// gpuHeadline is the collapsed panel's value: the busiest GPU.
+func (m Model) gpuHeadline() string {
+	i := busiestGPU(m.gpus)
+	g := m.gpus[i]
+	return brightSt.Render(fmt.Sprintf("[%d] %s", i, g.Name)) + "  " +
+		lipgloss.NewStyle().Foreground(theme.LoadColor(g.UtilPercent)).Render(FormatPercent(g.UtilPercent))
+}
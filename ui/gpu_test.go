@@ -0,0 +1,56 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// The GPU panel appears with the first poll that finds one, and each
+// sample recorded after carries the poll.
+func TestGPUPanel(t *testing.T) {
+	m := sizedModel(100, 50)
+	rec := new(sinks)
+	m.rec = rec
+	if slices.Contains(m.panels(), PanelGPU) {
+		t.Fatal("GPU panel shown before a poll")
+	}
+	gpus := []metrics.GpuUsage{
+		{Name: "NVIDIA GeForce RTX 4090 Laptop GPU", UtilPercent: 95, MemUsedGB: 14, MemTotalGB: 16, TempC: 82},
+		{Name: "Tesla T4", UtilPercent: 5, MemUsedGB: 1, MemTotalGB: 15, TempC: 40},
+	}
+	tm, _ := m.Update(GPUMsg{gpus})
+	tm, _ = tm.Update(Reading{CPUTotal: 10, MemPercent: 40})
+	m = tm.(Model)
+	got := ansi.Strip(m.panel(PanelGPU, innerWidth(100)))
+	for _, want := range []string{"GPU  2 devices", "[0] NVIDIA GeForce RTX …", "95.0%", "14.0/16.0 GiB", "82.0°C", "[1] Tesla T4"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in\n%s", want, got)
+		}
+	}
+	if got := ansi.Strip(pressKeys(m, "f").panel(PanelGPU, innerWidth(100))); !strings.Contains(got, "179.6°F") {
+		t.Errorf("in °F: got\n%s", got)
+	}
+
+	// A failed poll leaves the last one shown, and recorded, marked stale.
+	tm, _ = m.Update(GPUMsg{})
+	tm, _ = tm.Update(Reading{CPUTotal: 10, MemPercent: 40})
+	m = tm.(Model)
+	if !slices.Contains(m.panels(), PanelGPU) || !strings.Contains(ansi.Strip(m.panel(PanelGPU, innerWidth(100))), "GPU  2 devices  stale") {
+		t.Errorf("after a failed poll: got\n%s", ansi.Strip(m.panel(PanelGPU, innerWidth(100))))
+	}
+	tm, _ = m.Update(GPUMsg{gpus[:1]})
+	if got := ansi.Strip(tm.(Model).panel(PanelGPU, innerWidth(100))); strings.Contains(got, "stale") {
+		t.Errorf("recovered: got\n%s", got)
+	}
+	if samples := rec.samples; len(samples) != 2 || !slices.Equal(samples[0].Gpus, gpus) || !slices.Equal(samples[1].Gpus, gpus) {
+		t.Errorf("logged: got %+v", samples)
+	}
+}
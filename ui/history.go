@@ -0,0 +1,67 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"time"
+
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ring"
+)
+
+// HistTrail keeps, in step with a pair of CPU and memory histories, the
+// time of each point and each core's reading at it.  Points are matched
+// from the newest, so the histories may hold points from before the trail
+// began, such as the zeros the TUI starts with; those are left out.
+type HistTrail struct {
+	times ring.Buffer   // Unix milliseconds of each point's tick
+	cores []ring.Buffer // one per core; started afresh when the count changes
+}
+
+func NewHistTrail(n int) HistTrail { return HistTrail{times: ring.New(n)} }
+
+// push records the tick at and the per-core readings of the point just
+// pushed to the histories; nil cores repeat the previous readings.
+func (t *HistTrail) Push(at time.Time, cores []float64) {
+	t.times.Push(float64(at.UnixMilli()))
+	if cores == nil {
+		for i := range t.cores {
+			v, _ := t.cores[i].Last(0)
+			t.cores[i].Push(v)
+		}
+		return
+	}
+	if len(cores) != len(t.cores) {
+		t.cores = make([]ring.Buffer, len(cores))
+		for i := range t.cores {
+			t.cores[i] = ring.New(t.times.Cap())
+		}
+	}
+	for i, v := range cores {
+		t.cores[i].Push(v)
+	}
+}
+
+// samples synthesizes a Sample from each point of cpu and mem that has a
+// time, oldest first.  withCores adds the per-core readings of the points
+// they were kept for.
+func (t *HistTrail) Samples(cpu, mem *ring.Buffer, withCores bool) []metrics.Sample {
+	n := min(t.times.Len(), cpu.Len(), mem.Len())
+	out := make([]metrics.Sample, n)
+	for k := range n {
+		s := &out[n-1-k]
+		ms, _ := t.times.Last(k)
+		s.TimestampUnixMs = int64(ms)
+		s.CpuTotal, _ = cpu.Last(k)
+		s.MemPercent, _ = mem.Last(k)
+		if !withCores || len(t.cores) == 0 || t.cores[0].Len() <= k {
+			continue
+		}
+		s.CpuCores = make([]float64, len(t.cores))
+		for i := range t.cores {
+			s.CpuCores[i], _ = t.cores[i].Last(k)
+		}
+	}
+	return out
+}
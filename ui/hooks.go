@@ -0,0 +1,158 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// ── Collectors, feeds and recorders ───────────────────────────────────────────
+//
+// A Model draws readings; where they come from and where they go besides
+// the screen are up to the program.  A Collector takes them on this
+// machine, a Feed brings another machine's, and a Recorder is handed each
+// one for a log or a server.  The infgo binary has its own of all three;
+// a Model without them reads this machine through a Source and records
+// nothing.
+
+// Collector takes the local readings.  Its commands deliver a Reading for
+// each, a SysInfo once, and the messages of the panels it can fill in.
+type Collector interface {
+	// Init starts the host info and any readings on timers of their own.
+	Init(ctx context.Context) tea.Cmd
+
+	// Read takes a reading for the stats tick due then, zero for one off
+	// schedule.  It returns nil while the previous one is still running:
+	// a tick never queues a reading behind a hung one.
+	Read(ctx context.Context, due time.Time) tea.Cmd
+
+	// Poll is run on every stats tick, for the readings that go with it.
+	Poll(ctx context.Context) tea.Cmd
+
+	// Update is handed the messages the Model has no use for, such as
+	// the Collector's own timers.
+	Update(ctx context.Context, msg tea.Msg) tea.Cmd
+
+	// Slept reports whether the machine has slept since the last reading,
+	// taken now, and the event that says so.  A Collector that says it has
+	// also forgets its CPU snapshot, which spans the sleep.
+	Slept(now time.Time) (metrics.Event, bool)
+
+	// Restart forgets the CPU snapshot and the time of the last reading
+	// after the process was stopped, so that the gap is not taken for a
+	// sleep or measured as CPU use.
+	Restart(now time.Time)
+}
+
+// Feed brings another machine's samples in place of a Collector's: an
+// HTTP poller, an SSH session or a capture replayed.
+type Feed interface {
+	Fetch() tea.Cmd // delivers one RemoteMsg
+	Name() string   // short label for the header, e.g. node7:9804
+	Target() string // what is being dialled, for the connecting screen
+	Close() error   // releases the connection once the TUI exits
+}
+
+// Replay is a Feed that plays back a recording, whose progress the header
+// shows in place of the link's.
+type Replay interface {
+	Feed
+	Progress() (played, total int)
+}
+
+// RemoteMsg is the outcome of one poll of a Feed.
+type RemoteMsg struct {
+	Reading Reading
+	Info    *SysInfo // nil until the remote knows its host info
+	RTT     time.Duration
+	Age     time.Duration // sample age as reported by the remote
+	Err     error
+}
+
+// Recorder is handed what a Model shows as it arrives.  Its methods are
+// called from Update and must not block.
+type Recorder interface {
+	// Init reads back the history a recording resumed with, if any, to
+	// be delivered as a Warm.
+	Init(ctx context.Context) tea.Cmd
+
+	Header(h metrics.Header)
+	Sample(s metrics.Sample, took time.Duration)
+	Event(e metrics.Event)
+	Process(p metrics.Process)
+
+	// Points is handed the history the sparklines show each time it
+	// changes, oldest first.
+	Points(ps []metrics.Sample)
+
+	// Flush writes out what is buffered, before the process is stopped.
+	Flush()
+
+	// Badges are shown at the right of the footer, in the order given.
+	Badges() []Badge
+}
+
+// Badge is a label of the footer.  Where the line is short of room the
+// badges of the lowest Prio go first; one with Shrink is first cut down
+// to as few as Min columns.
+type Badge struct {
+	Text   string
+	Prio   int
+	Min    int
+	Shrink func(w int) string
+}
+
+// nopRecorder records nothing.
+type nopRecorder struct{}
+
+func (nopRecorder) Init(context.Context) tea.Cmd         { return nil }
+func (nopRecorder) Header(metrics.Header)                {}
+func (nopRecorder) Sample(metrics.Sample, time.Duration) {}
+func (nopRecorder) Event(metrics.Event)                  {}
+func (nopRecorder) Process(metrics.Process)              {}
+func (nopRecorder) Points([]metrics.Sample)              {}
+func (nopRecorder) Flush()                               {}
+func (nopRecorder) Badges() []Badge                      { return nil }
+
+// ── Source collector ──────────────────────────────────────────────────────────
+
+// SampleMsg carries a reading of a Model's Source to it, through the
+// embedding program's Update.  ID is the Model's.
+type SampleMsg struct {
+	ID     int
+	Sample metrics.Sample
+}
+
+// sourceCollector is the Collector of a Model given a Source: a Sample on
+// each stats tick and none of the binary's other readings.
+type sourceCollector struct {
+	id   int
+	src  Source
+	busy *atomic.Bool // a reading is running
+}
+
+func (c sourceCollector) Init(ctx context.Context) tea.Cmd {
+	return func() tea.Msg { return readHostInfo(ctx) }
+}
+
+func (c sourceCollector) Read(ctx context.Context, _ time.Time) tea.Cmd {
+	if !c.busy.CompareAndSwap(false, true) {
+		return nil
+	}
+	return func() tea.Msg {
+		defer c.busy.Store(false)
+		return SampleMsg{ID: c.id, Sample: c.src(ctx)}
+	}
+}
+
+func (sourceCollector) Poll(context.Context) tea.Cmd            { return nil }
+func (sourceCollector) Update(context.Context, tea.Msg) tea.Cmd { return nil }
+func (sourceCollector) Slept(time.Time) (metrics.Event, bool)   { return metrics.Event{}, false }
+func (sourceCollector) Restart(time.Time)                       {}
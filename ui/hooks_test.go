@@ -0,0 +1,164 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+
+	syslogger "github.com/ALH477/infgo/logger"
+	"github.com/ALH477/infgo/metrics"
+)
+
+// sinks is a Recorder that keeps what it is handed, and writes the samples
+// to log if there is one.
+type sinks struct {
+	nopRecorder
+	log     *syslogger.Logger
+	headers []metrics.Header
+	samples []metrics.Sample
+	events  []metrics.Event
+	procs   []metrics.Process
+	flushes int
+}
+
+func (r *sinks) Header(h metrics.Header) { r.headers = append(r.headers, h) }
+func (r *sinks) Event(e metrics.Event)   { r.events = append(r.events, e) }
+func (r *sinks) Process(p metrics.Process) {
+	r.procs = append(r.procs, p)
+}
+func (r *sinks) Flush() { r.flushes++ }
+
+func (r *sinks) Sample(s metrics.Sample, _ time.Duration) {
+	if r.log != nil {
+		_ = r.log.WriteSample(s)
+		return
+	}
+	r.samples = append(r.samples, s)
+}
+
+// script is a Source playing samples, then the last again.
+func script(samples ...metrics.Sample) Source {
+	i := 0
+	return func(context.Context) metrics.Sample {
+		s := samples[min(i, len(samples)-1)]
+		i++
+		return s
+	}
+}
+
+// run applies n readings of m, as a program would, skipping the waits.
+func run(t *testing.T, m Model, n int) Model {
+	t.Helper()
+	var tm tea.Model = m
+	for range n {
+		msg, ok := m.collector.Read(m.ctx, time.Time{})().(SampleMsg)
+		if !ok || msg.ID != m.ID() {
+			t.Fatalf("got %#v, want a SampleMsg of model %d", msg, m.ID())
+		}
+		tm, _ = tm.Update(msg)
+	}
+	return tm.(Model)
+}
+
+func ramp(n int) []metrics.Sample {
+	out := make([]metrics.Sample, n)
+	for i := range out {
+		out[i] = metrics.Sample{
+			TimestampUnixMs: int64(1700000000000 + 500*i),
+			CpuTotal:        float64(10 + 3*i), CpuCores: []float64{1, 2, 3, 4},
+			MemPercent: float64(40 + i), MemUsedGB: 6.4, MemTotalGB: 16,
+			Load1: 1.5, Load5: 1, Load15: 0.5,
+		}
+	}
+	return out
+}
+
+// A Model given a Source shows its readings; with WithPanels, only the
+// panels asked for, with neither header nor footer.
+func TestSourceModel(t *testing.T) {
+	m := run(t, New(WithSource(script(ramp(3)...)), WithWidth(60), WithPanels(PanelMemory)), 3)
+	view := ansi.Strip(m.View())
+	if strings.Contains(view, "CPU") || strings.Contains(view, "quit") || !strings.Contains(view, "MEMORY") || !strings.Contains(view, "42.0%") {
+		t.Errorf("memory only: got\n%s", view)
+	}
+	if m.width != 60 {
+		t.Errorf("width: got %d, want 60", m.width)
+	}
+}
+
+// Two Models in one program take only their own readings and ticks, and
+// follow the terminal's width unless it is fixed.
+func TestModelsApart(t *testing.T) {
+	a := New(WithSource(script(ramp(1)...)))
+	b := New(WithSource(script(ramp(1)...)), WithWidth(50))
+	msg := a.collector.Read(a.ctx, time.Time{})().(SampleMsg)
+	tb, _ := b.Update(msg)
+	if tb.(Model).cpuTotal != 0 {
+		t.Error("b took a's reading")
+	}
+	ta, _ := a.Update(msg)
+	if ta.(Model).cpuTotal != 10 {
+		t.Errorf("a's CPU: got %v, want 10", ta.(Model).cpuTotal)
+	}
+	if _, cmd := tb.Update(statsTickMsg{id: a.ID()}); cmd != nil {
+		t.Error("b took a's stats tick")
+	}
+
+	ta, _ = ta.Update(tea.WindowSizeMsg{Width: 70, Height: 20})
+	tb, _ = tb.Update(tea.WindowSizeMsg{Width: 70, Height: 20})
+	if ta.(Model).width != 70 || tb.(Model).width != 50 {
+		t.Errorf("got widths %d and %d, want 70 and the fixed 50", ta.(Model).width, tb.(Model).width)
+	}
+}
+
+// A reading off schedule, at 100ms, reaches the Recorder each time but
+// the panels only every third; the footer shows the interval.
+func TestUpdateDisplayThrottle(t *testing.T) {
+	rec := new(sinks)
+	m := New(WithInterval(100*time.Millisecond), WithRecorder(rec))
+	t0 := time.Now()
+
+	var tm tea.Model = m
+	for i, cpu := range []float64{10, 20, 90} {
+		before := tm.(Model)
+		msg := Reading{CPUTotal: cpu, CPUCores: []float64{cpu}, MemPercent: 50, At: t0.Add(time.Duration(i) * 100 * time.Millisecond)}
+		tm, _ = tm.Update(msg)
+		got := tm.(Model)
+		if i < 2 {
+			if got.cpuTotal != 0 || got.ready {
+				t.Fatalf("reading %d: displayed %.0f%%, want it held back", i, got.cpuTotal)
+			}
+			if got.rev != before.rev {
+				t.Errorf("reading %d: a held-back reading changed the panels", i)
+			}
+		}
+	}
+	got := tm.(Model)
+	if got.cpuTotal != 90 || got.cpuPeak != 90 || !got.ready {
+		t.Errorf("got cpu %.0f%%, peak %.0f%%, want 90 for both", got.cpuTotal, got.cpuPeak)
+	}
+	if last, _ := got.cpuHistory.Last(0); last != 40 {
+		t.Errorf("history point: got %.1f, want 40, the mean of the three", last)
+	}
+	if n := len(rec.samples); n != 3 {
+		t.Errorf("the recorder got %d samples, want 3", n)
+	}
+	if want := 3 * 100 * time.Millisecond * HistoryLen / time.Second; got.sparkWindowSeconds() != int(want) {
+		t.Errorf("sparkline window: got %ds, want %ds", got.sparkWindowSeconds(), want)
+	}
+	tm, _ = tm.Update(tea.WindowSizeMsg{Width: 140, Height: 50})
+	if v := ansi.Strip(tm.View()); !strings.Contains(v, "↺ 100ms") {
+		t.Errorf("the footer does not show the 100ms interval:\n%s", v)
+	}
+}
+
+// busy marks the reading of m, a Model reading a Source, as running, as if
+// it had hung, so that a stats tick starts none.
+func busy(m Model, on bool) { m.collector.(sourceCollector).busy.Store(on) }
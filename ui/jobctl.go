@@ -0,0 +1,58 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// StoppedEvent is the event logged as ctrl+z stops the process.
+func StoppedEvent(at time.Time) metrics.Event {
+	return metrics.Event{TimestampUnixMs: at.UnixMilli(), Kind: "stopped", Message: "stopped by job control"}
+}
+
+// ContinuedEvent is the event logged as the process, stopped at from, is
+// continued at to.
+func ContinuedEvent(from, to time.Time) metrics.Event {
+	return metrics.Event{TimestampUnixMs: to.UnixMilli(), Kind: "continued",
+		Message: fmt.Sprintf("continued after %s stopped", to.Sub(from).Round(time.Second))}
+}
+
+// continuedMsg is sent once the TUI's process is continued after ctrl+z.
+type continuedMsg struct {
+	from time.Time // when it stopped
+	err  error     // the process could not be stopped
+}
+
+// stopJob marks the stop in the log, flushes it and hands the terminal
+// back to the shell.
+func (m Model) stopJob() (tea.Model, tea.Cmd) {
+	at := time.Now()
+	m.rec.Event(StoppedEvent(at))
+	m.rec.Flush()
+	return m, tea.Exec(m.jobStop, func(err error) tea.Msg { return continuedMsg{from: at, err: err} })
+}
+
+// continueJob marks the end of the stop and takes a reading at once, its
+// CPU snapshot primed afresh rather than measured across the stop.
+func (m Model) continueJob(msg continuedMsg) (tea.Model, tea.Cmd) {
+	now := time.Now()
+	if msg.err != nil {
+		fmt.Fprintf(os.Stderr, "infgo: stop: %v\n", msg.err)
+	}
+	m.rec.Event(ContinuedEvent(msg.from, now))
+	if m.collector == nil {
+		return m, nil
+	}
+	m.collector.Restart(now)
+	m.sched = newStatsSchedule(m.sched.interval) // the stop is not jitter
+	m.pending = pendingReadings{}
+	return m, m.fetch(time.Time{})
+}
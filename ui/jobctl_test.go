@@ -0,0 +1,49 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// restarting is a Collector that counts its restarts.
+type restarting struct {
+	Collector
+	n int
+}
+
+func (c *restarting) Restart(time.Time) { c.n++ }
+
+// ctrl+z logs the stop and flushes it before handing over the terminal;
+// continuing logs for how long, restarts the collector and reads at once.
+func TestStopJob(t *testing.T) {
+	rec := new(sinks)
+	m := New(WithSource(script(ramp(1)...)), WithRecorder(rec))
+	c := &restarting{Collector: m.collector}
+	m.collector = c
+	m.sched.skipped = 3
+
+	next, cmd := m.stopJob()
+	if cmd == nil {
+		t.Fatal("ctrl+z did not hand over the terminal")
+	}
+	if len(rec.events) != 1 || rec.events[0].Kind != "stopped" || rec.flushes != 1 {
+		t.Fatalf("before stopping: got %+v, %d flushes", rec.events, rec.flushes)
+	}
+
+	from := time.Now().Add(-90 * time.Second)
+	next, cmd = next.(Model).Update(continuedMsg{from: from})
+	got := next.(Model)
+	if cmd == nil || c.n != 1 {
+		t.Errorf("got %d restarts, want a reading started after the one", c.n)
+	}
+	if got.sched.skipped != 0 {
+		t.Errorf("got %d skipped ticks after the stop, want the schedule restarted", got.sched.skipped)
+	}
+	if len(rec.events) != 2 || rec.events[1].Kind != "continued" || !strings.Contains(rec.events[1].Message, "1m30s") {
+		t.Errorf("got %+v, want a continued event after 1m30s", rec.events)
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"time"
+)
+
+// KernelCounters are the context switches and interrupts since boot.
+type KernelCounters struct {
+	Ctxt, Intr uint64
+}
+
+// kernelRates are the context switches and interrupts a second between
+// readings prev, at prevAt, and cur, at at.  It is false for the first
+// reading, whose prevAt is zero, where no time passed, and where a counter
+// went backwards.
+func kernelRates(prev KernelCounters, prevAt time.Time, cur KernelCounters, at time.Time) (ctxt, intr float64, ok bool) {
+	if prevAt.IsZero() {
+		return 0, 0, false
+	}
+	ctxt, okC := counterRate(prev.Ctxt, cur.Ctxt, at.Sub(prevAt))
+	intr, okI := counterRate(prev.Intr, cur.Intr, at.Sub(prevAt))
+	if !okC || !okI {
+		return 0, 0, false
+	}
+	return ctxt, intr, true
+}
+
+// counterRate is how fast a cumulative counter moved from prev to cur, dt
+// later, a second.  It is false where no time passed and where the counter
+// went backwards, as one reset or begun again from zero, whose delta is not
+// a rate.
+func counterRate(prev, cur uint64, dt time.Duration) (float64, bool) {
+	if dt <= 0 || cur < prev {
+		return 0, false
+	}
+	return float64(cur-prev) / dt.Seconds(), true
+}
+
+// kernelMeter is the model's state for the Kernel row.
+type kernelMeter struct {
+	prev       KernelCounters
+	prevAt     time.Time
+	ctxt, intr float64
+	ok         bool // ctxt and intr hold a rate
+}
+
+// observe folds in a reading at at; a zero at is a reading without the
+// counters, which leaves the rates as they were.
+func (k *kernelMeter) observe(c KernelCounters, at time.Time) {
+	if at.IsZero() {
+		return
+	}
+	k.ctxt, k.intr, k.ok = kernelRates(k.prev, k.prevAt, c, at)
+	k.prev, k.prevAt = c, at
+}
+
+// text is the Kernel row's value, with "—" for the rates until there are
+// two readings to take them from.
+func (k *kernelMeter) text() string {
+	ctxt, intr := "—", "—"
+	if k.ok {
+		ctxt, intr = humanizeRate(k.ctxt), humanizeRate(k.intr)
+	}
+	return brightSt.Render(ctxt) + dimSt.Render(" ctx switches · ") +
+		brightSt.Render(intr) + dimSt.Render(" interrupts")
+}
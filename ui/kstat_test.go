@@ -0,0 +1,76 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestKernelRates(t *testing.T) {
+	t0 := time.Unix(1700000000, 0)
+	prev := KernelCounters{Ctxt: 1000, Intr: 500}
+	tests := []struct {
+		name       string
+		prevAt, at time.Time
+		cur        KernelCounters
+		ctxt, intr float64
+		ok         bool
+	}{
+		{"first reading", time.Time{}, t0, prev, 0, 0, false},
+		{"on time", t0, t0.Add(500 * time.Millisecond), KernelCounters{6000, 1500}, 10000, 2000, true},
+		{"a late tick", t0, t0.Add(2 * time.Second), KernelCounters{21000, 4500}, 10000, 2000, true},
+		{"no time passed", t0, t0, KernelCounters{6000, 1500}, 0, 0, false},
+		{"a counter went backwards", t0, t0.Add(time.Second), KernelCounters{10, 1500}, 0, 0, false},
+	}
+	for _, tt := range tests {
+		ctxt, intr, ok := kernelRates(prev, tt.prevAt, tt.cur, tt.at)
+		if ctxt != tt.ctxt || intr != tt.intr || ok != tt.ok {
+			t.Errorf("%s: got %v, %v, %v", tt.name, ctxt, intr, ok)
+		}
+	}
+}
+
+func TestCounterRate(t *testing.T) {
+	tests := []struct {
+		prev, cur uint64
+		dt        time.Duration
+		rate      float64
+		ok        bool
+	}{
+		{1000, 1000, time.Second, 0, true},
+		{1000, 1500, 500 * time.Millisecond, 1000, true},
+		{1000, 7000, 2 * time.Second, 3000, true},
+		{1000, 1500, 0, 0, false},
+		{1000, 1500, -time.Second, 0, false},
+		{1000, 10, time.Second, 0, false}, // reset
+	}
+	for _, tt := range tests {
+		if rate, ok := counterRate(tt.prev, tt.cur, tt.dt); rate != tt.rate || ok != tt.ok {
+			t.Errorf("%d to %d in %v: got %v, %v", tt.prev, tt.cur, tt.dt, rate, ok)
+		}
+	}
+}
+
+// The Kernel row is left out until the counters are read, shows "—" for
+// the first reading and the rates from the second.
+func TestKernelRow(t *testing.T) {
+	m := sizedModel(100, 50)
+	at := time.Unix(1700000000, 0)
+	if got := ansi.Strip(m.renderSystem(innerWidth(100) + 4)); strings.Contains(got, "Kernel") {
+		t.Fatalf("without the counters: got\n%s", got)
+	}
+	for i, want := range []string{"— ctx switches · — interrupts", "150.0k/s ctx switches · 2000/s interrupts"} {
+		tm, _ := m.Update(Reading{CPUTotal: 10, MemPercent: 40,
+			Kernel: KernelCounters{Ctxt: uint64(i) * 150_000, Intr: uint64(i) * 2000}, KernelAt: at})
+		m = tm.(Model)
+		if got := ansi.Strip(m.renderSystem(innerWidth(100) + 4)); !strings.Contains(got, "Kernel  "+want) {
+			t.Errorf("reading %d: got\n%s", i, got)
+		}
+		at = at.Add(time.Second)
+	}
+}
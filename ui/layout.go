@@ -0,0 +1,122 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// ── Collapsed panels ──────────────────────────────────────────────────────────
+
+// The number keys collapse a panel to a single line with its headline
+// value, and expand it again.  Each panel has its number in Panel order,
+// 1 for CPU, 2 MEMORY, 3 SYSTEM and LOAD AVG, 4 USERS and so on, whichever
+// others are shown, so a panel that appears late does not move the rest.
+// Past 9 they go on to 0 and then alt+1, alt+2 and so on.  The rows a
+// collapsed panel frees go to the core grid, which is sized to what the
+// other panels leave (roomForCPU).
+
+// panelNames name the panels in the layout file, in Panel order.
+var panelNames = [numPanels]string{"cpu", "memory", "system", "users", "disk", "io", "net", "procs", "temp", "gpu", "pid", "pressure"}
+
+// togglePanel collapses p if it is expanded and expands it if not.  A
+// panel that is not shown is left alone.
+func (m Model) togglePanel(p Panel) Model {
+	for _, q := range m.panels() {
+		if q == p {
+			m.collapsed[p] = !m.collapsed[p]
+			m.rev++
+		}
+	}
+	return m
+}
+
+// collapseKey is the key that collapses p: "1" to "9", then "0", then
+// "alt+1" on.
+func collapseKey(p Panel) string {
+	switch {
+	case p < 9:
+		return fmt.Sprint(int(p) + 1)
+	case p == 9:
+		return "0"
+	}
+	return fmt.Sprintf("alt+%d", int(p)-9)
+}
+
+// collapseKeys is the footer's list of the keys of the panels shown, runs
+// of the digits 1-9 as ranges: "1-3,5,0,alt+2".
+func (m Model) collapseKeys() string {
+	var runs []string
+	ps := m.panels()
+	slices.Sort(ps)
+	for i := 0; i < len(ps); i++ {
+		j := i
+		for ps[i] < 9 && j+1 < len(ps) && ps[j+1] < 9 && ps[j+1] == ps[j]+1 {
+			j++
+		}
+		run := collapseKey(ps[i])
+		if j > i {
+			run += "-" + collapseKey(ps[j])
+		}
+		runs = append(runs, run)
+		i = j
+	}
+	return strings.Join(runs, ",")
+}
+
+// renderCollapsed is panel p's collapsed form: its title and headline
+// value on one line, e.g. "MEMORY  61.8%  ▸".
+func (m Model) renderCollapsed(p Panel, iw int) string {
+	var head string
+	switch p {
+	case PanelCPU:
+		head = labelSt.Render("CPU") + "  " +
+			m.valueStyle(metrics.MissingCPU, m.cpuTotal).Render(FormatPercent(m.cpuTotal)) +
+			m.staleTag(metrics.MissingCPU, m.cpuSeen) + m.throttle.badge()
+	case PanelMemory:
+		head = labelSt.Render("MEMORY") + "  " +
+			m.valueStyle(metrics.MissingMem, m.memPercent).Render(FormatPercent(m.memPercent)) +
+			m.staleTag(metrics.MissingMem, m.memSeen)
+	case PanelSystem:
+		load := 100 * m.load1 / float64(max(1, m.numCores))
+		head = labelSt.Render("SYSTEM") + "  " + brightSt.Render(m.hostname) + "   " +
+			labelSt.Render("LOAD AVG") + "  " +
+			m.valueStyle(metrics.MissingLoad, load).Render(FormatNumber(m.load1, 2)) +
+			m.staleTag(metrics.MissingLoad, m.loadSeen)
+	case PanelDisk:
+		head = labelSt.Render("DISK") + "  " + m.diskHeadline()
+	case PanelIO:
+		head = labelSt.Render("DISK I/O") + "  " + m.ioHeadline()
+	case PanelNet:
+		head = labelSt.Render("NET") + "  " + m.netHeadline()
+	case PanelProcs:
+		head = labelSt.Render("PROCESSES") + "  " + m.procsHeadline()
+	case PanelTemp:
+		head = labelSt.Render("TEMP") + "  " + m.tempHeadline()
+	case PanelGPU:
+		head = labelSt.Render("GPU") + "  " + m.gpuHeadline()
+	case PanelPID:
+		head = labelSt.Render("PID") + "  " + m.pidsHeadline()
+	case PanelPressure:
+		head = labelSt.Render("PRESSURE") + "  " + m.pressureHeadline()
+	case PanelUsers:
+		head = labelSt.Render("USERS") + "  "
+		if len(m.users) == 0 {
+			head += dimSt.Render("scanning processes…")
+		} else {
+			top := m.users[0]
+			share := min(100, top.cpu/float64(max(1, m.numCores)))
+			head += brightSt.Render(top.name) + "  " +
+				lipgloss.NewStyle().Foreground(theme.LoadColor(share)).Render(FormatPercent(top.cpu))
+		}
+	}
+	return ansi.Truncate("   "+head+"  "+dimSt.Render("▸"), iw+4, "…")
+}
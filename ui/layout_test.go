@@ -0,0 +1,115 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ring"
+)
+
+// pressKeys sends each key to m in turn.
+func pressKeys(m Model, keys string) Model {
+	var tm tea.Model = m
+	for _, r := range keys {
+		tm, _ = tm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	return tm.(Model)
+}
+
+// The panels past 9 take 0 and then alt with a digit.
+func TestCollapseKeysPastNine(t *testing.T) {
+	m := sizedModel(100, 40)
+	m.gpus = []metrics.GpuUsage{{Name: "Tesla T4"}}
+	m.pids = []pidWatch{{pid: 4242, history: ring.New(HistoryLen)}}
+	m.hasPressure = true
+	if got := m.collapseKeys(); got != "1-3,0,alt+1,alt+2" {
+		t.Errorf("got keys %q", got)
+	}
+	var tm tea.Model = pressKeys(m, "0")
+	for _, r := range "12" {
+		tm, _ = tm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}, Alt: true})
+	}
+	m = tm.(Model)
+	if !m.collapsed[PanelGPU] || !m.collapsed[PanelPID] || !m.collapsed[PanelPressure] || m.collapsed[PanelCPU] {
+		t.Errorf("got collapsed %v, want GPU, PID and PRESSURE", m.collapsed)
+	}
+}
+
+func TestCollapsedLayouts(t *testing.T) {
+	tests := []struct {
+		keys   string
+		users  bool
+		golden string
+	}{
+		{"2", false, "layout_memory.txt"},
+		{"23", false, "layout_memory_system.txt"},
+		{"1", false, "layout_cpu.txt"},
+		{"1234", true, "layout_all.txt"},
+		{"22", false, ""}, // collapsed and expanded again
+	}
+	full := sizedModel(100, 40).View()
+	for _, tt := range tests {
+		var opts []Option
+		if tt.users {
+			opts = append(opts, WithUsers())
+		}
+		m := pressKeys(sizedModel(100, 40, opts...), tt.keys)
+		view := m.View()
+		if lines := strings.Count(view, "\n") + 1; lines > 40 {
+			t.Errorf("%s: got %d lines, want at most 40", tt.keys, lines)
+		}
+		if tt.golden == "" {
+			if view != full {
+				t.Errorf("%s: got\n%s\nwant the full layout", tt.keys, view)
+			}
+			continue
+		}
+		checkGoldenText(t, tt.golden, ansi.Strip(view))
+	}
+}
+
+// The rows a collapsed panel frees go to the core grid.
+func TestCollapseGrowsCoreGrid(t *testing.T) {
+	m := sizedModel(100, 40)
+	msg := benchStats()
+	var tm tea.Model = m
+	tm, _ = tm.Update(msg)
+	m = tm.(Model)
+	hidden := hiddenCores(m.View())
+	if hidden == 0 {
+		t.Fatalf("all %d cores fit already; the test needs a shorter screen", len(msg.CPUCores))
+	}
+	if got := hiddenCores(pressKeys(m, "2").View()); got >= hidden {
+		t.Errorf("memory collapsed: %d cores hidden, want fewer than %d", got, hidden)
+	}
+	if got, was := hiddenCores(pressKeys(m, "23").View()), hiddenCores(pressKeys(m, "2").View()); got > was {
+		t.Errorf("memory and system collapsed: %d cores hidden, want at most %d", got, was)
+	}
+	if m := pressKeys(m, "4"); m.collapsed[PanelUsers] {
+		t.Error("4 collapsed the USERS panel, which is not shown")
+	}
+}
+
+// A panel keeps its number when another appears before it, as TEMP does
+// with its first reading.
+func TestCollapseKeysFixed(t *testing.T) {
+	m := pressKeys(sizedModel(100, 40), "3")
+	if !m.collapsed[PanelSystem] || m.collapseKeys() != "1-3" {
+		t.Fatalf("got collapsed %v, keys %q", m.collapsed, m.collapseKeys())
+	}
+	m.temps = []TempGroup{{Name: "coretemp", Celsius: 45}}
+	if got := m.collapseKeys(); got != "1-3,9" {
+		t.Errorf("with TEMP: got keys %q", got)
+	}
+	m = pressKeys(m, "39")
+	if m.collapsed[PanelSystem] || !m.collapsed[PanelTemp] {
+		t.Errorf("got collapsed %v, want SYSTEM expanded and TEMP collapsed", m.collapsed)
+	}
+}
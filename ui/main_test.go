@@ -0,0 +1,527 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/progress"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
+
+	syslogger "github.com/ALH477/infgo/logger"
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ring"
+)
+
+// benchStats is a reading with a typical core count.
+func benchStats() Reading {
+	cores := make([]float64, 16)
+	for i := range cores {
+		cores[i] = float64(i * 6)
+	}
+	return Reading{CPUTotal: 45, CPUCores: cores, MemPercent: 61, MemUsedGB: 9.8, MemTotalGB: 16}
+}
+
+// benchModel is a model mid-session: full histories, host info and a
+// reading with the given core count, sized to a width-column terminal.
+func benchModel(width, cores int) Model {
+	m := New()
+	m.width, m.height = width, 50
+	m.memProgress.Width = innerWidth(width) - 6
+	m.hostname, m.platform, m.uptime = "bench-host", "linux 6.8", 86400
+	for i := range HistoryLen {
+		m.cpuHistory.Push(float64(i * 7 % 100))
+		m.memHistory.Push(float64(40 + i%20))
+	}
+	msg := benchStats()
+	msg.CPUCores = make([]float64, cores)
+	for i := range msg.CPUCores {
+		msg.CPUCores[i] = float64(i * 13 % 100)
+	}
+	msg.Load1, msg.Load5, msg.Load15 = 2.5, 1.8, 1.2
+	next, _ := m.Update(msg)
+	return next.(Model)
+}
+
+// BenchmarkView is the cost of rendering a whole frame from scratch, as
+// on every reading, from the narrowest layout to the widest and with
+// more cores than the grid shows.  BenchmarkFrames has the frames between
+// readings, which the panel cache serves.
+func BenchmarkView(b *testing.B) {
+	for _, width := range []int{minInnerWidth + 4, 90, maxInnerWidth + 4} {
+		for _, cores := range []int{4, 16, 64} {
+			b.Run(fmt.Sprintf("w%d/cores%d", width, cores), func(b *testing.B) {
+				m := benchModel(width, cores)
+				m.view = nil
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					_ = m.View()
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkSparkline is the per-frame cost of drawing a history.
+func BenchmarkSparkline(b *testing.B) {
+	m := New()
+	for i := range HistoryLen {
+		m.cpuHistory.Push(float64(i * 3))
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = sparkline(&m.cpuHistory, HistoryLen, cViolet)
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	h := ring.New(4)
+	for _, v := range []float64{0, 100, 50, 0, 100} {
+		h.Push(v)
+	}
+	tests := []struct {
+		width int
+		want  string
+	}{
+		{4, "█▅▁█"},
+		{3, "▅▁█"}, // the newest readings
+		{10, "█▅▁█"},
+	}
+	for _, tt := range tests {
+		if got := ansi.Strip(sparkline(&h, tt.width, cCyan)); got != tt.want {
+			t.Errorf("width %d: got %q, want %q", tt.width, got, tt.want)
+		}
+	}
+}
+
+// markedColumn is the column of the peak marker in a sparkline rendered
+// in colour, or -1.
+func markedColumn(spark string) int {
+	open, _, _ := strings.Cut(peakSt.Render("x"), "x")
+	i := strings.Index(spark, open)
+	if i < 0 {
+		return -1
+	}
+	return utf8.RuneCountInString(ansi.Strip(spark[:i]))
+}
+
+// inColour renders in true colour for the rest of the test.
+func inColour(t *testing.T) {
+	prev := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	t.Cleanup(func() { lipgloss.SetColorProfile(prev) })
+}
+
+func TestMarkedSparkline(t *testing.T) {
+	inColour(t)
+	h := ring.New(4)
+	for _, v := range []float64{0, 100, 50, 0, 100} {
+		h.Push(v)
+	}
+	tests := []struct {
+		width, back int
+		want        int // marked column
+	}{
+		{4, 0, 3},  // the newest, last
+		{4, 3, 0},  // the oldest held, first
+		{4, 4, -1}, // evicted
+		{3, 2, 0},  // the oldest shown
+		{3, 3, -1}, // held, but scrolled out of view
+		{4, -1, -1},
+	}
+	for _, tt := range tests {
+		got := markedSparkline(&h, tt.width, cCyan, tt.back)
+		if col := markedColumn(got); col != tt.want {
+			t.Errorf("width %d, back %d: marked column %d, want %d", tt.width, tt.back, col, tt.want)
+		}
+		// The marker only recolours a rune.
+		if plain, want := ansi.Strip(got), ansi.Strip(sparkline(&h, tt.width, cCyan)); plain != want {
+			t.Errorf("width %d, back %d: got %q, want %q", tt.width, tt.back, plain, want)
+		}
+	}
+}
+
+// The peak is marked where it happened and scrolls along with the history;
+// once it has been evicted the marker goes but the number stays.
+func TestUpdatePeakMarker(t *testing.T) {
+	inColour(t)
+	var tm tea.Model = New()
+	tm, _ = tm.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	msg := benchStats()
+	const border = 3 // the panel's border and padding before the sparkline
+	sparkRow := func(m Model, title string) string {
+		panel := m.renderCPU(innerWidth(m.width))
+		if title == "MEMORY" {
+			panel = m.renderMemory(innerWidth(m.width))
+		}
+		for _, line := range strings.Split(panel, "\n") {
+			if strings.Contains(line, "←") {
+				return line
+			}
+		}
+		t.Fatalf("no sparkline in the %s panel", title)
+		return ""
+	}
+	// A peak in the newest reading is marked in the last column.
+	msg.CPUTotal, msg.MemPercent = 90, 80
+	tm, _ = tm.Update(msg)
+	m := tm.(Model)
+	last := utf8.RuneCountInString(ansi.Strip(sparkline(&m.cpuHistory, 1000, cViolet))) - 1
+	for _, title := range []string{"CPU", "MEMORY"} {
+		row := sparkRow(m, title)
+		open := strings.Index(row, "←")
+		if col := markedColumn(row[:open]); col != border+last {
+			t.Errorf("%s: peak marked at column %d, want %d", title, col-border, last)
+		}
+	}
+
+	// After HistoryLen-1 more readings it is the oldest held, the first.
+	msg.CPUTotal, msg.MemPercent = 10, 10
+	for range HistoryLen - 1 {
+		tm, _ = tm.Update(msg)
+	}
+	m = tm.(Model)
+	if col := markedColumn(sparkRow(m, "CPU")); col != border {
+		t.Errorf("oldest: peak marked at column %d, want 0", col-border)
+	}
+
+	// One more and it is gone from the history, but not from the title.
+	tm, _ = tm.Update(msg)
+	m = tm.(Model)
+	if col := markedColumn(sparkRow(m, "CPU")); col != -1 {
+		t.Errorf("evicted: peak still marked at column %d", col-border)
+	}
+	if panel := ansi.Strip(m.renderCPU(innerWidth(m.width))); !strings.Contains(panel, "peak 90.0%") {
+		t.Errorf("evicted: the session peak is no longer shown:\n%s", panel)
+	}
+	if panel := ansi.Strip(m.renderMemory(innerWidth(m.width))); !strings.Contains(panel, "peak 80.0%") {
+		t.Errorf("evicted: the memory peak is no longer shown:\n%s", panel)
+	}
+}
+
+// A tick while the previous reading is still out starts no second one.
+func TestStatsTickWhileFetching(t *testing.T) {
+	m := New()
+	busy(m, true)
+	next, _ := m.Update(statsTickMsg{id: m.id, t: time.Now()})
+	got := next.(Model)
+	if got.sched.busy != 1 {
+		t.Errorf("got %d busy ticks, want 1", got.sched.busy)
+	}
+	if !strings.Contains(got.sched.summary(), ", 1 busy") {
+		t.Errorf("summary: got %q", got.sched.summary())
+	}
+
+	busy(m, false)
+	next, _ = m.Update(statsTickMsg{id: m.id, t: time.Now()})
+	if got := next.(Model); got.sched.busy != 0 || !got.collector.(sourceCollector).busy.Load() {
+		t.Errorf("idle: got %d busy ticks, fetching %v; want 0 and a reading started", got.sched.busy, got.collector.(sourceCollector).busy.Load())
+	}
+}
+
+// The SWAP row sits under the memory bar where the machine has swap, and
+// keeps its last reading through one that could not read it.
+func TestSwapRow(t *testing.T) {
+	m := sizedModel(100, 50)
+	if got := ansi.Strip(m.renderMemory(innerWidth(100))); strings.Contains(got, "SWAP") {
+		t.Errorf("no swap: got\n%s", got)
+	}
+	tm, _ := m.Update(Reading{MemPercent: 40, CPUCores: []float64{1},
+		SwapUsedGB: 1, SwapTotalGB: 4, SwapPercent: 25, HasSwap: true})
+	tm, _ = tm.Update(Reading{MemPercent: 41, CPUCores: []float64{1}})
+	m = tm.(Model)
+	lines := strings.Split(ansi.Strip(m.renderMemory(innerWidth(100))), "\n")
+	i := slices.IndexFunc(lines, func(l string) bool { return strings.Contains(l, "SWAP") })
+	if i < 0 || !strings.Contains(lines[i-1], "░") || !strings.Contains(lines[i], FormatPercent(25)+"  1.00 GiB / 4.00 GiB") {
+		t.Fatalf("got\n%s", strings.Join(lines, "\n"))
+	}
+	if w := ansi.StringWidth(lines[i]); w != ansi.StringWidth(lines[0]) {
+		t.Errorf("SWAP row is %d wide, the panel %d", w, ansi.StringWidth(lines[0]))
+	}
+}
+
+// BenchmarkUpdateStats is the per-tick cost of applying a reading.
+func BenchmarkUpdateStats(b *testing.B) {
+	var m tea.Model = New()
+	msg := benchStats()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		msg.CPUTotal = float64(i % 100)
+		m, _ = m.Update(msg)
+	}
+}
+
+// BenchmarkHotPath is a whole local tick on a 64-core host: the reading
+// applied and logged, then with the frame rendered too.  gopsutil's own
+// slice per reading is not counted.
+func BenchmarkHotPath(b *testing.B) {
+	for _, render := range []bool{false, true} {
+		name := "log"
+		if render {
+			name = "log+render"
+		}
+		b.Run(name, func(b *testing.B) {
+			lgr, err := syslogger.NewWriter(io.Discard)
+			if err != nil {
+				b.Fatal(err)
+			}
+			m := New()
+			m.rec = &sinks{log: lgr}
+			m.width, m.height = 120, 40
+			var tm tea.Model = m
+			msg := benchStats()
+			msg.CPUCores = make([]float64, 64)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				msg.CPUTotal = float64(i % 100)
+				tm, _ = tm.Update(msg)
+				if render {
+					_ = tm.View()
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkFilledBar is the CPU panel's main bar at a wide terminal's width.
+func BenchmarkFilledBar(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = theme.Bar(float64(i%100), 160)
+	}
+}
+
+// BenchmarkPadVisual is one cell of the per-core grid.
+func BenchmarkPadVisual(b *testing.B) {
+	cell := dimSt.Render("[12] ") + theme.MiniBar(45, 8) + dimSt.Render(" 45.0%")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = PadVisual(cell, 40)
+	}
+}
+
+func TestUpdateStatsHistory(t *testing.T) {
+	var m tea.Model = New()
+	msg := benchStats()
+	for i := range HistoryLen + 5 {
+		msg.CPUTotal, msg.MemPercent = float64(i), float64(100-i)
+		m, _ = m.Update(msg)
+	}
+	got := m.(Model)
+	if got.cpuHistory.Len() != HistoryLen {
+		t.Fatalf("got %d readings, want %d", got.cpuHistory.Len(), HistoryLen)
+	}
+	if v, _ := got.cpuHistory.Last(0); v != HistoryLen+4 {
+		t.Errorf("newest CPU reading: got %v, want %d", v, HistoryLen+4)
+	}
+	if v := got.memHistory.At(0); v != 95 {
+		t.Errorf("oldest memory reading: got %v, want 95", v)
+	}
+}
+
+func TestQuitCancelsSampling(t *testing.T) {
+	m := New()
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Fatal("q did not quit")
+	}
+	if next.(Model).ctx.Err() == nil {
+		t.Error("the sampling context is still live after quit")
+	}
+}
+
+func TestUpdateStatsStale(t *testing.T) {
+	t0 := time.Now().Add(-time.Minute)
+	tests := []struct {
+		name    string
+		missing metrics.Missing
+		panel   string // the title the stale tag follows
+	}{
+		{"cpu", metrics.MissingCPU, "CPU"},
+		{"mem", metrics.MissingMem, "MEMORY"},
+		{"load", metrics.MissingLoad, "LOAD AVG"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m tea.Model = New()
+			good := benchStats()
+			good.Load1, good.At = 1.5, t0
+			m, _ = m.Update(good)
+
+			// Failing: the previous values stay, repeated into the histories.
+			failed := Reading{CPUTotal: 0, Missing: tt.missing, At: t0.Add(12 * time.Second)}
+			if !tt.missing.Has(metrics.MissingCPU) {
+				failed.CPUTotal, failed.CPUCores = 80, []float64{80}
+			}
+			if !tt.missing.Has(metrics.MissingMem) {
+				failed.MemPercent = 70
+			}
+			if !tt.missing.Has(metrics.MissingLoad) {
+				failed.Load1 = 3
+			}
+			m, _ = m.Update(failed)
+			got := m.(Model)
+			want := [...]float64{good.CPUTotal, good.MemPercent, good.Load1}
+			fresh := [...]float64{80, 70, 3}
+			vals := [...]float64{got.cpuTotal, got.memPercent, got.load1}
+			for i, g := range [...]metrics.Missing{metrics.MissingCPU, metrics.MissingMem, metrics.MissingLoad} {
+				if !tt.missing.Has(g) {
+					want[i] = fresh[i]
+				}
+				if vals[i] != want[i] {
+					t.Errorf("%q: got %v, want %v", g, vals[i], want[i])
+				}
+			}
+			if v, _ := got.memHistory.Last(0); v != got.memPercent {
+				t.Errorf("memory history: got %v, want the value shown, %v", v, got.memPercent)
+			}
+			if v, _ := got.cpuHistory.Last(0); v != got.cpuTotal {
+				t.Errorf("CPU history: got %v, want the value shown, %v", v, got.cpuTotal)
+			}
+
+			view := ansi.Strip(got.View())
+			if !strings.Contains(view, "stale ") {
+				t.Errorf("no stale tag in the view:\n%s", view)
+			}
+			if n := strings.Count(view, "stale "); n != 1 {
+				t.Errorf("got %d stale tags, want 1 for %s", n, tt.panel)
+			}
+
+			// Recovering clears it.
+			m, _ = m.Update(good)
+			if view := ansi.Strip(m.View()); strings.Contains(view, "stale ") {
+				t.Error("still stale after a good reading")
+			}
+		})
+	}
+}
+
+// A reading in which nothing could be read changes nothing on screen.
+func TestUpdateStatsNothingRead(t *testing.T) {
+	var m tea.Model = New()
+	m, _ = m.Update(Reading{Missing: metrics.MissingAll})
+	if m.(Model).ready {
+		t.Error("an empty first reading made the model ready")
+	}
+	m, _ = m.Update(benchStats())
+	m, _ = m.Update(Reading{Missing: metrics.MissingAll})
+	if got := m.(Model); got.cpuTotal != 45 || got.memPercent != 61 {
+		t.Errorf("got cpu %v mem %v, want the previous reading kept", got.cpuTotal, got.memPercent)
+	}
+}
+
+func TestUpdateStatsTimedOut(t *testing.T) {
+	var m tea.Model = New()
+	m, _ = m.Update(benchStats())
+	m, _ = m.Update(Reading{CPUTotal: 10, CPUCores: []float64{10},
+		Missing: metrics.MissingMem | metrics.MissingLoad, TimedOut: metrics.MissingMem})
+	view := ansi.Strip(m.View())
+	if n := strings.Count(view, "timed out · "); n != 1 {
+		t.Errorf("got %d timed-out tags, want 1 for MEMORY:\n%s", n, view)
+	}
+}
+
+// The per-core readings decide the core count, as on Windows where they
+// can disagree with runtime.NumCPU; a change mid-session is followed.
+func TestUpdateStatsCoreCount(t *testing.T) {
+	rec := new(sinks)
+	m := New(WithRecorder(rec))
+	m.numCores = 96
+	m.width, m.height = 120, 60
+	readCores := func(n int) {
+		t.Helper()
+		msg := Reading{CPUTotal: 50, CPUCores: make([]float64, n), Load1: float64(n) / 2}
+		next, _ := m.Update(msg)
+		m = next.(Model)
+		view := ansi.Strip(m.View())
+		if m.numCores != n || !strings.Contains(view, fmt.Sprintf("%d logical", n)) {
+			t.Errorf("%d cores read: got numCores %d", n, m.numCores)
+		}
+		shown := n - hiddenCores(view)
+		last := fmt.Sprintf("[%d] ", shown-1)
+		if !strings.Contains(view, last) || strings.Contains(view, fmt.Sprintf("[%d] ", shown)) {
+			t.Errorf("%d cores read: the grid does not end at %q", n, last)
+		}
+		// Load is normalised against the count read: n/2 is 50%.
+		if want := theme.MiniBar(50, 9); !strings.Contains(m.renderLoad(40), want) {
+			t.Errorf("%d cores read: the load bar is not at 50%%", n)
+		}
+	}
+
+	readCores(64) // the first processor group only
+	readCores(64)
+	readCores(8)
+	events, samples := rec.events, rec.samples
+	if len(events) != 2 {
+		t.Fatalf("got events %+v, want one per change", events)
+	}
+	if e := events[0]; e.Kind != "cores" || !strings.HasPrefix(e.Message, "64 cores in the per-core readings, was 96") {
+		t.Errorf("got %+v", e)
+	}
+	if got := len(samples[len(samples)-1].CpuCores); got != 8 {
+		t.Errorf("last sample logged %d cores, want 8", got)
+	}
+}
+
+// The memory bar takes the amber and red ramps past warnPct and critPct.
+// The goldens pin the bar, in true colour, at each level.
+func TestMemoryBarGradient(t *testing.T) {
+	tests := []struct {
+		pct    float64
+		level  int
+		golden string
+	}{
+		{50, 0, "membar_50.txt"},
+		{75, 1, "membar_75.txt"},
+		{95, 2, "membar_95.txt"},
+	}
+	for _, tt := range tests {
+		var tm tea.Model = New()
+		tm, _ = tm.Update(Reading{MemPercent: tt.pct, CPUCores: []float64{1}})
+		m := tm.(Model)
+		if m.memLevel != tt.level {
+			t.Errorf("%v%%: got level %d, want %d", tt.pct, m.memLevel, tt.level)
+		}
+		progress.WithColorProfile(termenv.TrueColor)(&m.memProgress)
+		m.memProgress.Width = 40
+		checkGoldenText(t, tt.golden, m.memProgress.ViewAs(tt.pct/100))
+	}
+}
+
+// Crossing a threshold swaps the ramp without restarting the easing: the
+// bar carries on from where it was shown.
+func TestMemoryBarGradientKeepsEasing(t *testing.T) {
+	var tm tea.Model = New()
+	tm, cmd := tm.Update(Reading{MemPercent: 50, CPUCores: []float64{1}})
+	for range 3 {
+		tm, cmd = tm.Update(cmd())
+	}
+	filled := func(m tea.Model) int {
+		return strings.Count(ansi.Strip(m.(Model).memProgress.View()), "█")
+	}
+	before := filled(tm)
+	if before == 0 {
+		t.Fatal("the bar did not move")
+	}
+	tm, cmd = tm.Update(Reading{MemPercent: 95, CPUCores: []float64{1}})
+	if m := tm.(Model); m.memLevel != 2 {
+		t.Fatalf("got level %d at 95%%, want 2", m.memLevel)
+	}
+	if got := filled(tm); got != before {
+		t.Errorf("swapping the ramp moved the bar from %d cells to %d", before, got)
+	}
+	tm, _ = tm.Update(cmd())
+	if got := filled(tm); got <= before {
+		t.Errorf("after the swap the bar did not ease on: %d cells, was %d", got, before)
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"strings"
+)
+
+// ── Memory breakdown ──────────────────────────────────────────────────────────
+//
+// Linux counts little of its page cache as used, but much of the rest of
+// what a reader calls used can be handed back on demand, and free -m users
+// reckon memory by what is available rather than by what is free.  The
+// MEMORY panel breaks the memory down under the used and total, and a
+// switches its title between the memory used and the memory not available.
+
+// memHeadline is the title's percentage: of the memory used, or with
+// memFromAvail of the memory not available.  Without an available reading
+// it is the memory used.
+func (m Model) memHeadline() float64 {
+	if !m.memFromAvail || m.memAvailGB <= 0 || m.memTotalGB <= 0 {
+		return m.memPercent
+	}
+	return min(max(100*(m.memTotalGB-m.memAvailGB)/m.memTotalGB, 0), 100)
+}
+
+// memHeadlineHint names what a switches the title to, where it can.
+func (m Model) memHeadlineHint() string {
+	switch {
+	case m.memAvailGB <= 0:
+		return ""
+	case m.memFromAvail:
+		return dimSt.Render("   a used")
+	default:
+		return dimSt.Render("   a avail")
+	}
+}
+
+// memBreakdownRow is "avail 9.10 GiB · cache 4.20 GiB · buf 300.00 MiB",
+// each part left out where it was not read; false where none was.
+func (m Model) memBreakdownRow() (string, bool) {
+	var parts []string
+	for _, p := range []struct {
+		label string
+		gb    float64
+	}{{"avail", m.memAvailGB}, {"cache", m.memCachedGB}, {"buf", m.memBuffersGB}} {
+		if p.gb > 0 {
+			parts = append(parts, dimSt.Render(p.label+" ")+brightSt.Render(FormatBytes(p.gb*BytesPerGiB)))
+		}
+	}
+	if len(parts) == 0 {
+		return "", false
+	}
+	return strings.Join(parts, dimSt.Render(" · ")), true
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// The breakdown is shown under the sizes and recorded in the sample, and a
+// switches the title to the memory not available.
+func TestMemBreakdown(t *testing.T) {
+	m := sizedModel(100, 50)
+	rec := new(sinks)
+	m.rec = rec
+	if _, ok := m.memBreakdownRow(); ok {
+		t.Error("a breakdown before any was read")
+	}
+	if m = pressKeys(m, "a"); m.memFromAvail {
+		t.Error("a switched the title with nothing available to read")
+	}
+	tm, _ := m.Update(Reading{CPUTotal: 10, MemPercent: 40, MemUsedGB: 6.4, MemTotalGB: 16, MemAvailGB: 8, MemCachedGB: 4.25, MemBuffersGB: 0.25})
+	m = tm.(Model)
+	got := ansi.Strip(m.renderMemory(innerWidth(100)))
+	for _, want := range []string{"MEMORY   40.0%", "a avail", "avail 8.00 GiB · cache 4.25 GiB · buf 256.00 MiB"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in\n%s", want, got)
+		}
+	}
+	m = pressKeys(m, "a")
+	if got := ansi.Strip(m.renderMemory(innerWidth(100))); !strings.Contains(got, "MEMORY   50.0%") || !strings.Contains(got, "a used") {
+		t.Errorf("not available: got\n%s", got)
+	}
+	m.memAvailGB = 4
+	if got := m.memHeadline(); got != 75 {
+		t.Errorf("not available: got %v%%", got)
+	}
+
+	samples := rec.samples
+	if len(samples) != 1 || samples[0].MemAvailableGB != 8 || samples[0].MemCachedGB != 4.25 || samples[0].MemBuffersGB != 0.25 {
+		t.Errorf("logged: got %+v", samples)
+	}
+}
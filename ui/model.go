@@ -0,0 +1,1499 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/progress"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/ALH477/infgo/analysis"
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ring"
+)
+
+// ── Tuning constants ──────────────────────────────────────────────────────────
+
+const (
+	// DefaultInterval is the time between readings unless WithInterval
+	// says otherwise.
+	DefaultInterval = 500 * time.Millisecond
+
+	// animInterval drives the spinner and live-dot pulse; kept well below the
+	// stats interval so animations stay smooth without any extra I/O.
+	animInterval = 110 * time.Millisecond
+
+	// HistoryLen is the number of samples retained for sparkline graphs.
+	// At 500 ms per sample this represents a 19-second rolling window.
+	HistoryLen = 38
+
+	// minWidth / maxWidth are the content-width bounds used by innerWidth().
+	minInnerWidth = 68
+	maxInnerWidth = 102
+)
+
+// spinnerFrames is a 10-frame braille spinner.
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// liveDotColors cycles to produce a breathing green pulse effect.
+var liveDotColors = []lipgloss.Color{"#10b981", "#34d399", "#6ee7b7", "#34d399"}
+
+// ── Colour palette ────────────────────────────────────────────────────────────
+
+// theme is the palette every panel is drawn in, and the colours and base
+// styles below are taken from it.  They are package-level: WithTheme sets
+// them for every Model in the program, not just the one it is given to.
+var theme Theme
+
+var (
+	cViolet, cViolet2, cCyan, cGreen, cAmber, cRed lipgloss.Color
+	cGray700, cGray500, cGray50                    lipgloss.Color
+)
+
+// ── Package-level base styles ─────────────────────────────────────────────────
+//
+// These are intentionally immutable value types; every .Foreground() /
+// .Bold() call on them returns a *new* style, leaving the originals intact.
+
+var (
+	boldSt   = lipgloss.NewStyle().Bold(true)
+	dimSt    lipgloss.Style
+	brightSt lipgloss.Style
+	labelSt  lipgloss.Style
+	accentSt lipgloss.Style
+)
+
+func init() { setTheme(DefaultTheme) }
+
+// setTheme makes t the palette of every panel.
+func setTheme(t Theme) {
+	theme = t
+	cViolet, cViolet2, cCyan = t.Accent, t.AccentDark, t.Memory
+	cGreen, cAmber, cRed = t.OK, t.Warn, t.Crit
+	cGray700, cGray500, cGray50 = t.Border, t.Dim, t.Bright
+
+	dimSt = lipgloss.NewStyle().Foreground(cGray500)
+	brightSt = lipgloss.NewStyle().Foreground(cGray50)
+	labelSt = lipgloss.NewStyle().Bold(true).Foreground(cViolet)
+	accentSt = lipgloss.NewStyle().Foreground(cCyan)
+	peakSt = lipgloss.NewStyle().Foreground(cRed).Bold(true)
+	unusualSt = lipgloss.NewStyle().Foreground(cAmber)
+	tickerFade = [2]lipgloss.Color{cGray700, cGray500}
+	memGradients[1][1], memGradients[2][1] = string(cAmber), string(cRed)
+}
+
+// ── Tea messages ──────────────────────────────────────────────────────────────
+
+// animTickMsg is sent by the fast animation timer (110 ms) of the Model
+// numbered id.
+type animTickMsg struct {
+	id int
+	t  time.Time
+}
+
+// statsTickMsg is sent by the slower stats timer (500 ms).
+type statsTickMsg struct {
+	id int
+	t  time.Time
+}
+
+// Reading carries a fresh snapshot of system metrics.
+type Reading struct {
+	CPUTotal   float64   // aggregate CPU % (averaged across all cores)
+	CPUCores   []float64 // per-logical-core CPU %
+	CoreMHz    []float64 // per-core clock; nil when not read
+	Times      CPUTimes  // CPU time by mode, when hasTimes
+	HasTimes   bool
+	MemPercent float64
+	MemUsedGB  float64
+	MemTotalGB float64
+	Load1      float64
+	Load5      float64
+	Load15     float64
+
+	// memAvailGB, memCachedGB and memBuffersGB break the memory down, read
+	// with it; zero where the platform does not report them.
+	MemAvailGB, MemCachedGB, MemBuffersGB float64
+
+	// swapUsedGB, swapTotalGB and swapPercent are the use of swap space,
+	// where hasSwap: read with the memory, and not where it failed.
+	SwapUsedGB, SwapTotalGB, SwapPercent float64
+	HasSwap                              bool
+
+	// missing names the subsystems that could not be read; their fields
+	// are zero and the model keeps its previous values for them.
+	// timedOut is the part of missing whose queries ran out of time.
+	// errs has the error of each group in missing that was queried; one
+	// waiting out its backoff has none.
+	Missing, TimedOut metrics.Missing
+	Errs              map[metrics.Missing]error
+
+	// watts is the CPU packages' power since the previous reading and
+	// joules their energy since infgo started, where RAPL can be read.
+	Watts    float64
+	Joules   float64
+	HasWatts bool
+
+	Took time.Duration // how long the gopsutil round-trips took
+	At   time.Time     // when the sample was taken; zero means on receipt
+
+	// collect is how long the machine that took the reading spent on it,
+	// logged as collect_ms; 0 where unknown.  A local reading taken on a
+	// stats tick also has that tick's deadline in due, and begun is when
+	// it started: how late the collector was to it.
+	Collect    time.Duration
+	Due, Begun time.Time
+
+	// disks is the usage of the -disks filesystems that could be read,
+	// and mounts that of every filesystem, for the DISK panel; nil
+	// without it.
+	Disks  []metrics.DiskUsage
+	Mounts []metrics.DiskUsage
+
+	// io is the disks' I/O counters with -disk-io, read at ioAt; nil
+	// when they were not read.
+	IO   []IOCounter
+	IOAt time.Time
+
+	// nics is the counters of the network interfaces up, read at netAt,
+	// for the NET panel; nil when they were not read.
+	NICs  []NetCounter
+	NetAt time.Time
+
+	// retrans is the TCP segments retransmitted since boot, read at
+	// retransAt with the interfaces; zero when it was not read.
+	Retrans   uint64
+	RetransAt time.Time
+
+	// temps is the hottest sensor of each group, for the TEMP panel; nil
+	// when they were not read.
+	Temps []TempGroup
+
+	// pressure is the pressure stall information, for the PRESSURE
+	// panel; hasPressure is false when it was not read.
+	Pressure    Pressure
+	HasPressure bool
+
+	// kernel is the context switch and interrupt counters, read at
+	// kernelAt; zero when they were not read.
+	Kernel   KernelCounters
+	KernelAt time.Time
+
+	// faults is the page fault counters, read at faultsAt; zero when they
+	// were not read.
+	Faults   FaultCounters
+	FaultsAt time.Time
+}
+
+// sample converts msg into a log record stamped with ts.
+func (msg Reading) Sample(ts time.Time) metrics.Sample {
+	s := metrics.Sample{
+		TimestampUnixMs: ts.UnixMilli(),
+		CpuTotal:        msg.CPUTotal,
+		CpuCores:        msg.CPUCores,
+		MemPercent:      msg.MemPercent,
+		MemUsedGB:       msg.MemUsedGB,
+		MemTotalGB:      msg.MemTotalGB,
+		Load1:           msg.Load1,
+		Load5:           msg.Load5,
+		Load15:          msg.Load15,
+		Missing:         msg.Missing,
+		Disks:           msg.Disks,
+		SwapUsedGB:      msg.SwapUsedGB,
+		SwapTotalGB:     msg.SwapTotalGB,
+		SwapPercent:     msg.SwapPercent,
+		MemAvailableGB:  msg.MemAvailGB,
+		MemCachedGB:     msg.MemCachedGB,
+		MemBuffersGB:    msg.MemBuffersGB,
+	}
+	if msg.HasWatts {
+		w := msg.Watts
+		s.PowerWatts = &w
+	}
+	if msg.Collect > 0 {
+		ms := float64(msg.Collect) / float64(time.Millisecond)
+		s.CollectMs = &ms
+	}
+	return s
+}
+
+// SysInfo carries one-time host metadata fetched on startup.
+type SysInfo struct {
+	Hostname string
+	Platform string
+	Uptime   uint64 // seconds since boot
+	NumCores int    // 0 keeps the local runtime.NumCPU() count
+
+	// zone is the IANA name of the host's zone and utcOffset its offset
+	// east of UTC in seconds; both zero where unknown.
+	Zone      string
+	UTCOffset int
+}
+
+// ── Model ─────────────────────────────────────────────────────────────────────
+
+// model is the single source of truth for the entire TUI (Elm Architecture).
+type Model struct {
+	// Terminal geometry
+	width  int
+	height int
+	sized  bool // a WindowSizeMsg has been applied
+
+	// The latest size while a resize settles, and its number.
+	pendingW, pendingH int
+	resizeSeq          uint64
+
+	// CPU state
+	cpuTotal   float64
+	cpuPrev    float64     // reading from the previous tick; used for trend arrow
+	cpuCores   []float64   // per-core readings; may be nil before first fetch
+	coreMHz    []float64   // their clocks, shown in the grid; nil without
+	cpuTimes   CPUTimes    // the last CPU times read, to take modes from
+	modes      cpuModes    // the CPU time by mode between the last two
+	hasModes   bool        // modes holds a reading; see cputimes.go
+	cpuHistory ring.Buffer // the last HistoryLen readings
+	cpuPeak    float64     // session high-watermark
+	cpuPeakSeq uint64      // the history point holding cpuPeak; see histSeq
+
+	// Memory state
+	memPercent float64
+	memUsedGB  float64
+	memTotalGB float64
+	memPeak    float64 // session high-watermark
+	memPeakSeq uint64  // the history point holding memPeak
+	memHistory ring.Buffer
+
+	// swapUsedGB, swapTotalGB and swapPercent are the latest use of swap
+	// space; the SWAP row is hidden while swapTotalGB is zero.
+	swapUsedGB, swapTotalGB, swapPercent float64
+
+	// memAvailGB, memCachedGB and memBuffersGB are the latest breakdown of
+	// the memory, under the used and total; memFromAvail (a) has the
+	// title give the memory not available, as free -m does, in place of
+	// the memory used.  See membreakdown.go.
+	memAvailGB, memCachedGB, memBuffersGB float64
+	memFromAvail                          bool
+
+	// faults is the page faults a second, for the MEMORY panel's faults
+	// row and the samples; see vmstat.go.
+	faults FaultMeter
+
+	// HistTrail has the times of the points in cpuHistory and memHistory,
+	// and the per-core readings at them.
+	histTrail HistTrail
+
+	// histSeq numbers the points pushed to the histories, from 1.  A peak
+	// keeps its point's number rather than an index, which every push
+	// would shift; pointsSince maps it back at render time.
+	histSeq uint64
+
+	// warmSeq is the last history point read back from the capture
+	// -log-append added to, after which the sparklines draw a divider; 0
+	// for none.
+	warmSeq uint64
+
+	// Load averages (unsupported on Windows; gopsutil returns 0 gracefully)
+	load1  float64
+	load5  float64
+	load15 float64
+
+	// Host info
+	hostname string
+	platform string
+	uptime   uint64
+	numCores int // logical CPU count: runtime.NumCPU() until a reading says otherwise
+
+	// Animation counters (driven by animTick, no I/O)
+	spinFrame  int
+	liveDotIdx int
+	frameCount int
+
+	// Bubbles progress bar for memory (handles its own easing animation).
+	memProgress progress.Model
+	memLevel    int // which of memGradients the bar is drawn with
+
+	// ready is false until the first Reading arrives; prevents a blank frame.
+	ready bool
+
+	// rev counts the changes to what the panels show, and barRev the frames
+	// of the memory bar's easing; view keeps the panels rendered from them.
+	// Anything in Update that changes a panel must bump rev.
+	rev, barRev uint64
+	view        *viewCache
+
+	// missing names the panels whose last reading failed; they show their
+	// previous values, dimmed, with the age of the last good reading.
+	// timedOut is the part of missing whose queries ran out of time.
+	missing, timedOut          metrics.Missing
+	cpuSeen, memSeen, loadSeen time.Time
+
+	// rec is handed every reading, event and header for the binary's log,
+	// HTTP endpoints and alerts, and supplies the footer's badges.  A
+	// Model of its own records nothing.
+	rec Recorder
+
+	// memTrend keeps the readings the memory forecast is fitted to; nil
+	// with -forecast-window 0.  forecast is the latest, if hasForecast.
+	memTrend    *memTrend
+	forecast    analysis.Forecast
+	hasForecast bool
+
+	// cpuUsual and memUsual mark readings far above those of the last
+	// few minutes; nil with -anomaly-sigma 0.
+	cpuUsual, memUsual *anomalyTracker
+
+	// showUsers shows the USERS panel and showProcs the PROCESSES panel,
+	// of the process scans the collector sends.  users is the latest
+	// scan summed by user, of procCount processes, and procList the scan
+	// itself, which the PROCESSES panel sorts by procSort (M) as it is
+	// drawn.
+	showUsers, showProcs bool
+	users                []userUsage
+	procCount            int
+	topProc              ProcInfo // the busiest process of the scan, for the ticker
+	procList             []ProcInfo
+	procSort             procSort
+
+	// procTree shows the scan as a process tree instead (t): procRoots,
+	// of which procExpanded holds the nodes enter has expanded or
+	// collapsed, and procSel is the pid of the selected one.
+	procTree     bool
+	procRoots    []*procNode
+	procExpanded map[int32]bool
+	procSel      int32
+
+	// PiStatus is the latest reading of a Raspberry Pi's throttle flags,
+	// once piRead is set.
+	piStatus PiStatus
+	piRead   bool
+
+	// coreSecs integrates the CPU use of the session; watts, joules and
+	// hasWatts are the latest RAPL reading, and raplNote says why there
+	// is none where the counters exist but cannot be read.
+	coreSecs coreMeter
+	watts    float64
+	joules   float64
+	hasWatts bool
+	raplNote string
+
+	// disks is the latest usage of the -disks filesystems.  diskPanel
+	// shows the DISK panel, of mounts, the latest usage of each mounted
+	// filesystem; see diskpanel.go.
+	disks     []metrics.DiskUsage
+	diskPanel bool
+	mounts    []metrics.DiskUsage
+
+	// io is the DISK I/O panel's throughput, with -disk-io; nil without
+	// it.  See diskio.go.
+	io *ioMeter
+
+	// net is the NET panel's throughput; nil without the panel.  See
+	// netpanel.go.
+	net *netMeter
+
+	// temps is the latest reading of the temperature sensors, and the
+	// TEMP panel is shown while it has any; fahrenheit shows them in °F
+	// (f).  See temps.go.
+	temps      []TempGroup
+	fahrenheit bool
+
+	// pressure is the latest reading of the pressure stall information,
+	// and the PRESSURE panel is shown once there has been one.  See
+	// pressure.go.
+	pressure    Pressure
+	hasPressure bool
+
+	// cgroup is the limits of the container infgo reads, noted in the
+	// header; zero on a host and with -host.  See cgroup.go.
+	cgroup CgroupLimits
+
+	// gpus is the last GPU poll that worked; the GPU panel is shown while
+	// it has any, marked stale while the polls after it fail.  See gpu.go.
+	gpus     []metrics.GpuUsage
+	gpuStale bool
+
+	// numa is the last reading of the NUMA nodes that worked, a row each
+	// in the MEMORY panel.  See numa.go.
+	numa []NUMANode
+
+	// fans is the last reading of the fans, the SYSTEM panel's Fans row.
+	// See fans.go.
+	fans []FanReading
+
+	// throttle is what the last reading of the CPU's thermal throttling
+	// found, the CPU panel's badge and each sample's cpu_throttled.  See
+	// throttle.go.
+	throttle ThrottleState
+
+	// pids are the processes watched, each reading of which the
+	// collector sends, and shown in the PID panel.  See pidwatch.go.
+	pids []pidWatch
+
+	// kernel is the context switches and interrupts a second, for the
+	// SYSTEM panel's Kernel row; see kstat.go.
+	kernel kernelMeter
+
+	// fds is the latest reading of the file descriptors, for the FDs row
+	// while fdsRead is set; see fds.go.
+	fds     FDUsage
+	fdsRead bool
+
+	// ProcCounts is the latest count of the processes, for the Procs row
+	// while procCountsRead is set; see proccount.go.
+	procCounts     ProcCounts
+	procCountsRead bool
+
+	// ticker names the -ticker items; tickerFrame counts the animation
+	// frames it has run for, and tickerPaused holds it (p).  See ticker.go.
+	ticker       []string
+	tickerFrame  int
+	tickerPaused bool
+
+	// collapsed panels show only their headline value; see layout.go.
+	collapsed [numPanels]bool
+
+	// sched times the local stats ticks and measures their jitter, and
+	// latency how long the readings took and how late they started.
+	sched   statsSchedule
+	latency readLatency
+
+	// pending holds the readings since the last displayed one when
+	// sampling faster than MinDisplayInterval.
+	pending pendingReadings
+
+	// ctx is cancelled on quit, so readings in flight are discarded.
+	ctx  context.Context
+	stop context.CancelFunc
+
+	// collector takes the local readings; nil with a feed.
+	collector Collector
+
+	// feed replaces the collector with another machine's samples: -connect,
+	// -ssh or -replay.  nil unless one was given; the fields below are
+	// unused then.
+	feed         Feed
+	remoteInfo   bool          // host info received and applied
+	remoteErr    error         // last poll failure; nil while connected
+	retryIn      time.Duration // current reconnect backoff; 0 while connected
+	remoteSeen   time.Time     // when the last poll succeeded
+	linkRTT      time.Duration // round-trip time of the last poll
+	remoteAge    time.Duration // age of the remote's sample when fetched
+	lastRemoteMs int64         // timestamp of the last sample applied
+
+	// baseline is the -baseline capture under a -replay; nil otherwise.
+	// cpuBase and memBase are its points in step with cpuHistory and
+	// memHistory, and baseNow, if baseOK, its sample at baseAt, the time
+	// of the replay's last point.
+	baseline         *Baseline
+	cpuBase, memBase ring.Buffer
+	baseNow          metrics.Sample
+	baseOK           bool
+	baseAt           time.Time
+
+	// collectErrs counts the failures of readings that repeat; shared by
+	// the copies of the model.
+	collectErrs *ErrStreaks
+
+	// id tells this Model's ticks from those of others in the program.
+	id int
+
+	// pane draws only the panels of WithPanels, without the header and
+	// footer, for a pane of another program; fixedWidth keeps the width
+	// WithWidth gave it whatever the terminal's.
+	pane       []Panel
+	fixedWidth bool
+
+	// jobStop stops the process on ctrl+z; nil without job control.
+	jobStop tea.ExecCommand
+}
+
+// ── Commands ──────────────────────────────────────────────────────────────────
+
+func (m Model) animTick() tea.Cmd {
+	id := m.id
+	return tea.Tick(animInterval, func(t time.Time) tea.Msg {
+		return animTickMsg{id, t}
+	})
+}
+
+// statsTick fires after d; locally d comes from the model's statsSchedule.
+func (m Model) statsTick(d time.Duration) tea.Cmd {
+	id := m.id
+	return tea.Tick(d, func(t time.Time) tea.Msg {
+		return statsTickMsg{id, t}
+	})
+}
+
+// header builds the session header for a log or the HTTP endpoints.
+func (msg SysInfo) Header(started time.Time, numCores int, interval time.Duration) metrics.Header {
+	return metrics.Header{
+		Hostname:      msg.Hostname,
+		Platform:      msg.Platform,
+		StartedUnixMs: started.UnixMilli(),
+		NumCores:      int32(numCores),
+		IntervalMs:    interval.Milliseconds(),
+		Timezone:      msg.Zone,
+		UTCOffsetS:    int32(msg.UTCOffset),
+	}
+}
+
+// ── Init ──────────────────────────────────────────────────────────────────────
+
+func (m Model) Init() tea.Cmd {
+	warm := m.rec.Init(m.ctx)
+	if m.feed != nil {
+		return tea.Batch(m.feed.Fetch(), m.animTick(), warm)
+	}
+	return tea.Batch(m.fetch(time.Time{}), m.collector.Init(m.ctx), m.animTick(), m.statsTick(m.sched.interval), warm)
+}
+
+// fetch starts a local reading for the tick due then, or returns nil while
+// the previous one is still running.
+func (m Model) fetch(due time.Time) tea.Cmd {
+	return m.collector.Read(m.ctx, due)
+}
+
+// ── Update ────────────────────────────────────────────────────────────────────
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+
+	case tea.WindowSizeMsg:
+		return m.resize(msg)
+
+	case resizeMsg:
+		return m.settle(msg)
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c":
+			m.stop() // abandon a reading in flight
+			return m, tea.Quit
+		case "ctrl+z":
+			if m.jobStop != nil {
+				return m.stopJob()
+			}
+		case "1", "2", "3", "4", "5", "6", "7", "8", "9", "0", "alt+1", "alt+2":
+			for p := range numPanels {
+				if collapseKey(p) == msg.String() {
+					return m.togglePanel(p), nil
+				}
+			}
+		case "p":
+			m.tickerPaused = !m.tickerPaused && m.ticker != nil
+		case "d":
+			if m.io != nil {
+				m.io.expanded = !m.io.expanded
+				m.rev++
+			}
+		case "n":
+			if m.net != nil {
+				m.net.next()
+				m.rev++
+			}
+		case "a":
+			if m.memAvailGB > 0 {
+				m.memFromAvail = !m.memFromAvail
+				m.rev++
+			}
+		case "f":
+			if len(m.temps) > 0 || len(m.gpus) > 0 {
+				m.fahrenheit = !m.fahrenheit
+				m.rev++
+			}
+		case "M":
+			if m.showProcs {
+				m.procSort = (m.procSort + 1) % 2
+				m.rev++
+			}
+		case "t":
+			if m.showUsers {
+				m.procTree = !m.procTree
+				m.rev++
+			}
+		case "up", "k", "down", "j", "enter":
+			if m.treeShown() {
+				return m.treeKey(msg.String()), nil
+			}
+		}
+
+	case continuedMsg:
+		return m.continueJob(msg)
+
+	// Fast tick — only mutates animation counters; no I/O whatsoever.
+	case animTickMsg:
+		if msg.id != m.id {
+			return m, nil
+		}
+		m.frameCount++
+		m.spinFrame = m.frameCount % len(spinnerFrames)
+		m.liveDotIdx = (m.frameCount / 3) % len(liveDotColors)
+		if !m.tickerPaused {
+			m.tickerFrame++
+		}
+		return m, m.animTick()
+
+	// Slow tick — schedules a stats fetch goroutine for the next cycle.
+	// Remote polls reschedule themselves once they complete, so a slow
+	// link never has more than one request in flight.
+	case statsTickMsg:
+		if msg.id != m.id {
+			return m, nil
+		}
+		if m.feed != nil {
+			return m, m.feed.Fetch()
+		}
+		m.rev++ // the Sample row
+		wait := m.sched.fired(msg.t, time.Now())
+		fetch := m.fetch(m.sched.due)
+		if fetch == nil {
+			m.sched.busy++
+		}
+		return m, tea.Batch(fetch, m.statsTick(wait), m.collector.Poll(m.ctx))
+
+	case RemoteMsg:
+		return m.updateRemote(msg)
+
+	case ProcsMsg:
+		m.rev++
+		m.users, m.procCount = aggregateUsers(msg.Procs, usersShown), len(msg.Procs)
+		m.topProc = topProcess(msg.Procs)
+		if msg.Procs != nil || m.procList == nil {
+			m.procList = msg.Procs
+		}
+		m.procRoots = buildProcTree(msg.Procs)
+		forgetExited(m.procExpanded, msg.Procs)
+		return m, nil
+
+	case GPUMsg:
+		m.rev++
+		if msg.GPUs != nil {
+			m.gpus = msg.GPUs
+		}
+		m.gpuStale = msg.GPUs == nil
+		return m, nil
+
+	case NUMAMsg:
+		if msg.Nodes != nil {
+			m.rev++
+			m.numa = msg.Nodes
+		}
+		return m, nil
+
+	case FansMsg:
+		m.rev++
+		m.fans = msg.Fans
+		return m, nil
+
+	case ThrottleMsg:
+		m.rev++
+		m.throttle.Observe(msg)
+		return m, nil
+
+	case PIDsMsg:
+		m.rev++
+		for i := range m.pids {
+			if rec, ok := m.pids[i].observe(msg.Readings[i], msg.Errs[i], msg.At); ok {
+				m.rec.Process(rec)
+			}
+		}
+		return m, nil
+
+	case FDsMsg:
+		m.rev++
+		m.fds, m.fdsRead = msg.Usage, msg.OK
+		return m, nil
+
+	case ProcCountsMsg:
+		m.rev++
+		m.procCounts, m.procCountsRead = msg.Counts, msg.OK
+		return m, nil
+
+	case PiMsg:
+		return m.updatePi(msg), nil
+
+	case Warm:
+		return m.warm(msg), nil
+
+	case SampleMsg:
+		if msg.ID != m.id {
+			return m, nil
+		}
+		return m.Update(SampleReading(msg.Sample, 0))
+
+	case Reading:
+		if m.feed == nil {
+			m.latency.add(msg)
+		}
+		for _, e := range m.collectErrs.Observe(time.Now(), msg.Missing, msg.Errs) {
+			m.rec.Event(e)
+		}
+		// Nothing could be read; keep the previous readings.
+		if msg.Missing.Has(metrics.MissingAll) {
+			m.rev++
+			m.missing, m.timedOut = msg.Missing, msg.TimedOut
+			return m, nil
+		}
+		// The first local reading after a resume spans the suspend.  It is
+		// dropped, and a fresh one taken at once, primed like the first.
+		if m.collector != nil {
+			if e, slept := m.collector.Slept(time.Now()); slept {
+				m.rev++
+				m.rec.Event(e)
+				m.sched = newStatsSchedule(m.sched.interval) // the sleep is not jitter
+				m.pending = pendingReadings{}
+				return m, m.fetch(time.Time{})
+			}
+		}
+		now := msg.At
+		if now.IsZero() {
+			now = time.Now()
+		}
+		m.sched.read(now)
+		if !msg.Missing.Has(metrics.MissingCPU) {
+			// The readings, not runtime.NumCPU, say how many cores there are.
+			if n := len(msg.CPUCores); n > 0 && n != m.numCores {
+				cpus := 0
+				if m.feed == nil {
+					cpus = runtime.NumCPU()
+				}
+				m.rec.Event(CoresEvent(now, m.numCores, n, cpus))
+				m.numCores = n
+			}
+			if msg.CPUTotal > m.cpuPeak {
+				m.cpuPeak, m.cpuPeakSeq = msg.CPUTotal, m.histSeq+1
+			}
+			// A gap like the ones power.go takes for a clock jump is
+			// not counted.
+			m.coreSecs.add(msg.CPUTotal, m.numCores, now, 5*m.sched.interval)
+			m.cpuUsual.add(now, msg.CPUTotal)
+		}
+		if msg.HasWatts {
+			m.watts, m.joules, m.hasWatts = msg.Watts, msg.Joules, true
+		}
+		if !msg.Missing.Has(metrics.MissingMem) {
+			if msg.MemPercent > m.memPeak {
+				m.memPeak, m.memPeakSeq = msg.MemPercent, m.histSeq+1
+			}
+			m.memTrend.add(now, msg.MemPercent)
+			m.memUsual.add(now, msg.MemPercent)
+		}
+		if msg.HasTimes {
+			m.modes, m.hasModes = CPUBreakdown(m.cpuTimes, msg.Times)
+			m.cpuTimes = msg.Times
+		}
+		m.faults.Observe(msg.Faults, msg.FaultsAt)
+		m.record(msg, now)
+
+		// Sampling faster than MinDisplayInterval, only every few readings
+		// are shown; the ones between are folded into the histories.
+		if m.pending.add(msg); m.pending.n < displayEvery(m.sched.interval) {
+			return m, nil
+		}
+		p := m.pending
+		m.pending = pendingReadings{}
+		m.rev++
+		// A subsystem that failed keeps its previous values, which the
+		// histories repeat so that the sparklines stay in step.
+		m.missing, m.timedOut = msg.Missing, msg.TimedOut
+		if !msg.Missing.Has(metrics.MissingCPU) {
+			m.cpuPrev = m.cpuTotal
+			m.cpuTotal = msg.CPUTotal
+			m.cpuCores = msg.CPUCores
+			if msg.CoreMHz != nil {
+				m.coreMHz = msg.CoreMHz // nil while the clocks back off
+			}
+			m.cpuSeen = now
+		}
+		m.cpuHistory.Push(point(p.cpu, p.nCPU, m.cpuTotal))
+		m.cpuUsual.pushed()
+		if !msg.Missing.Has(metrics.MissingMem) {
+			m.memPercent = msg.MemPercent
+			m.memUsedGB = msg.MemUsedGB
+			m.memTotalGB = msg.MemTotalGB
+			m.memAvailGB, m.memCachedGB, m.memBuffersGB = msg.MemAvailGB, msg.MemCachedGB, msg.MemBuffersGB
+			m.memSeen = now
+		}
+		if msg.HasSwap {
+			m.swapUsedGB, m.swapTotalGB, m.swapPercent = msg.SwapUsedGB, msg.SwapTotalGB, msg.SwapPercent
+		}
+		m.memHistory.Push(point(p.mem, p.nMem, m.memPercent))
+		m.memUsual.pushed()
+		m.histTrail.Push(now, m.cpuCores)
+		m.rec.Points(m.histTrail.Samples(&m.cpuHistory, &m.memHistory, true))
+		m.pushBaseline(now)
+		m.forecast, m.hasForecast = m.memTrend.forecast()
+		m.histSeq++
+		if l := memLevel(m.memPercent); l != m.memLevel {
+			// Applied to the live bar, the option keeps its eased position.
+			m.memLevel = l
+			progress.WithGradient(memGradients[l][0], memGradients[l][1])(&m.memProgress)
+		}
+		if !msg.Missing.Has(metrics.MissingLoad) {
+			m.load1, m.load5, m.load15 = msg.Load1, msg.Load5, msg.Load15
+			m.loadSeen = now
+		}
+		m.disks = msg.Disks
+		if msg.Mounts != nil {
+			m.mounts = msg.Mounts
+		}
+		if m.io != nil {
+			m.io.observe(msg.IO, msg.IOAt)
+		}
+		if m.net != nil {
+			m.net.observe(msg.NICs, msg.NetAt)
+			m.net.retrans.observe(msg.Retrans, msg.RetransAt)
+		}
+		if msg.Temps != nil {
+			m.temps = msg.Temps
+		}
+		if msg.HasPressure {
+			m.pressure, m.hasPressure = msg.Pressure, true
+		}
+		m.kernel.observe(msg.Kernel, msg.KernelAt)
+		m.ready = true
+		// SetPercent returns a FrameMsg command that drives the easing loop.
+		return m, m.memProgress.SetPercent(m.memPercent / 100)
+	case SysInfo:
+		m.rev++
+		m.hostname = msg.Hostname
+		m.platform = msg.Platform
+		m.uptime = msg.Uptime
+		if msg.NumCores > 0 {
+			m.numCores = msg.NumCores
+		}
+		// Write the session header now that we know hostname and platform.
+		hdr := msg.Header(time.Now(), m.numCores, m.sched.interval)
+		m.rec.Header(hdr)
+		return m, nil
+
+	// Forward Bubbles frame messages so the progress bar can animate smoothly.
+	case progress.FrameMsg:
+		m.barRev++
+		pm, cmd := m.memProgress.Update(msg)
+		m.memProgress = pm.(progress.Model)
+		return m, cmd
+	}
+
+	if m.collector != nil {
+		return m, m.collector.Update(m.ctx, msg)
+	}
+	return m, nil
+}
+
+// record hands a reading to the sinks: every reading, including those the
+// panels skip when sampling faster than MinDisplayInterval.
+func (m Model) record(msg Reading, now time.Time) {
+	// Every sink is handed the same Sample.  Its CpuCores is the slice
+	// readStats got from gopsutil, which is fresh on every reading and
+	// never written again, so the sinks that keep it need no copy.
+	s := msg.Sample(now)
+	s.Gpus = m.gpus
+	s.CpuThrottled = m.throttle.Active
+	if msg.HasTimes && m.hasModes {
+		m.modes.Put(&s)
+	}
+	if !msg.FaultsAt.IsZero() && m.faults.OK {
+		m.faults.Put(&s)
+	}
+	m.rec.Sample(s, msg.Took)
+}
+
+// ── View helpers ──────────────────────────────────────────────────────────────
+
+// innerWidth returns the content width clamped to [minInnerWidth, maxInnerWidth].
+// The outer View wrapper adds 2 chars of horizontal padding on each side.
+func innerWidth(termW int) int {
+	w := termW - 4
+	if w < minInnerWidth {
+		return minInnerWidth
+	}
+	if w > maxInnerWidth {
+		return maxInnerWidth
+	}
+	return w
+}
+
+// sparkline renders the newest width readings of history as Unicode spark
+// characters.  col is the foreground colour applied to the entire rune
+// sequence.
+func sparkline(history *ring.Buffer, width int, col lipgloss.Color) string {
+	return markedSparkline(history, width, col, -1)
+}
+
+// peakSt marks a session peak on a sparkline.
+var peakSt lipgloss.Style
+
+// markedSparkline is sparkline with the reading back pushes before the
+// newest drawn in peakSt.  Nothing is marked if back is negative or the
+// reading has scrolled out of view.
+func markedSparkline(history *ring.Buffer, width int, col lipgloss.Color, back int) string {
+	return tintedSparkline(history, width, col, back, 0, -1)
+}
+
+// tintedSparkline is markedSparkline with the newest tint readings drawn
+// in unusualSt; the peak mark takes precedence.  Unless split is negative,
+// a dim divider follows the reading split pushes before the newest, in
+// place of the oldest reading shown.
+func tintedSparkline(history *ring.Buffer, width int, col lipgloss.Color, back, tint, split int) string {
+	n := history.Len()
+	start := 0
+	if n > width {
+		start = n - width
+	}
+	div := n - 1 - split
+	switch {
+	case split < 0 || div < start || div == start && n-start >= width:
+		div = -1 // nothing from before it in view
+	case n-start >= width:
+		start++ // room for the divider
+	}
+	mark := n - 1 - back
+	if back < 0 || mark < start {
+		mark = -1
+	}
+	st := lipgloss.NewStyle().Foreground(col)
+	// Runs of runes in one style, rendered as each ends.
+	var out, run strings.Builder
+	run.Grow((n - start) * 3) // every spark rune is 3 bytes of UTF-8
+	cur := st
+	flush := func() {
+		if run.Len() > 0 {
+			out.WriteString(cur.Render(run.String()))
+			run.Reset()
+		}
+	}
+	for i := start; i < n; i++ {
+		next := st
+		switch {
+		case i == mark:
+			next = peakSt
+		case i >= n-tint:
+			next = unusualSt
+		}
+		if next.GetForeground() != cur.GetForeground() || next.GetBold() != cur.GetBold() {
+			flush()
+			cur = next
+		}
+		run.WriteRune(SparkRune(history.At(i)))
+		if i == div {
+			flush()
+			out.WriteString(dimSt.Render(warmDivider))
+		}
+	}
+	flush()
+	return out.String()
+}
+
+// pointsSince is how many history points were pushed after the one numbered
+// seq, or -1 if seq is 0 (none) or is still to be pushed.
+func (m Model) pointsSince(seq uint64) int {
+	if seq == 0 || seq > m.histSeq {
+		return -1
+	}
+	return int(m.histSeq - seq)
+}
+
+// trendArrow compares two consecutive readings and returns a directional glyph.
+// A deadband of ±3 % prevents jitter on stable loads.
+func trendArrow(curr, prev float64) string {
+	delta := curr - prev
+	switch {
+	case delta > 3:
+		return lipgloss.NewStyle().Foreground(cRed).Render("▲")
+	case delta < -3:
+		return lipgloss.NewStyle().Foreground(cGreen).Render("▼")
+	default:
+		return dimSt.Render("─")
+	}
+}
+
+// formatUptime converts a seconds-since-boot value to a human-readable string.
+func formatUptime(s uint64) string {
+	d := s / 86400
+	h := (s % 86400) / 3600
+	m := (s % 3600) / 60
+	switch {
+	case d > 0:
+		return fmt.Sprintf("%dd %dh %dm", d, h, m)
+	case h > 0:
+		return fmt.Sprintf("%dh %dm", h, m)
+	default:
+		return fmt.Sprintf("%dm", m)
+	}
+}
+
+// memGradients are the memory bar's colour ramps: violet→cyan below
+// WarnPct, then amber and red ones, so that the bar agrees with the
+// percentage printed above it.
+var memGradients = [...][2]string{
+	{"#7c3aed", "#06b6d4"},
+	{"#b45309", "#f59e0b"},
+	{"#991b1b", "#ef4444"},
+}
+
+// memLevel is the index into memGradients for pct.
+func memLevel(pct float64) int {
+	switch {
+	case pct >= CritPct:
+		return 2
+	case pct >= WarnPct:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// sparkWindowSeconds returns the total seconds covered by the history buffer:
+// one point per displayed reading.
+func (m Model) sparkWindowSeconds() int {
+	interval := m.sched.interval
+	return int(interval * time.Duration(displayEvery(interval)*HistoryLen) / time.Second)
+}
+
+// ── Section renderers ─────────────────────────────────────────────────────────
+
+func (m Model) renderHeader(iw int) string {
+	spinner := lipgloss.NewStyle().Foreground(cViolet).Render(spinnerFrames[m.spinFrame])
+	title := boldSt.Copy().Foreground(cViolet).Render("INFGO")
+	dot := lipgloss.NewStyle().Foreground(liveDotColors[m.liveDotIdx]).Bold(true).Render("●")
+	liveLabel := dimSt.Render(" LIVE")
+
+	left := spinner + "  " + title
+	status := dot + liveLabel
+	if m.feed != nil {
+		status = m.renderRemoteStatus()
+	}
+	// The host gives way first, a label at a time; the title and the
+	// status stay.
+	host := segment{text: dimSt.Render(m.hostname), prio: 1, right: true, min: 8,
+		shrink: func(w int) string { return dimSt.Render(abbrevHost(m.hostname, w)) }}
+	if m.hostname == "" {
+		host.text = ""
+	}
+	// The cgroup's limits outlast the host: they change what the readings
+	// mean.
+	var cgroupSeg segment
+	if m.cgroup.Limited() {
+		cgroupSeg = segment{text: lipgloss.NewStyle().Foreground(cAmber).Render(m.cgroup.badge()), prio: 2, right: true}
+	}
+
+	// innerLen is the renderable width inside the border+padding box.
+	innerLen := iw + 2
+	line := fitLine(innerLen, "  ", []segment{
+		{text: left, prio: alwaysShown},
+		cgroupSeg,
+		host,
+		{text: status, prio: alwaysShown, right: true},
+	})
+
+	return lipgloss.NewStyle().
+		BorderStyle(lipgloss.ThickBorder()).
+		BorderForeground(cViolet2).
+		Padding(0, 1).
+		Width(iw + 4).
+		Render(line)
+}
+
+// roomForCPU is the height left for the CPU panel: the terminal's, less
+// the header, banner, footer and the other panels of the frame, each
+// after a blank line.
+func (m Model) roomForCPU(iw int) int {
+	h := m.height - lipgloss.Height(m.renderHeader(iw)) -
+		lipgloss.Height(m.banner(iw)) - lipgloss.Height(m.renderFooter(iw))
+	for _, p := range m.panels() {
+		if p != PanelCPU {
+			h -= 1 + lipgloss.Height(m.panel(p, iw))
+		}
+	}
+	return h
+}
+
+func (m Model) renderCPU(iw int) string {
+	barW := iw - 20
+	if barW < 10 {
+		barW = 10
+	}
+
+	// ── Title row ─────────────────────────────────────────────────────────
+	pctStr := m.valueStyle(metrics.MissingCPU, m.cpuTotal).
+		Render(fmt.Sprintf("%6s", FormatPercent(m.cpuTotal)))
+	titleRow := labelSt.Render("CPU") + "  " + pctStr + "  " +
+		trendArrow(m.cpuTotal, m.cpuPrev) + "   " +
+		dimSt.Render(fmt.Sprintf("peak %5s", FormatPercent(m.cpuPeak))) +
+		m.staleTag(metrics.MissingCPU, m.cpuSeen) + m.cpuUsual.badge() + m.throttle.badge()
+
+	// ── Main bar ──────────────────────────────────────────────────────────
+	bar := theme.Bar(m.cpuTotal, barW)
+
+	// ── Sparkline ─────────────────────────────────────────────────────────
+	spark := tintedSparkline(&m.cpuHistory, barW, cViolet, m.pointsSince(m.cpuPeakSeq), m.cpuUsual.tint(), m.pointsSince(m.warmSeq))
+	sparkRow := spark + "  " + dimSt.Render(fmt.Sprintf("←%ds", m.sparkWindowSeconds()))
+
+	// ── Per-core 2-column grid ────────────────────────────────────────────
+	// The grid gets the rows the rest of the frame leaves it: every core on
+	// a tall terminal, and on a shorter one as many as fit above a line
+	// counting the rest.  Without per-core readings, or room for a row and
+	// that line, the panel shows only the aggregate.
+	sections := []string{titleRow, "", bar, "", sparkRow}
+	if m.hasModes {
+		sections = []string{titleRow, "", bar, m.modes.render(), "", sparkRow}
+	}
+	if m.baseline != nil {
+		sections = append(sections, baselineRow(&m.cpuBase, barW))
+	}
+	rows := m.roomForCPU(iw) - 2 - len(sections) - 2 // borders; blank and CORES label
+	cores := m.cpuCores
+	need := (len(cores) + 1) / 2
+	if need == 0 || need > rows && rows < 2 {
+		return theme.HeatPanel(m.cpuTotal, iw+4).Render(strings.Join(sections, "\n"))
+	}
+	if need > rows {
+		cores = cores[:2*(rows-1)]
+	}
+	colW := iw/2 - 1
+	freqs := m.coreFreqs(colW)
+
+	// FIX: use PadVisual() (lipgloss.Width-aware) instead of the old
+	// padRunes() which miscounted ANSI escape bytes as visible characters.
+	var coreLines []string
+	for i := 0; i < len(cores); i += 2 {
+		lCell := coreCell(i, cores[i], freqs)
+		var rCell string
+		if i+1 < len(cores) {
+			rCell = coreCell(i+1, cores[i+1], freqs)
+		}
+		coreLines = append(coreLines, PadVisual(lCell, colW)+" "+rCell)
+	}
+	if hidden := len(m.cpuCores) - len(cores); hidden > 0 {
+		coreLines = append(coreLines,
+			dimSt.Render(fmt.Sprintf("  (+%d more cores)", hidden)))
+	}
+
+	sections = append(sections, "", dimSt.Render("CORES"))
+	sections = append(sections, coreLines...)
+	return theme.HeatPanel(m.cpuTotal, iw+4).Render(strings.Join(sections, "\n"))
+}
+
+// coreBarW is the width of each core's bar in the CPU panel's grid.
+const coreBarW = 8
+
+// coreCell is core i's cell in the grid: its number, bar and percentage,
+// and its clock when freqs has one for each core.
+func coreCell(i int, pct float64, freqs []float64) string {
+	cell := dimSt.Render(fmt.Sprintf("[%d] ", i)) +
+		theme.MiniBar(pct, coreBarW) +
+		dimSt.Render(fmt.Sprintf(" %5s", FormatPercent(pct)))
+	if freqs != nil {
+		cell += dimSt.Render(fmt.Sprintf(" %6s", fmtFreq(freqs[i])))
+	}
+	return cell
+}
+
+// coreCellW is the widest of n cores' cells without their clocks.
+func coreCellW(n int) int {
+	return len(fmt.Sprintf("[%d] ", max(n-1, 0))) + coreBarW + 6
+}
+
+// valueStyle is the style of a panel's headline value pct: coloured by
+// load, or dimmed while the reading behind it is stale.
+func (m Model) valueStyle(g metrics.Missing, pct float64) lipgloss.Style {
+	if m.missing.Has(g) {
+		return dimSt
+	}
+	return boldSt.Copy().Foreground(theme.LoadColor(pct))
+}
+
+// staleTag follows a panel's title while the last reading of g failed:
+// the age of the values shown, or a note that there are none yet, after
+// "timed out" if the query hung.
+func (m Model) staleTag(g metrics.Missing, seen time.Time) string {
+	if !m.missing.Has(g) {
+		return ""
+	}
+	tag := "stale " + m.staleAge(g, seen).String()
+	if seen.IsZero() {
+		tag = "no reading"
+	}
+	if m.timedOut.Has(g) {
+		tag = "timed out · " + tag
+	}
+	return "  " + dimSt.Render(tag)
+}
+
+// staleAge is the age, to the second, of the values shown for g while its
+// readings fail; 0 otherwise.
+func (m Model) staleAge(g metrics.Missing, seen time.Time) time.Duration {
+	if !m.missing.Has(g) || seen.IsZero() {
+		return 0
+	}
+	return time.Since(seen).Truncate(time.Second)
+}
+
+func (m Model) renderMemory(iw int) string {
+	freeGB := m.memTotalGB - m.memUsedGB
+
+	headPct := m.memHeadline()
+	pctStr := m.valueStyle(metrics.MissingMem, headPct).
+		Render(fmt.Sprintf("%6s", FormatPercent(headPct)))
+	titleRow := labelSt.Render("MEMORY") + "  " + pctStr + "   " +
+		dimSt.Render(fmt.Sprintf("peak %5s", FormatPercent(m.memPeak))) +
+		m.staleTag(metrics.MissingMem, m.memSeen) + m.memUsual.badge() + m.memHeadlineHint()
+
+	// Update width on the local copy so the bar fills the panel correctly.
+	// (This is a value receiver so the stored model is unaffected.)
+	m.memProgress.Width = iw - 2
+
+	statsRow := dimSt.Render(fmt.Sprintf(
+		"%s used  ╱  %s total  ╱  %s free",
+		FormatBytes(m.memUsedGB*BytesPerGiB), FormatBytes(m.memTotalGB*BytesPerGiB), FormatBytes(freeGB*BytesPerGiB),
+	))
+
+	sparkW := iw - 14
+	if sparkW < 5 {
+		sparkW = 5
+	}
+	spark := tintedSparkline(&m.memHistory, sparkW, cCyan, m.pointsSince(m.memPeakSeq), m.memUsual.tint(), m.pointsSince(m.warmSeq))
+	sparkRow := spark + "  " + dimSt.Render(fmt.Sprintf("←%ds", m.sparkWindowSeconds()))
+
+	rows := []string{titleRow, "", m.memProgress.View()}
+	if m.swapTotalGB > 0 {
+		rows = append(rows, m.swapRow(iw))
+	}
+	rows = append(rows, statsRow)
+	if row, ok := m.memBreakdownRow(); ok {
+		rows = append(rows, row)
+	}
+	if !m.faults.prevAt.IsZero() {
+		rows = append(rows, m.faults.text())
+	}
+	if nodes := m.numaRows(iw); nodes != nil {
+		rows = append(append(rows, ""), nodes...)
+	}
+	if m.hasForecast {
+		rows = append(rows, dimSt.Render(ansi.Truncate(ForecastText(m.forecast), iw, "…")))
+	}
+	rows = append(rows, "", sparkRow)
+	if m.baseline != nil {
+		rows = append(rows, baselineRow(&m.memBase, sparkW))
+	}
+	body := strings.Join(rows, "\n")
+	return theme.HeatPanel(m.memPercent, iw+4).Render(body)
+}
+
+// swapRow is the SWAP row under the memory bar: a thin bar of the swap
+// space used, coloured by how full it is.
+func (m Model) swapRow(iw int) string {
+	pct := lipgloss.NewStyle().Foreground(theme.LoadColor(m.swapPercent)).Render(fmt.Sprintf("%6s", FormatPercent(m.swapPercent)))
+	size := dimSt.Render(FormatBytes(m.swapUsedGB*BytesPerGiB) + " / " + FormatBytes(m.swapTotalGB*BytesPerGiB))
+	barW := max(iw-2-6-lipgloss.Width(pct)-2-lipgloss.Width(size)-2, 5)
+	return dimSt.Render("SWAP  ") + thinBar(m.swapPercent, barW) + pct + "  " + size
+}
+
+// thinBar is a one-line bar of pct, in the loadColor of pct, thinner than
+// miniBar's.
+func thinBar(pct float64, width int) string {
+	filled := min(max(int(pct/100*float64(width)+0.5), 0), width)
+	return lipgloss.NewStyle().Foreground(theme.LoadColor(pct)).Render(strings.Repeat("━", filled)) +
+		lipgloss.NewStyle().Foreground(cGray700).Render(strings.Repeat("─", width-filled))
+}
+
+func (m Model) renderSystem(w int) string {
+	rows := []struct{ k, v string }{
+		{"Host  ", m.hostname},
+		{"OS    ", m.platform},
+		{"Uptime", m.uptimeText()},
+		{"Cores ", fmt.Sprintf("%d logical", m.numCores)},
+	}
+	if m.feed == nil {
+		rows = append(rows, struct{ k, v string }{"Sample", m.sched.summary()},
+			struct{ k, v string }{"Read  ", m.latency.readText()},
+			struct{ k, v string }{"Late  ", m.latency.lateText()})
+	}
+	lines := []string{labelSt.Render("SYSTEM"), ""}
+	for _, r := range rows {
+		lines = append(lines, dimSt.Render(r.k)+"  "+brightSt.Render(r.v))
+	}
+	if m.feed == nil {
+		lines = append(lines, dimSt.Render("Energy")+"  "+ansi.Truncate(m.energyText(), w-12, "…"))
+	}
+	if m.fdsRead {
+		lines = append(lines, dimSt.Render("FDs   ")+"  "+ansi.Truncate(m.fds.render(), w-12, "…"))
+	}
+	if m.procCountsRead {
+		lines = append(lines, dimSt.Render("Procs ")+"  "+ansi.Truncate(m.procCounts.render(), w-12, "…"))
+	}
+	if !m.kernel.prevAt.IsZero() {
+		lines = append(lines, dimSt.Render("Kernel")+"  "+ansi.Truncate(m.kernel.text(), w-12, "…"))
+	}
+	if len(m.fans) > 0 {
+		lines = append(lines, dimSt.Render("Fans  ")+"  "+ansi.Truncate(m.fansText(), w-12, "…"))
+	}
+	if m.piRead {
+		lines = append(lines, dimSt.Render("Pi    ")+"  "+ansi.Truncate(m.piStatus.render(), w-12, "…"))
+	}
+	return lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(cGray700).
+		Padding(0, 2).
+		Width(w).
+		Render(strings.Join(lines, "\n"))
+}
+
+// uptimeText formats the uptime; a -connect remote does not report it.
+func (m Model) uptimeText() string {
+	if m.feed != nil {
+		return "—"
+	}
+	return formatUptime(m.uptime)
+}
+
+func (m Model) renderLoad(w int) string {
+	const lbW = 9
+	maxLoad := float64(m.numCores)
+
+	// barPct normalises a load-average value against the logical CPU count.
+	barPct := func(v float64) float64 {
+		p := v / maxLoad * 100
+		if p > 100 {
+			p = 100
+		}
+		return p
+	}
+
+	// FIX: previously wrapped theme.MiniBar() output in a redundant Foreground().Render()
+	// which double-escaped the ANSI sequences already present inside miniBar.
+	// Now we call miniBar directly.
+	row := func(label string, v float64) string {
+		pct := barPct(v)
+		num := m.valueStyle(metrics.MissingLoad, pct).Render(FormatNumber(v, 2))
+		return dimSt.Render(PadVisual(label, 3)) + "  " + theme.MiniBar(pct, lbW) + "  " + num
+	}
+
+	body := strings.Join([]string{
+		labelSt.Render("LOAD AVG") + m.staleTag(metrics.MissingLoad, m.loadSeen), "",
+		row("1m", m.load1),
+		row("5m", m.load5),
+		row("15m", m.load15),
+	}, "\n")
+
+	return lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(cGray700).
+		Padding(0, 2).
+		Width(w).
+		Render(body)
+}
+
+func (m Model) renderFooter(iw int) string {
+	quit := accentSt.Copy().Bold(true).Render("q") + dimSt.Render(" · ") +
+		accentSt.Copy().Bold(true).Render("ctrl+c") + dimSt.Render("  quit")
+	// The collapse keys, where there is room for them.
+	fold := dimSt.Render(" ") + accentSt.Copy().Bold(true).Render(m.collapseKeys()) + dimSt.Render("  collapse")
+
+	// Segments give way from the lowest prio: the collapse keys go, the
+	// badges that can shrink are cut down, then whole badges go, the least
+	// telling first.
+	segs := []segment{{text: quit, prio: alwaysShown}, {text: fold, prio: 1}}
+	for _, b := range append(m.rec.Badges(), Badge{Text: dimSt.Render(fmt.Sprintf("↺ %v", m.sched.interval)), Prio: 3}) {
+		if b.Text != "" {
+			segs = append(segs, segment{text: b.Text, prio: b.Prio, right: true, min: b.Min, shrink: b.Shrink})
+		}
+	}
+
+	totalW := iw + 4
+	footer := lipgloss.NewStyle().
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderTop(true).
+		BorderForeground(cGray700).
+		Padding(0, 1).
+		Width(totalW).
+		Render(fitLine(totalW-4, "  ", segs))
+	// The ticker keeps its line while no item has anything to show, so
+	// that the panels above do not move.
+	if m.ticker != nil {
+		footer = " " + m.renderTicker(iw+2) + "\n" + footer
+	}
+	return footer
+}
+
+// ── View ──────────────────────────────────────────────────────────────────────
+
+func (m Model) View() string {
+	if m.tooSmall() {
+		return m.renderTooSmall()
+	}
+	if !m.ready && m.feed != nil {
+		return m.renderConnecting()
+	}
+	if !m.ready {
+		sp := lipgloss.NewStyle().Foreground(cViolet).Render(spinnerFrames[m.spinFrame])
+		return "\n  " + sp + dimSt.Render("  Initialising…") + "\n"
+	}
+
+	iw := innerWidth(m.width)
+	if m.pane != nil {
+		return m.renderPane(iw)
+	}
+	panels := make([]string, 0, numPanels)
+	for _, p := range m.panels() {
+		panels = append(panels, m.panel(p, iw))
+	}
+	stale := m.feed != nil && m.remoteStale()
+	banner := m.banner(iw)
+	// A stale remote's dimmed panels are rare enough to lay out in full.
+	if m.view != nil && !stale {
+		return m.frame(m.renderHeader(iw), banner, m.renderFooter(iw))
+	}
+
+	blocks := []string{m.renderHeader(iw), banner}
+	for i, panel := range panels {
+		if i > 0 {
+			blocks = append(blocks, "")
+		}
+		if stale {
+			// Keep the last readings visible but make it obvious they are old.
+			panel = dimPanel(panel)
+		}
+		blocks = append(blocks, panel)
+	}
+	out := strings.Join(append(blocks, m.renderFooter(iw)), "\n")
+
+	return lipgloss.NewStyle().Padding(0, 1).Render(out)
+}
+
+// renderPane is the View of WithPanels: the panels alone.
+func (m Model) renderPane(iw int) string {
+	var blocks []string
+	for _, p := range m.panels() {
+		if len(blocks) > 0 {
+			blocks = append(blocks, "")
+		}
+		blocks = append(blocks, m.panel(p, iw))
+	}
+	return lipgloss.NewStyle().Padding(0, 1).Render(strings.Join(blocks, "\n"))
+}
+
+// banner is the line under the header: the reconnect notice while a
+// remote link is down, the errors of readings that fail, the comparison
+// with a -baseline, and otherwise empty.
+func (m Model) banner(iw int) string {
+	if m.feed != nil && m.remoteStale() && m.remoteErr != nil {
+		return m.renderReconnect(iw)
+	}
+	if m.collectErrs.failing() {
+		return m.collectErrs.render(iw)
+	}
+	if m.baseline != nil && !m.baseAt.IsZero() {
+		return m.renderBaselineSummary(iw)
+	}
+	return ""
+}
@@ -0,0 +1,273 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/ALH477/infgo/ring"
+)
+
+// NetCounter is an interface's counters, cumulative since it came up: of
+// bytes, and of packets in error and dropped, in and out.
+type NetCounter struct {
+	Name        string
+	Rx, Tx      uint64
+	Errs, Drops uint64
+}
+
+// netRate is an interface's throughput between two readings, in bytes a
+// second.
+type netRate struct {
+	name   string
+	rx, tx float64
+}
+
+// netMeter turns the counters of successive readings into rates, and keeps
+// their history for the sparklines.
+type netMeter struct {
+	// prev is the counters of each interface at the last reading, taken
+	// at prevAt.
+	prev   map[string]NetCounter
+	prevAt time.Time
+
+	// rates is the latest throughput of each interface that had one, and
+	// ok is set once there has been one.
+	rates []netRate
+	ok    bool
+
+	// errs and drops are the packets in error and dropped a second by all
+	// the interfaces at the latest reading; errRun and dropRun count the
+	// readings in a row each has been above zero, and errTotal and
+	// dropTotal the packets since the first reading.
+	errs, drops         float64
+	errRun, dropRun     int
+	errTotal, dropTotal uint64
+
+	// hist holds the rx and tx history of each interface, and of their
+	// sum under "", a point a displayed reading.
+	hist map[string]*netHistory
+
+	// shown is the interface the panel shows, "" for the sum (n).
+	shown string
+
+	// retrans is the TCP retransmits, for the title's badge; see snmp.go.
+	retrans retransMeter
+}
+
+type netHistory struct {
+	rx, tx ring.Buffer
+	last   netRate // the rate before the latest, for the trend arrows
+}
+
+func newNetMeter() *netMeter {
+	n := &netMeter{hist: map[string]*netHistory{}, retrans: retransMeter{warn: RetransWarn, crit: RetransCrit}}
+	n.history("")
+	return n
+}
+
+func (n *netMeter) history(name string) *netHistory {
+	h, ok := n.hist[name]
+	if !ok {
+		h = &netHistory{rx: ring.New(HistoryLen), tx: ring.New(HistoryLen)}
+		h.rx.Fill(0)
+		h.tx.Fill(0)
+		n.hist[name] = h
+	}
+	return h
+}
+
+// observe takes the counters cur read at at, nil where they were not, and
+// pushes the throughput since the previous ones; the last is repeated
+// where there is none.
+func (n *netMeter) observe(cur []NetCounter, at time.Time) {
+	if cur != nil {
+		if dt := at.Sub(n.prevAt); n.prev != nil && dt > 0 {
+			n.rates = n.rates[:0]
+			n.errs, n.drops = 0, 0
+			for _, c := range cur {
+				p, ok := n.prev[c.Name]
+				if !ok {
+					continue // back from a gap
+				}
+				rx, okRx := counterRate(p.Rx, c.Rx, dt)
+				tx, okTx := counterRate(p.Tx, c.Tx, dt)
+				if !okRx || !okTx {
+					continue // reset
+				}
+				n.rates = append(n.rates, netRate{name: c.Name, rx: rx, tx: tx})
+				if errs, ok := counterRate(p.Errs, c.Errs, dt); ok {
+					n.errs += errs
+					n.errTotal += c.Errs - p.Errs
+				}
+				if drops, ok := counterRate(p.Drops, c.Drops, dt); ok {
+					n.drops += drops
+					n.dropTotal += c.Drops - p.Drops
+				}
+			}
+			n.errRun, n.dropRun = runAbove(n.errRun, n.errs), runAbove(n.dropRun, n.drops)
+			n.ok = true
+		}
+		n.prev = make(map[string]NetCounter, len(cur))
+		for _, c := range cur {
+			n.prev[c.Name] = c
+			n.history(c.Name) // n can show it before it has a rate
+		}
+		n.prevAt = at
+	}
+	push := func(name string, r netRate) {
+		h := n.history(name)
+		if n := h.rx.Len(); n > 0 {
+			h.last = netRate{rx: h.rx.At(n - 1), tx: h.tx.At(n - 1)}
+		}
+		h.rx.Push(r.rx)
+		h.tx.Push(r.tx)
+	}
+	total := netRate{}
+	for name := range n.hist {
+		if name != "" && !slices.ContainsFunc(n.rates, func(r netRate) bool { return r.name == name }) {
+			push(name, netRate{})
+		}
+	}
+	for _, r := range n.rates {
+		total.rx += r.rx
+		total.tx += r.tx
+		push(r.name, r)
+	}
+	push("", total)
+	if n.shown != "" && !slices.Contains(n.names(), n.shown) {
+		n.shown = ""
+	}
+}
+
+// runAbove is the count of readings in a row above zero, n before one of
+// rate.
+func runAbove(n int, rate float64) int {
+	if rate > 0 {
+		return n + 1
+	}
+	return 0
+}
+
+// names are the interfaces up at the last reading.
+func (n *netMeter) names() []string {
+	names := make([]string, 0, len(n.prev))
+	for name := range n.prev {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// next shows the interface after the one shown, and the sum after the
+// last.  With one interface up, the sum is that interface, and n does
+// nothing.
+func (n *netMeter) next() {
+	names := n.names()
+	if len(names) < 2 {
+		n.shown = ""
+		return
+	}
+	i := slices.Index(names, n.shown) // -1 for the sum
+	if i+1 < len(names) {
+		n.shown = names[i+1]
+	} else {
+		n.shown = ""
+	}
+}
+
+// label names what the panel shows: the interface, or those summed.
+func (n *netMeter) label() string {
+	names := n.names()
+	switch {
+	case n.shown != "":
+		return n.shown
+	case len(names) == 0:
+		return "no interface up"
+	case len(names) == 1:
+		return names[0]
+	}
+	return fmt.Sprintf("all %d: %s", len(names), strings.Join(names, ", "))
+}
+
+// rateText is a throughput, or "—" before there is one.
+func (n *netMeter) rateText(rate float64) string {
+	if !n.ok {
+		return "—"
+	}
+	return FormatBytes(rate) + "/s"
+}
+
+func (m Model) renderNet(iw int) string {
+	n := m.net
+	title := labelSt.Render("NET") + "  " + brightSt.Render(n.label())
+	if len(n.prev) > 1 {
+		title += dimSt.Render("   n next")
+	}
+	if !n.retrans.prevAt.IsZero() {
+		title = ansi.Truncate(title+"   "+n.retrans.badge(), iw, "…")
+	}
+	h := n.hist[n.shown]
+	const valueW = 16
+	sparkW := max(iw-2-6-2-valueW, 5)
+	const mb = 1e6
+	row := func(label string, hist *ring.Buffer, prev float64, col lipgloss.Color) string {
+		now := hist.At(hist.Len() - 1)
+		value := brightSt.Render(n.rateText(now))
+		if n.ok {
+			value += " " + trendArrow(now/mb, prev/mb)
+		}
+		return dimSt.Render(PadVisual(label, 6)) + ioSparkline(hist, sparkW, col) + "  " + value
+	}
+	lines := []string{title, "",
+		row("rx", &h.rx, h.last.rx, cCyan),
+		row("tx", &h.tx, h.last.tx, cViolet),
+		dimSt.Render(PadVisual("", 6)) + ansi.Truncate(n.faultsText(), iw-6, "…"),
+	}
+	return theme.HeatPanel(0, iw+4).Render(strings.Join(lines, "\n"))
+}
+
+// netHeadline is the collapsed panel's value: the throughput summed.
+func (m Model) netHeadline() string {
+	h := m.net.hist[""]
+	last := h.rx.Len() - 1
+	return dimSt.Render("rx ") + brightSt.Render(m.net.rateText(h.rx.At(last))) + "  " +
+		dimSt.Render("tx ") + brightSt.Render(m.net.rateText(h.tx.At(last)))
+}
+
+// faultsText is the row of errors and drops, "errs 0 · drops 3.0/s", with
+// their counts since the first reading once there are any, and the TCP
+// retransmits' where they are read.
+func (n *netMeter) faultsText() string {
+	text := dimSt.Render("errs ") + faultRate(n.errs, n.errRun, n.ok) +
+		dimSt.Render(" · drops ") + faultRate(n.drops, n.dropRun, n.ok)
+	if n.errTotal > 0 || n.dropTotal > 0 || n.retrans.total > 0 {
+		since := fmt.Sprintf("   %s errs · %s drops", fmtCount(n.errTotal), fmtCount(n.dropTotal))
+		if !n.retrans.prevAt.IsZero() {
+			since += " · " + fmtCount(n.retrans.total) + " retrans"
+		}
+		text += dimSt.Render(since + " since start")
+	}
+	return text
+}
+
+// faultRate is a rate of errors or drops: "—" before there is one, a dim 0,
+// and red once it has been above zero for two readings in a row.
+func faultRate(rate float64, run int, ok bool) string {
+	switch {
+	case !ok:
+		return dimSt.Render("—")
+	case rate == 0:
+		return dimSt.Render("0")
+	case run >= 2:
+		return lipgloss.NewStyle().Foreground(cRed).Bold(true).Render(fmtRate(rate))
+	}
+	return brightSt.Render(fmtRate(rate))
+}
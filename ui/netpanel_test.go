@@ -0,0 +1,134 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// An interface that goes down and comes back, its counters from zero, has
+// no rate for the reading it is back in, and none is ever negative.
+func TestNetMeter(t *testing.T) {
+	const mb = 1 << 20
+	n := newNetMeter()
+	at := time.Unix(1700000000, 0)
+	seq := []struct {
+		dt   time.Duration
+		nics []NetCounter
+		want []netRate
+	}{
+		{0, []NetCounter{{"eth0", 0, 0, 0, 0}, {"wlan0", 5 * mb, mb, 0, 0}}, nil},
+		{time.Second, []NetCounter{{"eth0", 2 * mb, mb, 0, 0}, {"wlan0", 6 * mb, mb, 0, 0}}, []netRate{{"eth0", 2 * mb, mb}, {"wlan0", mb, 0}}},
+		{2 * time.Second, []NetCounter{{"eth0", 6 * mb, mb, 0, 0}}, []netRate{{"eth0", 2 * mb, 0}}},                // wlan0 down, late
+		{time.Second, []NetCounter{{"eth0", 6 * mb, mb, 0, 0}, {"wlan0", mb, 0, 0, 0}}, []netRate{{"eth0", 0, 0}}}, // wlan0 back from zero
+		{time.Second, []NetCounter{{"eth0", 6 * mb, mb, 0, 0}, {"wlan0", 4 * mb, mb, 0, 0}}, []netRate{{"eth0", 0, 0}, {"wlan0", 3 * mb, mb}}},
+		{time.Second, []NetCounter{{"eth0", mb, mb, 0, 0}, {"wlan0", 4 * mb, mb, 0, 0}}, []netRate{{"wlan0", 0, 0}}}, // eth0 reset
+	}
+	for i, s := range seq {
+		at = at.Add(s.dt)
+		n.observe(s.nics, at)
+		if !slices.Equal(n.rates, s.want) && (len(n.rates) > 0 || s.want != nil) {
+			t.Errorf("reading %d: got %v, want %v", i, n.rates, s.want)
+		}
+		for name, h := range n.hist {
+			if rx, tx := h.rx.At(h.rx.Len()-1), h.tx.At(h.tx.Len()-1); rx < 0 || tx < 0 {
+				t.Errorf("reading %d: %q at %v, %v", i, name, rx, tx)
+			}
+		}
+	}
+	if sum := n.hist[""]; sum.rx.At(HistoryLen-2) != 3*mb || sum.tx.At(HistoryLen-2) != mb {
+		t.Errorf("sum: got %v, %v", sum.rx.At(HistoryLen-2), sum.tx.At(HistoryLen-2))
+	}
+}
+
+// The panel shows the sum under the names of the interfaces summed, and n
+// steps through them and back to the sum.
+func TestRenderNet(t *testing.T) {
+	inColour(t)
+	m := sizedModel(100, 50)
+	m.net = newNetMeter()
+	at := time.Unix(1700000000, 0)
+	for _, rx := range []uint64{0, 4 << 20} {
+		tm, _ := m.Update(Reading{CPUTotal: 10, MemPercent: 40, NetAt: at,
+			NICs: []NetCounter{{"eth0", rx, 0, 0, 0}, {"wlan0", rx / 4, 0, 0, 0}}})
+		m = tm.(Model)
+		if got := ansi.Strip(m.panel(PanelNet, innerWidth(100))); (rx == 0) != strings.Contains(got, "—") {
+			t.Errorf("rx %d: got\n%s", rx, got)
+		}
+		at = at.Add(time.Second)
+	}
+	for _, want := range []string{"all 2: eth0, wlan0", "eth0", "wlan0", "all 2"} {
+		got := ansi.Strip(m.panel(PanelNet, innerWidth(100)))
+		if !strings.Contains(got, "NET  "+want) {
+			t.Errorf("want %q: got\n%s", want, got)
+		}
+		if want == "eth0" && !strings.Contains(got, FormatBytes(4<<20)+"/s") {
+			t.Errorf("eth0: got\n%s", got)
+		}
+		m = pressKeys(m, "n")
+	}
+}
+
+// n can show an interface from its first reading, before it has a rate.
+func TestNetNextBeforeRate(t *testing.T) {
+	m := sizedModel(100, 50)
+	m.net = newNetMeter()
+	tm, _ := m.Update(Reading{CPUTotal: 10, MemPercent: 40, NetAt: time.Unix(1700000000, 0),
+		NICs: []NetCounter{{"eth0", 0, 0, 0, 0}, {"wlan0", 0, 0, 0, 0}}})
+	m = pressKeys(tm.(Model), "n")
+	if got := ansi.Strip(m.panel(PanelNet, innerWidth(100))); !strings.Contains(got, "NET  eth0") || !strings.Contains(got, "—") {
+		t.Errorf("got\n%s", got)
+	}
+	if got := ansi.Strip(m.netHeadline()); !strings.Contains(got, "rx —") {
+		t.Errorf("headline: got %q", got)
+	}
+}
+
+// The errors and drops are summed over the interfaces a second, red only
+// from the second reading in a row above zero, and counted since the first
+// reading; an interface whose counters were reset adds nothing.
+func TestNetFaults(t *testing.T) {
+	inColour(t)
+	n := newNetMeter()
+	at := time.Unix(1700000000, 0)
+	seq := []struct {
+		nics        []NetCounter
+		errs, drops float64
+		red         bool
+		total       string
+	}{
+		{[]NetCounter{{"eth0", 0, 0, 0, 10}, {"wlan0", 0, 0, 5, 0}}, 0, 0, false, ""},
+		{[]NetCounter{{"eth0", 0, 0, 0, 13}, {"wlan0", 0, 0, 5, 0}}, 0, 3, false, "0 errs · 3 drops"},
+		{[]NetCounter{{"eth0", 0, 0, 0, 14}, {"wlan0", 0, 0, 5, 2}}, 0, 3, true, "0 errs · 6 drops"},
+		{[]NetCounter{{"eth0", 0, 0, 1, 14}, {"wlan0", 0, 0, 5, 2}}, 1, 0, false, "1 errs · 6 drops"},
+		{[]NetCounter{{"eth0", 0, 0, 0, 0}, {"wlan0", 0, 0, 5, 2}}, 0, 0, false, "1 errs · 6 drops"}, // eth0 reset
+	}
+	for i, s := range seq {
+		n.observe(s.nics, at)
+		at = at.Add(time.Second)
+		if n.errs != s.errs || n.drops != s.drops {
+			t.Errorf("reading %d: got %v errs, %v drops a second", i, n.errs, n.drops)
+		}
+		text := n.faultsText()
+		if red := strings.Contains(text, lipgloss.NewStyle().Foreground(cRed).Bold(true).Render(fmtRate(n.drops))); red != s.red {
+			t.Errorf("reading %d: red %v in %q", i, red, text)
+		}
+		if got := ansi.Strip(text); s.total != "" && !strings.Contains(got, s.total+" since start") || s.total == "" && strings.Contains(got, "since") {
+			t.Errorf("reading %d: got %q", i, got)
+		}
+	}
+
+	// The row fits the narrowest panel.
+	m := sizedModel(68, 50)
+	m.net = n
+	if got := m.panel(PanelNet, innerWidth(68)); strings.Count(got, "\n") != 6 {
+		t.Errorf("got\n%s", ansi.Strip(got))
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// NUMANode is the memory of one node.
+type NUMANode struct {
+	ID              int
+	UsedGB, TotalGB float64
+}
+
+func (n NUMANode) percent() float64 {
+	if n.TotalGB <= 0 {
+		return 0
+	}
+	return 100 * n.UsedGB / n.TotalGB
+}
+
+// NUMAMsg carries a reading of the nodes to Update; nodes is nil if it
+// failed.
+type NUMAMsg struct{ Nodes []NUMANode }
+
+// numaRows are the MEMORY panel's rows for the nodes, iw wide: none
+// unless there are two or more.
+func (m Model) numaRows(iw int) []string {
+	if len(m.numa) < 2 {
+		return nil
+	}
+	const nameW = 7 // "node 10"
+	sizes := make([]string, len(m.numa))
+	sizeW := 0
+	for i, n := range m.numa {
+		sizes[i] = FormatBytes(n.UsedGB*BytesPerGiB) + " / " + FormatBytes(n.TotalGB*BytesPerGiB)
+		sizeW = max(sizeW, lipgloss.Width(sizes[i]))
+	}
+	barW := max(iw-nameW-2-2-sizeW-1-6, 5) // the percentage, and the gaps
+	rows := make([]string, len(m.numa))
+	for i, n := range m.numa {
+		pct := n.percent()
+		rows[i] = dimSt.Render(PadVisual(fmt.Sprintf("node %d", n.ID), nameW)) + "  " +
+			theme.MiniBar(pct, barW) + "  " + dimSt.Render(PadVisual(sizes[i], sizeW)) + " " +
+			lipgloss.NewStyle().Foreground(theme.LoadColor(pct)).Render(fmt.Sprintf("%6s", FormatPercent(pct)))
+	}
+	return rows
+}
@@ -0,0 +1,40 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Each node has a row in the MEMORY panel that fits the narrowest panel.
+func TestNUMARows(t *testing.T) {
+	m := sizedModel(100, 50)
+	if rows := m.numaRows(innerWidth(100)); rows != nil {
+		t.Errorf("no nodes: got %q", rows)
+	}
+	tm, _ := m.Update(NUMAMsg{Nodes: []NUMANode{{ID: 0, UsedGB: 60, TotalGB: 64}, {ID: 1, UsedGB: 4, TotalGB: 64}}})
+	m = tm.(Model)
+	got := ansi.Strip(m.renderMemory(innerWidth(100)))
+	for _, want := range []string{"node 0", "60.00 GiB / 64.00 GiB  93.8%", "node 1", "4.00 GiB / 64.00 GiB    6.2%"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in\n%s", want, got)
+		}
+	}
+	// A failed reading leaves the rows of the last.
+	tm, _ = m.Update(NUMAMsg{})
+	if got := ansi.Strip(tm.(Model).renderMemory(innerWidth(100))); !strings.Contains(got, "node 1") {
+		t.Errorf("after a failed reading: got\n%s", got)
+	}
+	for _, termW := range []int{68, 100, 140} {
+		iw := innerWidth(termW)
+		for _, row := range m.numaRows(iw) {
+			if w := ansi.StringWidth(row); w > iw {
+				t.Errorf("%d columns: row %d wide in %d: %q", termW, w, iw, ansi.Strip(row))
+			}
+		}
+	}
+}
@@ -0,0 +1,233 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/charmbracelet/bubbles/progress"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ALH477/infgo/ring"
+)
+
+// ── Options ───────────────────────────────────────────────────────────────────
+
+// lastID numbers the Models of the program.
+var lastID atomic.Int64
+
+// Option configures a Model.
+type Option func(*Model)
+
+// New returns a Model with every panel of the infgo binary, reading this
+// machine through LocalSource every DefaultInterval, unless opts say
+// otherwise.
+func New(opts ...Option) Model {
+	p := progress.New(
+		progress.WithGradient(memGradients[0][0], memGradients[0][1]),
+		progress.WithoutPercentage(), // we render our own value
+		progress.WithWidth(50),
+	)
+	ctx, stop := context.WithCancel(context.Background())
+	m := Model{
+		id:           int(lastID.Add(1)),
+		ctx:          ctx,
+		stop:         stop,
+		rec:          nopRecorder{},
+		view:         new(viewCache),
+		collectErrs:  new(ErrStreaks),
+		width:        80,
+		height:       24,
+		cpuHistory:   ring.New(HistoryLen),
+		memHistory:   ring.New(HistoryLen),
+		histTrail:    NewHistTrail(HistoryLen),
+		numCores:     runtime.NumCPU(),
+		memProgress:  p,
+		procExpanded: map[int32]bool{},
+		sched:        newStatsSchedule(DefaultInterval),
+	}
+	m.cpuHistory.Fill(0)
+	m.memHistory.Fill(0)
+	for _, opt := range opts {
+		opt(&m)
+	}
+	if m.fixedWidth {
+		m.memProgress.Width = innerWidth(m.width) - 6
+	}
+	if m.collector == nil && m.feed == nil {
+		m.collector = sourceCollector{id: m.id, src: LocalSource(), busy: new(atomic.Bool)}
+	}
+	return m
+}
+
+// ID tells the Model's SampleMsgs from those of others in the program.
+func (m Model) ID() int { return m.id }
+
+// WithInterval reads every d.
+func WithInterval(d time.Duration) Option {
+	return func(m *Model) { m.sched = newStatsSchedule(d) }
+}
+
+// WithPanels draws only ps, one under the other, without the header and
+// footer: a pane of a program of its own.  A panel is still only drawn
+// while it has something to show.
+func WithPanels(ps ...Panel) Option {
+	return func(m *Model) { m.pane = ps }
+}
+
+// WithWidth keeps the Model w columns wide, whatever the terminal's width.
+func WithWidth(w int) Option {
+	return func(m *Model) { m.width, m.fixedWidth = w, true }
+}
+
+// WithTheme draws in t.  The palette is the package's, so it is the last
+// theme given that every Model in the program draws in.
+func WithTheme(t Theme) Option {
+	return func(*Model) { setTheme(t) }
+}
+
+// WithSource takes the readings from src, delivered as SampleMsgs.
+func WithSource(src Source) Option {
+	return func(m *Model) { m.collector = sourceCollector{id: m.id, src: src, busy: new(atomic.Bool)} }
+}
+
+// WithCollector takes the readings from c.
+func WithCollector(c Collector) Option {
+	return func(m *Model) { m.collector = c }
+}
+
+// WithFeed shows another machine's samples from f, in place of this
+// machine's.
+func WithFeed(f Feed) Option {
+	return func(m *Model) { m.feed, m.collector = f, nil }
+}
+
+// WithRecorder hands every reading, event and header to r.
+func WithRecorder(r Recorder) Option {
+	return func(m *Model) { m.rec = r }
+}
+
+// WithDiskPanel shows the DISK panel of the mounted filesystems.
+func WithDiskPanel() Option {
+	return func(m *Model) { m.diskPanel = true }
+}
+
+// WithDiskIO shows the DISK I/O panel.
+func WithDiskIO() Option {
+	return func(m *Model) { m.io = newIOMeter() }
+}
+
+// WithNetPanel shows the NET panel, its retransmits badge amber from warn
+// and red from crit segments a second.
+func WithNetPanel(warn, crit float64) Option {
+	return func(m *Model) {
+		m.net = newNetMeter()
+		m.net.retrans.warn, m.net.retrans.crit = warn, crit
+	}
+}
+
+// WithUsers shows the USERS panel.
+func WithUsers() Option {
+	return func(m *Model) { m.showUsers = true }
+}
+
+// WithProcs shows the PROCESSES panel.
+func WithProcs() Option {
+	return func(m *Model) { m.showProcs = true }
+}
+
+// WithPID adds pid to the PID panel, first read at at.  Its CPU until the
+// next reading is its average since it started.
+func WithPID(pid int32, first PIDReading, at time.Time) Option {
+	return func(m *Model) { m.pids = append(m.pids, watchPid(pid, first, at)) }
+}
+
+// WithTicker runs the footer's ticker through items; see ParseTicker.
+func WithTicker(items []string) Option {
+	return func(m *Model) { m.ticker = items }
+}
+
+// WithFahrenheit shows temperatures in °F.
+func WithFahrenheit() Option {
+	return func(m *Model) { m.fahrenheit = true }
+}
+
+// WithCollapsed starts with ps collapsed.
+func WithCollapsed(ps ...Panel) Option {
+	return func(m *Model) {
+		for _, p := range ps {
+			m.collapsed[p] = true
+		}
+	}
+}
+
+// WithForecast fits the memory readings of the last window to forecast
+// when it runs out.
+func WithForecast(window time.Duration) Option {
+	return func(m *Model) { m.memTrend = newMemTrend(window) }
+}
+
+// WithAnomalySigma marks the CPU and memory readings more than k standard
+// deviations above those of the last few minutes.
+func WithAnomalySigma(k float64) Option {
+	return func(m *Model) { m.cpuUsual, m.memUsual = newAnomalyTracker(k), newAnomalyTracker(k) }
+}
+
+// WithCgroup notes the limits of the container read in the header.
+func WithCgroup(l CgroupLimits) Option {
+	return func(m *Model) { m.cgroup = l }
+}
+
+// WithRAPLNote says in the CPU panel why there is no power reading.
+func WithRAPLNote(note string) Option {
+	return func(m *Model) { m.raplNote = note }
+}
+
+// WithBaseline lays b under the histories of a replay.
+func WithBaseline(b *Baseline) Option {
+	return func(m *Model) {
+		m.baseline = b
+		m.cpuBase, m.memBase = ring.New(HistoryLen), ring.New(HistoryLen)
+		m.cpuBase.Fill(math.NaN())
+		m.memBase.Fill(math.NaN())
+	}
+}
+
+// WithJobControl has ctrl+z hand the terminal to stop, which returns once
+// the process is continued.
+func WithJobControl(stop tea.ExecCommand) Option {
+	return func(m *Model) { m.jobStop = stop }
+}
+
+// ── Panels ────────────────────────────────────────────────────────────────────
+
+// String is the panel's name in the layout file, e.g. "memory".
+func (p Panel) String() string { return panelNames[p] }
+
+// ParsePanel is the panel named name.
+func ParsePanel(name string) (Panel, error) {
+	for p, pn := range panelNames {
+		if name == pn {
+			return Panel(p), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown panel %q (want %s)", name, strings.Join(panelNames[:], ", "))
+}
+
+// Collapsed lists the panels collapsed.
+func (m Model) Collapsed() []Panel {
+	var ps []Panel
+	for p, c := range m.collapsed {
+		if c {
+			ps = append(ps, Panel(p))
+		}
+	}
+	return ps
+}
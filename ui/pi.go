@@ -0,0 +1,120 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// PiFlags is the firmware's get_throttled word.  The low bits hold while
+// a condition is asserted; the same bits shifted by piOccurred stay set
+// from its first occurrence until the next boot.
+type PiFlags uint32
+
+const (
+	piUnderVoltage PiFlags = 1 << iota // supply below 4.63 V
+	piFreqCapped                       // ARM frequency capped
+	piThrottled                        // throttled
+	piSoftTemp                         // soft temperature limit active
+
+	piOccurred = 16
+	PiNow      = piUnderVoltage | piFreqCapped | piThrottled | piSoftTemp
+)
+
+// piConditions names the flags, in the order the status row lists them.
+var piConditions = []struct {
+	flag PiFlags
+	name string
+}{
+	{piUnderVoltage, "under-voltage"},
+	{piFreqCapped, "freq capped"},
+	{piThrottled, "throttled"},
+	{piSoftTemp, "temp limit"},
+}
+
+// PiStatus is one reading of the flags and the SoC temperature.
+type PiStatus struct {
+	Flags    PiFlags
+	HasFlags bool // neither sysfs nor vcgencmd could be read without it
+	TempC    float64
+	HasTemp  bool
+}
+
+// PiEvents returns a "throttle" event for each condition raised or
+// cleared between readings was and now.
+func PiEvents(was, now PiFlags, at time.Time) []metrics.Event {
+	var out []metrics.Event
+	for _, c := range piConditions {
+		if (was^now)&c.flag == 0 {
+			continue
+		}
+		msg := c.name + " cleared"
+		if now&c.flag != 0 {
+			msg = c.name + " asserted"
+		}
+		out = append(out, metrics.Event{TimestampUnixMs: at.UnixMilli(), Kind: "throttle", Message: msg})
+	}
+	return out
+}
+
+// PiMsg carries a reading to Update.
+type PiMsg struct {
+	Status PiStatus
+	At     time.Time
+}
+
+// updatePi applies a reading, logging the conditions it raises or clears.
+func (m Model) updatePi(msg PiMsg) Model {
+	if msg.Status.HasFlags {
+		for _, e := range PiEvents(m.piStatus.Flags&PiNow, msg.Status.Flags&PiNow, msg.At) {
+			m.rec.Event(e)
+		}
+	}
+	m.rev++
+	m.piStatus, m.piRead = msg.Status, true
+	return m
+}
+
+// render is the SYSTEM panel's Pi row: asserted conditions in red, ones
+// that have cleared since boot in amber, then the temperature.
+func (st PiStatus) render() string {
+	var parts []string
+	if st.HasFlags {
+		for _, c := range piConditions {
+			switch {
+			case st.Flags&c.flag != 0:
+				parts = append(parts, lipgloss.NewStyle().Foreground(cRed).Bold(true).Render(c.name))
+			case st.Flags&(c.flag<<piOccurred) != 0:
+				parts = append(parts, lipgloss.NewStyle().Foreground(cAmber).Render(c.name+" earlier"))
+			}
+		}
+		if len(parts) == 0 {
+			parts = append(parts, lipgloss.NewStyle().Foreground(cGreen).Render("ok"))
+		}
+	}
+	if st.HasTemp {
+		parts = append(parts, brightSt.Render(FormatNumber(st.TempC, 1)+"°C"))
+	}
+	return strings.Join(parts, dimSt.Render(" · "))
+}
+
+// tickerLine is the ticker's temp item: the SoC temperature, and the
+// conditions asserted now.
+func (st PiStatus) tickerLine() (string, bool) {
+	var parts []string
+	if st.HasTemp {
+		parts = append(parts, "SoC "+FormatNumber(st.TempC, 1)+"°C")
+	}
+	for _, c := range piConditions {
+		if st.HasFlags && st.Flags&c.flag != 0 {
+			parts = append(parts, c.name)
+		}
+	}
+	return strings.Join(parts, " · "), len(parts) > 0
+}
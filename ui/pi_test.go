@@ -0,0 +1,65 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestPiEvents(t *testing.T) {
+	at := time.Unix(1_700_000_000, 0)
+	tests := []struct {
+		was, now PiFlags
+		want     []string
+	}{
+		{0, 0, nil},
+		{0, piUnderVoltage, []string{"under-voltage asserted"}},
+		{piUnderVoltage, piUnderVoltage, nil},
+		{piUnderVoltage | piThrottled, piFreqCapped, []string{"under-voltage cleared", "freq capped asserted", "throttled cleared"}},
+	}
+	for _, tt := range tests {
+		got := PiEvents(tt.was, tt.now, at)
+		if len(got) != len(tt.want) {
+			t.Errorf("%#x → %#x: got %v, want %v", tt.was, tt.now, got, tt.want)
+			continue
+		}
+		for i, e := range got {
+			if e.Kind != "throttle" || e.Message != tt.want[i] || e.TimestampUnixMs != at.UnixMilli() {
+				t.Errorf("%#x → %#x: got %v, want %v", tt.was, tt.now, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestPiStatusRow(t *testing.T) {
+	tests := []struct {
+		st   PiStatus
+		want string
+	}{
+		{PiStatus{HasFlags: true, HasTemp: true, TempC: 48.3}, "ok · 48.3°C"},
+		{PiStatus{HasFlags: true, Flags: 0x50005}, "under-voltage · throttled"},
+		{PiStatus{HasFlags: true, Flags: 0x60004}, "freq capped earlier · throttled"},
+		{PiStatus{HasTemp: true, TempC: 61}, "61.0°C"},
+	}
+	for _, tt := range tests {
+		if got := ansi.Strip(tt.st.render()); got != tt.want {
+			t.Errorf("%+v: got %q, want %q", tt.st, got, tt.want)
+		}
+	}
+
+	// The row joins the SYSTEM panel once the first reading is in.
+	m := sizedModel(100, 40)
+	if view := ansi.Strip(m.View()); strings.Contains(view, "Pi  ") {
+		t.Errorf("before a reading: got\n%s", view)
+	}
+	m = m.updatePi(PiMsg{Status: PiStatus{HasFlags: true, Flags: 0x50005}, At: time.Now()})
+	if view := ansi.Strip(m.View()); !strings.Contains(view, "Pi      under-voltage · throttled") {
+		t.Errorf("got\n%s", view)
+	}
+}
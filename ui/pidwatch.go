@@ -0,0 +1,168 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ring"
+)
+
+// PIDReading is one reading of a process.
+type PIDReading struct {
+	Create  int64 // ms since the epoch
+	Name    string
+	CPUSecs float64 // user + system
+	RSS     uint64
+	Threads int32
+	FDs     int32 // 0 where they cannot be counted, as another user's
+}
+
+// ErrPIDGone is the reading of a process that has exited.
+var ErrPIDGone = errors.New("process exited")
+
+// pidWatch is one process given with -pid.
+type pidWatch struct {
+	pid    int32
+	create int64 // the start time it was first read with
+	name   string
+
+	// last is the latest reading, at lastAt; cpu is the percent of a core
+	// it used since the one before.
+	last   PIDReading
+	lastAt time.Time
+	cpu    float64
+
+	exited  bool
+	history ring.Buffer // cpu, capped at 100
+}
+
+// watchPid starts watching pid from its first reading r, taken at now.
+// Its CPU until the next reading is its average since it started.
+func watchPid(pid int32, r PIDReading, now time.Time) pidWatch {
+	w := pidWatch{pid: pid, create: r.Create, name: r.Name, last: r, lastAt: now, history: ring.New(HistoryLen)}
+	if age := now.Sub(time.UnixMilli(r.Create)).Seconds(); r.Create > 0 && age > 0 {
+		w.cpu = max(0, r.CPUSecs/age*100)
+	}
+	w.history.Push(min(100, w.cpu))
+	return w
+}
+
+// observe takes the reading r of w at, or the error reading it, and
+// returns the record of it for the log.  A process that is gone or has
+// started since, under the same pid, has exited; the record says so once,
+// and later readings are ignored.  A reading that failed otherwise, that
+// timed out say, is skipped, and the next one tried as usual.
+func (w *pidWatch) observe(r PIDReading, err error, at time.Time) (metrics.Process, bool) {
+	if w.exited {
+		return metrics.Process{}, false
+	}
+	if err != nil && !errors.Is(err, ErrPIDGone) {
+		return metrics.Process{}, false
+	}
+	rec := metrics.Process{TimestampUnixMs: at.UnixMilli(), Pid: w.pid, StartedUnixMs: w.create, Name: w.name}
+	if err != nil || r.Create != w.create {
+		w.exited, rec.Exited = true, true
+		return rec, true
+	}
+	if dt := at.Sub(w.lastAt).Seconds(); dt > 0 {
+		w.cpu = max(0, (r.CPUSecs-w.last.CPUSecs)/dt*100)
+	}
+	w.last, w.lastAt = r, at
+	w.history.Push(min(100, w.cpu))
+	rec.CpuPercent, rec.RssBytes = w.cpu, int64(r.RSS)
+	rec.Threads, rec.Fds = r.Threads, r.FDs
+	return rec, true
+}
+
+// PIDsMsg carries a reading of each watched process to Update, in the
+// model's order; one that has exited is not read and has ErrPIDGone.
+type PIDsMsg struct {
+	At       time.Time
+	Readings []PIDReading
+	Errs     []error
+}
+
+// renderPids is the PID panel: a row for each process and a sparkline of
+// its CPU under it.
+func (m Model) renderPids(w int) string {
+	const (
+		pidW  = 8
+		nameW = 16
+		barW  = 10
+	)
+	title := labelSt.Render("PID")
+	if len(m.pids) > 1 {
+		title += dimSt.Render(fmt.Sprintf("  %d processes", len(m.pids)))
+	}
+	lines := []string{title}
+	cores := float64(max(1, m.numCores))
+	inner := w - 6 // less the border and padding
+	sparkW := max(5, inner-pidW-len("  cpu"))
+	for i := range m.pids {
+		p := &m.pids[i]
+		name := p.name
+		if name == "" {
+			name = "?"
+		}
+		if ansi.StringWidth(name) > nameW {
+			name = ansi.Truncate(name, nameW, "…")
+		}
+		row := dimSt.Render(PadVisual(strconv.Itoa(int(p.pid)), pidW)) + brightSt.Render(PadVisual(name, nameW)) + "  "
+		if p.exited {
+			row += lipgloss.NewStyle().Foreground(cRed).Bold(true).Render("exited")
+		} else {
+			// The bar is the process's share of the whole machine.
+			share := min(100, p.cpu/cores)
+			row += PadVisual(theme.MiniBar(share, barW), barW) + "  " +
+				lipgloss.NewStyle().Foreground(theme.LoadColor(share)).Render(fmt.Sprintf("%7s", FormatPercent(p.cpu))) + "  " +
+				brightSt.Render(fmt.Sprintf("%10s", FormatBytes(float64(p.last.RSS)))) + "  " +
+				dimSt.Render(fmt.Sprintf("%d threads", p.last.Threads))
+			if p.last.FDs > 0 {
+				row += dimSt.Render(fmt.Sprintf(" · %d fds", p.last.FDs))
+			}
+		}
+		if ansi.StringWidth(row) > inner {
+			row = ansi.Truncate(row, inner, "…")
+		}
+		col := cCyan
+		if p.exited {
+			col = cGray700
+		}
+		lines = append(lines, "", row,
+			strings.Repeat(" ", pidW)+sparkline(&p.history, sparkW, col)+"  "+dimSt.Render("cpu"))
+	}
+	return lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(cGray700).
+		Padding(0, 2).
+		Width(w).
+		Render(strings.Join(lines, "\n"))
+}
+
+// pidsHeadline is the collapsed panel's value: the busiest process still
+// running, or that they have all exited.
+func (m Model) pidsHeadline() string {
+	best := -1
+	for i, p := range m.pids {
+		if !p.exited && (best < 0 || p.cpu > m.pids[best].cpu) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return lipgloss.NewStyle().Foreground(cRed).Bold(true).Render("exited")
+	}
+	p := m.pids[best]
+	share := min(100, p.cpu/float64(max(1, m.numCores)))
+	return brightSt.Render(fmt.Sprintf("%s (%d)", p.name, p.pid)) + "  " +
+		lipgloss.NewStyle().Foreground(theme.LoadColor(share)).Render(FormatPercent(p.cpu))
+}
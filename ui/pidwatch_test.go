@@ -0,0 +1,89 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// A watch measures the CPU between readings, and one that reads a process
+// started since under the same pid marks it exited, once.
+func TestPidWatch(t *testing.T) {
+	r := PIDReading{Create: 1000_000, Name: "nginx", CPUSecs: 10, RSS: 48 << 20, Threads: 9, FDs: 31}
+	at := time.UnixMilli(r.Create).Add(100 * time.Second)
+	w := watchPid(4242, r, at)
+	if w.cpu != 10 || w.name != "nginx" {
+		t.Fatalf("got %+v", w)
+	}
+
+	r.CPUSecs, at = 11, at.Add(2*time.Second)
+	rec, ok := w.observe(r, nil, at)
+	want := metrics.Process{TimestampUnixMs: at.UnixMilli(), Pid: 4242, StartedUnixMs: r.Create, Name: "nginx",
+		CpuPercent: 50, RssBytes: 48 << 20, Threads: 9, Fds: 31}
+	if !ok || rec != want {
+		t.Errorf("got %+v, want %+v", rec, want)
+	}
+
+	// A reading that fails for another reason is skipped.
+	if _, ok := w.observe(PIDReading{}, context.DeadlineExceeded, at.Add(time.Second/2)); ok || w.exited {
+		t.Errorf("timed out: got %v, exited %v", ok, w.exited)
+	}
+
+	// The pid given to a new process.
+	r.Create, at = 1200_000, at.Add(time.Second)
+	rec, ok = w.observe(r, nil, at)
+	want = metrics.Process{TimestampUnixMs: at.UnixMilli(), Pid: 4242, StartedUnixMs: 1000_000, Name: "nginx", Exited: true}
+	if !ok || rec != want || !w.exited {
+		t.Errorf("recycled: got %+v, want %+v", rec, want)
+	}
+	if _, ok := w.observe(r, nil, at.Add(time.Second)); ok || w.history.Len() != 2 {
+		t.Errorf("read after exiting: %v, %d points", ok, w.history.Len())
+	}
+}
+
+// A process that exits shows "exited" with its history, and each reading
+// reaches the Recorder as a Process record.
+func TestPidPanel(t *testing.T) {
+	r := PIDReading{Create: 1000_000, Name: "nginx", CPUSecs: 10, RSS: 48 << 20, Threads: 9, FDs: 31}
+	now := time.UnixMilli(r.Create).Add(100 * time.Second)
+	if m := sizedModel(100, 50); slices.Contains(m.panels(), PanelPID) {
+		t.Fatal("PID panel shown without -pid")
+	}
+	rec := new(sinks)
+	m := sizedModel(100, 50, WithPID(4242, r, now), WithRecorder(rec))
+
+	r.CPUSecs = 11
+	tm, _ := m.Update(PIDsMsg{At: now.Add(2 * time.Second), Readings: []PIDReading{r}, Errs: []error{nil}})
+	m = tm.(Model)
+	got := ansi.Strip(m.panel(PanelPID, innerWidth(100)))
+	for _, want := range []string{"PID", "4242", "nginx", "50.0%", "48.00 MiB", "9 threads · 31 fds"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in\n%s", want, got)
+		}
+	}
+
+	tm, _ = m.Update(PIDsMsg{At: now.Add(3 * time.Second), Readings: []PIDReading{{}}, Errs: []error{ErrPIDGone}})
+	m = tm.(Model)
+	got = ansi.Strip(m.panel(PanelPID, innerWidth(100)))
+	if !strings.Contains(got, "exited") || strings.Contains(got, "50.0%") {
+		t.Errorf("exited: got\n%s", got)
+	}
+	if spark := strings.Split(got, "\n")[4]; !strings.ContainsAny(spark, "▂▃▄▅▆▇█") {
+		t.Errorf("history lost: %q", spark)
+	}
+	if head := ansi.Strip(m.pidsHeadline()); head != "exited" {
+		t.Errorf("headline: got %q", head)
+	}
+	if len(rec.procs) != 2 || rec.procs[0].CpuPercent != 50 || !rec.procs[1].Exited {
+		t.Errorf("recorded: got %+v", rec.procs)
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// PSI is a share of time stalled, which is bad far below a load's
+// percentages: psiSomeWarn and psiSomeCrit are where some tasks stalling
+// turns a row amber and red, and psiFullWarn and psiFullCrit where all of
+// them stalling does.
+const (
+	psiSomeWarn = 10
+	psiSomeCrit = 40
+	psiFullWarn = 2
+	psiFullCrit = 10
+)
+
+// PSIAvgs are the shares of time stalled, in percent, over the last 10,
+// 60 and 300 seconds.
+type PSIAvgs struct {
+	Avg10, Avg60, Avg300 float64
+}
+
+// PSIResource is the pressure on one of the CPU, memory and I/O.
+type PSIResource struct {
+	Some PSIAvgs
+	Full PSIAvgs
+	// HasFull is false for the CPU before Linux 5.13, which had no full
+	// line for it.
+	HasFull bool
+}
+
+// Pressure is the pressure on each resource.
+type Pressure struct {
+	CPU, Memory, IO PSIResource
+}
+
+// psiLoad places a resource's pressure over the last 10 seconds on the
+// load scale, so that its warning levels are WarnPct and its critical
+// ones CritPct, for loadColor and the bars: the worse of some and full.
+func psiLoad(res PSIResource) float64 {
+	load := psiScale(res.Some.Avg10, psiSomeWarn, psiSomeCrit)
+	if res.HasFull {
+		load = max(load, psiScale(res.Full.Avg10, psiFullWarn, psiFullCrit))
+	}
+	return min(max(load, 0), 100)
+}
+
+// psiScale maps pct linearly so that warn is WarnPct and crit
+// CritPct.
+func psiScale(pct, warn, crit float64) float64 {
+	if pct <= warn {
+		return pct * WarnPct / warn
+	}
+	return WarnPct + (pct-warn)*(CritPct-WarnPct)/(crit-warn)
+}
+
+// psiRow is one of the panel's rows.
+type psiRow struct {
+	name string
+	res  PSIResource
+}
+
+// rows are the panel's rows, in its order.
+func (p Pressure) rows() []psiRow {
+	return []psiRow{{"cpu", p.CPU}, {"memory", p.Memory}, {"io", p.IO}}
+}
+
+// psiAvgsText is a, "  0.12  0.05  0.01", in columns psiColW wide.
+func psiAvgsText(a PSIAvgs) string {
+	return fmt.Sprintf("%*s%*s%*s", psiColW, FormatNumber(a.Avg10, 2), psiColW, FormatNumber(a.Avg60, 2), psiColW, FormatNumber(a.Avg300, 2))
+}
+
+// psiColW is the width of each average's column.
+const psiColW = 6
+
+func (m Model) renderPressure(iw int) string {
+	const (
+		nameW = 8
+		barW  = 10
+	)
+	avgs := fmt.Sprintf("%*s%*s%*s", psiColW, "10s", psiColW, "60s", psiColW, "300s")
+	lines := []string{
+		labelSt.Render("PRESSURE") + dimSt.Render("   % of time stalled"),
+		"",
+		dimSt.Render(strings.Repeat(" ", nameW+2+barW+2) + "some" + avgs + "  full" + avgs),
+	}
+	var worst float64
+	for _, row := range m.pressure.rows() {
+		load := psiLoad(row.res)
+		worst = max(worst, load)
+		full := fmt.Sprintf("%*s", 3*psiColW, "—")
+		if row.res.HasFull {
+			full = psiAvgsText(row.res.Full)
+		}
+		lines = append(lines, brightSt.Render(PadVisual(row.name, nameW))+"  "+
+			PadVisual(theme.MiniBar(load, barW), barW)+"  "+
+			lipgloss.NewStyle().Foreground(theme.LoadColor(load)).Render("    "+psiAvgsText(row.res.Some)+"      "+full))
+	}
+	return theme.HeatPanel(worst, iw+4).Render(strings.Join(lines, "\n"))
+}
+
+// pressureHeadline is the collapsed panel's value: the resource under the
+// most pressure, with its share of time stalled over the last 10 seconds.
+func (m Model) pressureHeadline() string {
+	rows := m.pressure.rows()
+	worst := rows[0]
+	for _, row := range rows[1:] {
+		if psiLoad(row.res) > psiLoad(worst.res) {
+			worst = row
+		}
+	}
+	load := psiLoad(worst.res)
+	text := "some " + FormatPercent(worst.res.Some.Avg10)
+	if worst.res.HasFull {
+		text += " · full " + FormatPercent(worst.res.Full.Avg10)
+	}
+	return brightSt.Render(worst.name) + "  " + lipgloss.NewStyle().Foreground(theme.LoadColor(load)).Render(text)
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// The panel appears with the first reading and shows each resource, with
+// a dash for the full line a kernel does not have; 12% of time with all
+// tasks stalled on memory is critical.
+func TestPressurePanel(t *testing.T) {
+	m := sizedModel(100, 50)
+	if slices.Contains(m.panels(), PanelPressure) {
+		t.Fatal("PRESSURE panel shown before a reading")
+	}
+	// As Linux 5.4 reads it.
+	psi := Pressure{
+		CPU:    PSIResource{Some: PSIAvgs{Avg10: 3.41, Avg60: 2.05, Avg300: 0.87}},
+		Memory: PSIResource{Some: PSIAvgs{Avg10: 18.52, Avg60: 9.31, Avg300: 3.02}, Full: PSIAvgs{Avg10: 12.07, Avg60: 6.44, Avg300: 1.95}, HasFull: true},
+		IO:     PSIResource{Some: PSIAvgs{Avg10: 0.45, Avg60: 0.30, Avg300: 0.12}, Full: PSIAvgs{Avg10: 0.20, Avg60: 0.11, Avg300: 0.04}, HasFull: true},
+	}
+	tm, _ := m.Update(Reading{Pressure: psi, HasPressure: true})
+	m = tm.(Model)
+	if !slices.Contains(m.panels(), PanelPressure) {
+		t.Fatal("no PRESSURE panel")
+	}
+	got := ansi.Strip(m.panel(PanelPressure, innerWidth(100)))
+	for _, want := range []string{"PRESSURE", "cpu", "3.41", "—", "memory", "18.52", "12.07", "io", "0.45"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in\n%s", want, got)
+		}
+	}
+	if load := psiLoad(m.pressure.Memory); load < 90 {
+		t.Errorf("memory: load %v, want critical", load)
+	}
+	if load := psiLoad(m.pressure.CPU); load >= 70 {
+		t.Errorf("cpu: load %v, want below warning", load)
+	}
+	if head := ansi.Strip(m.pressureHeadline()); head != "memory  some 18.5% · full 12.1%" {
+		t.Errorf("headline: got %q", head)
+	}
+	// Six lines at the narrowest, none wrapped.
+	if narrow := ansi.Strip(m.panel(PanelPressure, innerWidth(68))); strings.Count(narrow, "\n") != 7 {
+		t.Errorf("at 68 columns: got\n%s", narrow)
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ProcCounts is one reading of the processes.
+type ProcCounts struct {
+	Total, Zombies int
+}
+
+// ProcCountsMsg carries a reading to Update; ok is false if it failed.
+type ProcCountsMsg struct {
+	Counts ProcCounts
+	OK     bool
+}
+
+// render is the Procs row's value, "412 (3 zombie)", the zombies in amber
+// and left out where there are none.
+func (c ProcCounts) render() string {
+	text := brightSt.Render(fmtCount(uint64(c.Total)))
+	if c.Zombies > 0 {
+		text += " " + lipgloss.NewStyle().Foreground(cAmber).Render(fmt.Sprintf("(%d zombie)", c.Zombies))
+	}
+	return text
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// The zombies are in amber, and left out where there are none.
+func TestProcCountsRender(t *testing.T) {
+	inColour(t)
+	if got := (ProcCounts{Total: 1412}).render(); got != brightSt.Render("1,412") {
+		t.Errorf("no zombies: got %q", got)
+	}
+	want := brightSt.Render("412") + " " + lipgloss.NewStyle().Foreground(cAmber).Render("(3 zombie)")
+	if got := (ProcCounts{Total: 412, Zombies: 3}).render(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+// The Procs row is shown once the processes are counted, and left out
+// again when a reading fails.
+func TestProcsRow(t *testing.T) {
+	m := sizedModel(100, 50)
+	for _, tt := range []struct {
+		msg  ProcCountsMsg
+		want string
+	}{
+		{ProcCountsMsg{ProcCounts{Total: 412, Zombies: 3}, true}, "Procs   412 (3 zombie)"},
+		{ProcCountsMsg{}, ""},
+	} {
+		tm, _ := m.Update(tt.msg)
+		m = tm.(Model)
+		got := ansi.Strip(m.renderSystem(innerWidth(100) + 4))
+		if tt.want == "" {
+			if strings.Contains(got, "Procs") {
+				t.Errorf("after a failed reading: got\n%s", got)
+			}
+		} else if !strings.Contains(got, tt.want) {
+			t.Errorf("want %q: got\n%s", tt.want, got)
+		}
+	}
+}
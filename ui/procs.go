@@ -0,0 +1,137 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ── Processes panel ───────────────────────────────────────────────────────────
+
+// -procs adds a PROCESSES panel: the busiest processes by CPU, or with M
+// by resident memory, from the same scan as the USERS panel, on its
+// procInterval tick.  The model keeps the whole scan, so M sorts it again
+// at once.  A scan that runs past the tick is not queued behind, and one
+// that fails leaves the last list shown.
+
+// procsShown is how many processes the panel lists.
+const procsShown = 5
+
+// procSort is what the PROCESSES panel is sorted by.
+type procSort int
+
+const (
+	procsByCPU procSort = iota
+	procsByMem
+)
+
+func (s procSort) String() string {
+	if s == procsByMem {
+		return "memory"
+	}
+	return "CPU"
+}
+
+// topProcesses are the n busiest of procs by key: by CPU, then memory,
+// then pid, or by memory, then CPU, then pid.
+func topProcesses(procs []ProcInfo, n int, key procSort) []ProcInfo {
+	out := slices.Clone(procs)
+	slices.SortFunc(out, func(a, b ProcInfo) int {
+		byCPU, byMem := cmp.Compare(b.CPU, a.CPU), cmp.Compare(b.RSS, a.RSS)
+		if key == procsByMem {
+			byCPU, byMem = byMem, byCPU
+		}
+		if byCPU != 0 {
+			return byCPU
+		}
+		if byMem != 0 {
+			return byMem
+		}
+		return cmp.Compare(a.PID, b.PID)
+	})
+	return out[:min(n, len(out))]
+}
+
+// procName is how the panel names p: a kernel thread in brackets, as ps
+// does.
+func procName(p ProcInfo) string {
+	switch {
+	case p.Name == "":
+		return "?"
+	case p.Kernel:
+		return "[" + p.Name + "]"
+	}
+	return p.Name
+}
+
+// renderProcs is the PROCESSES panel.
+func (m Model) renderProcs(w int) string {
+	const (
+		pidW  = 7
+		nameW = 16
+		barW  = 10
+	)
+	title := labelSt.Render("PROCESSES") + "  " + accentSt.Render("by "+m.procSort.String())
+	if len(m.procList) > 0 {
+		title += dimSt.Render(fmt.Sprintf("  %d processes", len(m.procList)))
+	}
+	title += dimSt.Render("   M sort")
+	lines := []string{title, ""}
+	if m.procList == nil {
+		lines = append(lines, dimSt.Render("scanning processes…"))
+	}
+	cores := float64(max(1, m.numCores))
+	for _, p := range topProcesses(m.procList, procsShown, m.procSort) {
+		name := procName(p)
+		if ansi.StringWidth(name) > nameW {
+			name = ansi.Truncate(name, nameW, "…")
+		}
+		nameSt := brightSt
+		if p.Kernel {
+			nameSt = dimSt
+		}
+		// The bar is the process's share of the whole machine: of its
+		// cores, or of its memory.
+		share, value := min(100, p.CPU/cores), FormatPercent(p.CPU)
+		if m.procSort == procsByMem {
+			share = 0
+			if m.memTotalGB > 0 {
+				share = min(100, 100*float64(p.RSS)/(m.memTotalGB*BytesPerGiB))
+			}
+			value = FormatPercent(share)
+		}
+		lines = append(lines, dimSt.Render(PadVisual(strconv.Itoa(int(p.PID)), pidW))+
+			nameSt.Render(PadVisual(name, nameW))+"  "+
+			PadVisual(theme.MiniBar(share, barW), barW)+"  "+
+			lipgloss.NewStyle().Foreground(theme.LoadColor(share)).Render(fmt.Sprintf("%7s", value))+"  "+
+			brightSt.Render(fmt.Sprintf("%9.1f MiB", float64(p.RSS)/(1<<20))))
+	}
+	return lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(cGray700).
+		Padding(0, 2).
+		Width(w).
+		Render(strings.Join(lines, "\n"))
+}
+
+// procsHeadline is the collapsed panel's value: the top process.
+func (m Model) procsHeadline() string {
+	top := topProcesses(m.procList, 1, m.procSort)
+	if len(top) == 0 {
+		return dimSt.Render("scanning processes…")
+	}
+	if m.procSort == procsByMem {
+		return brightSt.Render(procName(top[0])) + "  " + brightSt.Render(FormatBytes(float64(top[0].RSS)))
+	}
+	share := min(100, top[0].CPU/float64(max(1, m.numCores)))
+	return brightSt.Render(procName(top[0])) + "  " +
+		lipgloss.NewStyle().Foreground(theme.LoadColor(share)).Render(FormatPercent(top[0].CPU))
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestTopProcesses(t *testing.T) {
+	procs := []ProcInfo{
+		{PID: 10, CPU: 5, RSS: 1}, {PID: 11, CPU: 80}, {PID: 12, CPU: 5, RSS: 9},
+		{PID: 13, CPU: 0}, {PID: 9, CPU: 5, RSS: 1}, {PID: 14, CPU: 30},
+	}
+	for _, tt := range []struct {
+		key  procSort
+		want []int32
+	}{
+		{procsByCPU, []int32{11, 14, 12, 9, 10}},
+		{procsByMem, []int32{12, 9, 10, 11, 14}},
+	} {
+		var got []int32
+		for _, p := range topProcesses(procs, procsShown, tt.key) {
+			got = append(got, p.PID)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("by %v: got %v, want %v", tt.key, got, tt.want)
+		}
+	}
+	if got := topProcesses(procs[:2], procsShown, procsByCPU); len(got) != 2 {
+		t.Errorf("two processes: got %d", len(got))
+	}
+}
+
+// The PROCESSES panel lists the busiest processes in aligned columns, and
+// keeps them through a scan that failed.
+func TestProcsPanel(t *testing.T) {
+	inColour(t)
+	m := sizedModel(100, 50, WithProcs())
+	if view := ansi.Strip(m.View()); !strings.Contains(view, "PROCESSES") || !strings.Contains(view, "scanning processes") {
+		t.Errorf("before the first scan: got\n%s", view)
+	}
+	var tm tea.Model = m
+	tm, _ = tm.Update(ProcsMsg{Procs: []ProcInfo{
+		{PID: 4242, Name: "chromium-renderer-process", CPU: 150, RSS: 512 << 20},
+		{PID: 7, Name: "sshd", CPU: 0.5, RSS: 6 << 20},
+		{PID: 1, Name: "", CPU: 2, RSS: 12 << 20},
+		{PID: 2, Name: "kthreadd", Kernel: true, CPU: 0.1},
+	}})
+	tm, _ = tm.Update(ProcsMsg{})
+	m = tm.(Model)
+	lines := strings.Split(ansi.Strip(m.panel(PanelProcs, innerWidth(100))), "\n")
+	var rows []string
+	for _, l := range lines {
+		if strings.Contains(l, " MiB") {
+			rows = append(rows, l)
+		}
+	}
+	if len(rows) != 4 || !strings.Contains(lines[1], "by CPU  4 processes") || !strings.Contains(rows[0], "4242") || !strings.Contains(rows[0], "chromium-render…") ||
+		!strings.Contains(rows[0], "512.0 MiB") || !strings.Contains(rows[1], "?") || !strings.Contains(rows[2], "sshd") {
+		t.Fatalf("got\n%s", strings.Join(lines, "\n"))
+	}
+	col := func(row, s string) int { return ansi.StringWidth(row[:strings.Index(row, s)]) }
+	for _, r := range rows[1:] {
+		if col(r, "%") != col(rows[0], "%") || col(r, "MiB") != col(rows[0], "MiB") {
+			t.Errorf("columns do not line up:\n%s", strings.Join(rows, "\n"))
+		}
+	}
+	if !strings.Contains(rows[3], "[kthreadd]") {
+		t.Errorf("kernel thread: got %q", rows[3])
+	}
+
+	// M sorts the same scan by memory, showing each process's share of it.
+	m.memTotalGB = 4
+	m = pressKeys(m, "M")
+	lines = strings.Split(ansi.Strip(m.panel(PanelProcs, innerWidth(100))), "\n")
+	if !strings.Contains(lines[1], "by memory") || !strings.Contains(lines[3], "4242") ||
+		!strings.Contains(lines[3], FormatPercent(12.5)) || !strings.Contains(lines[4], "?") {
+		t.Errorf("by memory: got\n%s", strings.Join(lines, "\n"))
+	}
+	if m = pressKeys(m, "M"); m.procSort != procsByCPU {
+		t.Errorf("M twice: sorted by %v", m.procSort)
+	}
+
+	// The next scan replaces the list: a process gone from it is gone.
+	tm, _ = m.Update(ProcsMsg{Procs: []ProcInfo{{PID: 7, Name: "sshd", CPU: 1, RSS: 6 << 20}}})
+	if got := ansi.Strip(tm.(Model).panel(PanelProcs, innerWidth(100))); strings.Contains(got, "4242") {
+		t.Errorf("exited process still listed:\n%s", got)
+	}
+}
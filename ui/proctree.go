@@ -0,0 +1,292 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ── Process tree ──────────────────────────────────────────────────────────────
+
+// t turns the USERS panel into a PROCESSES panel: the latest scan as a
+// tree by parent, so that the forty renderer processes of one browser sit
+// under it.  A collapsed node shows the CPU and memory of its whole
+// subtree; ↑ and ↓ select a node and enter expands or collapses it.  The
+// tree is built afresh from every scan.
+
+// procRowsShown is how many rows of the tree the panel shows, scrolled to
+// keep the selected one in view.
+const procRowsShown = 8
+
+// procNode is a process of the tree with the processes below it.
+type procNode struct {
+	ProcInfo
+	children []*procNode // busiest first
+
+	// Of the process and all below it.
+	treeCPU float64
+	treeRSS uint64
+	treeN   int
+}
+
+// buildProcTree arranges procs by parent and returns the roots, busiest
+// first.  A process whose parent is not in procs is a root: pid 1 and the
+// kernel's threads, and any whose parent exited between the reads of the
+// scan, before the process was adopted by init.  The reads of a scan are
+// not one snapshot, so a reused pid can make a loop of parents; the loop
+// is cut at the first of its processes in procs order, which becomes a
+// root.  Of two processes with one pid, the first is kept.
+func buildProcTree(procs []ProcInfo) []*procNode {
+	nodes := make(map[int32]*procNode, len(procs))
+	order := make([]*procNode, 0, len(procs))
+	for _, p := range procs {
+		if _, dup := nodes[p.PID]; dup {
+			continue
+		}
+		n := &procNode{ProcInfo: p}
+		nodes[p.PID] = n
+		order = append(order, n)
+	}
+	cut := map[int32]bool{}
+	parent := func(n *procNode) *procNode {
+		if n.PPID == n.PID || cut[n.PID] {
+			return nil
+		}
+		return nodes[n.PPID]
+	}
+
+	// Walk up from each process, marking the path, until a root or a
+	// process already walked; meeting the path again is a loop.
+	const (
+		onPath = 1
+		walked = 2
+	)
+	state := make(map[int32]uint8, len(nodes))
+	var path []*procNode
+	for _, n := range order {
+		path = path[:0]
+		cur := n
+		for cur != nil && state[cur.PID] == 0 {
+			state[cur.PID] = onPath
+			path = append(path, cur)
+			cur = parent(cur)
+		}
+		if cur != nil && state[cur.PID] == onPath {
+			cut[cur.PID] = true
+		}
+		for _, p := range path {
+			state[p.PID] = walked
+		}
+	}
+
+	var roots []*procNode
+	for _, n := range order {
+		if p := parent(n); p != nil {
+			p.children = append(p.children, n)
+		} else {
+			roots = append(roots, n)
+		}
+	}
+	for _, r := range roots {
+		sumProcTree(r)
+	}
+	sortProcNodes(roots)
+	return roots
+}
+
+// sumProcTree fills in the totals of n's subtree and sorts the children
+// of every node in it.
+func sumProcTree(n *procNode) {
+	n.treeCPU, n.treeRSS, n.treeN = n.CPU, n.RSS, 1
+	for _, c := range n.children {
+		sumProcTree(c)
+		n.treeCPU += c.treeCPU
+		n.treeRSS += c.treeRSS
+		n.treeN += c.treeN
+	}
+	sortProcNodes(n.children)
+}
+
+// sortProcNodes orders nodes by subtree CPU, then memory, then pid.
+func sortProcNodes(nodes []*procNode) {
+	sort.Slice(nodes, func(i, j int) bool {
+		a, b := nodes[i], nodes[j]
+		if a.treeCPU != b.treeCPU {
+			return a.treeCPU > b.treeCPU
+		}
+		if a.treeRSS != b.treeRSS {
+			return a.treeRSS > b.treeRSS
+		}
+		return a.PID < b.PID
+	})
+}
+
+// forgetExited drops from expanded the processes not in procs, so that a
+// long session does not keep every pid it has expanded.
+func forgetExited(expanded map[int32]bool, procs []ProcInfo) {
+	if len(expanded) == 0 {
+		return
+	}
+	alive := make(map[int32]bool, len(procs))
+	for _, p := range procs {
+		alive[p.PID] = true
+	}
+	for pid := range expanded {
+		if !alive[pid] {
+			delete(expanded, pid)
+		}
+	}
+}
+
+// procRow is a line of the tree as shown.
+type procRow struct {
+	node  *procNode
+	guide string // box-drawing indent, e.g. "│  ├─ "
+	open  bool   // showing its children
+}
+
+// flattenProcTree lists the rows of the tree under roots, each node's
+// children below it while open says it is expanded.
+func flattenProcTree(roots []*procNode, open func(n *procNode, depth int) bool) []procRow {
+	var rows []procRow
+	var walk func(nodes []*procNode, indent string, depth int)
+	walk = func(nodes []*procNode, indent string, depth int) {
+		for i, n := range nodes {
+			last := i == len(nodes)-1
+			guide, below := indent, indent
+			if depth > 0 {
+				if last {
+					guide, below = indent+"└─ ", indent+"   "
+				} else {
+					guide, below = indent+"├─ ", indent+"│  "
+				}
+			}
+			o := len(n.children) > 0 && open(n, depth)
+			rows = append(rows, procRow{node: n, guide: guide, open: o})
+			if o {
+				walk(n.children, below, depth+1)
+			}
+		}
+	}
+	walk(roots, "", 0)
+	return rows
+}
+
+// procOpen reports whether n is expanded: as enter last left it, and
+// otherwise only if it is a root.
+func (m Model) procOpen(n *procNode, depth int) bool {
+	if open, ok := m.procExpanded[n.PID]; ok {
+		return open
+	}
+	return depth == 0
+}
+
+// procRows is the tree as the panel shows it.
+func (m Model) procRows() []procRow {
+	return flattenProcTree(m.procRoots, m.procOpen)
+}
+
+// treeShown reports whether the tree is on screen, and so takes the
+// selection keys.
+func (m Model) treeShown() bool {
+	return m.showUsers && m.procTree && !m.collapsed[PanelUsers]
+}
+
+// treeKey moves the selection (up, down) or expands or collapses the
+// selected node (enter).
+func (m Model) treeKey(key string) Model {
+	rows := m.procRows()
+	if len(rows) == 0 {
+		return m
+	}
+	i := m.selectedRow(rows)
+	switch key {
+	case "up", "k":
+		i = max(0, i-1)
+	case "down", "j":
+		i = min(len(rows)-1, i+1)
+	case "enter":
+		if n := rows[i].node; len(n.children) > 0 {
+			m.procExpanded[n.PID] = !rows[i].open
+		}
+	}
+	m.procSel = rows[i].node.PID
+	m.rev++
+	return m
+}
+
+// selectedRow is the index in rows of the selected process, or the first
+// row once it has gone: exited, or folded away.
+func (m Model) selectedRow(rows []procRow) int {
+	for i, r := range rows {
+		if r.node.PID == m.procSel {
+			return i
+		}
+	}
+	return 0
+}
+
+// renderProcTree is the USERS panel in tree mode.
+func (m Model) renderProcTree(w int) string {
+	title := labelSt.Render("PROCESSES")
+	if m.procCount > 0 {
+		title += dimSt.Render(fmt.Sprintf("  %d processes", m.procCount))
+	}
+	title += dimSt.Render("   ↑↓ select  enter expand  t users")
+	lines := []string{title, ""}
+	rows := m.procRows()
+	if m.procRoots == nil {
+		lines = append(lines, dimSt.Render("scanning processes…"))
+	}
+
+	sel := m.selectedRow(rows)
+	start := max(0, sel-procRowsShown+1)
+	cores := float64(max(1, m.numCores))
+	nameW := max(8, w-4-2-33)
+	for i := start; i < min(len(rows), start+procRowsShown); i++ {
+		r := rows[i]
+		n := r.node
+		cpu, rss, count := n.CPU, n.RSS, ""
+		marker := "" // a leaf's name follows its guide
+		if r.guide == "" {
+			marker = "  " // in line with the roots that have one
+		}
+		switch {
+		case r.open:
+			marker = "▾ "
+		case len(n.children) > 0:
+			marker = "▸ "
+			cpu, rss = n.treeCPU, n.treeRSS
+			count = fmt.Sprintf("%4d procs", n.treeN)
+		}
+		name := n.Name
+		if name == "" {
+			name = "?"
+		}
+		cursor, nameSt := "  ", brightSt
+		if i == sel {
+			cursor, nameSt = labelSt.Render("› "), boldSt.Foreground(cGray50)
+		}
+		left := dimSt.Render(r.guide+marker) + nameSt.Render(name) + dimSt.Render(fmt.Sprintf(" %d", n.PID))
+		share := min(100, cpu/cores)
+		lines = append(lines, cursor+PadVisual(ansi.Truncate(left, nameW, "…"), nameW)+"  "+
+			lipgloss.NewStyle().Foreground(theme.LoadColor(share)).Render(fmt.Sprintf("%7s", FormatPercent(cpu)))+"  "+
+			brightSt.Render(fmt.Sprintf("%10s", FormatBytes(float64(rss))))+"  "+
+			dimSt.Render(fmt.Sprintf("%-10s", count)))
+	}
+	if rest := len(rows) - start - procRowsShown; rest > 0 {
+		lines = append(lines, dimSt.Render(fmt.Sprintf("  … %d more", rest)))
+	}
+	return lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(cGray700).
+		Padding(0, 2).
+		Width(w).
+		Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,183 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// treeShape writes the tree under roots as "1(100(101 102) 200) 2".
+func treeShape(roots []*procNode) string {
+	parts := make([]string, len(roots))
+	for i, n := range roots {
+		parts[i] = strconv.Itoa(int(n.PID))
+		if len(n.children) > 0 {
+			parts[i] += "(" + treeShape(n.children) + ")"
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestBuildProcTree(t *testing.T) {
+	tests := []struct {
+		name  string
+		procs []ProcInfo
+		want  string
+	}{
+		{"none", nil, ""},
+		{"by parent, busiest first", browserTable(), "1(100(101 102 103) 200(201)) 2"},
+		// The parent exited between the reads of the scan, before init
+		// adopted the process.
+		{"missing parent", append(browserTable(), proc(300, 999, "orphan", 50, 0)), "1(100(101 102 103) 200(201)) 300 2"},
+		// The next scan finds it adopted.
+		{"reparented", append(browserTable(), proc(300, 1, "orphan", 50, 0)), "1(100(101 102 103) 300 200(201)) 2"},
+		{"own parent", []ProcInfo{proc(0, 0, "idle", 0, 0), proc(5, 0, "x", 0, 0)}, "0(5)"},
+		{"loop", []ProcInfo{proc(500, 501, "a", 0, 3), proc(501, 500, "b", 0, 2), proc(502, 501, "c", 0, 1)}, "500(501(502))"},
+		{"loop below a root", []ProcInfo{proc(1, 0, "init", 0, 9), proc(600, 601, "a", 0, 2), proc(601, 602, "b", 0, 0), proc(602, 600, "c", 0, 0), proc(603, 1, "d", 0, 1)},
+			"1(603) 600(602(601))"},
+		{"pid seen twice", []ProcInfo{proc(1, 0, "init", 0, 0), proc(7, 1, "first", 0, 0), proc(7, 7, "second", 0, 0)}, "1(7)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roots := buildProcTree(tt.procs)
+			if got := treeShape(roots); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+			n := 0
+			for _, r := range roots {
+				n += r.treeN
+			}
+			seen := map[int32]bool{}
+			for _, p := range tt.procs {
+				seen[p.PID] = true
+			}
+			if n != len(seen) {
+				t.Errorf("the roots hold %d processes, want every one of %d once", n, len(seen))
+			}
+		})
+	}
+}
+
+func TestProcTreeTotals(t *testing.T) {
+	roots := buildProcTree(browserTable())
+	tests := []struct {
+		node *procNode
+		cpu  float64
+		rss  uint64
+		n    int
+	}{
+		{roots[0], 76, 735, 7},
+		{roots[0].children[0], 70, 700, 4},
+		{roots[0].children[0].children[2], 10, 200, 1},
+		{roots[0].children[1], 5, 25, 2},
+		{roots[1], 0, 0, 1},
+	}
+	for _, tt := range tests {
+		if n := tt.node; n.treeCPU != tt.cpu || n.treeRSS != tt.rss || n.treeN != tt.n {
+			t.Errorf("%d: got %g%% %d bytes of %d, want %g%% %d of %d", n.PID, n.treeCPU, n.treeRSS, n.treeN, tt.cpu, tt.rss, tt.n)
+		}
+	}
+}
+
+func TestFlattenProcTree(t *testing.T) {
+	roots := buildProcTree(browserTable())
+	all := func(*procNode, int) bool { return true }
+	var got []string
+	for _, r := range flattenProcTree(roots, all) {
+		got = append(got, r.guide+r.node.Name)
+	}
+	want := []string{
+		"init",
+		"├─ chrome",
+		"│  ├─ chrome",
+		"│  ├─ chrome",
+		"│  └─ chrome",
+		"└─ bash",
+		"   └─ vim",
+		"kthreadd",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	rootsOnly := func(_ *procNode, depth int) bool { return depth == 0 }
+	if rows := flattenProcTree(roots, rootsOnly); len(rows) != 4 || rows[1].open || !rows[0].open || rows[3].open {
+		t.Errorf("roots expanded: got %d rows", len(rows))
+	}
+}
+
+// proc is a process of a synthetic table.
+func proc(pid, ppid int32, name string, cpu float64, rss uint64) ProcInfo {
+	return ProcInfo{User: "root", CPU: cpu, RSS: rss, PID: pid, PPID: ppid, Name: name}
+}
+
+// browserTable is init with a browser of three renderers and a shell
+// under it, and the kernel's thread parent beside it.
+func browserTable() []ProcInfo {
+	return []ProcInfo{
+		proc(1, 0, "init", 1, 10),
+		proc(2, 0, "kthreadd", 0, 0),
+		proc(100, 1, "chrome", 10, 100),
+		proc(101, 100, "chrome", 30, 200),
+		proc(102, 100, "chrome", 20, 200),
+		proc(103, 100, "chrome", 10, 200),
+		proc(200, 1, "bash", 0, 5),
+		proc(201, 200, "vim", 5, 20),
+	}
+}
+
+// t shows the tree, with the browser collapsed to its subtree's total
+// until enter expands it.
+func TestProcTreePanel(t *testing.T) {
+	m := sizedModel(100, 50)
+	m.showUsers = true
+	m.numCores = 1
+	key := func(k tea.KeyMsg) {
+		t.Helper()
+		tm, _ := m.Update(k)
+		m = tm.(Model)
+	}
+	tm, _ := m.Update(ProcsMsg{browserTable()})
+	m = tm.(Model)
+	if view := ansi.Strip(m.View()); !strings.Contains(view, "USERS") || !strings.Contains(view, "t tree") {
+		t.Fatalf("before t: got\n%s", view)
+	}
+
+	key(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	view := ansi.Strip(m.View())
+	for _, want := range []string{"PROCESSES", "8 processes", "› ▾ init 1", "├─ ▸ chrome 100", "70.0%", "4 procs", "kthreadd 2"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("tree: missing %q in\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "chrome 101") {
+		t.Errorf("collapsed browser shows its renderers:\n%s", view)
+	}
+
+	key(tea.KeyMsg{Type: tea.KeyDown})
+	key(tea.KeyMsg{Type: tea.KeyEnter})
+	view = ansi.Strip(m.View())
+	for _, want := range []string{"› ├─ ▾ chrome 100", "│  ├─ chrome 101", "30.0%"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expanded: missing %q in\n%s", want, view)
+		}
+	}
+
+	// The next scan keeps the expansion and the selection.
+	tm, _ = m.Update(ProcsMsg{browserTable()})
+	m = tm.(Model)
+	if view := ansi.Strip(m.View()); !strings.Contains(view, "› ├─ ▾ chrome 100") {
+		t.Errorf("after a scan: got\n%s", view)
+	}
+
+	key(tea.KeyMsg{Type: tea.KeyEnter})
+	if view := ansi.Strip(m.View()); strings.Contains(view, "chrome 101") {
+		t.Errorf("collapsed again: got\n%s", view)
+	}
+}
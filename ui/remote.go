@@ -0,0 +1,179 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// SampleReading converts a remote's sample to the message local sampling
+// produces, keeping the remote's timestamp.
+func SampleReading(s metrics.Sample, took time.Duration) Reading {
+	msg := Reading{
+		CPUTotal:   s.CpuTotal,
+		CPUCores:   s.CpuCores,
+		MemPercent: s.MemPercent,
+		MemUsedGB:  s.MemUsedGB,
+		MemTotalGB: s.MemTotalGB,
+		Load1:      s.Load1,
+		Load5:      s.Load5,
+		Load15:     s.Load15,
+		Missing:    s.Missing,
+		Took:       took,
+		At:         s.Time(),
+		Disks:      s.Disks,
+
+		SwapUsedGB:  s.SwapUsedGB,
+		SwapTotalGB: s.SwapTotalGB,
+		SwapPercent: s.SwapPercent,
+		HasSwap:     !s.Missing.Has(metrics.MissingMem),
+
+		MemAvailGB:   s.MemAvailableGB,
+		MemCachedGB:  s.MemCachedGB,
+		MemBuffersGB: s.MemBuffersGB,
+	}
+	if s.CollectMs != nil {
+		msg.Collect = time.Duration(*s.CollectMs * float64(time.Millisecond))
+	}
+	return msg
+}
+
+// HeaderInfo is the host info carried by a remote's session header.
+func HeaderInfo(h metrics.Header) *SysInfo {
+	return &SysInfo{
+		Hostname:  h.Hostname,
+		Platform:  h.Platform,
+		NumCores:  int(h.NumCores),
+		Zone:      h.Timezone,
+		UTCOffset: int(h.UTCOffsetS),
+	}
+}
+
+// staleAfter is how old a remote's latest sample may get before its
+// panels are dimmed: several missed ticks, so one slow reading does not
+// flap them.
+const staleAfter = 5 * DefaultInterval
+
+// remoteBackoffMin / remoteBackoffMax bound the delay between
+// reconnection attempts; it doubles after every failure.
+const (
+	remoteBackoffMin = 500 * time.Millisecond
+	remoteBackoffMax = 10 * time.Second
+)
+
+// NextBackoff doubles d within [remoteBackoffMin, remoteBackoffMax].
+func NextBackoff(d time.Duration) time.Duration {
+	d *= 2
+	if d < remoteBackoffMin {
+		return remoteBackoffMin
+	}
+	if d > remoteBackoffMax {
+		return remoteBackoffMax
+	}
+	return d
+}
+
+// updateRemote applies one poll result.  Failures keep the last readings on
+// screen and retry with backoff; successes feed the ordinary SysInfo and
+// Reading paths, so -log and -listen behave as they do locally.
+func (m Model) updateRemote(msg RemoteMsg) (tea.Model, tea.Cmd) {
+	if msg.Err != nil {
+		m.remoteErr = msg.Err
+		m.retryIn = NextBackoff(m.retryIn)
+		return m, m.statsTick(m.retryIn)
+	}
+	m.remoteErr, m.retryIn = nil, 0
+	m.remoteSeen = time.Now()
+	m.linkRTT, m.remoteAge = msg.RTT, msg.Age
+
+	// Samples are only applied once the header is known, so a -log capture
+	// always starts with it.
+	cmds := []tea.Cmd{m.statsTick(DefaultInterval)}
+	if msg.Info != nil && !m.remoteInfo {
+		m.remoteInfo = true
+		next, cmd := m.Update(*msg.Info)
+		m = next.(Model)
+		cmds = append(cmds, cmd)
+	}
+	// Polling is not synchronised with the remote's sampling, so the same
+	// sample can be fetched twice; apply it once.
+	if ts := msg.Reading.At.UnixMilli(); m.remoteInfo && ts != m.lastRemoteMs {
+		m.lastRemoteMs = ts
+		next, cmd := m.Update(msg.Reading)
+		m = next.(Model)
+		cmds = append(cmds, cmd)
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// remoteStale reports whether the panels show data that can no longer be
+// trusted: the link is down or the remote's own sampling has stalled.
+func (m Model) remoteStale() bool {
+	return m.remoteErr != nil || m.remoteAge > staleAfter
+}
+
+// renderRemoteStatus replaces the header's LIVE badge in -connect mode with
+// the link latency, or the reason the data is stale.
+func (m Model) renderRemoteStatus() string {
+	if r, ok := m.feed.(Replay); ok {
+		return m.renderReplayStatus(r.Progress())
+	}
+	switch {
+	case m.remoteErr != nil:
+		return lipgloss.NewStyle().Foreground(cRed).Bold(true).Render("●") + dimSt.Render(" OFFLINE")
+	case m.remoteAge > staleAfter:
+		return lipgloss.NewStyle().Foreground(cAmber).Bold(true).Render("●") +
+			dimSt.Render(fmt.Sprintf(" STALE %.0fs", m.remoteAge.Seconds()))
+	default:
+		dot := lipgloss.NewStyle().Foreground(liveDotColors[m.liveDotIdx]).Bold(true).Render("●")
+		return dot + dimSt.Render(fmt.Sprintf(" %s ⇄ %dms", m.feed.Name(), m.linkRTT.Milliseconds()))
+	}
+}
+
+// renderReplayStatus replaces the header's LIVE badge under a Replay: how
+// far it has got.
+func (m Model) renderReplayStatus(played, total int) string {
+	mark, label := lipgloss.NewStyle().Foreground(cGreen).Bold(true).Render("▶"), " REPLAY"
+	if played == total {
+		mark, label = lipgloss.NewStyle().Foreground(cGray500).Bold(true).Render("■"), " END"
+	}
+	return mark + dimSt.Render(fmt.Sprintf("%s %d/%d", label, played, total))
+}
+
+// renderReconnect is the banner shown under the header while the link is
+// down.
+func (m Model) renderReconnect(iw int) string {
+	msg := fmt.Sprintf("⟳ reconnecting… retry in %s", m.retryIn)
+	reason := ansi.Strip(m.remoteErr.Error())
+	if room := iw - lipgloss.Width(msg) - 3; room > 10 {
+		if lipgloss.Width(reason) > room {
+			reason = ansi.Truncate(reason, room-1, "…")
+		}
+		msg += "  " + reason
+	}
+	return lipgloss.NewStyle().Foreground(cAmber).Padding(0, 1).Render(msg)
+}
+
+// renderConnecting is the whole screen until the first remote sample.
+func (m Model) renderConnecting() string {
+	sp := lipgloss.NewStyle().Foreground(cViolet).Render(spinnerFrames[m.spinFrame])
+	out := "\n  " + sp + dimSt.Render("  Connecting to "+m.feed.Target()+"…") + "\n"
+	if m.remoteErr != nil {
+		out += "\n  " + lipgloss.NewStyle().Foreground(cAmber).Render(m.remoteErr.Error()) +
+			dimSt.Render(fmt.Sprintf("  (retry in %s)", m.retryIn)) + "\n"
+	}
+	return out
+}
+
+// dimPanel renders a panel in the muted style used while data is stale.
+func dimPanel(s string) string {
+	return dimSt.Render(ansi.Strip(s))
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNextBackoff(t *testing.T) {
+	var d time.Duration
+	var got []time.Duration
+	for i := 0; i < 7; i++ {
+		d = NextBackoff(d)
+		got = append(got, d)
+	}
+	want := []time.Duration{
+		500 * time.Millisecond, time.Second, 2 * time.Second, 4 * time.Second,
+		8 * time.Second, 10 * time.Second, 10 * time.Second,
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("backoff %d: got %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestUpdateRemote(t *testing.T) {
+	rec := new(sinks)
+	m := New(WithFeed(stubFeed("node7:9804")), WithRecorder(rec))
+
+	sample := func(ts int64, cpu float64) RemoteMsg {
+		cores := make([]float64, 16)
+		for i := range cores {
+			cores[i] = cpu
+		}
+		return RemoteMsg{
+			Reading: Reading{CPUTotal: cpu, CPUCores: cores, At: time.UnixMilli(ts)},
+			Info:    &SysInfo{Hostname: "node7", NumCores: 16},
+			RTT:     3 * time.Millisecond,
+		}
+	}
+	apply := func(msg RemoteMsg) {
+		next, _ := m.Update(msg)
+		m = next.(Model)
+	}
+
+	apply(sample(1000, 10))
+	apply(sample(1000, 10)) // polled twice before the remote sampled again
+	apply(sample(1500, 20))
+	if m.hostname != "node7" || m.numCores != 16 {
+		t.Errorf("host info: got %q with %d cores", m.hostname, m.numCores)
+	}
+	if m.cpuTotal != 20 || m.cpuPrev != 10 {
+		t.Errorf("cpu: got %v (prev %v), want 20 (prev 10)", m.cpuTotal, m.cpuPrev)
+	}
+
+	// Losing the link keeps the last readings and backs off.
+	apply(RemoteMsg{Err: errors.New("connection refused")})
+	apply(RemoteMsg{Err: errors.New("connection refused")})
+	if m.cpuTotal != 20 || !m.remoteStale() || m.retryIn != time.Second {
+		t.Errorf("while offline: cpu %v, stale %v, retryIn %v", m.cpuTotal, m.remoteStale(), m.retryIn)
+	}
+	if view := m.View(); !strings.Contains(view, "reconnecting") || !strings.Contains(view, "OFFLINE") {
+		t.Errorf("offline view lacks the reconnecting banner:\n%s", view)
+	}
+
+	apply(sample(2000, 30))
+	if m.remoteStale() || m.retryIn != 0 {
+		t.Errorf("after reconnect: stale %v, retryIn %v", m.remoteStale(), m.retryIn)
+	}
+
+	// The recorder gets one header and each distinct remote sample, with
+	// the remote's timestamps.
+	var stamps []int64
+	for _, s := range rec.samples {
+		stamps = append(stamps, s.TimestampUnixMs)
+	}
+	if len(rec.headers) != 1 || len(stamps) != 3 || stamps[0] != 1000 || stamps[2] != 2000 {
+		t.Errorf("got %d headers and samples %v, want 1 header and [1000 1500 2000]", len(rec.headers), stamps)
+	}
+}
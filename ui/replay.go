@@ -0,0 +1,137 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/ALH477/infgo/analysis"
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ring"
+)
+
+// Baseline is a -baseline capture resampled onto the timeline of the
+// replay.
+type Baseline struct {
+	name    string
+	start   time.Time        // of the replay, which the baseline's start is moved to
+	buckets []metrics.Sample // by time, one per replay interval that has samples
+	hold    int64            // ms a bucket stands for, where the baseline has none after it
+}
+
+// NewBaseline moves base, read from path, to start when primary does
+// and resamples it at primary's interval.  A baseline sampled more often
+// is averaged into the replay's intervals; one sampled less often holds
+// each of its samples until the next, but not across a gap in it such as a
+// suspend.
+func NewBaseline(path string, primary, base *analysis.Capture) (*Baseline, error) {
+	if len(base.Samples) == 0 {
+		return nil, fmt.Errorf("-baseline: %s has no samples", path)
+	}
+	every := analysis.CaptureInterval(primary)
+	if every < time.Millisecond {
+		every = DefaultInterval
+	}
+	start := primary.Samples[0].TimestampUnixMs
+	shift := start - base.Samples[0].TimestampUnixMs
+	moved := make([]metrics.Sample, len(base.Samples))
+	for i, s := range base.Samples {
+		s.TimestampUnixMs += shift
+		moved[i] = s
+	}
+	buckets, err := metrics.Downsample(moved, every, metrics.AggMean)
+	if err != nil {
+		return nil, fmt.Errorf("-baseline: %w", err)
+	}
+	hold := max(every, analysis.CaptureInterval(base))
+	return &Baseline{name: filepath.Base(path), start: time.UnixMilli(start), buckets: buckets, hold: 2 * hold.Milliseconds()}, nil
+}
+
+// at is the baseline at the replay's time t: the last bucket begun by
+// then, unless t is past the end of the baseline or in a gap in it.
+func (b *Baseline) at(t time.Time) (metrics.Sample, bool) {
+	ms := t.UnixMilli()
+	i := sort.Search(len(b.buckets), func(i int) bool { return b.buckets[i].TimestampUnixMs > ms }) - 1
+	if i < 0 || ms >= b.buckets[i].TimestampUnixMs+b.hold {
+		return metrics.Sample{}, false
+	}
+	return b.buckets[i], true
+}
+
+// end is how far into the replay the baseline reaches.
+func (b *Baseline) end() time.Duration {
+	last := b.buckets[len(b.buckets)-1].TimestampUnixMs + b.hold/2
+	return time.UnixMilli(last).Sub(b.start)
+}
+
+// pushBaseline adds the baseline at now to its histories, in step with the
+// replay's, NaN where it has no sample.
+func (m *Model) pushBaseline(now time.Time) {
+	if m.baseline == nil {
+		return
+	}
+	m.baseAt = now
+	m.baseNow, m.baseOK = m.baseline.at(now)
+	cpu, mem := math.NaN(), math.NaN()
+	if m.baseOK {
+		cpu, mem = m.baseNow.CpuTotal, m.baseNow.MemPercent
+	}
+	m.cpuBase.Push(cpu)
+	m.memBase.Push(mem)
+}
+
+// baselineRow is the dim sparkline under a panel's own, blank where the
+// baseline has no sample.
+func baselineRow(history *ring.Buffer, width int) string {
+	n := history.Len()
+	start := max(0, n-width)
+	var sb strings.Builder
+	for i := start; i < n; i++ {
+		if v := history.At(i); math.IsNaN(v) {
+			sb.WriteByte(' ')
+		} else {
+			sb.WriteRune(SparkRune(v))
+		}
+	}
+	return lipgloss.NewStyle().Foreground(cGray500).Render(sb.String()) + "  " + dimSt.Render("base")
+}
+
+// renderBaselineSummary is the banner under a replay with a baseline: the
+// replay's CPU and memory against the baseline's at the same time since
+// the start.
+func (m Model) renderBaselineSummary(iw int) string {
+	b := m.baseline
+	at := m.baseAt.Sub(b.start).Truncate(time.Second)
+	line := labelSt.Render("BASELINE") + dimSt.Render(fmt.Sprintf(" %s at +%s", b.name, at))
+	if !m.baseOK {
+		line += dimSt.Render(fmt.Sprintf("   no sample here; it spans +0s to +%s", b.end().Truncate(time.Second)))
+	} else {
+		line += "   " + baselineDelta("CPU", m.cpuTotal, m.baseNow.CpuTotal) +
+			"   " + baselineDelta("MEM", m.memPercent, m.baseNow.MemPercent)
+	}
+	return " " + ansi.Truncate(line, iw, "…")
+}
+
+// baselineDelta is "CPU 52.1% vs 40.0% +12.1": higher than the baseline
+// in amber, lower in green, within a point dim.
+func baselineDelta(label string, cur, base float64) string {
+	d := cur - base
+	st := dimSt
+	switch {
+	case d >= 1:
+		st = lipgloss.NewStyle().Foreground(cAmber)
+	case d <= -1:
+		st = lipgloss.NewStyle().Foreground(cGreen)
+	}
+	return dimSt.Render(label+" ") + brightSt.Render(FormatPercent(cur)) + dimSt.Render(" vs ") +
+		brightSt.Render(FormatPercent(base)) + " " + st.Render(fmt.Sprintf("%+.1f", d))
+}
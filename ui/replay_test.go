@@ -0,0 +1,87 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ALH477/infgo/analysis"
+	"github.com/ALH477/infgo/metrics"
+)
+
+// rampCapture is n samples every step from start, CPU and memory rising
+// by one point a sample from cpu and mem.
+func rampCapture(start time.Time, step time.Duration, n int, cpu, mem float64) *analysis.Capture {
+	h := SysInfo{Hostname: "box", Platform: "linux"}.Header(start, 4, step)
+	c := &analysis.Capture{Header: &h}
+	for i := range n {
+		c.Samples = append(c.Samples, metrics.Sample{
+			TimestampUnixMs: start.Add(time.Duration(i) * step).UnixMilli(),
+			CpuTotal:        cpu + float64(i),
+			MemPercent:      mem + float64(i),
+			CpuCores:        []float64{10, 20, 30, 40},
+			MemUsedGB:       8,
+			MemTotalGB:      16,
+		})
+	}
+	return c
+}
+
+func TestBaselineTrack(t *testing.T) {
+	t0 := time.UnixMilli(1_700_000_000_000)
+	primary := rampCapture(t0, time.Second, 10, 50, 50)
+	// The baselines were recorded a day earlier.
+	b0 := t0.Add(-24 * time.Hour)
+	tests := []struct {
+		name string
+		base *analysis.Capture
+		at   time.Duration // into the replay
+		cpu  float64
+		ok   bool
+	}{
+		// At 250ms, four samples to each second of the replay: the
+		// mean of 26, 27, 28 and 29.
+		{"finer", rampCapture(b0, 250*time.Millisecond, 40, 10, 10), 4 * time.Second, 27.5, true},
+		// At 3s, each sample holds for the two seconds after it.
+		{"coarser", rampCapture(b0, 3*time.Second, 4, 10, 10), 5 * time.Second, 11, true},
+		{"coarser, at a sample", rampCapture(b0, 3*time.Second, 4, 10, 10), 6 * time.Second, 12, true},
+		{"longer", rampCapture(b0, time.Second, 60, 10, 10), 9 * time.Second, 19, true},
+		{"shorter", rampCapture(b0, time.Second, 4, 10, 10), 3 * time.Second, 13, true},
+		{"shorter, past its end", rampCapture(b0, time.Second, 4, 10, 10), 6 * time.Second, 0, false},
+		{"before the start", rampCapture(b0, time.Second, 4, 10, 10), -time.Second, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := NewBaseline("old.infgo", primary, tt.base)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s, ok := b.at(t0.Add(tt.at))
+			if ok != tt.ok || s.CpuTotal != tt.cpu {
+				t.Errorf("at +%v: got %g, %v, want %g, %v", tt.at, s.CpuTotal, ok, tt.cpu, tt.ok)
+			}
+		})
+	}
+
+	// A gap in the baseline, such as a suspend, is not bridged.
+	gap := rampCapture(b0, time.Second, 8, 10, 10)
+	gap.Samples = append(gap.Samples[:2], gap.Samples[6:]...)
+	b, err := NewBaseline("old.infgo", primary, gap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		at int
+		ok bool
+	}{{1, true}, {2, true}, {3, false}, {6, true}} {
+		if _, ok := b.at(t0.Add(time.Duration(tt.at) * time.Second)); ok != tt.ok {
+			t.Errorf("gap, at +%ds: got %v, want %v", tt.at, ok, tt.ok)
+		}
+	}
+
+	if _, err := NewBaseline("old.infgo", primary, &analysis.Capture{}); err == nil {
+		t.Error("an empty baseline: got no error")
+	}
+}
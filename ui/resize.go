@@ -0,0 +1,87 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ── Resizing ──────────────────────────────────────────────────────────────────
+
+// Dragging a terminal's corner sends a WindowSizeMsg for every step of the
+// drag.  Only the first size is applied at once; after that a resize waits
+// resizeSettle for the next, and the layout is recomputed once the sizes
+// stop coming.
+
+// resizeSettle is how long a resize waits for another before it is applied.
+const resizeSettle = 50 * time.Millisecond
+
+// MinTermWidth / MinTermHeight are the smallest terminal the layout fits:
+// below them the box art would wrap, so View asks for a bigger window
+// instead.
+const (
+	MinTermWidth  = minInnerWidth + 4
+	MinTermHeight = 20
+)
+
+// resizeMsg applies the pending size of the Model numbered id if no resize
+// has come since the one numbered seq.
+type resizeMsg struct {
+	id  int
+	seq uint64
+}
+
+// resize handles a WindowSizeMsg.
+func (m Model) resize(msg tea.WindowSizeMsg) (tea.Model, tea.Cmd) {
+	if !m.sized {
+		return m.resized(msg.Width, msg.Height), nil
+	}
+	m.resizeSeq++
+	m.pendingW, m.pendingH = msg.Width, msg.Height
+	id, seq := m.id, m.resizeSeq
+	return m, tea.Tick(resizeSettle, func(time.Time) tea.Msg {
+		return resizeMsg{id, seq}
+	})
+}
+
+// settle applies the pending size once the resizes have stopped.
+func (m Model) settle(msg resizeMsg) (tea.Model, tea.Cmd) {
+	if msg.id != m.id || msg.seq != m.resizeSeq {
+		return m, nil // a later resize is still settling
+	}
+	return m.resized(m.pendingW, m.pendingH), nil
+}
+
+// resized lays m out for a w×h terminal at once.
+func (m Model) resized(w, h int) Model {
+	if m.fixedWidth {
+		w = m.width
+	}
+	m.rev++
+	m.sized = true
+	m.width, m.height = w, h
+	// Keep the Bubbles progress bar in sync with the actual terminal width.
+	m.memProgress.Width = innerWidth(w) - 6
+	return m
+}
+
+// tooSmall reports whether the terminal is smaller than the layout.  A
+// pane of WithPanels is as tall as its panels.
+func (m Model) tooSmall() bool {
+	return m.sized && (m.width < MinTermWidth || m.pane == nil && m.height < MinTermHeight)
+}
+
+// renderTooSmall replaces the whole frame while the terminal is too small,
+// centred, and wrapped if it is narrower than the message.
+func (m Model) renderTooSmall() string {
+	text := fmt.Sprintf("Terminal too small — need at least %d×%d (currently %d×%d)",
+		MinTermWidth, MinTermHeight, m.width, m.height)
+	w := max(1, min(m.width, lipgloss.Width(text)))
+	text = lipgloss.NewStyle().Width(w).Align(lipgloss.Center).Foreground(cAmber).Render(text)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, text)
+}
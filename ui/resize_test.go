@@ -0,0 +1,174 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// checkGoldenText compares rendered output against testdata/name.
+func checkGoldenText(t *testing.T, name, got string) {
+	t.Helper()
+	got += "\n"
+	path := filepath.Join("testdata", name)
+	if *updateGolden {
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("%s: screen differs from the golden file (rerun with -update to accept)\ngot:\n%s", name, got)
+	}
+}
+
+// sizedModel is a model with a reading, laid out for a w×h terminal.
+func sizedModel(w, h int, opts ...Option) Model {
+	var tm tea.Model = New(opts...)
+	tm, _ = tm.Update(tea.WindowSizeMsg{Width: w, Height: h})
+	tm, _ = tm.Update(SysInfo{Hostname: "box", Platform: "linux", NumCores: 4})
+	msg := benchStats()
+	msg.CPUCores = msg.CPUCores[:4]
+	tm, _ = tm.Update(msg)
+	return tm.(Model)
+}
+
+// A drag's burst of resizes is applied once, as its last size, and ends in
+// the layout a single resize to that size gives.
+func TestResizeBurst(t *testing.T) {
+	var tm tea.Model = sizedModel(80, 30)
+	var cmds []tea.Cmd
+	for _, size := range [][2]int{{100, 40}, {50, 12}, {120, 45}, {90, 28}} {
+		var cmd tea.Cmd
+		tm, cmd = tm.Update(tea.WindowSizeMsg{Width: size[0], Height: size[1]})
+		if cmd == nil {
+			t.Fatal("a resize after the first was applied without settling")
+		}
+		cmds = append(cmds, cmd)
+	}
+	if m := tm.(Model); m.width != 80 || m.height != 30 {
+		t.Fatalf("mid-drag: got %d×%d, want the layout left at 80×30", m.width, m.height)
+	}
+	// The ticks arrive in order, each resizeSettle after its resize.
+	for i, cmd := range cmds {
+		msg := cmd()
+		if _, ok := msg.(resizeMsg); !ok {
+			t.Fatalf("resize %d: got %T, want resizeMsg", i, msg)
+		}
+		tm, _ = tm.Update(msg)
+		if m := tm.(Model); i < len(cmds)-1 && m.width != 80 {
+			t.Fatalf("resize %d applied while later ones were pending", i)
+		}
+	}
+	got := tm.(Model)
+	if got.width != 90 || got.height != 28 {
+		t.Fatalf("after the drag: got %d×%d, want 90×28", got.width, got.height)
+	}
+	if want := sizedModel(90, 28); fullView(got) != fullView(want) || got.View() != fullView(want) {
+		t.Errorf("after the drag the layout differs from a single resize:\n%s\nwant:\n%s", got.View(), fullView(want))
+	}
+}
+
+// hiddenCores is the count on the core grid's "(+N more cores)" line, or 0.
+func hiddenCores(view string) int {
+	match := regexp.MustCompile(`\(\+(\d+) more cores\)`).FindStringSubmatch(view)
+	if match == nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(match[1])
+	return n
+}
+
+func TestTooSmall(t *testing.T) {
+	tests := []struct {
+		w, h   int
+		golden string // empty where the layout fits
+	}{
+		{MinTermWidth - 1, MinTermHeight, "too_small_wide.txt"},
+		{MinTermWidth, MinTermHeight - 1, "too_small_short.txt"},
+		{40, 15, "too_small_narrow.txt"},
+		{MinTermWidth, MinTermHeight, ""},
+	}
+	for _, tt := range tests {
+		m := sizedModel(tt.w, tt.h)
+		view := m.View()
+		if tt.golden == "" {
+			if strings.Contains(view, "too small") {
+				t.Errorf("%d×%d: got the too-small screen, want the layout", tt.w, tt.h)
+			}
+			continue
+		}
+		if lines := strings.Count(view, "\n") + 1; lines != tt.h {
+			t.Errorf("%d×%d: got %d lines, want the screen filled", tt.w, tt.h, lines)
+		}
+		checkGoldenText(t, tt.golden, ansi.Strip(view))
+	}
+
+	// Growing the window brings the layout back.
+	var tm tea.Model = sizedModel(40, 15)
+	tm, cmd := tm.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	tm, _ = tm.Update(cmd())
+	if view := tm.View(); strings.Contains(view, "too small") || !strings.Contains(view, "CPU") {
+		t.Errorf("after growing: got\n%s", view)
+	}
+}
+
+// The core grid takes the rows the other panels leave: the frame fills a
+// terminal tall enough for it and never runs past the bottom.
+func TestCoreGridFits(t *testing.T) {
+	for _, cores := range []int{4, 16, 64} {
+		for _, h := range []int{MinTermHeight, 24, 34, 36, 40, 60, 80} {
+			for _, users := range []bool{false, true} {
+				var opts []Option
+				if users {
+					opts = append(opts, WithUsers(), WithDiskPanel())
+				}
+				m := sizedModel(100, h, opts...)
+				msg := benchStats()
+				msg.CPUCores = make([]float64, cores)
+				msg.Mounts = []metrics.DiskUsage{{Mount: "/", UsedPercent: 50, UsedGB: 50, TotalGB: 100}}
+				next, _ := m.Update(msg)
+				m = next.(Model)
+				name := fmt.Sprintf("%d cores, %d rows, users and disks %v", cores, h, users)
+
+				view := ansi.Strip(m.View())
+				lines := strings.Count(view, "\n") + 1
+				hidden := hiddenCores(view)
+				// Without the grid the frame is as short as it gets;
+				// a terminal shorter still shows the aggregate alone.
+				bare := m
+				bare.view, bare.cpuCores = nil, nil
+				floor := strings.Count(ansi.Strip(bare.View()), "\n") + 1
+				switch {
+				case h < floor && strings.Contains(view, "CORES"):
+					t.Errorf("%s: got a core grid below the %d-line floor", name, floor)
+				case h >= floor && lines > h:
+					t.Errorf("%s: got %d lines", name, lines)
+				case h >= floor+2+(cores+1)/2 && (hidden > 0 || !strings.Contains(view, fmt.Sprintf("[%d] ", cores-1))):
+					t.Errorf("%s: not every core shown, with room for them all", name)
+				case hidden > 0 && lines != h:
+					t.Errorf("%s: %d cores left out of %d lines", name, hidden, lines)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ALH477/infgo/analysis"
+)
+
+// ── Stats scheduling ──────────────────────────────────────────────────────────
+
+// jitterGain is the weight of each new interval in the running jitter
+// estimate, as in RTP's interarrival jitter (RFC 3550): 1/16.
+const jitterGain = 16
+
+// statsSchedule aims every stats tick at an absolute deadline, start +
+// k·interval, rather than one interval after the previous tick was handled.
+// Time spent in Update and in the Bubble Tea queue therefore delays single
+// ticks but never accumulates, and the sparklines cover the wall-clock span
+// they are labelled with.
+type statsSchedule struct {
+	interval time.Duration
+	next     time.Time // deadline of the tick in flight; zero before the first
+	due      time.Time // deadline of the tick fired last
+	last     time.Time // when the previous tick fired
+
+	jitter  time.Duration // smoothed |achieved interval - interval|
+	skipped int           // deadlines passed over because a tick came too late
+	busy    int           // ticks that took no reading: the last had not returned
+
+	// Readings that came back, and when the first and latest did: the
+	// achieved rate, which falls short of the interval's when readings
+	// take longer than it.
+	reads               int
+	firstRead, lastRead time.Time
+}
+
+func newStatsSchedule(interval time.Duration) statsSchedule {
+	return statsSchedule{interval: interval}
+}
+
+// fired records a tick that fired at at and is being handled at now, and
+// returns how long to wait from now for the next deadline.  A tick so late
+// that the following deadline has passed too skips ahead to the first one
+// still in the future rather than firing a burst to catch up.
+func (s *statsSchedule) fired(at, now time.Time) time.Duration {
+	if !s.last.IsZero() {
+		dev := at.Sub(s.last) - s.interval
+		if dev < 0 {
+			dev = -dev
+		}
+		s.jitter += (dev - s.jitter) / jitterGain
+	}
+	s.last = at
+	if s.next.IsZero() {
+		s.next = at
+	}
+	s.due = s.next
+	s.next = s.next.Add(s.interval)
+	if !s.next.After(now) {
+		behind := now.Sub(s.next)/s.interval + 1
+		s.next = s.next.Add(behind * s.interval)
+		s.skipped += int(behind)
+	}
+	return s.next.Sub(now)
+}
+
+// read records a reading that came back at at.
+func (s *statsSchedule) read(at time.Time) {
+	if s.reads == 0 {
+		s.firstRead = at
+	}
+	s.reads++
+	s.lastRead = at
+}
+
+// rate is the readings per second achieved so far, or 0 before there are
+// two of them.
+func (s *statsSchedule) rate() float64 {
+	span := s.lastRead.Sub(s.firstRead)
+	if s.reads < 2 || span <= 0 {
+		return 0
+	}
+	return float64(s.reads-1) / span.Seconds()
+}
+
+// summary is the sampling row of the SYSTEM panel, e.g. "500ms ±0.4ms".
+// Faster than MinDisplayInterval, it adds the achieved rate, which is not
+// otherwise visible: "100ms ±0.3ms, 9.9/s".
+func (s *statsSchedule) summary() string {
+	out := fmt.Sprintf("%v ±%sms", s.interval, FormatNumber(float64(s.jitter)/float64(time.Millisecond), 1))
+	if r := s.rate(); s.interval < MinDisplayInterval && r > 0 {
+		out += ", " + fmtRate(r)
+	}
+	if s.skipped > 0 {
+		out += fmt.Sprintf(", %d skipped", s.skipped)
+	}
+	if s.busy > 0 {
+		out += fmt.Sprintf(", %d busy", s.busy)
+	}
+	return out
+}
+
+// ── Reading latency ───────────────────────────────────────────────────────────
+
+// readLatency is how long the local readings take, and how late after
+// their tick's deadline they start: the latest of each and the 95th
+// percentile over the session.  On an overloaded machine both grow, and
+// show when the monitor itself is being starved.
+type readLatency struct {
+	took, late       time.Duration
+	tookP95, lateP95 *analysis.P2
+	hasLate          bool // a reading taken on a tick has come back
+}
+
+// add counts msg.  Readings that did not time themselves are left out.
+func (l *readLatency) add(msg Reading) {
+	if msg.Collect <= 0 {
+		return
+	}
+	if l.tookP95 == nil {
+		l.tookP95, l.lateP95 = analysis.NewP2(0.95), analysis.NewP2(0.95)
+	}
+	l.took = msg.Collect
+	l.tookP95.Add(float64(l.took))
+	if !msg.Due.IsZero() && !msg.Begun.IsZero() {
+		l.late, l.hasLate = max(0, msg.Begun.Sub(msg.Due)), true
+		l.lateP95.Add(float64(l.late))
+	}
+}
+
+// FormatMillis formats d in milliseconds with one decimal: "2.1ms".
+func FormatMillis(d time.Duration) string {
+	return FormatNumber(float64(d)/float64(time.Millisecond), 1) + "ms"
+}
+
+// readText is the Read row of the SYSTEM panel, e.g. "2.1ms, p95 3.4ms".
+func (l readLatency) readText() string {
+	if l.tookP95 == nil {
+		return "—"
+	}
+	return fmt.Sprintf("%s, p95 %s", FormatMillis(l.took), FormatMillis(time.Duration(l.tookP95.Value())))
+}
+
+// lateText is the Late row: how far after its deadline the latest reading
+// started, e.g. "0.3ms, p95 0.8ms".
+func (l readLatency) lateText() string {
+	if !l.hasLate {
+		return "—"
+	}
+	return fmt.Sprintf("%s, p95 %s", FormatMillis(l.late), FormatMillis(time.Duration(l.lateP95.Value())))
+}
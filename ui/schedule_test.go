@@ -0,0 +1,135 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+)
+
+// runSchedule drives a statsSchedule with a fake clock for n ticks.  Each
+// timer fires fireLate after it was due and Update handles the tick
+// handleLate after that; the returned slice holds the firing times.
+func runSchedule(s *statsSchedule, start time.Time, n int, fireLate, handleLate func(i int) time.Duration) []time.Time {
+	fires := make([]time.Time, 0, n)
+	clock := start
+	wait := s.interval // Init's first tick
+	for i := range n {
+		at := clock.Add(wait + fireLate(i))
+		fires = append(fires, at)
+		clock = at.Add(handleLate(i))
+		wait = s.fired(at, clock)
+		if wait <= 0 || wait > s.interval {
+			panic("wait out of range")
+		}
+	}
+	return fires
+}
+
+func TestStatsScheduleBoundedDrift(t *testing.T) {
+	const iv = 500 * time.Millisecond
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	rng := rand.New(rand.NewSource(1))
+	s := newStatsSchedule(iv)
+	const n = 10000
+	fires := runSchedule(&s, start, n,
+		func(int) time.Duration { return time.Duration(rng.Intn(3)) * time.Millisecond },
+		func(int) time.Duration { return time.Duration(rng.Intn(40)) * time.Millisecond },
+	)
+	// The first tick anchors the deadlines, and every later one lands within
+	// the timer's own lateness of anchor + k·iv: processing time never
+	// accumulates.
+	for k, at := range fires {
+		if off := at.Sub(fires[0].Add(time.Duration(k) * iv)); off < 0 || off >= 3*time.Millisecond {
+			t.Fatalf("tick %d: %v off its deadline", k, off)
+		}
+	}
+	if got := fires[n-1].Sub(start); got > n*iv+6*time.Millisecond {
+		t.Errorf("%d ticks took %v, want about %v", n, got, n*iv)
+	}
+	if s.skipped != 0 {
+		t.Errorf("got %d skipped, want 0", s.skipped)
+	}
+	if s.jitter <= 0 || s.jitter > 3*time.Millisecond {
+		t.Errorf("jitter: got %v, want within the timer lateness", s.jitter)
+	}
+
+	// The relative scheduling this replaces drifts by the processing time
+	// on every tick.
+	rel := start
+	rng = rand.New(rand.NewSource(1))
+	for range n {
+		rel = rel.Add(iv + time.Duration(rng.Intn(3))*time.Millisecond + time.Duration(rng.Intn(40))*time.Millisecond)
+	}
+	if drift := rel.Sub(start) - n*iv; drift < time.Minute {
+		t.Errorf("relative ticks drifted only %v; the comparison is broken", drift)
+	}
+}
+
+func TestStatsScheduleSkipsAhead(t *testing.T) {
+	const iv = 500 * time.Millisecond
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	s := newStatsSchedule(iv)
+	fires := runSchedule(&s, start, 6,
+		func(int) time.Duration { return 0 },
+		func(i int) time.Duration {
+			if i == 2 {
+				return 1200 * time.Millisecond // a stall in Update
+			}
+			return 0
+		},
+	)
+	// Deadlines 4 and 5 (2.0s, 2.5s) passed during the stall: the next
+	// tick fires at 3.0s, not in a burst.
+	want := []time.Duration{500, 1000, 1500, 3000, 3500, 4000}
+	for i, at := range fires {
+		if got := at.Sub(start); got != want[i]*time.Millisecond {
+			t.Errorf("tick %d: fired at %v, want %v", i, got, want[i]*time.Millisecond)
+		}
+	}
+	if s.skipped != 2 {
+		t.Errorf("got %d skipped, want 2", s.skipped)
+	}
+	if got := s.summary(); !strings.HasPrefix(got, "500ms ±") || !strings.HasSuffix(got, ", 2 skipped") {
+		t.Errorf("summary: got %q", got)
+	}
+}
+
+func TestReadLatency(t *testing.T) {
+	const iv = 500 * time.Millisecond
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	s := newStatsSchedule(iv)
+	var l readLatency
+	if got := l.readText() + " " + l.lateText(); got != "— —" {
+		t.Errorf("before any reading: got %q", got)
+	}
+	l.add(Reading{}) // a remote's, untimed
+	for i := range 20 {
+		// Each tick fires on time; the reading starts 1ms after it, 50ms
+		// after every tenth, and takes 2ms, 40ms after every tenth.
+		at := start.Add(time.Duration(i+1) * iv)
+		s.fired(at, at)
+		msg := Reading{Due: s.due, Begun: s.due.Add(time.Millisecond), Collect: 2 * time.Millisecond}
+		if i%10 == 9 {
+			msg.Begun, msg.Collect = s.due.Add(50*time.Millisecond), 40*time.Millisecond
+		}
+		if !s.due.Equal(at) {
+			t.Fatalf("tick %d: due %v, want %v", i, s.due, at)
+		}
+		l.add(msg)
+	}
+	if got, want := l.readText(), "40.0ms, p95 "; !strings.HasPrefix(got, want) {
+		t.Errorf("Read: got %q, want %q…", got, want)
+	}
+	if got, want := l.lateText(), "50.0ms, p95 "; !strings.HasPrefix(got, want) {
+		t.Errorf("Late: got %q, want %q…", got, want)
+	}
+
+	l.add(Reading{Collect: 3 * time.Millisecond}) // off schedule: no lateness
+	if got := l.lateText(); !strings.HasPrefix(got, "50.0ms") {
+		t.Errorf("Late after an off-schedule reading: got %q", got)
+	}
+}
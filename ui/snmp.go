@@ -0,0 +1,62 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// RetransWarn and RetransCrit are the defaults of -retrans-warn and
+	// -retrans-crit, in segments a second.
+	RetransWarn = 10
+	RetransCrit = 100
+)
+
+// retransMeter turns the retransmits of successive readings into a rate.
+type retransMeter struct {
+	prev   uint64
+	prevAt time.Time
+	rate   float64
+	ok     bool   // rate holds one
+	total  uint64 // since the first reading
+
+	// warn and crit are the rates from which the badge is amber and red.
+	warn, crit float64
+}
+
+// observe folds in a reading at at; a zero at is a reading without the
+// counter, which leaves the rate as it was.
+func (t *retransMeter) observe(n uint64, at time.Time) {
+	if at.IsZero() {
+		return
+	}
+	t.ok = false
+	if !t.prevAt.IsZero() {
+		if t.rate, t.ok = counterRate(t.prev, n, at.Sub(t.prevAt)); t.ok {
+			t.total += n - t.prev
+		}
+	}
+	t.prev, t.prevAt = n, at
+}
+
+// badge is "retrans 3/s": dim at zero and until there is a rate, amber
+// from warn and red from crit.
+func (t *retransMeter) badge() string {
+	if !t.ok {
+		return dimSt.Render("retrans —")
+	}
+	st := brightSt
+	switch {
+	case t.rate == 0:
+		st = dimSt
+	case t.rate >= t.crit:
+		st = lipgloss.NewStyle().Foreground(cRed).Bold(true)
+	case t.rate >= t.warn:
+		st = lipgloss.NewStyle().Foreground(cAmber)
+	}
+	return dimSt.Render("retrans ") + st.Render(humanizeRate(t.rate))
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// The rate is the delta over the time between readings, none for the
+// first reading or across a reset, and the count since the first reading
+// goes on across both.
+func TestRetransMeter(t *testing.T) {
+	tm := retransMeter{warn: RetransWarn, crit: RetransCrit}
+	at := time.Unix(1700000000, 0)
+	for i, s := range []struct {
+		dt    time.Duration
+		n     uint64
+		rate  float64
+		ok    bool
+		total uint64
+	}{
+		{0, 1000, 0, false, 0},
+		{time.Second, 1000, 0, true, 0},
+		{2 * time.Second, 1040, 20, true, 40},
+		{time.Second, 10, 0, false, 40}, // reset
+		{time.Second, 15, 5, true, 45},
+	} {
+		at = at.Add(s.dt)
+		tm.observe(s.n, at)
+		if tm.ok != s.ok || tm.rate != s.rate || tm.total != s.total {
+			t.Errorf("reading %d: got %v, %v, %d", i, tm.rate, tm.ok, tm.total)
+		}
+	}
+}
+
+// The badge is dim at zero, amber from warn and red from crit.
+func TestRetransBadge(t *testing.T) {
+	inColour(t)
+	amber, red := lipgloss.NewStyle().Foreground(cAmber), lipgloss.NewStyle().Foreground(cRed).Bold(true)
+	for _, tt := range []struct {
+		rate float64
+		ok   bool
+		want string
+	}{
+		{0, false, dimSt.Render("retrans —")},
+		{0, true, dimSt.Render("retrans ") + dimSt.Render("0/s")},
+		{3, true, dimSt.Render("retrans ") + brightSt.Render("3/s")},
+		{10, true, dimSt.Render("retrans ") + amber.Render("10/s")},
+		{250, true, dimSt.Render("retrans ") + red.Render("250/s")},
+	} {
+		tm := retransMeter{rate: tt.rate, ok: tt.ok, warn: RetransWarn, crit: RetransCrit}
+		if got := tm.badge(); got != tt.want {
+			t.Errorf("%v/s: got %q, want %q", tt.rate, got, tt.want)
+		}
+	}
+}
+
+// The NET panel has the badge in its title once the retransmits are read,
+// and their count since the start beside the errors and drops.
+func TestRetransInNet(t *testing.T) {
+	m := sizedModel(100, 50)
+	m.net = newNetMeter()
+	if got := ansi.Strip(m.panel(PanelNet, innerWidth(100))); strings.Contains(got, "retrans") {
+		t.Errorf("without the counter: got\n%s", got)
+	}
+	at := time.Unix(1700000000, 0)
+	for _, n := range []uint64{1000, 1012} {
+		tm, _ := m.Update(Reading{CPUTotal: 10, MemPercent: 40, NetAt: at, RetransAt: at, Retrans: n,
+			NICs: []NetCounter{{"eth0", 0, 0, 0, 0}}})
+		m = tm.(Model)
+		at = at.Add(time.Second)
+	}
+	got := ansi.Strip(m.panel(PanelNet, innerWidth(100)))
+	for _, want := range []string{"NET  eth0   retrans 12/s", "0 errs · 0 drops · 12 retrans since start"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in\n%s", want, got)
+		}
+	}
+}
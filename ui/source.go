@@ -0,0 +1,73 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"context"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/host"
+	"github.com/shirou/gopsutil/v3/load"
+	"github.com/shirou/gopsutil/v3/mem"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// ── Sources ───────────────────────────────────────────────────────────────────
+
+// Source takes one reading.  A subsystem it could not read is named in the
+// sample's Missing set, and the Model keeps showing its last value.
+type Source func(ctx context.Context) metrics.Sample
+
+// LocalSource reads this machine with gopsutil.  Its first CPU reading
+// only primes gopsutil's snapshot and is reported missing: a delta over
+// the instant since the process started would read 0% or a spike.
+// gopsutil keeps that snapshot per process, so the infgo binary uses its
+// own reader rather than this one.
+func LocalSource() Source {
+	primed := false
+	return func(ctx context.Context) metrics.Sample {
+		s := metrics.Sample{TimestampUnixMs: time.Now().UnixMilli()}
+		cores, err := cpu.PercentWithContext(ctx, 0, true)
+		if err != nil || !primed || len(cores) == 0 {
+			primed = primed || err == nil
+			s.Missing |= metrics.MissingCPU
+		} else {
+			for _, c := range cores {
+				s.CpuTotal += c
+			}
+			s.CpuTotal /= float64(len(cores))
+			s.CpuCores = cores
+		}
+		if vm, err := mem.VirtualMemoryWithContext(ctx); err != nil {
+			s.Missing |= metrics.MissingMem
+		} else {
+			const gb = 1 << 30
+			s.MemPercent = vm.UsedPercent
+			s.MemUsedGB = float64(vm.Used) / gb
+			s.MemTotalGB = float64(vm.Total) / gb
+		}
+		// load.Avg is a no-op on Windows, where the averages read as zero.
+		if avg, err := load.AvgWithContext(ctx); err != nil {
+			s.Missing |= metrics.MissingLoad
+		} else if avg != nil {
+			s.Load1, s.Load5, s.Load15 = avg.Load1, avg.Load5, avg.Load15
+		}
+		return s
+	}
+}
+
+// readHostInfo is the SYSTEM panel's host info of this machine.
+func readHostInfo(ctx context.Context) SysInfo {
+	info, err := host.InfoWithContext(ctx)
+	if err != nil {
+		return SysInfo{Hostname: "unknown", Platform: "unknown"}
+	}
+	return SysInfo{
+		Hostname: info.Hostname,
+		Platform: info.Platform + " · " + info.KernelArch,
+		Uptime:   info.Uptime,
+	}
+}
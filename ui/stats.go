@@ -0,0 +1,24 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// CoresEvent marks a change from was to n in the number of per-core
+// readings, which is taken as the machine's core count from then on.
+// cpus is runtime.NumCPU for a local reading, 0 for a remote one.  On
+// Windows with more than 64 logical processors the two can disagree:
+// processor groups can leave some of them out of the per-core query.
+func CoresEvent(at time.Time, was, n, cpus int) metrics.Event {
+	msg := fmt.Sprintf("%d cores in the per-core readings, was %d", n, was)
+	if cpus > 0 && n != cpus {
+		msg += fmt.Sprintf("; runtime.NumCPU reports %d", cpus)
+	}
+	return metrics.Event{TimestampUnixMs: at.UnixMilli(), Kind: "cores", Message: msg}
+}
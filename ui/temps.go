@@ -0,0 +1,90 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// tempWarnC and tempCritC are the temperatures at which a reading turns
+// from OK to Warn and from Warn to Crit, as a load does at WarnPct and
+// CritPct.
+const (
+	tempWarnC = 60
+	tempCritC = 80
+)
+
+// TempGroup is the hottest of a group of sensors.
+type TempGroup struct {
+	Name    string  // the chip, e.g. coretemp
+	Sensor  string  // its hottest sensor, e.g. package_id_0; "" if unlabelled
+	Celsius float64 // that sensor's reading
+	Sensors int     // how many the group has
+}
+
+// tempLoad is c placed on the load scale, so that tempWarnC is WarnPct
+// and tempCritC CritPct, for loadColor and the bars.
+func tempLoad(c float64) float64 {
+	return WarnPct + (c-tempWarnC)*(CritPct-WarnPct)/(tempCritC-tempWarnC)
+}
+
+// tempText is c in the unit the panel shows.
+func (m Model) tempText(c float64) string {
+	if m.fahrenheit {
+		return FormatNumber(c*9/5+32, 1) + "°F"
+	}
+	return FormatNumber(c, 1) + "°C"
+}
+
+// hottestTemp is the hottest group; false before any was read.
+func hottestTemp(groups []TempGroup) (TempGroup, bool) {
+	if len(groups) == 0 {
+		return TempGroup{}, false
+	}
+	return slices.MaxFunc(groups, func(a, b TempGroup) int { return cmp.Compare(a.Celsius, b.Celsius) }), true
+}
+
+func (m Model) renderTemps(iw int) string {
+	const (
+		nameW  = 12
+		barW   = 10
+		valueW = 9
+	)
+	title := labelSt.Render("TEMP")
+	if m.fahrenheit {
+		title += dimSt.Render("   f °C")
+	} else {
+		title += dimSt.Render("   f °F")
+	}
+	lines := []string{title, ""}
+	sensorW := max(iw-2-nameW-2-barW-2-valueW-2, 4)
+	for _, g := range m.temps {
+		name, sensor := g.Name, g.Sensor
+		if ansi.StringWidth(name) > nameW {
+			name = ansi.Truncate(name, nameW, "…")
+		}
+		if ansi.StringWidth(sensor) > sensorW {
+			sensor = ansi.Truncate(sensor, sensorW, "…")
+		}
+		load := tempLoad(g.Celsius)
+		lines = append(lines, brightSt.Render(PadVisual(name, nameW))+"  "+
+			PadVisual(theme.MiniBar(min(max(load, 0), 100), barW), barW)+"  "+
+			lipgloss.NewStyle().Foreground(theme.LoadColor(load)).Render(PadVisual(m.tempText(g.Celsius), valueW))+"  "+
+			dimSt.Render(sensor))
+	}
+	hot, _ := hottestTemp(m.temps)
+	return theme.HeatPanel(tempLoad(hot.Celsius), iw+4).Render(strings.Join(lines, "\n"))
+}
+
+// tempHeadline is the collapsed panel's value: the hottest group.
+func (m Model) tempHeadline() string {
+	hot, _ := hottestTemp(m.temps)
+	return brightSt.Render(hot.Name) + "  " +
+		lipgloss.NewStyle().Foreground(theme.LoadColor(tempLoad(hot.Celsius))).Render(m.tempText(hot.Celsius))
+}
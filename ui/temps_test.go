@@ -0,0 +1,66 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestTempLoad(t *testing.T) {
+	for c, want := range map[float64]float64{tempWarnC: WarnPct, tempCritC: CritPct, 70: 80} {
+		if got := tempLoad(c); got != want {
+			t.Errorf("tempLoad(%v) = %v, want %v", c, got, want)
+		}
+	}
+	if theme.LoadColor(tempLoad(59)) != cGreen || theme.LoadColor(tempLoad(65)) != cAmber || theme.LoadColor(tempLoad(85)) != cRed {
+		t.Error("readings are not coloured at 60 and 80 °C")
+	}
+}
+
+// The TEMP panel appears with the first sensor read, truncates a long
+// sensor name to its column and switches to °F with f.
+func TestRenderTemps(t *testing.T) {
+	m := sizedModel(100, 50)
+	if slices.Contains(m.panels(), PanelTemp) {
+		t.Fatal("TEMP panel shown before any sensor was read")
+	}
+	m = pressKeys(m, "f")
+	if m.fahrenheit {
+		t.Error("f switched units with no panel")
+	}
+	long := strings.Repeat("x", 200)
+	tm, _ := m.Update(Reading{CPUTotal: 10, MemPercent: 40,
+		Temps: []TempGroup{{"coretemp", "package_id_0", 62, 3}, {"nvme", long, 41, 1}}})
+	m = tm.(Model)
+	if !slices.Contains(m.panels(), PanelTemp) {
+		t.Fatal("TEMP panel not shown")
+	}
+	got := ansi.Strip(m.panel(PanelTemp, innerWidth(100)))
+	for _, want := range []string{"TEMP", "coretemp", "62.0°C", "package_id_0", "41.0°C", "…"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in\n%s", want, got)
+		}
+	}
+	full := ansi.StringWidth(strings.Split(m.panel(PanelCPU, innerWidth(100)), "\n")[0])
+	for _, line := range strings.Split(got, "\n") {
+		if w := ansi.StringWidth(line); w != full {
+			t.Errorf("line %d wide: %q", w, line)
+		}
+	}
+
+	// A reading without the sensors leaves the last shown.
+	tm, _ = pressKeys(m, "f").Update(Reading{CPUTotal: 10, MemPercent: 40})
+	m = tm.(Model)
+	got = ansi.Strip(m.panel(PanelTemp, innerWidth(100)))
+	if !strings.Contains(got, "143.6°F") || strings.Contains(got, "62.0°C") {
+		t.Errorf("in °F: got\n%s", got)
+	}
+	if head := ansi.Strip(m.tempHeadline()); head != "coretemp  143.6°F" {
+		t.Errorf("headline: got %q", head)
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+// Package ui provides infgo's panels as a Bubble Tea component, so that
+// another program can show the CPU, memory and load of its host in a pane
+// of its own.  New returns a Model that samples the machine itself and
+// draws in the colours, bars and sparklines of the infgo binary, which
+// takes them from here.
+//
+// Every reading reaches the embedding program's Update as a SampleMsg on
+// its way to the Model, so it can be logged or acted on there as well.
+package ui
+
+import (
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ── Theme ─────────────────────────────────────────────────────────────────────
+
+// Theme is the palette the panels are drawn in.
+type Theme struct {
+	Accent     lipgloss.Color // titles and the CPU sparkline
+	AccentDark lipgloss.Color // the header's border
+	Memory     lipgloss.Color // the memory sparkline and key hints
+	OK         lipgloss.Color // a reading below WarnPct
+	Warn       lipgloss.Color // from WarnPct
+	Crit       lipgloss.Color // from CritPct
+	Border     lipgloss.Color // quiet borders and the empty part of a bar
+	Dim        lipgloss.Color // labels
+	Bright     lipgloss.Color // values
+}
+
+// DefaultTheme is infgo's own palette.
+var DefaultTheme = Theme{
+	Accent:     "#a78bfa",
+	AccentDark: "#7c3aed",
+	Memory:     "#06b6d4",
+	OK:         "#10b981",
+	Warn:       "#f59e0b",
+	Crit:       "#ef4444",
+	Border:     "#374151",
+	Dim:        "#6b7280",
+	Bright:     "#f9fafb",
+}
+
+// WarnPct and CritPct are the percentages at which readings, bars and
+// borders turn from OK to Warn and from Warn to Crit.
+const (
+	WarnPct = 70
+	CritPct = 90
+)
+
+// LoadColor is the traffic-light colour of a 0-100 percentage.
+func (t Theme) LoadColor(pct float64) lipgloss.Color {
+	switch {
+	case pct >= CritPct:
+		return t.Crit
+	case pct >= WarnPct:
+		return t.Warn
+	default:
+		return t.OK
+	}
+}
+
+// HeatPanel is a rounded panel totalW wide whose border turns Warn and
+// Crit with pct; below WarnPct it stays the quiet Border colour.
+func (t Theme) HeatPanel(pct float64, totalW int) lipgloss.Style {
+	bc := t.Border
+	if pct >= WarnPct {
+		bc = t.LoadColor(pct)
+	}
+	return lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(bc).
+		Padding(0, 2).
+		Width(totalW)
+}
+
+func (t Theme) dim() lipgloss.Style    { return lipgloss.NewStyle().Foreground(t.Dim) }
+func (t Theme) bright() lipgloss.Style { return lipgloss.NewStyle().Foreground(t.Bright) }
+func (t Theme) label() lipgloss.Style  { return lipgloss.NewStyle().Bold(true).Foreground(t.Accent) }
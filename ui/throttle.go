@@ -0,0 +1,53 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ThrottleReading is one reading of the throttling: events counted, where
+// the platform counts them, or whether the CPU is limited now.
+type ThrottleReading struct {
+	Events  uint64 // the throttle counts summed, where counted
+	Counted bool
+	Limited bool // where not counted
+}
+
+// ThrottleMsg carries a reading of the throttling to Update; ok is false
+// if it failed.
+type ThrottleMsg struct {
+	Reading ThrottleReading
+	OK      bool
+}
+
+// ThrottleState is the model's state for the badge.
+type ThrottleState struct {
+	prev   uint64 // the events at the last reading, where counted
+	seen   bool   // prev holds a reading
+	Active bool
+}
+
+// observe folds in a reading: active while the events went up since the
+// last, or while the CPU is limited.  A failed reading clears it.
+func (t *ThrottleState) Observe(msg ThrottleMsg) {
+	r := msg.Reading
+	switch {
+	case !msg.OK:
+		t.Active = false
+	case r.Counted:
+		t.Active = t.seen && r.Events > t.prev
+		t.prev, t.seen = r.Events, true
+	default:
+		t.Active = r.Limited
+	}
+}
+
+// badge is the CPU panel's title badge while the CPU is throttled.
+func (t ThrottleState) badge() string {
+	if !t.Active {
+		return ""
+	}
+	return lipgloss.NewStyle().Foreground(cRed).Bold(true).Render("  THROTTLED")
+}
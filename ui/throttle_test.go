@@ -0,0 +1,30 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// A sample taken while throttled records it.
+func TestThrottledSample(t *testing.T) {
+	m := sizedModel(100, 50)
+	rec := new(sinks)
+	m.rec = rec
+	for _, active := range []bool{false, true} {
+		m.throttle.Active = active
+		if got := ansi.Strip(m.renderCPU(innerWidth(100))); strings.Contains(got, "THROTTLED") != active {
+			t.Errorf("active %v: got\n%s", active, got)
+		}
+		tm, _ := m.Update(Reading{CPUTotal: 60, MemPercent: 40})
+		m = tm.(Model)
+	}
+	samples := rec.samples
+	if len(samples) != 2 || samples[0].CpuThrottled || !samples[1].CpuThrottled {
+		t.Errorf("logged: got %+v", samples)
+	}
+}
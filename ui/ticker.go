@@ -0,0 +1,95 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ── Footer ticker (-ticker) ───────────────────────────────────────────────────
+//
+// Readings that do not merit a panel of their own take turns on a line
+// above the footer, a few seconds each, fading in and out on the animation
+// tick.  p holds the one shown.
+
+// tickerItem is one of the summaries the ticker takes turns through.
+// tickerLine returns false where there is nothing to show, from a
+// collector that is off or has not read yet; the item skips its turn.
+type tickerItem interface {
+	tickerLine() (string, bool)
+}
+
+// tickerNames are the -ticker items, in the order all takes them.
+var tickerNames = []string{"process", "disk", "power", "temp"}
+
+// tickerFrames is how many animation frames an item is shown for, about
+// four seconds, and tickerFade the colours it fades in through and out
+// by, from the panel borders' grey.
+const tickerFrames = 36
+
+var tickerFade [2]lipgloss.Color
+
+// ParseTicker resolves -ticker: a comma-separated list of tickerNames,
+// all for every one, or empty for no ticker.
+func ParseTicker(spec string) ([]string, error) {
+	switch spec {
+	case "":
+		return nil, nil
+	case "all":
+		return tickerNames, nil
+	}
+	var out []string
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.TrimSpace(name)
+		if !slices.Contains(tickerNames, name) {
+			return nil, fmt.Errorf("-ticker: unknown item %q; want %s or all", name, strings.Join(tickerNames, ", "))
+		}
+		out = append(out, name)
+	}
+	return out, nil
+}
+
+// tickerItem returns the item name stands for.
+func (m Model) tickerItem(name string) tickerItem {
+	switch name {
+	case "process":
+		return m.topProc
+	case "disk":
+		return diskUsages(m.disks)
+	case "power":
+		return packagePower{m.watts, m.joules, m.hasWatts}
+	default:
+		return m.piStatus
+	}
+}
+
+// renderTicker is the ticker line, iw wide: the item whose turn it is
+// among those with something to show, blank if none has.
+func (m Model) renderTicker(iw int) string {
+	var lines []string
+	for _, name := range m.ticker {
+		if line, ok := m.tickerItem(name).tickerLine(); ok {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	turn, frame := m.tickerFrame/tickerFrames, m.tickerFrame%tickerFrames
+	line := lines[turn%len(lines)]
+	col := cGray50
+	if edge := min(frame, tickerFrames-1-frame); edge < len(tickerFade) && !m.tickerPaused {
+		col = tickerFade[edge]
+	}
+	mark := accentSt.Render("▸ ")
+	if m.tickerPaused {
+		mark = accentSt.Render("⏸ ")
+	}
+	return mark + lipgloss.NewStyle().Foreground(col).Render(ansi.Truncate(line, iw-2, "…"))
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+func TestParseTicker(t *testing.T) {
+	for spec, want := range map[string][]string{
+		"":              nil,
+		"all":           tickerNames,
+		"disk, process": {"disk", "process"},
+	} {
+		if got, err := ParseTicker(spec); err != nil || !slices.Equal(got, want) {
+			t.Errorf("%q: got %v, %v; want %v", spec, got, err, want)
+		}
+	}
+	for _, spec := range []string{"swap", "disk,", "all,disk"} {
+		if _, err := ParseTicker(spec); err == nil {
+			t.Errorf("%q: got nil error", spec)
+		}
+	}
+}
+
+// The items take turns, those with nothing to show skipping theirs, and
+// fade in and out at the ends of each.
+func TestTickerTurns(t *testing.T) {
+	inColour(t)
+	m := New()
+	m.ticker = tickerNames
+	if got := m.renderTicker(80); got != "" {
+		t.Errorf("nothing read yet: got %q", got)
+	}
+	m.disks = []metrics.DiskUsage{{Mount: "/", UsedPercent: 62, TotalGB: 200}}
+	m.topProc = ProcInfo{PID: 4312, Name: "firefox", CPU: 35, RSS: 1 << 30}
+
+	shown := func() string { return ansi.Strip(m.renderTicker(80)) }
+	if got := shown(); !strings.HasPrefix(got, "▸ top process firefox (4312) · "+FormatPercent(35)) {
+		t.Errorf("first turn: got %q", got)
+	}
+	m.tickerFrame = tickerFrames
+	if got := shown(); !strings.HasPrefix(got, "▸ disk / "+FormatPercent(62)+" of ") {
+		t.Errorf("second turn: got %q", got)
+	}
+	m.tickerFrame = 2 * tickerFrames // power and temp have nothing; back to the first
+	if got := shown(); !strings.Contains(got, "firefox") {
+		t.Errorf("third turn: got %q", got)
+	}
+
+	colour := func(frame int) lipgloss.TerminalColor {
+		m.tickerFrame = frame
+		out := m.renderTicker(80)
+		line, _ := m.topProc.tickerLine()
+		for _, c := range []lipgloss.Color{tickerFade[0], tickerFade[1], cGray50} {
+			if strings.HasSuffix(out, lipgloss.NewStyle().Foreground(c).Render(line)) {
+				return c
+			}
+		}
+		return nil
+	}
+	if colour(0) != tickerFade[0] || colour(1) != tickerFade[1] || colour(tickerFrames/2) != cGray50 || colour(tickerFrames-1) != tickerFade[0] {
+		t.Errorf("fade: got %v %v %v %v", colour(0), colour(1), colour(tickerFrames/2), colour(tickerFrames-1))
+	}
+}
+
+// p holds the item shown, and the ticker's line stays put with none.
+func TestTickerPause(t *testing.T) {
+	m := New()
+	m.ticker = []string{"power"}
+	m.watts, m.joules, m.hasWatts = 12.5, 7200, true
+	var tm tea.Model = m
+	tm, _ = tm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	for range 3 {
+		tm, _ = tm.Update(animTickMsg{})
+	}
+	m = tm.(Model)
+	if !m.tickerPaused || m.tickerFrame != 0 {
+		t.Fatalf("got paused %v at frame %d", m.tickerPaused, m.tickerFrame)
+	}
+	if got := ansi.Strip(m.renderTicker(80)); got != "⏸ CPU package "+FormatNumber(12.5, 1)+" W · "+fmtEnergy(7200)+" since start" {
+		t.Errorf("got %q", got)
+	}
+
+	with := lipgloss.Height(m.renderFooter(80))
+	m.hasWatts = false
+	if got := lipgloss.Height(m.renderFooter(80)); got != with {
+		t.Errorf("footer: got %d lines with nothing to show, want %d", got, with)
+	}
+	m.ticker = nil
+	if got := lipgloss.Height(m.renderFooter(80)); got != with-1 {
+		t.Errorf("footer: got %d lines without the ticker, want %d", got, with-1)
+	}
+}
+
+func TestPiTickerLine(t *testing.T) {
+	if _, ok := (PiStatus{}).tickerLine(); ok {
+		t.Error("nothing read: got a line")
+	}
+	st := PiStatus{TempC: 61.2, HasTemp: true, Flags: piThrottled | piUnderVoltage<<piOccurred, HasFlags: true}
+	if got, _ := st.tickerLine(); got != "SoC "+FormatNumber(61.2, 1)+"°C · throttled" {
+		t.Errorf("got %q", got)
+	}
+}
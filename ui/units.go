@@ -0,0 +1,153 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ── Number formatting ─────────────────────────────────────────────────────────
+
+// numberFormat is how values are written for people to read: in the TUI,
+// and in the analyze and report text.  Machine formats (the log, JSON,
+// CSV, the metrics endpoints and the plain lines of redirected output)
+// never use it and always write 1234.5 in the units they document.
+type numberFormat struct {
+	si      bool   // bytes in kB/MB/GB (powers of 1000) rather than KiB/MiB/GiB
+	decimal string // decimal separator
+	group   string // thousands separator; "" for none
+}
+
+// display is the format set by SetUnits and SetSeparators, from -units
+// and -locale.  The default is what
+// infgo has always printed: IEC units and a point, without grouping.
+var display = numberFormat{decimal: "."}
+
+// SetUnits writes bytes in SI units (kB, MB, GB) if si, and in IEC units
+// (KiB, MiB, GiB) if not.
+func SetUnits(si bool) { display.si = si }
+
+// SetSeparators writes numbers with the decimal and thousands separators
+// given; a group of "" does not group them.
+func SetSeparators(decimal, group string) { display.decimal, display.group = decimal, group }
+
+// FormatBytes formats b bytes, e.g. "9.80 GiB".
+func FormatBytes(b float64) string { return display.bytes(b) }
+
+// FormatPercent formats p percent with one decimal, e.g. "45.6%".
+func FormatPercent(p float64) string { return display.percent(p) }
+
+// FormatRate formats r per second with one decimal, e.g. "9.9/s".
+func fmtRate(r float64) string { return display.rate(r) }
+
+// humanizeRate formats a count of r a second, e.g. "12345/s", "123.5k/s".
+func humanizeRate(r float64) string { return display.humanRate(r) }
+
+// FormatCount formats a count n grouped in thousands, e.g. "1,048,576":
+// with the -locale separator, or a comma without one.
+func fmtCount(n uint64) string {
+	f := display
+	if f.group == "" {
+		f.group = ","
+	}
+	return f.number(float64(n), 0)
+}
+
+// FormatNumber formats v with prec decimals, e.g. "1,234.50".
+func FormatNumber(v float64, prec int) string { return display.number(v, prec) }
+
+// FormatSigned is FormatNumber with a + on values that are not negative, as
+// %+.2f would write them.
+func FormatSigned(v float64, prec int) string {
+	s := display.number(v, prec)
+	if s[0] != '-' {
+		s = "+" + s
+	}
+	return s
+}
+
+// BytesPerGiB converts the GiB values that readings carry to bytes.
+const BytesPerGiB = 1 << 30
+
+var (
+	iecByteUnits = [...]string{"B", "KiB", "MiB", "GiB", "TiB", "PiB"}
+	siByteUnits  = [...]string{"B", "kB", "MB", "GB", "TB", "PB"}
+)
+
+// bytes picks the largest unit in which b is at least 1, as it would be
+// printed: 1023 bytes stay "1023 B", 1024 are "1.00 KiB".  Whole bytes are
+// printed without decimals.
+func (f numberFormat) bytes(b float64) string {
+	base, units := 1024.0, iecByteUnits
+	if f.si {
+		base, units = 1000, siByteUnits
+	}
+	v, i := b, 0
+	for ; i < len(units)-1; i++ {
+		prec := 2
+		if i == 0 {
+			prec = 0
+		}
+		if round(math.Abs(v), prec) < base {
+			break
+		}
+		v /= base
+	}
+	if i == 0 {
+		return f.number(v, 0) + " B"
+	}
+	return f.number(v, 2) + " " + units[i]
+}
+
+func (f numberFormat) percent(p float64) string { return f.number(p, 1) + "%" }
+
+func (f numberFormat) rate(r float64) string { return f.number(r, 1) + "/s" }
+
+// rateSuffixes are the multiples humanRate writes, from 100k a second.
+var rateSuffixes = [...]string{"k", "M", "G"}
+
+// humanRate writes r a second whole below 100,000, and from there in
+// thousands, millions or billions with one decimal, as it would be
+// printed: 999,960 a second is "1.0M/s", not "1000.0k/s".
+func (f numberFormat) humanRate(r float64) string {
+	if round(math.Abs(r), 0) < 100_000 {
+		return f.number(r, 0) + "/s"
+	}
+	v, i := r/1000, 0
+	for ; i < len(rateSuffixes)-1 && round(math.Abs(v), 1) >= 1000; i++ {
+		v /= 1000
+	}
+	return f.number(v, 1) + rateSuffixes[i] + "/s"
+}
+
+// number formats v with prec decimals and f's separators.
+func (f numberFormat) number(v float64, prec int) string {
+	s := strconv.FormatFloat(v, 'f', prec, 64)
+	sign := ""
+	if s[0] == '-' {
+		sign, s = "-", s[1:]
+	}
+	whole, frac, _ := strings.Cut(s, ".")
+	if f.group != "" && len(whole) > 3 {
+		var b strings.Builder
+		for i, d := range whole {
+			if i > 0 && (len(whole)-i)%3 == 0 {
+				b.WriteString(f.group)
+			}
+			b.WriteRune(d)
+		}
+		whole = b.String()
+	}
+	if frac == "" {
+		return sign + whole
+	}
+	return sign + whole + f.decimal + frac
+}
+
+func round(v float64, prec int) float64 {
+	p := math.Pow10(prec)
+	return math.Round(v*p) / p
+}
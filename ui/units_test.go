@@ -0,0 +1,78 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"testing"
+)
+
+var (
+	iecFormat = numberFormat{decimal: "."}
+	siFormat  = numberFormat{si: true, decimal: "."}
+)
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		b       float64
+		iec, si string
+	}{
+		{0, "0 B", "0 B"},
+		{999, "999 B", "999 B"},
+		{1000, "1000 B", "1.00 kB"},
+		{1023, "1023 B", "1.02 kB"},
+		{1024, "1.00 KiB", "1.02 kB"},
+		{1024*1024 - 1, "1.00 MiB", "1.05 MB"}, // 1023.999 KiB would print as 1024.00
+		{1024 * 1024, "1.00 MiB", "1.05 MB"},
+		{9.8 * (1 << 30), "9.80 GiB", "10.52 GB"},
+		{16 * (1 << 30), "16.00 GiB", "17.18 GB"},
+		{999.999e9, "931.32 GiB", "1.00 TB"},
+		{1 << 40, "1.00 TiB", "1.10 TB"},
+		{4e12, "3.64 TiB", "4.00 TB"},
+		{1 << 60, "1024.00 PiB", "1152.92 PB"},
+		{-2048, "-2.00 KiB", "-2.05 kB"},
+	}
+	for _, tt := range tests {
+		if got := iecFormat.bytes(tt.b); got != tt.iec {
+			t.Errorf("iec %v: got %q, want %q", tt.b, got, tt.iec)
+		}
+		if got := siFormat.bytes(tt.b); got != tt.si {
+			t.Errorf("si %v: got %q, want %q", tt.b, got, tt.si)
+		}
+	}
+}
+
+func TestHumanizeRate(t *testing.T) {
+	tests := []struct {
+		r    float64
+		want string
+	}{
+		{0, "0/s"},
+		{12.4, "12/s"},
+		{12345, "12345/s"},
+		{99_999.4, "99999/s"},
+		{99_999.6, "100.0k/s"},
+		{123_456, "123.5k/s"},
+		{999_940, "999.9k/s"},
+		{999_960, "1.0M/s"},
+		{4_560_000, "4.6M/s"},
+		{2.5e12, "2500.0G/s"},
+	}
+	for _, tt := range tests {
+		if got := iecFormat.humanRate(tt.r); got != tt.want {
+			t.Errorf("humanRate(%v) = %q, want %q", tt.r, got, tt.want)
+		}
+	}
+	if got := (numberFormat{decimal: ",", group: "."}).humanRate(123_456); got != "123,5k/s" {
+		t.Errorf("de: got %q", got)
+	}
+}
+
+func TestRate(t *testing.T) {
+	en := numberFormat{decimal: ".", group: ","}
+	for v, want := range map[float64]string{0: "0.0/s", 999: "999.0/s", 1000: "1,000.0/s", -12345.6: "-12,345.6/s"} {
+		if got := en.rate(v); got != want {
+			t.Errorf("rate %v: got %q, want %q", v, got, want)
+		}
+	}
+}
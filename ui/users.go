@@ -0,0 +1,142 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+const (
+	// usersShown is how many users the panel lists, busiest first.
+	usersShown = 5
+
+	// unknownUser groups the processes whose owner could not be looked
+	// up: a deleted account, or a uid from another container.
+	unknownUser = "unknown"
+)
+
+// ProcInfo is one process of a scan.
+type ProcInfo struct {
+	User string  // "" when the lookup failed
+	CPU  float64 // percent of one core since the previous scan
+	RSS  uint64  // bytes
+
+	PID, PPID int32
+	Name      string // "" when it could not be read
+	Kernel    bool   // a kernel thread: no command line
+}
+
+// userUsage is the processes of one user, summed.
+type userUsage struct {
+	name  string
+	cpu   float64 // percent of one core; may exceed 100
+	rss   uint64
+	procs int
+}
+
+// aggregateUsers sums procs by user and returns the n busiest: by CPU,
+// then memory, then name.
+func aggregateUsers(procs []ProcInfo, n int) []userUsage {
+	byName := map[string]*userUsage{}
+	for _, p := range procs {
+		name := p.User
+		if name == "" {
+			name = unknownUser
+		}
+		u := byName[name]
+		if u == nil {
+			u = &userUsage{name: name}
+			byName[name] = u
+		}
+		u.cpu += p.CPU
+		u.rss += p.RSS
+		u.procs++
+	}
+	out := make([]userUsage, 0, len(byName))
+	for _, u := range byName {
+		out = append(out, *u)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.cpu != b.cpu {
+			return a.cpu > b.cpu
+		}
+		if a.rss != b.rss {
+			return a.rss > b.rss
+		}
+		return a.name < b.name
+	})
+	if len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
+
+// topProcess is the busiest of procs by CPU; the zero ProcInfo if none.
+func topProcess(procs []ProcInfo) ProcInfo {
+	var top ProcInfo
+	for _, p := range procs {
+		if p.CPU > top.CPU || top.PID == 0 {
+			top = p
+		}
+	}
+	return top
+}
+
+// tickerLine is the ticker's process item: the busiest of the last scan.
+func (p ProcInfo) tickerLine() (string, bool) {
+	if p.PID == 0 {
+		return "", false
+	}
+	name := p.Name
+	if name == "" {
+		name = "?"
+	}
+	return fmt.Sprintf("top process %s (%d) · %s CPU · %s", name, p.PID, FormatPercent(p.CPU), FormatBytes(float64(p.RSS))), true
+}
+
+// ProcsMsg carries a scan to Update.
+type ProcsMsg struct{ Procs []ProcInfo }
+
+// renderUsers is the USERS panel.
+func (m Model) renderUsers(w int) string {
+	const (
+		nameW = 12
+		barW  = 10
+	)
+	title := labelSt.Render("USERS")
+	if m.procCount > 0 {
+		title += dimSt.Render(fmt.Sprintf("  %d processes", m.procCount))
+	}
+	title += dimSt.Render("   t tree")
+	lines := []string{title, ""}
+	if m.users == nil {
+		lines = append(lines, dimSt.Render("scanning processes…"))
+	}
+	cores := float64(max(1, m.numCores))
+	for _, u := range m.users {
+		// The bar is the user's share of the whole machine.
+		share := min(100, u.cpu/cores)
+		procs := "procs"
+		if u.procs == 1 {
+			procs = "proc "
+		}
+		lines = append(lines, brightSt.Render(PadVisual(ansi.Truncate(u.name, nameW, "…"), nameW))+"  "+
+			theme.MiniBar(share, barW)+"  "+
+			lipgloss.NewStyle().Foreground(theme.LoadColor(share)).Render(fmt.Sprintf("%7s", FormatPercent(u.cpu)))+"  "+
+			brightSt.Render(fmt.Sprintf("%10s", FormatBytes(float64(u.rss))))+"  "+
+			dimSt.Render(fmt.Sprintf("%4d %s", u.procs, procs)))
+	}
+	return lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(cGray700).
+		Padding(0, 2).
+		Width(w).
+		Render(strings.Join(lines, "\n"))
+}
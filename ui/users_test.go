@@ -0,0 +1,83 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestAggregateUsers(t *testing.T) {
+	tests := []struct {
+		name  string
+		procs []ProcInfo
+		n     int
+		want  []userUsage
+	}{
+		{"none", nil, 5, []userUsage{}},
+		{
+			"summed by user",
+			[]ProcInfo{{User: "alice", CPU: 10, RSS: 100}, {User: "bob", CPU: 5, RSS: 50}, {User: "alice", CPU: 20, RSS: 200}},
+			5,
+			[]userUsage{{"alice", 30, 300, 2}, {"bob", 5, 50, 1}},
+		},
+		{
+			"failed lookups are unknown",
+			[]ProcInfo{{User: "", CPU: 1, RSS: 10}, {User: "root", CPU: 2, RSS: 20}, {User: "", CPU: 3, RSS: 30}},
+			5,
+			[]userUsage{{unknownUser, 4, 40, 2}, {"root", 2, 20, 1}},
+		},
+		{
+			"top n",
+			[]ProcInfo{{User: "a", CPU: 1, RSS: 0}, {User: "b", CPU: 4, RSS: 0}, {User: "c", CPU: 3, RSS: 0}, {User: "d", CPU: 2, RSS: 0}},
+			2,
+			[]userUsage{{"b", 4, 0, 1}, {"c", 3, 0, 1}},
+		},
+		{
+			"ties on CPU go by memory, then name",
+			[]ProcInfo{{User: "z", CPU: 0, RSS: 10}, {User: "y", CPU: 0, RSS: 10}, {User: "x", CPU: 0, RSS: 20}},
+			5,
+			[]userUsage{{"x", 0, 20, 1}, {"y", 0, 10, 1}, {"z", 0, 10, 1}},
+		},
+		{
+			"a user called unknown shares the group",
+			[]ProcInfo{{User: "unknown", CPU: 1, RSS: 1}, {User: "", CPU: 1, RSS: 1}},
+			5,
+			[]userUsage{{unknownUser, 2, 2, 2}},
+		},
+	}
+	for _, tt := range tests {
+		got := aggregateUsers(tt.procs, tt.n)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+// A scan reaches the USERS panel, which -users adds below the others.
+func TestUsersPanel(t *testing.T) {
+	if strings.Contains(ansi.Strip(sizedModel(100, 40).View()), "USERS") {
+		t.Fatal("USERS panel shown without -users")
+	}
+	m := sizedModel(100, 40, WithUsers())
+	if view := ansi.Strip(m.View()); !strings.Contains(view, "scanning processes") {
+		t.Errorf("before the first scan: got\n%s", view)
+	}
+	tm, _ := m.Update(ProcsMsg{Procs: []ProcInfo{{User: "alice", CPU: 150, RSS: 2 << 30}, {User: "", CPU: 3, RSS: 1 << 20}, {User: "alice", CPU: 50, RSS: 0}}})
+	view := ansi.Strip(tm.View())
+	for _, want := range []string{"3 processes", "alice", "200.0%", "2.00 GiB", "2 procs", unknownUser} {
+		if !strings.Contains(view, want) {
+			t.Errorf("missing %q in\n%s", want, view)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// ── Frame caching ─────────────────────────────────────────────────────────────
+
+// View runs on every animation tick, nine times a second, but between stats
+// ticks only the spinner and live dot in the header change.  The panels
+// below it are therefore kept from frame to frame and rendered again only
+// when something they show has changed; the header and footer, which are
+// cheap and carry the animation, are rendered every frame.
+
+// Panel names one of the panels, for WithPanels and the collapse keys.
+type Panel int
+
+const (
+	PanelCPU      Panel = iota
+	PanelMemory         // includes the eased progress bar
+	PanelSystem         // SYSTEM and LOAD AVG side by side
+	PanelUsers          // -users only
+	PanelDisk           // under MEMORY; this host's TUI only
+	PanelIO             // -disk-io only
+	PanelNet            // this host's TUI only
+	PanelProcs          // -procs only
+	PanelTemp           // while there are sensors to show
+	PanelGPU            // while nvidia-smi reports a GPU
+	PanelPID            // -pid only
+	PanelPressure       // once /proc/pressure has been read
+	numPanels
+)
+
+// panelKey is what a cached panel was rendered from.  A panel whose key is
+// unchanged would render identically.
+type panelKey struct {
+	rev   uint64        // model.rev
+	bar   uint64        // model.barRev; memory panel only
+	width int           // inner width
+	age   time.Duration // age of a stale reading, which its title shows
+}
+
+type cachedPanel struct {
+	key   panelKey
+	out   string
+	ok    bool
+	width int // of out's widest line
+
+	// padded is out as it appears in a frame padW columns wide.
+	padded string
+	padW   int
+}
+
+// pad returns c's panel laid out in a frame w columns wide.
+func (c *cachedPanel) pad(w int) string {
+	if c.padded == "" || c.padW != w {
+		var b strings.Builder
+		padLines(&b, c.out, w)
+		c.padded, c.padW = b.String(), w
+	}
+	return c.padded
+}
+
+// viewCache holds the panels of the last frame.  It is shared by the
+// model's copies, like the histories; Bubble Tea calls View and Update
+// from the same goroutine.
+type viewCache [numPanels]cachedPanel
+
+// panel returns panel p at inner width iw, from the cache when its key has
+// not changed.  A model without a cache renders every frame in full.
+func (m Model) panel(p Panel, iw int) string {
+	if m.view == nil {
+		return m.renderPanel(p, iw)
+	}
+	key := m.panelKey(p, iw)
+	c := &m.view[p]
+	if !c.ok || c.key != key {
+		out := m.renderPanel(p, iw)
+		*c = cachedPanel{key: key, out: out, ok: true, width: lipgloss.Width(out)}
+	}
+	return c.out
+}
+
+// frame lays out the frame's blocks, one under the other, as
+// lipgloss.NewStyle().Padding(0, 1) would: each line left-aligned to the
+// widest and given a column of space either side.  Measuring every line
+// is most of the cost of a frame, so the panels are laid out from the
+// cache, and only the header, banner and footer are measured afresh.
+func (m Model) frame(header, banner, footer string) string {
+	w := max(lipgloss.Width(header), lipgloss.Width(banner), lipgloss.Width(footer))
+	for p := range m.view {
+		w = max(w, m.view[p].width)
+	}
+	var b strings.Builder
+	padLines(&b, header, w)
+	b.WriteByte('\n')
+	padLines(&b, banner, w)
+	for _, p := range m.panels() {
+		if p != PanelCPU {
+			b.WriteByte('\n')
+			padLines(&b, "", w)
+		}
+		b.WriteByte('\n')
+		b.WriteString(m.view[p].pad(w))
+	}
+	b.WriteByte('\n')
+	padLines(&b, footer, w)
+	return b.String()
+}
+
+// panels lists the panels of a frame, top to bottom.
+func (m Model) panels() []Panel {
+	ps := []Panel{PanelCPU, PanelMemory}
+	if m.diskPanel {
+		ps = append(ps, PanelDisk)
+	}
+	if m.io != nil {
+		ps = append(ps, PanelIO)
+	}
+	if m.net != nil {
+		ps = append(ps, PanelNet)
+	}
+	if len(m.temps) > 0 {
+		ps = append(ps, PanelTemp)
+	}
+	if len(m.gpus) > 0 {
+		ps = append(ps, PanelGPU)
+	}
+	if m.hasPressure {
+		ps = append(ps, PanelPressure)
+	}
+	if len(m.pids) > 0 {
+		ps = append(ps, PanelPID)
+	}
+	ps = append(ps, PanelSystem)
+	if m.showUsers {
+		ps = append(ps, PanelUsers)
+	}
+	if m.showProcs {
+		ps = append(ps, PanelProcs)
+	}
+	if m.pane != nil {
+		ps = slices.DeleteFunc(ps, func(p Panel) bool { return !slices.Contains(m.pane, p) })
+	}
+	return ps
+}
+
+// padLines writes the lines of s to b, each padded to w columns with a
+// space either side.
+func padLines(b *strings.Builder, s string, w int) {
+	for i, line := range strings.Split(s, "\n") {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteByte(' ')
+		b.WriteString(line)
+		b.WriteString(strings.Repeat(" ", w-ansi.StringWidth(line)+1))
+	}
+}
+
+func (m Model) panelKey(p Panel, iw int) panelKey {
+	key := panelKey{rev: m.rev, width: iw}
+	switch p {
+	case PanelCPU:
+		key.age = m.staleAge(metrics.MissingCPU, m.cpuSeen)
+	case PanelMemory:
+		key.bar = m.barRev
+		key.age = m.staleAge(metrics.MissingMem, m.memSeen)
+	case PanelSystem:
+		key.age = m.staleAge(metrics.MissingLoad, m.loadSeen)
+	}
+	return key
+}
+
+func (m Model) renderPanel(p Panel, iw int) string {
+	if m.collapsed[p] {
+		return m.renderCollapsed(p, iw)
+	}
+	switch p {
+	case PanelCPU:
+		return m.renderCPU(iw)
+	case PanelMemory:
+		return m.renderMemory(iw)
+	case PanelDisk:
+		return m.renderDisk(iw)
+	case PanelIO:
+		return m.renderIO(iw)
+	case PanelNet:
+		return m.renderNet(iw)
+	case PanelProcs:
+		return m.renderProcs(iw + 4)
+	case PanelTemp:
+		return m.renderTemps(iw)
+	case PanelGPU:
+		return m.renderGPU(iw)
+	case PanelPID:
+		return m.renderPids(iw + 4)
+	case PanelPressure:
+		return m.renderPressure(iw)
+	case PanelUsers:
+		if m.procTree {
+			return m.renderProcTree(iw + 4)
+		}
+		return m.renderUsers(iw + 4)
+	default:
+		return m.renderBottom(iw)
+	}
+}
+
+// renderBottom is the bottom row: system info (wider) and load averages
+// (narrower) side by side.
+func (m Model) renderBottom(iw int) string {
+	sysW := (iw+4)*56/100 - 2
+	loadW := iw + 4 - sysW - 3
+	return lipgloss.JoinHorizontal(lipgloss.Top,
+		m.renderSystem(sysW),
+		"  ",
+		m.renderLoad(loadW),
+	)
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/progress"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// Every frame rendered from the cache matches one rendered in full.
+func TestViewCacheMatchesFullRender(t *testing.T) {
+	m := New()
+	busy(m, true) // keep statsTickMsg from reading this machine
+	t0 := time.Now()
+	stats := benchStats()
+	stats.Load1, stats.At = 2.5, t0.Add(-5500*time.Millisecond)
+
+	var bar tea.Cmd
+	steps := []struct {
+		name string
+		msg  tea.Msg
+	}{
+		{"resize", tea.WindowSizeMsg{Width: 120, Height: 40}},
+		{"sysinfo", SysInfo{Hostname: "box", Platform: "linux", Uptime: 3600, NumCores: 16}},
+		{"stats", stats},
+		{"anim", animTickMsg{id: m.id, t: t0}},
+		{"bar", nil}, // a frame of the memory bar's easing
+		{"anim", animTickMsg{id: m.id, t: t0}},
+		{"tick", statsTickMsg{id: m.id, t: t0}},
+		{"stale mem", Reading{CPUTotal: 70, CPUCores: []float64{70}, Missing: metrics.MissingMem, TimedOut: metrics.MissingMem}},
+		{"anim", animTickMsg{id: m.id, t: t0}},
+		{"narrow", tea.WindowSizeMsg{Width: 70, Height: 30}},
+		{"anim", animTickMsg{id: m.id, t: t0}},
+		{"bar", nil},
+	}
+	var tm tea.Model = m
+	for i, step := range steps {
+		msg := step.msg
+		if step.name == "bar" {
+			if bar == nil {
+				t.Fatalf("step %d: the memory bar is not animating", i)
+			}
+			msg = bar()
+			if _, ok := msg.(progress.FrameMsg); !ok {
+				t.Fatalf("step %d: got %T from the bar, want a FrameMsg", i, msg)
+			}
+		}
+		var cmd tea.Cmd
+		tm, cmd = tm.Update(msg)
+		if _, ok := msg.(Reading); ok || step.name == "bar" {
+			bar = cmd
+		}
+		got := tm.(Model)
+		if cached, full := got.View(), fullView(got); cached != full {
+			t.Fatalf("step %d (%s): cached frame differs:\n%s\nfull:\n%s", i, step.name, cached, full)
+		}
+	}
+}
+
+// An animation tick reuses the panels; a reading renders them again.
+func TestViewCacheReuse(t *testing.T) {
+	var tm tea.Model = New()
+	tm, _ = tm.Update(benchStats())
+	m := tm.(Model)
+	_ = m.View()
+	for p := range m.view {
+		m.view[p].out, m.view[p].padded = fmt.Sprintf("<panel %d>", p), ""
+	}
+
+	tm, _ = tm.Update(animTickMsg{id: m.id, t: time.Now()})
+	view := tm.View()
+	for _, p := range m.panels() {
+		if !strings.Contains(view, fmt.Sprintf("<panel %d>", p)) {
+			t.Errorf("panel %d was rendered again on an animation tick", p)
+		}
+	}
+
+	tm, _ = tm.Update(benchStats())
+	if view := tm.View(); strings.Contains(view, "<panel") {
+		t.Errorf("a reading left cached panels in the frame:\n%s", view)
+	}
+}
+
+// BenchmarkFrames renders 1000 frames, with and without the panel cache:
+// "idle" has no fresh stats between them; "live" has a reading every
+// fourth frame, about the ratio of the stats and animation ticks.
+func BenchmarkFrames(b *testing.B) {
+	for _, every := range []int{0, 4} {
+		name := "idle"
+		if every > 0 {
+			name = "live"
+		}
+		for _, cached := range []bool{true, false} {
+			mode := "cached"
+			if !cached {
+				mode = "full"
+			}
+			b.Run(name+"/"+mode, func(b *testing.B) {
+				m := New()
+				if !cached {
+					m.view = nil
+				}
+				var tm tea.Model = m
+				tm, _ = tm.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+				msg := benchStats()
+				tm, _ = tm.Update(msg)
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					for f := range 1000 {
+						if every > 0 && f%every == 0 {
+							msg.CPUTotal = float64(f % 100)
+							tm, _ = tm.Update(msg)
+						}
+						tm, _ = tm.Update(animTickMsg{})
+						_ = tm.View()
+					}
+				}
+			})
+		}
+	}
+}
+
+// fullView renders m without its cache.
+func fullView(m Model) string {
+	m.view = nil
+	return m.View()
+}
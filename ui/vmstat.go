@@ -0,0 +1,65 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// majorFaultCrit is the major faults a second past which the row is red:
+// a handful is a program starting, hundreds the working set not fitting.
+const majorFaultCrit = 100
+
+// FaultCounters are the page faults since boot, and the major ones among
+// them.
+type FaultCounters struct {
+	All, Major uint64
+}
+
+// FaultMeter is the model's state for the faults row.
+type FaultMeter struct {
+	prev       FaultCounters
+	prevAt     time.Time
+	all, major float64
+	OK         bool // all and major hold a rate
+}
+
+// observe folds in a reading at at; a zero at is a reading without the
+// counters, which leaves the rates as they were.
+func (f *FaultMeter) Observe(c FaultCounters, at time.Time) {
+	if at.IsZero() {
+		return
+	}
+	f.OK = false
+	if !f.prevAt.IsZero() {
+		all, okA := counterRate(f.prev.All, c.All, at.Sub(f.prevAt))
+		major, okM := counterRate(f.prev.Major, c.Major, at.Sub(f.prevAt))
+		f.all, f.major, f.OK = all, major, okA && okM
+	}
+	f.prev, f.prevAt = c, at
+}
+
+// put records the rates in s.
+func (f *FaultMeter) Put(s *metrics.Sample) {
+	s.PageFaultsPerSec, s.MajorFaultsPerSec = f.all, f.major
+}
+
+// text is the faults row, "faults 120/s (maj 4/s)", with "—" for the rates
+// until there are two readings to take them from.
+func (f *FaultMeter) text() string {
+	all, major := "—", "—"
+	majSt := brightSt
+	if f.OK {
+		all, major = humanizeRate(f.all), humanizeRate(f.major)
+		if f.major > majorFaultCrit {
+			majSt = lipgloss.NewStyle().Foreground(cRed).Bold(true)
+		}
+	}
+	return dimSt.Render("faults ") + brightSt.Render(all) +
+		dimSt.Render(" (maj ") + majSt.Render(major) + dimSt.Render(")")
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// The MEMORY panel has the faults row once the counters are read, the
+// major faults red past majorFaultCrit, and each sample carries the rates.
+func TestFaultsRow(t *testing.T) {
+	inColour(t)
+	m := sizedModel(100, 50)
+	rec := new(sinks)
+	m.rec = rec
+	if got := ansi.Strip(m.renderMemory(innerWidth(100))); strings.Contains(got, "faults") {
+		t.Errorf("without the counters: got\n%s", got)
+	}
+	at := time.Unix(1700000000, 0)
+	for i, want := range []string{"faults — (maj —)", "faults 120/s (maj 4/s)", "faults 1500/s (maj 400/s)"} {
+		c := FaultCounters{All: []uint64{1000, 1120, 2620}[i], Major: []uint64{10, 14, 414}[i]}
+		tm, _ := m.Update(Reading{CPUTotal: 10, MemPercent: 40, Faults: c, FaultsAt: at})
+		m = tm.(Model)
+		if got := ansi.Strip(m.renderMemory(innerWidth(100))); !strings.Contains(got, want) {
+			t.Errorf("reading %d: got\n%s", i, got)
+		}
+		at = at.Add(time.Second)
+	}
+	red := lipgloss.NewStyle().Foreground(cRed).Bold(true)
+	if got := m.faults.text(); !strings.Contains(got, red.Render("400/s")) {
+		t.Errorf("thrashing: got %q", got)
+	}
+	m.faults.major = majorFaultCrit
+	if got := m.faults.text(); strings.Contains(got, red.Render(humanizeRate(majorFaultCrit))) {
+		t.Errorf("at the threshold: got %q", got)
+	}
+	samples := rec.samples
+	if len(samples) != 3 || samples[0].PageFaultsPerSec != 0 || samples[1].PageFaultsPerSec != 120 || samples[2].MajorFaultsPerSec != 400 {
+		t.Errorf("logged: got %+v", samples)
+	}
+}
+
+// The rates are the deltas over the time between readings, none for the
+// first reading or across a reset, and a reading without the counters
+// keeps the last.
+func TestFaultMeter(t *testing.T) {
+	var f FaultMeter
+	at := time.Unix(1700000000, 0)
+	for i, s := range []struct {
+		dt         time.Duration
+		c          FaultCounters
+		all, major float64
+		ok         bool
+	}{
+		{0, FaultCounters{1000, 10}, 0, 0, false},
+		{time.Second, FaultCounters{1120, 14}, 120, 4, true},
+		{2 * time.Second, FaultCounters{1320, 34}, 100, 10, true}, // late
+		{time.Second, FaultCounters{20, 0}, 0, 0, false},          // reset
+		{time.Second, FaultCounters{50, 1}, 30, 1, true},
+	} {
+		at = at.Add(s.dt)
+		f.Observe(s.c, at)
+		if f.OK != s.ok || (f.OK && (f.all != s.all || f.major != s.major)) {
+			t.Errorf("reading %d: got %v, %v, %v", i, f.all, f.major, f.OK)
+		}
+	}
+	f.Observe(FaultCounters{}, time.Time{})
+	if !f.OK || f.all != 30 {
+		t.Errorf("no counters: got %v, %v", f.all, f.OK)
+	}
+}
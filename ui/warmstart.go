@@ -0,0 +1,101 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"time"
+
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ring"
+)
+
+// warmDivider marks on the sparklines where the restart fell.
+const warmDivider = "┊"
+
+// Warm carries the samples read back, oldest first, and the interval
+// of the session that recorded the last of them; zero if unknown.
+type Warm struct {
+	Samples  []metrics.Sample
+	Interval time.Duration
+}
+
+// warm puts the samples read back ahead of the points in the histories,
+// folded as the live readings are, and takes the peaks and core-seconds
+// among them.  The points already shown keep their places at the end.
+func (m Model) warm(msg Warm) Model {
+	m.rev++
+	live := m.histTrail.Samples(&m.cpuHistory, &m.memHistory, true)
+	m.cpuHistory, m.memHistory = ring.New(HistoryLen), ring.New(HistoryLen)
+	m.cpuHistory.Fill(0)
+	m.memHistory.Fill(0)
+	m.histTrail = NewHistTrail(HistoryLen)
+
+	gap := 5 * max(msg.Interval, m.sched.interval)
+	every := displayEvery(m.sched.interval)
+	var (
+		p        pendingReadings
+		cpu, mem float64
+		cores    []float64
+		at       time.Time
+		secs     coreMeter
+		points   uint64
+		cpuPeak  float64
+		memPeak  float64
+		cpuSeq   uint64
+		memSeq   uint64
+	)
+	push := func() {
+		m.cpuHistory.Push(point(p.cpu, p.nCPU, cpu))
+		m.memHistory.Push(point(p.mem, p.nMem, mem))
+		m.histTrail.Push(at, cores)
+		points++
+		p = pendingReadings{}
+	}
+	for _, s := range msg.Samples {
+		at = time.UnixMilli(s.TimestampUnixMs)
+		if !s.Missing.Has(metrics.MissingCPU) {
+			cpu, cores = s.CpuTotal, s.CpuCores
+			if cpu > cpuPeak {
+				cpuPeak, cpuSeq = cpu, points+1
+			}
+			secs.add(cpu, max(len(cores), 1), at, gap)
+		}
+		if !s.Missing.Has(metrics.MissingMem) {
+			mem = s.MemPercent
+			if mem > memPeak {
+				memPeak, memSeq = mem, points+1
+			}
+		}
+		if p.add(Reading{CPUTotal: s.CpuTotal, MemPercent: s.MemPercent, Missing: s.Missing}); p.n >= every {
+			push()
+		}
+	}
+	if p.n > 0 {
+		push()
+	}
+
+	// The points shown so far follow, renumbered after the ones read back.
+	for _, s := range live {
+		m.cpuHistory.Push(s.CpuTotal)
+		m.memHistory.Push(s.MemPercent)
+		m.histTrail.Push(time.UnixMilli(s.TimestampUnixMs), s.CpuCores)
+	}
+	if m.cpuPeakSeq > 0 {
+		m.cpuPeakSeq += points
+	}
+	if m.memPeakSeq > 0 {
+		m.memPeakSeq += points
+	}
+	m.histSeq += points
+	m.warmSeq = points
+	if cpuPeak > m.cpuPeak {
+		m.cpuPeak, m.cpuPeakSeq = cpuPeak, cpuSeq
+	}
+	if memPeak > m.memPeak {
+		m.memPeak, m.memPeakSeq = memPeak, memSeq
+	}
+	m.coreSecs.seconds += secs.seconds
+	m.rec.Points(m.histTrail.Samples(&m.cpuHistory, &m.memHistory, true))
+	return m
+}
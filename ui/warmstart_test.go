@@ -0,0 +1,99 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ring"
+)
+
+// The divider takes the place of the oldest reading shown, and goes once
+// the last reading before it has scrolled out of view.
+func TestSparklineDivider(t *testing.T) {
+	h := ring.New(6)
+	for _, v := range []float64{0, 20, 40, 60, 80, 100} {
+		h.Push(v)
+	}
+	r := func(vs ...float64) string {
+		var b strings.Builder
+		for _, v := range vs {
+			b.WriteRune(SparkRune(v))
+		}
+		return b.String()
+	}
+	tests := []struct {
+		width, split int
+		want         string
+	}{
+		{6, 2, r(20, 40, 60) + warmDivider + r(80, 100)},
+		{6, 0, r(20, 40, 60, 80, 100) + warmDivider},
+		{6, 4, r(20) + warmDivider + r(40, 60, 80, 100)},
+		{6, 5, r(0, 20, 40, 60, 80, 100)},
+		{4, 3, r(40, 60, 80, 100)},
+		{8, 3, r(0, 20, 40) + warmDivider + r(60, 80, 100)},
+		{6, -1, r(0, 20, 40, 60, 80, 100)},
+	}
+	for _, tt := range tests {
+		got := ansi.Strip(tintedSparkline(&h, tt.width, cCyan, -1, 0, tt.split))
+		if got != tt.want {
+			t.Errorf("width %d, split %d: got %q, want %q", tt.width, tt.split, got, tt.want)
+		}
+	}
+}
+
+// A warm start continues the sparklines, the peaks and the core-seconds
+// from the capture, ahead of the readings taken meanwhile.
+func TestWarmStart(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	warm := Warm{Interval: time.Second}
+	for i := range 30 {
+		s := metrics.Sample{TimestampUnixMs: t0.Add(time.Duration(i) * time.Second).UnixMilli(),
+			CpuTotal: 10, CpuCores: []float64{10, 10}, MemPercent: 40}
+		if i == 20 {
+			s.CpuTotal = 90
+		}
+		warm.Samples = append(warm.Samples, s)
+	}
+
+	var tm tea.Model = New()
+	restart := t0.Add(time.Minute)
+	for i := range 2 {
+		tm, _ = tm.Update(Reading{CPUTotal: 50, CPUCores: []float64{50, 50}, MemPercent: 45, At: restart.Add(time.Duration(i) * time.Second)})
+	}
+	tm, _ = tm.Update(warm)
+	m := tm.(Model)
+
+	if v, _ := m.cpuHistory.Last(0); v != 50 {
+		t.Errorf("newest point: got %v, want the live 50", v)
+	}
+	if v, _ := m.cpuHistory.Last(2); v != 10 {
+		t.Errorf("the point before the restart: got %v, want 10", v)
+	}
+	if m.cpuPeak != 90 || m.pointsSince(m.cpuPeakSeq) != 11 || m.memPeak != 45 || m.pointsSince(m.memPeakSeq) != 1 {
+		t.Errorf("peaks: got %v (%d back) and %v (%d back)", m.cpuPeak, m.pointsSince(m.cpuPeakSeq), m.memPeak, m.pointsSince(m.memPeakSeq))
+	}
+	if m.pointsSince(m.warmSeq) != 2 {
+		t.Errorf("divider: got %d points since, want 2", m.pointsSince(m.warmSeq))
+	}
+	// 29 seconds at 10 % of two cores, 80 % more for the one at 90 %, and
+	// the live second at 50 %.
+	if got := m.coreSecs.seconds; got < 8.39 || got > 8.41 {
+		t.Errorf("core-seconds: got %v, want 8.4", got)
+	}
+	points := m.histTrail.Samples(&m.cpuHistory, &m.memHistory, false)
+	if len(points) != 32 || points[29].TimestampUnixMs != t0.Add(29*time.Second).UnixMilli() || points[30].TimestampUnixMs != restart.UnixMilli() {
+		t.Errorf("got %d points", len(points))
+	}
+	spark := ansi.Strip(tintedSparkline(&m.cpuHistory, 20, cViolet, -1, 0, m.pointsSince(m.warmSeq)))
+	if !strings.HasSuffix(spark, warmDivider+"▅▅") {
+		t.Errorf("sparkline: got %q", spark)
+	}
+}
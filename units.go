@@ -0,0 +1,80 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+// localeSeparators are the decimal and thousands separators by language.
+// Languages that group with a space use a no-break space, so that a
+// number is never split across lines.
+var localeSeparators = map[string][2]string{}
+
+func init() {
+	for seps, langs := range map[[2]string][]string{
+		{".", ""}:       {"c", "posix"},
+		{".", ","}:      {"en", "ja", "ko", "zh", "he", "th", "hi"},
+		{",", "."}:      {"de", "nl", "it", "es", "pt", "da", "id", "tr", "el", "ro", "sl", "hr"},
+		{",", "\u00a0"}: {"fr", "ru", "pl", "cs", "sk", "sv", "fi", "nb", "nn", "no", "uk", "hu", "bg", "et", "lt", "lv"},
+	} {
+		for _, l := range langs {
+			localeSeparators[l] = seps
+		}
+	}
+}
+
+// parseLocale returns the separators for a locale name such as de_DE.UTF-8
+// or fr-CA.  "auto" takes the name from $LC_ALL, $LC_NUMERIC or $LANG and
+// falls back to the default for one it does not know.
+func parseLocale(name string) (decimal, group string, err error) {
+	auto := name == "auto"
+	if auto {
+		name = "C"
+		for _, env := range []string{"LC_ALL", "LC_NUMERIC", "LANG"} {
+			if v := os.Getenv(env); v != "" {
+				name = v
+				break
+			}
+		}
+	}
+	lang, _, _ := strings.Cut(strings.ToLower(name), ".")
+	lang, _, _ = strings.Cut(lang, "@")
+	if i := strings.IndexAny(lang, "_-"); i >= 0 {
+		lang = lang[:i]
+	}
+	seps, ok := localeSeparators[lang]
+	switch {
+	case ok:
+		return seps[0], seps[1], nil
+	case auto:
+		return ".", "", nil
+	}
+	return "", "", fmt.Errorf("unknown locale %q", name)
+}
+
+// addFormatFlags registers -units and -locale on fs; they set the
+// units and separators of the panels.
+func addFormatFlags(fs *flag.FlagSet) {
+	fs.Func("units", "show bytes in `si` units (kB, MB, GB) or iec units (KiB, MiB, GiB; the default)", func(v string) error {
+		if v != "si" && v != "iec" {
+			return fmt.Errorf("want si or iec")
+		}
+		ui.SetUnits(v == "si")
+		return nil
+	})
+	fs.Func("locale", "separate decimals and thousands as in `locale`, e.g. de_DE, or auto for $LC_NUMERIC (default 1234.5)", func(v string) error {
+		dec, group, err := parseLocale(v)
+		if err != nil {
+			return err
+		}
+		ui.SetSeparators(dec, group)
+		return nil
+	})
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"testing"
+
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ui"
+)
+
+func TestFormatLocale(t *testing.T) {
+	tests := []struct {
+		locale  string
+		number  string // 1234567.891 to two places
+		percent string // 45.67
+		bytes   string // 1.5 KiB
+	}{
+		{"C", "1234567.89", "45.7%", "1.50 KiB"},
+		{"en_US.UTF-8", "1,234,567.89", "45.7%", "1.50 KiB"},
+		{"de_DE.UTF-8", "1.234.567,89", "45,7%", "1,50 KiB"},
+		{"fr-CA", "1 234 567,89", "45,7%", "1,50 KiB"},
+		{"pt_BR@euro", "1.234.567,89", "45,7%", "1,50 KiB"},
+	}
+	defer ui.SetSeparators(".", "")
+	for _, tt := range tests {
+		dec, group, err := parseLocale(tt.locale)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.locale, err)
+		}
+		ui.SetSeparators(dec, group)
+		if got := ui.FormatNumber(1234567.891, 2); got != tt.number {
+			t.Errorf("%s: number got %q, want %q", tt.locale, got, tt.number)
+		}
+		if got := ui.FormatPercent(45.67); got != tt.percent {
+			t.Errorf("%s: percent got %q, want %q", tt.locale, got, tt.percent)
+		}
+		if got := ui.FormatBytes(1536); got != tt.bytes {
+			t.Errorf("%s: bytes got %q, want %q", tt.locale, got, tt.bytes)
+		}
+	}
+
+	if _, _, err := parseLocale("xx_YY"); err == nil {
+		t.Error("an unknown locale was accepted")
+	}
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_NUMERIC", "xx_YY")
+	if dec, group, err := parseLocale("auto"); err != nil || dec != "." || group != "" {
+		t.Errorf("auto with an unknown $LC_NUMERIC: got %q %q %v, want the default", dec, group, err)
+	}
+	t.Setenv("LC_NUMERIC", "de_AT.UTF-8")
+	if dec, group, _ := parseLocale("auto"); dec != "," || group != "." {
+		t.Errorf("auto with de_AT: got %q %q", dec, group)
+	}
+}
+
+// The display setting is for people; the plain lines stay machine-readable.
+func TestFormatLeavesPlainLines(t *testing.T) {
+	s := metrics.Sample{TimestampUnixMs: 1, CpuTotal: 1234.5, MemPercent: 50, MemUsedGB: 8, MemTotalGB: 16}
+	want := plainLine(s)
+	defer ui.SetUnits(false)
+	defer ui.SetSeparators(".", "")
+	ui.SetUnits(true)
+	ui.SetSeparators(",", ".")
+	if got := plainLine(s); got != want {
+		t.Errorf("with -units si -locale de: got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,219 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ── Segment upload (-upload) ──────────────────────────────────────────────────
+
+const (
+	// uploadQueue is how many closed segments may wait for upload before
+	// further ones are left on disk unsent.
+	uploadQueue = 64
+
+	// Failed uploads are retried for as long as the collector runs,
+	// backing off from uploadRetryMin to uploadRetryMax.
+	uploadRetryMin = 2 * time.Second
+	uploadRetryMax = 5 * time.Minute
+
+	// uploadDrain is how long shutdown waits for queued segments to go up.
+	uploadDrain = 30 * time.Second
+)
+
+// uploadConfig holds the -upload* flags.
+type uploadConfig struct {
+	target   string // s3://bucket/prefix
+	endpoint string // S3-compatible service; empty for AWS
+	keep     int    // uploaded segments to keep on disk
+}
+
+func (c uploadConfig) enabled() bool { return c.target != "" }
+
+// uploader sends the log segments closed by rotation to S3 on its own
+// goroutine, so a slow or unreachable bucket never holds up logging.
+// Objects are named <prefix>/<hostname>/<segment file name>[.gz].
+type uploader struct {
+	s3   *s3Client
+	host string
+	keep int
+	errs io.Writer
+
+	retryMin, retryMax, drain time.Duration
+
+	queue  chan string
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	// Owned by run.
+	kept    []string // uploaded segments still on disk, oldest first
+	pending []string // segments never uploaded, reported on close
+}
+
+func newUploader(cfg uploadConfig, host string, errs io.Writer) (*uploader, error) {
+	creds, err := awsCredentialsFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	s3, err := newS3Client(cfg.target, cfg.endpoint, creds)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.keep < 0 {
+		return nil, errors.New("-upload-keep must not be negative")
+	}
+	u := &uploader{
+		s3: s3, host: host, keep: cfg.keep, errs: errs,
+		retryMin: uploadRetryMin, retryMax: uploadRetryMax, drain: uploadDrain,
+		queue: make(chan string, uploadQueue),
+		done:  make(chan struct{}),
+	}
+	u.ctx, u.cancel = context.WithCancel(context.Background())
+	return u, nil
+}
+
+// start runs the uploader on its own goroutine until close.
+func (u *uploader) start() {
+	go u.run()
+}
+
+// enqueue hands a closed segment to the uploader without blocking.  It is
+// the headless collector's rotation hook.
+func (u *uploader) enqueue(path string) {
+	select {
+	case u.queue <- path:
+	default:
+		fmt.Fprintf(u.errs, "infgo: upload: queue full; %s stays on disk\n", path)
+	}
+}
+
+// close gives the queued segments up to u.drain to go up, then abandons
+// the rest and reports them; they stay on disk to be uploaded by hand.
+func (u *uploader) close() {
+	if u == nil {
+		return
+	}
+	close(u.queue)
+	select {
+	case <-u.done:
+	case <-time.After(u.drain):
+		u.cancel()
+		<-u.done
+	}
+	u.cancel()
+	if len(u.pending) > 0 {
+		fmt.Fprintf(u.errs, "infgo: upload: %d segments not uploaded: %s\n", len(u.pending), strings.Join(u.pending, ", "))
+	}
+}
+
+func (u *uploader) run() {
+	defer close(u.done)
+	for path := range u.queue {
+		if u.ctx.Err() != nil || !u.upload(path) {
+			u.pending = append(u.pending, path)
+		}
+	}
+}
+
+// upload sends one segment, retrying until it succeeds, fails permanently
+// or the uploader is closed, and then applies the retention.
+func (u *uploader) upload(path string) bool {
+	body, name, err := segmentBody(path)
+	if err != nil {
+		fmt.Fprintf(u.errs, "infgo: upload %s: %v\n", path, err)
+		return false
+	}
+	key := u.s3.key(u.host + "/" + name)
+	delay := u.retryMin
+	for attempt := 1; ; attempt++ {
+		err = u.putVerified(key, body)
+		if err == nil {
+			break
+		}
+		if u.ctx.Err() != nil {
+			return false
+		}
+		if !s3Retryable(err) {
+			fmt.Fprintf(u.errs, "infgo: upload %s: %v; giving up\n", path, err)
+			return false
+		}
+		if attempt == 1 {
+			fmt.Fprintf(u.errs, "infgo: upload %s: %v; retrying\n", path, err)
+		}
+		select {
+		case <-time.After(delay):
+		case <-u.ctx.Done():
+			return false
+		}
+		delay = min(delay*2, u.retryMax)
+	}
+	u.retain(path)
+	return true
+}
+
+// putVerified uploads body and checks that the service stored it intact:
+// the ETag of a single-part upload is the body's MD5.  Buckets encrypted
+// with KMS return some other ETag, so the stored size is compared instead.
+func (u *uploader) putVerified(key string, body []byte) error {
+	etag, err := u.s3.put(u.ctx, key, body, "application/octet-stream")
+	if err != nil {
+		return err
+	}
+	sum := md5.Sum(body)
+	etag = strings.Trim(etag, `"`)
+	if etag == hex.EncodeToString(sum[:]) {
+		return nil
+	}
+	if len(etag) == 2*md5.Size && !strings.Contains(etag, "-") {
+		if _, err := hex.DecodeString(etag); err == nil {
+			return fmt.Errorf("ETag %s does not match the MD5 of the %d bytes sent", etag, len(body))
+		}
+	}
+	size, err := u.s3.size(u.ctx, key)
+	if err != nil {
+		return err
+	}
+	if size != int64(len(body)) {
+		return fmt.Errorf("stored object is %d bytes, sent %d", size, len(body))
+	}
+	return nil
+}
+
+// retain deletes uploaded segments beyond the newest u.keep.
+func (u *uploader) retain(path string) {
+	u.kept = append(u.kept, path)
+	for len(u.kept) > u.keep {
+		if err := os.Remove(u.kept[0]); err != nil && !os.IsNotExist(err) {
+			fmt.Fprintf(u.errs, "infgo: upload: remove %s: %v\n", u.kept[0], err)
+		}
+		u.kept = u.kept[1:]
+	}
+}
+
+// segmentBody reads the segment at path and gzips it unless it is already
+// compressed.  name is the object name: the file name, plus .gz if this
+// compressed it.
+func segmentBody(path string) (body []byte, name string, err error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", err
+	}
+	name = filepath.Base(path)
+	if bytes.HasPrefix(raw, []byte{0x1f, 0x8b}) || bytes.HasPrefix(raw, []byte{0x28, 0xb5, 0x2f, 0xfd}) {
+		return raw, name, nil // gzip or zstd already
+	}
+	return gzipBytes(raw), name + ".gz", nil
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"os/user"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/shirou/gopsutil/v3/process"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+// ── Users panel ───────────────────────────────────────────────────────────────
+
+// -users adds a USERS panel answering "who is using this box?": the CPU
+// and resident memory of every process, summed by the user running it.
+// Enumerating processes costs far more than the other readings, so it is
+// done on its own, slower tick, away from the stats ticks.
+
+// procInterval is the time between process scans.
+const procInterval = 2 * time.Second
+
+// procTimes is a process's CPU time at a scan.  create tells a process
+// from a later one that was given the same pid.
+type procTimes struct {
+	create int64   // ms since the epoch
+	cpu    float64 // user + system seconds
+}
+
+// procScanner enumerates the processes.  It keeps the CPU times of the
+// previous scan, which a process's share over the interval is worked out
+// from, and the user names of the uids it has seen.  Scans run one at a
+// time, off the Update goroutine.
+type procScanner struct {
+	busy  atomic.Bool
+	last  time.Time
+	times map[int32]procTimes
+	names map[int32]string // "" for a uid that could not be looked up
+}
+
+func newProcScanner() *procScanner {
+	return &procScanner{times: map[int32]procTimes{}, names: map[int32]string{}}
+}
+
+type procTickMsg time.Time
+
+func procTick() tea.Cmd {
+	return tea.Tick(procInterval, func(t time.Time) tea.Msg { return procTickMsg(t) })
+}
+
+// scanCmd starts a scan, or returns nil while the previous one runs.
+func (s *procScanner) scanCmd(ctx context.Context) tea.Cmd {
+	if !s.busy.CompareAndSwap(false, true) {
+		return nil
+	}
+	return func() tea.Msg {
+		defer s.busy.Store(false)
+		return ui.ProcsMsg{Procs: s.scan(ctx, time.Now())}
+	}
+}
+
+// scan reads every process.  One that exits during the scan is left out.
+// A process not seen by the previous scan is given its average since it
+// started.
+func (s *procScanner) scan(ctx context.Context, now time.Time) []ui.ProcInfo {
+	ps, err := process.ProcessesWithContext(ctx)
+	if err != nil {
+		return nil
+	}
+	elapsed := now.Sub(s.last).Seconds()
+	times := make(map[int32]procTimes, len(ps))
+	out := make([]ui.ProcInfo, 0, len(ps))
+	for _, p := range ps {
+		t, err := p.TimesWithContext(ctx)
+		if err != nil {
+			continue
+		}
+		mem, err := p.MemoryInfoWithContext(ctx)
+		if err != nil {
+			continue
+		}
+		create, _ := p.CreateTimeWithContext(ctx)
+		cur := procTimes{create: create, cpu: t.User + t.System}
+		times[p.Pid] = cur
+
+		ppid, _ := p.PpidWithContext(ctx)
+		name, _ := p.NameWithContext(ctx)
+		cmdline, err := p.CmdlineWithContext(ctx)
+		info := ui.ProcInfo{User: s.userOf(ctx, p), RSS: mem.RSS, PID: p.Pid, PPID: ppid, Name: name,
+			Kernel: err == nil && cmdline == ""}
+		if prev, ok := s.times[p.Pid]; ok && prev.create == create && elapsed > 0 {
+			info.CPU = (cur.cpu - prev.cpu) / elapsed * 100
+		} else if age := now.Sub(time.UnixMilli(create)).Seconds(); create > 0 && age > 0 {
+			info.CPU = cur.cpu / age * 100
+		}
+		info.CPU = max(0, info.CPU)
+		out = append(out, info)
+	}
+	s.times, s.last = times, now
+	return out
+}
+
+// userOf is the name of p's real user, or "" if it cannot be found.
+func (s *procScanner) userOf(ctx context.Context, p *process.Process) string {
+	uids, err := p.UidsWithContext(ctx)
+	if err != nil || len(uids) == 0 {
+		return ""
+	}
+	uid := uids[0]
+	name, ok := s.names[uid]
+	if !ok {
+		if u, err := user.LookupId(strconv.Itoa(int(uid))); err == nil {
+			name = u.Username
+		}
+		s.names[uid] = name
+	}
+	return name
+}
@@ -0,0 +1,276 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+// ── Start-up validation ───────────────────────────────────────────────────────
+//
+// The main flags are checked together once they are parsed, before
+// anything is started or the terminal touched.  Every combination that
+// contradicts itself, or that would silently do nothing, is reported at
+// once, each problem naming its flags, and infgo exits 2.
+
+// startFlags is what the validation pass checks: the main flags as parsed.
+type startFlags struct {
+	set map[string]bool // the flags given on the command line
+
+	headless, plain bool // no TUI: -headless, or stdout is not a terminal
+
+	logPath, logDir string // -log auto stands for -log-dir on its own
+	logAppend       bool
+	disks           string
+
+	connect, ssh, replay, baseline string
+	interval                       time.Duration
+
+	serve     serveConfig
+	basicAuth string
+	push      pushConfig
+
+	alerts   int // -alert rules
+	alertFor time.Duration
+	webhooks webhookConfig
+
+	idleFloor    float64
+	idleFor      time.Duration
+	forecastFor  time.Duration
+	anomalySigma float64
+	retransWarn  float64
+	retransCrit  float64
+	ticker       string
+	users        bool
+	procs        bool
+	saveLayout   bool
+	diskPanel    bool
+	diskIO       bool
+	pids         int // -pid processes
+
+	control     string
+	rotateEvery time.Duration
+	upload      uploadConfig
+}
+
+// visited returns the names of the flags fs was given.
+func visited(fs *flag.FlagSet) map[string]bool {
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	return set
+}
+
+// sources counts -connect, -ssh and -replay.
+func (f startFlags) sources() int {
+	n := 0
+	for _, s := range []string{f.connect, f.ssh, f.replay} {
+		if s != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// problems returns every problem with f, each naming the flags involved.
+func (f startFlags) problems() []string {
+	var out []string
+	bad := func(format string, a ...any) { out = append(out, fmt.Sprintf(format, a...)) }
+	tui := !f.headless && !f.plain
+	remote := f.sources() > 0
+
+	// Sources.
+	if f.headless && remote {
+		bad("-connect, -ssh and -replay are TUI modes and cannot be combined with -headless")
+	} else if f.plain && remote {
+		bad("stdout is not a terminal, which -connect, -ssh and -replay need (-force-tui to start the TUI anyway)")
+	}
+	if f.sources() > 1 {
+		bad("-connect, -ssh and -replay are alternative sources; pass one")
+	}
+	if f.disks != "" && remote {
+		bad("-disks reads this machine's filesystems; it cannot be combined with -connect, -ssh or -replay")
+	}
+	if f.baseline != "" && f.replay == "" {
+		bad("-baseline is drawn under a -replay; pass the capture to compare it with as -replay")
+	}
+	if f.interval < ui.MinInterval {
+		bad("-interval must be at least %v", ui.MinInterval)
+	} else if f.interval != ui.DefaultInterval && remote {
+		bad("-interval sets this host's sampling; with -connect and -ssh the remote's applies, and -replay plays a sample per half second")
+	}
+
+	// The log.
+	if f.logDir != "" && f.logPath != autoLog {
+		bad("-log-dir is where -log auto puts captures; it cannot be combined with a -log path")
+	}
+	if f.logAppend && (f.logPath == "" || f.logPath == autoLog || f.logPath == stdinPath) {
+		bad("-log-append adds to the -log file named; it needs -log with a path")
+	}
+	for _, in := range []struct{ flag, path string }{{"replay", f.replay}, {"baseline", f.baseline}} {
+		if in.path != "" && f.logPath != "" && sameFile(in.path, f.logPath) {
+			bad("-log %s is the -%s capture, which it would overwrite", f.logPath, in.flag)
+		}
+	}
+	if f.logPath == stdinPath {
+		switch {
+		case f.plain:
+			bad("-log - needs -headless; without it stdout gets a line per sample")
+		case tui:
+			bad("-log - needs -headless; the TUI owns stdout")
+		}
+	}
+
+	// Listeners and sinks.
+	if _, _, err := parseBasicAuth(f.basicAuth); f.basicAuth != "" && err != nil {
+		bad("%v", err)
+	}
+	if err := f.serve.validate(); err != nil {
+		bad("%v", err)
+	}
+	if f.serve.addr != "" && f.serve.grpcAddr != "" && sameAddr(f.serve.addr, f.serve.grpcAddr) {
+		bad("-listen and -grpc-listen cannot share %s", f.serve.grpcAddr)
+	}
+	if targets := strings.Split(f.connect, ","); len(targets) > 1 && (f.logPath != "" || f.serve.enabled() || f.push.enabled() || f.alerts > 0) {
+		bad("-log, -listen, -grpc-listen, push targets and -alert follow a single host; pass one -connect url to use them")
+	}
+	if f.headless && f.logPath == "" && !f.serve.enabled() && !f.push.enabled() && !f.webhooks.enabled() {
+		bad("-headless needs -log, -listen, -grpc-listen, a push target or an alert destination; nothing would be recorded")
+	}
+
+	// Alerts.
+	if f.webhooks.enabled() && f.alerts == 0 {
+		bad("-alert-webhook, -alert-slack, -alert-discord and -alert-email need at least one -alert rule")
+	}
+	if f.alertFor < 0 || f.webhooks.cooldown < 0 || f.webhooks.timeout <= 0 || f.webhooks.retries < 0 {
+		bad("-alert-for, -alert-cooldown and -alert-retries must not be negative, and -alert-timeout must be positive")
+	}
+
+	// The panels.
+	if f.idleFloor <= 0 || f.idleFloor > 100 || f.idleFor < 0 {
+		bad("-idle-floor must be a percentage above 0 and -idle-after must not be negative")
+	}
+	if f.forecastFor < 0 {
+		bad("-forecast-window must not be negative")
+	}
+	if f.anomalySigma < 0 {
+		bad("-anomaly-sigma must not be negative")
+	}
+	if f.retransWarn < 0 || f.retransCrit < f.retransWarn {
+		bad("-retrans-warn must not be negative, nor above -retrans-crit")
+	}
+	if items, err := ui.ParseTicker(f.ticker); err != nil {
+		bad("%v", err)
+	} else if items != nil && f.headless {
+		bad("-ticker is drawn by the TUI; it does nothing with -headless")
+	} else if slices.Contains(items, "process") && f.ticker != "all" && !f.users && !f.procs {
+		bad("-ticker process shows the busiest process of the -users or -procs scan; pass -users or -procs")
+	}
+	if f.users && (remote || f.headless) {
+		bad("-users shows this host's processes in the TUI; it cannot be combined with -connect, -ssh, -replay or -headless")
+	}
+	if f.procs && (remote || f.headless) {
+		bad("-procs shows this host's processes in the TUI; it cannot be combined with -connect, -ssh, -replay or -headless")
+	}
+	if f.set["pseudo-fs"] && (!f.diskPanel || f.headless || remote) {
+		bad("-pseudo-fs adds to the DISK panel of this host's TUI; it does nothing with -disk-panel=false, -headless, -connect, -ssh or -replay")
+	}
+	if f.diskIO && (remote || f.headless) {
+		bad("-disk-io shows this host's disks in the TUI; it cannot be combined with -connect, -ssh, -replay or -headless")
+	}
+	if f.pids > 0 && (remote || f.headless) {
+		bad("-pid follows this host's processes in the TUI; it cannot be combined with -connect, -ssh, -replay or -headless")
+	}
+	if f.saveLayout && f.headless {
+		bad("-save-layout saves the TUI's panels; it does nothing with -headless")
+	}
+
+	// The headless collector.
+	if f.headless || f.plain {
+		if (f.rotateEvery != 0 || f.upload.enabled()) && (f.logPath == "" || f.logPath == stdinPath) {
+			bad("-rotate-every and -upload need -log with a file")
+		}
+		if f.rotateEvery < 0 || (f.rotateEvery > 0 && f.rotateEvery < time.Second) {
+			bad("-rotate-every must be at least 1s")
+		}
+	} else if f.control != "" || f.rotateEvery != 0 || f.upload.enabled() {
+		bad("-control, -rotate-every and -upload need -headless")
+	}
+
+	// Flags that only qualify another one.
+	for _, d := range []struct {
+		flag, needs string
+		has         bool
+	}{
+		{"force", "log", f.logPath != ""},
+		{"min-free", "log", f.logPath != ""},
+		{"log-schema", "log", f.logPath != ""},
+		{"log-pids", "log", f.logPath != ""},
+		{"log-pids", "pid", f.pids > 0},
+		{"ssh-key", "ssh", f.ssh != ""},
+		{"cors", "listen", f.serve.addr != ""},
+		{"influx-org", "influx-url", f.push.influx.url != ""},
+		{"influx-bucket", "influx-url", f.push.influx.url != ""},
+		{"remote-write-user", "remote-write", f.push.remoteWrite.url != ""},
+		{"graphite-prefix", "graphite", f.push.graphite != ""},
+		{"alert-for", "alert", f.alerts > 0},
+		{"smtp-host", "alert-email", len(f.webhooks.email.to) > 0},
+		{"smtp-user", "alert-email", len(f.webhooks.email.to) > 0},
+		{"smtp-from", "alert-email", len(f.webhooks.email.to) > 0},
+		{"control-mode", "control", f.control != ""},
+		{"upload-endpoint", "upload", f.upload.enabled()},
+		{"upload-keep", "upload", f.upload.enabled()},
+	} {
+		if f.set[d.flag] && !d.has {
+			bad("-%s does nothing without -%s", d.flag, d.needs)
+		}
+	}
+	return out
+}
+
+// reportProblems prints problems to w, a line each, and reports whether
+// there were any.
+func reportProblems(w io.Writer, problems []string) bool {
+	for _, p := range problems {
+		fmt.Fprintf(w, "infgo: %s\n", p)
+	}
+	if len(problems) > 1 {
+		fmt.Fprintf(w, "infgo: %d problems with the flags; see infgo -h\n", len(problems))
+	}
+	return len(problems) > 0
+}
+
+// sameFile reports whether paths a and b name the same file: the same
+// one on disk, or the same path where either does not exist yet.
+func sameFile(a, b string) bool {
+	sa, errA := os.Stat(a)
+	sb, errB := os.Stat(b)
+	if errA == nil && errB == nil {
+		return os.SameFile(sa, sb)
+	}
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
+
+// sameAddr reports whether two listen addresses would clash: the same
+// port, on the same host or where either listens on every interface.
+func sameAddr(a, b string) bool {
+	hostA, portA, errA := net.SplitHostPort(a)
+	hostB, portB, errB := net.SplitHostPort(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	wild := func(h string) bool { return h == "" || h == "0.0.0.0" || h == "::" }
+	return portA == portB && portA != "0" && (hostA == hostB || wild(hostA) || wild(hostB))
+}
@@ -0,0 +1,184 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+// validFlags are the flags of a plain `infgo` in a terminal.
+func validFlags() startFlags {
+	return startFlags{
+		set:          map[string]bool{},
+		interval:     ui.DefaultInterval,
+		idleFloor:    idleFloor,
+		idleFor:      idleAfter,
+		forecastFor:  ui.ForecastWindow,
+		anomalySigma: 3,
+		retransWarn:  ui.RetransWarn,
+		retransCrit:  ui.RetransCrit,
+		alertFor:     alertHold,
+		webhooks:     webhookConfig{cooldown: alertCooldown, timeout: alertTimeout, retries: alertRetries},
+	}
+}
+
+func TestStartFlagsValid(t *testing.T) {
+	for name, f := range map[string]func(*startFlags){
+		"defaults": func(*startFlags) {},
+		"headless log": func(f *startFlags) {
+			f.headless, f.logPath, f.set["force"] = true, "-", true
+		},
+		"replay with baseline": func(f *startFlags) { f.replay, f.baseline, f.logPath = "a.infgo", "b.infgo", "c.infgo" },
+		"ticker all":           func(f *startFlags) { f.ticker = "all" },
+		"ticker procs":         func(f *startFlags) { f.ticker, f.procs = "process", true },
+		"pseudo fs":            func(f *startFlags) { f.diskPanel, f.set["pseudo-fs"] = true, true },
+		"listeners":            func(f *startFlags) { f.serve.addr, f.serve.grpcAddr, f.set["cors"] = ":9804", ":9805", true },
+		"rotation": func(f *startFlags) {
+			f.headless, f.logPath, f.rotateEvery, f.upload.target = true, "node.infgo", time.Hour, "s3://b/p"
+			f.set["upload-keep"] = true
+		},
+	} {
+		sf := validFlags()
+		f(&sf)
+		if got := sf.problems(); len(got) > 0 {
+			t.Errorf("%s: got %q", name, got)
+		}
+	}
+}
+
+// Each invalid combination is reported, naming its flags, and alongside
+// any others.
+func TestStartFlagsInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		set  func(*startFlags)
+		want []string
+	}{
+		{"headless source", func(f *startFlags) { f.headless, f.logPath, f.connect = true, "x.infgo", "http://a:9804" }, []string{"-headless"}},
+		{"plain source", func(f *startFlags) { f.plain, f.ssh = true, "me@host" }, []string{"-force-tui"}},
+		{"two sources", func(f *startFlags) { f.connect, f.replay = "http://a:9804", "a.infgo" }, []string{"alternative sources"}},
+		{"disks remote", func(f *startFlags) { f.disks, f.ssh = "/", "me@host" }, []string{"-disks"}},
+		{"baseline alone", func(f *startFlags) { f.baseline = "b.infgo" }, []string{"-baseline"}},
+		{"interval short", func(f *startFlags) { f.interval = time.Millisecond }, []string{"-interval must be at least"}},
+		{"interval replay", func(f *startFlags) { f.interval, f.replay = time.Second, "a.infgo" }, []string{"-interval sets"}},
+		{"log dir and path", func(f *startFlags) { f.logDir, f.logPath = "/var/log", "x.infgo" }, []string{"-log-dir"}},
+		{"append auto", func(f *startFlags) { f.logAppend, f.logPath = true, autoLog }, []string{"-log-append"}},
+		{"log over replay", func(f *startFlags) { f.replay, f.logPath = "a.infgo", "./a.infgo" }, []string{"-log ./a.infgo is the -replay capture"}},
+		{"log over baseline", func(f *startFlags) { f.replay, f.baseline, f.logPath = "a.infgo", "b.infgo", "b.infgo" }, []string{"-baseline capture"}},
+		{"stdout log in tui", func(f *startFlags) { f.logPath = "-" }, []string{"the TUI owns stdout"}},
+		{"stdout log plain", func(f *startFlags) { f.plain, f.logPath = true, "-" }, []string{"a line per sample"}},
+		{"basic auth", func(f *startFlags) { f.basicAuth = "user" }, []string{"-basic-auth"}},
+		{"tls half", func(f *startFlags) { f.serve.addr, f.serve.tlsCert = ":9804", "c.pem" }, []string{"-tls-key"}},
+		{"same address", func(f *startFlags) { f.serve.addr, f.serve.grpcAddr = "127.0.0.1:9804", ":9804" }, []string{"cannot share :9804"}},
+		{"grid with log", func(f *startFlags) { f.connect, f.logPath = "http://a:9804,http://b:9804", "x.infgo" }, []string{"single host"}},
+		{"headless idle", func(f *startFlags) { f.headless = true }, []string{"nothing would be recorded"}},
+		{"webhook no rule", func(f *startFlags) { f.webhooks.urls = []string{"http://hook"} }, []string{"at least one -alert"}},
+		{"alert timeout", func(f *startFlags) { f.webhooks.timeout = 0 }, []string{"-alert-timeout"}},
+		{"idle floor", func(f *startFlags) { f.idleFloor = 120 }, []string{"-idle-floor"}},
+		{"forecast", func(f *startFlags) { f.forecastFor = -time.Second }, []string{"-forecast-window"}},
+		{"anomaly", func(f *startFlags) { f.anomalySigma = -1 }, []string{"-anomaly-sigma"}},
+		{"retrans", func(f *startFlags) { f.retransWarn, f.retransCrit = 50, 20 }, []string{"-retrans-warn"}},
+		{"ticker item", func(f *startFlags) { f.ticker = "swap" }, []string{"unknown item"}},
+		{"ticker headless", func(f *startFlags) { f.headless, f.logPath, f.ticker = true, "x.infgo", "disk" }, []string{"-ticker is drawn by the TUI"}},
+		{"ticker process", func(f *startFlags) { f.ticker = "process,disk" }, []string{"pass -users"}},
+		{"users remote", func(f *startFlags) { f.users, f.connect = true, "http://a:9804" }, []string{"-users"}},
+		{"procs headless", func(f *startFlags) { f.headless, f.logPath, f.procs = true, "x.infgo", true }, []string{"-procs"}},
+		{"pseudo fs headless", func(f *startFlags) {
+			f.headless, f.logPath, f.diskPanel, f.set["pseudo-fs"] = true, "x.infgo", true, true
+		}, []string{"-pseudo-fs"}},
+		{"pseudo fs no panel", func(f *startFlags) { f.set["pseudo-fs"] = true }, []string{"-pseudo-fs"}},
+		{"disk io remote", func(f *startFlags) { f.diskIO, f.connect = true, "http://a:9804" }, []string{"-disk-io"}},
+		{"pid remote", func(f *startFlags) { f.pids, f.replay = 1, "x.infgo" }, []string{"-pid follows"}},
+		{"log pids alone", func(f *startFlags) { f.set["log-pids"] = true }, []string{"-log-pids does nothing without -log", "-log-pids does nothing without -pid"}},
+		{"save layout headless", func(f *startFlags) { f.headless, f.logPath, f.saveLayout = true, "x.infgo", true }, []string{"-save-layout"}},
+		{"rotate stdout", func(f *startFlags) { f.headless, f.logPath, f.rotateEvery = true, "-", time.Hour }, []string{"need -log with a file"}},
+		{"rotate short", func(f *startFlags) { f.headless, f.logPath, f.rotateEvery = true, "x.infgo", time.Millisecond }, []string{"at least 1s"}},
+		{"control in tui", func(f *startFlags) { f.control = "/run/infgo.sock" }, []string{"need -headless"}},
+		{"dependents", func(f *startFlags) {
+			for _, name := range []string{"force", "ssh-key", "influx-org", "graphite-prefix", "smtp-host", "upload-keep"} {
+				f.set[name] = true
+			}
+		}, []string{
+			"-force does nothing without -log", "-ssh-key does nothing without -ssh", "-influx-org does nothing without -influx-url",
+			"-graphite-prefix does nothing without -graphite", "-smtp-host does nothing without -alert-email", "-upload-keep does nothing without -upload",
+		}},
+		{"several at once", func(f *startFlags) { f.baseline, f.anomalySigma, f.set["cors"] = "b.infgo", -1, true }, []string{"-baseline", "-anomaly-sigma", "-cors"}},
+	}
+	for _, tt := range tests {
+		f := validFlags()
+		tt.set(&f)
+		got := f.problems()
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: got %d problems %q, want %d", tt.name, len(got), got, len(tt.want))
+			continue
+		}
+		for i, want := range tt.want {
+			if !strings.Contains(got[i], want) {
+				t.Errorf("%s: problem %d is %q, want it to mention %q", tt.name, i, got[i], want)
+			}
+		}
+	}
+}
+
+func TestReportProblems(t *testing.T) {
+	var b strings.Builder
+	if reportProblems(&b, nil) || b.Len() > 0 {
+		t.Errorf("none: got %q", b.String())
+	}
+	if !reportProblems(&b, []string{"-a is wrong", "-b is wrong"}) {
+		t.Error("two: got false")
+	}
+	want := "infgo: -a is wrong\ninfgo: -b is wrong\ninfgo: 2 problems with the flags; see infgo -h\n"
+	if b.String() != want {
+		t.Errorf("got %q, want %q", b.String(), want)
+	}
+}
+
+func TestSameFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.infgo")
+	if err := os.WriteFile(path, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "link.infgo")
+	if err := os.Symlink(path, link); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		a, b string
+		want bool
+	}{
+		{path, link, true},
+		{path, filepath.Join(dir, ".", "a.infgo"), true},
+		{path, filepath.Join(dir, "b.infgo"), false},
+		{filepath.Join(dir, "new.infgo"), filepath.Join(dir, "sub", "..", "new.infgo"), true},
+	} {
+		if got := sameFile(tt.a, tt.b); got != tt.want {
+			t.Errorf("%s, %s: got %v", tt.a, tt.b, got)
+		}
+	}
+}
+
+func TestSameAddr(t *testing.T) {
+	for _, tt := range []struct {
+		a, b string
+		want bool
+	}{
+		{":9804", ":9804", true},
+		{"0.0.0.0:9804", "127.0.0.1:9804", true},
+		{"127.0.0.1:9804", "10.0.0.1:9804", false},
+		{":9804", ":9805", false},
+		{":0", ":0", false},
+	} {
+		if got := sameAddr(tt.a, tt.b); got != tt.want {
+			t.Errorf("%s, %s: got %v", tt.a, tt.b, got)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+// ── Page faults ───────────────────────────────────────────────────────────────
+//
+// The MEMORY panel's faults row: the page faults a second, and the major
+// ones among them, which had to wait on the disk.  A steady stream of
+// major faults is the clearest sign a machine is thrashing, so their rate
+// is red past majorFaultCrit, and both are recorded with each sample.  The
+// counters are read from /proc/vmstat with the stats, and their rates are
+// the deltas over the time between readings; where they cannot be read the
+// row is left out.
+
+// errNoFaultCounters is a /proc/vmstat without the pgfault and pgmajfault
+// lines.
+var errNoFaultCounters = errors.New("/proc/vmstat: no pgfault or pgmajfault line")
+
+// parseVMStat reads the pgfault and pgmajfault counters from a
+// /proc/vmstat, lines of a name and a count.
+func parseVMStat(r io.Reader) (ui.FaultCounters, error) {
+	var c ui.FaultCounters
+	var seen int
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		f := strings.Fields(sc.Text())
+		if len(f) != 2 || (f[0] != "pgfault" && f[0] != "pgmajfault") {
+			continue
+		}
+		v, err := strconv.ParseUint(f[1], 10, 64)
+		if err != nil {
+			return c, err
+		}
+		if f[0] == "pgfault" {
+			c.All = v
+		} else {
+			c.Major = v
+		}
+		seen++
+	}
+	if err := sc.Err(); err != nil {
+		return c, err
+	}
+	if seen < 2 {
+		return c, errNoFaultCounters
+	}
+	return c, nil
+}
+
+// readFaults reads the counters into msg.  A failure backs off on its
+// own, leaving the other readings alone.  Off Linux there is no
+// r.src.faults, and nothing is read.
+func (r *statsReader) readFaults(ctx context.Context, msg *ui.Reading) {
+	if r.src.faults == nil || !r.vmstat.due(r.now()) {
+		return
+	}
+	c, err := query(ctx, &r.vmstat, r.timeout, r.src.faults)
+	if err != nil {
+		r.vmstat.failed(r.now())
+		return
+	}
+	r.vmstat.recovered()
+	msg.Faults, msg.FaultsAt = c, r.now()
+}
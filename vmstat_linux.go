@@ -0,0 +1,23 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+//go:build linux
+
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+// readFaultCounters reads the page faults since boot from /proc/vmstat.
+func readFaultCounters(context.Context) (ui.FaultCounters, error) {
+	f, err := os.Open("/proc/vmstat")
+	if err != nil {
+		return ui.FaultCounters{}, err
+	}
+	defer f.Close()
+	return parseVMStat(f)
+}
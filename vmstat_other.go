@@ -0,0 +1,16 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+//go:build !linux
+
+package main
+
+import (
+	"context"
+
+	"github.com/ALH477/infgo/ui"
+)
+
+// readFaultCounters is nil here: the counters are read from /proc/vmstat
+// only, and the MEMORY panel has no faults row.
+var readFaultCounters func(context.Context) (ui.FaultCounters, error)
@@ -0,0 +1,81 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	syslogger "github.com/ALH477/infgo/logger"
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ui"
+)
+
+func TestParseVMStat(t *testing.T) {
+	f, err := os.Open("testdata/vmstat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if got, err := parseVMStat(f); err != nil || got != (ui.FaultCounters{All: 1175436591, Major: 148093}) {
+		t.Errorf("got %+v, %v", got, err)
+	}
+	for _, bad := range []string{"", "pgfault 12\n", "pgfault 12\npgmajfault lots\n", "nr_free_pages 1\npgpgin 2\n"} {
+		if got, err := parseVMStat(strings.NewReader(bad)); err == nil {
+			t.Errorf("%q: got %+v", bad, got)
+		}
+	}
+}
+
+func TestReadFaults(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	r := fakeReader(&fakeSources{}, &now)
+	if msg := r.read(context.Background()); !msg.FaultsAt.IsZero() {
+		t.Errorf("without a source: got %+v at %v", msg.Faults, msg.FaultsAt)
+	}
+	r.src.faults = func(context.Context) (ui.FaultCounters, error) { return ui.FaultCounters{All: 7, Major: 3}, nil }
+	if msg := r.read(context.Background()); msg.Faults != (ui.FaultCounters{All: 7, Major: 3}) || !msg.FaultsAt.Equal(now) {
+		t.Errorf("got %+v at %v", msg.Faults, msg.FaultsAt)
+	}
+	r.src.faults = func(context.Context) (ui.FaultCounters, error) { return ui.FaultCounters{}, errNoFaultCounters }
+	now = now.Add(ui.DefaultInterval)
+	if msg := r.read(context.Background()); !msg.FaultsAt.IsZero() || msg.Missing != 0 {
+		t.Errorf("failing: got %v, missing %q", msg.FaultsAt, msg.Missing)
+	}
+	if r.vmstat.due(now) {
+		t.Error("a failed reading did not back off")
+	}
+}
+
+// The headless collector records the rates as the TUI does.
+func TestHeadlessFaults(t *testing.T) {
+	var out bytes.Buffer
+	lgr, err := syslogger.NewWriter(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	at, reads := time.Unix(1700000000, 0), 0
+	h := &headless{logger: lgr, interval: 10 * time.Millisecond, read: func(context.Context) ui.Reading {
+		if reads++; reads > 2 {
+			cancel()
+			return ui.Reading{Missing: metrics.MissingAll}
+		}
+		c := ui.FaultCounters{All: []uint64{1000, 1120}[reads-1], Major: []uint64{10, 14}[reads-1]}
+		return ui.Reading{CPUCores: []float64{10}, Faults: c, FaultsAt: at.Add(time.Duration(reads) * time.Second)}
+	}}
+	if err := h.run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	lgr.Flush()
+	_, samples := readLog(t, &out)
+	if len(samples) != 2 || samples[0].PageFaultsPerSec != 0 || samples[1].PageFaultsPerSec != 120 || samples[1].MajorFaultsPerSec != 4 {
+		t.Errorf("logged: got %+v", samples)
+	}
+}
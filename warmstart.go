@@ -0,0 +1,58 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	syslogger "github.com/ALH477/infgo/logger"
+	"github.com/ALH477/infgo/ui"
+)
+
+// ── Warm start (-log-append) ──────────────────────────────────────────────────
+//
+// A TUI restarted with -log-append on the capture it was recording reads
+// back the end of it, so that the sparklines, the peaks and the core-seconds
+// carry on from the readings of moments ago.  The readings are merged in
+// when they arrive, ahead of any taken since; a dim divider on the
+// sparklines marks where the restart fell.
+
+// warmWindow is how much of the end of the capture is read back, and
+// warmTimeout how long that may take: a capture on a slow disk, or one
+// whose end makes no sense, starts the display afresh instead.
+const (
+	warmWindow  = 1 << 20
+	warmTimeout = 2 * time.Second
+)
+
+// warmStart reads back the samples of the capture at path before end, the
+// offset this session's records begin at.  Nothing is delivered where they
+// cannot be had within warmTimeout.
+func warmStart(ctx context.Context, path string, end int64) tea.Cmd {
+	return func() tea.Msg {
+		var sub subsystem
+		recs, err := query(ctx, &sub, warmTimeout, func(context.Context) ([]syslogger.Record, error) {
+			return syslogger.Tail(path, end, warmWindow)
+		})
+		if err != nil {
+			return nil
+		}
+		var msg ui.Warm
+		for _, rec := range recs {
+			switch {
+			case rec.Header != nil:
+				msg.Interval = time.Duration(rec.Header.IntervalMs) * time.Millisecond
+			case rec.Sample != nil:
+				msg.Samples = append(msg.Samples, *rec.Sample)
+			}
+		}
+		if len(msg.Samples) == 0 {
+			return nil
+		}
+		return msg
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	syslogger "github.com/ALH477/infgo/logger"
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ui"
+)
+
+// A restart with -log-append reads back the end of the capture for the
+// TUI to continue from.
+func TestWarmStart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "node.infgo")
+	lgr, err := syslogger.New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t0 := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	lgr.WriteHeader(metrics.Header{Hostname: "node", IntervalMs: 1000})
+	for i := range 30 {
+		s := metrics.Sample{TimestampUnixMs: t0.Add(time.Duration(i) * time.Second).UnixMilli(),
+			CpuTotal: 10, CpuCores: []float64{10, 10}, MemPercent: 40}
+		if i == 20 {
+			s.CpuTotal = 90
+		}
+		lgr.WriteSample(s)
+	}
+	lgr.Close()
+	if lgr, err = syslogger.Append(path); err != nil {
+		t.Fatal(err)
+	}
+	defer lgr.Close()
+
+	cmd := (&recorder{logger: lgr, logPath: path}).Init(context.Background())
+	if cmd == nil {
+		t.Fatal("no warm start with -log-append")
+	}
+	warm, ok := cmd().(ui.Warm)
+	if !ok || len(warm.Samples) != 30 || warm.Interval != time.Second || warm.Samples[20].CpuTotal != 90 {
+		t.Fatalf("got %#v, want the 30 samples at 1s", warm)
+	}
+
+	if msg := warmStart(context.Background(), filepath.Join(t.TempDir(), "none.infgo"), 100)(); msg != nil {
+		t.Errorf("no capture: got %#v, want nothing", msg)
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ALH477/infgo/analysis"
+	syslogger "github.com/ALH477/infgo/logger"
+	"github.com/ALH477/infgo/metrics"
+)
+
+// ── analyze -watch ────────────────────────────────────────────────────────────
+
+// `infgo analyze -watch 30s capture.infgo` follows a capture that is still
+// being written, as during a long benchmark.  Each cycle it reads only the
+// records appended since the last, from the offset the Reader stopped at,
+// folds them into running statistics (analysis.RunningSummary) and draws
+// the report again in place.  A capture that has been rotated away or
+// truncated is read again from its start.
+
+// clearScreen moves the cursor home and clears the terminal.
+const clearScreen = "\x1b[H\x1b[2J"
+
+// captureWatch follows one capture file as it grows.
+type captureWatch struct {
+	path   string
+	window time.Duration // of the memory forecast; 0 for none
+	tz     string        // the -tz choice, resolved against the header
+
+	file os.FileInfo // the file as last read
+	off  int64       // just past its last whole record
+
+	header      *metrics.Header
+	first, last time.Time
+	n           int
+	sum         analysis.RunningSummary
+	recent      []metrics.Sample // the last window of samples, for the forecast
+	restarts    int              // times the file was replaced or truncated
+}
+
+func newCaptureWatch(path string, window time.Duration) *captureWatch {
+	return &captureWatch{path: path, window: window, sum: analysis.NewRunningSummary()}
+}
+
+// poll reads the records appended since the last poll.  A record still
+// being written at the end is left for the next.  If the file has been
+// replaced or has shrunk since, the statistics start again from its first
+// record; while it is missing, as between a rotation's rename and the new
+// file's creation, there is nothing to read.
+func (cw *captureWatch) poll() error {
+	f, err := os.Open(cw.path)
+	if errors.Is(err, fs.ErrNotExist) && cw.file != nil {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if cw.file != nil && (!os.SameFile(cw.file, fi) || fi.Size() < cw.off) {
+		*cw = captureWatch{path: cw.path, window: cw.window, sum: analysis.NewRunningSummary(), restarts: cw.restarts + 1}
+	}
+	cw.file = fi
+	if fi.Size() == 0 {
+		return nil // created, but nothing flushed to it yet
+	}
+	rd, err := syslogger.Resume(f, cw.off)
+	if err != nil {
+		return err
+	}
+	defer rd.Close()
+	for {
+		rec, err := rd.Next()
+		if err == io.EOF || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		cw.add(rec)
+		cw.off = rd.Offset()
+	}
+	return nil
+}
+
+// add folds one record into the statistics.
+func (cw *captureWatch) add(rec *syslogger.Record) {
+	switch {
+	case rec.Header != nil && cw.header == nil:
+		cw.header = rec.Header
+	case rec.Sample != nil:
+		s := rec.Sample
+		cw.n++
+		if cw.n == 1 {
+			cw.first = s.Time()
+		}
+		cw.last = s.Time()
+		cw.sum.Add(s)
+		if cw.window > 0 {
+			cw.recent = append(cw.recent, *s)
+			drop := 0
+			for drop < len(cw.recent) && cw.last.Sub(cw.recent[drop].Time()) > cw.window {
+				drop++
+			}
+			cw.recent = cw.recent[drop:]
+		}
+	}
+}
+
+// report writes the report so far, and returns the number of conds that
+// failed.
+func (cw *captureWatch) report(w io.Writer, conds []analysis.Comparison, every time.Duration, now time.Time) int {
+	if cw.n == 0 {
+		fmt.Fprintf(w, "\n  waiting for samples in %s\n\n", cw.path)
+		return 0
+	}
+	sum := cw.sum.Summary()
+	zone := resolveZone(cw.tz, cw.header)
+	printSession(w, "watching "+filepath.Base(cw.path), cw.header, cw.first, cw.last, cw.n, zone)
+	printForecast(w, captureForecast(cw.recent, cw.window))
+	printStats(w, "", sum)
+	fmt.Fprintln(w)
+
+	results := make([]analysis.Assertion, len(conds))
+	failed := 0
+	for i, c := range conds {
+		results[i] = analysis.Evaluate(sum, c)
+		if results[i].Failed {
+			failed++
+		}
+	}
+	printAssertions(w, results)
+	restarted := ""
+	if cw.restarts > 0 {
+		restarted = fmt.Sprintf(" · restarted %d× on rotation", cw.restarts)
+	}
+	fmt.Fprintf(w, "  updated %s · every %s%s · ctrl+c to stop\n",
+		now.In(zone.loc).Format("15:04:05"), every, restarted)
+	return failed
+}
+
+// watchAnalyze polls path every interval until ctx is done, drawing the
+// report over the last one when clear is set.  It fails if the conditions
+// failed at the last poll.
+func watchAnalyze(ctx context.Context, path string, every, window time.Duration, tz string, conds []analysis.Comparison, w io.Writer, clear bool) error {
+	cw := newCaptureWatch(path, window)
+	cw.tz = tz
+	tick := time.NewTicker(every)
+	defer tick.Stop()
+	for {
+		if err := cw.poll(); err != nil {
+			return err
+		}
+		var b bytes.Buffer
+		if clear {
+			b.WriteString(clearScreen)
+		}
+		failed := cw.report(&b, conds, every, time.Now())
+		if _, err := w.Write(b.Bytes()); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			if failed > 0 {
+				return fmt.Errorf("%d of %d assertions failed", failed, len(conds))
+			}
+			return nil
+		case <-tick.C:
+		}
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ALH477/infgo/analysis"
+	syslogger "github.com/ALH477/infgo/logger"
+	"github.com/ALH477/infgo/metrics"
+	"github.com/ALH477/infgo/ui"
+)
+
+// appendSamples writes samples from..to-1, CPU at i %, and flushes them.
+func appendSamples(t *testing.T, lgr *syslogger.Logger, from, to int) {
+	t.Helper()
+	for i := from; i < to; i++ {
+		s := metrics.Sample{TimestampUnixMs: 1704067200000 + int64(i)*1000, CpuTotal: float64(i)}
+		if err := lgr.WriteSample(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := lgr.Flush(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCaptureWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bench.infgo")
+	lgr, err := syslogger.New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lgr.Close()
+	cw := newCaptureWatch(path, 0)
+	if err := cw.poll(); err != nil || cw.n != 0 {
+		t.Fatalf("nothing flushed: got %d samples, %v", cw.n, err)
+	}
+
+	lgr.WriteHeader(metrics.Header{Hostname: "bench"})
+	appendSamples(t, lgr, 0, 10)
+	if err := cw.poll(); err != nil || cw.n != 10 || cw.header == nil {
+		t.Fatalf("got %d samples, header %v, %v", cw.n, cw.header, err)
+	}
+	off := cw.off
+	appendSamples(t, lgr, 10, 15)
+	if err := cw.poll(); err != nil || cw.n != 15 || cw.off <= off {
+		t.Fatalf("appended: got %d samples, offset %d, %v", cw.n, cw.off, err)
+	}
+	if st := cw.sum.Summary()["cpu"]; st.Max != 14 || st.Mean != 7 {
+		t.Errorf("got %+v, want max 14 and mean 7", st)
+	}
+
+	// Half a record at the end is left for the next poll.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte{byte(syslogger.RecordTypeSample), 0, 0})
+	f.Close()
+	if err := cw.poll(); err != nil || cw.n != 15 {
+		t.Errorf("half a record: got %d samples, %v", cw.n, err)
+	}
+
+	// Rotated: the new file is read from its start.
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cw.poll(); err != nil || cw.n != 15 {
+		t.Errorf("between rename and create: got %d samples, %v", cw.n, err)
+	}
+	next, err := syslogger.New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer next.Close()
+	next.WriteHeader(metrics.Header{Hostname: "bench"})
+	appendSamples(t, next, 100, 103)
+	if err := cw.poll(); err != nil || cw.n != 3 || cw.restarts != 1 {
+		t.Fatalf("rotated: got %d samples, %d restarts, %v", cw.n, cw.restarts, err)
+	}
+	if st := cw.sum.Summary()["cpu"]; st.Min != 100 || st.Max != 102 {
+		t.Errorf("rotated: got %+v, want only the new file's samples", st)
+	}
+
+	// Truncated in place.
+	if err := os.Truncate(path, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := cw.poll(); err != nil || cw.n != 0 || cw.restarts != 2 {
+		t.Errorf("truncated: got %d samples, %d restarts, %v", cw.n, cw.restarts, err)
+	}
+}
+
+func TestCaptureWatchReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bench.infgo")
+	cw := newCaptureWatch(path, ui.ForecastWindow)
+	cond, err := analysis.ParseAssertion("cpu.max>50")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	now := time.Date(2026, 1, 2, 15, 4, 5, 0, time.Local)
+	if failed := cw.report(&out, []analysis.Comparison{cond}, 30*time.Second, now); failed != 0 || !strings.Contains(out.String(), "waiting for samples") {
+		t.Errorf("no samples: got %d failed,\n%s", failed, out.String())
+	}
+
+	lgr, err := syslogger.New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lgr.Close()
+	appendSamples(t, lgr, 0, 60)
+	if err := cw.poll(); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if failed := cw.report(&out, []analysis.Comparison{cond}, 30*time.Second, now); failed != 1 {
+		t.Errorf("got %d failed, want cpu.max>50 to fail", failed)
+	}
+	for _, want := range []string{"watching bench.infgo", "Samples    60", "FAIL  cpu.max>50", "updated 15:04:05 · every 30s"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("missing %q in\n%s", want, out.String())
+		}
+	}
+}
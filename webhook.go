@@ -0,0 +1,355 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// ── Alert webhooks (-alert-webhook) ───────────────────────────────────────────
+
+const (
+	// alertQueue bounds the alerts waiting for delivery.  Beyond it new
+	// ones are dropped, so a dead endpoint never stalls sampling.
+	alertQueue = 32
+
+	alertTimeout    = 5 * time.Second
+	alertRetries    = 3
+	alertRetryDelay = time.Second // doubles after each failed attempt
+
+	// alertCloseWait bounds how long quitting waits for queued deliveries.
+	alertCloseWait = 5 * time.Second
+
+	// alertCooldown is the -alert-cooldown default: a rule that fires again
+	// this soon after its last chat message is reported as flapping.
+	alertCooldown = 5 * time.Minute
+
+	// alertSignatureHeader carries "sha256=" and the hex HMAC-SHA256 of the
+	// request body, keyed with -alert-secret.
+	alertSignatureHeader = "X-Infgo-Signature"
+)
+
+// webhookConfig holds the -alert-* delivery flags.
+type webhookConfig struct {
+	urls     []string
+	slack    []string
+	discord  []string
+	email    smtpConfig
+	secret   string
+	timeout  time.Duration
+	retries  int
+	cooldown time.Duration
+
+	// errs receives each destination's first failure as it happens.  When
+	// nil, as under the TUI, close returns it instead.
+	errs io.Writer
+}
+
+func (c webhookConfig) enabled() bool {
+	return len(c.urls)+len(c.slack)+len(c.discord)+len(c.email.to) > 0
+}
+
+// alertTarget is one destination: the payload it expects and how to send
+// it.  Targets read by people also get a limiter.
+type alertTarget struct {
+	encode func(alertEvent) ([]byte, error)
+	send   func(body []byte) (retry bool, err error)
+	limit  *alertLimiter
+	failed bool // its first failure has been reported
+}
+
+// encodeAlertJSON is the generic payload: the alertEvent as JSON.
+func encodeAlertJSON(ev alertEvent) ([]byte, error) { return json.Marshal(ev) }
+
+// alertNotifier delivers alerts to every target on its own goroutine.
+type alertNotifier struct {
+	targets    []alertTarget
+	secret     []byte
+	client     *http.Client
+	retries    int
+	retryDelay time.Duration
+
+	queue    chan alertEvent
+	done     chan struct{}
+	overflow atomic.Int64 // alerts dropped because the queue was full
+	warning  atomic.Value // string: the first delivery failure, kept for the footer
+	errs     io.Writer
+}
+
+// newAlertNotifier validates the webhook URLs and starts the delivery
+// worker.  It returns nil when no URL is configured; a nil notifier
+// ignores every alert.
+func newAlertNotifier(cfg webhookConfig) (*alertNotifier, error) {
+	if !cfg.enabled() {
+		return nil, nil
+	}
+	n := &alertNotifier{
+		secret:     []byte(cfg.secret),
+		client:     &http.Client{Timeout: cfg.timeout},
+		retries:    cfg.retries,
+		retryDelay: alertRetryDelay,
+		queue:      make(chan alertEvent, alertQueue),
+		done:       make(chan struct{}),
+		errs:       cfg.errs,
+	}
+	add := func(flag string, urls []string, encode func(alertEvent) ([]byte, error), chat bool) error {
+		for _, raw := range urls {
+			u, err := url.Parse(raw)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("%s: want an http(s) URL, got %q", flag, raw)
+			}
+			t := alertTarget{encode: encode, send: func(body []byte) (bool, error) { return n.post(raw, body) }}
+			if chat {
+				t.limit = newAlertLimiter(cfg.cooldown)
+			}
+			n.targets = append(n.targets, t)
+		}
+		return nil
+	}
+	if err := add("-alert-webhook", cfg.urls, encodeAlertJSON, false); err != nil {
+		return nil, err
+	}
+	if err := add("-alert-slack", cfg.slack, slackEncoder(cfg.cooldown), true); err != nil {
+		return nil, err
+	}
+	if err := add("-alert-discord", cfg.discord, discordEncoder(cfg.cooldown), true); err != nil {
+		return nil, err
+	}
+	if len(cfg.email.to) > 0 {
+		mail, err := newSMTPSender(cfg.email, cfg.cooldown)
+		if err != nil {
+			return nil, err
+		}
+		n.targets = append(n.targets, alertTarget{encode: mail.encode, send: mail.send, limit: newAlertLimiter(cfg.cooldown)})
+	}
+	go n.run()
+	return n, nil
+}
+
+// notify queues ev for delivery without blocking.
+func (n *alertNotifier) notify(ev alertEvent) {
+	if n == nil {
+		return
+	}
+	select {
+	case n.queue <- ev:
+	default:
+		err := fmt.Errorf("alert queue full; dropped %s %s", ev.Rule, ev.State)
+		if n.overflow.Add(1) == 1 && n.errs != nil {
+			fmt.Fprintf(n.errs, "infgo: %v\n", err)
+		}
+		n.warn(err)
+	}
+}
+
+// run delivers queued alerts, and once a second releases what the chat
+// limiters held back from rules that have stopped flapping.  Closing the
+// queue releases everything still held.
+func (n *alertNotifier) run() {
+	defer close(n.done)
+	tick := time.NewTicker(time.Second)
+	defer tick.Stop()
+	for {
+		select {
+		case ev, ok := <-n.queue:
+			if !ok {
+				for i := range n.targets {
+					n.send(&n.targets[i], n.targets[i].limit.settle(time.Time{}))
+				}
+				return
+			}
+			now := time.Now()
+			for i := range n.targets {
+				n.send(&n.targets[i], n.targets[i].limit.admit(ev, now))
+			}
+		case now := <-tick.C:
+			for i := range n.targets {
+				n.send(&n.targets[i], n.targets[i].limit.settle(now))
+			}
+		}
+	}
+}
+
+// send delivers evs to t.  Only t's first failure is reported, so a
+// wrong password or a dead server does not print once per alert.
+func (n *alertNotifier) send(t *alertTarget, evs []alertEvent) {
+	for _, ev := range evs {
+		err := n.deliver(t, ev)
+		if err == nil {
+			continue
+		}
+		n.warn(err)
+		if !t.failed && n.errs != nil {
+			fmt.Fprintf(n.errs, "infgo: %v\n", err)
+		}
+		t.failed = true
+	}
+}
+
+// deliver sends ev to t, retrying up to n.retries times on failures that
+// t.send deems temporary.
+func (n *alertNotifier) deliver(t *alertTarget, ev alertEvent) error {
+	body, err := t.encode(ev)
+	if err != nil {
+		return err
+	}
+	delay := n.retryDelay
+	for attempt := 0; ; attempt++ {
+		retry, err := t.send(body)
+		if err == nil || !retry || attempt >= n.retries {
+			return err
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// post makes one webhook request.  Network errors, 429 and 5xx responses
+// are worth retrying.
+func (n *alertNotifier) post(target string, body []byte) (retry bool, err error) {
+	req, err := http.NewRequest(http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(n.secret) > 0 {
+		req.Header.Set(alertSignatureHeader, signAlert(n.secret, body))
+	}
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("webhook %s: %w", req.URL.Host, err)
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	resp.Body.Close()
+	switch {
+	case resp.StatusCode/100 == 2:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode/100 == 5:
+		return true, fmt.Errorf("webhook %s: %s", req.URL.Host, resp.Status)
+	default:
+		return false, fmt.Errorf("webhook %s: %s", req.URL.Host, resp.Status)
+	}
+}
+
+// signAlert is the alertSignatureHeader value for body.  Receivers
+// recompute it with the shared secret and compare in constant time.
+func signAlert(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// warn keeps the first delivery failure for the footer; later ones would
+// only churn it.
+func (n *alertNotifier) warn(err error) {
+	n.warning.CompareAndSwap(nil, err.Error())
+}
+
+// warningText is the first delivery failure, or "".
+func (n *alertNotifier) warningText() string {
+	if n == nil {
+		return ""
+	}
+	s, _ := n.warning.Load().(string)
+	return s
+}
+
+// close waits up to alertCloseWait for queued deliveries, so an alert that
+// fired just before quitting still goes out.  Without an errs writer it
+// returns the first failure, for printing once the terminal is restored.
+func (n *alertNotifier) close() error {
+	if n == nil {
+		return nil
+	}
+	close(n.queue)
+	select {
+	case <-n.done:
+	case <-time.After(alertCloseWait):
+	}
+	if w := n.warningText(); w != "" && n.errs == nil {
+		return errors.New(w)
+	}
+	return nil
+}
+
+// ── Flap suppression ──────────────────────────────────────────────────────────
+
+// alertLimiter keeps a flapping rule from flooding a chat channel.  When a
+// rule fires again within the cooldown of its last message, the channel is
+// told once that it is flapping; every transition after that is held back
+// until the rule has been quiet for a full cooldown, and then only its
+// latest state is sent.  A nil limiter passes everything through.
+type alertLimiter struct {
+	cooldown time.Duration
+	rules    map[string]*flapState
+}
+
+type flapState struct {
+	last     time.Time   // when the last message for the rule went out
+	flapping bool        // holding back transitions until quiet
+	quiet    time.Time   // when the flapping ends if nothing else happens
+	held     *alertEvent // latest transition held back
+	changes  int         // transitions held back after the flapping notice
+}
+
+func newAlertLimiter(cooldown time.Duration) *alertLimiter {
+	return &alertLimiter{cooldown: cooldown, rules: make(map[string]*flapState)}
+}
+
+// admit returns what to send for ev, received at now.
+func (l *alertLimiter) admit(ev alertEvent, now time.Time) []alertEvent {
+	if l == nil || l.cooldown <= 0 {
+		return []alertEvent{ev}
+	}
+	st := l.rules[ev.Rule]
+	if st == nil {
+		st = &flapState{}
+		l.rules[ev.Rule] = st
+	}
+	switch {
+	case st.flapping:
+		st.held, st.quiet = &ev, now.Add(l.cooldown)
+		st.changes++
+		return nil
+	case ev.State == alertStarted && !st.last.IsZero() && now.Sub(st.last) < l.cooldown:
+		st.flapping, st.held, st.quiet = true, &ev, now.Add(l.cooldown)
+		flap := ev
+		flap.State = alertFlapping
+		return []alertEvent{flap}
+	}
+	st.last = now
+	return []alertEvent{ev}
+}
+
+// settle ends the flapping of rules quiet since before now and returns
+// their latest state.  A zero now settles every rule.
+func (l *alertLimiter) settle(now time.Time) []alertEvent {
+	if l == nil {
+		return nil
+	}
+	var out []alertEvent
+	for _, st := range l.rules {
+		if !st.flapping || (!now.IsZero() && now.Before(st.quiet)) {
+			continue
+		}
+		ev := *st.held
+		ev.Suppressed = st.changes
+		out = append(out, ev)
+		*st = flapState{last: now}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Rule < out[j].Rule })
+	return out
+}
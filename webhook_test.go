@@ -0,0 +1,230 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"crypto/hmac"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testAlert() alertEvent {
+	return alertEvent{
+		Hostname: "node7", Rule: "cpu>90", Metric: "cpu", Op: ">", Threshold: 90, Value: 97,
+		State: alertStarted, StartedAt: time.UnixMilli(1704067200000).UTC(),
+	}
+}
+
+// deliverOne sends testAlert through a notifier for url and waits for the
+// worker to finish.
+func deliverOne(t *testing.T, cfg webhookConfig) *alertNotifier {
+	t.Helper()
+	n, err := newAlertNotifier(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n.retryDelay = time.Millisecond
+	n.notify(testAlert())
+	n.close()
+	return n
+}
+
+func TestWebhookDelivery(t *testing.T) {
+	var got alertEvent
+	var sig string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		sig = r.Header.Get(alertSignatureHeader)
+		// A receiver authenticates the body with the shared secret.
+		if !hmac.Equal([]byte(sig), []byte(signAlert([]byte("hunter2"), body))) {
+			http.Error(w, "bad signature", http.StatusUnauthorized)
+			return
+		}
+		if err := json.Unmarshal(body, &got); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	n := deliverOne(t, webhookConfig{urls: []string{srv.URL}, secret: "hunter2", timeout: time.Second})
+	if w := n.warningText(); w != "" {
+		t.Fatalf("delivery failed: %s", w)
+	}
+	if got.Hostname != "node7" || got.Rule != "cpu>90" || got.Value != 97 || got.State != "started" || got.ClearedAt != nil {
+		t.Errorf("payload: got %+v", got)
+	}
+	if !strings.HasPrefix(sig, "sha256=") || len(sig) != len("sha256=")+64 {
+		t.Errorf("signature header: got %q", sig)
+	}
+
+	// A different secret is rejected by the receiver, and the footer says so.
+	n = deliverOne(t, webhookConfig{urls: []string{srv.URL}, secret: "wrong", timeout: time.Second})
+	if w := n.warningText(); !strings.Contains(w, "401") {
+		t.Errorf("wrong secret: got warning %q, want the 401", w)
+	}
+}
+
+func TestWebhookRetry(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    []int // per attempt; the last repeats
+		retries   int
+		wantCalls int32
+		wantOK    bool
+	}{
+		{"recovers", []int{503, 502, 200}, 3, 3, true},
+		{"gives up", []int{500}, 2, 3, false},
+		{"client error", []int{404}, 3, 1, false},
+	}
+	for _, tt := range tests {
+		var calls atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n := int(calls.Add(1))
+			w.WriteHeader(tt.status[min(n, len(tt.status))-1])
+		}))
+		n := deliverOne(t, webhookConfig{urls: []string{srv.URL}, timeout: time.Second, retries: tt.retries})
+		srv.Close()
+		if calls.Load() != tt.wantCalls || (n.warningText() == "") != tt.wantOK {
+			t.Errorf("%s: got %d calls, warning %q; want %d calls, ok %v", tt.name, calls.Load(), n.warningText(), tt.wantCalls, tt.wantOK)
+		}
+	}
+}
+
+func TestWebhookTimeout(t *testing.T) {
+	release := make(chan struct{})
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	n := deliverOne(t, webhookConfig{urls: []string{srv.URL}, timeout: 20 * time.Millisecond, retries: 1})
+	if calls.Load() != 2 || !strings.Contains(n.warningText(), "Timeout") {
+		t.Errorf("got %d calls, warning %q; want 2 timed-out attempts", calls.Load(), n.warningText())
+	}
+}
+
+func TestWebhookQueueDoesNotBlock(t *testing.T) {
+	block := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { <-block }))
+	defer srv.Close()
+	defer close(block)
+
+	n, err := newAlertNotifier(webhookConfig{urls: []string{srv.URL}, timeout: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 3*alertQueue; i++ {
+			n.notify(testAlert())
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("notify blocked on a stuck endpoint")
+	}
+	if n.overflow.Load() == 0 || !strings.Contains(n.warningText(), "queue full") {
+		t.Errorf("overflow %d, warning %q", n.overflow.Load(), n.warningText())
+	}
+}
+
+func TestNewAlertNotifier(t *testing.T) {
+	if n, err := newAlertNotifier(webhookConfig{}); n != nil || err != nil {
+		t.Errorf("no URLs: got %v, %v; want nil, nil", n, err)
+	}
+	if _, err := newAlertNotifier(webhookConfig{urls: []string{"hooks.example/x"}}); err == nil {
+		t.Error("URL without scheme: got nil error")
+	}
+	var n *alertNotifier
+	n.notify(testAlert()) // a nil notifier ignores alerts
+	n.close()
+}
+
+func TestAlertLimiter(t *testing.T) {
+	l := newAlertLimiter(5 * time.Minute)
+	t0 := time.Unix(1704067200, 0)
+	ev := func(state string) alertEvent { e := testAlert(); e.State = state; return e }
+
+	steps := []struct {
+		at      time.Duration
+		state   string // "" settles instead of admitting
+		want    []string
+		wantSup int // Suppressed on the single event returned
+	}{
+		{0, alertStarted, []string{alertStarted}, 0},
+		{time.Minute, alertCleared, []string{alertCleared}, 0},
+		{2 * time.Minute, alertStarted, []string{alertFlapping}, 0},
+		{3 * time.Minute, alertCleared, nil, 0},
+		{4 * time.Minute, alertStarted, nil, 0},
+		{8 * time.Minute, "", nil, 0},
+		// Quiet for a full cooldown since 4m: the channel learns it is firing.
+		{9 * time.Minute, "", []string{alertStarted}, 2},
+		{10 * time.Minute, alertCleared, []string{alertCleared}, 0},
+		{20 * time.Minute, alertStarted, []string{alertStarted}, 0},
+	}
+	for _, s := range steps {
+		var got []alertEvent
+		if s.state == "" {
+			got = l.settle(t0.Add(s.at))
+		} else {
+			got = l.admit(ev(s.state), t0.Add(s.at))
+		}
+		var states []string
+		for _, e := range got {
+			states = append(states, e.State)
+		}
+		if strings.Join(states, ",") != strings.Join(s.want, ",") {
+			t.Fatalf("at %s: got %v, want %v", s.at, states, s.want)
+		}
+		if len(got) == 1 && got[0].Suppressed != s.wantSup {
+			t.Errorf("at %s: got %d suppressed, want %d", s.at, got[0].Suppressed, s.wantSup)
+		}
+	}
+
+	// Closing releases a rule that is still flapping.
+	l.admit(ev(alertCleared), t0.Add(21*time.Minute))
+	l.admit(ev(alertStarted), t0.Add(22*time.Minute))
+	if got := l.settle(time.Time{}); len(got) != 1 || got[0].State != alertStarted {
+		t.Errorf("settle on close: got %+v", got)
+	}
+
+	var none *alertLimiter
+	if got := none.admit(ev(alertStarted), t0); len(got) != 1 || none.settle(t0) != nil {
+		t.Error("nil limiter did not pass the alert through")
+	}
+}
+
+func TestChatDelivery(t *testing.T) {
+	bodies := make(chan []byte, 4)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies <- body
+	}))
+	defer srv.Close()
+
+	n := deliverOne(t, webhookConfig{slack: []string{srv.URL}, discord: []string{srv.URL + "/discord"},
+		timeout: time.Second, cooldown: time.Minute})
+	if w := n.warningText(); w != "" {
+		t.Fatalf("delivery failed: %s", w)
+	}
+	var slack slackPayload
+	var discord discordPayload
+	if err := json.Unmarshal(<-bodies, &slack); err != nil || len(slack.Attachments) != 1 {
+		t.Errorf("slack body: %+v, %v", slack, err)
+	}
+	if err := json.Unmarshal(<-bodies, &discord); err != nil || len(discord.Embeds) != 1 || discord.Embeds[0].Color != 0xef4444 {
+		t.Errorf("discord body: %+v, %v", discord, err)
+	}
+}
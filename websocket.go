@@ -0,0 +1,102 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// ── WebSocket stream ──────────────────────────────────────────────────────────
+
+const (
+	// wsWriteWait bounds every write, so a client that stops reading is
+	// disconnected instead of wedging its handler.
+	wsWriteWait = 5 * time.Second
+
+	// wsPongWait is how long a client may stay silent before it is
+	// considered gone; pings are sent well within it.
+	wsPongWait   = 60 * time.Second
+	wsPingPeriod = wsPongWait * 9 / 10
+)
+
+// handleStream upgrades /api/v1/stream to a WebSocket and pushes every new
+// sample as a JSON text message (the metrics.Sample JSON form).  Incoming
+// messages are ignored.  corsOrigin widens the same-origin check exactly as
+// -cors does for the JSON endpoints.
+func (l *liveState) handleStream(corsOrigin string) http.HandlerFunc {
+	up := websocket.Upgrader{CheckOrigin: func(r *http.Request) bool {
+		return checkOrigin(r, corsOrigin)
+	}}
+	return func(w http.ResponseWriter, r *http.Request) {
+		sub := l.subscribe()
+		if sub == nil {
+			http.Error(w, "server shutting down", http.StatusServiceUnavailable)
+			return
+		}
+		defer l.unsubscribe(sub)
+
+		conn, err := up.Upgrade(w, r, nil)
+		if err != nil {
+			return // Upgrade has already replied with an HTTP error
+		}
+
+		// The reader exists to process pongs and the client's close frame;
+		// it ends when the connection fails or is closed below.
+		readDone := make(chan struct{})
+		go func() {
+			defer close(readDone)
+			conn.SetReadLimit(512)
+			_ = conn.SetReadDeadline(time.Now().Add(wsPongWait))
+			conn.SetPongHandler(func(string) error {
+				return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+			})
+			for {
+				if _, _, err := conn.NextReader(); err != nil {
+					return
+				}
+			}
+		}()
+		defer func() { conn.Close(); <-readDone }()
+
+		ping := time.NewTicker(wsPingPeriod)
+		defer ping.Stop()
+		for {
+			select {
+			case u := <-sub.ch:
+				if u.Sample == nil {
+					continue // the WebSocket feed carries samples only
+				}
+				_ = conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+				if err := conn.WriteJSON(u.Sample); err != nil {
+					return
+				}
+			case <-ping.C:
+				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait)); err != nil {
+					return
+				}
+			case <-readDone:
+				return
+			case <-l.closing:
+				msg := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+				_ = conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(wsWriteWait))
+				return
+			}
+		}
+	}
+}
+
+// checkOrigin accepts requests without an Origin header (non-browser
+// clients), same-origin requests, and those from the -cors origin.
+func checkOrigin(r *http.Request, corsOrigin string) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" || corsOrigin == "*" || origin == corsOrigin {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && u.Host == r.Host
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2026 ALH477
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/ALH477/infgo/metrics"
+)
+
+// dialStream connects a WebSocket client to srv's /api/v1/stream.
+func dialStream(t *testing.T, srv *httptest.Server, origin string) *websocket.Conn {
+	t.Helper()
+	hdr := http.Header{}
+	if origin != "" {
+		hdr.Set("Origin", origin)
+	}
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/api/v1/stream"
+	conn, _, err := websocket.DefaultDialer.Dial(url, hdr)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	return conn
+}
+
+// waitClients polls until live has n streaming clients.
+func waitClients(t *testing.T, live *liveState, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for live.clientCount() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("clientCount: got %d, want %d", live.clientCount(), n)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestStreamPushesSamples(t *testing.T) {
+	live := newLiveState()
+	srv := httptest.NewServer(newServeMux(live, serveConfig{}))
+	defer srv.Close()
+	defer live.closeStreams()
+
+	conn := dialStream(t, srv, "")
+	defer conn.Close()
+	waitClients(t, live, 1)
+
+	for i := 1; i <= 3; i++ {
+		live.setSample(metrics.Sample{TimestampUnixMs: int64(i), CpuTotal: float64(10 * i)}, 0)
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for i := 1; i <= 3; i++ {
+		var s metrics.Sample
+		if err := conn.ReadJSON(&s); err != nil {
+			t.Fatalf("message %d: %v", i, err)
+		}
+		if s.TimestampUnixMs != int64(i) || s.CpuTotal != float64(10*i) {
+			t.Errorf("message %d: got %+v", i, s)
+		}
+	}
+
+	conn.Close()
+	waitClients(t, live, 0)
+}
+
+func TestStreamShutdown(t *testing.T) {
+	live := newLiveState()
+	srv := httptest.NewServer(newServeMux(live, serveConfig{}))
+	defer srv.Close()
+
+	conns := []*websocket.Conn{dialStream(t, srv, ""), dialStream(t, srv, "")}
+	waitClients(t, live, 2)
+
+	done := make(chan struct{})
+	go func() { live.closeStreams(); close(done) }()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("closeStreams did not return")
+	}
+	if n := live.clientCount(); n != 0 {
+		t.Errorf("clients after shutdown: got %d, want 0", n)
+	}
+
+	for i, conn := range conns {
+		_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		_, _, err := conn.ReadMessage()
+		if !websocket.IsCloseError(err, websocket.CloseGoingAway) {
+			t.Errorf("client %d: got %v, want close 1001", i, err)
+		}
+		conn.Close()
+	}
+
+	// Late arrivals are turned away rather than left hanging.
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/api/v1/stream"
+	if _, resp, err := websocket.DefaultDialer.Dial(url, nil); err == nil || resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("dial after shutdown: got %v, want 503", err)
+	}
+}
+
+func TestStreamRejectsForeignOrigin(t *testing.T) {
+	live := newLiveState()
+	srv := httptest.NewServer(newServeMux(live, serveConfig{}))
+	defer srv.Close()
+
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/api/v1/stream"
+	hdr := http.Header{"Origin": {"https://evil.example"}}
+	if _, resp, err := websocket.DefaultDialer.Dial(url, hdr); err == nil || resp.StatusCode != http.StatusForbidden {
+		t.Errorf("foreign origin: got %v, want 403", err)
+	}
+	waitClients(t, live, 0)
+
+	// The same origin is fine once -cors allows it.
+	srv2 := httptest.NewServer(newServeMux(live, serveConfig{corsOrigin: "https://evil.example"}))
+	defer srv2.Close()
+	defer live.closeStreams()
+	conn := dialStream(t, srv2, "https://evil.example")
+	conn.Close()
+}
+
+func TestSubscriberDropsOldest(t *testing.T) {
+	sub := &subscriber{ch: make(chan liveUpdate, subscriberQueue)}
+	const extra = 5
+	for i := 0; i < subscriberQueue+extra; i++ {
+		sub.offer(liveUpdate{Sample: &metrics.Sample{TimestampUnixMs: int64(i)}})
+	}
+	if got := sub.dropped.Load(); got != extra {
+		t.Errorf("dropped: got %d, want %d", got, extra)
+	}
+	if first := <-sub.ch; first.Sample.TimestampUnixMs != extra {
+		t.Errorf("oldest kept: got %d, want %d", first.Sample.TimestampUnixMs, extra)
+	}
+}